@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/spf13/cobra"
+)
+
+var chatStorageCmd = &cobra.Command{
+	Use:   "chatstorage",
+	Short: "Maintain the chat storage database",
+}
+
+var chatStorageDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check chat storage for inconsistencies and optionally repair them",
+	Long: `Check every device's chat storage for chats without messages, messages without a chat
+and @lid chats that were not merged into their phone number chat. --repair also removes media
+no message references any more. Exits with status 1 when issues remain (found without
+--repair, or failed to repair).`,
+	Run: chatStorageDoctor,
+}
+
+var (
+	chatStorageDoctorDevice string
+	chatStorageDoctorRepair bool
+	chatStorageDoctorJSON   bool
+)
+
+func init() {
+	rootCmd.AddCommand(chatStorageCmd)
+	chatStorageCmd.AddCommand(chatStorageDoctorCmd)
+	chatStorageDoctorCmd.Flags().StringVar(&chatStorageDoctorDevice, "device", "", "Only check this device ID (default: all devices)")
+	chatStorageDoctorCmd.Flags().BoolVar(&chatStorageDoctorRepair, "repair", false, "Repair the issues found")
+	chatStorageDoctorCmd.Flags().BoolVar(&chatStorageDoctorJSON, "json", false, "Print the reports as JSON")
+}
+
+func chatStorageDoctor(_ *cobra.Command, _ []string) {
+	ctx := context.Background()
+	dm := whatsapp.GetDeviceManager()
+	if dm == nil {
+		fmt.Fprintln(os.Stderr, "device manager is not initialized")
+		os.Exit(1)
+	}
+
+	var deviceIDs []string
+	if chatStorageDoctorDevice != "" {
+		deviceIDs = append(deviceIDs, chatStorageDoctorDevice)
+	} else {
+		for _, inst := range dm.ListDevices() {
+			deviceIDs = append(deviceIDs, inst.ID())
+		}
+	}
+	if len(deviceIDs) == 0 {
+		fmt.Fprintln(os.Stderr, "no devices found")
+		os.Exit(1)
+	}
+
+	reports := make([]domainChat.StorageDoctorReport, 0, len(deviceIDs))
+	unresolved := false
+	for _, deviceID := range deviceIDs {
+		inst, err := dm.EnsureClient(ctx, deviceID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "device %s: %v\n", deviceID, err)
+			os.Exit(1)
+		}
+
+		report, err := chatUsecase.CheckStorage(whatsapp.ContextWithDevice(ctx, inst), domainChat.StorageDoctorRequest{Repair: chatStorageDoctorRepair})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "device %s: %v\n", deviceID, err)
+			os.Exit(1)
+		}
+		reports = append(reports, report)
+		if (report.Issues > 0 && !report.Repaired) || len(report.RepairErrors) > 0 {
+			unresolved = true
+		}
+	}
+
+	if chatStorageDoctorJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(reports)
+	} else {
+		for _, report := range reports {
+			printStorageDoctorReport(os.Stdout, report)
+		}
+	}
+
+	if unresolved {
+		os.Exit(1)
+	}
+}
+
+func printStorageDoctorReport(w io.Writer, report domainChat.StorageDoctorReport) {
+	status := "found"
+	if report.Repaired {
+		status = "repaired"
+	}
+	fmt.Fprintf(w, "Device %s: %d issue(s) %s\n", report.DeviceID, report.Issues, status)
+
+	if len(report.EmptyChats) > 0 {
+		fmt.Fprintf(w, "  Chats without messages (%d):\n", len(report.EmptyChats))
+		for _, jid := range report.EmptyChats {
+			fmt.Fprintf(w, "    - %s\n", jid)
+		}
+	}
+	if len(report.MissingChats) > 0 {
+		fmt.Fprintf(w, "  Messages without a chat (%d chats):\n", len(report.MissingChats))
+		for _, chat := range report.MissingChats {
+			fmt.Fprintf(w, "    - %s: %d message(s), last at %s\n", chat.ChatJID, chat.MessageCount, chat.LastMessageTime)
+		}
+	}
+	if len(report.DuplicateLIDChats) > 0 {
+		fmt.Fprintf(w, "  Unmerged LID chats (%d):\n", len(report.DuplicateLIDChats))
+		for _, chat := range report.DuplicateLIDChats {
+			fmt.Fprintf(w, "    - %s -> %s\n", chat.LIDJID, chat.PhoneJID)
+		}
+	}
+	if report.RemovedMedia > 0 {
+		fmt.Fprintf(w, "  Removed unreferenced media: %d\n", report.RemovedMedia)
+	}
+	if len(report.RepairErrors) > 0 {
+		fmt.Fprintf(w, "  Repair errors (%d):\n", len(report.RepairErrors))
+		for _, repairErr := range report.RepairErrors {
+			fmt.Fprintf(w, "    - %s\n", repairErr)
+		}
+	}
+}
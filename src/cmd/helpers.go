@@ -5,13 +5,23 @@ import (
 	"sync"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 )
 
-var presencePulseSchedulerOnce sync.Once
+var (
+	presencePulseSchedulerOnce sync.Once
+	groupSnapshotRefresherOnce sync.Once
+	chatTrashPurgerOnce        sync.Once
+	webhookOutboxOnce          sync.Once
+	webhookDeliveryOnce        sync.Once
+	sendQueueOnce              sync.Once
+	chatStorageMaintenanceOnce sync.Once
+	lidResolverMaintenanceOnce sync.Once
+)
 
 // getValidWhatsAppClient returns an initialized WhatsApp client if available.
 func getValidWhatsAppClient() *whatsmeow.Client {
@@ -55,3 +65,103 @@ func startPresencePulseSchedulerIfEnabled() {
 		logrus.Infof("presence pulse scheduler started; interval=%s duration=%s", config.WhatsappPresencePulseInterval, config.WhatsappPresencePulseDuration)
 	})
 }
+
+// startGroupSnapshotRefresherIfEnabled starts the process-wide group snapshot refresher once.
+func startGroupSnapshotRefresherIfEnabled() {
+	if config.WhatsappGroupSnapshotRefreshInterval <= 0 {
+		return
+	}
+
+	dm := whatsapp.GetDeviceManager()
+	if dm == nil {
+		logrus.Warn("device manager is nil; group snapshot refresher not started")
+		return
+	}
+
+	groupSnapshotRefresherOnce.Do(func() {
+		whatsapp.StartGroupSnapshotRefresher(context.Background(), dm, config.WhatsappGroupSnapshotRefreshInterval)
+		logrus.Infof("group snapshot refresher started; interval=%s", config.WhatsappGroupSnapshotRefreshInterval)
+	})
+}
+
+// startLIDResolverMaintenanceIfEnabled starts the process-wide retry of failed LID lookups once.
+func startLIDResolverMaintenanceIfEnabled() {
+	if config.WhatsappLIDCleanupInterval <= 0 {
+		return
+	}
+
+	dm := whatsapp.GetDeviceManager()
+	if dm == nil {
+		logrus.Warn("device manager is nil; LID resolver maintenance not started")
+		return
+	}
+
+	lidResolverMaintenanceOnce.Do(func() {
+		whatsapp.StartLIDResolverMaintenance(context.Background(), dm, config.WhatsappLIDCleanupInterval)
+		logrus.Infof("LID resolver maintenance started; interval=%s", config.WhatsappLIDCleanupInterval)
+	})
+}
+
+// startChatTrashPurgerIfEnabled starts the process-wide purge of expired trashed chats once.
+func startChatTrashPurgerIfEnabled() {
+	if config.ChatStorageTrashRetention <= 0 {
+		return
+	}
+
+	chatTrashPurgerOnce.Do(func() {
+		chatstorage.StartTrashPurger(context.Background(), chatStorageRepo, config.ChatStorageTrashRetention)
+		logrus.Infof("chat trash purger started; retention=%s", config.ChatStorageTrashRetention)
+	})
+}
+
+// startWebhookOutboxDispatcherIfEnabled starts the process-wide webhook outbox dispatcher once.
+func startWebhookOutboxDispatcherIfEnabled() {
+	if !config.WhatsappWebhookOutbox {
+		return
+	}
+
+	webhookOutboxOnce.Do(func() {
+		whatsapp.StartWebhookOutboxDispatcher(context.Background(), chatStorageRepo)
+		logrus.Info("webhook outbox dispatcher started")
+	})
+}
+
+// startWebhookDeliveryWorkerIfEnabled starts the process-wide webhook retry queue worker once.
+func startWebhookDeliveryWorkerIfEnabled() {
+	if config.WhatsappWebhookRetryMaxAttempts <= 0 {
+		return
+	}
+
+	webhookDeliveryOnce.Do(func() {
+		whatsapp.StartWebhookDeliveryWorker(context.Background(), chatStorageRepo)
+		logrus.Infof("webhook delivery worker started; max attempts=%d", config.WhatsappWebhookRetryMaxAttempts)
+	})
+}
+
+// startSendQueueIfEnabled starts the process-wide outgoing message queue once.
+func startSendQueueIfEnabled() {
+	if config.WhatsappSendQueueWorkers <= 0 {
+		return
+	}
+
+	sendQueueOnce.Do(func() {
+		whatsapp.StartSendQueue(context.Background(), chatStorageRepo, config.WhatsappSendQueueWorkers)
+		logrus.Infof("send queue started; workers=%d", config.WhatsappSendQueueWorkers)
+	})
+}
+
+// startChatStorageMaintenanceIfEnabled starts the process-wide WAL checkpoint and vacuum routines once.
+func startChatStorageMaintenanceIfEnabled() {
+	checkpointInterval := config.ChatStorageCheckpointInterval
+	if !config.ChatStorageEnableWAL {
+		checkpointInterval = 0
+	}
+	if checkpointInterval <= 0 && config.ChatStorageVacuumInterval <= 0 {
+		return
+	}
+
+	chatStorageMaintenanceOnce.Do(func() {
+		chatstorage.StartMaintenance(context.Background(), chatStorageRepo, checkpointInterval, config.ChatStorageVacuumInterval)
+		logrus.Infof("chat storage maintenance started; checkpoint=%s vacuum=%s", checkpointInterval, config.ChatStorageVacuumInterval)
+	})
+}
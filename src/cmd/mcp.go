@@ -35,6 +35,13 @@ func mcpServer(_ *cobra.Command, _ []string) {
 
 	// Set daily presence pulse scheduler when enabled
 	startPresencePulseSchedulerIfEnabled()
+	startGroupSnapshotRefresherIfEnabled()
+	startLIDResolverMaintenanceIfEnabled()
+	startChatTrashPurgerIfEnabled()
+	startWebhookOutboxDispatcherIfEnabled()
+	startWebhookDeliveryWorkerIfEnabled()
+	startSendQueueIfEnabled()
+	startChatStorageMaintenanceIfEnabled()
 
 	// Create MCP server with capabilities
 	mcpServer := server.NewMCPServer(
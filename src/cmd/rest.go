@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,6 +14,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/grpc"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -129,6 +131,9 @@ func restServer(_ *cobra.Command, _ []string) {
 		app.Post(webhookPath, chatwootHandler.HandleWebhook)
 	}
 
+	// Webhook media links are signed, so they are served before basic auth too.
+	app.Get(config.AppBasePath+"/webhooks/media/:name", rest.WebhookMedia)
+
 	if len(config.AppBasicAuthCredential) > 0 {
 		account := make(map[string]string)
 		for _, basicAuth := range config.AppBasicAuthCredential {
@@ -158,12 +163,22 @@ func restServer(_ *cobra.Command, _ []string) {
 		rest.InitRestMessage(r, messageUsecase)
 		rest.InitRestGroup(r, groupUsecase)
 		rest.InitRestNewsletter(r, newsletterUsecase)
+		rest.InitRestJob(r, jobUsecase)
 		websocket.RegisterRoutes(r, appUsecase)
 	}
 
 	// Device management routes (no device_id required)
 	rest.InitRestDevice(apiGroup, deviceUsecase)
 
+	// Instance-wide settings export/import (not device-scoped)
+	rest.InitRestSettings(apiGroup, settingsUsecase)
+
+	// Event pipeline latency counters (instance-wide)
+	rest.InitRestDiagnostics(apiGroup)
+
+	// Live event subscriptions across devices (instance-wide)
+	websocket.RegisterEventRoutes(apiGroup)
+
 	// Device-scoped operations (header-based)
 	headerDeviceGroup := apiGroup.Group("", middleware.DeviceMiddleware(dm))
 	registerDeviceScopedRoutes(headerDeviceGroup)
@@ -195,6 +210,13 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	// Set daily presence pulse scheduler when enabled
 	startPresencePulseSchedulerIfEnabled()
+	startGroupSnapshotRefresherIfEnabled()
+	startLIDResolverMaintenanceIfEnabled()
+	startChatTrashPurgerIfEnabled()
+	startWebhookOutboxDispatcherIfEnabled()
+	startWebhookDeliveryWorkerIfEnabled()
+	startSendQueueIfEnabled()
+	startChatStorageMaintenanceIfEnabled()
 
 	// Listen in a goroutine so we can trap SIGINT/SIGTERM and drain the
 	// server cleanly. Without this, Fiber's Listen blocks until the OS
@@ -205,6 +227,22 @@ func restServer(_ *cobra.Command, _ []string) {
 		listenErr <- app.Listen(config.AppHost + ":" + config.AppPort)
 	}()
 
+	stopGrpc := func() {}
+	if config.AppGrpcPort != "" {
+		grpcListener, err := net.Listen("tcp", config.AppHost+":"+config.AppGrpcPort)
+		if err != nil {
+			logrus.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		server := grpc.NewServer(sendUsecase)
+		stopGrpc = server.Stop
+		logrus.Infof("Serving gRPC API on %s", grpcListener.Addr())
+		go func() {
+			if err := server.Serve(grpcListener); err != nil {
+				listenErr <- fmt.Errorf("gRPC server: %w", err)
+			}
+		}()
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
@@ -220,6 +258,8 @@ func restServer(_ *cobra.Command, _ []string) {
 		if err := app.ShutdownWithContext(shutdownCtx); err != nil {
 			logrus.Warnf("HTTP server shutdown: %v", err)
 		}
+		// Event streams never end on their own, so gRPC calls are cut rather than drained.
+		stopGrpc()
 		// Release the Chatwoot direct-Postgres importer pool if one was
 		// opened. Safe when Chatwoot is disabled or the pool was never
 		// initialized — GetDefaultSyncService() returns nil.
@@ -228,6 +268,11 @@ func restServer(_ *cobra.Command, _ []string) {
 				logrus.Warnf("Chatwoot sync close: %v", err)
 			}
 		}
+		if chatStorageRepo != nil {
+			if err := chatStorageRepo.FlushPendingWrites(); err != nil {
+				logrus.Warnf("Chat storage flush: %v", err)
+			}
+		}
 		if chatStorageDB != nil {
 			if err := chatStorageDB.Close(); err != nil {
 				logrus.Warnf("Chat storage close: %v", err)
@@ -17,11 +17,14 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainSettings "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/settings"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/eventbus"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/sqlite"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -53,6 +56,8 @@ var (
 	groupUsecase      domainGroup.IGroupUsecase
 	newsletterUsecase domainNewsletter.INewsletterUsecase
 	deviceUsecase     domainDevice.IDeviceUsecase
+	jobUsecase        domainJob.IJobUsecase
+	settingsUsecase   domainSettings.ISettingsUsecase
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -87,6 +92,9 @@ func initEnvConfig() {
 	if envHost := viper.GetString("app_host"); envHost != "" {
 		config.AppHost = envHost
 	}
+	if envGrpcPort := viper.GetString("app_grpc_port"); envGrpcPort != "" {
+		config.AppGrpcPort = envGrpcPort
+	}
 	if envDebug := viper.GetBool("app_debug"); envDebug {
 		config.AppDebug = envDebug
 	}
@@ -112,6 +120,26 @@ func initEnvConfig() {
 	if envDBKEYSURI := viper.GetString("db_keys_uri"); envDBKEYSURI != "" {
 		config.DBKeysURI = envDBKEYSURI
 	}
+	if envChatStorageEncryptionKey := viper.GetString("chat_storage_encryption_key"); envChatStorageEncryptionKey != "" {
+		config.ChatStorageEncryptionKey = envChatStorageEncryptionKey
+	}
+	if viper.IsSet("chat_storage_write_behind_interval") {
+		config.ChatStorageWriteBehindInterval = viper.GetDuration("chat_storage_write_behind_interval")
+	}
+	if viper.IsSet("chat_storage_write_behind_batch") {
+		if batch := viper.GetInt("chat_storage_write_behind_batch"); batch > 0 {
+			config.ChatStorageWriteBehindBatch = batch
+		}
+	}
+	if viper.IsSet("chat_storage_trash_retention") {
+		config.ChatStorageTrashRetention = viper.GetDuration("chat_storage_trash_retention")
+	}
+	if viper.IsSet("chat_storage_checkpoint_interval") {
+		config.ChatStorageCheckpointInterval = viper.GetDuration("chat_storage_checkpoint_interval")
+	}
+	if viper.IsSet("chat_storage_vacuum_interval") {
+		config.ChatStorageVacuumInterval = viper.GetDuration("chat_storage_vacuum_interval")
+	}
 
 	// WhatsApp settings
 	if envAutoReply := viper.GetString("whatsapp_auto_reply"); envAutoReply != "" {
@@ -123,6 +151,12 @@ func initEnvConfig() {
 	if viper.IsSet("whatsapp_auto_download_media") {
 		config.WhatsappAutoDownloadMedia = viper.GetBool("whatsapp_auto_download_media")
 	}
+	if viper.IsSet("whatsapp_link_preview") {
+		config.WhatsappLinkPreview = viper.GetBool("whatsapp_link_preview")
+	}
+	if viper.IsSet("whatsapp_auto_convert_video") {
+		config.WhatsappSettingAutoConvertVideo = viper.GetBool("whatsapp_auto_convert_video")
+	}
 	if envWebhook := viper.GetString("whatsapp_webhook"); envWebhook != "" {
 		webhook := strings.Split(envWebhook, ",")
 		config.WhatsappWebhook = webhook
@@ -130,19 +164,135 @@ func initEnvConfig() {
 	if envWebhookSecret := viper.GetString("whatsapp_webhook_secret"); envWebhookSecret != "" {
 		config.WhatsappWebhookSecret = envWebhookSecret
 	}
+	if envWebhookSecretPrevious := viper.GetString("whatsapp_webhook_secret_previous"); envWebhookSecretPrevious != "" {
+		config.WhatsappWebhookSecretPrevious = envWebhookSecretPrevious
+	}
+	if envWebhookSignatureAlgorithm := viper.GetString("whatsapp_webhook_signature_algorithm"); envWebhookSignatureAlgorithm != "" {
+		config.WhatsappWebhookSignatureAlgorithm = envWebhookSignatureAlgorithm
+	}
+	if envWebhookCACert := viper.GetString("whatsapp_webhook_ca_cert"); envWebhookCACert != "" {
+		config.WhatsappWebhookCACert = envWebhookCACert
+	}
+	if envWebhookClientCert := viper.GetString("whatsapp_webhook_client_cert"); envWebhookClientCert != "" {
+		config.WhatsappWebhookClientCert = envWebhookClientCert
+	}
+	if envWebhookClientKey := viper.GetString("whatsapp_webhook_client_key"); envWebhookClientKey != "" {
+		config.WhatsappWebhookClientKey = envWebhookClientKey
+	}
 	if viper.IsSet("whatsapp_webhook_insecure_skip_verify") {
 		config.WhatsappWebhookInsecureSkipVerify = viper.GetBool("whatsapp_webhook_insecure_skip_verify")
 	}
+	if viper.IsSet("whatsapp_webhook_presence") {
+		config.WhatsappWebhookPresence = viper.GetBool("whatsapp_webhook_presence")
+	}
+	if viper.IsSet("whatsapp_webhook_outgoing") {
+		config.WhatsappWebhookOutgoing = viper.GetBool("whatsapp_webhook_outgoing")
+	}
+	if viper.IsSet("whatsapp_webhook_outgoing_api") {
+		config.WhatsappWebhookOutgoingAPI = viper.GetBool("whatsapp_webhook_outgoing_api")
+	}
+	if viper.IsSet("whatsapp_webhook_chat_ordering") {
+		config.WhatsappWebhookChatOrdering = viper.GetBool("whatsapp_webhook_chat_ordering")
+	}
+	if viper.IsSet("whatsapp_webhook_outbox") {
+		config.WhatsappWebhookOutbox = viper.GetBool("whatsapp_webhook_outbox")
+	}
+	if viper.IsSet("whatsapp_webhook_retry_max_attempts") {
+		config.WhatsappWebhookRetryMaxAttempts = viper.GetInt("whatsapp_webhook_retry_max_attempts")
+	}
+	if viper.IsSet("whatsapp_send_queue_workers") {
+		config.WhatsappSendQueueWorkers = viper.GetInt("whatsapp_send_queue_workers")
+	}
+	if viper.IsSet("whatsapp_send_rate_limit") {
+		config.WhatsappSendRateLimit = viper.GetInt("whatsapp_send_rate_limit")
+	}
+	if viper.IsSet("whatsapp_send_rate_limit_per_recipient") {
+		config.WhatsappSendRateLimitPerRecipient = viper.GetInt("whatsapp_send_rate_limit_per_recipient")
+	}
+	if viper.IsSet("whatsapp_send_rate_limit_burst") {
+		config.WhatsappSendRateLimitBurst = viper.GetInt("whatsapp_send_rate_limit_burst")
+	}
+	if viper.IsSet("whatsapp_send_daily_cap") {
+		config.WhatsappSendDailyCap = viper.GetInt("whatsapp_send_daily_cap")
+	}
+	if viper.IsSet("whatsapp_send_jitter") {
+		config.WhatsappSendJitter = viper.GetDuration("whatsapp_send_jitter")
+	}
+	if viper.IsSet("whatsapp_webhook_batch_interval") {
+		config.WhatsappWebhookBatchInterval = viper.GetDuration("whatsapp_webhook_batch_interval")
+	}
+	if viper.IsSet("whatsapp_webhook_batch_size") {
+		config.WhatsappWebhookBatchSize = viper.GetInt("whatsapp_webhook_batch_size")
+	}
+	if envWebhookFilters := viper.GetString("whatsapp_webhook_filters"); envWebhookFilters != "" {
+		config.WhatsappWebhookFilters = strings.Split(envWebhookFilters, ",")
+	}
+	if envWebhookMaxPayloadSizes := viper.GetString("whatsapp_webhook_max_payload_sizes"); envWebhookMaxPayloadSizes != "" {
+		config.WhatsappWebhookMaxPayloadSizes = strings.Split(envWebhookMaxPayloadSizes, ",")
+	}
+	if envWebhookMediaBaseURL := viper.GetString("whatsapp_webhook_media_base_url"); envWebhookMediaBaseURL != "" {
+		config.WhatsappWebhookMediaBaseURL = envWebhookMediaBaseURL
+	}
+	if viper.IsSet("whatsapp_webhook_media_url_ttl") {
+		if ttl := viper.GetDuration("whatsapp_webhook_media_url_ttl"); ttl > 0 {
+			config.WhatsappWebhookMediaURLTTL = ttl
+		}
+	}
+	if envWebhookFormat := viper.GetString("whatsapp_webhook_format"); envWebhookFormat != "" {
+		config.WhatsappWebhookFormat = envWebhookFormat
+	}
+	if envWebhookIdentifier := viper.GetString("whatsapp_webhook_identifier"); envWebhookIdentifier != "" {
+		config.WhatsappWebhookIdentifier = envWebhookIdentifier
+	}
+	if envWebhookTemplates := viper.GetString("whatsapp_webhook_templates"); envWebhookTemplates != "" {
+		config.WhatsappWebhookTemplates = strings.Split(envWebhookTemplates, ",")
+	}
 	if envWebhookEvents := viper.GetString("whatsapp_webhook_events"); envWebhookEvents != "" {
 		events := strings.Split(envWebhookEvents, ",")
 		config.WhatsappWebhookEvents = events
 	}
+	if envSendPolicyRules := viper.GetString("whatsapp_send_policy_rules"); envSendPolicyRules != "" {
+		config.WhatsappSendPolicyRules = strings.Split(envSendPolicyRules, ",")
+	}
+	if envSendPolicyURL := viper.GetString("whatsapp_send_policy_url"); envSendPolicyURL != "" {
+		config.WhatsappSendPolicyURL = envSendPolicyURL
+	}
+	if envQuietHours := viper.GetString("whatsapp_quiet_hours"); envQuietHours != "" {
+		config.WhatsappQuietHours = envQuietHours
+	}
+	if envQuietHoursTimezone := viper.GetString("whatsapp_quiet_hours_timezone"); envQuietHoursTimezone != "" {
+		config.WhatsappQuietHoursTimezone = envQuietHoursTimezone
+	}
+	if envQuietHoursReply := viper.GetString("whatsapp_quiet_hours_reply"); envQuietHoursReply != "" {
+		config.WhatsappQuietHoursReply = envQuietHoursReply
+	}
+	if envTenantRoutes := viper.GetString("whatsapp_tenant_routes"); envTenantRoutes != "" {
+		config.WhatsappTenantRoutes = strings.Split(envTenantRoutes, ",")
+	}
+	if envTenantWebhooks := viper.GetString("whatsapp_tenant_webhooks"); envTenantWebhooks != "" {
+		config.WhatsappTenantWebhooks = strings.Split(envTenantWebhooks, ",")
+	}
+	if envEventBusDriver := viper.GetString("whatsapp_event_bus_driver"); envEventBusDriver != "" {
+		config.WhatsappEventBusDriver = envEventBusDriver
+	}
+	if envEventBusURL := viper.GetString("whatsapp_event_bus_url"); envEventBusURL != "" {
+		config.WhatsappEventBusURL = envEventBusURL
+	}
+	if envEventBusTopic := viper.GetString("whatsapp_event_bus_topic"); envEventBusTopic != "" {
+		config.WhatsappEventBusTopic = envEventBusTopic
+	}
+	if envEventBusTopics := viper.GetString("whatsapp_event_bus_topics"); envEventBusTopics != "" {
+		config.WhatsappEventBusTopics = strings.Split(envEventBusTopics, ",")
+	}
 	if viper.IsSet("whatsapp_account_validation") {
 		config.WhatsappAccountValidation = viper.GetBool("whatsapp_account_validation")
 	}
 	if viper.IsSet("whatsapp_auto_reject_call") {
 		config.WhatsappAutoRejectCall = viper.GetBool("whatsapp_auto_reject_call")
 	}
+	if envAutoRejectCallMessage := viper.GetString("whatsapp_auto_reject_call_message"); envAutoRejectCallMessage != "" {
+		config.WhatsappAutoRejectCallMessage = envAutoRejectCallMessage
+	}
 	if envPresenceOnConnect := viper.GetString("whatsapp_presence_on_connect"); envPresenceOnConnect != "" {
 		config.WhatsappPresenceOnConnect = envPresenceOnConnect
 	}
@@ -159,6 +309,25 @@ func initEnvConfig() {
 			config.WhatsappPresencePulseDuration = duration
 		}
 	}
+	if viper.IsSet("whatsapp_lid_failed_lookup_ttl") {
+		if ttl := viper.GetDuration("whatsapp_lid_failed_lookup_ttl"); ttl > 0 {
+			config.WhatsappLIDFailedLookupTTL = ttl
+		}
+	}
+	if viper.IsSet("whatsapp_lid_proactive_timeout") {
+		if timeout := viper.GetDuration("whatsapp_lid_proactive_timeout"); timeout > 0 {
+			config.WhatsappLIDProactiveTimeout = timeout
+		}
+	}
+	if viper.IsSet("whatsapp_lid_cleanup_interval") {
+		config.WhatsappLIDCleanupInterval = viper.GetDuration("whatsapp_lid_cleanup_interval")
+	}
+	if viper.IsSet("whatsapp_group_snapshot_refresh_interval") {
+		config.WhatsappGroupSnapshotRefreshInterval = viper.GetDuration("whatsapp_group_snapshot_refresh_interval")
+	}
+	if viper.IsSet("whatsapp_event_latency_budget") {
+		config.WhatsappEventLatencyBudget = viper.GetDuration("whatsapp_event_latency_budget")
+	}
 
 	// WhatsApp Proxy settings
 	if envProxyURL := viper.GetString("whatsapp_proxy_url"); envProxyURL != "" {
@@ -276,6 +445,12 @@ func initFlags() {
 		config.AppHost,
 		`host to bind the server --host <string> | example: --host="127.0.0.1"`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.AppGrpcPort,
+		"grpc-port", "",
+		config.AppGrpcPort,
+		`also serve the gRPC API on this port (empty: disabled) --grpc-port <number> | example: --grpc-port=50051`,
+	)
 
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.AppDebug,
@@ -321,6 +496,42 @@ func initFlags() {
 		config.DBKeysURI,
 		`the database uri to store the optional keys cache (by default, we'll use the same database uri). avoid in-memory storage in production. database uri --db-keys-uri <string> | example: --db-keys-uri="file:storages/whatsapp-keys.db?_foreign_keys=on"`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.ChatStorageEncryptionKey,
+		"chat-storage-encryption-key", "",
+		config.ChatStorageEncryptionKey,
+		`encrypt message content and media keys in the chat storage database; existing plaintext rows are encrypted on startup --chat-storage-encryption-key <string> | example: --chat-storage-encryption-key="a-long-random-secret"`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.ChatStorageWriteBehindInterval,
+		"chat-storage-write-behind-interval", "",
+		config.ChatStorageWriteBehindInterval,
+		`buffer incoming messages and write them in batches at this interval, 0 writes each message immediately --chat-storage-write-behind-interval <duration> | example: --chat-storage-write-behind-interval=200ms`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.ChatStorageWriteBehindBatch,
+		"chat-storage-write-behind-batch", "",
+		config.ChatStorageWriteBehindBatch,
+		`write buffered messages early once this many are waiting --chat-storage-write-behind-batch <int> | example: --chat-storage-write-behind-batch=200`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.ChatStorageTrashRetention,
+		"chat-storage-trash-retention", "",
+		config.ChatStorageTrashRetention,
+		`keep deleted chats restorable in the trash for this long before purging them, 0 deletes chats immediately --chat-storage-trash-retention <duration> | example: --chat-storage-trash-retention=168h`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.ChatStorageCheckpointInterval,
+		"chat-storage-checkpoint-interval", "",
+		config.ChatStorageCheckpointInterval,
+		`checkpoint and truncate the chat storage WAL at this interval, 0 disables --chat-storage-checkpoint-interval <duration> | example: --chat-storage-checkpoint-interval=15m`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.ChatStorageVacuumInterval,
+		"chat-storage-vacuum-interval", "",
+		config.ChatStorageVacuumInterval,
+		`return free chat storage pages to the file system at this interval, 0 disables; takes effect once the database was compacted --chat-storage-vacuum-interval <duration> | example: --chat-storage-vacuum-interval=6h`,
+	)
 
 	// WhatsApp flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -341,6 +552,18 @@ func initFlags() {
 		config.WhatsappAutoDownloadMedia,
 		`auto download media from incoming messages --auto-download-media <true/false> | example: --auto-download-media=false`,
 	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappLinkPreview,
+		"link-preview", "",
+		config.WhatsappLinkPreview,
+		`attach a link preview to outgoing text messages containing a URL, unless the request sets link_preview --link-preview <true/false> | example: --link-preview=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappSettingAutoConvertVideo,
+		"auto-convert-video", "",
+		config.WhatsappSettingAutoConvertVideo,
+		`convert outgoing videos to H.264/AAC MP4 (max 1280px) when needed, requires ffmpeg --auto-convert-video <true/false> | example: --auto-convert-video=true`,
+	)
 	rootCmd.PersistentFlags().StringSliceVarP(
 		&config.WhatsappWebhook,
 		"webhook", "w",
@@ -353,18 +576,240 @@ func initFlags() {
 		config.WhatsappWebhookSecret,
 		`secure webhook request --webhook-secret <string> | example: --webhook-secret="super-secret-key"`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookSecretPrevious,
+		"webhook-secret-previous", "",
+		config.WhatsappWebhookSecretPrevious,
+		`previous webhook secret, also signed with while consumers move to the new one --webhook-secret-previous <string> | example: --webhook-secret-previous="old-secret-key"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookSignatureAlgorithm,
+		"webhook-signature-algorithm", "",
+		config.WhatsappWebhookSignatureAlgorithm,
+		`HMAC algorithm of the X-Webhook-Signature header: sha256 or sha512 --webhook-signature-algorithm <string> | example: --webhook-signature-algorithm="sha512"`,
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappWebhookInsecureSkipVerify,
 		"webhook-insecure-skip-verify", "",
 		config.WhatsappWebhookInsecureSkipVerify,
 		`skip TLS certificate verification for webhooks (INSECURE - use only for development/self-signed certs) --webhook-insecure-skip-verify <true/false> | example: --webhook-insecure-skip-verify=true`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookCACert,
+		"webhook-ca-cert", "",
+		config.WhatsappWebhookCACert,
+		`PEM CA bundle trusted for webhook TLS on top of the system roots --webhook-ca-cert <string> | example: --webhook-ca-cert="/etc/gowa/ca.pem"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookClientCert,
+		"webhook-client-cert", "",
+		config.WhatsappWebhookClientCert,
+		`PEM client certificate presented to webhooks requiring mutual TLS --webhook-client-cert <string> | example: --webhook-client-cert="/etc/gowa/client.pem"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookClientKey,
+		"webhook-client-key", "",
+		config.WhatsappWebhookClientKey,
+		`PEM private key of --webhook-client-cert --webhook-client-key <string> | example: --webhook-client-key="/etc/gowa/client-key.pem"`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookPresence,
+		"webhook-presence", "",
+		config.WhatsappWebhookPresence,
+		`forward contacts going online/offline and typing/recording as presence.update events (high volume) --webhook-presence <true/false> | example: --webhook-presence=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookOutgoing,
+		"webhook-outgoing", "",
+		config.WhatsappWebhookOutgoing,
+		`forward messages sent from the linked phone as message.outgoing events instead of message --webhook-outgoing <true/false> | example: --webhook-outgoing=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookOutgoingAPI,
+		"webhook-outgoing-api", "",
+		config.WhatsappWebhookOutgoingAPI,
+		`also forward messages sent through this API as message.outgoing events --webhook-outgoing-api <true/false> | example: --webhook-outgoing-api=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookChatOrdering,
+		"webhook-chat-ordering", "",
+		config.WhatsappWebhookChatOrdering,
+		`deliver webhook events of the same chat one at a time, in the order they happened --webhook-chat-ordering <true/false> | example: --webhook-chat-ordering=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookOutbox,
+		"webhook-outbox", "",
+		config.WhatsappWebhookOutbox,
+		`record message webhook events in chat storage with the message and redeliver those not delivered --webhook-outbox <true/false> | example: --webhook-outbox=true`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookRetryMaxAttempts,
+		"webhook-retry-max-attempts", "",
+		config.WhatsappWebhookRetryMaxAttempts,
+		`retry failed webhook calls from chat storage up to this many times before keeping them as dead letters, 0 disables the retry queue --webhook-retry-max-attempts <int> | example: --webhook-retry-max-attempts=10`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendQueueWorkers,
+		"send-queue-workers", "",
+		config.WhatsappSendQueueWorkers,
+		`send messages through a persistent queue with this many workers, keeping each chat in order and retrying once reconnected, 0 sends directly --send-queue-workers <int> | example: --send-queue-workers=4`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendRateLimit,
+		"send-rate-limit", "",
+		config.WhatsappSendRateLimit,
+		`messages per minute each device may send, rejecting faster sends with 429, 0 disables --send-rate-limit <int> | example: --send-rate-limit=20`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendRateLimitPerRecipient,
+		"send-rate-limit-per-recipient", "",
+		config.WhatsappSendRateLimitPerRecipient,
+		`messages per minute each device may send to one chat, 0 disables --send-rate-limit-per-recipient <int> | example: --send-rate-limit-per-recipient=5`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendRateLimitBurst,
+		"send-rate-limit-burst", "",
+		config.WhatsappSendRateLimitBurst,
+		`messages that may be sent back to back before the per-minute send limits apply --send-rate-limit-burst <int> | example: --send-rate-limit-burst=5`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendDailyCap,
+		"send-daily-cap", "",
+		config.WhatsappSendDailyCap,
+		`messages each device may send per day, 0 disables --send-daily-cap <int> | example: --send-daily-cap=1000`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappSendJitter,
+		"send-jitter", "",
+		config.WhatsappSendJitter,
+		`wait a random time up to this long before each send, 0 disables --send-jitter <duration> | example: --send-jitter=3s`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookBatchInterval,
+		"webhook-batch-interval", "",
+		config.WhatsappWebhookBatchInterval,
+		`post webhook events in batches collected for this long per URL, 0 disables batching --webhook-batch-interval <duration> | example: --webhook-batch-interval=500ms`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookBatchSize,
+		"webhook-batch-size", "",
+		config.WhatsappWebhookBatchSize,
+		`post a webhook batch early once it holds this many events --webhook-batch-size <int> | example: --webhook-batch-size=50`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookFilters,
+		"webhook-filters", "",
+		config.WhatsappWebhookFilters,
+		`allow/deny rules deciding which events are posted, first match wins --webhook-filters <string> | example: --webhook-filters="deny:chat_type=group"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookMaxPayloadSizes,
+		"webhook-max-payload-sizes", "",
+		config.WhatsappWebhookMaxPayloadSizes,
+		`largest body in bytes a webhook URL accepts, inline base64 of bigger payloads is replaced with signed download links --webhook-max-payload-sizes <string> | example: --webhook-max-payload-sizes="https://yourwebhook.site/handler=1048576"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookMediaBaseURL,
+		"webhook-media-base-url", "",
+		config.WhatsappWebhookMediaBaseURL,
+		`public URL of this instance used in webhook media download links --webhook-media-base-url <string> | example: --webhook-media-base-url="https://gowa.example.com"`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookMediaURLTTL,
+		"webhook-media-url-ttl", "",
+		config.WhatsappWebhookMediaURLTTL,
+		`how long webhook media download links stay valid --webhook-media-url-ttl <duration> | example: --webhook-media-url-ttl=48h`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookFormat,
+		"webhook-format", "",
+		config.WhatsappWebhookFormat,
+		`webhook payload format: default, or chatwoot to shape message events for the Chatwoot API --webhook-format <string> | example: --webhook-format="chatwoot"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookIdentifier,
+		"webhook-identifier", "",
+		config.WhatsappWebhookIdentifier,
+		`identifier of users in webhook payloads: pn (phone numbers), lid, or both to always include from_lid/from_pn and the like --webhook-identifier <string> | example: --webhook-identifier="lid"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookTemplates,
+		"webhook-templates", "",
+		config.WhatsappWebhookTemplates,
+		`reshape the body posted to a webhook URL with a Go template file rendering a JSON object --webhook-templates <string> | example: --webhook-templates="https://n8n.example.com/webhook/wa=/etc/gowa/n8n.tmpl"`,
+	)
 	rootCmd.PersistentFlags().StringSliceVarP(
 		&config.WhatsappWebhookEvents,
 		"webhook-events", "",
 		config.WhatsappWebhookEvents,
 		`whitelist of events to forward to webhook (empty = all events) --webhook-events <string> | example: --webhook-events="message,message.ack,group.participants"`,
 	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappSendPolicyRules,
+		"send-policy-rules", "",
+		config.WhatsappSendPolicyRules,
+		`regex content rules applied to outgoing text and captions, "block:<regex>" rejects and "strip:<regex>" removes matches --send-policy-rules <string> | example: --send-policy-rules="block:(?i)casino,strip:https?://\S+"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappSendPolicyURL,
+		"send-policy-url", "",
+		config.WhatsappSendPolicyURL,
+		`callback that can allow, modify or reject every outgoing text before it is sent --send-policy-url <string> | example: --send-policy-url="https://yourcallback.com/policy"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappQuietHours,
+		"quiet-hours", "",
+		config.WhatsappQuietHours,
+		`daily window during which bulk send campaigns are held --quiet-hours <HH:MM-HH:MM> | example: --quiet-hours="22:00-08:00"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappQuietHoursTimezone,
+		"quiet-hours-timezone", "",
+		config.WhatsappQuietHoursTimezone,
+		`IANA time zone of the quiet hours window, defaults to the server time zone --quiet-hours-timezone <string> | example: --quiet-hours-timezone="Asia/Jakarta"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappQuietHoursReply,
+		"quiet-hours-reply", "",
+		config.WhatsappQuietHoursReply,
+		`auto-reply sent during quiet hours instead of --autoreply, {opens_at} is replaced by the reopening time --quiet-hours-reply <string> | example: --quiet-hours-reply="We are closed, back at {opens_at}"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappTenantRoutes,
+		"tenant-routes", "",
+		config.WhatsappTenantRoutes,
+		`route chats to tenants, adding tenant_id to webhook payloads; first match wins --tenant-routes <string> | example: --tenant-routes="acme=120363025246125486@g.us,globex=@s.whatsapp.net"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappTenantWebhooks,
+		"tenant-webhooks", "",
+		config.WhatsappTenantWebhooks,
+		`deliver a tenant's events to its own webhook URLs instead of --webhook --tenant-webhooks <string> | example: --tenant-webhooks="acme=https://acme.example.com/callback"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappEventBusDriver,
+		"event-bus-driver", "",
+		config.WhatsappEventBusDriver,
+		`also publish webhook events to a message broker: kafka, nats, rabbitmq, redis-stream or redis-pubsub --event-bus-driver <string> | example: --event-bus-driver="nats"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappEventBusURL,
+		"event-bus-url", "",
+		config.WhatsappEventBusURL,
+		`event bus address: host:port brokers for kafka (comma-separated), nats://, amqp:// or redis:// URL --event-bus-url <string> | example: --event-bus-url="nats://localhost:4222"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappEventBusTopic,
+		"event-bus-topic", "",
+		config.WhatsappEventBusTopic,
+		`event bus topic pattern with {event} and {device_id} placeholders, defaults to whatsapp.{event} (whatsapp:{device_id} for redis-stream) --event-bus-topic <string> | example: --event-bus-topic="gowa.{event}"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappEventBusTopics,
+		"event-bus-topics", "",
+		config.WhatsappEventBusTopics,
+		`event bus topic per event, overriding --event-bus-topic --event-bus-topics <string> | example: --event-bus-topics="message=wa-messages"`,
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappAccountValidation,
 		"account-validation", "",
@@ -377,6 +822,12 @@ func initFlags() {
 		config.WhatsappAutoRejectCall,
 		`auto reject incoming calls --auto-reject-call <true/false> | example: --auto-reject-call=true`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappAutoRejectCallMessage,
+		"auto-reject-call-message", "",
+		config.WhatsappAutoRejectCallMessage,
+		`text sent to a caller after an auto-rejected call --auto-reject-call-message <string> | example: --auto-reject-call-message="We don't take calls here, please send a message"`,
+	)
 	rootCmd.PersistentFlags().StringVarP(
 		&config.WhatsappPresenceOnConnect,
 		"presence-on-connect", "",
@@ -401,6 +852,36 @@ func initFlags() {
 		config.WhatsappPresencePulseDuration,
 		`duration to stay available during a presence pulse --presence-pulse-duration <duration> | example: --presence-pulse-duration=5m`,
 	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappGroupSnapshotRefreshInterval,
+		"group-snapshot-refresh-interval", "",
+		config.WhatsappGroupSnapshotRefreshInterval,
+		`how often stored group metadata and participants are refreshed, 0 disables --group-snapshot-refresh-interval <duration> | example: --group-snapshot-refresh-interval=6h`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappLIDFailedLookupTTL,
+		"lid-failed-lookup-ttl", "",
+		config.WhatsappLIDFailedLookupTTL,
+		`how long a failed LID lookup holds back WhatsApp queries for the same JID --lid-failed-lookup-ttl <duration> | example: --lid-failed-lookup-ttl=10m`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappLIDProactiveTimeout,
+		"lid-proactive-timeout", "",
+		config.WhatsappLIDProactiveTimeout,
+		`how long to wait for the user info query of a phone number with no known LID --lid-proactive-timeout <duration> | example: --lid-proactive-timeout=5s`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappLIDCleanupInterval,
+		"lid-cleanup-interval", "",
+		config.WhatsappLIDCleanupInterval,
+		`how often expired failed LID lookups are dropped and pending ones retried, 0 disables --lid-cleanup-interval <duration> | example: --lid-cleanup-interval=2m`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappEventLatencyBudget,
+		"event-latency-budget", "",
+		config.WhatsappEventLatencyBudget,
+		`log a slow-event record when an incoming message takes longer than this from receipt to webhook delivery, 0 disables --event-latency-budget <duration> | example: --event-latency-budget=3s`,
+	)
 
 	// WhatsApp Proxy flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -592,8 +1073,24 @@ func initApp() {
 		logrus.Fatalf("failed to initialize chat storage: %v", err)
 	}
 
-	chatStorageRepo = chatstorage.NewStorageRepository(chatStorageDB)
-	chatStorageRepo.InitializeSchema()
+	if config.ChatStorageEncryptionKey != "" {
+		chatStorageRepo, err = chatstorage.NewEncryptedStorageRepository(chatStorageDB, config.ChatStorageEncryptionKey)
+		if err != nil {
+			logrus.Fatalf("failed to initialize chat storage encryption: %v", err)
+		}
+	} else {
+		chatStorageRepo = chatstorage.NewStorageRepository(chatStorageDB)
+	}
+	if err := chatStorageRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize chat storage schema: %v", err)
+	}
+	if repo, ok := chatStorageRepo.(*chatstorage.SQLiteRepository); ok && config.ChatStorageWriteBehindInterval > 0 {
+		repo.EnableWriteBehind(config.ChatStorageWriteBehindInterval, config.ChatStorageWriteBehindBatch)
+	}
+	if repo, ok := chatStorageRepo.(*chatstorage.SQLiteRepository); ok && config.WhatsappWebhookOutbox {
+		repo.EnableEventsOutbox()
+	}
+	utils.SetLIDMappingStore(whatsapp.NewLIDMappingStore(chatStorageRepo))
 
 	whatsappDB, err := whatsapp.InitWaDB(ctx, config.DBURI)
 	if err != nil {
@@ -610,6 +1107,24 @@ func initApp() {
 		}
 	}
 
+	if err := whatsapp.ValidateWebhookSignatureAlgorithm(config.WhatsappWebhookSignatureAlgorithm); err != nil {
+		logrus.Fatalf("invalid webhook signature algorithm: %v", err)
+	}
+	if err := whatsapp.ValidateWebhookTLS(); err != nil {
+		logrus.Fatalf("invalid webhook TLS configuration: %v", err)
+	}
+	if err := whatsapp.ValidateWebhookFilters(config.WhatsappWebhookFilters); err != nil {
+		logrus.Fatalf("invalid webhook filters: %v", err)
+	}
+
+	if config.WhatsappEventBusDriver != "" {
+		publisher, err := eventbus.New(config.WhatsappEventBusDriver, config.WhatsappEventBusURL)
+		if err != nil {
+			logrus.Fatalf("failed to connect event bus: %v", err)
+		}
+		whatsapp.SetEventPublisher(publisher)
+	}
+
 	whatsappCli = whatsapp.InitWaCLI(ctx, whatsappDB, keysDB, chatStorageRepo)
 
 	// Initialize device manager and usecase for multi-device support
@@ -625,8 +1140,10 @@ func initApp() {
 	userUsecase = usecase.NewUserService(chatStorageRepo)
 	messageUsecase = usecase.NewMessageService(chatStorageRepo)
 	groupUsecase = usecase.NewGroupService()
-	newsletterUsecase = usecase.NewNewsletterService()
+	newsletterUsecase = usecase.NewNewsletterService(chatStorageRepo)
 	deviceUsecase = usecase.NewDeviceService(dm)
+	jobUsecase = usecase.NewJobService(chatStorageRepo)
+	settingsUsecase = usecase.NewSettingsService()
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"sync"
 	"time"
 
 	"go.mau.fi/whatsmeow/proto/waCompanionReg"
@@ -20,6 +21,10 @@ var (
 	McpPort = "8080"
 	McpHost = "localhost"
 
+	// AppGrpcPort serves the gRPC API (ui/grpc/proto/whatsapp.proto) on
+	// AppHost alongside REST; empty disables it.
+	AppGrpcPort = ""
+
 	PathQrCode    = "statics/qrcode"
 	PathSendItems = "statics/senditems"
 	PathMedia     = "statics/media"
@@ -28,27 +33,157 @@ var (
 	DBURI     = "file:storages/whatsapp.db"
 	DBKeysURI = ""
 
-	WhatsappAutoReplyMessage          string
-	WhatsappAutoMarkRead              = false // Auto-mark incoming messages as read
-	WhatsappAutoDownloadMedia         = true  // Auto-download media from incoming messages
-	WhatsappWebhook                   []string
-	WhatsappWebhookSecret             = "secret"
-	WhatsappWebhookInsecureSkipVerify = false          // Skip TLS certificate verification for webhooks (insecure)
-	WhatsappWebhookEvents             []string         // Whitelist of events to forward to webhook (empty = all events)
-	WhatsappAutoRejectCall                     = false // Auto-reject incoming calls
-	WhatsappLogLevel                           = "ERROR"
-	WhatsappSettingMaxImageSize       int64    = 20000000  // 20MB
-	WhatsappSettingMaxFileSize        int64    = 50000000  // 50MB
-	WhatsappSettingMaxVideoSize       int64    = 100000000 // 100MB
-	WhatsappSettingMaxDownloadSize    int64    = 500000000 // 500MB
-	WhatsappTypeUser                           = "@s.whatsapp.net"
-	WhatsappTypeGroup                          = "@g.us"
-	WhatsappTypeLid                            = "@lid"
-	WhatsappAccountValidation                  = true
-	WhatsappPresenceOnConnect                  = "unavailable" // Presence to send on connect: "available", "unavailable", or "none"
-	WhatsappPresencePulseEnabled               = true          // Periodically pulse presence available, then unavailable
-	WhatsappPresencePulseInterval              = 24 * time.Hour
-	WhatsappPresencePulseDuration              = 5 * time.Minute
+	WhatsappAutoReplyMessage             string
+	WhatsappAutoMarkRead                 = false // Auto-mark incoming messages as read
+	WhatsappAutoDownloadMedia            = true  // Auto-download media from incoming messages
+	WhatsappLinkPreview                  = false // Attach a link preview to outgoing text messages containing a URL
+	WhatsappWebhook                      []string
+	WhatsappWebhookSecret                = "secret"
+	WhatsappWebhookInsecureSkipVerify    = false          // Skip TLS certificate verification for webhooks (insecure)
+	WhatsappWebhookEvents                []string         // Whitelist of events to forward to webhook (empty = all events)
+	WhatsappWebhookChatOrdering                   = true  // Deliver each chat's webhook events one at a time, in order
+	WhatsappWebhookOutbox                         = false // Record message webhook events with the stored message and redeliver missed ones
+	WhatsappWebhookRetryMaxAttempts               = 10    // Retry failed webhook calls from chat storage up to this many times, then keep them as dead letters; 0 disables
+	WhatsappSendQueueWorkers                      = 0     // Send through the persistent outgoing queue with this many workers; 0 sends directly
+	WhatsappSendRateLimit                         = 0     // Messages per minute each device may send; 0 disables
+	WhatsappSendRateLimitPerRecipient             = 0     // Messages per minute each device may send to one chat; 0 disables
+	WhatsappSendRateLimitBurst                    = 5     // Messages that may be sent back to back before the per-minute limits apply
+	WhatsappSendDailyCap                          = 0     // Messages each device may send per day; 0 disables
+	WhatsappAutoRejectCall                        = false // Auto-reject incoming calls
+	WhatsappLogLevel                              = "ERROR"
+	WhatsappSettingMaxImageSize          int64    = 20000000  // 20MB
+	WhatsappSettingMaxFileSize           int64    = 50000000  // 50MB
+	WhatsappSettingMaxVideoSize          int64    = 100000000 // 100MB
+	WhatsappSettingAutoConvertVideo               = false     // Convert outgoing videos to H.264/AAC MP4 when clients may not play them
+	WhatsappSettingMaxDownloadSize       int64    = 500000000 // 500MB
+	WhatsappTypeUser                              = "@s.whatsapp.net"
+	WhatsappTypeGroup                             = "@g.us"
+	WhatsappTypeLid                               = "@lid"
+	WhatsappAccountValidation                     = true
+	WhatsappPresenceOnConnect                     = "unavailable" // Presence to send on connect: "available", "unavailable", or "none"
+	WhatsappPresencePulseEnabled                  = true          // Periodically pulse presence available, then unavailable
+	WhatsappPresencePulseInterval                 = 24 * time.Hour
+	WhatsappPresencePulseDuration                 = 5 * time.Minute
+	WhatsappGroupSnapshotRefreshInterval          = 6 * time.Hour   // Refresh stored group metadata and participants; 0 disables
+	WhatsappEventLatencyBudget                    = 5 * time.Second // Log events slower than this from receipt to webhook delivery; 0 disables
+	WhatsappSendJitter                            = 0 * time.Second // Wait a random time up to this long before each send; 0 disables
+
+	// LID resolver tuning. A failed LID lookup holds back WhatsApp queries for
+	// the same JID for WhatsappLIDFailedLookupTTL, and a user info query for a
+	// phone number no store knows waits at most WhatsappLIDProactiveTimeout.
+	// Every WhatsappLIDCleanupInterval, plus a random jitter of up to a fifth of
+	// it, expired failures are dropped and the pending ones are retried, so a
+	// transient failure heals without waiting for the next message; 0 disables.
+	WhatsappLIDFailedLookupTTL  = 5 * time.Minute
+	WhatsappLIDProactiveTimeout = 3 * time.Second
+	WhatsappLIDCleanupInterval  = time.Minute
+
+	// Outgoing message content policy. WhatsappSendPolicyRules are embedded
+	// "block:<regex>" / "strip:<regex>" rules evaluated in order against message
+	// text and captions. WhatsappSendPolicyURL, when set, receives every outgoing
+	// text and answers allow, modify or reject; sends fail closed if it errors.
+	WhatsappSendPolicyRules []string
+	WhatsappSendPolicyURL   = ""
+
+	// Quiet hours. WhatsappQuietHours ("HH:MM-HH:MM", may cross midnight) is
+	// evaluated in WhatsappQuietHoursTimezone; bulk send campaigns are held
+	// until it ends, then resume on their own. WhatsappQuietHoursReply, when set, is sent as the auto-reply
+	// during the window; "{opens_at}" is replaced by the reopening time.
+	WhatsappQuietHours         = ""
+	WhatsappQuietHoursTimezone = ""
+	WhatsappQuietHoursReply    = ""
+
+	// Multi-tenant event routing. WhatsappTenantRoutes maps chats to tenants with
+	// "<tenant>=<jid>" rules, where jid is an exact JID, an address-space wildcard
+	// ("@g.us", "@s.whatsapp.net", "@lid") or "*"; the first match wins and its
+	// tenant is added to webhook payloads as tenant_id. WhatsappTenantWebhooks
+	// ("<tenant>=<url>") deliver a tenant's events to its own URLs instead of WhatsappWebhook.
+	WhatsappTenantRoutes   []string
+	WhatsappTenantWebhooks []string
+
+	// Webhook payload shape. "default" posts the event payload as documented in
+	// docs/webhook-payload.md; "chatwoot" posts message events shaped like the
+	// Chatwoot API calls (contact, conversation source_id and message), so a
+	// receiver can relay them to a Chatwoot it manages. Other events keep the
+	// default shape.
+	WhatsappWebhookFormat = "default"
+	// WhatsappWebhookIdentifier picks the identifier of users in webhook
+	// payloads: "pn" uses phone numbers for from, chat_id and the like, adding
+	// the LID in from_lid, chat_lid, ... when known; "lid" uses LIDs, adding the
+	// phone number in from_pn, chat_pn, ...; "both" uses phone numbers and
+	// always includes both the _lid and _pn fields. Either falls back to the
+	// other identifier when one is unknown.
+	WhatsappWebhookIdentifier = "pn"
+	// WhatsappWebhookTemplates ("<url>=<template file>") reshape the body posted
+	// to a webhook URL with a Go text/template rendered against the payload; the
+	// output must be a JSON object. Template files are read once, on first use.
+	WhatsappWebhookTemplates []string
+
+	// Event bus publishing. With WhatsappEventBusDriver ("kafka", "nats" or
+	// "rabbitmq", "redis-stream" or "redis-pubsub") set, every event forwarded
+	// to webhooks is also published to WhatsappEventBusURL, keyed by chat.
+	// Topics follow WhatsappEventBusTopic, where "{event}" is the event name and
+	// "{device_id}" the device JID (empty: the driver's default), unless
+	// WhatsappEventBusTopics ("<event>=<topic>") names one for the event.
+	WhatsappEventBusDriver = ""
+	WhatsappEventBusURL    = ""
+	WhatsappEventBusTopic  = ""
+	WhatsappEventBusTopics []string
+
+	// Webhook signing. Besides X-Hub-Signature-256, every webhook request carries
+	// X-Webhook-Timestamp and X-Webhook-Signature, an HMAC with
+	// WhatsappWebhookSignatureAlgorithm ("sha256" or "sha512") of
+	// "<timestamp>.<body>". While WhatsappWebhookSecretPrevious is set, the
+	// header holds a signature with each secret so the secret can be rotated
+	// without rejected deliveries.
+	WhatsappWebhookSecretPrevious     = ""
+	WhatsappWebhookSignatureAlgorithm = "sha256"
+
+	// TLS of the webhook HTTP client, also used for the send policy hook.
+	// WhatsappWebhookCACert is a PEM bundle trusted on top of the system roots;
+	// WhatsappWebhookClientCert and WhatsappWebhookClientKey are PEM files
+	// presented to receivers that require mutual TLS.
+	WhatsappWebhookCACert     = ""
+	WhatsappWebhookClientCert = ""
+	WhatsappWebhookClientKey  = ""
+
+	// Webhook batching. With WhatsappWebhookBatchInterval set, events for each
+	// device and webhook URL are collected and posted together as one
+	// {"batch": true, "events": [...]} request once the interval passed since
+	// the first of them, or as soon as WhatsappWebhookBatchSize are waiting.
+	WhatsappWebhookBatchInterval time.Duration
+	WhatsappWebhookBatchSize     = 100
+
+	// Webhook payload size limits. WhatsappWebhookMaxPayloadSizes
+	// ("<url>=<bytes>") cap the body posted to a webhook URL; inline base64
+	// values of a larger payload are stored under PathStorages and replaced with
+	// download URLs signed with WhatsappWebhookSecret, valid for
+	// WhatsappWebhookMediaURLTTL. WhatsappWebhookMediaBaseURL is the public URL
+	// of this instance, prefixed to those links (empty: relative links).
+	WhatsappWebhookMaxPayloadSizes []string
+	WhatsappWebhookMediaBaseURL    = ""
+	WhatsappWebhookMediaURLTTL     = 24 * time.Hour
+
+	// Outgoing messages. WhatsappWebhookOutgoing forwards messages sent from the
+	// linked phone as message.outgoing events instead of message events with
+	// is_from_me set; WhatsappWebhookOutgoingAPI also forwards the messages sent
+	// through this API as message.outgoing, which WhatsApp never echoes back.
+	WhatsappWebhookOutgoing    = false
+	WhatsappWebhookOutgoingAPI = false
+
+	// WhatsappWebhookFilters ("<allow|deny>:<conditions>") decide per webhook
+	// URL which events are posted, by chat, chat type, media type and from_me.
+	// The first matching rule wins; events no rule matches are posted.
+	WhatsappWebhookFilters []string
+
+	// WhatsappWebhookPresence forwards contacts going online or offline and
+	// typing or recording in a chat as presence.update events. Off by default
+	// since presence is high volume.
+	WhatsappWebhookPresence = false
+
+	// WhatsappAutoRejectCallMessage is texted to a caller after
+	// WhatsappAutoRejectCall rejected their call (empty: no message).
+	WhatsappAutoRejectCallMessage = ""
 
 	// Proxy configuration for WhatsApp connections
 	WhatsappProxyURL         string  // Proxy URL (http://, https://, socks5://)
@@ -59,6 +194,23 @@ var (
 	ChatStorageURI               = "file:storages/chatstorage.db"
 	ChatStorageEnableForeignKeys = true
 	ChatStorageEnableWAL         = true
+	// ChatStorageEncryptionKey, when set, encrypts message content, media keys
+	// and edit history in the chat storage database with AES-256-GCM. Existing
+	// plaintext rows are encrypted on startup; keep the key, it cannot be recovered.
+	ChatStorageEncryptionKey = ""
+	// ChatStorageWriteBehindInterval, when above zero, buffers stored messages
+	// and writes them in one transaction per interval or per
+	// ChatStorageWriteBehindBatch messages, whichever comes first.
+	ChatStorageWriteBehindInterval = time.Duration(0)
+	ChatStorageWriteBehindBatch    = 100
+	// ChatStorageTrashRetention is how long a deleted chat stays in the trash,
+	// where it can be restored, before it is purged. 0 deletes chats immediately.
+	ChatStorageTrashRetention = 30 * 24 * time.Hour
+	// ChatStorageCheckpointInterval is how often the WAL is checkpointed and
+	// truncated; ChatStorageVacuumInterval how often free pages are returned to
+	// the file system. 0 turns either routine off.
+	ChatStorageCheckpointInterval = 30 * time.Minute
+	ChatStorageVacuumInterval     = 24 * time.Hour
 
 	ChatwootEnabled   = false
 	ChatwootURL       = ""
@@ -138,3 +290,15 @@ var (
 	ChatwootMessageRead   = false
 	ChatwootMessageDelete = false
 )
+
+// SettingsMu guards the settings a settings import changes at runtime. The
+// import writes them under Lock; code reading them while the app runs goes
+// through Setting.
+var SettingsMu sync.RWMutex
+
+// Setting returns the current value of the runtime setting v points to.
+func Setting[T any](v *T) T {
+	SettingsMu.RLock()
+	defer SettingsMu.RUnlock()
+	return *v
+}
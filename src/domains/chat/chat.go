@@ -1,5 +1,13 @@
 package chat
 
+import (
+	"encoding/json"
+	"io"
+	"mime/multipart"
+
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+)
+
 // Request and Response structures for chat operations
 
 type ListChatsRequest struct {
@@ -8,6 +16,10 @@ type ListChatsRequest struct {
 	Search   string `json:"search" query:"search"`
 	HasMedia bool   `json:"has_media" query:"has_media"`
 	Archived *bool  `json:"archived" query:"archived"`
+	Pinned   *bool  `json:"pinned" query:"pinned"`
+	Muted    *bool  `json:"muted" query:"muted"`
+	Unread   *bool  `json:"unread" query:"unread"`
+	LabelID  string `json:"label_id" query:"label_id"`
 }
 
 type ListChatsResponse struct {
@@ -24,6 +36,8 @@ type GetChatMessagesRequest struct {
 	MediaOnly bool    `json:"media_only" query:"media_only"`
 	IsFromMe  *bool   `json:"is_from_me" query:"is_from_me"`
 	Search    string  `json:"search" query:"search"`
+	// Cursor is the opaque next_cursor from a previous page; it cannot be combined with offset.
+	Cursor string `json:"cursor" query:"cursor"`
 }
 
 type GetChatMessagesResponse struct {
@@ -32,6 +46,23 @@ type GetChatMessagesResponse struct {
 	ChatInfo   ChatInfo           `json:"chat_info"`
 }
 
+// Message context operations
+type GetMessageContextRequest struct {
+	ChatJID   string `json:"chat_jid" uri:"chat_jid"`
+	MessageID string `json:"message_id" uri:"message_id"`
+	// Before and After are how many neighbouring messages to return on each side.
+	Before int `json:"before" query:"before"`
+	After  int `json:"after" query:"after"`
+}
+
+// GetMessageContextResponse is a message with its neighbours, both sides oldest first.
+type GetMessageContextResponse struct {
+	Before   []MessageInfo `json:"before"`
+	Message  MessageInfo   `json:"message"`
+	After    []MessageInfo `json:"after"`
+	ChatInfo ChatInfo      `json:"chat_info"`
+}
+
 // Pin Chat operations
 type PinChatRequest struct {
 	ChatJID string `json:"chat_jid" uri:"chat_jid"`
@@ -53,6 +84,14 @@ type ChatInfo struct {
 	CreatedAt           string `json:"created_at"`
 	UpdatedAt           string `json:"updated_at"`
 	Archived            bool   `json:"archived"`
+	Pinned              bool   `json:"pinned"`
+	Muted               bool   `json:"muted"`
+	MutedUntil          string `json:"muted_until,omitempty"`
+	UnreadCount         int    `json:"unread_count"`
+	// ParticipantCount is set for groups with a stored participant snapshot.
+	ParticipantCount int `json:"participant_count,omitempty"`
+	// Labels are the IDs of the WhatsApp Business labels applied to the chat.
+	Labels []string `json:"labels,omitempty"`
 }
 
 type MessageInfo struct {
@@ -65,6 +104,15 @@ type MessageInfo struct {
 	IsFromMe   bool           `json:"is_from_me"`
 	MediaType  string         `json:"media_type"`
 	Reactions  []ReactionInfo `json:"reactions,omitempty"`
+	// QuotedMessageID/QuotedParticipant are set when the message is a reply;
+	// QuotedMessage carries the quoted message when it is stored in this chat.
+	QuotedMessageID   string             `json:"quoted_message_id,omitempty"`
+	QuotedParticipant string             `json:"quoted_participant,omitempty"`
+	QuotedMessage     *QuotedMessageInfo `json:"quoted_message,omitempty"`
+	// Poll carries the question and current results when the message is a poll.
+	Poll *PollInfo `json:"poll,omitempty"`
+	// Pin is set while the message is pinned in the chat.
+	Pin *PinInfo `json:"pin,omitempty"`
 	// CallMetadata is JSON when media_type is "call" (incoming call log).
 	CallMetadata string `json:"call_metadata,omitempty"`
 	Filename     string `json:"filename"`
@@ -72,12 +120,26 @@ type MessageInfo struct {
 	FileLength   uint64 `json:"file_length"`
 	CreatedAt    string `json:"created_at"`
 	UpdatedAt    string `json:"updated_at"`
+	// Labels are the IDs of the WhatsApp Business labels applied to the message.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// QuotedMessageInfo is the inline preview of the message a reply quotes.
+type QuotedMessageInfo struct {
+	ID        string `json:"id"`
+	SenderJID string `json:"sender_jid"`
+	Content   string `json:"content"`
+	Timestamp string `json:"timestamp"`
+	IsFromMe  bool   `json:"is_from_me"`
+	MediaType string `json:"media_type"`
 }
 
 type PaginationResponse struct {
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
 	Total  int `json:"total"`
+	// NextCursor is set when more messages may follow; pass it back as cursor to fetch the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // Disappearing Messages operations
@@ -105,3 +167,348 @@ type ArchiveChatResponse struct {
 	ChatJID  string `json:"chat_jid"`
 	Archived bool   `json:"archived"`
 }
+
+// Mute Chat operations
+type MuteChatRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+	Muted   bool   `json:"muted"`
+	// Duration in seconds; 0 mutes until unmuted. Ignored when unmuting.
+	Duration int64 `json:"duration"`
+}
+
+type MuteChatResponse struct {
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	ChatJID    string `json:"chat_jid"`
+	Muted      bool   `json:"muted"`
+	MutedUntil string `json:"muted_until,omitempty"`
+}
+
+// Mark Chat Read operations
+type MarkChatReadRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+	Read    bool   `json:"read"`
+}
+
+type MarkChatReadResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	ChatJID string `json:"chat_jid"`
+	Read    bool   `json:"read"`
+}
+
+// Chat trash operations
+type DeleteChatRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+}
+
+type DeleteChatResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	ChatJID string `json:"chat_jid"`
+	// Trashed is false when the trash is disabled and the chat was deleted for good.
+	Trashed bool `json:"trashed"`
+	// PurgeAt is when a trashed chat will be deleted for good.
+	PurgeAt string `json:"purge_at,omitempty"`
+}
+
+type RestoreChatRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+}
+
+type RestoreChatResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	ChatJID string `json:"chat_jid"`
+}
+
+type TrashedChatInfo struct {
+	ChatInfo
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at"`
+}
+
+type ListTrashedChatsResponse struct {
+	Data []TrashedChatInfo `json:"data"`
+}
+
+// Merge Chat operations
+type MergeChatRequest struct {
+	ChatJID   string `json:"chat_jid" uri:"chat_jid"`
+	TargetJID string `json:"target_jid"`
+	// DryRun previews what the merge would move without changing anything.
+	DryRun bool `json:"dry_run"`
+}
+
+// MergeChatResponse counts what was moved from chat_jid into target_jid, or
+// what would be moved for a dry run.
+type MergeChatResponse struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	ChatJID   string `json:"chat_jid"`
+	TargetJID string `json:"target_jid"`
+	DryRun    bool   `json:"dry_run"`
+	// TargetExists is false when the chat is renamed to target_jid.
+	TargetExists bool `json:"target_exists"`
+	Messages     int  `json:"messages"`
+	// DuplicateMessages were stored in both chats; the target copy is kept.
+	DuplicateMessages int `json:"duplicate_messages"`
+	Reactions         int `json:"reactions"`
+	Edits             int `json:"edits"`
+	Polls             int `json:"polls"`
+	PollVotes         int `json:"poll_votes"`
+	ChatLabels        int `json:"chat_labels"`
+	MessageLabels     int `json:"message_labels"`
+	ReadReceipts      int `json:"read_receipts"`
+	OutboxEvents      int `json:"outbox_events"`
+	Drafts            int `json:"drafts"`
+}
+
+// Chat Stats operations
+type ChatStatsRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+	// Days is how many days, including today, the daily histogram covers.
+	Days int `json:"days" query:"days"`
+}
+
+type ChatStatsResponse struct {
+	ChatJID          string `json:"chat_jid"`
+	TotalMessages    int    `json:"total_messages"`
+	SentMessages     int    `json:"sent_messages"`
+	ReceivedMessages int    `json:"received_messages"`
+	// MediaTypes counts messages per media type; messages without media count as "text".
+	MediaTypes       map[string]int `json:"media_types"`
+	FirstMessageTime string         `json:"first_message_time,omitempty"`
+	LastMessageTime  string         `json:"last_message_time,omitempty"`
+	// TopSenders is only filled for groups.
+	TopSenders []SenderStat `json:"top_senders,omitempty"`
+	Daily      []DailyStat  `json:"daily"`
+}
+
+type SenderStat struct {
+	JID   string `json:"jid"`
+	Count int    `json:"count"`
+}
+
+type DailyStat struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// Label operations
+type LabelInfo struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Color        int32  `json:"color"`
+	PredefinedID int32  `json:"predefined_id,omitempty"`
+	OrderIndex   int32  `json:"order_index"`
+	ChatCount    int    `json:"chat_count"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type ListLabelsResponse struct {
+	Data []LabelInfo `json:"data"`
+}
+
+type LabelChatRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+	LabelID string `json:"label_id"`
+	// LabelName is only used in the response message.
+	LabelName string `json:"label_name"`
+	Labeled   bool   `json:"labeled"`
+}
+
+type LabelChatResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	ChatJID string `json:"chat_jid"`
+	LabelID string `json:"label_id"`
+	Labeled bool   `json:"labeled"`
+}
+
+// Search index operations
+const (
+	SearchReindexStatusIdle      = "idle"
+	SearchReindexStatusRunning   = "running"
+	SearchReindexStatusCompleted = "completed"
+	SearchReindexStatusFailed    = "failed"
+	SearchReindexStatusCancelled = "cancelled"
+)
+
+// SearchReindexProgress reports the state of the most recent search index rebuild.
+// The rebuild runs as a job; JobID can be used with the jobs API.
+type SearchReindexProgress struct {
+	JobID          string `json:"job_id,omitempty"`
+	Status         string `json:"status"`
+	CurrentStep    string `json:"current_step,omitempty"`
+	CompletedSteps int    `json:"completed_steps"`
+	TotalSteps     int    `json:"total_steps"`
+	StartedAt      string `json:"started_at,omitempty"`
+	CompletedAt    string `json:"completed_at,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+type SearchReindexResponse struct {
+	// Started is false when a rebuild was already running; Progress then describes that run.
+	Started  bool                  `json:"started"`
+	Progress SearchReindexProgress `json:"progress"`
+}
+
+type LIDBackfillResponse struct {
+	// Started is false when a backfill was already running for the device; Job then describes that run.
+	Started bool              `json:"started"`
+	Job     domainJob.JobInfo `json:"job"`
+}
+
+// StorageCompactionResponse reports an on-demand compaction of the chat storage database.
+type StorageCompactionResponse struct {
+	SizeBefore int64 `json:"size_before"`
+	SizeAfter  int64 `json:"size_after"`
+	FreedBytes int64 `json:"freed_bytes"`
+	// WALBusy is set when open readers kept the WAL from being fully truncated.
+	WALBusy    bool  `json:"wal_busy"`
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// Webhook dead letter operations
+type WebhookDeadLetter struct {
+	ID        int64  `json:"id"`
+	URL       string `json:"url"`
+	Event     string `json:"event"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error"`
+	// Payload is the webhook body that could not be delivered.
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt string          `json:"created_at"`
+	DeadAt    string          `json:"dead_at"`
+}
+
+type ListWebhookDeadLettersResponse struct {
+	Data []WebhookDeadLetter `json:"data"`
+}
+
+type RetryWebhookDeadLetterRequest struct {
+	DeliveryID int64 `json:"delivery_id" uri:"delivery_id"`
+}
+
+type RetryWebhookDeadLetterResponse struct {
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	DeliveryID int64  `json:"delivery_id"`
+}
+
+// ReplayWebhookDeadLettersRequest replays every dead letter that died at or
+// after Since (RFC3339).
+type ReplayWebhookDeadLettersRequest struct {
+	Since string `json:"since" query:"since"`
+}
+
+type ReplayWebhookDeadLettersResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Replayed int64  `json:"replayed"`
+}
+
+// Storage doctor operations
+type StorageDoctorRequest struct {
+	// Repair fixes what the check finds; without it the report is read-only.
+	Repair bool `json:"repair"`
+}
+
+// StorageDoctorReport lists the chat storage inconsistencies found for a device.
+// When Repaired is set the issues were found before the repair ran; RepairErrors
+// lists the ones it could not fix.
+type StorageDoctorReport struct {
+	DeviceID string `json:"device_id"`
+	Repaired bool   `json:"repaired"`
+	Issues   int    `json:"issues"`
+	// EmptyChats are chats without messages; repair deletes them.
+	EmptyChats []string `json:"empty_chats"`
+	// MissingChats are chats with messages but no chat row; repair recreates the row.
+	MissingChats []MissingChatInfo `json:"missing_chats"`
+	// DuplicateLIDChats are @lid chats whose phone number is known; repair merges
+	// each into its phone number chat.
+	DuplicateLIDChats []DuplicateLIDChatInfo `json:"duplicate_lid_chats"`
+	// RemovedMedia counts the media blobs no message referenced any more; repair
+	// deletes them together with their downloaded files.
+	RemovedMedia int      `json:"removed_media"`
+	RepairErrors []string `json:"repair_errors,omitempty"`
+}
+
+type MissingChatInfo struct {
+	ChatJID         string `json:"chat_jid"`
+	MessageCount    int    `json:"message_count"`
+	LastMessageTime string `json:"last_message_time"`
+}
+
+type DuplicateLIDChatInfo struct {
+	LIDJID   string `json:"lid_jid"`
+	PhoneJID string `json:"phone_jid"`
+	// PhoneChatExists is false when only the @lid chat is stored; repair then renames it.
+	PhoneChatExists bool `json:"phone_chat_exists"`
+}
+
+// Export Chat operations
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+	ExportFormatTXT  = "txt"
+)
+
+// Export progress steps
+const (
+	ExportStepMessages = "messages"
+	ExportStepMedia    = "media"
+)
+
+type ExportChatRequest struct {
+	ChatJID      string `json:"chat_jid" uri:"chat_jid"`
+	Format       string `json:"format" query:"format"`
+	IncludeMedia bool   `json:"include_media" query:"include_media"`
+	// Progress, when set, is called once per exported message or media item;
+	// returning an error aborts the export.
+	Progress func(step string) error `json:"-"`
+}
+
+// ExportChatResponse describes a prepared export. Write streams the export body and is
+// invoked after the response headers are sent, so it must not rely on request-scoped state.
+type ExportChatResponse struct {
+	Filename    string                  `json:"filename"`
+	ContentType string                  `json:"content_type"`
+	Write       func(w io.Writer) error `json:"-"`
+}
+
+// ImportChatRequest loads a WhatsApp "Export chat" file (the .txt, or the ZIP
+// that bundles it) into chat storage.
+type ImportChatRequest struct {
+	ChatJID  string `json:"chat_jid" uri:"chat_jid"`
+	ChatName string `json:"chat_name" form:"chat_name"`
+	// OwnName is the name the export shows for the exporting account; its
+	// messages are stored as sent. "You" is always treated as the own name.
+	OwnName string                `json:"own_name" form:"own_name"`
+	File    *multipart.FileHeader `json:"-" form:"file"`
+}
+
+type ImportChatResponse struct {
+	ChatJID  string `json:"chat_jid"`
+	ChatName string `json:"chat_name"`
+	Imported int    `json:"imported"`
+	// Skipped counts system lines of the export (encryption notices, group
+	// changes) that are not messages.
+	Skipped int `json:"skipped"`
+}
+
+// ExportMessage is a single row of a JSON or CSV chat export.
+type ExportMessage struct {
+	ID         string `json:"id"`
+	Timestamp  string `json:"timestamp"`
+	SenderJID  string `json:"sender_jid"`
+	SenderName string `json:"sender_name"`
+	IsFromMe   bool   `json:"is_from_me"`
+	Content    string `json:"content"`
+	MediaType  string `json:"media_type,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	FileLength uint64 `json:"file_length,omitempty"`
+	// MediaFile is the path of the media entry inside the ZIP bundle when include_media is set.
+	MediaFile string `json:"media_file,omitempty"`
+}
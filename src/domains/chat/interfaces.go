@@ -2,13 +2,54 @@ package chat
 
 import (
 	"context"
+
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
 )
 
 // IChatUsecase defines the interface for chat-related operations
 type IChatUsecase interface {
 	ListChats(ctx context.Context, request ListChatsRequest) (response ListChatsResponse, err error)
 	GetChatMessages(ctx context.Context, request GetChatMessagesRequest) (response GetChatMessagesResponse, err error)
+	// GetMessageContext returns a stored message with the messages around it, for jumping to it in the chat.
+	GetMessageContext(ctx context.Context, request GetMessageContextRequest) (response GetMessageContextResponse, err error)
 	PinChat(ctx context.Context, request PinChatRequest) (response PinChatResponse, err error)
 	SetDisappearingTimer(ctx context.Context, request SetDisappearingTimerRequest) (response SetDisappearingTimerResponse, err error)
 	ArchiveChat(ctx context.Context, request ArchiveChatRequest) (response ArchiveChatResponse, err error)
+	MuteChat(ctx context.Context, request MuteChatRequest) (response MuteChatResponse, err error)
+	MarkChatRead(ctx context.Context, request MarkChatReadRequest) (response MarkChatReadResponse, err error)
+	// DeleteChat removes a chat from chat storage, into the trash when it is enabled.
+	DeleteChat(ctx context.Context, request DeleteChatRequest) (response DeleteChatResponse, err error)
+	RestoreChat(ctx context.Context, request RestoreChatRequest) (response RestoreChatResponse, err error)
+	ListTrashedChats(ctx context.Context) (response ListTrashedChatsResponse, err error)
+	// MergeChat moves a chat's messages, reactions, edits, labels and read state
+	// into another chat, for duplicates such as a @lid chat and its phone number chat.
+	MergeChat(ctx context.Context, request MergeChatRequest) (response MergeChatResponse, err error)
+	// GetChatStats summarizes a chat's stored messages.
+	GetChatStats(ctx context.Context, request ChatStatsRequest) (response ChatStatsResponse, err error)
+	LabelChat(ctx context.Context, request LabelChatRequest) (response LabelChatResponse, err error)
+	ListLabels(ctx context.Context) (response ListLabelsResponse, err error)
+	// SyncLabels re-fetches labels and their chat and message associations from app state.
+	SyncLabels(ctx context.Context) (response ListLabelsResponse, err error)
+	ExportChat(ctx context.Context, request ExportChatRequest) (response ExportChatResponse, err error)
+	// StartExportChatJob writes the export to a file in the background; the job's result is downloadable.
+	StartExportChatJob(ctx context.Context, request ExportChatRequest) (response domainJob.JobInfo, err error)
+	// ImportChat loads a WhatsApp chat export file into chat storage. Importing
+	// the same file again does not duplicate messages.
+	ImportChat(ctx context.Context, request ImportChatRequest) (response ImportChatResponse, err error)
+	ReindexSearch(ctx context.Context) (response SearchReindexResponse, err error)
+	GetSearchReindexStatus(ctx context.Context) (response SearchReindexProgress, err error)
+	// CompactStorage vacuums the whole chat storage database and truncates its WAL.
+	CompactStorage(ctx context.Context) (response StorageCompactionResponse, err error)
+	// ListWebhookDeadLetters returns the device's webhook calls that ran out of retries.
+	ListWebhookDeadLetters(ctx context.Context) (response ListWebhookDeadLettersResponse, err error)
+	// RetryWebhookDeadLetter puts a dead letter back in the retry queue with a fresh set of attempts.
+	RetryWebhookDeadLetter(ctx context.Context, request RetryWebhookDeadLetterRequest) (response RetryWebhookDeadLetterResponse, err error)
+	// ReplayWebhookDeadLetters requeues every dead letter that died since the given time.
+	ReplayWebhookDeadLetters(ctx context.Context, request ReplayWebhookDeadLettersRequest) (response ReplayWebhookDeadLettersResponse, err error)
+	// CheckStorage reports chat storage inconsistencies for the device and, when
+	// requested, repairs them.
+	CheckStorage(ctx context.Context, request StorageDoctorRequest) (response StorageDoctorReport, err error)
+	// StartLIDBackfill merges the device's @lid chats into the phone number
+	// chats of the same contacts as a background job.
+	StartLIDBackfill(ctx context.Context) (response LIDBackfillResponse, err error)
 }
@@ -0,0 +1,16 @@
+package chat
+
+// PollInfo is a poll message's question and its aggregated results.
+type PollInfo struct {
+	Question        string           `json:"question"`
+	SelectableCount uint32           `json:"selectable_count"`
+	Options         []PollOptionInfo `json:"options"`
+	TotalVoters     int              `json:"total_voters"`
+}
+
+// PollOptionInfo is the vote tally for a single poll option.
+type PollOptionInfo struct {
+	Name   string   `json:"name"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
@@ -7,3 +7,10 @@ type ReactionInfo struct {
 	IsFromMe  bool   `json:"is_from_me"`
 	Timestamp string `json:"timestamp"`
 }
+
+// PinInfo describes the pin of a pinned message.
+type PinInfo struct {
+	PinnedBy  string `json:"pinned_by"`
+	PinnedAt  string `json:"pinned_at"`
+	ExpiresAt string `json:"expires_at"`
+}
@@ -12,29 +12,53 @@ type Chat struct {
 	CreatedAt           time.Time `db:"created_at"`
 	UpdatedAt           time.Time `db:"updated_at"`
 	Archived            bool      `db:"archived"`
+	Pinned              bool      `db:"pinned"`
+	MutedUntil          time.Time `db:"muted_until"` // Zero when not muted; whatsmeow's store.MutedForever for indefinite mutes
+	UnreadCount         int       `db:"unread_count"`
+	DeletedAt           time.Time `db:"deleted_at"` // Zero unless the chat is in the trash
+}
+
+// IsMuted reports whether the chat is muted at the given time.
+func (c *Chat) IsMuted(now time.Time) bool {
+	return !c.MutedUntil.IsZero() && c.MutedUntil.After(now)
+}
+
+// ChatStateUpdate carries the per-chat flags driven by app state sync. Nil
+// fields are left unchanged.
+type ChatStateUpdate struct {
+	Archived    *bool
+	Pinned      *bool
+	MutedUntil  *time.Time
+	UnreadCount *int
 }
 
 // Message represents a WhatsApp message
 type Message struct {
-	ID               string     `db:"id"`
-	ChatJID          string     `db:"chat_jid"`
-	DeviceID         string     `db:"device_id"`
-	Sender           string     `db:"sender"`
-	Content          string     `db:"content"`
-	Timestamp        time.Time  `db:"timestamp"`
-	IsFromMe         bool       `db:"is_from_me"`
-	MediaType        string     `db:"media_type"`
-	CallMetadata     string     `db:"call_metadata"`
-	Filename         string     `db:"filename"`
-	URL              string     `db:"url"`
-	MediaKey         []byte     `db:"media_key"`
-	FileSHA256       []byte     `db:"file_sha256"`
-	FileEncSHA256    []byte     `db:"file_enc_sha256"`
-	FileLength       uint64     `db:"file_length"`
-	ReferralMetadata string     `db:"referral_metadata"`
-	Reactions        []Reaction `db:"-"`
-	CreatedAt        time.Time  `db:"created_at"`
-	UpdatedAt        time.Time  `db:"updated_at"`
+	ID                string       `db:"id"`
+	ChatJID           string       `db:"chat_jid"`
+	DeviceID          string       `db:"device_id"`
+	Sender            string       `db:"sender"`
+	Content           string       `db:"content"`
+	Timestamp         time.Time    `db:"timestamp"`
+	IsFromMe          bool         `db:"is_from_me"`
+	MediaType         string       `db:"media_type"`
+	CallMetadata      string       `db:"call_metadata"`
+	Filename          string       `db:"filename"`
+	URL               string       `db:"url"`
+	MediaKey          []byte       `db:"media_key"`
+	FileSHA256        []byte       `db:"file_sha256"`
+	FileEncSHA256     []byte       `db:"file_enc_sha256"`
+	FileLength        uint64       `db:"file_length"`
+	ReferralMetadata  string       `db:"referral_metadata"`
+	QuotedMessageID   string       `db:"quoted_message_id"`  // ID of the message this one replies to
+	QuotedParticipant string       `db:"quoted_participant"` // Sender of the quoted message
+	Reactions         []Reaction   `db:"-"`
+	QuotedMessage     *Message     `db:"-"` // Resolved by GetMessages when the quoted message is stored in the same chat
+	Poll              *PollResults `db:"-"` // Resolved by GetMessages for poll creation messages
+	Pin               *MessagePin  `db:"-"` // Resolved by GetMessages while the message is pinned
+	OutboxEvent       string       `db:"-"` // Webhook event recorded in events_outbox in the same transaction; empty for none
+	CreatedAt         time.Time    `db:"created_at"`
+	UpdatedAt         time.Time    `db:"updated_at"`
 }
 
 // MessageEdit represents a single edit applied to an existing WhatsApp message.
@@ -83,6 +107,37 @@ type ChatwootForwardEvent struct {
 	UpdatedAt         time.Time `db:"updated_at"`
 }
 
+// OutboxEvent is a webhook event recorded in the same transaction as the
+// stored message it describes, so it is still delivered when the process stops
+// between the database write and the webhook call.
+type OutboxEvent struct {
+	ID            int64     `db:"id"`
+	DeviceID      string    `db:"device_id"`
+	ChatJID       string    `db:"chat_jid"`
+	MessageID     string    `db:"message_id"`
+	EventName     string    `db:"event_name"`
+	Attempts      int       `db:"attempts"`
+	LastError     string    `db:"last_error"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// WebhookDelivery is a webhook call to one URL that failed and is retried from
+// the delivery queue. When it runs out of attempts it is kept as a dead letter.
+type WebhookDelivery struct {
+	ID            int64     `db:"id"`
+	DeviceID      string    `db:"device_id"`
+	URL           string    `db:"url"`
+	EventName     string    `db:"event_name"`
+	PayloadJSON   string    `db:"payload_json"`
+	Attempts      int       `db:"attempts"`
+	LastError     string    `db:"last_error"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	DeadAt        time.Time `db:"dead_at"` // Zero while the delivery is still retried
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
 // MediaInfo represents downloadable media information
 type MediaInfo struct {
 	MessageID     string
@@ -105,6 +160,12 @@ type DeviceRecord struct {
 	UpdatedAt   time.Time `db:"updated_at"`
 }
 
+// MessageCursor is a keyset position in a chat's newest-first message order.
+type MessageCursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
 // MessageFilter represents query filters for messages
 type MessageFilter struct {
 	DeviceID  string
@@ -115,6 +176,17 @@ type MessageFilter struct {
 	EndTime   *time.Time
 	MediaOnly bool
 	IsFromMe  *bool
+	// Before, when set, returns only messages strictly older than the cursor and
+	// replaces Offset so deep pages cost the same as the first one.
+	Before *MessageCursor
+}
+
+// MessageContext is a message with the messages around it in the same chat,
+// both sides oldest first.
+type MessageContext struct {
+	Before  []*Message
+	Message *Message
+	After   []*Message
 }
 
 // ChatFilter represents query filters for chats
@@ -125,4 +197,10 @@ type ChatFilter struct {
 	SearchName string
 	HasMedia   bool
 	IsArchived *bool
+	IsPinned   *bool
+	IsMuted    *bool
+	HasUnread  *bool
+	LabelID    string
+	// Trashed selects the chats in the trash instead of the live ones.
+	Trashed bool
 }
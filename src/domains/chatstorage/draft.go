@@ -0,0 +1,13 @@
+package chatstorage
+
+import "time"
+
+// Draft is an unsent composition saved for a chat, one per chat and device.
+type Draft struct {
+	DeviceID       string    `db:"device_id"`
+	ChatJID        string    `db:"chat_jid"`
+	Content        string    `db:"content"`
+	ReplyMessageID string    `db:"reply_message_id"` // Message the draft replies to, if any
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
@@ -8,3 +8,6 @@ var ErrMissingDeviceContext = errors.New("missing WhatsApp client/device context
 
 // ErrCallOfferMissingPeerJID is returned when a CallOffer has no group or peer From JID to map to a chat row.
 var ErrCallOfferMissingPeerJID = errors.New("unable to resolve peer JID for CallOffer")
+
+// ErrChatNotFound is returned by MergeChats when the source chat is not stored.
+var ErrChatNotFound = errors.New("chat not found")
@@ -0,0 +1,55 @@
+package chatstorage
+
+import "time"
+
+// Group participant roles, as reported in membership diffs. RoleNone means the
+// JID is not a participant.
+const (
+	GroupRoleNone       = "none"
+	GroupRoleMember     = "member"
+	GroupRoleAdmin      = "admin"
+	GroupRoleSuperAdmin = "superadmin"
+)
+
+// GroupParticipant is a member of a group as last seen by a device.
+type GroupParticipant struct {
+	JID          string `json:"jid"`
+	IsAdmin      bool   `json:"is_admin"`
+	IsSuperAdmin bool   `json:"is_super_admin"`
+}
+
+// Role returns the participant's role.
+func (p GroupParticipant) Role() string {
+	switch {
+	case p.IsSuperAdmin:
+		return GroupRoleSuperAdmin
+	case p.IsAdmin:
+		return GroupRoleAdmin
+	default:
+		return GroupRoleMember
+	}
+}
+
+// GroupSnapshot is the last known metadata and membership of a group.
+type GroupSnapshot struct {
+	DeviceID            string             `db:"device_id"`
+	GroupJID            string             `db:"group_jid"`
+	Name                string             `db:"name"`
+	Topic               string             `db:"topic"`
+	OwnerJID            string             `db:"owner_jid"`
+	EphemeralExpiration uint32             `db:"ephemeral_expiration"`
+	Announce            bool               `db:"announce"`     // Only admins can send messages
+	Locked              bool               `db:"locked"`       // Only admins can edit group info
+	Participants        []GroupParticipant `db:"participants"` // Stored as a JSON array
+	UpdatedAt           time.Time          `db:"updated_at"`
+}
+
+// ParticipantRole returns the role of jid in the group, or GroupRoleNone.
+func (g *GroupSnapshot) ParticipantRole(jid string) string {
+	for _, p := range g.Participants {
+		if p.JID == jid {
+			return p.Role()
+		}
+	}
+	return GroupRoleNone
+}
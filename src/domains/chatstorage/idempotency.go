@@ -0,0 +1,14 @@
+package chatstorage
+
+import "time"
+
+// IdempotencyKey is the recorded result of a send made with an idempotency
+// key, so a retry with the same key gets it back instead of sending again.
+type IdempotencyKey struct {
+	DeviceID  string    `db:"device_id"`
+	Key       string    `db:"key"`
+	Operation string    `db:"operation"` // Send the key was used for, such as "send/message"
+	MessageID string    `db:"message_id"`
+	Response  string    `db:"response"` // JSON of the send response
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,10 @@
+package chatstorage
+
+import "time"
+
+// MissingChat describes messages stored for a chat that has no chat row.
+type MissingChat struct {
+	ChatJID         string    `db:"chat_jid"`
+	MessageCount    int       `db:"message_count"`
+	LastMessageTime time.Time `db:"last_message_time"`
+}
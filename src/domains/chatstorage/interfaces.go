@@ -16,24 +16,143 @@ type IChatStorageRepository interface {
 	// CreateIncomingCallRecord persists an incoming call as a synthetic message (media_type "call") for chat history.
 	CreateIncomingCallRecord(ctx context.Context, evt *events.CallOffer, autoRejected bool) error
 	StoreChat(chat *Chat) error
+	// UpdateChatState sets archived/pinned/muted/unread flags without touching the rest of the chat row.
+	UpdateChatState(deviceID, jid string, update ChatStateUpdate) error
 	GetChat(jid string) (*Chat, error)
 	GetChatByDevice(deviceID, jid string) (*Chat, error)
 	GetChats(filter *ChatFilter) ([]*Chat, error)
 	DeleteChat(jid string) error
 	DeleteChatByDevice(deviceID, jid string) error
+	// TrashChat soft-deletes a chat: it disappears from GetChats until it is
+	// restored or PurgeTrashedChats deletes it for good.
+	TrashChat(deviceID, jid string) error
+	// RestoreChat takes a chat out of the trash and reports whether it was trashed.
+	RestoreChat(deviceID, jid string) (bool, error)
+	// PurgeTrashedChats permanently deletes the chats of every device trashed before the given time.
+	PurgeTrashedChats(before time.Time) (int, error)
 
 	// Message operations
 	StoreMessage(message *Message) error
 	StoreMessageEdit(edit *MessageEdit) error
 	StoreMessagesBatch(messages []*Message) error
-	GetMessageByID(id string) (*Message, error)                    // New method for efficient ID-only search
+	// GetMessageByID looks a message up by ID; a non-empty deviceID or chatJID narrows the lookup.
+	GetMessageByID(id, deviceID, chatJID string) (*Message, error)
 	GetMessageByIDAndDevice(deviceID, id string) (*Message, error) // Device-scoped ID lookup for device-isolated flows
+	// GetMessageWithContext returns a message with up to before older and after newer
+	// messages of its chat, or nil when the message is not stored.
+	GetMessageWithContext(deviceID, chatJID, id string, before, after int) (*MessageContext, error)
 	GetMessageEdits(originalMessageID, deviceID string) ([]*MessageEdit, error)
 	GetMessages(filter *MessageFilter) ([]*Message, error)
+	// IterateMessages walks every message matching filter in chronological order without
+	// materializing the full result set; fn returning an error stops the walk.
+	IterateMessages(filter *MessageFilter, fn func(*Message) error) error
 	SearchMessages(deviceID, chatJID, searchText string, limit int) ([]*Message, error) // Database-level search with device isolation
+	// RebuildSearchIndex rebuilds the indexes and planner statistics message search relies on.
+	// It is database-wide; progress is called before each step and once more when all steps are done.
+	RebuildSearchIndex(ctx context.Context, progress func(step string, completed, total int)) error
 	DeleteMessage(id, chatJID string) error
 	DeleteMessageByDevice(deviceID, id, chatJID string) error
 	StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time, msg *waE2E.Message) error
+	// GetChatStats aggregates a chat's messages in SQL without loading them.
+	GetChatStats(filter *ChatStatsFilter) (*ChatStats, error)
+
+	// Poll operations
+	StorePoll(poll *Poll) error
+	// CreatePollVote stores a poll update whose vote the caller already decrypted.
+	CreatePollVote(ctx context.Context, evt *events.Message, vote *waE2E.PollVoteMessage) error
+	GetPollResults(deviceID, messageID string) (*PollResults, error)
+
+	// Pin operations
+	// CreateMessagePin pins or unpins the message a PinInChatMessage event refers to.
+	CreateMessagePin(ctx context.Context, evt *events.Message) error
+	StoreMessagePin(pin *MessagePin) error
+	DeleteMessagePin(deviceID, chatJID, messageID string) error
+	// GetMessagePins returns the messages of a chat still pinned at now, most recently pinned first.
+	GetMessagePins(deviceID, chatJID string, now time.Time) ([]*MessagePin, error)
+
+	// LID mapping operations; mappings are shared by all devices.
+	// StoreLIDMapping records that lid and phoneJID are the same account,
+	// replacing any other LID recorded for phoneJID.
+	StoreLIDMapping(lid, phoneJID string) error
+	// GetLIDMapping returns the mapping of a LID, or nil when it is unknown.
+	GetLIDMapping(lid string) (*LIDMapping, error)
+	// GetLIDMappingByPhone returns the mapping of a phone number JID, or nil when it is unknown.
+	GetLIDMappingByPhone(phoneJID string) (*LIDMapping, error)
+
+	// Group snapshot operations
+	StoreGroupSnapshot(snapshot *GroupSnapshot) error
+	GetGroupSnapshot(deviceID, groupJID string) (*GroupSnapshot, error)
+	// GetGroupParticipantCounts returns participant counts keyed by group JID;
+	// groups without a snapshot are absent.
+	GetGroupParticipantCounts(deviceID string, groupJIDs []string) (map[string]int, error)
+
+	// Label operations
+	StoreLabel(label *Label) error
+	// DeleteLabel removes a label together with its chat and message associations.
+	DeleteLabel(deviceID, labelID string) error
+	GetLabels(deviceID string) ([]*Label, error)
+	SetChatLabel(deviceID, chatJID, labelID string, labeled bool) error
+	SetMessageLabel(deviceID, chatJID, messageID, labelID string, labeled bool) error
+	// GetChatLabelIDs returns label IDs keyed by chat JID; unlabeled chats are absent.
+	GetChatLabelIDs(deviceID string, chatJIDs []string) (map[string][]string, error)
+	// GetMessageLabelIDs returns label IDs keyed by message ID; unlabeled messages are absent.
+	GetMessageLabelIDs(deviceID, chatJID string, messageIDs []string) (map[string][]string, error)
+
+	// Draft operations
+	// StoreDraft creates or replaces the draft of a chat.
+	StoreDraft(draft *Draft) error
+	// GetDraft returns the draft of a chat, or nil when there is none.
+	GetDraft(deviceID, chatJID string) (*Draft, error)
+	// GetDrafts returns the device's drafts, most recently updated first.
+	GetDrafts(deviceID string) ([]*Draft, error)
+	// DeleteDraft removes the draft of a chat and reports whether there was one.
+	DeleteDraft(deviceID, chatJID string) (bool, error)
+
+	// Message template operations
+	// StoreMessageTemplate creates or replaces a template by name.
+	StoreMessageTemplate(template *MessageTemplate) error
+	// GetMessageTemplate returns a template, or nil when there is none by that name.
+	GetMessageTemplate(deviceID, name string) (*MessageTemplate, error)
+	GetMessageTemplates(deviceID string) ([]*MessageTemplate, error)
+	// DeleteMessageTemplate removes a template and reports whether there was one.
+	DeleteMessageTemplate(deviceID, name string) (bool, error)
+
+	// Idempotency key operations
+	// StoreIdempotencyKey records the result of a send, replacing an earlier record of the key.
+	StoreIdempotencyKey(key *IdempotencyKey) error
+	// GetIdempotencyKey returns the record of a key created at or after since, or nil.
+	GetIdempotencyKey(deviceID, key string, since time.Time) (*IdempotencyKey, error)
+	// DeleteIdempotencyKeysBefore removes the keys of every device created before the given time.
+	DeleteIdempotencyKeysBefore(before time.Time) (int64, error)
+
+	// Integrity operations
+	// GetEmptyChats returns the JIDs of chats without messages. Archived, pinned
+	// and muted chats are left out: their state is synced from WhatsApp even when
+	// no message was stored.
+	GetEmptyChats(deviceID string) ([]string, error)
+	// GetMissingChats returns the chats that have messages but no chat row.
+	GetMissingChats(deviceID string) ([]*MissingChat, error)
+	// RestoreMissingChats creates a chat row for every chat GetMissingChats reports.
+	RestoreMissingChats(deviceID string) (int64, error)
+
+	// Media operations
+	// GetMedia returns the media blob stored for a file hash, or nil.
+	GetMedia(deviceID string, fileSHA256 []byte) (*Media, error)
+	// SetMediaLocalPath records where the file of a media blob was downloaded to.
+	SetMediaLocalPath(deviceID string, fileSHA256 []byte, localPath string) error
+	// GetMessagesByMedia returns every message that references a media blob.
+	GetMessagesByMedia(deviceID string, fileSHA256 []byte) ([]*Message, error)
+	// DeleteUnreferencedMedia deletes the media blobs no message references any
+	// more and returns them, so their downloaded files can be removed.
+	DeleteUnreferencedMedia(deviceID string) ([]*Media, error)
+
+	// Job operations
+	StoreJob(job *Job) error
+	GetJob(id string) (*Job, error)
+	GetJobs(filter *JobFilter) ([]*Job, error)
+	// FailActiveJobs marks every queued or running job as failed with reason. Jobs
+	// run in-process, so any still active at startup were interrupted by a restart.
+	FailActiveJobs(reason string) (int64, error)
 
 	// Chatwoot correlation operations
 	UpsertChatwootMessageLink(link *ChatwootMessageLink) error
@@ -46,11 +165,51 @@ type IChatStorageRepository interface {
 	MarkChatwootForwardEventFailed(id int64, lastError string, nextAttemptAt time.Time) error
 	MarkChatwootForwardEventDone(id int64) error
 
+	// Webhook outbox operations
+	ListDueOutboxEvents(now time.Time, limit int) ([]*OutboxEvent, error)
+	MarkOutboxEventDone(deviceID, messageID, eventName string) error
+	MarkOutboxEventFailed(id int64, lastError string, nextAttemptAt time.Time) error
+	PruneOutboxEvents(deliveredBefore time.Time) (int64, error)
+
+	// Webhook delivery queue operations
+	EnqueueWebhookDelivery(delivery *WebhookDelivery) error
+	ListDueWebhookDeliveries(now time.Time, limit int) ([]*WebhookDelivery, error)
+	MarkWebhookDeliveryFailed(id int64, lastError string, nextAttemptAt time.Time) error
+	// MarkWebhookDeliveryDead stops retrying a delivery and keeps it as a dead letter.
+	MarkWebhookDeliveryDead(id int64, lastError string) error
+	MarkWebhookDeliveryDone(id int64) error
+	ListDeadWebhookDeliveries(deviceID string, limit int) ([]*WebhookDelivery, error)
+	// RequeueWebhookDelivery schedules a dead letter of the device for immediate
+	// delivery with a fresh set of attempts. It reports false when there is none.
+	RequeueWebhookDelivery(deviceID string, id int64) (bool, error)
+	// RequeueDeadWebhookDeliveries does the same for every dead letter of the
+	// device that died at or after since, returning how many were requeued.
+	RequeueDeadWebhookDeliveries(deviceID string, since time.Time) (int64, error)
+
+	// Outgoing message queue operations
+	EnqueueOutgoingMessage(message *OutgoingMessage) error
+	// ListDueOutgoingMessages returns the oldest pending message of every
+	// chat of every device, when it is due at now, oldest first. Messages
+	// behind it in their chat are left out, so one busy chat or device
+	// cannot crowd out the others.
+	ListDueOutgoingMessages(now time.Time) ([]*OutgoingMessage, error)
+	// MarkOutgoingMessageSent removes a sent message from the queue.
+	MarkOutgoingMessageSent(id int64) error
+	MarkOutgoingMessageRetry(id int64, lastError string, nextAttemptAt time.Time) error
+	MarkOutgoingMessageFailed(id int64, lastError string) error
+	// ListOutgoingMessages returns the queued messages of a device, oldest
+	// first, optionally only those with the given status.
+	ListOutgoingMessages(deviceID, status string, limit int) ([]*OutgoingMessage, error)
+	// CountOutgoingMessages returns the number of queued messages of every device by status.
+	CountOutgoingMessages() (map[string]int64, error)
+
 	// Statistics
 	GetChatMessageCount(chatJID string) (int64, error)
 	GetChatMessageCountByDevice(deviceID, chatJID string) (int64, error)
 	GetTotalMessageCount() (int64, error)
 	GetTotalChatCount() (int64, error)
+	GetTotalMessageCountByDevice(deviceID string) (int64, error)
+	GetTotalChatCountByDevice(deviceID string) (int64, error)
 	GetFilteredChatCount(filter *ChatFilter) (int64, error)
 	GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string
 	GetChatNameWithPushNameByDevice(deviceID string, jid types.JID, chatJID string, senderUser string, pushName string) string
@@ -63,7 +222,12 @@ type IChatStorageRepository interface {
 
 	// LID deduplication (fork-only — see docs/plans/2026-01-18-fix-history-sync-lid-duplicate-chats.md)
 	MergeLIDChat(deviceID, lidJID, phoneJID string) error
+	// MergeChats moves everything stored for sourceJID into targetJID and removes
+	// the source chat. With dryRun nothing is changed and the summary previews the merge.
+	MergeChats(deviceID, sourceJID, targetJID string, dryRun bool) (*ChatMergeSummary, error)
 	GetLIDChats(deviceID string) ([]*Chat, error)
+	// GetPhoneChats returns the device's chats with phone number JIDs.
+	GetPhoneChats(deviceID string) ([]*Chat, error)
 
 	// Device registry operations
 	SaveDeviceRecord(record *DeviceRecord) error
@@ -73,4 +237,18 @@ type IChatStorageRepository interface {
 
 	// Schema operations
 	InitializeSchema() error
+
+	// Maintenance operations; all of them are database-wide.
+	// CheckpointWAL copies the write-ahead log into the database and truncates it.
+	CheckpointWAL(ctx context.Context) (*WALCheckpoint, error)
+	// IncrementalVacuum returns up to maxPages free pages to the file system
+	// (all of them when maxPages is 0) and reports how many bytes were freed.
+	IncrementalVacuum(ctx context.Context, maxPages int) (int64, error)
+	GetStorageSize(ctx context.Context) (*StorageSize, error)
+	// CompactStorage rebuilds the database file with VACUUM and truncates the WAL.
+	CompactStorage(ctx context.Context) (*CompactionResult, error)
+
+	// FlushPendingWrites writes messages buffered by write-behind and stops
+	// buffering. Called on shutdown.
+	FlushPendingWrites() error
 }
@@ -0,0 +1,43 @@
+package chatstorage
+
+import "time"
+
+// Job statuses. Queued and running jobs are active; the rest are terminal.
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// Job is a long-running operation tracked beyond the HTTP request that started it.
+type Job struct {
+	ID          string    `db:"id"`
+	Type        string    `db:"type"`
+	DeviceID    string    `db:"device_id"` // Empty for database-wide jobs
+	Status      string    `db:"status"`
+	CurrentStep string    `db:"current_step"`
+	Completed   int       `db:"completed"`
+	Total       int       `db:"total"`  // 0 when the amount of work is not known up front
+	Result      string    `db:"result"` // Short, client-facing outcome, e.g. the export filename
+	ResultPath  string    `db:"result_path"`
+	Error       string    `db:"error"`
+	CreatedAt   time.Time `db:"created_at"`
+	StartedAt   time.Time `db:"started_at"`
+	FinishedAt  time.Time `db:"finished_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// IsActive reports whether the job is still queued or running.
+func (j *Job) IsActive() bool {
+	return j.Status == JobStatusQueued || j.Status == JobStatusRunning
+}
+
+// JobFilter narrows GetJobs; empty fields match every job.
+type JobFilter struct {
+	Type      string
+	Status    string
+	DeviceIDs []string // Jobs of any of these devices; "" matches database-wide jobs
+	Limit     int
+}
@@ -0,0 +1,15 @@
+package chatstorage
+
+import "time"
+
+// Label is a WhatsApp Business label as last synced from app state.
+type Label struct {
+	DeviceID     string    `db:"device_id"`
+	ID           string    `db:"label_id"`
+	Name         string    `db:"name"`
+	Color        int32     `db:"color"`         // Index into WhatsApp's label palette
+	PredefinedID int32     `db:"predefined_id"` // Non-zero for labels WhatsApp creates by default
+	OrderIndex   int32     `db:"order_index"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	ChatCount    int       `db:"-"` // Filled by GetLabels
+}
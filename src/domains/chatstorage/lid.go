@@ -0,0 +1,11 @@
+package chatstorage
+
+import "time"
+
+// LIDMapping pairs a LID with the phone number JID of the same account. The
+// pairing is the same for every device, so mappings are not device-scoped.
+type LIDMapping struct {
+	LID       string    `db:"lid"`
+	PhoneJID  string    `db:"phone_jid"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package chatstorage
+
+import "time"
+
+// WALCheckpoint is the outcome of a write-ahead log checkpoint.
+type WALCheckpoint struct {
+	// Busy is set when readers or writers kept the checkpoint from completing.
+	Busy bool
+	// LogFrames is the number of frames in the WAL, -1 when the database is not in WAL mode.
+	LogFrames          int
+	CheckpointedFrames int
+}
+
+// StorageSize describes the size of the chat storage database file.
+type StorageSize struct {
+	Bytes int64
+	// FreeBytes is unused space inside the file that a vacuum can return.
+	FreeBytes int64
+}
+
+// CompactionResult reports a full compaction of the chat storage database.
+type CompactionResult struct {
+	Before     StorageSize
+	After      StorageSize
+	Checkpoint WALCheckpoint
+	Duration   time.Duration
+}
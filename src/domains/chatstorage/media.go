@@ -0,0 +1,18 @@
+package chatstorage
+
+import "time"
+
+// Media is a media blob shared by every message of a device that carries the
+// same file. RefCount is the number of those messages.
+type Media struct {
+	DeviceID      string    `db:"device_id"`
+	FileSHA256    []byte    `db:"file_sha256"`
+	URL           string    `db:"url"`
+	MediaKey      []byte    `db:"media_key"`
+	FileEncSHA256 []byte    `db:"file_enc_sha256"`
+	FileLength    uint64    `db:"file_length"`
+	LocalPath     string    `db:"local_path"` // Set once the file has been downloaded
+	RefCount      int       `db:"ref_count"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
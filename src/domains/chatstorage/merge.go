@@ -0,0 +1,21 @@
+package chatstorage
+
+// ChatMergeSummary counts the rows a chat merge moves from the source chat to
+// the target chat.
+type ChatMergeSummary struct {
+	// TargetExists is false when the source chat is renamed to the target JID.
+	TargetExists bool
+	Messages     int
+	// DuplicateMessages were stored in both chats; the source copy is dropped.
+	DuplicateMessages int
+	Reactions         int
+	Edits             int
+	Polls             int
+	PollVotes         int
+	ChatLabels        int
+	MessageLabels     int
+	// ReadReceipts are the Chatwoot message links, which hold each message's read state.
+	ReadReceipts int
+	OutboxEvents int
+	Drafts       int
+}
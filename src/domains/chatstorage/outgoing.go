@@ -0,0 +1,26 @@
+package chatstorage
+
+import "time"
+
+// Statuses of an outgoing message. Sent messages are removed from the queue.
+const (
+	OutgoingMessagePending = "pending" // Waiting to be sent, or to be retried
+	OutgoingMessageFailed  = "failed"  // Rejected by WhatsApp or out of attempts; not retried
+)
+
+// OutgoingMessage is a message waiting in the outgoing queue. Messages of a
+// chat are sent one at a time, in the order they were queued.
+type OutgoingMessage struct {
+	ID            int64     `db:"id"`
+	DeviceID      string    `db:"device_id"`
+	ChatJID       string    `db:"chat_jid"`
+	MessageID     string    `db:"message_id"` // WhatsApp message ID, assigned when queued
+	Payload       []byte    `db:"payload"`    // Protobuf of the waE2E.Message to send
+	Content       string    `db:"content"`    // Text stored with the sent message
+	Status        string    `db:"status"`
+	Attempts      int       `db:"attempts"`
+	LastError     string    `db:"last_error"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
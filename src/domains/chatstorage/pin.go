@@ -0,0 +1,18 @@
+package chatstorage
+
+import "time"
+
+// MessagePin is a message pinned for everyone in a chat. WhatsApp unpins it
+// on its own once ExpiresAt passes.
+type MessagePin struct {
+	MessageID string    `db:"message_id"`
+	ChatJID   string    `db:"chat_jid"`
+	DeviceID  string    `db:"device_id"`
+	PinnedBy  string    `db:"pinned_by"`
+	PinnedAt  time.Time `db:"pinned_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// DefaultMessagePinDuration is how long WhatsApp keeps a message pinned when
+// the pin does not say.
+const DefaultMessagePinDuration = 7 * 24 * time.Hour
@@ -0,0 +1,43 @@
+package chatstorage
+
+import "time"
+
+// Poll is the question and options of a poll, keyed by its creation message.
+type Poll struct {
+	MessageID       string    `db:"message_id"`
+	ChatJID         string    `db:"chat_jid"`
+	DeviceID        string    `db:"device_id"`
+	CreatorJID      string    `db:"creator_jid"`
+	Question        string    `db:"question"`
+	Options         []string  `db:"options"` // Stored as a JSON array, in the order the poll lists them
+	SelectableCount uint32    `db:"selectable_count"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// PollVote is a voter's latest selection on a poll. A new vote replaces the
+// previous one; an empty selection retracts it.
+type PollVote struct {
+	PollMessageID   string    `db:"poll_message_id"`
+	ChatJID         string    `db:"chat_jid"`
+	DeviceID        string    `db:"device_id"`
+	VoterJID        string    `db:"voter_jid"`
+	SelectedOptions []string  `db:"selected_options"` // Stored as a JSON array of option names
+	IsFromMe        bool      `db:"is_from_me"`
+	Timestamp       time.Time `db:"vote_timestamp"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// PollOptionResult is the tally for a single poll option.
+type PollOptionResult struct {
+	Name   string
+	Votes  int
+	Voters []string
+}
+
+// PollResults aggregates the current votes of a poll.
+type PollResults struct {
+	Poll        *Poll
+	Options     []PollOptionResult
+	TotalVoters int
+}
@@ -0,0 +1,41 @@
+package chatstorage
+
+import "time"
+
+// ChatStats aggregates the stored messages of one chat.
+type ChatStats struct {
+	TotalMessages    int
+	SentMessages     int // Sent from this device's account
+	ReceivedMessages int
+	// MediaTypes counts messages per media type; messages without media count as "text".
+	MediaTypes       []MediaTypeCount
+	FirstMessageTime time.Time // Zero when the chat has no messages
+	LastMessageTime  time.Time
+	TopSenders       []SenderCount
+	Daily            []DailyCount // Oldest day first; days without messages are left out
+}
+
+type MediaTypeCount struct {
+	MediaType string
+	Count     int
+}
+
+type SenderCount struct {
+	Sender string
+	Count  int
+}
+
+type DailyCount struct {
+	Date  string // YYYY-MM-DD
+	Count int
+}
+
+// ChatStatsFilter selects what GetChatStats aggregates besides the totals.
+type ChatStatsFilter struct {
+	DeviceID string
+	ChatJID  string
+	// TopSenders is how many of the most active senders to return; 0 skips them.
+	TopSenders int
+	// Since limits the daily histogram to messages at or after it; zero includes every day.
+	Since time.Time
+}
@@ -0,0 +1,15 @@
+package chatstorage
+
+import "time"
+
+// MessageTemplate is a reusable message body saved per device, optionally sent
+// with a header image, video or document.
+type MessageTemplate struct {
+	DeviceID   string    `db:"device_id"`
+	Name       string    `db:"name"`
+	Body       string    `db:"body"`        // Text with {{placeholders}}
+	HeaderType string    `db:"header_type"` // "", "image", "video" or "document"
+	HeaderURL  string    `db:"header_url"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
@@ -0,0 +1,54 @@
+package job
+
+import "context"
+
+// Job types
+const (
+	TypeSearchReindex = "search_reindex"
+	TypeChatExport    = "chat_export"
+	TypeCampaign      = "campaign"
+	TypeLIDBackfill   = "lid_backfill"
+)
+
+type IJobUsecase interface {
+	ListJobs(ctx context.Context, request ListJobsRequest) (response ListJobsResponse, err error)
+	GetJob(ctx context.Context, jobID string) (response JobInfo, err error)
+	CancelJob(ctx context.Context, jobID string) (response JobInfo, err error)
+	// GetJobResult returns the file a completed job produced, e.g. a chat export.
+	GetJobResult(ctx context.Context, jobID string) (response JobResultFile, err error)
+}
+
+// JobInfo reports the state of a long-running operation. Total is 0 when the
+// amount of work is not known up front.
+type JobInfo struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	DeviceID     string `json:"device_id,omitempty"`
+	Status       string `json:"status"`
+	CurrentStep  string `json:"current_step,omitempty"`
+	Completed    int    `json:"completed"`
+	Total        int    `json:"total"`
+	Result       string `json:"result,omitempty"`
+	Downloadable bool   `json:"downloadable"`
+	Error        string `json:"error,omitempty"`
+	CreatedAt    string `json:"created_at"`
+	StartedAt    string `json:"started_at,omitempty"`
+	FinishedAt   string `json:"finished_at,omitempty"`
+}
+
+// ListJobsRequest filters the jobs of the request's device, which are listed
+// together with database-wide jobs.
+type ListJobsRequest struct {
+	Type   string `json:"type" query:"type"`
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+}
+
+type ListJobsResponse struct {
+	Data []JobInfo `json:"data"`
+}
+
+type JobResultFile struct {
+	Path     string `json:"-"`
+	Filename string `json:"filename"`
+}
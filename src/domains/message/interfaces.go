@@ -10,6 +10,11 @@ type IMessageActions interface {
 	ReactMessage(ctx context.Context, request ReactionRequest) (response GenericResponse, err error)
 	RevokeMessage(ctx context.Context, request RevokeRequest) (response GenericResponse, err error)
 	UpdateMessage(ctx context.Context, request UpdateMessageRequest) (response GenericResponse, err error)
+	LabelMessage(ctx context.Context, request LabelMessageRequest) (response GenericResponse, err error)
+	PinMessage(ctx context.Context, request PinMessageRequest) (response GenericResponse, err error)
+	// ForwardMessage sends a stored message to other chats marked as forwarded,
+	// reusing its uploaded media.
+	ForwardMessage(ctx context.Context, request ForwardMessageRequest) (response ForwardMessageResponse, err error)
 }
 
 // IMessageManagement handles message management operations
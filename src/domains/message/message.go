@@ -21,6 +21,23 @@ type ReactionRequest struct {
 	Emoji     string `json:"emoji" form:"emoji"`
 }
 
+// Durations WhatsApp can pin a message for, in seconds.
+const (
+	PinDuration24Hours = 24 * 60 * 60
+	PinDuration7Days   = 7 * 24 * 60 * 60
+	PinDuration30Days  = 30 * 24 * 60 * 60
+)
+
+// PinMessageRequest pins a message for everyone in the chat, or unpins it when
+// Pinned is false. Duration is how long it stays pinned, in seconds; zero means
+// PinDuration7Days.
+type PinMessageRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+	Phone     string `json:"phone" form:"phone"`
+	Duration  int    `json:"duration" form:"duration"`
+	Pinned    bool   `json:"-"`
+}
+
 type UpdateMessageRequest struct {
 	MessageID string `json:"message_id" uri:"message_id"`
 	Message   string `json:"message" form:"message"`
@@ -38,6 +55,34 @@ type StarRequest struct {
 	IsStarred bool   `json:"is_starred"`
 }
 
+type LabelMessageRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+	Phone     string `json:"phone" form:"phone"`
+	LabelID   string `json:"label_id" form:"label_id"`
+	Labeled   bool   `json:"labeled" form:"labeled"`
+}
+
+// ForwardMessageRequest forwards a stored message of the chat Phone to every
+// chat in Targets.
+type ForwardMessageRequest struct {
+	MessageID string   `json:"message_id" uri:"message_id"`
+	Phone     string   `json:"phone" form:"phone"`
+	Targets   []string `json:"targets" form:"targets"`
+}
+
+// ForwardResult is the outcome of forwarding to one target; Error is set when it failed.
+type ForwardResult struct {
+	Phone     string `json:"phone"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ForwardMessageResponse struct {
+	MessageID string          `json:"message_id"`
+	Status    string          `json:"status"`
+	Results   []ForwardResult `json:"results"`
+}
+
 type DownloadMediaRequest struct {
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
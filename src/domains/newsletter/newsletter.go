@@ -4,8 +4,33 @@ import "context"
 
 type INewsletterUsecase interface {
 	Unfollow(ctx context.Context, request UnfollowRequest) (err error)
+	GetMessages(ctx context.Context, request GetMessagesRequest) (response GetMessagesResponse, err error)
 }
 
 type UnfollowRequest struct {
 	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
 }
+
+type GetMessagesRequest struct {
+	NewsletterID string `json:"newsletter_id" uri:"newsletter_id"`
+	// Count is how many messages to fetch, newest first.
+	Count int `json:"count" query:"count"`
+	// Before is a server ID; only older messages are returned when set.
+	Before int `json:"before" query:"before"`
+}
+
+type MessageInfo struct {
+	ServerID       int            `json:"server_id"`
+	ID             string         `json:"id"`
+	Type           string         `json:"type"`
+	Timestamp      string         `json:"timestamp"`
+	Body           string         `json:"body,omitempty"`
+	MediaType      string         `json:"media_type,omitempty"`
+	ViewsCount     int            `json:"views_count"`
+	ReactionCounts map[string]int `json:"reaction_counts,omitempty"`
+}
+
+type GetMessagesResponse struct {
+	NewsletterID string        `json:"newsletter_id"`
+	Data         []MessageInfo `json:"data"`
+}
@@ -0,0 +1,33 @@
+package send
+
+import "mime/multipart"
+
+// Album item types
+const (
+	AlbumItemImage = "image"
+	AlbumItemVideo = "video"
+)
+
+// AlbumItem is one image or video of an album, from URL or an upload.
+type AlbumItem struct {
+	Type    string                `json:"type"`
+	URL     *string               `json:"url"`
+	File    *multipart.FileHeader `json:"-"`
+	Caption string                `json:"caption"`
+}
+
+// AlbumRequest sends images and videos grouped as one album. Caption is put
+// on the first item when that item has no caption of its own.
+type AlbumRequest struct {
+	BaseRequest
+	Items    []AlbumItem `json:"items"`
+	Caption  string      `json:"caption" form:"caption"`
+	Compress bool        `json:"compress" form:"compress"`
+}
+
+// AlbumResponse holds the ID of the album message and of each of its items.
+type AlbumResponse struct {
+	MessageID  string   `json:"message_id"`
+	Status     string   `json:"status"`
+	MessageIDs []string `json:"message_ids"`
+}
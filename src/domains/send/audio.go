@@ -4,8 +4,9 @@ import "mime/multipart"
 
 type AudioRequest struct {
 	BaseRequest
-	Audio          *multipart.FileHeader `json:"audio" form:"audio"`
-	AudioURL       *string               `json:"audio_url" form:"audio_url"`
-	ReplyMessageID *string               `json:"reply_message_id" form:"reply_message_id"`
-	PTT            bool                  `json:"ptt" form:"ptt"`
+	Audio    *multipart.FileHeader `json:"audio" form:"audio"`
+	AudioURL *string               `json:"audio_url" form:"audio_url"`
+	PTT      bool                  `json:"ptt" form:"ptt"`
+	// VoiceNote is an alias of PTT: the audio is sent as a voice note.
+	VoiceNote bool `json:"voice_note" form:"voice_note"`
 }
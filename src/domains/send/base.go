@@ -4,4 +4,16 @@ type BaseRequest struct {
 	Phone       string `json:"phone" form:"phone"`
 	Duration    *int   `json:"duration,omitempty" form:"duration"`
 	IsForwarded bool   `json:"is_forwarded,omitempty" form:"is_forwarded"`
+	// ReplyMessageID quotes a message of the chat found in chat storage; the
+	// message is sent without the quote when it is not stored.
+	ReplyMessageID *string `json:"reply_message_id,omitempty" form:"reply_message_id"`
+	// SimulateTyping shows the recipient "typing…" (or "recording audio…" for
+	// voice notes) before the message is sent, for as long as a person would
+	// take unless TypingSeconds is set.
+	SimulateTyping bool `json:"simulate_typing,omitempty" form:"simulate_typing"`
+	TypingSeconds  int  `json:"typing_seconds,omitempty" form:"typing_seconds"`
+	// DedupID is an idempotency key: a retry with the key of a successful
+	// send returns that send's response instead of sending again. REST
+	// callers can pass it as the Idempotency-Key header instead.
+	DedupID string `json:"dedup_id,omitempty" form:"dedup_id"`
 }
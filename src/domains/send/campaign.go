@@ -0,0 +1,53 @@
+package send
+
+import (
+	"mime/multipart"
+
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+)
+
+// Campaign recipient statuses
+const (
+	CampaignRecipientPending = "pending"
+	CampaignRecipientSent    = "sent"
+	CampaignRecipientFailed  = "failed"
+)
+
+// CampaignRecipient is one target of a campaign. Variables fill the
+// {{name}} placeholders of the message; {{phone}} is always available.
+type CampaignRecipient struct {
+	Phone     string            `json:"phone"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// CampaignRequest sends one templated text message to many recipients as a
+// background job, throttled to RatePerMinute with up to JitterSeconds of
+// random extra delay between sends. Template names a stored template to send
+// instead of Message.
+type CampaignRequest struct {
+	Message    string              `json:"message" form:"message"`
+	Template   string              `json:"template" form:"template"`
+	Recipients []CampaignRecipient `json:"recipients"`
+	// RecipientsCSV is a CSV upload with a "phone" column; every other column
+	// is a variable named after its header. Its rows follow Recipients.
+	RecipientsCSV *multipart.FileHeader `json:"-" form:"recipients_csv"`
+	RatePerMinute int                   `json:"rate_per_minute" form:"rate_per_minute"`
+	JitterSeconds int                   `json:"jitter_seconds" form:"jitter_seconds"`
+}
+
+type CampaignRecipientStatus struct {
+	Phone     string `json:"phone"`
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	SentAt    string `json:"sent_at,omitempty"`
+}
+
+// CampaignStatusResponse is a campaign's job with the status of every recipient.
+type CampaignStatusResponse struct {
+	Job        domainJob.JobInfo         `json:"job"`
+	Sent       int                       `json:"sent"`
+	Failed     int                       `json:"failed"`
+	Pending    int                       `json:"pending"`
+	Recipients []CampaignRecipientStatus `json:"recipients"`
+}
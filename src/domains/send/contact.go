@@ -1,7 +1,19 @@
 package send
 
+// ContactCard is one contact of a contact message, rendered as a vCard.
+type ContactCard struct {
+	Name         string   `json:"name"`
+	Phones       []string `json:"phones"`
+	Organization string   `json:"organization,omitempty"`
+	Emails       []string `json:"emails,omitempty"`
+}
+
+// ContactRequest sends either the single contact of ContactName and
+// ContactPhone or the cards of Contacts; more than one card is sent as one
+// contacts array message.
 type ContactRequest struct {
 	BaseRequest
-	ContactName  string `json:"contact_name" form:"contact_name"`
-	ContactPhone string `json:"contact_phone" form:"contact_phone"`
+	ContactName  string        `json:"contact_name" form:"contact_name"`
+	ContactPhone string        `json:"contact_phone" form:"contact_phone"`
+	Contacts     []ContactCard `json:"contacts"`
 }
@@ -0,0 +1,27 @@
+package send
+
+// DraftRequest saves the unsent composition of a chat, replacing any draft it
+// already has.
+type DraftRequest struct {
+	ChatJID        string `json:"chat_jid" form:"chat_jid"`
+	Content        string `json:"content" form:"content"`
+	ReplyMessageID string `json:"reply_message_id" form:"reply_message_id"`
+}
+
+// Draft is the saved composition of a chat. Times are RFC 3339.
+type Draft struct {
+	ChatJID        string `json:"chat_jid"`
+	Content        string `json:"content"`
+	ReplyMessageID string `json:"reply_message_id,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type ListDraftsResponse struct {
+	Data []Draft `json:"data"`
+}
+
+// SendDraftRequest sends the draft of a chat as a text message and clears it.
+type SendDraftRequest struct {
+	BaseRequest
+}
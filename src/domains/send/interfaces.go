@@ -2,6 +2,8 @@ package send
 
 import (
 	"context"
+
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
 )
 
 // ITextSender handles text message sending operations
@@ -14,6 +16,7 @@ type IMediaSender interface {
 	SendImage(ctx context.Context, request ImageRequest) (response GenericResponse, err error)
 	SendFile(ctx context.Context, request FileRequest) (response GenericResponse, err error)
 	SendVideo(ctx context.Context, request VideoRequest) (response GenericResponse, err error)
+	SendAlbum(ctx context.Context, request AlbumRequest) (response AlbumResponse, err error)
 	SendAudio(ctx context.Context, request AudioRequest) (response GenericResponse, err error)
 	SendSticker(ctx context.Context, request StickerRequest) (response GenericResponse, err error)
 }
@@ -23,7 +26,9 @@ type IInteractionSender interface {
 	SendContact(ctx context.Context, request ContactRequest) (response GenericResponse, err error)
 	SendLink(ctx context.Context, request LinkRequest) (response GenericResponse, err error)
 	SendLocation(ctx context.Context, request LocationRequest) (response GenericResponse, err error)
+	SendLiveLocation(ctx context.Context, request LiveLocationRequest) (response LiveLocationResponse, err error)
 	SendPoll(ctx context.Context, request PollRequest) (response GenericResponse, err error)
+	SendProduct(ctx context.Context, request ProductRequest) (response GenericResponse, err error)
 }
 
 // IPresenceSender handles presence-related operations
@@ -32,10 +37,46 @@ type IPresenceSender interface {
 	SendChatPresence(ctx context.Context, request ChatPresenceRequest) (response GenericResponse, err error)
 }
 
+// ICampaignSender handles throttled bulk sends
+type ICampaignSender interface {
+	// StartCampaign validates the campaign and sends it as a background job.
+	StartCampaign(ctx context.Context, request CampaignRequest) (response domainJob.JobInfo, err error)
+	GetCampaign(ctx context.Context, jobID string) (response CampaignStatusResponse, err error)
+}
+
+// IDraftSender keeps unsent compositions per chat and sends them
+type IDraftSender interface {
+	SaveDraft(ctx context.Context, request DraftRequest) (response Draft, err error)
+	GetDraft(ctx context.Context, chatJID string) (response Draft, err error)
+	ListDrafts(ctx context.Context) (response ListDraftsResponse, err error)
+	DeleteDraft(ctx context.Context, chatJID string) (err error)
+	// SendDraft sends the draft as a text message and deletes it once sent; a
+	// draft that fails to send is kept.
+	SendDraft(ctx context.Context, request SendDraftRequest) (response GenericResponse, err error)
+}
+
+// ITemplateSender manages message templates and sends them
+type ITemplateSender interface {
+	SaveTemplate(ctx context.Context, request TemplateRequest) (response Template, err error)
+	GetTemplate(ctx context.Context, name string) (response Template, err error)
+	ListTemplates(ctx context.Context) (response ListTemplatesResponse, err error)
+	DeleteTemplate(ctx context.Context, name string) (err error)
+	SendTemplate(ctx context.Context, request SendTemplateRequest) (response GenericResponse, err error)
+}
+
+// IQueueReader lists the outgoing message queue
+type IQueueReader interface {
+	ListQueue(ctx context.Context, request ListQueueRequest) (response ListQueueResponse, err error)
+}
+
 // ISendUsecase combines all sender interfaces for backward compatibility
 type ISendUsecase interface {
 	ITextSender
 	IMediaSender
 	IInteractionSender
 	IPresenceSender
+	ICampaignSender
+	IDraftSender
+	ITemplateSender
+	IQueueReader
 }
@@ -4,4 +4,33 @@ type LocationRequest struct {
 	BaseRequest
 	Latitude  string `json:"latitude" form:"latitude"`
 	Longitude string `json:"longitude" form:"longitude"`
+	Name      string `json:"name" form:"name"`
+	Address   string `json:"address" form:"address"`
+}
+
+// Live location actions
+const (
+	LiveLocationStart  = "start"
+	LiveLocationUpdate = "update"
+	LiveLocationStop   = "stop"
+)
+
+// LiveLocationRequest starts, updates or stops sharing a live location with
+// a chat. A share ends by itself once DurationSeconds have passed.
+type LiveLocationRequest struct {
+	BaseRequest
+	Action           string `json:"action" form:"action"`
+	Latitude         string `json:"latitude" form:"latitude"`
+	Longitude        string `json:"longitude" form:"longitude"`
+	AccuracyInMeters uint32 `json:"accuracy_in_meters" form:"accuracy_in_meters"`
+	Caption          string `json:"caption" form:"caption"`
+	DurationSeconds  int    `json:"duration_seconds" form:"duration_seconds"`
+}
+
+type LiveLocationResponse struct {
+	MessageID      string `json:"message_id,omitempty"`
+	Status         string `json:"status"`
+	Active         bool   `json:"active"`
+	SequenceNumber int64  `json:"sequence_number"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
 }
@@ -0,0 +1,11 @@
+package send
+
+// ProductRequest shares a product of our own business catalog, or the whole
+// catalog when ProductID is empty. The product or catalog snapshot is looked
+// up in the catalog, so only its ID is needed.
+type ProductRequest struct {
+	BaseRequest
+	ProductID string `json:"product_id" form:"product_id"`
+	Body      string `json:"body" form:"body"`
+	Footer    string `json:"footer" form:"footer"`
+}
@@ -0,0 +1,24 @@
+package send
+
+// ListQueueRequest lists the messages of the device in the outgoing queue,
+// optionally only those with a status (pending or failed).
+type ListQueueRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+}
+
+// QueuedMessage is a message in the outgoing queue. Sent messages leave the
+// queue. Times are RFC 3339.
+type QueuedMessage struct {
+	MessageID     string `json:"message_id"`
+	ChatJID       string `json:"chat_jid"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error,omitempty"`
+	NextAttemptAt string `json:"next_attempt_at"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type ListQueueResponse struct {
+	Data []QueuedMessage `json:"data"`
+}
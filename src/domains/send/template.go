@@ -0,0 +1,41 @@
+package send
+
+// Template header types
+const (
+	TemplateHeaderImage    = "image"
+	TemplateHeaderVideo    = "video"
+	TemplateHeaderDocument = "document"
+)
+
+// TemplateRequest creates or replaces a message template. Body may contain
+// {{name}} placeholders; with a header the body becomes the media caption.
+type TemplateRequest struct {
+	Name       string `json:"name" form:"name"`
+	Body       string `json:"body" form:"body"`
+	HeaderType string `json:"header_type" form:"header_type"`
+	HeaderURL  string `json:"header_url" form:"header_url"`
+}
+
+// Template is a stored message template. Variables lists its placeholders in
+// order of first use. Times are RFC 3339.
+type Template struct {
+	Name       string   `json:"name"`
+	Body       string   `json:"body"`
+	HeaderType string   `json:"header_type,omitempty"`
+	HeaderURL  string   `json:"header_url,omitempty"`
+	Variables  []string `json:"variables"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+type ListTemplatesResponse struct {
+	Data []Template `json:"data"`
+}
+
+// SendTemplateRequest sends a stored template, filling its placeholders from
+// Variables; {{phone}} defaults to the recipient's phone.
+type SendTemplateRequest struct {
+	BaseRequest
+	Template  string            `json:"template" form:"template"`
+	Variables map[string]string `json:"variables" form:"variables"`
+}
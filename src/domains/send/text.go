@@ -1,8 +1,25 @@
 package send
 
+import "strings"
+
+// Keywords accepted in MessageRequest.Mentions to mention every participant of
+// a group.
+const (
+	MentionEveryone = "@everyone"
+	MentionAll      = "all"
+)
+
 type MessageRequest struct {
 	BaseRequest
-	Message        string   `json:"message" form:"message"`
-	ReplyMessageID *string  `json:"reply_message_id" form:"reply_message_id"`
-	Mentions       []string `json:"mentions,omitempty" form:"mentions"` // List of phone numbers/JIDs to mention (ghost mentions)
+	Message  string   `json:"message" form:"message"`
+	Mentions []string `json:"mentions,omitempty" form:"mentions"` // List of phone numbers/JIDs to mention (ghost mentions), or "all"/"@everyone"
+	// LinkPreview attaches a preview of the first URL of Message; nil uses
+	// the WHATSAPP_LINK_PREVIEW default.
+	LinkPreview *bool `json:"link_preview,omitempty" form:"link_preview"`
+}
+
+// IsMentionEveryone reports whether mention asks to mention every group participant.
+func IsMentionEveryone(mention string) bool {
+	mention = strings.TrimSpace(mention)
+	return mention == MentionEveryone || strings.EqualFold(mention, MentionAll)
 }
@@ -0,0 +1,80 @@
+package settings
+
+import "context"
+
+// DocumentVersion is the settings document format written by ExportSettings.
+const DocumentVersion = 1
+
+type ISettingsUsecase interface {
+	// ExportSettings returns the instance's runtime settings as a YAML document.
+	ExportSettings(ctx context.Context) (document []byte, err error)
+	// ImportSettings validates a YAML settings document and applies it. Keys
+	// missing from the document keep their current value.
+	ImportSettings(ctx context.Context, request ImportSettingsRequest) (response ImportSettingsResponse, err error)
+}
+
+// Settings is the portable part of an instance's configuration. Secrets
+// (webhook secret, basic auth, Chatwoot token) and per-host values (ports,
+// database URIs, proxies) are deliberately left out so a document exported
+// from staging can be applied to production as is.
+type Settings struct {
+	Version    int                `yaml:"version" json:"version"`
+	AutoReply  AutoReplySettings  `yaml:"auto_reply" json:"auto_reply"`
+	Webhook    WebhookSettings    `yaml:"webhook" json:"webhook"`
+	SendPolicy SendPolicySettings `yaml:"send_policy" json:"send_policy"`
+	Behavior   BehaviorSettings   `yaml:"behavior" json:"behavior"`
+}
+
+type AutoReplySettings struct {
+	Message            string `yaml:"message" json:"message"`
+	QuietHours         string `yaml:"quiet_hours" json:"quiet_hours"`
+	QuietHoursTimezone string `yaml:"quiet_hours_timezone" json:"quiet_hours_timezone"`
+	QuietHoursReply    string `yaml:"quiet_hours_reply" json:"quiet_hours_reply"`
+}
+
+type WebhookSettings struct {
+	URLs               []string `yaml:"urls" json:"urls"`
+	Events             []string `yaml:"events" json:"events"`
+	InsecureSkipVerify bool     `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	ChatOrdering       bool     `yaml:"chat_ordering" json:"chat_ordering"`
+	TenantRoutes       []string `yaml:"tenant_routes" json:"tenant_routes"`
+	TenantWebhooks     []string `yaml:"tenant_webhooks" json:"tenant_webhooks"`
+	Format             string   `yaml:"format" json:"format"`
+	Identifier         string   `yaml:"identifier" json:"identifier"`
+	Templates          []string `yaml:"templates" json:"templates"`
+	MaxPayloadSizes    []string `yaml:"max_payload_sizes" json:"max_payload_sizes"`
+	Filters            []string `yaml:"filters" json:"filters"`
+	SignatureAlgorithm string   `yaml:"signature_algorithm" json:"signature_algorithm"`
+	Presence           bool     `yaml:"presence" json:"presence"`
+	Outgoing           bool     `yaml:"outgoing" json:"outgoing"`
+	OutgoingAPI        bool     `yaml:"outgoing_api" json:"outgoing_api"`
+}
+
+type SendPolicySettings struct {
+	Rules []string `yaml:"rules" json:"rules"`
+	URL   string   `yaml:"url" json:"url"`
+}
+
+type BehaviorSettings struct {
+	AutoMarkRead          bool   `yaml:"auto_mark_read" json:"auto_mark_read"`
+	AutoDownloadMedia     bool   `yaml:"auto_download_media" json:"auto_download_media"`
+	AutoConvertVideo      bool   `yaml:"auto_convert_video" json:"auto_convert_video"`
+	LinkPreview           bool   `yaml:"link_preview" json:"link_preview"`
+	AutoRejectCall        bool   `yaml:"auto_reject_call" json:"auto_reject_call"`
+	AutoRejectCallMessage string `yaml:"auto_reject_call_message" json:"auto_reject_call_message"`
+	AccountValidation     bool   `yaml:"account_validation" json:"account_validation"`
+	PresenceOnConnect     string `yaml:"presence_on_connect" json:"presence_on_connect"`
+}
+
+type ImportSettingsRequest struct {
+	Document []byte `json:"-"`
+	// DryRun validates the document and reports what would change without applying it.
+	DryRun bool `json:"dry_run" query:"dry_run"`
+}
+
+type ImportSettingsResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Changed lists the settings that differ from the current ones, as
+	// dotted document keys such as "webhook.urls".
+	Changed []string `json:"changed"`
+}
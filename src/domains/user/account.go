@@ -110,3 +110,70 @@ type BusinessProfileResponse struct {
 	BusinessHoursTimeZone string                       `json:"business_hours_timezone"`
 	BusinessHours         []BusinessProfileHoursConfig `json:"business_hours"`
 }
+
+// BusinessCatalogRequest reads one page of the catalog of the business account
+// Phone, or of our own account when Phone is empty.
+type BusinessCatalogRequest struct {
+	Phone  string `json:"phone" query:"phone"`
+	Limit  int    `json:"limit" query:"limit"`
+	Cursor string `json:"cursor" query:"cursor"`
+}
+
+// BusinessCatalogProduct is a catalog product; prices are in thousandths of
+// the currency unit.
+type BusinessCatalogProduct struct {
+	ID              string `json:"id"`
+	RetailerID      string `json:"retailer_id,omitempty"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	URL             string `json:"url,omitempty"`
+	Currency        string `json:"currency,omitempty"`
+	PriceAmount1000 int64  `json:"price_amount_1000"`
+	ImageURL        string `json:"image_url,omitempty"`
+	IsHidden        bool   `json:"is_hidden"`
+}
+
+type BusinessCatalogResponse struct {
+	JID        string                   `json:"jid"`
+	Products   []BusinessCatalogProduct `json:"products"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+}
+
+// ResolveContactRequest translates a LID to a phone number JID, or a phone
+// number JID to a LID; exactly one of LID and Phone is set.
+type ResolveContactRequest struct {
+	LID   string `json:"lid" query:"lid"`
+	Phone string `json:"phone" query:"phone"`
+}
+
+// ResolveContactResponse is a LID and the phone number JID of the same
+// account. Source is "store" when the mapping was already persisted and
+// "whatsapp" when it was resolved by the device.
+type ResolveContactResponse struct {
+	LID    string `json:"lid"`
+	Phone  string `json:"phone"`
+	Source string `json:"source"`
+}
+
+// ResolveContactsRequest translates a list of LIDs and phone number JIDs at once.
+type ResolveContactsRequest struct {
+	JIDs []string `json:"jids"`
+}
+
+// ResolveContactsItem is the outcome for one JID of a ResolveContactsRequest.
+// Source is "user_info" when the mapping had to be queried from WhatsApp, and
+// CacheHit is true when it did not.
+type ResolveContactsItem struct {
+	JID      string `json:"jid"`
+	LID      string `json:"lid,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Resolved bool   `json:"resolved"`
+	CacheHit bool   `json:"cache_hit"`
+	Source   string `json:"source,omitempty"`
+}
+
+type ResolveContactsResponse struct {
+	Items     []ResolveContactsItem `json:"items"`
+	Resolved  int                   `json:"resolved"`
+	CacheHits int                   `json:"cache_hits"`
+}
@@ -9,6 +9,9 @@ type IUserInfo interface {
 	Info(ctx context.Context, request InfoRequest) (response InfoResponse, err error)
 	IsOnWhatsApp(ctx context.Context, request CheckRequest) (response CheckResponse, err error)
 	BusinessProfile(ctx context.Context, request BusinessProfileRequest) (response BusinessProfileResponse, err error)
+	BusinessCatalog(ctx context.Context, request BusinessCatalogRequest) (response BusinessCatalogResponse, err error)
+	ResolveContact(ctx context.Context, request ResolveContactRequest) (response ResolveContactResponse, err error)
+	ResolveContacts(ctx context.Context, request ResolveContactsRequest) (response ResolveContactsResponse, err error)
 }
 
 // IUserProfile handles user profile operations
@@ -0,0 +1,386 @@
+package chatstorage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+// encryptedPrefix marks a column value sealed by fieldCipher. Rows without it
+// are plaintext, which lets an existing database be encrypted in place.
+const encryptedPrefix = "enc:v1:"
+
+// encryptBatchSize bounds how many rows are rewritten per transaction when an
+// existing plaintext database is encrypted on startup.
+const encryptBatchSize = 500
+
+var errWrongEncryptionKey = errors.New("chat storage encryption key does not match the stored data")
+
+// fieldCipher seals message content and media keys with AES-256-GCM. A nil
+// *fieldCipher passes values through unchanged.
+type fieldCipher struct {
+	aead cipher.AEAD
+}
+
+// newFieldCipher derives the AES key from the configured secret with SHA-256,
+// so any passphrase length is accepted.
+func newFieldCipher(secret string) (*fieldCipher, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("chat storage encryption key is empty")
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldCipher{aead: aead}, nil
+}
+
+func (c *fieldCipher) seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *fieldCipher) open(sealed []byte) ([]byte, error) {
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, errWrongEncryptionKey
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errWrongEncryptionKey
+	}
+	return plaintext, nil
+}
+
+func (c *fieldCipher) encryptString(value string) (string, error) {
+	if c == nil || value == "" {
+		return value, nil
+	}
+	sealed, err := c.seal([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *fieldCipher) decryptString(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("chat storage is encrypted but no encryption key is configured")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", errWrongEncryptionKey
+	}
+	plaintext, err := c.open(sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (c *fieldCipher) encryptBytes(value []byte) ([]byte, error) {
+	if c == nil || len(value) == 0 {
+		return value, nil
+	}
+	sealed, err := c.seal(value)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(encryptedPrefix), sealed...), nil
+}
+
+func (c *fieldCipher) decryptBytes(value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, []byte(encryptedPrefix)) {
+		return value, nil
+	}
+	if c == nil {
+		return nil, fmt.Errorf("chat storage is encrypted but no encryption key is configured")
+	}
+	return c.open(value[len(encryptedPrefix):])
+}
+
+// NewEncryptedStorageRepository creates a SQLite repository that encrypts
+// message content, media keys and edit history at rest. InitializeSchema
+// encrypts any rows still stored in plaintext.
+func NewEncryptedStorageRepository(db *sql.DB, encryptionKey string) (domainChatStorage.IChatStorageRepository, error) {
+	fc, err := newFieldCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteRepository{db: db, cipher: fc}, nil
+}
+
+// sealMessage returns the content and media key of message as they are written
+// to the database, leaving message itself in plaintext.
+func (r *SQLiteRepository) sealMessage(message *domainChatStorage.Message) (string, []byte, error) {
+	content, err := r.cipher.encryptString(message.Content)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encrypt message %s: %w", message.ID, err)
+	}
+	mediaKey, err := r.cipher.encryptBytes(message.MediaKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encrypt media key of message %s: %w", message.ID, err)
+	}
+	return content, mediaKey, nil
+}
+
+// decryptMessage replaces the sealed columns of a scanned message with their plaintext.
+func (r *SQLiteRepository) decryptMessage(message *domainChatStorage.Message) error {
+	content, err := r.cipher.decryptString(message.Content)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt message %s: %w", message.ID, err)
+	}
+	mediaKey, err := r.cipher.decryptBytes(message.MediaKey)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt media key of message %s: %w", message.ID, err)
+	}
+	message.Content = content
+	message.MediaKey = mediaKey
+	return nil
+}
+
+// encryptExistingData verifies the configured key against data that is already
+// encrypted, then seals every plaintext message, media key and edit in batches. It is a
+// no-op once the database is fully encrypted, so it runs on every startup.
+func (r *SQLiteRepository) encryptExistingData() error {
+	if r.cipher == nil {
+		return nil
+	}
+	if err := r.verifyEncryptionKey(); err != nil {
+		return err
+	}
+
+	messages, err := r.encryptPlaintextMessages()
+	if err != nil {
+		return fmt.Errorf("failed to encrypt messages: %w", err)
+	}
+	media, err := r.encryptPlaintextMedia()
+	if err != nil {
+		return fmt.Errorf("failed to encrypt media keys: %w", err)
+	}
+	edits, err := r.encryptPlaintextEdits()
+	if err != nil {
+		return fmt.Errorf("failed to encrypt message edits: %w", err)
+	}
+	if messages > 0 || media > 0 || edits > 0 {
+		logrus.Infof("Encrypted %d messages, %d media keys and %d message edits in chat storage", messages, media, edits)
+	}
+	return nil
+}
+
+// verifyEncryptionKey fails fast when the database was encrypted with a
+// different key, before any new rows are sealed with the wrong one.
+func (r *SQLiteRepository) verifyEncryptionKey() error {
+	var content string
+	err := r.db.QueryRow(`SELECT content FROM messages WHERE content LIKE ? LIMIT 1`, encryptedPrefix+"%").Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = r.cipher.decryptString(content)
+	return err
+}
+
+func (r *SQLiteRepository) encryptPlaintextMessages() (int, error) {
+	type plaintextRow struct {
+		rowID   int64
+		content string
+	}
+
+	total := 0
+	for {
+		rows, err := r.db.Query(`
+			SELECT rowid, content
+			FROM messages
+			WHERE COALESCE(content, '') != '' AND content NOT LIKE ?
+			LIMIT ?
+		`, encryptedPrefix+"%", encryptBatchSize)
+		if err != nil {
+			return total, err
+		}
+		var batch []plaintextRow
+		for rows.Next() {
+			var row plaintextRow
+			if err := rows.Scan(&row.rowID, &row.content); err != nil {
+				rows.Close()
+				return total, err
+			}
+			batch = append(batch, row)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return total, err
+		}
+		rows.Close()
+
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		tx, err := r.db.Begin()
+		if err != nil {
+			return total, err
+		}
+		for _, row := range batch {
+			content, err := r.cipher.encryptString(row.content)
+			if err != nil {
+				tx.Rollback()
+				return total, err
+			}
+			if _, err := tx.Exec(`UPDATE messages SET content = ? WHERE rowid = ?`, content, row.rowID); err != nil {
+				tx.Rollback()
+				return total, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return total, err
+		}
+		total += len(batch)
+	}
+}
+
+func (r *SQLiteRepository) encryptPlaintextMedia() (int, error) {
+	type plaintextRow struct {
+		rowID    int64
+		mediaKey []byte
+	}
+
+	total := 0
+	for {
+		rows, err := r.db.Query(`
+			SELECT rowid, media_key
+			FROM media
+			WHERE length(media_key) > 0 AND substr(media_key, 1, ?) != CAST(? AS BLOB)
+			LIMIT ?
+		`, len(encryptedPrefix), encryptedPrefix, encryptBatchSize)
+		if err != nil {
+			return total, err
+		}
+		var batch []plaintextRow
+		for rows.Next() {
+			var row plaintextRow
+			if err := rows.Scan(&row.rowID, &row.mediaKey); err != nil {
+				rows.Close()
+				return total, err
+			}
+			batch = append(batch, row)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return total, err
+		}
+		rows.Close()
+
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		tx, err := r.db.Begin()
+		if err != nil {
+			return total, err
+		}
+		for _, row := range batch {
+			mediaKey, err := r.cipher.encryptBytes(row.mediaKey)
+			if err != nil {
+				tx.Rollback()
+				return total, err
+			}
+			if _, err := tx.Exec(`UPDATE media SET media_key = ? WHERE rowid = ?`, mediaKey, row.rowID); err != nil {
+				tx.Rollback()
+				return total, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return total, err
+		}
+		total += len(batch)
+	}
+}
+
+func (r *SQLiteRepository) encryptPlaintextEdits() (int, error) {
+	type plaintextRow struct {
+		rowID           int64
+		previousContent string
+		newContent      string
+	}
+
+	total := 0
+	for {
+		rows, err := r.db.Query(`
+			SELECT rowid, COALESCE(previous_content, ''), COALESCE(new_content, '')
+			FROM message_edits
+			WHERE (COALESCE(previous_content, '') != '' AND previous_content NOT LIKE ?)
+				OR (COALESCE(new_content, '') != '' AND new_content NOT LIKE ?)
+			LIMIT ?
+		`, encryptedPrefix+"%", encryptedPrefix+"%", encryptBatchSize)
+		if err != nil {
+			return total, err
+		}
+		var batch []plaintextRow
+		for rows.Next() {
+			var row plaintextRow
+			if err := rows.Scan(&row.rowID, &row.previousContent, &row.newContent); err != nil {
+				rows.Close()
+				return total, err
+			}
+			batch = append(batch, row)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return total, err
+		}
+		rows.Close()
+
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		tx, err := r.db.Begin()
+		if err != nil {
+			return total, err
+		}
+		for _, row := range batch {
+			values := []string{row.previousContent, row.newContent}
+			for i, value := range values {
+				if strings.HasPrefix(value, encryptedPrefix) {
+					continue
+				}
+				if values[i], err = r.cipher.encryptString(value); err != nil {
+					tx.Rollback()
+					return total, err
+				}
+			}
+			if _, err := tx.Exec(`UPDATE message_edits SET previous_content = ?, new_content = ? WHERE rowid = ?`, values[0], values[1], row.rowID); err != nil {
+				tx.Rollback()
+				return total, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return total, err
+		}
+		total += len(batch)
+	}
+}
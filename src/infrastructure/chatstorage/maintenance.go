@@ -0,0 +1,169 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+// storageQueryer is satisfied by both *sql.DB and a pinned *sql.Conn.
+type storageQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// CheckpointWAL runs a TRUNCATE checkpoint so the WAL file shrinks back to
+// zero bytes once every reader is done with it.
+func (r *SQLiteRepository) CheckpointWAL(ctx context.Context) (*domainChatStorage.WALCheckpoint, error) {
+	return checkpointWAL(ctx, r.db)
+}
+
+func checkpointWAL(ctx context.Context, q storageQueryer) (*domainChatStorage.WALCheckpoint, error) {
+	var busy int
+	checkpoint := &domainChatStorage.WALCheckpoint{}
+	err := q.QueryRowContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &checkpoint.LogFrames, &checkpoint.CheckpointedFrames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	checkpoint.Busy = busy != 0
+	return checkpoint, nil
+}
+
+// IncrementalVacuum frees pages only when the database uses incremental
+// auto-vacuum, which CompactStorage switches it to; otherwise it frees nothing.
+func (r *SQLiteRepository) IncrementalVacuum(ctx context.Context, maxPages int) (int64, error) {
+	before, err := storageSize(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+	// The pragma frees one page per step, so it has to be read to the end.
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`PRAGMA incremental_vacuum(%d)`, max(maxPages, 0)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to run incremental vacuum: %w", err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to run incremental vacuum: %w", err)
+	}
+	after, err := storageSize(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+	return before.Bytes - after.Bytes, nil
+}
+
+func (r *SQLiteRepository) GetStorageSize(ctx context.Context) (*domainChatStorage.StorageSize, error) {
+	return storageSize(ctx, r.db)
+}
+
+func storageSize(ctx context.Context, q storageQueryer) (*domainChatStorage.StorageSize, error) {
+	var pageSize, pageCount, freePages int64
+	if err := q.QueryRowContext(ctx, `PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return nil, fmt.Errorf("failed to read page size: %w", err)
+	}
+	if err := q.QueryRowContext(ctx, `PRAGMA page_count`).Scan(&pageCount); err != nil {
+		return nil, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := q.QueryRowContext(ctx, `PRAGMA freelist_count`).Scan(&freePages); err != nil {
+		return nil, fmt.Errorf("failed to read free page count: %w", err)
+	}
+	return &domainChatStorage.StorageSize{Bytes: pageSize * pageCount, FreeBytes: pageSize * freePages}, nil
+}
+
+// CompactStorage rewrites the database with VACUUM, which also switches it to
+// incremental auto-vacuum so the scheduled IncrementalVacuum can keep it
+// compact afterwards. The rewritten pages pass through the WAL, so it is
+// truncated again at the end. Writers wait until the compaction is done.
+func (r *SQLiteRepository) CompactStorage(ctx context.Context) (*domainChatStorage.CompactionResult, error) {
+	r.flushPendingMessages()
+	started := time.Now()
+
+	// auto_vacuum is a per-connection setting until VACUUM writes it to the
+	// file, so both statements must run on the same connection.
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	before, err := storageSize(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, `PRAGMA auto_vacuum = INCREMENTAL`); err != nil {
+		return nil, fmt.Errorf("failed to enable incremental auto-vacuum: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `VACUUM`); err != nil {
+		return nil, fmt.Errorf("failed to vacuum: %w", err)
+	}
+	checkpoint, err := checkpointWAL(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	after, err := storageSize(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domainChatStorage.CompactionResult{
+		Before:     *before,
+		After:      *after,
+		Checkpoint: *checkpoint,
+		Duration:   time.Since(started),
+	}, nil
+}
+
+// StartMaintenance checkpoints the WAL every checkpointInterval and runs an
+// incremental vacuum every vacuumInterval until ctx ends. A zero interval
+// turns that routine off.
+func StartMaintenance(ctx context.Context, repo domainChatStorage.IChatStorageRepository, checkpointInterval, vacuumInterval time.Duration) {
+	if repo == nil {
+		return
+	}
+	if checkpointInterval > 0 {
+		go runPeriodically(ctx, checkpointInterval, func() { checkpointStorage(ctx, repo) })
+	}
+	if vacuumInterval > 0 {
+		go runPeriodically(ctx, vacuumInterval, func() { vacuumStorage(ctx, repo) })
+	}
+}
+
+func runPeriodically(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			fn()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func checkpointStorage(ctx context.Context, repo domainChatStorage.IChatStorageRepository) {
+	checkpoint, err := repo.CheckpointWAL(ctx)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to checkpoint chat storage WAL")
+		return
+	}
+	if checkpoint.Busy {
+		logrus.Debugf("Chat storage WAL checkpoint was blocked; %d of %d frames copied", checkpoint.CheckpointedFrames, checkpoint.LogFrames)
+	}
+}
+
+func vacuumStorage(ctx context.Context, repo domainChatStorage.IChatStorageRepository) {
+	freed, err := repo.IncrementalVacuum(ctx, 0)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to vacuum chat storage")
+		return
+	}
+	if freed > 0 {
+		logrus.Infof("Chat storage vacuum freed %d bytes", freed)
+	}
+}
@@ -0,0 +1,55 @@
+package chatstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactStorageAndIncrementalVacuum(t *testing.T) {
+	repo, db := newTestRepo(t)
+	ctx := context.Background()
+	chatJID := "628123456789@s.whatsapp.net"
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	fill := func(prefix string) {
+		for i := 0; i < 200; i++ {
+			insertMessage(t, db, fmt.Sprintf("%s%d", prefix, i), chatJID, "device-a", chatJID, strings.Repeat("x", 2000), now)
+		}
+	}
+
+	fill("a")
+	_, err := db.Exec(`DELETE FROM messages`)
+	require.NoError(t, err)
+
+	result, err := repo.CompactStorage(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, result.Before.FreeBytes)
+	assert.Less(t, result.After.Bytes, result.Before.Bytes)
+	assert.Zero(t, result.After.FreeBytes)
+	assert.Zero(t, result.Checkpoint.LogFrames, "the WAL is truncated after the vacuum")
+
+	var autoVacuum int
+	require.NoError(t, db.QueryRow(`PRAGMA auto_vacuum`).Scan(&autoVacuum))
+	assert.Equal(t, 2, autoVacuum, "compaction switches to incremental auto-vacuum")
+
+	// With incremental auto-vacuum, space freed later is returned without a full VACUUM.
+	fill("b")
+	_, err = db.Exec(`DELETE FROM messages`)
+	require.NoError(t, err)
+	size, err := repo.GetStorageSize(ctx)
+	require.NoError(t, err)
+	require.Positive(t, size.FreeBytes)
+
+	freed, err := repo.IncrementalVacuum(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, size.FreeBytes, freed)
+
+	checkpoint, err := repo.CheckpointWAL(ctx)
+	require.NoError(t, err)
+	assert.False(t, checkpoint.Busy)
+}
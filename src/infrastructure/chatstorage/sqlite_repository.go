@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -20,6 +23,14 @@ import (
 // SQLiteRepository implements Repository using SQLite
 type SQLiteRepository struct {
 	db *sql.DB
+	// cipher seals message content and media keys at rest; nil stores plaintext.
+	cipher *fieldCipher
+	// writeBehind buffers StoreMessage calls into batches; nil writes each
+	// message immediately.
+	writeBehind *writeBehind
+	// eventsOutbox records a webhook event in events_outbox with every message
+	// stored by CreateMessage.
+	eventsOutbox bool
 }
 
 // NewSQLiteRepository creates a new SQLite repository
@@ -27,16 +38,19 @@ func NewStorageRepository(db *sql.DB) domainChatStorage.IChatStorageRepository {
 	return &SQLiteRepository{db: db}
 }
 
-// StoreChat creates or updates a chat
+// StoreChat creates or updates a chat. A trashed chat whose last message is
+// newer than the time it was trashed is taken out of the trash, so a
+// conversation that goes on is listed again instead of being purged.
 func (r *SQLiteRepository) StoreChat(chat *domainChatStorage.Chat) error {
 	now := time.Now()
 	chat.UpdatedAt = now
 
 	// Try update first, then insert if no rows affected (cross-db compatible)
 	result, err := r.db.Exec(`
-		UPDATE chats SET name = ?, last_message_time = ?, ephemeral_expiration = ?, updated_at = ?, archived = ?
+		UPDATE chats SET name = ?, last_message_time = ?, ephemeral_expiration = ?, updated_at = ?, archived = ?,
+			deleted_at = CASE WHEN deleted_at > 0 AND ? >= deleted_at THEN 0 ELSE deleted_at END
 		WHERE jid = ? AND device_id = ?
-	`, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, chat.UpdatedAt, chat.Archived, chat.JID, chat.DeviceID)
+	`, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, chat.UpdatedAt, chat.Archived, chat.LastMessageTime.Unix(), chat.JID, chat.DeviceID)
 	if err != nil {
 		return err
 	}
@@ -51,10 +65,48 @@ func (r *SQLiteRepository) StoreChat(chat *domainChatStorage.Chat) error {
 	return err
 }
 
+// UpdateChatState updates the app-state driven flags of an existing chat.
+// Unlike StoreChat it only writes the fields set in update, so concurrent
+// message inserts (which rewrite name/last_message_time) never reset them.
+// muted_until is stored as unix seconds, 0 meaning not muted.
+func (r *SQLiteRepository) UpdateChatState(deviceID, jid string, update domainChatStorage.ChatStateUpdate) error {
+	var sets []string
+	var args []any
+	if update.Archived != nil {
+		sets = append(sets, "archived = ?")
+		args = append(args, *update.Archived)
+	}
+	if update.Pinned != nil {
+		sets = append(sets, "pinned = ?")
+		args = append(args, *update.Pinned)
+	}
+	if update.MutedUntil != nil {
+		sets = append(sets, "muted_until = ?")
+		if update.MutedUntil.IsZero() {
+			args = append(args, 0)
+		} else {
+			args = append(args, update.MutedUntil.Unix())
+		}
+	}
+	if update.UnreadCount != nil {
+		sets = append(sets, "unread_count = ?")
+		args = append(args, max(*update.UnreadCount, 0))
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	sets = append(sets, "updated_at = ?")
+	args = append(args, time.Now(), jid, deviceID)
+	_, err := r.db.Exec(`UPDATE chats SET `+strings.Join(sets, ", ")+` WHERE jid = ? AND device_id = ?`, args...)
+	return err
+}
+
 // GetChat retrieves a chat by JID
 func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error) {
 	query := `
-		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived
+		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived,
+			pinned, muted_until, unread_count, deleted_at
 		FROM chats
 		WHERE jid = ?
 	`
@@ -70,7 +122,8 @@ func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error)
 // GetChatByDevice retrieves a chat by JID for a specific device
 func (r *SQLiteRepository) GetChatByDevice(deviceID, jid string) (*domainChatStorage.Chat, error) {
 	query := `
-		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived
+		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived,
+			pinned, muted_until, unread_count, deleted_at
 		FROM chats
 		WHERE jid = ? AND device_id = ?
 	`
@@ -83,19 +136,31 @@ func (r *SQLiteRepository) GetChatByDevice(deviceID, jid string) (*domainChatSto
 	return chat, err
 }
 
-// GetMessageByID retrieves a message by its ID from any chat
-// This is more efficient than searching through all chats
-func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message, error) {
+// GetMessageByID retrieves a message by its ID. Empty deviceID or chatJID
+// search every device or chat.
+func (r *SQLiteRepository) GetMessageByID(id, deviceID, chatJID string) (*domainChatStorage.Message, error) {
+	r.flushPendingMessages()
+	conditions := []string{"id = ?"}
+	args := []any{id}
+	if deviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, deviceID)
+	}
+	if chatJID != "" {
+		conditions = append(conditions, "chat_jid = ?")
+		args = append(args, chatJID)
+	}
+
 	query := `
 		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
 			media_type, call_metadata, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-		FROM messages
-		WHERE id = ?
+			file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		FROM message_rows
+		WHERE ` + strings.Join(conditions, " AND ") + `
 		LIMIT 1
 	`
 
-	message, err := r.scanMessage(r.db.QueryRow(query, id))
+	message, err := r.scanMessage(r.db.QueryRow(query, args...))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -103,14 +168,95 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 	return message, err
 }
 
+// GetMessageWithContext retrieves a message together with the messages
+// stored right before and after it in the same chat, in (timestamp, id) order.
+// Each side is capped at 100 messages.
+func (r *SQLiteRepository) GetMessageWithContext(deviceID, chatJID, id string, before, after int) (*domainChatStorage.MessageContext, error) {
+	if deviceID == "" {
+		return nil, fmt.Errorf("device_id is required for message queries (data isolation)")
+	}
+
+	target, err := r.GetMessageByID(id, deviceID, chatJID)
+	if err != nil || target == nil {
+		return nil, err
+	}
+
+	result := &domainChatStorage.MessageContext{Message: target}
+	if before > 0 {
+		result.Before, err = r.queryMessagesAround(target, "(timestamp < ? OR (timestamp = ? AND id < ?))", "DESC", min(before, 100))
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(result.Before)
+	}
+	if after > 0 {
+		result.After, err = r.queryMessagesAround(target, "(timestamp > ? OR (timestamp = ? AND id > ?))", "ASC", min(after, 100))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	messages := make([]*domainChatStorage.Message, 0, len(result.Before)+len(result.After)+1)
+	messages = append(messages, result.Before...)
+	messages = append(messages, target)
+	messages = append(messages, result.After...)
+
+	if err := r.loadMessageReactions(target.DeviceID, target.ChatJID, messages); err != nil {
+		return nil, err
+	}
+	if err := r.loadQuotedMessages(target.DeviceID, target.ChatJID, messages); err != nil {
+		return nil, err
+	}
+	if err := r.loadMessagePolls(target.DeviceID, target.ChatJID, messages); err != nil {
+		return nil, fmt.Errorf("failed to load message polls: %w", err)
+	}
+	if err := r.loadMessagePins(target.DeviceID, target.ChatJID, messages); err != nil {
+		return nil, fmt.Errorf("failed to load message pins: %w", err)
+	}
+
+	return result, nil
+}
+
+// queryMessagesAround returns up to limit messages of target's chat on the
+// side of target selected by position, ordered by (timestamp, id) in the given direction.
+func (r *SQLiteRepository) queryMessagesAround(target *domainChatStorage.Message, position, order string, limit int) ([]*domainChatStorage.Message, error) {
+	query := `
+		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
+			media_type, call_metadata, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		FROM message_rows
+		WHERE device_id = ? AND chat_jid = ? AND ` + position + `
+		ORDER BY timestamp ` + order + `, id ` + order + `
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, target.DeviceID, target.ChatJID, target.Timestamp, target.Timestamp, target.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.Message
+	for rows.Next() {
+		message, err := r.scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
 // GetMessageByIDAndDevice retrieves a message by its ID scoped to a specific device,
 // so a message ID belonging to another device cannot be read from device-isolated flows.
 func (r *SQLiteRepository) GetMessageByIDAndDevice(deviceID, id string) (*domainChatStorage.Message, error) {
+	r.flushPendingMessages()
 	query := `
 		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
 			media_type, call_metadata, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-		FROM messages
+			file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		FROM message_rows
 		WHERE id = ? AND device_id = ?
 		LIMIT 1
 	`
@@ -153,6 +299,13 @@ func (r *SQLiteRepository) GetMessageEdits(originalMessageID, deviceID string) (
 		); err != nil {
 			return nil, err
 		}
+		var err error
+		if edit.PreviousContent, err = r.cipher.decryptString(edit.PreviousContent); err != nil {
+			return nil, err
+		}
+		if edit.NewContent, err = r.cipher.decryptString(edit.NewContent); err != nil {
+			return nil, err
+		}
 		edits = append(edits, &edit)
 	}
 	return edits, rows.Err()
@@ -171,9 +324,13 @@ func (r *SQLiteRepository) buildChatFilterQuery(filter *domainChatStorage.ChatFi
 		conditions = append(conditions, `EXISTS (SELECT 1 FROM messages m WHERE m.chat_jid = c.jid AND m.device_id = c.device_id AND m.media_type NOT IN ('', 'call'))`)
 	}
 
-	if filter.DeviceID != "" {
-		conditions = append(conditions, "c.device_id = ?")
-		args = append(args, filter.DeviceID)
+	conditions = append(conditions, "c.device_id = ?")
+	args = append(args, filter.DeviceID)
+
+	if filter.Trashed {
+		conditions = append(conditions, "c.deleted_at > 0")
+	} else {
+		conditions = append(conditions, "c.deleted_at = 0")
 	}
 
 	if filter.IsArchived != nil {
@@ -185,13 +342,46 @@ func (r *SQLiteRepository) buildChatFilterQuery(filter *domainChatStorage.ChatFi
 		}
 	}
 
+	if filter.IsPinned != nil {
+		conditions = append(conditions, "c.pinned = ?")
+		args = append(args, *filter.IsPinned)
+	}
+
+	if filter.IsMuted != nil {
+		if *filter.IsMuted {
+			conditions = append(conditions, "c.muted_until > ?")
+		} else {
+			conditions = append(conditions, "c.muted_until <= ?")
+		}
+		args = append(args, time.Now().Unix())
+	}
+
+	if filter.HasUnread != nil {
+		if *filter.HasUnread {
+			conditions = append(conditions, "c.unread_count > 0")
+		} else {
+			conditions = append(conditions, "c.unread_count = 0")
+		}
+	}
+
+	if filter.LabelID != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM chat_labels cl WHERE cl.device_id = c.device_id AND cl.chat_jid = c.jid AND cl.label_id = ?)`)
+		args = append(args, filter.LabelID)
+	}
+
 	return joinClause, conditions, args
 }
 
 // GetChats retrieves chats with filtering
 func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
+	// Require device_id for data isolation - fail fast if missing
+	if filter.DeviceID == "" {
+		return nil, fmt.Errorf("device_id is required for chat queries (data isolation)")
+	}
+
 	query := `
-		SELECT c.device_id, c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at, c.archived
+		SELECT c.device_id, c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at, c.archived,
+			c.pinned, c.muted_until, c.unread_count, c.deleted_at
 		FROM chats c
 	`
 
@@ -238,6 +428,7 @@ func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*do
 
 // DeleteChat deletes a chat and all its messages
 func (r *SQLiteRepository) DeleteChat(jid string) error {
+	r.flushPendingMessages()
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
@@ -247,12 +438,30 @@ func (r *SQLiteRepository) DeleteChat(jid string) error {
 	if _, err := tx.Exec("DELETE FROM message_reactions WHERE chat_jid = ?", jid); err != nil {
 		return err
 	}
+	if _, err := tx.Exec("DELETE FROM message_pins WHERE chat_jid = ?", jid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM poll_votes WHERE chat_jid = ?", jid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM polls WHERE chat_jid = ?", jid); err != nil {
+		return err
+	}
 	if _, err := tx.Exec("DELETE FROM message_edits WHERE chat_jid = ?", jid); err != nil {
 		return err
 	}
 	if _, err := tx.Exec("DELETE FROM chatwoot_message_links WHERE wa_chat_jid = ?", jid); err != nil {
 		return err
 	}
+	if _, err := tx.Exec("DELETE FROM chat_labels WHERE chat_jid = ?", jid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM message_labels WHERE chat_jid = ?", jid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM drafts WHERE chat_jid = ?", jid); err != nil {
+		return err
+	}
 
 	// Delete messages after dependent rows to keep cleanup explicit.
 	_, err = tx.Exec("DELETE FROM messages WHERE chat_jid = ?", jid)
@@ -271,6 +480,7 @@ func (r *SQLiteRepository) DeleteChat(jid string) error {
 
 // DeleteChatByDevice deletes a chat and all its messages for a specific device
 func (r *SQLiteRepository) DeleteChatByDevice(deviceID, jid string) error {
+	r.flushPendingMessages()
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
@@ -280,12 +490,30 @@ func (r *SQLiteRepository) DeleteChatByDevice(deviceID, jid string) error {
 	if _, err := tx.Exec("DELETE FROM message_reactions WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
 		return err
 	}
+	if _, err := tx.Exec("DELETE FROM message_pins WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM poll_votes WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM polls WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
+		return err
+	}
 	if _, err := tx.Exec("DELETE FROM message_edits WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
 		return err
 	}
 	if _, err := tx.Exec("DELETE FROM chatwoot_message_links WHERE wa_chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
 		return err
 	}
+	if _, err := tx.Exec("DELETE FROM chat_labels WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM message_labels WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM drafts WHERE chat_jid = ? AND device_id = ?", jid, deviceID); err != nil {
+		return err
+	}
 
 	// Delete messages after dependent rows to keep cleanup explicit.
 	_, err = tx.Exec("DELETE FROM messages WHERE chat_jid = ? AND device_id = ?", jid, deviceID)
@@ -302,6 +530,62 @@ func (r *SQLiteRepository) DeleteChatByDevice(deviceID, jid string) error {
 	return tx.Commit()
 }
 
+// TrashChat moves a chat to the trash. Its messages are kept until the chat is
+// restored or purged.
+func (r *SQLiteRepository) TrashChat(deviceID, jid string) error {
+	_, err := r.db.Exec(`
+		UPDATE chats SET deleted_at = ?, updated_at = ?
+		WHERE jid = ? AND device_id = ? AND deleted_at = 0
+	`, time.Now().Unix(), time.Now(), jid, deviceID)
+	return err
+}
+
+// RestoreChat takes a chat out of the trash and reports whether it was there.
+func (r *SQLiteRepository) RestoreChat(deviceID, jid string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE chats SET deleted_at = 0, updated_at = ?
+		WHERE jid = ? AND device_id = ? AND deleted_at > 0
+	`, time.Now(), jid, deviceID)
+	if err != nil {
+		return false, err
+	}
+	restored, _ := result.RowsAffected()
+	return restored > 0, nil
+}
+
+// PurgeTrashedChats permanently deletes, for every device, the chats trashed
+// before the given time and returns how many were deleted.
+func (r *SQLiteRepository) PurgeTrashedChats(before time.Time) (int, error) {
+	rows, err := r.db.Query(`SELECT device_id, jid FROM chats WHERE deleted_at > 0 AND deleted_at < ?`, before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	type trashedChat struct{ deviceID, jid string }
+	var expired []trashedChat
+	for rows.Next() {
+		var chat trashedChat
+		if err := rows.Scan(&chat.deviceID, &chat.jid); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		expired = append(expired, chat)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	purged := 0
+	for _, chat := range expired {
+		if err := r.DeleteChatByDevice(chat.deviceID, chat.jid); err != nil {
+			return purged, fmt.Errorf("failed to purge chat %s: %w", chat.jid, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
 // StoreMessage creates or updates a message
 func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) error {
 	now := time.Now()
@@ -312,16 +596,34 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 	if message.Content == "" && message.MediaType == "" {
 		return nil
 	}
+	if r.bufferMessage(message) {
+		return nil
+	}
+
+	content, mediaKey, err := r.sealMessage(message)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.storeMediaExec(tx, message, mediaKey); err != nil {
+		return err
+	}
 
 	// Try update first, then insert if no rows affected (cross-db compatible)
-	result, err := r.db.Exec(`
+	result, err := tx.Exec(`
 		UPDATE messages SET sender = ?, content = ?, timestamp = ?, is_from_me = ?,
-			media_type = ?, call_metadata = ?, filename = ?, url = ?, media_key = ?, file_sha256 = ?,
-			file_enc_sha256 = ?, file_length = ?, referral_metadata = ?, updated_at = ?
+			media_type = ?, call_metadata = ?, filename = ?, file_sha256 = ?, media_sha256 = ?,
+			referral_metadata = ?, quoted_message_id = ?, quoted_participant = ?, updated_at = ?
 		WHERE id = ? AND chat_jid = ? AND device_id = ?
-	`, message.Sender, message.Content, message.Timestamp, message.IsFromMe,
-		message.MediaType, message.CallMetadata, message.Filename, message.URL, message.MediaKey, message.FileSHA256,
-		message.FileEncSHA256, message.FileLength, message.ReferralMetadata, message.UpdatedAt,
+	`, message.Sender, content, message.Timestamp, message.IsFromMe,
+		message.MediaType, message.CallMetadata, message.Filename, message.FileSHA256, mediaRef(message),
+		message.ReferralMetadata, message.QuotedMessageID, message.QuotedParticipant, message.UpdatedAt,
 		message.ID, message.ChatJID, message.DeviceID)
 	if err != nil {
 		return err
@@ -329,18 +631,24 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		_, err = r.db.Exec(`
+		_, err = tx.Exec(`
 			INSERT INTO messages (
 				id, chat_jid, device_id, sender, content, timestamp, is_from_me,
-				media_type, call_metadata, filename, url, media_key, file_sha256,
-				file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, message.ID, message.ChatJID, message.DeviceID, message.Sender, message.Content,
+				media_type, call_metadata, filename, file_sha256, media_sha256,
+				referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, message.ID, message.ChatJID, message.DeviceID, message.Sender, content,
 			message.Timestamp, message.IsFromMe, message.MediaType, message.CallMetadata, message.Filename,
-			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-			message.FileLength, message.ReferralMetadata, message.CreatedAt, message.UpdatedAt)
+			message.FileSHA256, mediaRef(message), message.ReferralMetadata, message.QuotedMessageID, message.QuotedParticipant,
+			message.CreatedAt, message.UpdatedAt)
+		if err != nil {
+			return err
+		}
 	}
-	return err
+	if err := r.storeOutboxEventExec(tx, message); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // StoreMessagesBatch creates or updates multiple messages in a single transaction
@@ -358,8 +666,8 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 	// Prepare statements for update and insert
 	updateStmt, err := tx.Prepare(`
 		UPDATE messages SET sender = ?, content = ?, timestamp = ?, is_from_me = ?,
-			media_type = ?, call_metadata = ?, filename = ?, url = ?, media_key = ?, file_sha256 = ?,
-			file_enc_sha256 = ?, file_length = ?, referral_metadata = ?, updated_at = ?
+			media_type = ?, call_metadata = ?, filename = ?, file_sha256 = ?, media_sha256 = ?,
+			referral_metadata = ?, quoted_message_id = ?, quoted_participant = ?, updated_at = ?
 		WHERE id = ? AND chat_jid = ? AND device_id = ?
 	`)
 	if err != nil {
@@ -370,9 +678,9 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 	insertStmt, err := tx.Prepare(`
 		INSERT INTO messages (
 			id, chat_jid, device_id, sender, content, timestamp, is_from_me,
-			media_type, call_metadata, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			media_type, call_metadata, filename, file_sha256, media_sha256,
+			referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare insert statement: %w", err)
@@ -388,10 +696,18 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		message.CreatedAt = now
 		message.UpdatedAt = now
 
+		content, mediaKey, err := r.sealMessage(message)
+		if err != nil {
+			return err
+		}
+		if err := r.storeMediaExec(tx, message, mediaKey); err != nil {
+			return err
+		}
+
 		result, err := updateStmt.Exec(
-			message.Sender, message.Content, message.Timestamp, message.IsFromMe,
-			message.MediaType, message.CallMetadata, message.Filename, message.URL, message.MediaKey, message.FileSHA256,
-			message.FileEncSHA256, message.FileLength, message.ReferralMetadata, message.UpdatedAt,
+			message.Sender, content, message.Timestamp, message.IsFromMe,
+			message.MediaType, message.CallMetadata, message.Filename, message.FileSHA256, mediaRef(message),
+			message.ReferralMetadata, message.QuotedMessageID, message.QuotedParticipant, message.UpdatedAt,
 			message.ID, message.ChatJID, message.DeviceID,
 		)
 		if err != nil {
@@ -401,15 +717,18 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		rowsAffected, _ := result.RowsAffected()
 		if rowsAffected == 0 {
 			_, err = insertStmt.Exec(
-				message.ID, message.ChatJID, message.DeviceID, message.Sender, message.Content,
+				message.ID, message.ChatJID, message.DeviceID, message.Sender, content,
 				message.Timestamp, message.IsFromMe, message.MediaType, message.CallMetadata, message.Filename,
-				message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-				message.FileLength, message.ReferralMetadata, message.CreatedAt, message.UpdatedAt,
+				message.FileSHA256, mediaRef(message), message.ReferralMetadata, message.QuotedMessageID, message.QuotedParticipant,
+				message.CreatedAt, message.UpdatedAt,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to insert message %s: %w", message.ID, err)
 			}
 		}
+		if err := r.storeOutboxEventExec(tx, message); err != nil {
+			return err
+		}
 	}
 
 	return tx.Commit()
@@ -469,16 +788,8 @@ func (r *SQLiteRepository) DeleteReaction(messageID, reactorJID, deviceID string
 	return err
 }
 
-// GetMessages retrieves messages with filtering
-func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
-	// Require device_id for data isolation - fail fast if missing
-	if filter.DeviceID == "" {
-		return nil, fmt.Errorf("device_id is required for message queries (data isolation)")
-	}
-
-	var conditions []string
-	var args []any
-
+// buildMessageFilterConditions constructs the WHERE conditions shared by message list and iteration queries.
+func (r *SQLiteRepository) buildMessageFilterConditions(filter *domainChatStorage.MessageFilter) (conditions []string, args []any) {
 	conditions = append(conditions, "chat_jid = ?")
 	args = append(args, filter.ChatJID)
 
@@ -505,13 +816,32 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 		args = append(args, *filter.IsFromMe)
 	}
 
+	return conditions, args
+}
+
+// GetMessages retrieves messages with filtering
+func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
+	r.flushPendingMessages()
+	// Require device_id for data isolation - fail fast if missing
+	if filter.DeviceID == "" {
+		return nil, fmt.Errorf("device_id is required for message queries (data isolation)")
+	}
+
+	conditions, args := r.buildMessageFilterConditions(filter)
+
+	if filter.Before != nil {
+		conditions = append(conditions, "(timestamp < ? OR (timestamp = ? AND id < ?))")
+		args = append(args, filter.Before.Timestamp, filter.Before.Timestamp, filter.Before.ID)
+	}
+
+	// id breaks timestamp ties so keyset pages never skip or repeat rows.
 	query := `
 		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
 			media_type, call_metadata, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-		FROM messages
+			file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		FROM message_rows
 		WHERE ` + strings.Join(conditions, " AND ") + `
-		ORDER BY timestamp DESC
+		ORDER BY timestamp DESC, id DESC
 	`
 
 	// Safely add LIMIT and OFFSET using parameterized values
@@ -522,7 +852,7 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 		query += " LIMIT ?"
 		args = append(args, filter.Limit)
 
-		if filter.Offset > 0 {
+		if filter.Offset > 0 && filter.Before == nil {
 			query += " OFFSET ?"
 			args = append(args, filter.Offset)
 		}
@@ -551,77 +881,295 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 		return nil, err
 	}
 
-	return messages, nil
-}
-
-// SearchMessages performs database-level search for messages containing specific text
-func (r *SQLiteRepository) SearchMessages(deviceID, chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
-	// Require device_id for data isolation - fail fast if missing
-	if deviceID == "" {
-		return nil, fmt.Errorf("device_id is required for message search (data isolation)")
+	if err := r.loadQuotedMessages(filter.DeviceID, filter.ChatJID, messages); err != nil {
+		return nil, err
 	}
 
-	if strings.TrimSpace(searchText) == "" {
-		return []*domainChatStorage.Message{}, nil
+	if err := r.loadMessagePolls(filter.DeviceID, filter.ChatJID, messages); err != nil {
+		return nil, fmt.Errorf("failed to load message polls: %w", err)
 	}
 
-	var conditions []string
-	var args []any
-
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, chatJID)
-
-	conditions = append(conditions, "device_id = ?")
-	args = append(args, deviceID)
-
-	// Add search condition using LIKE operator for case-insensitive search
-	conditions = append(conditions, "LOWER(content) LIKE ?")
-	args = append(args, "%"+strings.ToLower(searchText)+"%")
+	if err := r.loadMessagePins(filter.DeviceID, filter.ChatJID, messages); err != nil {
+		return nil, fmt.Errorf("failed to load message pins: %w", err)
+	}
 
-	query := `
-		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
-			media_type, call_metadata, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-		FROM messages
-		WHERE ` + strings.Join(conditions, " AND ") + `
-		ORDER BY timestamp DESC
-	`
+	return messages, nil
+}
 
-	// Add limit with validation
-	if limit > 0 {
-		if limit > 1000 {
-			limit = 1000
+// loadQuotedMessages attaches the stored message each reply quotes. Quoted
+// messages outside the page are fetched in one query per chunk; replies to
+// messages that were never stored keep only QuotedMessageID/QuotedParticipant.
+func (r *SQLiteRepository) loadQuotedMessages(deviceID, chatJID string, messages []*domainChatStorage.Message) error {
+	byID := make(map[string]*domainChatStorage.Message, len(messages))
+	for _, message := range messages {
+		if message != nil {
+			byID[message.ID] = message
 		}
-		query += " LIMIT ?"
-		args = append(args, limit)
 	}
 
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search messages: %w", err)
+	var missingIDs []string
+	seen := make(map[string]bool)
+	for _, message := range messages {
+		if message == nil || message.QuotedMessageID == "" || seen[message.QuotedMessageID] {
+			continue
+		}
+		seen[message.QuotedMessageID] = true
+		if _, ok := byID[message.QuotedMessageID]; !ok {
+			missingIDs = append(missingIDs, message.QuotedMessageID)
+		}
 	}
-	defer rows.Close()
 
-	var messages []*domainChatStorage.Message
-	for rows.Next() {
-		message, err := r.scanMessage(rows)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan message: %w", err)
+	quoted := make(map[string]*domainChatStorage.Message, len(seen))
+	for id := range seen {
+		if message, ok := byID[id]; ok {
+			quoted[id] = message
 		}
-		messages = append(messages, message)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating messages: %w", err)
-	}
+	for start := 0; start < len(missingIDs); start += 500 {
+		end := min(start+500, len(missingIDs))
 
-	if err := r.loadMessageReactions(deviceID, chatJID, messages); err != nil {
-		return nil, fmt.Errorf("failed to load message reactions: %w", err)
-	}
+		batchIDs := missingIDs[start:end]
+		placeholders := make([]string, 0, len(batchIDs))
+		args := make([]any, 0, len(batchIDs)+2)
+		args = append(args, deviceID, chatJID)
+		for _, messageID := range batchIDs {
+			placeholders = append(placeholders, "?")
+			args = append(args, messageID)
+		}
+
+		query := `
+			SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
+				media_type, call_metadata, filename, url, media_key, file_sha256,
+				file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+			FROM message_rows
+			WHERE device_id = ? AND chat_jid = ? AND id IN (` + strings.Join(placeholders, ",") + `)
+		`
+
+		rows, err := r.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			message, err := r.scanMessage(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			quoted[message.ID] = message
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+	}
+
+	for _, message := range messages {
+		if message == nil || message.QuotedMessageID == "" {
+			continue
+		}
+		if target, ok := quoted[message.QuotedMessageID]; ok {
+			// Shallow copy without its own quote so the JSON stays one level deep.
+			copied := *target
+			copied.QuotedMessage = nil
+			copied.Reactions = nil
+			message.QuotedMessage = &copied
+		}
+	}
+
+	return nil
+}
+
+// iterateMessagesBatchSize bounds how many rows IterateMessages holds in memory at once.
+const iterateMessagesBatchSize = 500
+
+// IterateMessages walks all messages matching filter oldest-first. Rows are read in
+// keyset-paged batches and the result set is closed before fn runs, so callbacks may
+// issue their own queries even though chatstorage runs with db.SetMaxOpenConns(1).
+// Limit and Offset on the filter are ignored.
+func (r *SQLiteRepository) IterateMessages(filter *domainChatStorage.MessageFilter, fn func(*domainChatStorage.Message) error) error {
+	r.flushPendingMessages()
+	if filter.DeviceID == "" {
+		return fmt.Errorf("device_id is required for message queries (data isolation)")
+	}
+
+	baseConditions, baseArgs := r.buildMessageFilterConditions(filter)
+
+	var lastTimestamp time.Time
+	var lastID string
+	for {
+		conditions := append([]string{}, baseConditions...)
+		args := append([]any{}, baseArgs...)
+		if lastID != "" {
+			conditions = append(conditions, "(timestamp > ? OR (timestamp = ? AND id > ?))")
+			args = append(args, lastTimestamp, lastTimestamp, lastID)
+		}
+		args = append(args, iterateMessagesBatchSize)
+
+		query := `
+			SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
+				media_type, call_metadata, filename, url, media_key, file_sha256,
+				file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+			FROM message_rows
+			WHERE ` + strings.Join(conditions, " AND ") + `
+			ORDER BY timestamp ASC, id ASC
+			LIMIT ?
+		`
+
+		rows, err := r.db.Query(query, args...)
+		if err != nil {
+			return err
+		}
+
+		batch := make([]*domainChatStorage.Message, 0, iterateMessagesBatchSize)
+		for rows.Next() {
+			message, err := r.scanMessage(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			batch = append(batch, message)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+
+		for _, message := range batch {
+			if err := fn(message); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < iterateMessagesBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		lastTimestamp, lastID = last.Timestamp, last.ID
+	}
+}
+
+// SearchMessages performs database-level search for messages containing specific text
+func (r *SQLiteRepository) SearchMessages(deviceID, chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
+	r.flushPendingMessages()
+	// Require device_id for data isolation - fail fast if missing
+	if deviceID == "" {
+		return nil, fmt.Errorf("device_id is required for message search (data isolation)")
+	}
+
+	if strings.TrimSpace(searchText) == "" {
+		return []*domainChatStorage.Message{}, nil
+	}
+
+	var conditions []string
+	var args []any
+
+	conditions = append(conditions, "chat_jid = ?")
+	args = append(args, chatJID)
+
+	conditions = append(conditions, "device_id = ?")
+	args = append(args, deviceID)
+
+	// Add search condition using LIKE operator for case-insensitive search.
+	// Encrypted content can only be matched after decryption, so the chat's
+	// rows are scanned and filtered below instead.
+	needle := strings.ToLower(searchText)
+	if r.cipher == nil {
+		conditions = append(conditions, "LOWER(content) LIKE ?")
+		args = append(args, "%"+needle+"%")
+	}
+
+	query := `
+		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
+			media_type, call_metadata, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		FROM message_rows
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY timestamp DESC
+	`
+
+	// Add limit with validation
+	if limit > 1000 {
+		limit = 1000
+	}
+	if limit > 0 && r.cipher == nil {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.Message
+	for rows.Next() {
+		message, err := r.scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		if r.cipher != nil && !strings.Contains(strings.ToLower(message.Content), needle) {
+			continue
+		}
+		messages = append(messages, message)
+		if limit > 0 && len(messages) == limit {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating messages: %w", err)
+	}
+	// Release the connection before loading reactions; the loop may stop early.
+	rows.Close()
+
+	if err := r.loadMessageReactions(deviceID, chatJID, messages); err != nil {
+		return nil, fmt.Errorf("failed to load message reactions: %w", err)
+	}
 
 	return messages, nil
 }
 
+// searchIndexSteps rebuilds the b-tree indexes that back message search and
+// chat lookups, then refreshes the statistics the query planner uses to pick
+// them. Search is a LIKE scan over the device/chat-scoped rows, so stale
+// statistics after bulk imports or purges are what make it slow.
+var searchIndexSteps = []struct {
+	name  string
+	query string
+}{
+	{"reindex messages", `REINDEX messages`},
+	{"reindex chats", `REINDEX chats`},
+	{"analyze messages", `ANALYZE messages`},
+	{"analyze chats", `ANALYZE chats`},
+	{"optimize", `PRAGMA optimize`},
+}
+
+// RebuildSearchIndex runs every search index step in order, stopping early if
+// ctx is cancelled between steps.
+func (r *SQLiteRepository) RebuildSearchIndex(ctx context.Context, progress func(step string, completed, total int)) error {
+	r.flushPendingMessages()
+	total := len(searchIndexSteps)
+	for i, step := range searchIndexSteps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(step.name, i, total)
+		}
+		if _, err := r.db.ExecContext(ctx, step.query); err != nil {
+			return fmt.Errorf("search index step %q failed: %w", step.name, err)
+		}
+	}
+	if progress != nil {
+		progress("", total, total)
+	}
+	return nil
+}
+
 func (r *SQLiteRepository) loadMessageReactions(deviceID, chatJID string, messages []*domainChatStorage.Message) error {
 	if len(messages) == 0 {
 		return nil
@@ -696,56 +1244,81 @@ func (r *SQLiteRepository) loadMessageReactions(deviceID, chatJID string, messag
 
 // DeleteMessage deletes a specific message
 func (r *SQLiteRepository) DeleteMessage(id, chatJID string) error {
+	r.flushPendingMessages()
 	if _, err := r.db.Exec("DELETE FROM message_reactions WHERE message_id = ? AND chat_jid = ?", id, chatJID); err != nil {
 		return err
 	}
+	if _, err := r.db.Exec("DELETE FROM message_pins WHERE message_id = ? AND chat_jid = ?", id, chatJID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("DELETE FROM poll_votes WHERE poll_message_id = ? AND chat_jid = ?", id, chatJID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("DELETE FROM polls WHERE message_id = ? AND chat_jid = ?", id, chatJID); err != nil {
+		return err
+	}
 	if _, err := r.db.Exec("DELETE FROM chatwoot_message_links WHERE wa_message_id = ? AND wa_chat_jid = ?", id, chatJID); err != nil {
 		return err
 	}
+	if _, err := r.db.Exec("DELETE FROM message_labels WHERE message_id = ? AND chat_jid = ?", id, chatJID); err != nil {
+		return err
+	}
 	_, err := r.db.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ?", id, chatJID)
 	return err
 }
 
 // DeleteMessageByDevice deletes a specific message for a specific device
 func (r *SQLiteRepository) DeleteMessageByDevice(deviceID, id, chatJID string) error {
+	r.flushPendingMessages()
 	if _, err := r.db.Exec("DELETE FROM message_reactions WHERE message_id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
 		return err
 	}
+	if _, err := r.db.Exec("DELETE FROM message_pins WHERE message_id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("DELETE FROM poll_votes WHERE poll_message_id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("DELETE FROM polls WHERE message_id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
+		return err
+	}
 	if _, err := r.db.Exec("DELETE FROM chatwoot_message_links WHERE wa_message_id = ? AND wa_chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
 		return err
 	}
+	if _, err := r.db.Exec("DELETE FROM message_labels WHERE message_id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
+		return err
+	}
 	_, err := r.db.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID)
 	return err
 }
 
-// UpsertChatwootMessageLink records the stable mapping between a WhatsApp
-// message and the Chatwoot row created for it.
-func (r *SQLiteRepository) UpsertChatwootMessageLink(link *domainChatStorage.ChatwootMessageLink) error {
-	if link == nil {
-		return fmt.Errorf("chatwoot message link is required")
-	}
-	if strings.TrimSpace(link.DeviceID) == "" {
-		return fmt.Errorf("device_id is required for chatwoot message link")
+// StoreGroupSnapshot creates or replaces the stored metadata and membership of a group.
+func (r *SQLiteRepository) StoreGroupSnapshot(snapshot *domainChatStorage.GroupSnapshot) error {
+	if snapshot == nil {
+		return nil
 	}
-	if strings.TrimSpace(link.WhatsAppMessageID) == "" {
-		return fmt.Errorf("wa_message_id is required for chatwoot message link")
+	if snapshot.GroupJID == "" || snapshot.DeviceID == "" {
+		return fmt.Errorf("group snapshot requires group_jid and device_id")
 	}
 
-	now := time.Now()
-	if link.CreatedAt.IsZero() {
-		link.CreatedAt = now
+	participants := snapshot.Participants
+	if participants == nil {
+		participants = []domainChatStorage.GroupParticipant{}
 	}
-	link.UpdatedAt = now
+	encoded, err := json.Marshal(participants)
+	if err != nil {
+		return fmt.Errorf("failed to encode group participants: %w", err)
+	}
+	snapshot.UpdatedAt = time.Now()
 
 	result, err := r.db.Exec(`
-		UPDATE chatwoot_message_links
-		SET wa_chat_jid = ?, chatwoot_message_id = ?, chatwoot_conversation_id = ?,
-		    chatwoot_inbox_id = ?, chatwoot_contact_inbox_source_id = ?, source_id = ?,
-		    direction = ?, is_read = ?, updated_at = ?
-		WHERE device_id = ? AND wa_message_id = ?
-	`, link.WhatsAppChatJID, link.ChatwootMessageID, link.ChatwootConversationID,
-		link.ChatwootInboxID, link.ChatwootContactInboxSourceID, link.SourceID,
-		link.Direction, link.IsRead, link.UpdatedAt, link.DeviceID, link.WhatsAppMessageID)
+		UPDATE group_snapshots
+		SET name = ?, topic = ?, owner_jid = ?, ephemeral_expiration = ?, announce = ?, locked = ?,
+			participants = ?, participant_count = ?, updated_at = ?
+		WHERE device_id = ? AND group_jid = ?
+	`, snapshot.Name, snapshot.Topic, snapshot.OwnerJID, snapshot.EphemeralExpiration, snapshot.Announce, snapshot.Locked,
+		string(encoded), len(participants), snapshot.UpdatedAt,
+		snapshot.DeviceID, snapshot.GroupJID)
 	if err != nil {
 		return err
 	}
@@ -753,356 +1326,491 @@ func (r *SQLiteRepository) UpsertChatwootMessageLink(link *domainChatStorage.Cha
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
 		_, err = r.db.Exec(`
-			INSERT INTO chatwoot_message_links (
-				device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
-				chatwoot_conversation_id, chatwoot_inbox_id,
-				chatwoot_contact_inbox_source_id, source_id, direction,
-				is_read, created_at, updated_at
-			)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, link.DeviceID, link.WhatsAppMessageID, link.WhatsAppChatJID,
-			link.ChatwootMessageID, link.ChatwootConversationID, link.ChatwootInboxID,
-			link.ChatwootContactInboxSourceID, link.SourceID, link.Direction,
-			link.IsRead, link.CreatedAt, link.UpdatedAt)
+			INSERT INTO group_snapshots (
+				device_id, group_jid, name, topic, owner_jid, ephemeral_expiration, announce, locked,
+				participants, participant_count, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, snapshot.DeviceID, snapshot.GroupJID, snapshot.Name, snapshot.Topic, snapshot.OwnerJID,
+			snapshot.EphemeralExpiration, snapshot.Announce, snapshot.Locked,
+			string(encoded), len(participants), snapshot.UpdatedAt)
 	}
 	return err
 }
 
-func (r *SQLiteRepository) GetChatwootMessageLinkByWhatsAppID(deviceID, waMessageID string) (*domainChatStorage.ChatwootMessageLink, error) {
-	query := `
-		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
-			chatwoot_conversation_id, chatwoot_inbox_id,
-			chatwoot_contact_inbox_source_id, source_id, direction,
-			is_read, created_at, updated_at
-		FROM chatwoot_message_links
-		WHERE device_id = ? AND wa_message_id = ?
-		LIMIT 1
-	`
-
-	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, deviceID, waMessageID))
+// GetGroupSnapshot returns the stored snapshot of a group, or nil when the
+// device has not seen the group yet.
+func (r *SQLiteRepository) GetGroupSnapshot(deviceID, groupJID string) (*domainChatStorage.GroupSnapshot, error) {
+	var snapshot domainChatStorage.GroupSnapshot
+	var participants string
+	err := r.db.QueryRow(`
+		SELECT device_id, group_jid, name, topic, owner_jid, ephemeral_expiration, announce, locked,
+			participants, updated_at
+		FROM group_snapshots
+		WHERE device_id = ? AND group_jid = ?
+	`, deviceID, groupJID).Scan(
+		&snapshot.DeviceID, &snapshot.GroupJID, &snapshot.Name, &snapshot.Topic, &snapshot.OwnerJID,
+		&snapshot.EphemeralExpiration, &snapshot.Announce, &snapshot.Locked, &participants, &snapshot.UpdatedAt,
+	)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	return link, err
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(participants), &snapshot.Participants); err != nil {
+		return nil, fmt.Errorf("failed to decode participants of group %s: %w", groupJID, err)
+	}
+	return &snapshot, nil
 }
 
-func (r *SQLiteRepository) GetChatwootMessageLinkByChatwootID(deviceID string, chatwootMessageID int) (*domainChatStorage.ChatwootMessageLink, error) {
-	query := `
-		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
-			chatwoot_conversation_id, chatwoot_inbox_id,
-			chatwoot_contact_inbox_source_id, source_id, direction,
-			is_read, created_at, updated_at
-		FROM chatwoot_message_links
-		WHERE device_id = ? AND chatwoot_message_id = ?
-		LIMIT 1
-	`
+// GetGroupParticipantCounts returns the participant count of every listed group
+// that has a snapshot for the device.
+func (r *SQLiteRepository) GetGroupParticipantCounts(deviceID string, groupJIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(groupJIDs) == 0 {
+		return counts, nil
+	}
 
-	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, deviceID, chatwootMessageID))
-	if err == sql.ErrNoRows {
-		return nil, nil
+	args := make([]any, 0, len(groupJIDs)+1)
+	args = append(args, deviceID)
+	for _, jid := range groupJIDs {
+		args = append(args, jid)
 	}
-	return link, err
-}
 
-func (r *SQLiteRepository) GetLatestChatwootMessageLinkByConversation(conversationID int) (*domainChatStorage.ChatwootMessageLink, error) {
-	query := `
-		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
-			chatwoot_conversation_id, chatwoot_inbox_id,
-			chatwoot_contact_inbox_source_id, source_id, direction,
-			is_read, created_at, updated_at
-		FROM chatwoot_message_links
-		WHERE chatwoot_conversation_id = ?
-		ORDER BY updated_at DESC, created_at DESC
-		LIMIT 1
-	`
+	rows, err := r.db.Query(`
+		SELECT group_jid, participant_count
+		FROM group_snapshots
+		WHERE device_id = ? AND group_jid IN (`+placeholders(len(groupJIDs))+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, conversationID))
-	if err == sql.ErrNoRows {
-		return nil, nil
+	for rows.Next() {
+		var jid string
+		var count int
+		if err := rows.Scan(&jid, &count); err != nil {
+			return nil, err
+		}
+		counts[jid] = count
 	}
-	return link, err
+	return counts, rows.Err()
 }
 
-func (r *SQLiteRepository) GetLatestUnreadChatwootMessageLinkByChat(deviceID, waChatJID string) (*domainChatStorage.ChatwootMessageLink, error) {
-	query := `
-		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
-			chatwoot_conversation_id, chatwoot_inbox_id,
-			chatwoot_contact_inbox_source_id, source_id, direction,
-			is_read, created_at, updated_at
-		FROM chatwoot_message_links
-		WHERE device_id = ? AND wa_chat_jid = ? AND direction = 'incoming' AND is_read = 0
-		ORDER BY updated_at DESC, created_at DESC
-		LIMIT 1
-	`
-
-	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, deviceID, waChatJID))
-	if err == sql.ErrNoRows {
-		return nil, nil
+// StoreLabel creates or updates a label.
+func (r *SQLiteRepository) StoreLabel(label *domainChatStorage.Label) error {
+	if label == nil {
+		return nil
 	}
-	return link, err
-}
-
-func (r *SQLiteRepository) EnqueueChatwootForwardEvent(event *domainChatStorage.ChatwootForwardEvent) error {
-	if event == nil || strings.TrimSpace(event.DeviceID) == "" || strings.TrimSpace(event.EventName) == "" || strings.TrimSpace(event.WhatsAppMessageID) == "" || strings.TrimSpace(event.PayloadJSON) == "" {
-		return fmt.Errorf("chatwoot forward event requires device id, event name, whatsapp message id, and payload")
+	if label.ID == "" || label.DeviceID == "" {
+		return fmt.Errorf("label requires label_id and device_id")
 	}
+	label.UpdatedAt = time.Now()
 
-	now := time.Now()
-	if event.NextAttemptAt.IsZero() {
-		event.NextAttemptAt = now
-	}
-	if event.CreatedAt.IsZero() {
-		event.CreatedAt = now
+	result, err := r.db.Exec(`
+		UPDATE labels SET name = ?, color = ?, predefined_id = ?, order_index = ?, updated_at = ?
+		WHERE device_id = ? AND label_id = ?
+	`, label.Name, label.Color, label.PredefinedID, label.OrderIndex, label.UpdatedAt, label.DeviceID, label.ID)
+	if err != nil {
+		return err
 	}
-	event.UpdatedAt = now
 
-	_, err := r.db.Exec(`
-		INSERT INTO chatwoot_forward_queue (
-			device_id, event_name, wa_message_id, payload_json,
-			attempts, last_error, next_attempt_at, created_at, updated_at
-		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(device_id, event_name, wa_message_id) DO UPDATE SET
-			payload_json = excluded.payload_json,
-			last_error = excluded.last_error,
-			next_attempt_at = excluded.next_attempt_at,
-			updated_at = excluded.updated_at
-	`, event.DeviceID, event.EventName, event.WhatsAppMessageID, event.PayloadJSON,
-		event.Attempts, event.LastError, event.NextAttemptAt, event.CreatedAt, event.UpdatedAt)
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO labels (device_id, label_id, name, color, predefined_id, order_index, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, label.DeviceID, label.ID, label.Name, label.Color, label.PredefinedID, label.OrderIndex, label.UpdatedAt)
+	}
 	return err
 }
 
-func (r *SQLiteRepository) ListDueChatwootForwardEvents(now time.Time, limit int) ([]*domainChatStorage.ChatwootForwardEvent, error) {
-	if limit <= 0 {
-		limit = 20
+// DeleteLabel removes a label together with its chat and message associations.
+func (r *SQLiteRepository) DeleteLabel(deviceID, labelID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM chat_labels WHERE device_id = ? AND label_id = ?`, deviceID, labelID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM message_labels WHERE device_id = ? AND label_id = ?`, deviceID, labelID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM labels WHERE device_id = ? AND label_id = ?`, deviceID, labelID); err != nil {
+		return err
 	}
+	return tx.Commit()
+}
 
+// GetLabels returns the device's labels in WhatsApp's display order, each with
+// the number of chats carrying it.
+func (r *SQLiteRepository) GetLabels(deviceID string) ([]*domainChatStorage.Label, error) {
 	rows, err := r.db.Query(`
-		SELECT id, device_id, event_name, wa_message_id, payload_json,
-			attempts, last_error, next_attempt_at, created_at, updated_at
-		FROM chatwoot_forward_queue
-		WHERE next_attempt_at <= ?
-		ORDER BY next_attempt_at ASC, id ASC
-		LIMIT ?
-	`, now, limit)
+		SELECT l.device_id, l.label_id, l.name, l.color, l.predefined_id, l.order_index, l.updated_at,
+			(SELECT COUNT(*) FROM chat_labels cl WHERE cl.device_id = l.device_id AND cl.label_id = l.label_id)
+		FROM labels l
+		WHERE l.device_id = ?
+		ORDER BY l.order_index, l.label_id
+	`, deviceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	events := make([]*domainChatStorage.ChatwootForwardEvent, 0)
+	var labels []*domainChatStorage.Label
 	for rows.Next() {
-		event := &domainChatStorage.ChatwootForwardEvent{}
-		if err := rows.Scan(
-			&event.ID, &event.DeviceID, &event.EventName, &event.WhatsAppMessageID,
-			&event.PayloadJSON, &event.Attempts, &event.LastError, &event.NextAttemptAt,
-			&event.CreatedAt, &event.UpdatedAt,
-		); err != nil {
+		var label domainChatStorage.Label
+		if err := rows.Scan(&label.DeviceID, &label.ID, &label.Name, &label.Color, &label.PredefinedID,
+			&label.OrderIndex, &label.UpdatedAt, &label.ChatCount); err != nil {
 			return nil, err
 		}
-		events = append(events, event)
+		labels = append(labels, &label)
 	}
-	return events, rows.Err()
+	return labels, rows.Err()
 }
 
-func (r *SQLiteRepository) MarkChatwootForwardEventFailed(id int64, lastError string, nextAttemptAt time.Time) error {
-	if id == 0 {
-		return fmt.Errorf("chatwoot forward event id is required")
+// StoreDraft creates or replaces the draft of a chat. The content is sealed
+// like message content when encryption is enabled.
+func (r *SQLiteRepository) StoreDraft(draft *domainChatStorage.Draft) error {
+	if draft == nil {
+		return nil
 	}
-	_, err := r.db.Exec(`
-		UPDATE chatwoot_forward_queue
-		SET attempts = attempts + 1,
-			last_error = ?,
-			next_attempt_at = ?,
-			updated_at = ?
-		WHERE id = ?
-	`, lastError, nextAttemptAt, time.Now(), id)
-	return err
-}
-
-func (r *SQLiteRepository) MarkChatwootForwardEventDone(id int64) error {
-	if id == 0 {
-		return fmt.Errorf("chatwoot forward event id is required")
+	if draft.DeviceID == "" || draft.ChatJID == "" {
+		return fmt.Errorf("draft requires device_id and chat_jid")
 	}
-	_, err := r.db.Exec(`DELETE FROM chatwoot_forward_queue WHERE id = ?`, id)
-	return err
-}
-
-// getCount is a private helper for count queries
-func (r *SQLiteRepository) getCount(query string, args ...any) (int64, error) {
-	var count int64
-	err := r.db.QueryRow(query, args...).Scan(&count)
-	return count, err
-}
+	content, err := r.cipher.encryptString(draft.Content)
+	if err != nil {
+		return err
+	}
+	draft.UpdatedAt = time.Now()
 
-// scanMessage is a private helper for scanning message rows
-func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.Message, error) {
-	message := &domainChatStorage.Message{}
-	err := scanner.Scan(
-		&message.ID, &message.ChatJID, &message.DeviceID, &message.Sender, &message.Content,
-		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.CallMetadata, &message.Filename,
-		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.ReferralMetadata, &message.CreatedAt, &message.UpdatedAt,
-	)
-	return message, err
-}
+	result, err := r.db.Exec(`
+		UPDATE drafts SET content = ?, reply_message_id = ?, updated_at = ?
+		WHERE device_id = ? AND chat_jid = ?
+	`, content, draft.ReplyMessageID, draft.UpdatedAt, draft.DeviceID, draft.ChatJID)
+	if err != nil {
+		return err
+	}
 
-// scanChat is a private helper for scanning chat rows
-func (r *SQLiteRepository) scanChat(scanner interface{ Scan(...any) error }) (*domainChatStorage.Chat, error) {
-	chat := &domainChatStorage.Chat{}
-	err := scanner.Scan(
-		&chat.DeviceID, &chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
-		&chat.CreatedAt, &chat.UpdatedAt, &chat.Archived,
-	)
-	return chat, err
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		draft.CreatedAt = draft.UpdatedAt
+		_, err = r.db.Exec(`
+			INSERT INTO drafts (device_id, chat_jid, content, reply_message_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, draft.DeviceID, draft.ChatJID, content, draft.ReplyMessageID, draft.CreatedAt, draft.UpdatedAt)
+	}
+	return err
 }
 
-func (r *SQLiteRepository) scanChatwootMessageLink(scanner interface{ Scan(...any) error }) (*domainChatStorage.ChatwootMessageLink, error) {
-	link := &domainChatStorage.ChatwootMessageLink{}
-	err := scanner.Scan(
-		&link.DeviceID, &link.WhatsAppMessageID, &link.WhatsAppChatJID,
-		&link.ChatwootMessageID, &link.ChatwootConversationID, &link.ChatwootInboxID,
-		&link.ChatwootContactInboxSourceID, &link.SourceID, &link.Direction,
-		&link.IsRead, &link.CreatedAt, &link.UpdatedAt,
-	)
-	return link, err
+// GetDraft returns the draft of a chat, or nil when there is none.
+func (r *SQLiteRepository) GetDraft(deviceID, chatJID string) (*domainChatStorage.Draft, error) {
+	draft, err := r.scanDraft(r.db.QueryRow(`
+		SELECT device_id, chat_jid, content, reply_message_id, created_at, updated_at
+		FROM drafts
+		WHERE device_id = ? AND chat_jid = ?
+	`, deviceID, chatJID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return draft, err
 }
 
-// GetChatMessageCount returns the number of messages in a chat
-func (r *SQLiteRepository) GetChatMessageCount(chatJID string) (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ?", chatJID)
-}
+// GetDrafts returns the device's drafts, most recently updated first.
+func (r *SQLiteRepository) GetDrafts(deviceID string) ([]*domainChatStorage.Draft, error) {
+	rows, err := r.db.Query(`
+		SELECT device_id, chat_jid, content, reply_message_id, created_at, updated_at
+		FROM drafts
+		WHERE device_id = ?
+		ORDER BY updated_at DESC, chat_jid
+	`, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-// GetChatMessageCountByDevice returns the number of messages in a chat for a specific device
-func (r *SQLiteRepository) GetChatMessageCountByDevice(deviceID, chatJID string) (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ? AND device_id = ?", chatJID, deviceID)
+	var drafts []*domainChatStorage.Draft
+	for rows.Next() {
+		draft, err := r.scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts, rows.Err()
 }
 
-// GetTotalMessageCount returns the total number of messages
-func (r *SQLiteRepository) GetTotalMessageCount() (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM messages")
+// DeleteDraft removes the draft of a chat and reports whether there was one.
+func (r *SQLiteRepository) DeleteDraft(deviceID, chatJID string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM drafts WHERE device_id = ? AND chat_jid = ?`, deviceID, chatJID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
 }
 
-// GetTotalChatCount returns the total number of chats
-func (r *SQLiteRepository) GetTotalChatCount() (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM chats")
+func (r *SQLiteRepository) scanDraft(scanner interface{ Scan(...any) error }) (*domainChatStorage.Draft, error) {
+	var draft domainChatStorage.Draft
+	if err := scanner.Scan(&draft.DeviceID, &draft.ChatJID, &draft.Content, &draft.ReplyMessageID,
+		&draft.CreatedAt, &draft.UpdatedAt); err != nil {
+		return nil, err
+	}
+	content, err := r.cipher.decryptString(draft.Content)
+	if err != nil {
+		return nil, err
+	}
+	draft.Content = content
+	return &draft, nil
 }
 
-// GetFilteredChatCount returns the count of chats matching the given filter
-func (r *SQLiteRepository) GetFilteredChatCount(filter *domainChatStorage.ChatFilter) (int64, error) {
-	query := `SELECT COUNT(*) FROM chats c`
-
-	joinClause, conditions, args := r.buildChatFilterQuery(filter)
-	query += joinClause
+// StoreMessageTemplate creates or replaces a template by name, keeping the
+// creation time of the template it replaces.
+func (r *SQLiteRepository) StoreMessageTemplate(template *domainChatStorage.MessageTemplate) error {
+	if template == nil {
+		return nil
+	}
+	if template.DeviceID == "" || template.Name == "" {
+		return fmt.Errorf("message template requires device_id and name")
+	}
+	template.UpdatedAt = time.Now()
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	result, err := r.db.Exec(`
+		UPDATE message_templates SET body = ?, header_type = ?, header_url = ?, updated_at = ?
+		WHERE device_id = ? AND name = ?
+	`, template.Body, template.HeaderType, template.HeaderURL, template.UpdatedAt, template.DeviceID, template.Name)
+	if err != nil {
+		return err
 	}
 
-	return r.getCount(query, args...)
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		template.CreatedAt = template.UpdatedAt
+		_, err = r.db.Exec(`
+			INSERT INTO message_templates (device_id, name, body, header_type, header_url, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, template.DeviceID, template.Name, template.Body, template.HeaderType, template.HeaderURL,
+			template.CreatedAt, template.UpdatedAt)
+	}
+	return err
 }
 
-// TruncateAllChats deletes all chats from the database
-// Note: Due to foreign key constraints, dependent rows are cleaned up first
-func (r *SQLiteRepository) TruncateAllChats() error {
-	tx, err := r.db.Begin()
+// GetMessageTemplate returns a template, or nil when there is none by that name.
+func (r *SQLiteRepository) GetMessageTemplate(deviceID, name string) (*domainChatStorage.MessageTemplate, error) {
+	var template domainChatStorage.MessageTemplate
+	err := r.db.QueryRow(`
+		SELECT device_id, name, body, header_type, header_url, created_at, updated_at
+		FROM message_templates
+		WHERE device_id = ? AND name = ?
+	`, deviceID, name).Scan(&template.DeviceID, &template.Name, &template.Body, &template.HeaderType,
+		&template.HeaderURL, &template.CreatedAt, &template.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback()
+	return &template, nil
+}
 
-	_, err = tx.Exec("DELETE FROM message_reactions")
+// GetMessageTemplates returns the device's templates ordered by name.
+func (r *SQLiteRepository) GetMessageTemplates(deviceID string) ([]*domainChatStorage.MessageTemplate, error) {
+	rows, err := r.db.Query(`
+		SELECT device_id, name, body, header_type, header_url, created_at, updated_at
+		FROM message_templates
+		WHERE device_id = ?
+		ORDER BY name
+	`, deviceID)
 	if err != nil {
-		return fmt.Errorf("failed to delete message reactions: %w", err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	_, err = tx.Exec("DELETE FROM message_edits")
-	if err != nil {
-		return fmt.Errorf("failed to delete message edits: %w", err)
+	var templates []*domainChatStorage.MessageTemplate
+	for rows.Next() {
+		var template domainChatStorage.MessageTemplate
+		if err := rows.Scan(&template.DeviceID, &template.Name, &template.Body, &template.HeaderType,
+			&template.HeaderURL, &template.CreatedAt, &template.UpdatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &template)
 	}
+	return templates, rows.Err()
+}
 
-	_, err = tx.Exec("DELETE FROM chatwoot_message_links")
+// DeleteMessageTemplate removes a template and reports whether there was one.
+func (r *SQLiteRepository) DeleteMessageTemplate(deviceID, name string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM message_templates WHERE device_id = ? AND name = ?`, deviceID, name)
 	if err != nil {
-		return fmt.Errorf("failed to delete chatwoot message links: %w", err)
+		return false, err
 	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
 
-	_, err = tx.Exec("DELETE FROM chatwoot_forward_queue")
-	if err != nil {
-		return fmt.Errorf("failed to delete chatwoot forward queue: %w", err)
+// StoreIdempotencyKey records the result of a send, replacing an earlier
+// record of the key, which callers only do once it has expired.
+func (r *SQLiteRepository) StoreIdempotencyKey(key *domainChatStorage.IdempotencyKey) error {
+	if key == nil {
+		return nil
+	}
+	if key.DeviceID == "" || key.Key == "" {
+		return fmt.Errorf("idempotency key requires device_id and key")
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
 	}
+	_, err := r.db.Exec(`
+		INSERT INTO idempotency_keys (device_id, key, operation, message_id, response, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, key) DO UPDATE SET
+			operation = excluded.operation,
+			message_id = excluded.message_id,
+			response = excluded.response,
+			created_at = excluded.created_at
+	`, key.DeviceID, key.Key, key.Operation, key.MessageID, key.Response, key.CreatedAt)
+	return err
+}
 
-	// Delete messages after dependent rows to keep cleanup explicit.
-	_, err = tx.Exec("DELETE FROM messages")
+// GetIdempotencyKey returns the record of a key created at or after since, or
+// nil when there is none.
+func (r *SQLiteRepository) GetIdempotencyKey(deviceID, key string, since time.Time) (*domainChatStorage.IdempotencyKey, error) {
+	var record domainChatStorage.IdempotencyKey
+	err := r.db.QueryRow(`
+		SELECT device_id, key, operation, message_id, response, created_at
+		FROM idempotency_keys
+		WHERE device_id = ? AND key = ? AND created_at >= ?
+	`, deviceID, key, since).Scan(&record.DeviceID, &record.Key, &record.Operation, &record.MessageID,
+		&record.Response, &record.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete messages: %w", err)
+		return nil, err
 	}
+	return &record, nil
+}
 
-	// Delete chats
-	_, err = tx.Exec("DELETE FROM chats")
+// DeleteIdempotencyKeysBefore removes the keys of every device created before
+// the given time.
+func (r *SQLiteRepository) DeleteIdempotencyKeysBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE created_at < ?`, before)
 	if err != nil {
-		return fmt.Errorf("failed to delete chats: %w", err)
+		return 0, err
 	}
-
-	return tx.Commit()
+	return result.RowsAffected()
 }
 
-// DeleteDeviceData deletes all chats, messages, and edit history for a specific device_id.
-func (r *SQLiteRepository) DeleteDeviceData(deviceID string) error {
-	if deviceID == "" {
-		return fmt.Errorf("device id is required")
+// SetChatLabel applies (labeled) or removes a label on a chat.
+func (r *SQLiteRepository) SetChatLabel(deviceID, chatJID, labelID string, labeled bool) error {
+	if !labeled {
+		_, err := r.db.Exec(`DELETE FROM chat_labels WHERE device_id = ? AND label_id = ? AND chat_jid = ?`, deviceID, labelID, chatJID)
+		return err
 	}
+	_, err := r.db.Exec(`
+		INSERT INTO chat_labels (device_id, label_id, chat_jid, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (device_id, label_id, chat_jid) DO NOTHING
+	`, deviceID, labelID, chatJID, time.Now())
+	return err
+}
 
-	tx, err := r.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// SetMessageLabel applies (labeled) or removes a label on a message.
+func (r *SQLiteRepository) SetMessageLabel(deviceID, chatJID, messageID, labelID string, labeled bool) error {
+	if !labeled {
+		_, err := r.db.Exec(`
+			DELETE FROM message_labels WHERE device_id = ? AND label_id = ? AND chat_jid = ? AND message_id = ?
+		`, deviceID, labelID, chatJID, messageID)
+		return err
 	}
-	defer tx.Rollback()
+	_, err := r.db.Exec(`
+		INSERT INTO message_labels (device_id, label_id, chat_jid, message_id, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (device_id, label_id, chat_jid, message_id) DO NOTHING
+	`, deviceID, labelID, chatJID, messageID, time.Now())
+	return err
+}
 
-	if _, err := tx.Exec(`DELETE FROM message_reactions WHERE device_id = ?`, deviceID); err != nil {
-		return fmt.Errorf("failed to delete device reactions: %w", err)
+// GetChatLabelIDs returns the label IDs of every listed chat that has any.
+func (r *SQLiteRepository) GetChatLabelIDs(deviceID string, chatJIDs []string) (map[string][]string, error) {
+	if len(chatJIDs) == 0 {
+		return map[string][]string{}, nil
 	}
-
-	if _, err := tx.Exec(`DELETE FROM message_edits WHERE device_id = ?`, deviceID); err != nil {
-		return fmt.Errorf("failed to delete device message edits: %w", err)
+	args := []any{deviceID}
+	for _, jid := range chatJIDs {
+		args = append(args, jid)
 	}
+	return r.queryLabelIDs(`
+		SELECT chat_jid, label_id FROM chat_labels
+		WHERE device_id = ? AND chat_jid IN (`+placeholders(len(chatJIDs))+`)
+		ORDER BY label_id
+	`, args...)
+}
 
-	if _, err := tx.Exec(`DELETE FROM chatwoot_message_links WHERE device_id = ?`, deviceID); err != nil {
-		return fmt.Errorf("failed to delete device chatwoot message links: %w", err)
+// GetMessageLabelIDs returns the label IDs of every listed message of a chat that has any.
+func (r *SQLiteRepository) GetMessageLabelIDs(deviceID, chatJID string, messageIDs []string) (map[string][]string, error) {
+	if len(messageIDs) == 0 {
+		return map[string][]string{}, nil
 	}
-
-	if _, err := tx.Exec(`DELETE FROM chatwoot_forward_queue WHERE device_id = ?`, deviceID); err != nil {
-		return fmt.Errorf("failed to delete device chatwoot forward queue: %w", err)
+	args := []any{deviceID, chatJID}
+	for _, id := range messageIDs {
+		args = append(args, id)
 	}
+	return r.queryLabelIDs(`
+		SELECT message_id, label_id FROM message_labels
+		WHERE device_id = ? AND chat_jid = ? AND message_id IN (`+placeholders(len(messageIDs))+`)
+		ORDER BY label_id
+	`, args...)
+}
 
-	// Delete messages after dependent rows via direct device_id filter.
-	if _, err := tx.Exec(`DELETE FROM messages WHERE device_id = ?`, deviceID); err != nil {
-		return fmt.Errorf("failed to delete device messages: %w", err)
+// queryLabelIDs groups (key, label_id) rows by key.
+func (r *SQLiteRepository) queryLabelIDs(query string, args ...any) (map[string][]string, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	if _, err := tx.Exec("DELETE FROM chats WHERE device_id = ?", deviceID); err != nil {
-		return fmt.Errorf("failed to delete device chats: %w", err)
+	labelIDs := make(map[string][]string)
+	for rows.Next() {
+		var key, labelID string
+		if err := rows.Scan(&key, &labelID); err != nil {
+			return nil, err
+		}
+		labelIDs[key] = append(labelIDs[key], labelID)
 	}
+	return labelIDs, rows.Err()
+}
 
-	return tx.Commit()
+// placeholders returns n comma-separated "?" placeholders for an IN clause.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
 }
 
-// SaveDeviceRecord upserts a device registration for persistence across restarts.
-func (r *SQLiteRepository) SaveDeviceRecord(record *domainChatStorage.DeviceRecord) error {
-	if record == nil || strings.TrimSpace(record.DeviceID) == "" {
-		return fmt.Errorf("device record with id is required")
+// StoreJob creates or updates a job, refreshing its updated_at.
+func (r *SQLiteRepository) StoreJob(job *domainChatStorage.Job) error {
+	if job == nil {
+		return nil
+	}
+	if job.ID == "" || job.Type == "" {
+		return fmt.Errorf("job requires id and type")
 	}
 
 	now := time.Now()
-	if record.CreatedAt.IsZero() {
-		record.CreatedAt = now
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = now
 	}
-	record.UpdatedAt = now
+	job.UpdatedAt = now
 
-	// Try update first, then insert if no rows affected (cross-db compatible)
 	result, err := r.db.Exec(`
-		UPDATE devices SET display_name = ?, jid = ?, updated_at = ?
-		WHERE device_id = ?
-	`, record.DisplayName, record.JID, record.UpdatedAt, record.DeviceID)
+		UPDATE jobs SET status = ?, current_step = ?, completed = ?, total = ?, result = ?,
+			result_path = ?, error = ?, started_at = ?, finished_at = ?, updated_at = ?
+		WHERE id = ?
+	`, job.Status, job.CurrentStep, job.Completed, job.Total, job.Result,
+		job.ResultPath, job.Error, nullTime(job.StartedAt), nullTime(job.FinishedAt), job.UpdatedAt,
+		job.ID)
 	if err != nil {
 		return err
 	}
@@ -1110,128 +1818,1299 @@ func (r *SQLiteRepository) SaveDeviceRecord(record *domainChatStorage.DeviceReco
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
 		_, err = r.db.Exec(`
-			INSERT INTO devices (device_id, display_name, jid, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?)
-		`, record.DeviceID, record.DisplayName, record.JID, record.CreatedAt, record.UpdatedAt)
+			INSERT INTO jobs (
+				id, type, device_id, status, current_step, completed, total, result,
+				result_path, error, created_at, started_at, finished_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, job.ID, job.Type, job.DeviceID, job.Status, job.CurrentStep, job.Completed, job.Total, job.Result,
+			job.ResultPath, job.Error, job.CreatedAt, nullTime(job.StartedAt), nullTime(job.FinishedAt), job.UpdatedAt)
 	}
 	return err
 }
 
-// ListDeviceRecords returns all registered devices.
-func (r *SQLiteRepository) ListDeviceRecords() ([]*domainChatStorage.DeviceRecord, error) {
+// GetJob returns a job by id, or nil when it does not exist.
+func (r *SQLiteRepository) GetJob(id string) (*domainChatStorage.Job, error) {
+	jobs, err := r.queryJobs(`WHERE id = ?`, id)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return jobs[0], nil
+}
+
+// GetJobs lists jobs newest-first.
+func (r *SQLiteRepository) GetJobs(filter *domainChatStorage.JobFilter) ([]*domainChatStorage.Job, error) {
+	if filter == nil {
+		filter = &domainChatStorage.JobFilter{}
+	}
+
+	var conditions []string
+	var args []any
+	if filter.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if len(filter.DeviceIDs) > 0 {
+		conditions = append(conditions, "device_id IN (?"+strings.Repeat(", ?", len(filter.DeviceIDs)-1)+")")
+		for _, deviceID := range filter.DeviceIDs {
+			args = append(args, deviceID)
+		}
+	}
+
+	clause := ""
+	if len(conditions) > 0 {
+		clause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	clause += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		clause += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	return r.queryJobs(clause, args...)
+}
+
+// FailActiveJobs marks jobs left queued or running by a previous process as failed.
+func (r *SQLiteRepository) FailActiveJobs(reason string) (int64, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE jobs SET status = ?, error = ?, finished_at = ?, updated_at = ?
+		WHERE status IN (?, ?)
+	`, domainChatStorage.JobStatusFailed, reason, now, now,
+		domainChatStorage.JobStatusQueued, domainChatStorage.JobStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) queryJobs(clause string, args ...any) ([]*domainChatStorage.Job, error) {
 	rows, err := r.db.Query(`
-		SELECT device_id, display_name, jid, created_at, updated_at
-		FROM devices
-		ORDER BY created_at ASC
-	`)
+		SELECT id, type, device_id, status, current_step, completed, total, result,
+			result_path, error, created_at, started_at, finished_at, updated_at
+		FROM jobs `+clause, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var records []*domainChatStorage.DeviceRecord
+	var jobs []*domainChatStorage.Job
 	for rows.Next() {
-		var rec domainChatStorage.DeviceRecord
-		if err := rows.Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		job := &domainChatStorage.Job{}
+		var startedAt, finishedAt sql.NullTime
+		if err := rows.Scan(
+			&job.ID, &job.Type, &job.DeviceID, &job.Status, &job.CurrentStep, &job.Completed, &job.Total, &job.Result,
+			&job.ResultPath, &job.Error, &job.CreatedAt, &startedAt, &finishedAt, &job.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
-		records = append(records, &rec)
+		job.StartedAt = startedAt.Time
+		job.FinishedAt = finishedAt.Time
+		jobs = append(jobs, job)
 	}
+	return jobs, rows.Err()
+}
 
-	return records, rows.Err()
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
 }
 
-// GetDeviceRecord fetches a device registration by id.
-func (r *SQLiteRepository) GetDeviceRecord(deviceID string) (*domainChatStorage.DeviceRecord, error) {
-	if strings.TrimSpace(deviceID) == "" {
-		return nil, fmt.Errorf("device id is required")
+// UpsertChatwootMessageLink records the stable mapping between a WhatsApp
+// message and the Chatwoot row created for it.
+func (r *SQLiteRepository) UpsertChatwootMessageLink(link *domainChatStorage.ChatwootMessageLink) error {
+	if link == nil {
+		return fmt.Errorf("chatwoot message link is required")
+	}
+	if strings.TrimSpace(link.DeviceID) == "" {
+		return fmt.Errorf("device_id is required for chatwoot message link")
+	}
+	if strings.TrimSpace(link.WhatsAppMessageID) == "" {
+		return fmt.Errorf("wa_message_id is required for chatwoot message link")
 	}
 
-	rec := &domainChatStorage.DeviceRecord{}
-	err := r.db.QueryRow(`
-		SELECT device_id, display_name, jid, created_at, updated_at
-		FROM devices
-		WHERE device_id = ?
-		LIMIT 1
-	`, deviceID).Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.CreatedAt, &rec.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return nil, nil
+	now := time.Now()
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = now
 	}
+	link.UpdatedAt = now
+
+	result, err := r.db.Exec(`
+		UPDATE chatwoot_message_links
+		SET wa_chat_jid = ?, chatwoot_message_id = ?, chatwoot_conversation_id = ?,
+		    chatwoot_inbox_id = ?, chatwoot_contact_inbox_source_id = ?, source_id = ?,
+		    direction = ?, is_read = ?, updated_at = ?
+		WHERE device_id = ? AND wa_message_id = ?
+	`, link.WhatsAppChatJID, link.ChatwootMessageID, link.ChatwootConversationID,
+		link.ChatwootInboxID, link.ChatwootContactInboxSourceID, link.SourceID,
+		link.Direction, link.IsRead, link.UpdatedAt, link.DeviceID, link.WhatsAppMessageID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return rec, nil
-}
 
-// DeleteDeviceRecord removes a device registration entry.
-func (r *SQLiteRepository) DeleteDeviceRecord(deviceID string) error {
-	if strings.TrimSpace(deviceID) == "" {
-		return fmt.Errorf("device id is required")
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO chatwoot_message_links (
+				device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
+				chatwoot_conversation_id, chatwoot_inbox_id,
+				chatwoot_contact_inbox_source_id, source_id, direction,
+				is_read, created_at, updated_at
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, link.DeviceID, link.WhatsAppMessageID, link.WhatsAppChatJID,
+			link.ChatwootMessageID, link.ChatwootConversationID, link.ChatwootInboxID,
+			link.ChatwootContactInboxSourceID, link.SourceID, link.Direction,
+			link.IsRead, link.CreatedAt, link.UpdatedAt)
 	}
-	_, err := r.db.Exec("DELETE FROM devices WHERE device_id = ?", deviceID)
 	return err
 }
 
-// GetChatNameWithPushName determines the appropriate name for a chat with pushname support
-func (r *SQLiteRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
-	// First, check if chat already exists with a name
-	existingChat, err := r.GetChat(chatJID)
-	if err == nil && existingChat != nil && existingChat.Name != "" {
-		// If we have a pushname and the existing name is just a phone number/JID user, update it
-		if pushName != "" && (existingChat.Name == jid.ToNonAD().User || existingChat.Name == senderUser) {
-			return pushName
-		}
-		return existingChat.Name
+func (r *SQLiteRepository) GetChatwootMessageLinkByWhatsAppID(deviceID, waMessageID string) (*domainChatStorage.ChatwootMessageLink, error) {
+	query := `
+		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
+			chatwoot_conversation_id, chatwoot_inbox_id,
+			chatwoot_contact_inbox_source_id, source_id, direction,
+			is_read, created_at, updated_at
+		FROM chatwoot_message_links
+		WHERE device_id = ? AND wa_message_id = ?
+		LIMIT 1
+	`
+
+	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, deviceID, waMessageID))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
+	return link, err
+}
 
-	// Determine chat type and name
-	var name string
+func (r *SQLiteRepository) GetChatwootMessageLinkByChatwootID(deviceID string, chatwootMessageID int) (*domainChatStorage.ChatwootMessageLink, error) {
+	query := `
+		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
+			chatwoot_conversation_id, chatwoot_inbox_id,
+			chatwoot_contact_inbox_source_id, source_id, direction,
+			is_read, created_at, updated_at
+		FROM chatwoot_message_links
+		WHERE device_id = ? AND chatwoot_message_id = ?
+		LIMIT 1
+	`
 
-	switch jid.Server {
-	case "g.us":
-		// This is a group chat
-		// For now, use a generic name - this can be enhanced later with group info
-		name = fmt.Sprintf("Group %s", jid.User)
-	case "newsletter":
-		// This is a newsletter/channel
-		name = fmt.Sprintf("Newsletter %s", jid.User)
-	default:
-		// This is an individual contact
-		// Priority: pushName > senderUser > JID user
-		if pushName != "" && pushName != senderUser && pushName != jid.ToNonAD().User {
-			name = pushName
-		} else if senderUser != "" {
-			name = senderUser
-		} else {
-			name = jid.ToNonAD().User
-		}
+	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, deviceID, chatwootMessageID))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-
-	return name
+	return link, err
 }
 
-// GetChatNameWithPushNameByDevice determines the appropriate name for a chat with pushname support (device-scoped)
-func (r *SQLiteRepository) GetChatNameWithPushNameByDevice(deviceID string, jid types.JID, chatJID string, senderUser string, pushName string) string {
-	// Special handling for status@broadcast - always return "Status"
-	if chatJID == "status@broadcast" || jid.String() == "status@broadcast" {
-		return "Status"
-	}
+func (r *SQLiteRepository) GetLatestChatwootMessageLinkByConversation(conversationID int) (*domainChatStorage.ChatwootMessageLink, error) {
+	query := `
+		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
+			chatwoot_conversation_id, chatwoot_inbox_id,
+			chatwoot_contact_inbox_source_id, source_id, direction,
+			is_read, created_at, updated_at
+		FROM chatwoot_message_links
+		WHERE chatwoot_conversation_id = ?
+		ORDER BY updated_at DESC, created_at DESC
+		LIMIT 1
+	`
 
-	// First, check if chat already exists with a name (device-scoped!)
-	existingChat, err := r.GetChatByDevice(deviceID, chatJID)
-	if err == nil && existingChat != nil && existingChat.Name != "" {
-		// If we have a pushname and the existing name is just a phone number/JID user, update it
-		if pushName != "" && (existingChat.Name == jid.ToNonAD().User || existingChat.Name == senderUser) {
-			return pushName
-		}
-		return existingChat.Name
+	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, conversationID))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
+	return link, err
+}
 
-	// Determine chat type and name
-	var name string
-
-	switch jid.Server {
-	case "g.us":
+func (r *SQLiteRepository) GetLatestUnreadChatwootMessageLinkByChat(deviceID, waChatJID string) (*domainChatStorage.ChatwootMessageLink, error) {
+	query := `
+		SELECT device_id, wa_message_id, wa_chat_jid, chatwoot_message_id,
+			chatwoot_conversation_id, chatwoot_inbox_id,
+			chatwoot_contact_inbox_source_id, source_id, direction,
+			is_read, created_at, updated_at
+		FROM chatwoot_message_links
+		WHERE device_id = ? AND wa_chat_jid = ? AND direction = 'incoming' AND is_read = 0
+		ORDER BY updated_at DESC, created_at DESC
+		LIMIT 1
+	`
+
+	link, err := r.scanChatwootMessageLink(r.db.QueryRow(query, deviceID, waChatJID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return link, err
+}
+
+func (r *SQLiteRepository) EnqueueChatwootForwardEvent(event *domainChatStorage.ChatwootForwardEvent) error {
+	if event == nil || strings.TrimSpace(event.DeviceID) == "" || strings.TrimSpace(event.EventName) == "" || strings.TrimSpace(event.WhatsAppMessageID) == "" || strings.TrimSpace(event.PayloadJSON) == "" {
+		return fmt.Errorf("chatwoot forward event requires device id, event name, whatsapp message id, and payload")
+	}
+
+	now := time.Now()
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = now
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = now
+	}
+	event.UpdatedAt = now
+
+	_, err := r.db.Exec(`
+		INSERT INTO chatwoot_forward_queue (
+			device_id, event_name, wa_message_id, payload_json,
+			attempts, last_error, next_attempt_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, event_name, wa_message_id) DO UPDATE SET
+			payload_json = excluded.payload_json,
+			last_error = excluded.last_error,
+			next_attempt_at = excluded.next_attempt_at,
+			updated_at = excluded.updated_at
+	`, event.DeviceID, event.EventName, event.WhatsAppMessageID, event.PayloadJSON,
+		event.Attempts, event.LastError, event.NextAttemptAt, event.CreatedAt, event.UpdatedAt)
+	return err
+}
+
+func (r *SQLiteRepository) ListDueChatwootForwardEvents(now time.Time, limit int) ([]*domainChatStorage.ChatwootForwardEvent, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, device_id, event_name, wa_message_id, payload_json,
+			attempts, last_error, next_attempt_at, created_at, updated_at
+		FROM chatwoot_forward_queue
+		WHERE next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, id ASC
+		LIMIT ?
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domainChatStorage.ChatwootForwardEvent, 0)
+	for rows.Next() {
+		event := &domainChatStorage.ChatwootForwardEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.DeviceID, &event.EventName, &event.WhatsAppMessageID,
+			&event.PayloadJSON, &event.Attempts, &event.LastError, &event.NextAttemptAt,
+			&event.CreatedAt, &event.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r *SQLiteRepository) MarkChatwootForwardEventFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	if id == 0 {
+		return fmt.Errorf("chatwoot forward event id is required")
+	}
+	_, err := r.db.Exec(`
+		UPDATE chatwoot_forward_queue
+		SET attempts = attempts + 1,
+			last_error = ?,
+			next_attempt_at = ?,
+			updated_at = ?
+		WHERE id = ?
+	`, lastError, nextAttemptAt, time.Now(), id)
+	return err
+}
+
+func (r *SQLiteRepository) MarkChatwootForwardEventDone(id int64) error {
+	if id == 0 {
+		return fmt.Errorf("chatwoot forward event id is required")
+	}
+	_, err := r.db.Exec(`DELETE FROM chatwoot_forward_queue WHERE id = ?`, id)
+	return err
+}
+
+// outboxDispatchDelay is how long the live webhook delivery of a message has to
+// mark its outbox event done before the dispatcher delivers it instead.
+const outboxDispatchDelay = 2 * time.Minute
+
+// EnableEventsOutbox makes CreateMessage record a webhook event in
+// events_outbox in the same transaction as the message. Must be called before
+// the repository is used.
+func (r *SQLiteRepository) EnableEventsOutbox() {
+	r.eventsOutbox = true
+}
+
+// storeOutboxEventExec records message.OutboxEvent, if any. A redelivered
+// message keeps its existing event.
+func (r *SQLiteRepository) storeOutboxEventExec(execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, message *domainChatStorage.Message) error {
+	if message.OutboxEvent == "" {
+		return nil
+	}
+	_, err := execer.Exec(`
+		INSERT INTO events_outbox (device_id, chat_jid, message_id, event_name, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, message_id, event_name) DO NOTHING
+	`, message.DeviceID, message.ChatJID, message.ID, message.OutboxEvent,
+		message.CreatedAt.Add(outboxDispatchDelay), message.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record outbox event for message %s: %w", message.ID, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) ListDueOutboxEvents(now time.Time, limit int) ([]*domainChatStorage.OutboxEvent, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	r.flushPendingMessages()
+
+	rows, err := r.db.Query(`
+		SELECT id, device_id, chat_jid, message_id, event_name, attempts, last_error, next_attempt_at, created_at
+		FROM events_outbox
+		WHERE delivered_at IS NULL AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, id ASC
+		LIMIT ?
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domainChatStorage.OutboxEvent, 0)
+	for rows.Next() {
+		event := &domainChatStorage.OutboxEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.DeviceID, &event.ChatJID, &event.MessageID, &event.EventName,
+			&event.Attempts, &event.LastError, &event.NextAttemptAt, &event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// MarkOutboxEventDone records that the event of a message was delivered. A
+// message still in the write-behind buffer is simply written without it.
+func (r *SQLiteRepository) MarkOutboxEventDone(deviceID, messageID, eventName string) error {
+	if r.dropBufferedOutboxEvent(deviceID, messageID, eventName) {
+		return nil
+	}
+	_, err := r.db.Exec(`
+		UPDATE events_outbox SET delivered_at = ?
+		WHERE device_id = ? AND message_id = ? AND event_name = ? AND delivered_at IS NULL
+	`, time.Now(), deviceID, messageID, eventName)
+	return err
+}
+
+func (r *SQLiteRepository) MarkOutboxEventFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	if id == 0 {
+		return fmt.Errorf("outbox event id is required")
+	}
+	_, err := r.db.Exec(`
+		UPDATE events_outbox
+		SET attempts = attempts + 1,
+			last_error = ?,
+			next_attempt_at = ?
+		WHERE id = ?
+	`, lastError, nextAttemptAt, id)
+	return err
+}
+
+// PruneOutboxEvents deletes events delivered before deliveredBefore. Delivered
+// events are kept for a while so a redelivered message is not sent twice.
+func (r *SQLiteRepository) PruneOutboxEvents(deliveredBefore time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM events_outbox WHERE delivered_at IS NOT NULL AND delivered_at < ?`, deliveredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) EnqueueWebhookDelivery(delivery *domainChatStorage.WebhookDelivery) error {
+	if delivery == nil || strings.TrimSpace(delivery.URL) == "" || strings.TrimSpace(delivery.EventName) == "" || strings.TrimSpace(delivery.PayloadJSON) == "" {
+		return fmt.Errorf("webhook delivery requires url, event name, and payload")
+	}
+
+	now := time.Now()
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = now
+	}
+	delivery.UpdatedAt = now
+
+	result, err := r.db.Exec(`
+		INSERT INTO webhook_deliveries (
+			device_id, url, event_name, payload_json,
+			attempts, last_error, next_attempt_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, delivery.DeviceID, delivery.URL, delivery.EventName, delivery.PayloadJSON,
+		delivery.Attempts, delivery.LastError, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	delivery.ID, err = result.LastInsertId()
+	return err
+}
+
+const webhookDeliveryColumns = `id, device_id, url, event_name, payload_json,
+	attempts, last_error, next_attempt_at, dead_at, created_at, updated_at`
+
+func (r *SQLiteRepository) queryWebhookDeliveries(query string, args ...any) ([]*domainChatStorage.WebhookDelivery, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*domainChatStorage.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery := &domainChatStorage.WebhookDelivery{}
+		var deadAt sql.NullTime
+		if err := rows.Scan(
+			&delivery.ID, &delivery.DeviceID, &delivery.URL, &delivery.EventName,
+			&delivery.PayloadJSON, &delivery.Attempts, &delivery.LastError, &delivery.NextAttemptAt,
+			&deadAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if deadAt.Valid {
+			delivery.DeadAt = deadAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *SQLiteRepository) ListDueWebhookDeliveries(now time.Time, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return r.queryWebhookDeliveries(`
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE dead_at IS NULL AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, id ASC
+		LIMIT ?
+	`, now, limit)
+}
+
+func (r *SQLiteRepository) MarkWebhookDeliveryFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	if id == 0 {
+		return fmt.Errorf("webhook delivery id is required")
+	}
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+			last_error = ?,
+			next_attempt_at = ?,
+			updated_at = ?
+		WHERE id = ?
+	`, lastError, nextAttemptAt, time.Now(), id)
+	return err
+}
+
+func (r *SQLiteRepository) MarkWebhookDeliveryDead(id int64, lastError string) error {
+	if id == 0 {
+		return fmt.Errorf("webhook delivery id is required")
+	}
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+			last_error = ?,
+			dead_at = ?,
+			updated_at = ?
+		WHERE id = ?
+	`, lastError, now, now, id)
+	return err
+}
+
+func (r *SQLiteRepository) MarkWebhookDeliveryDone(id int64) error {
+	if id == 0 {
+		return fmt.Errorf("webhook delivery id is required")
+	}
+	_, err := r.db.Exec(`DELETE FROM webhook_deliveries WHERE id = ?`, id)
+	return err
+}
+
+// ListDeadWebhookDeliveries returns the dead letters of a device, most recent first.
+func (r *SQLiteRepository) ListDeadWebhookDeliveries(deviceID string, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return r.queryWebhookDeliveries(`
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE device_id = ? AND dead_at IS NOT NULL
+		ORDER BY dead_at DESC, id DESC
+		LIMIT ?
+	`, deviceID, limit)
+}
+
+func (r *SQLiteRepository) RequeueWebhookDelivery(deviceID string, id int64) (bool, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET attempts = 0,
+			dead_at = NULL,
+			next_attempt_at = ?,
+			updated_at = ?
+		WHERE id = ? AND device_id = ? AND dead_at IS NOT NULL
+	`, now, now, id, deviceID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *SQLiteRepository) RequeueDeadWebhookDeliveries(deviceID string, since time.Time) (int64, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET attempts = 0,
+			dead_at = NULL,
+			next_attempt_at = ?,
+			updated_at = ?
+		WHERE device_id = ? AND dead_at IS NOT NULL AND dead_at >= ?
+	`, now, now, deviceID, since.Local()) // dead_at is written in local time
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) EnqueueOutgoingMessage(message *domainChatStorage.OutgoingMessage) error {
+	if message == nil || message.DeviceID == "" || message.ChatJID == "" || message.MessageID == "" || len(message.Payload) == 0 {
+		return fmt.Errorf("outgoing message requires device_id, chat_jid, message_id, and payload")
+	}
+
+	payload, err := r.cipher.encryptBytes(message.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt outgoing message %s: %w", message.MessageID, err)
+	}
+	content, err := r.cipher.encryptString(message.Content)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt outgoing message %s: %w", message.MessageID, err)
+	}
+
+	now := time.Now()
+	if message.Status == "" {
+		message.Status = domainChatStorage.OutgoingMessagePending
+	}
+	if message.NextAttemptAt.IsZero() {
+		message.NextAttemptAt = now
+	}
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = now
+	}
+	message.UpdatedAt = now
+
+	result, err := r.db.Exec(`
+		INSERT INTO outgoing_messages (
+			device_id, chat_jid, message_id, payload, content, status,
+			attempts, last_error, next_attempt_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, message.DeviceID, message.ChatJID, message.MessageID, payload, content, message.Status,
+		message.Attempts, message.LastError, message.NextAttemptAt, message.CreatedAt, message.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	message.ID, err = result.LastInsertId()
+	return err
+}
+
+const outgoingMessageColumns = `id, device_id, chat_jid, message_id, payload, content, status,
+	attempts, last_error, next_attempt_at, created_at, updated_at`
+
+func (r *SQLiteRepository) queryOutgoingMessages(query string, args ...any) ([]*domainChatStorage.OutgoingMessage, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]*domainChatStorage.OutgoingMessage, 0)
+	for rows.Next() {
+		message := &domainChatStorage.OutgoingMessage{}
+		if err := rows.Scan(
+			&message.ID, &message.DeviceID, &message.ChatJID, &message.MessageID,
+			&message.Payload, &message.Content, &message.Status, &message.Attempts,
+			&message.LastError, &message.NextAttemptAt, &message.CreatedAt, &message.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if message.Payload, err = r.cipher.decryptBytes(message.Payload); err != nil {
+			return nil, fmt.Errorf("failed to decrypt outgoing message %s: %w", message.MessageID, err)
+		}
+		if message.Content, err = r.cipher.decryptString(message.Content); err != nil {
+			return nil, fmt.Errorf("failed to decrypt outgoing message %s: %w", message.MessageID, err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+func (r *SQLiteRepository) ListDueOutgoingMessages(now time.Time) ([]*domainChatStorage.OutgoingMessage, error) {
+	return r.queryOutgoingMessages(`
+		SELECT `+outgoingMessageColumns+`
+		FROM outgoing_messages
+		WHERE id IN (
+			SELECT MIN(id) FROM outgoing_messages
+			WHERE status = ?
+			GROUP BY device_id, chat_jid
+		) AND next_attempt_at <= ?
+		ORDER BY id ASC
+	`, domainChatStorage.OutgoingMessagePending, now)
+}
+
+func (r *SQLiteRepository) MarkOutgoingMessageSent(id int64) error {
+	if id == 0 {
+		return fmt.Errorf("outgoing message id is required")
+	}
+	_, err := r.db.Exec(`DELETE FROM outgoing_messages WHERE id = ?`, id)
+	return err
+}
+
+func (r *SQLiteRepository) MarkOutgoingMessageRetry(id int64, lastError string, nextAttemptAt time.Time) error {
+	if id == 0 {
+		return fmt.Errorf("outgoing message id is required")
+	}
+	_, err := r.db.Exec(`
+		UPDATE outgoing_messages
+		SET attempts = attempts + 1,
+			last_error = ?,
+			next_attempt_at = ?,
+			updated_at = ?
+		WHERE id = ?
+	`, lastError, nextAttemptAt, time.Now(), id)
+	return err
+}
+
+func (r *SQLiteRepository) MarkOutgoingMessageFailed(id int64, lastError string) error {
+	if id == 0 {
+		return fmt.Errorf("outgoing message id is required")
+	}
+	_, err := r.db.Exec(`
+		UPDATE outgoing_messages
+		SET attempts = attempts + 1,
+			status = ?,
+			last_error = ?,
+			updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.OutgoingMessageFailed, lastError, time.Now(), id)
+	return err
+}
+
+func (r *SQLiteRepository) ListOutgoingMessages(deviceID, status string, limit int) ([]*domainChatStorage.OutgoingMessage, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `SELECT ` + outgoingMessageColumns + ` FROM outgoing_messages WHERE device_id = ?`
+	args := []any{deviceID}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY id ASC LIMIT ?`
+	args = append(args, limit)
+	return r.queryOutgoingMessages(query, args...)
+}
+
+func (r *SQLiteRepository) CountOutgoingMessages() (map[string]int64, error) {
+	rows, err := r.db.Query(`SELECT status, COUNT(*) FROM outgoing_messages GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// getCount is a private helper for count queries
+func (r *SQLiteRepository) getCount(query string, args ...any) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// scanMessage is a private helper for scanning message rows
+func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.Message, error) {
+	message := &domainChatStorage.Message{}
+	err := scanner.Scan(
+		&message.ID, &message.ChatJID, &message.DeviceID, &message.Sender, &message.Content,
+		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.CallMetadata, &message.Filename,
+		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
+		&message.FileLength, &message.ReferralMetadata, &message.QuotedMessageID, &message.QuotedParticipant, &message.CreatedAt, &message.UpdatedAt,
+	)
+	if err != nil {
+		return message, err
+	}
+	return message, r.decryptMessage(message)
+}
+
+// scanChat is a private helper for scanning chat rows
+func (r *SQLiteRepository) scanChat(scanner interface{ Scan(...any) error }) (*domainChatStorage.Chat, error) {
+	chat := &domainChatStorage.Chat{}
+	var mutedUntil, deletedAt int64
+	err := scanner.Scan(
+		&chat.DeviceID, &chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
+		&chat.CreatedAt, &chat.UpdatedAt, &chat.Archived,
+		&chat.Pinned, &mutedUntil, &chat.UnreadCount, &deletedAt,
+	)
+	if mutedUntil != 0 {
+		chat.MutedUntil = time.Unix(mutedUntil, 0).UTC()
+	}
+	if deletedAt != 0 {
+		chat.DeletedAt = time.Unix(deletedAt, 0).UTC()
+	}
+	return chat, err
+}
+
+func (r *SQLiteRepository) scanChatwootMessageLink(scanner interface{ Scan(...any) error }) (*domainChatStorage.ChatwootMessageLink, error) {
+	link := &domainChatStorage.ChatwootMessageLink{}
+	err := scanner.Scan(
+		&link.DeviceID, &link.WhatsAppMessageID, &link.WhatsAppChatJID,
+		&link.ChatwootMessageID, &link.ChatwootConversationID, &link.ChatwootInboxID,
+		&link.ChatwootContactInboxSourceID, &link.SourceID, &link.Direction,
+		&link.IsRead, &link.CreatedAt, &link.UpdatedAt,
+	)
+	return link, err
+}
+
+// GetChatMessageCount returns the number of messages in a chat
+func (r *SQLiteRepository) GetChatMessageCount(chatJID string) (int64, error) {
+	r.flushPendingMessages()
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ?", chatJID)
+}
+
+// GetChatMessageCountByDevice returns the number of messages in a chat for a specific device
+func (r *SQLiteRepository) GetChatMessageCountByDevice(deviceID, chatJID string) (int64, error) {
+	r.flushPendingMessages()
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ? AND device_id = ?", chatJID, deviceID)
+}
+
+// GetTotalMessageCount returns the total number of messages
+func (r *SQLiteRepository) GetTotalMessageCount() (int64, error) {
+	r.flushPendingMessages()
+	return r.getCount("SELECT COUNT(*) FROM messages")
+}
+
+// GetTotalChatCount returns the total number of chats
+func (r *SQLiteRepository) GetTotalChatCount() (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM chats")
+}
+
+// GetTotalMessageCountByDevice returns the number of messages stored for a specific device
+func (r *SQLiteRepository) GetTotalMessageCountByDevice(deviceID string) (int64, error) {
+	r.flushPendingMessages()
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE device_id = ?", deviceID)
+}
+
+// GetTotalChatCountByDevice returns the number of chats stored for a specific device
+func (r *SQLiteRepository) GetTotalChatCountByDevice(deviceID string) (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM chats WHERE device_id = ?", deviceID)
+}
+
+// GetChatStats aggregates a chat's messages with SQL so large chats are never
+// loaded into memory. Days of the histogram are taken from the stored
+// timestamps, in the timezone each message was recorded in.
+func (r *SQLiteRepository) GetChatStats(filter *domainChatStorage.ChatStatsFilter) (*domainChatStorage.ChatStats, error) {
+	if filter == nil || filter.DeviceID == "" || filter.ChatJID == "" {
+		return nil, fmt.Errorf("device id and chat jid are required")
+	}
+	r.flushPendingMessages()
+
+	stats := &domainChatStorage.ChatStats{}
+	err := r.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN is_from_me THEN 1 ELSE 0 END), 0)
+		FROM messages
+		WHERE device_id = ? AND chat_jid = ?
+	`, filter.DeviceID, filter.ChatJID).Scan(&stats.TotalMessages, &stats.SentMessages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+	stats.ReceivedMessages = stats.TotalMessages - stats.SentMessages
+	if stats.TotalMessages == 0 {
+		return stats, nil
+	}
+
+	// The first and last timestamps are read from the rows themselves; MIN and
+	// MAX would lose the column type and come back as text.
+	for _, bound := range []struct {
+		order string
+		dest  *time.Time
+	}{{"ASC", &stats.FirstMessageTime}, {"DESC", &stats.LastMessageTime}} {
+		err := r.db.QueryRow(`
+			SELECT timestamp FROM messages
+			WHERE device_id = ? AND chat_jid = ?
+			ORDER BY timestamp `+bound.order+`
+			LIMIT 1
+		`, filter.DeviceID, filter.ChatJID).Scan(bound.dest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message time: %w", err)
+		}
+	}
+
+	err = r.queryGroupCounts(`
+		SELECT COALESCE(NULLIF(media_type, ''), 'text'), COUNT(*)
+		FROM messages
+		WHERE device_id = ? AND chat_jid = ?
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+	`, []any{filter.DeviceID, filter.ChatJID}, func(key string, count int) {
+		stats.MediaTypes = append(stats.MediaTypes, domainChatStorage.MediaTypeCount{MediaType: key, Count: count})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count media types: %w", err)
+	}
+
+	if filter.TopSenders > 0 {
+		err = r.queryGroupCounts(`
+			SELECT sender, COUNT(*)
+			FROM messages
+			WHERE device_id = ? AND chat_jid = ? AND sender != ''
+			GROUP BY sender
+			ORDER BY 2 DESC, 1
+			LIMIT ?
+		`, []any{filter.DeviceID, filter.ChatJID, filter.TopSenders}, func(key string, count int) {
+			stats.TopSenders = append(stats.TopSenders, domainChatStorage.SenderCount{Sender: key, Count: count})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count senders: %w", err)
+		}
+	}
+
+	query := `
+		SELECT substr(timestamp, 1, 10), COUNT(*)
+		FROM messages
+		WHERE device_id = ? AND chat_jid = ?`
+	args := []any{filter.DeviceID, filter.ChatJID}
+	if !filter.Since.IsZero() {
+		query += " AND timestamp >= ?"
+		args = append(args, filter.Since)
+	}
+	query += " GROUP BY 1 ORDER BY 1"
+	err = r.queryGroupCounts(query, args, func(key string, count int) {
+		stats.Daily = append(stats.Daily, domainChatStorage.DailyCount{Date: key, Count: count})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages per day: %w", err)
+	}
+	return stats, nil
+}
+
+// queryGroupCounts runs a query returning (key, count) rows and passes each row to fn.
+func (r *SQLiteRepository) queryGroupCounts(query string, args []any, fn func(key string, count int)) error {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+		fn(key, count)
+	}
+	return rows.Err()
+}
+
+// GetFilteredChatCount returns the count of chats matching the given filter
+func (r *SQLiteRepository) GetFilteredChatCount(filter *domainChatStorage.ChatFilter) (int64, error) {
+	if filter.DeviceID == "" {
+		return 0, fmt.Errorf("device_id is required for chat queries (data isolation)")
+	}
+
+	query := `SELECT COUNT(*) FROM chats c`
+
+	joinClause, conditions, args := r.buildChatFilterQuery(filter)
+	query += joinClause
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return r.getCount(query, args...)
+}
+
+// TruncateAllChats deletes all chats from the database
+// Note: Due to foreign key constraints, dependent rows are cleaned up first
+func (r *SQLiteRepository) TruncateAllChats() error {
+	r.flushPendingMessages()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM message_reactions")
+	if err != nil {
+		return fmt.Errorf("failed to delete message reactions: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM message_pins")
+	if err != nil {
+		return fmt.Errorf("failed to delete message pins: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM message_edits")
+	if err != nil {
+		return fmt.Errorf("failed to delete message edits: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM poll_votes")
+	if err != nil {
+		return fmt.Errorf("failed to delete poll votes: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM polls")
+	if err != nil {
+		return fmt.Errorf("failed to delete polls: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM chatwoot_message_links")
+	if err != nil {
+		return fmt.Errorf("failed to delete chatwoot message links: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM chatwoot_forward_queue")
+	if err != nil {
+		return fmt.Errorf("failed to delete chatwoot forward queue: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM events_outbox")
+	if err != nil {
+		return fmt.Errorf("failed to delete events outbox: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM webhook_deliveries")
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM group_snapshots")
+	if err != nil {
+		return fmt.Errorf("failed to delete group snapshots: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM message_labels")
+	if err != nil {
+		return fmt.Errorf("failed to delete message labels: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM chat_labels")
+	if err != nil {
+		return fmt.Errorf("failed to delete chat labels: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM labels")
+	if err != nil {
+		return fmt.Errorf("failed to delete labels: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM drafts")
+	if err != nil {
+		return fmt.Errorf("failed to delete drafts: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM message_templates")
+	if err != nil {
+		return fmt.Errorf("failed to delete message templates: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM idempotency_keys")
+	if err != nil {
+		return fmt.Errorf("failed to delete idempotency keys: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM outgoing_messages")
+	if err != nil {
+		return fmt.Errorf("failed to delete outgoing messages: %w", err)
+	}
+
+	// Delete messages after dependent rows to keep cleanup explicit.
+	_, err = tx.Exec("DELETE FROM messages")
+	if err != nil {
+		return fmt.Errorf("failed to delete messages: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM media")
+	if err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+
+	// Delete chats
+	_, err = tx.Exec("DELETE FROM chats")
+	if err != nil {
+		return fmt.Errorf("failed to delete chats: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteDeviceData deletes all chats, messages, and edit history for a specific device_id.
+func (r *SQLiteRepository) DeleteDeviceData(deviceID string) error {
+	r.flushPendingMessages()
+	if deviceID == "" {
+		return fmt.Errorf("device id is required")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM message_reactions WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device reactions: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM message_pins WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device message pins: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM message_edits WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device message edits: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM poll_votes WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device poll votes: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM polls WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device polls: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM chatwoot_message_links WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device chatwoot message links: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM chatwoot_forward_queue WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device chatwoot forward queue: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM events_outbox WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device events outbox: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device webhook deliveries: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM group_snapshots WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device group snapshots: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM message_labels WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device message labels: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM chat_labels WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device chat labels: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM labels WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device labels: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM drafts WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device drafts: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM message_templates WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device message templates: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM idempotency_keys WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device idempotency keys: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM outgoing_messages WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device outgoing messages: %w", err)
+	}
+
+	// Delete messages after dependent rows via direct device_id filter.
+	if _, err := tx.Exec(`DELETE FROM messages WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device messages: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM media WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device media: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM chats WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device chats: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// SaveDeviceRecord upserts a device registration for persistence across restarts.
+func (r *SQLiteRepository) SaveDeviceRecord(record *domainChatStorage.DeviceRecord) error {
+	if record == nil || strings.TrimSpace(record.DeviceID) == "" {
+		return fmt.Errorf("device record with id is required")
+	}
+
+	now := time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	record.UpdatedAt = now
+
+	// Try update first, then insert if no rows affected (cross-db compatible)
+	result, err := r.db.Exec(`
+		UPDATE devices SET display_name = ?, jid = ?, updated_at = ?
+		WHERE device_id = ?
+	`, record.DisplayName, record.JID, record.UpdatedAt, record.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO devices (device_id, display_name, jid, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, record.DeviceID, record.DisplayName, record.JID, record.CreatedAt, record.UpdatedAt)
+	}
+	return err
+}
+
+// ListDeviceRecords returns all registered devices.
+func (r *SQLiteRepository) ListDeviceRecords() ([]*domainChatStorage.DeviceRecord, error) {
+	rows, err := r.db.Query(`
+		SELECT device_id, display_name, jid, created_at, updated_at
+		FROM devices
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*domainChatStorage.DeviceRecord
+	for rows.Next() {
+		var rec domainChatStorage.DeviceRecord
+		if err := rows.Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, &rec)
+	}
+
+	return records, rows.Err()
+}
+
+// GetDeviceRecord fetches a device registration by id.
+func (r *SQLiteRepository) GetDeviceRecord(deviceID string) (*domainChatStorage.DeviceRecord, error) {
+	if strings.TrimSpace(deviceID) == "" {
+		return nil, fmt.Errorf("device id is required")
+	}
+
+	rec := &domainChatStorage.DeviceRecord{}
+	err := r.db.QueryRow(`
+		SELECT device_id, display_name, jid, created_at, updated_at
+		FROM devices
+		WHERE device_id = ?
+		LIMIT 1
+	`, deviceID).Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.CreatedAt, &rec.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// DeleteDeviceRecord removes a device registration entry.
+func (r *SQLiteRepository) DeleteDeviceRecord(deviceID string) error {
+	if strings.TrimSpace(deviceID) == "" {
+		return fmt.Errorf("device id is required")
+	}
+	_, err := r.db.Exec("DELETE FROM devices WHERE device_id = ?", deviceID)
+	return err
+}
+
+// GetChatNameWithPushName determines the appropriate name for a chat with pushname support
+func (r *SQLiteRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
+	// First, check if chat already exists with a name
+	existingChat, err := r.GetChat(chatJID)
+	if err == nil && existingChat != nil && existingChat.Name != "" {
+		// If we have a pushname and the existing name is just a phone number/JID user, update it
+		if pushName != "" && (existingChat.Name == jid.ToNonAD().User || existingChat.Name == senderUser) {
+			return pushName
+		}
+		return existingChat.Name
+	}
+
+	// Determine chat type and name
+	var name string
+
+	switch jid.Server {
+	case "g.us":
+		// This is a group chat
+		// For now, use a generic name - this can be enhanced later with group info
+		name = fmt.Sprintf("Group %s", jid.User)
+	case "newsletter":
+		// This is a newsletter/channel
+		name = fmt.Sprintf("Newsletter %s", jid.User)
+	default:
+		// This is an individual contact
+		// Priority: pushName > senderUser > JID user
+		if pushName != "" && pushName != senderUser && pushName != jid.ToNonAD().User {
+			name = pushName
+		} else if senderUser != "" {
+			name = senderUser
+		} else {
+			name = jid.ToNonAD().User
+		}
+	}
+
+	return name
+}
+
+// GetChatNameWithPushNameByDevice determines the appropriate name for a chat with pushname support (device-scoped)
+func (r *SQLiteRepository) GetChatNameWithPushNameByDevice(deviceID string, jid types.JID, chatJID string, senderUser string, pushName string) string {
+	// Special handling for status@broadcast - always return "Status"
+	if chatJID == "status@broadcast" || jid.String() == "status@broadcast" {
+		return "Status"
+	}
+
+	// First, check if chat already exists with a name (device-scoped!)
+	existingChat, err := r.GetChatByDevice(deviceID, chatJID)
+	if err == nil && existingChat != nil && existingChat.Name != "" {
+		// If we have a pushname and the existing name is just a phone number/JID user, update it
+		if pushName != "" && (existingChat.Name == jid.ToNonAD().User || existingChat.Name == senderUser) {
+			return pushName
+		}
+		return existingChat.Name
+	}
+
+	// Determine chat type and name
+	var name string
+
+	switch jid.Server {
+	case "g.us":
 		// This is a group chat
 		// For now, use a generic name - this can be enhanced later with group info
 		name = fmt.Sprintf("Group %s", jid.User)
@@ -1250,15 +3129,394 @@ func (r *SQLiteRepository) GetChatNameWithPushNameByDevice(deviceID string, jid
 		}
 	}
 
-	return name
+	return name
+}
+
+func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Message) error {
+	if evt == nil || evt.Message == nil {
+		return nil
+	}
+
+	// Get WhatsApp client for LID resolution (device-scoped if present in context)
+	client := whatsapp.ClientFromContext(ctx)
+	deviceID := ""
+	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
+		deviceID = inst.JID()
+		if deviceID == "" {
+			deviceID = inst.ID()
+		}
+	}
+	if deviceID == "" && client != nil && client.Store != nil && client.Store.ID != nil {
+		deviceID = client.Store.ID.ToNonAD().String()
+	}
+
+	// Normalize chat and sender JIDs (convert @lid to @s.whatsapp.net)
+	normalizedChatJID := utils.ResolveLIDToPhone(ctx, evt.Info.Chat, client)
+	normalizedSender := utils.ResolveLIDToPhone(ctx, evt.Info.Sender, client)
+
+	chatJID := normalizedChatJID.String()
+	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
+	sender := normalizedSender.ToNonAD().String()
+
+	// Get appropriate chat name using pushname if available (device-scoped)
+	chatName := r.GetChatNameWithPushNameByDevice(deviceID, normalizedChatJID, chatJID, normalizedSender.User, evt.Info.PushName)
+
+	// Get existing chat to preserve ephemeral_expiration and archived status if needed (device-scoped)
+	existingChat, err := r.GetChatByDevice(deviceID, chatJID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing chat: %w", err)
+	}
+
+	if editMessage := extractEditedMessage(evt.Message); editMessage != nil {
+		if existingChat == nil {
+			chat := &domainChatStorage.Chat{
+				DeviceID:        deviceID,
+				JID:             chatJID,
+				Name:            chatName,
+				LastMessageTime: evt.Info.Timestamp,
+			}
+			if err := r.StoreChat(chat); err != nil {
+				return fmt.Errorf("failed to store chat for edited message: %w", err)
+			}
+		}
+
+		if err := r.storeEditedMessage(ctx, evt, deviceID, chatJID, sender, editMessage); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// Extract ephemeral expiration from incoming message
+	ephemeralExpiration := utils.ExtractEphemeralExpiration(evt.Message)
+
+	// Create or update chat
+	chat := &domainChatStorage.Chat{
+		DeviceID:        deviceID,
+		JID:             chatJID,
+		Name:            chatName,
+		LastMessageTime: evt.Info.Timestamp,
+	}
+
+	// Set ephemeral expiration: use incoming message value if > 0, otherwise preserve existing
+	if ephemeralExpiration > 0 {
+		chat.EphemeralExpiration = ephemeralExpiration
+	} else if existingChat != nil {
+		// Preserve existing ephemeral_expiration if incoming message doesn't have one
+		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+	}
+
+	// Preserve existing archived state
+	if existingChat != nil {
+		chat.Archived = existingChat.Archived
+	}
+
+	// Store or update the chat
+	if err := r.StoreChat(chat); err != nil {
+		return fmt.Errorf("failed to store chat: %w", err)
+	}
+
+	// Extract message content and media info
+	content := utils.ExtractMessageTextFromProto(evt.Message)
+	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(evt.Message)
+
+	// Skip if there's no content and no media
+	if content == "" && mediaType == "" {
+		logrus.Debugf("Skipping message %s - no content or media", evt.Info.ID)
+		return nil
+	}
+
+	var referralMetadata string
+	if referral := utils.ExtractExternalAdReply(evt.Message); referral != nil {
+		if jsonBytes, err := json.Marshal(referral); err == nil {
+			referralMetadata = string(jsonBytes)
+		}
+	}
+
+	quotedMessageID, quotedParticipant := utils.ExtractQuotedReference(evt.Message)
+
+	message := &domainChatStorage.Message{
+		ID:                evt.Info.ID,
+		ChatJID:           chatJID,
+		DeviceID:          deviceID,
+		Sender:            sender,
+		Content:           content,
+		Timestamp:         evt.Info.Timestamp,
+		IsFromMe:          evt.Info.IsFromMe,
+		MediaType:         mediaType,
+		Filename:          filename,
+		URL:               url,
+		MediaKey:          mediaKey,
+		FileSHA256:        fileSHA256,
+		FileEncSHA256:     fileEncSHA256,
+		FileLength:        fileLength,
+		ReferralMetadata:  referralMetadata,
+		QuotedMessageID:   quotedMessageID,
+		QuotedParticipant: normalizeQuotedParticipant(ctx, quotedParticipant, client),
+	}
+
+	if normalizedChatJID.Server != types.BroadcastServer {
+		if !message.IsFromMe {
+			if err := r.incrementUnreadCount(message); err != nil {
+				return fmt.Errorf("failed to update unread count: %w", err)
+			}
+		}
+		if r.eventsOutbox {
+			message.OutboxEvent = "message"
+		}
+	}
+
+	// Store the message
+	if err := r.StoreMessage(message); err != nil {
+		return err
+	}
+	return r.storePollFromMessage(message, evt.Message)
+}
+
+// incrementUnreadCount counts an incoming message as unread on its chat. It
+// runs before the message is stored so a redelivered message is not counted
+// twice.
+func (r *SQLiteRepository) incrementUnreadCount(message *domainChatStorage.Message) error {
+	r.flushPendingMessages()
+	_, err := r.db.Exec(`
+		UPDATE chats SET unread_count = unread_count + 1
+		WHERE jid = ? AND device_id = ?
+			AND NOT EXISTS (SELECT 1 FROM messages WHERE id = ? AND chat_jid = ? AND device_id = ?)
+	`, message.ChatJID, message.DeviceID, message.ID, message.ChatJID, message.DeviceID)
+	return err
+}
+
+// normalizeQuotedParticipant stores the quoted sender in the same form as
+// messages.sender (phone JID, no device part) so replies can be matched to it.
+func normalizeQuotedParticipant(ctx context.Context, participant string, client *whatsmeow.Client) string {
+	if participant == "" {
+		return ""
+	}
+	jid, err := types.ParseJID(participant)
+	if err != nil {
+		return participant
+	}
+	return utils.ResolveLIDToPhone(ctx, jid, client).ToNonAD().String()
+}
+
+func extractEditedMessage(msg *waE2E.Message) *waE2E.Message {
+	if msg == nil {
+		return nil
+	}
+	protocolMessage := msg.GetProtocolMessage()
+	if protocolMessage == nil || protocolMessage.GetType() != waE2E.ProtocolMessage_MESSAGE_EDIT {
+		return nil
+	}
+	return protocolMessage.GetEditedMessage()
+}
+
+func (r *SQLiteRepository) storeEditedMessage(ctx context.Context, evt *events.Message, deviceID, chatJID, sender string, editedMessage *waE2E.Message) error {
+	r.flushPendingMessages()
+	if evt == nil || editedMessage == nil {
+		return nil
+	}
+
+	protocolMessage := evt.Message.GetProtocolMessage()
+	if protocolMessage == nil {
+		return nil
+	}
+	key := protocolMessage.GetKey()
+	if key == nil || key.GetID() == "" {
+		return nil
+	}
+
+	originalMessageID := key.GetID()
+	newContent := utils.ExtractMessageTextFromProto(editedMessage)
+	now := time.Now()
+
+	existingMessage, err := r.getMessageByDeviceAndChatIDAndMessageID(deviceID, chatJID, originalMessageID)
+	if err != nil {
+		return fmt.Errorf("failed to load original message %s: %w", originalMessageID, err)
+	}
+
+	messageExists := existingMessage != nil
+	currentMessage := existingMessage
+	previousContent := ""
+
+	if currentMessage == nil {
+		currentMessage = &domainChatStorage.Message{
+			ID:        originalMessageID,
+			ChatJID:   chatJID,
+			DeviceID:  deviceID,
+			Sender:    sender,
+			Content:   newContent,
+			Timestamp: evt.Info.Timestamp,
+			IsFromMe:  evt.Info.IsFromMe,
+		}
+
+		if mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(editedMessage); mediaType != "" || newContent != "" {
+			currentMessage.MediaType = mediaType
+			currentMessage.Filename = filename
+			currentMessage.URL = url
+			currentMessage.MediaKey = mediaKey
+			currentMessage.FileSHA256 = fileSHA256
+			currentMessage.FileEncSHA256 = fileEncSHA256
+			currentMessage.FileLength = fileLength
+		}
+	} else {
+		previousContent = currentMessage.Content
+	}
+
+	edit := &domainChatStorage.MessageEdit{
+		OriginalMessageID: originalMessageID,
+		EditEventID:       evt.Info.ID,
+		ChatJID:           chatJID,
+		DeviceID:          deviceID,
+		Editor:            sender,
+		PreviousContent:   previousContent,
+		NewContent:        newContent,
+		EditedAt:          evt.Info.Timestamp,
+		CreatedAt:         now,
+	}
+
+	var sealedContent string
+	var sealedMediaKey []byte
+	if messageExists {
+		sealedContent, err = r.cipher.encryptString(newContent)
+	} else {
+		sealedContent, sealedMediaKey, err = r.sealMessage(currentMessage)
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin edit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if messageExists {
+		if _, err := tx.Exec(`
+			UPDATE messages SET content = ?, updated_at = ?
+			WHERE id = ? AND chat_jid = ? AND device_id = ?
+		`, sealedContent, now, originalMessageID, chatJID, deviceID); err != nil {
+			return fmt.Errorf("failed to update original message %s: %w", originalMessageID, err)
+		}
+	} else {
+		if err := r.storeMediaExec(tx, currentMessage, sealedMediaKey); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO messages (
+				id, chat_jid, device_id, sender, content, timestamp, is_from_me,
+				media_type, call_metadata, filename, file_sha256, media_sha256,
+				referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, currentMessage.ID, currentMessage.ChatJID, currentMessage.DeviceID, currentMessage.Sender, sealedContent,
+			currentMessage.Timestamp, currentMessage.IsFromMe, currentMessage.MediaType, currentMessage.CallMetadata, currentMessage.Filename,
+			currentMessage.FileSHA256, mediaRef(currentMessage), currentMessage.ReferralMetadata, currentMessage.QuotedMessageID, currentMessage.QuotedParticipant,
+			now, now); err != nil {
+			return fmt.Errorf("failed to insert edited message %s: %w", originalMessageID, err)
+		}
+	}
+
+	if err := r.storeMessageEditExec(tx, edit); err != nil {
+		return fmt.Errorf("failed to store edit history for %s: %w", originalMessageID, err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteRepository) getMessageByDeviceAndChatIDAndMessageID(deviceID, chatJID, messageID string) (*domainChatStorage.Message, error) {
+	r.flushPendingMessages()
+	query := `
+		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
+			media_type, call_metadata, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		FROM message_rows
+		WHERE id = ? AND chat_jid = ? AND device_id = ?
+		LIMIT 1
+	`
+	message, err := r.scanMessage(r.db.QueryRow(query, messageID, chatJID, deviceID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return message, err
+}
+
+func (r *SQLiteRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	r.flushPendingMessages()
+	return r.storeMessageEditExec(r.db, edit)
+}
+
+func (r *SQLiteRepository) storeMessageEditExec(execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, edit *domainChatStorage.MessageEdit) error {
+	if edit == nil {
+		return nil
+	}
+	if strings.TrimSpace(edit.OriginalMessageID) == "" || strings.TrimSpace(edit.EditEventID) == "" || strings.TrimSpace(edit.ChatJID) == "" || strings.TrimSpace(edit.DeviceID) == "" {
+		return fmt.Errorf("edit history requires original_message_id, edit_event_id, chat_jid, and device_id")
+	}
+
+	now := time.Now()
+	if edit.CreatedAt.IsZero() {
+		edit.CreatedAt = now
+	}
+	if edit.EditedAt.IsZero() {
+		edit.EditedAt = edit.CreatedAt
+	}
+
+	previousContent, err := r.cipher.encryptString(edit.PreviousContent)
+	if err != nil {
+		return err
+	}
+	newContent, err := r.cipher.encryptString(edit.NewContent)
+	if err != nil {
+		return err
+	}
+
+	_, err = execer.Exec(`
+		INSERT INTO message_edits (
+			original_message_id, edit_event_id, chat_jid, device_id, editor,
+			previous_content, new_content, edited_at, created_at
+		)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM message_edits
+			WHERE original_message_id = ? AND edit_event_id = ? AND device_id = ?
+		)
+	`, edit.OriginalMessageID, edit.EditEventID, edit.ChatJID, edit.DeviceID, edit.Editor,
+		previousContent, newContent, edit.EditedAt, edit.CreatedAt,
+		edit.OriginalMessageID, edit.EditEventID, edit.DeviceID)
+	return err
 }
 
-func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Message) error {
-	if evt == nil || evt.Message == nil {
+// CreateReaction stores or removes a reaction event as its own row in message_reactions.
+func (r *SQLiteRepository) CreateReaction(ctx context.Context, evt *events.Message) error {
+	reaction, err := r.reactionFromEvent(ctx, evt)
+	if err != nil {
+		return err
+	}
+	if reaction == nil {
 		return nil
 	}
+	return r.StoreReaction(reaction)
+}
+
+func (r *SQLiteRepository) reactionFromEvent(ctx context.Context, evt *events.Message) (*domainChatStorage.Reaction, error) {
+	if evt == nil || evt.Message == nil {
+		return nil, nil
+	}
+
+	msg := utils.UnwrapMessage(evt.Message)
+	reactionMessage := msg.GetReactionMessage()
+	if reactionMessage == nil {
+		return nil, nil
+	}
+
+	key := reactionMessage.GetKey()
+	if key == nil || key.GetID() == "" {
+		logrus.Debugf("Skipping reaction event %s - missing reacted message id", evt.Info.ID)
+		return nil, nil
+	}
 
-	// Get WhatsApp client for LID resolution (device-scoped if present in context)
 	client := whatsapp.ClientFromContext(ctx)
 	deviceID := ""
 	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
@@ -1270,302 +3528,407 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 	if deviceID == "" && client != nil && client.Store != nil && client.Store.ID != nil {
 		deviceID = client.Store.ID.ToNonAD().String()
 	}
+	if deviceID == "" {
+		return nil, domainChatStorage.ErrMissingDeviceContext
+	}
 
-	// Normalize chat and sender JIDs (convert @lid to @s.whatsapp.net)
-	normalizedChatJID := utils.ResolveLIDToPhone(ctx, evt.Info.Chat, client)
-	normalizedSender := utils.ResolveLIDToPhone(ctx, evt.Info.Sender, client)
+	chatJID := evt.Info.Chat
+	if chatJID.IsEmpty() && key.GetRemoteJID() != "" {
+		parsed, err := types.ParseJID(key.GetRemoteJID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reaction chat jid %q: %w", key.GetRemoteJID(), err)
+		}
+		chatJID = parsed
+	}
+	if chatJID.IsEmpty() {
+		return nil, fmt.Errorf("reaction chat jid is required")
+	}
+	normalizedChatJID := whatsapp.NormalizeJIDFromLIDWithContext(chatJID, client)
 
-	chatJID := normalizedChatJID.String()
-	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
-	sender := normalizedSender.ToNonAD().String()
+	reactorJID := evt.Info.Sender
+	if reactorJID.IsEmpty() && evt.Info.IsFromMe && client != nil && client.Store != nil && client.Store.ID != nil {
+		reactorJID = client.Store.ID.ToNonAD()
+	}
+	if reactorJID.IsEmpty() {
+		return nil, fmt.Errorf("reaction sender jid is required")
+	}
+	normalizedReactorJID := whatsapp.NormalizeJIDFromLIDWithContext(reactorJID, client)
 
-	// Get appropriate chat name using pushname if available (device-scoped)
-	chatName := r.GetChatNameWithPushNameByDevice(deviceID, normalizedChatJID, chatJID, normalizedSender.User, evt.Info.PushName)
+	return &domainChatStorage.Reaction{
+		MessageID:  key.GetID(),
+		ChatJID:    normalizedChatJID.String(),
+		DeviceID:   deviceID,
+		ReactorJID: normalizedReactorJID.ToNonAD().String(),
+		Emoji:      reactionMessage.GetText(),
+		IsFromMe:   evt.Info.IsFromMe,
+		Timestamp:  evt.Info.Timestamp,
+	}, nil
+}
 
-	// Get existing chat to preserve ephemeral_expiration and archived status if needed (device-scoped)
-	existingChat, err := r.GetChatByDevice(deviceID, chatJID)
-	if err != nil {
-		return fmt.Errorf("failed to get existing chat: %w", err)
+// StorePoll creates or updates the question and options of a poll.
+func (r *SQLiteRepository) StorePoll(poll *domainChatStorage.Poll) error {
+	if poll == nil {
+		return nil
+	}
+	if poll.MessageID == "" || poll.ChatJID == "" || poll.DeviceID == "" {
+		return fmt.Errorf("poll requires message_id, chat_jid, and device_id")
 	}
 
-	if editMessage := extractEditedMessage(evt.Message); editMessage != nil {
-		if existingChat == nil {
-			chat := &domainChatStorage.Chat{
-				DeviceID:        deviceID,
-				JID:             chatJID,
-				Name:            chatName,
-				LastMessageTime: evt.Info.Timestamp,
-			}
-			if err := r.StoreChat(chat); err != nil {
-				return fmt.Errorf("failed to store chat for edited message: %w", err)
-			}
-		}
+	options, err := json.Marshal(poll.Options)
+	if err != nil {
+		return fmt.Errorf("failed to encode poll options: %w", err)
+	}
+	if poll.CreatedAt.IsZero() {
+		poll.CreatedAt = time.Now()
+	}
 
-		if err := r.storeEditedMessage(ctx, evt, deviceID, chatJID, sender, editMessage); err != nil {
-			return err
-		}
-		return nil
+	result, err := r.db.Exec(`
+		UPDATE polls
+		SET chat_jid = ?, creator_jid = ?, question = ?, options = ?, selectable_count = ?
+		WHERE message_id = ? AND device_id = ?
+	`, poll.ChatJID, poll.CreatorJID, poll.Question, string(options), poll.SelectableCount,
+		poll.MessageID, poll.DeviceID)
+	if err != nil {
+		return err
 	}
 
-	// Extract ephemeral expiration from incoming message
-	ephemeralExpiration := utils.ExtractEphemeralExpiration(evt.Message)
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO polls (
+				message_id, chat_jid, device_id, creator_jid, question, options,
+				selectable_count, created_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, poll.MessageID, poll.ChatJID, poll.DeviceID, poll.CreatorJID, poll.Question,
+			string(options), poll.SelectableCount, poll.CreatedAt)
+	}
+	return err
+}
 
-	// Create or update chat
-	chat := &domainChatStorage.Chat{
-		DeviceID:        deviceID,
-		JID:             chatJID,
-		Name:            chatName,
-		LastMessageTime: evt.Info.Timestamp,
+// storePollFromMessage records the poll carried by a stored message, if any.
+func (r *SQLiteRepository) storePollFromMessage(message *domainChatStorage.Message, msg *waE2E.Message) error {
+	poll := utils.ExtractPollCreation(msg)
+	if poll == nil {
+		return nil
 	}
+	return r.StorePoll(&domainChatStorage.Poll{
+		MessageID:       message.ID,
+		ChatJID:         message.ChatJID,
+		DeviceID:        message.DeviceID,
+		CreatorJID:      message.Sender,
+		Question:        poll.GetName(),
+		Options:         utils.PollOptionNames(poll),
+		SelectableCount: poll.GetSelectableOptionsCount(),
+		CreatedAt:       message.Timestamp,
+	})
+}
 
-	// Set ephemeral expiration: use incoming message value if > 0, otherwise preserve existing
-	if ephemeralExpiration > 0 {
-		chat.EphemeralExpiration = ephemeralExpiration
-	} else if existingChat != nil {
-		// Preserve existing ephemeral_expiration if incoming message doesn't have one
-		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+// GetPoll returns a stored poll, or nil when the poll was never seen by this device.
+func (r *SQLiteRepository) GetPoll(deviceID, messageID string) (*domainChatStorage.Poll, error) {
+	polls, err := r.queryPolls(`device_id = ? AND message_id = ?`, deviceID, messageID)
+	if err != nil || len(polls) == 0 {
+		return nil, err
 	}
+	return polls[0], nil
+}
 
-	// Preserve existing archived state
-	if existingChat != nil {
-		chat.Archived = existingChat.Archived
+// GetPollResults returns the poll with its current vote tally, or nil when the
+// poll is not stored for the device.
+func (r *SQLiteRepository) GetPollResults(deviceID, messageID string) (*domainChatStorage.PollResults, error) {
+	if deviceID == "" {
+		return nil, fmt.Errorf("device_id is required for poll queries (data isolation)")
 	}
 
-	// Store or update the chat
-	if err := r.StoreChat(chat); err != nil {
-		return fmt.Errorf("failed to store chat: %w", err)
+	poll, err := r.GetPoll(deviceID, messageID)
+	if err != nil || poll == nil {
+		return nil, err
 	}
 
-	// Extract message content and media info
-	content := utils.ExtractMessageTextFromProto(evt.Message)
-	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(evt.Message)
+	votes, err := r.getPollVotes(deviceID, []string{poll.MessageID})
+	if err != nil {
+		return nil, err
+	}
+	return tallyPollVotes(poll, votes[poll.MessageID]), nil
+}
 
-	// Skip if there's no content and no media
-	if content == "" && mediaType == "" {
-		logrus.Debugf("Skipping message %s - no content or media", evt.Info.ID)
-		return nil
+func (r *SQLiteRepository) queryPolls(where string, args ...any) ([]*domainChatStorage.Poll, error) {
+	rows, err := r.db.Query(`
+		SELECT message_id, chat_jid, device_id, creator_jid, question, options, selectable_count, created_at
+		FROM polls
+		WHERE `+where, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	var referralMetadata string
-	if referral := utils.ExtractExternalAdReply(evt.Message); referral != nil {
-		if jsonBytes, err := json.Marshal(referral); err == nil {
-			referralMetadata = string(jsonBytes)
+	var polls []*domainChatStorage.Poll
+	for rows.Next() {
+		var poll domainChatStorage.Poll
+		var options string
+		if err := rows.Scan(
+			&poll.MessageID, &poll.ChatJID, &poll.DeviceID, &poll.CreatorJID, &poll.Question,
+			&options, &poll.SelectableCount, &poll.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(options), &poll.Options); err != nil {
+			return nil, fmt.Errorf("failed to decode options of poll %s: %w", poll.MessageID, err)
 		}
+		polls = append(polls, &poll)
 	}
+	return polls, rows.Err()
+}
 
-	message := &domainChatStorage.Message{
-		ID:               evt.Info.ID,
-		ChatJID:          chatJID,
-		DeviceID:         deviceID,
-		Sender:           sender,
-		Content:          content,
-		Timestamp:        evt.Info.Timestamp,
-		IsFromMe:         evt.Info.IsFromMe,
-		MediaType:        mediaType,
-		Filename:         filename,
-		URL:              url,
-		MediaKey:         mediaKey,
-		FileSHA256:       fileSHA256,
-		FileEncSHA256:    fileEncSHA256,
-		FileLength:       fileLength,
-		ReferralMetadata: referralMetadata,
+// getPollVotes returns the stored votes of the given polls, keyed by poll message ID.
+func (r *SQLiteRepository) getPollVotes(deviceID string, pollMessageIDs []string) (map[string][]domainChatStorage.PollVote, error) {
+	votesByPollID := make(map[string][]domainChatStorage.PollVote)
+	if len(pollMessageIDs) == 0 {
+		return votesByPollID, nil
 	}
 
-	// Store the message
-	return r.StoreMessage(message)
-}
+	placeholders := make([]string, 0, len(pollMessageIDs))
+	args := make([]any, 0, len(pollMessageIDs)+1)
+	args = append(args, deviceID)
+	for _, messageID := range pollMessageIDs {
+		placeholders = append(placeholders, "?")
+		args = append(args, messageID)
+	}
 
-func extractEditedMessage(msg *waE2E.Message) *waE2E.Message {
-	if msg == nil {
-		return nil
+	rows, err := r.db.Query(`
+		SELECT poll_message_id, chat_jid, device_id, voter_jid, selected_options, is_from_me,
+			vote_timestamp, created_at, updated_at
+		FROM poll_votes
+		WHERE device_id = ? AND poll_message_id IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY vote_timestamp ASC, created_at ASC
+	`, args...)
+	if err != nil {
+		return nil, err
 	}
-	protocolMessage := msg.GetProtocolMessage()
-	if protocolMessage == nil || protocolMessage.GetType() != waE2E.ProtocolMessage_MESSAGE_EDIT {
-		return nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var vote domainChatStorage.PollVote
+		var selected string
+		if err := rows.Scan(
+			&vote.PollMessageID, &vote.ChatJID, &vote.DeviceID, &vote.VoterJID, &selected,
+			&vote.IsFromMe, &vote.Timestamp, &vote.CreatedAt, &vote.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(selected), &vote.SelectedOptions); err != nil {
+			return nil, fmt.Errorf("failed to decode vote of %s on poll %s: %w", vote.VoterJID, vote.PollMessageID, err)
+		}
+		votesByPollID[vote.PollMessageID] = append(votesByPollID[vote.PollMessageID], vote)
 	}
-	return protocolMessage.GetEditedMessage()
+	return votesByPollID, rows.Err()
 }
 
-func (r *SQLiteRepository) storeEditedMessage(ctx context.Context, evt *events.Message, deviceID, chatJID, sender string, editedMessage *waE2E.Message) error {
-	if evt == nil || editedMessage == nil {
-		return nil
+// tallyPollVotes counts votes per option in the poll's option order. Selections
+// naming options the poll does not have are ignored.
+func tallyPollVotes(poll *domainChatStorage.Poll, votes []domainChatStorage.PollVote) *domainChatStorage.PollResults {
+	results := &domainChatStorage.PollResults{
+		Poll:    poll,
+		Options: make([]domainChatStorage.PollOptionResult, len(poll.Options)),
+	}
+	optionIndex := make(map[string]int, len(poll.Options))
+	for i, name := range poll.Options {
+		results.Options[i] = domainChatStorage.PollOptionResult{Name: name, Voters: []string{}}
+		optionIndex[name] = i
+	}
+
+	for _, vote := range votes {
+		counted := false
+		for _, name := range vote.SelectedOptions {
+			i, ok := optionIndex[name]
+			if !ok {
+				continue
+			}
+			results.Options[i].Votes++
+			results.Options[i].Voters = append(results.Options[i].Voters, vote.VoterJID)
+			counted = true
+		}
+		if counted {
+			results.TotalVoters++
+		}
 	}
+	return results
+}
 
-	protocolMessage := evt.Message.GetProtocolMessage()
-	if protocolMessage == nil {
-		return nil
-	}
-	key := protocolMessage.GetKey()
-	if key == nil || key.GetID() == "" {
-		return nil
+// loadMessagePolls attaches poll results to the poll creation messages of a page.
+func (r *SQLiteRepository) loadMessagePolls(deviceID, chatJID string, messages []*domainChatStorage.Message) error {
+	messageIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if message != nil && message.ID != "" {
+			messageIDs = append(messageIDs, message.ID)
+		}
 	}
 
-	originalMessageID := key.GetID()
-	newContent := utils.ExtractMessageTextFromProto(editedMessage)
-	now := time.Now()
+	resultsByMessageID := make(map[string]*domainChatStorage.PollResults)
+	for start := 0; start < len(messageIDs); start += 500 {
+		end := start + 500
+		if end > len(messageIDs) {
+			end = len(messageIDs)
+		}
 
-	existingMessage, err := r.getMessageByDeviceAndChatIDAndMessageID(deviceID, chatJID, originalMessageID)
-	if err != nil {
-		return fmt.Errorf("failed to load original message %s: %w", originalMessageID, err)
-	}
+		batchIDs := messageIDs[start:end]
+		placeholders := make([]string, 0, len(batchIDs))
+		args := make([]any, 0, len(batchIDs)+2)
+		args = append(args, deviceID, chatJID)
+		for _, messageID := range batchIDs {
+			placeholders = append(placeholders, "?")
+			args = append(args, messageID)
+		}
 
-	messageExists := existingMessage != nil
-	currentMessage := existingMessage
-	previousContent := ""
+		polls, err := r.queryPolls(`device_id = ? AND chat_jid = ? AND message_id IN (`+strings.Join(placeholders, ",")+`)`, args...)
+		if err != nil {
+			return err
+		}
+		if len(polls) == 0 {
+			continue
+		}
 
-	if currentMessage == nil {
-		currentMessage = &domainChatStorage.Message{
-			ID:        originalMessageID,
-			ChatJID:   chatJID,
-			DeviceID:  deviceID,
-			Sender:    sender,
-			Content:   newContent,
-			Timestamp: evt.Info.Timestamp,
-			IsFromMe:  evt.Info.IsFromMe,
+		pollIDs := make([]string, 0, len(polls))
+		for _, poll := range polls {
+			pollIDs = append(pollIDs, poll.MessageID)
+		}
+		votes, err := r.getPollVotes(deviceID, pollIDs)
+		if err != nil {
+			return err
 		}
+		for _, poll := range polls {
+			resultsByMessageID[poll.MessageID] = tallyPollVotes(poll, votes[poll.MessageID])
+		}
+	}
 
-		if mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(editedMessage); mediaType != "" || newContent != "" {
-			currentMessage.MediaType = mediaType
-			currentMessage.Filename = filename
-			currentMessage.URL = url
-			currentMessage.MediaKey = mediaKey
-			currentMessage.FileSHA256 = fileSHA256
-			currentMessage.FileEncSHA256 = fileEncSHA256
-			currentMessage.FileLength = fileLength
+	for _, message := range messages {
+		if message == nil {
+			continue
 		}
-	} else {
-		previousContent = currentMessage.Content
+		message.Poll = resultsByMessageID[message.ID]
 	}
 
-	edit := &domainChatStorage.MessageEdit{
-		OriginalMessageID: originalMessageID,
-		EditEventID:       evt.Info.ID,
-		ChatJID:           chatJID,
-		DeviceID:          deviceID,
-		Editor:            sender,
-		PreviousContent:   previousContent,
-		NewContent:        newContent,
-		EditedAt:          evt.Info.Timestamp,
-		CreatedAt:         now,
+	return nil
+}
+
+// StorePollVote records a voter's latest selection, replacing any earlier vote.
+// An empty selection removes the vote.
+func (r *SQLiteRepository) StorePollVote(vote *domainChatStorage.PollVote) error {
+	if vote == nil {
+		return nil
+	}
+	if vote.PollMessageID == "" || vote.ChatJID == "" || vote.DeviceID == "" || vote.VoterJID == "" {
+		return fmt.Errorf("poll vote requires poll_message_id, chat_jid, device_id, and voter_jid")
+	}
+	if len(vote.SelectedOptions) == 0 {
+		_, err := r.db.Exec(`
+			DELETE FROM poll_votes
+			WHERE poll_message_id = ? AND voter_jid = ? AND device_id = ?
+		`, vote.PollMessageID, vote.VoterJID, vote.DeviceID)
+		return err
 	}
 
-	tx, err := r.db.Begin()
+	selected, err := json.Marshal(vote.SelectedOptions)
 	if err != nil {
-		return fmt.Errorf("failed to begin edit transaction: %w", err)
+		return fmt.Errorf("failed to encode poll vote: %w", err)
 	}
-	defer tx.Rollback()
 
-	if messageExists {
-		if _, err := tx.Exec(`
-			UPDATE messages SET content = ?, updated_at = ?
-			WHERE id = ? AND chat_jid = ? AND device_id = ?
-		`, newContent, now, originalMessageID, chatJID, deviceID); err != nil {
-			return fmt.Errorf("failed to update original message %s: %w", originalMessageID, err)
-		}
-	} else {
-		if _, err := tx.Exec(`
-			INSERT INTO messages (
-				id, chat_jid, device_id, sender, content, timestamp, is_from_me,
-				media_type, call_metadata, filename, url, media_key, file_sha256,
-				file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, currentMessage.ID, currentMessage.ChatJID, currentMessage.DeviceID, currentMessage.Sender, currentMessage.Content,
-			currentMessage.Timestamp, currentMessage.IsFromMe, currentMessage.MediaType, currentMessage.CallMetadata, currentMessage.Filename,
-			currentMessage.URL, currentMessage.MediaKey, currentMessage.FileSHA256, currentMessage.FileEncSHA256,
-			currentMessage.FileLength, currentMessage.ReferralMetadata, now, now); err != nil {
-			return fmt.Errorf("failed to insert edited message %s: %w", originalMessageID, err)
-		}
+	now := time.Now()
+	if vote.CreatedAt.IsZero() {
+		vote.CreatedAt = now
 	}
+	vote.UpdatedAt = now
 
-	if err := r.storeMessageEditExec(tx, edit); err != nil {
-		return fmt.Errorf("failed to store edit history for %s: %w", originalMessageID, err)
+	result, err := r.db.Exec(`
+		UPDATE poll_votes
+		SET chat_jid = ?, selected_options = ?, is_from_me = ?, vote_timestamp = ?, updated_at = ?
+		WHERE poll_message_id = ? AND voter_jid = ? AND device_id = ?
+	`, vote.ChatJID, string(selected), vote.IsFromMe, vote.Timestamp, vote.UpdatedAt,
+		vote.PollMessageID, vote.VoterJID, vote.DeviceID)
+	if err != nil {
+		return err
 	}
 
-	return tx.Commit()
-}
-
-func (r *SQLiteRepository) getMessageByDeviceAndChatIDAndMessageID(deviceID, chatJID, messageID string) (*domainChatStorage.Message, error) {
-	query := `
-		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
-			media_type, call_metadata, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, referral_metadata, created_at, updated_at
-		FROM messages
-		WHERE id = ? AND chat_jid = ? AND device_id = ?
-		LIMIT 1
-	`
-	message, err := r.scanMessage(r.db.QueryRow(query, messageID, chatJID, deviceID))
-	if err == sql.ErrNoRows {
-		return nil, nil
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO poll_votes (
+				poll_message_id, chat_jid, device_id, voter_jid, selected_options, is_from_me,
+				vote_timestamp, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, vote.PollMessageID, vote.ChatJID, vote.DeviceID, vote.VoterJID, string(selected),
+			vote.IsFromMe, vote.Timestamp, vote.CreatedAt, vote.UpdatedAt)
 	}
-	return message, err
-}
-
-func (r *SQLiteRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
-	return r.storeMessageEditExec(r.db, edit)
+	return err
 }
 
-func (r *SQLiteRepository) storeMessageEditExec(execer interface {
-	Exec(query string, args ...any) (sql.Result, error)
-}, edit *domainChatStorage.MessageEdit) error {
-	if edit == nil {
+// CreatePollVote stores a decrypted poll update. Votes on polls this device
+// never stored are skipped because their option hashes cannot be resolved.
+func (r *SQLiteRepository) CreatePollVote(ctx context.Context, evt *events.Message, vote *waE2E.PollVoteMessage) error {
+	if evt == nil || evt.Message == nil || vote == nil {
 		return nil
 	}
-	if strings.TrimSpace(edit.OriginalMessageID) == "" || strings.TrimSpace(edit.EditEventID) == "" || strings.TrimSpace(edit.ChatJID) == "" || strings.TrimSpace(edit.DeviceID) == "" {
-		return fmt.Errorf("edit history requires original_message_id, edit_event_id, chat_jid, and device_id")
+
+	pollUpdate := utils.UnwrapMessage(evt.Message).GetPollUpdateMessage()
+	key := pollUpdate.GetPollCreationMessageKey()
+	if key == nil || key.GetID() == "" {
+		logrus.Debugf("Skipping poll vote %s - missing poll message id", evt.Info.ID)
+		return nil
 	}
 
-	now := time.Now()
-	if edit.CreatedAt.IsZero() {
-		edit.CreatedAt = now
+	client := whatsapp.ClientFromContext(ctx)
+	deviceID := ""
+	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
+		deviceID = inst.JID()
+		if deviceID == "" {
+			deviceID = inst.ID()
+		}
 	}
-	if edit.EditedAt.IsZero() {
-		edit.EditedAt = edit.CreatedAt
+	if deviceID == "" && client != nil && client.Store != nil && client.Store.ID != nil {
+		deviceID = client.Store.ID.ToNonAD().String()
+	}
+	if deviceID == "" {
+		return domainChatStorage.ErrMissingDeviceContext
 	}
 
-	_, err := execer.Exec(`
-		INSERT INTO message_edits (
-			original_message_id, edit_event_id, chat_jid, device_id, editor,
-			previous_content, new_content, edited_at, created_at
-		)
-		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?
-		WHERE NOT EXISTS (
-			SELECT 1
-			FROM message_edits
-			WHERE original_message_id = ? AND edit_event_id = ? AND device_id = ?
-		)
-	`, edit.OriginalMessageID, edit.EditEventID, edit.ChatJID, edit.DeviceID, edit.Editor,
-		edit.PreviousContent, edit.NewContent, edit.EditedAt, edit.CreatedAt,
-		edit.OriginalMessageID, edit.EditEventID, edit.DeviceID)
-	return err
-}
-
-// CreateReaction stores or removes a reaction event as its own row in message_reactions.
-func (r *SQLiteRepository) CreateReaction(ctx context.Context, evt *events.Message) error {
-	reaction, err := r.reactionFromEvent(ctx, evt)
+	poll, err := r.GetPoll(deviceID, key.GetID())
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get poll %s: %w", key.GetID(), err)
 	}
-	if reaction == nil {
+	if poll == nil {
+		logrus.Debugf("Skipping poll vote %s - poll %s is not stored", evt.Info.ID, key.GetID())
 		return nil
 	}
-	return r.StoreReaction(reaction)
-}
 
-func (r *SQLiteRepository) reactionFromEvent(ctx context.Context, evt *events.Message) (*domainChatStorage.Reaction, error) {
-	if evt == nil || evt.Message == nil {
-		return nil, nil
+	voterJID := evt.Info.Sender
+	if voterJID.IsEmpty() && evt.Info.IsFromMe && client != nil && client.Store != nil && client.Store.ID != nil {
+		voterJID = client.Store.ID.ToNonAD()
 	}
-
-	msg := utils.UnwrapMessage(evt.Message)
-	reactionMessage := msg.GetReactionMessage()
-	if reactionMessage == nil {
-		return nil, nil
+	if voterJID.IsEmpty() {
+		return fmt.Errorf("poll vote sender jid is required")
 	}
+	normalizedVoterJID := whatsapp.NormalizeJIDFromLIDWithContext(voterJID, client)
 
-	key := reactionMessage.GetKey()
+	return r.StorePollVote(&domainChatStorage.PollVote{
+		PollMessageID:   poll.MessageID,
+		ChatJID:         poll.ChatJID,
+		DeviceID:        deviceID,
+		VoterJID:        normalizedVoterJID.ToNonAD().String(),
+		SelectedOptions: utils.MatchPollOptionHashes(poll.Options, vote.GetSelectedOptions()),
+		IsFromMe:        evt.Info.IsFromMe,
+		Timestamp:       evt.Info.Timestamp,
+	})
+}
+
+// CreateMessagePin pins or unpins the message a PinInChatMessage event refers to.
+func (r *SQLiteRepository) CreateMessagePin(ctx context.Context, evt *events.Message) error {
+	if evt == nil || evt.Message == nil {
+		return nil
+	}
+	pinMessage := utils.UnwrapMessage(evt.Message).GetPinInChatMessage()
+	key := pinMessage.GetKey()
 	if key == nil || key.GetID() == "" {
-		logrus.Debugf("Skipping reaction event %s - missing reacted message id", evt.Info.ID)
-		return nil, nil
+		logrus.Debugf("Skipping pin %s - missing pinned message id", evt.Info.ID)
+		return nil
 	}
 
 	client := whatsapp.ClientFromContext(ctx)
@@ -1580,43 +3943,163 @@ func (r *SQLiteRepository) reactionFromEvent(ctx context.Context, evt *events.Me
 		deviceID = client.Store.ID.ToNonAD().String()
 	}
 	if deviceID == "" {
-		return nil, domainChatStorage.ErrMissingDeviceContext
+		return domainChatStorage.ErrMissingDeviceContext
 	}
 
-	chatJID := evt.Info.Chat
-	if chatJID.IsEmpty() && key.GetRemoteJID() != "" {
-		parsed, err := types.ParseJID(key.GetRemoteJID())
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse reaction chat jid %q: %w", key.GetRemoteJID(), err)
-		}
-		chatJID = parsed
+	chatJID := whatsapp.NormalizeJIDFromLIDWithContext(evt.Info.Chat, client).ToNonAD().String()
+	if pinMessage.GetType() != waE2E.PinInChatMessage_PIN_FOR_ALL {
+		return r.DeleteMessagePin(deviceID, chatJID, key.GetID())
 	}
-	if chatJID.IsEmpty() {
-		return nil, fmt.Errorf("reaction chat jid is required")
+
+	pinnedBy := evt.Info.Sender
+	if pinnedBy.IsEmpty() && evt.Info.IsFromMe && client != nil && client.Store != nil && client.Store.ID != nil {
+		pinnedBy = client.Store.ID.ToNonAD()
+	}
+	pinnedAt := evt.Info.Timestamp
+	if ms := pinMessage.GetSenderTimestampMS(); ms > 0 {
+		pinnedAt = time.UnixMilli(ms)
+	}
+	duration := domainChatStorage.DefaultMessagePinDuration
+	if secs := evt.Message.GetMessageContextInfo().GetMessageAddOnDurationInSecs(); secs > 0 {
+		duration = time.Duration(secs) * time.Second
 	}
-	normalizedChatJID := whatsapp.NormalizeJIDFromLIDWithContext(chatJID, client)
 
-	reactorJID := evt.Info.Sender
-	if reactorJID.IsEmpty() && evt.Info.IsFromMe && client != nil && client.Store != nil && client.Store.ID != nil {
-		reactorJID = client.Store.ID.ToNonAD()
+	return r.StoreMessagePin(&domainChatStorage.MessagePin{
+		MessageID: key.GetID(),
+		ChatJID:   chatJID,
+		DeviceID:  deviceID,
+		PinnedBy:  whatsapp.NormalizeJIDFromLIDWithContext(pinnedBy, client).ToNonAD().String(),
+		PinnedAt:  pinnedAt,
+		ExpiresAt: pinnedAt.Add(duration),
+	})
+}
+
+// StoreMessagePin pins a message, replacing an earlier pin of it.
+func (r *SQLiteRepository) StoreMessagePin(pin *domainChatStorage.MessagePin) error {
+	if pin == nil {
+		return nil
 	}
-	if reactorJID.IsEmpty() {
-		return nil, fmt.Errorf("reaction sender jid is required")
+	if pin.MessageID == "" || pin.ChatJID == "" || pin.DeviceID == "" {
+		return fmt.Errorf("pin requires message_id, chat_jid, and device_id")
 	}
-	normalizedReactorJID := whatsapp.NormalizeJIDFromLIDWithContext(reactorJID, client)
+	_, err := r.db.Exec(`
+		INSERT INTO message_pins (device_id, chat_jid, message_id, pinned_by, pinned_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, chat_jid, message_id) DO UPDATE SET
+			pinned_by = excluded.pinned_by,
+			pinned_at = excluded.pinned_at,
+			expires_at = excluded.expires_at
+	`, pin.DeviceID, pin.ChatJID, pin.MessageID, pin.PinnedBy, pin.PinnedAt, pin.ExpiresAt)
+	return err
+}
 
-	return &domainChatStorage.Reaction{
-		MessageID:  key.GetID(),
-		ChatJID:    normalizedChatJID.String(),
-		DeviceID:   deviceID,
-		ReactorJID: normalizedReactorJID.ToNonAD().String(),
-		Emoji:      reactionMessage.GetText(),
-		IsFromMe:   evt.Info.IsFromMe,
-		Timestamp:  evt.Info.Timestamp,
-	}, nil
+// DeleteMessagePin unpins a message; unpinning a message that is not pinned is not an error.
+func (r *SQLiteRepository) DeleteMessagePin(deviceID, chatJID, messageID string) error {
+	_, err := r.db.Exec(`DELETE FROM message_pins WHERE device_id = ? AND chat_jid = ? AND message_id = ?`,
+		deviceID, chatJID, messageID)
+	return err
+}
+
+// GetMessagePins returns the messages of a chat still pinned at now, most recently pinned first.
+func (r *SQLiteRepository) GetMessagePins(deviceID, chatJID string, now time.Time) ([]*domainChatStorage.MessagePin, error) {
+	return r.queryMessagePins(`device_id = ? AND chat_jid = ? AND expires_at > ?`, deviceID, chatJID, now)
+}
+
+func (r *SQLiteRepository) queryMessagePins(where string, args ...any) ([]*domainChatStorage.MessagePin, error) {
+	rows, err := r.db.Query(`
+		SELECT message_id, chat_jid, device_id, pinned_by, pinned_at, expires_at
+		FROM message_pins
+		WHERE `+where+`
+		ORDER BY pinned_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pins []*domainChatStorage.MessagePin
+	for rows.Next() {
+		var pin domainChatStorage.MessagePin
+		if err := rows.Scan(&pin.MessageID, &pin.ChatJID, &pin.DeviceID, &pin.PinnedBy, &pin.PinnedAt, &pin.ExpiresAt); err != nil {
+			return nil, err
+		}
+		pins = append(pins, &pin)
+	}
+	return pins, rows.Err()
+}
+
+// loadMessagePins attaches the current pin to the pinned messages of a page.
+func (r *SQLiteRepository) loadMessagePins(deviceID, chatJID string, messages []*domainChatStorage.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	pins, err := r.GetMessagePins(deviceID, chatJID, time.Now())
+	if err != nil {
+		return err
+	}
+	pinsByMessageID := make(map[string]*domainChatStorage.MessagePin, len(pins))
+	for _, pin := range pins {
+		pinsByMessageID[pin.MessageID] = pin
+	}
+	for _, message := range messages {
+		if message != nil {
+			message.Pin = pinsByMessageID[message.ID]
+		}
+	}
+	return nil
 }
 
 // CreateIncomingCallRecord stores an incoming call as a synthetic message row (media_type "call").
+// StoreLIDMapping records that lid and phoneJID are the same account. A phone
+// number has one LID, so a previous mapping of phoneJID to another LID is
+// replaced.
+func (r *SQLiteRepository) StoreLIDMapping(lid, phoneJID string) error {
+	if lid == "" || phoneJID == "" {
+		return fmt.Errorf("lid mapping requires lid and phone_jid")
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM lid_mappings WHERE phone_jid = ? AND lid != ?`, phoneJID, lid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO lid_mappings (lid, phone_jid, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(lid) DO UPDATE SET
+			phone_jid = excluded.phone_jid,
+			updated_at = excluded.updated_at
+	`, lid, phoneJID, time.Now()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetLIDMapping returns the mapping of a LID, or nil when it is unknown.
+func (r *SQLiteRepository) GetLIDMapping(lid string) (*domainChatStorage.LIDMapping, error) {
+	return r.queryLIDMapping(`lid = ?`, lid)
+}
+
+// GetLIDMappingByPhone returns the mapping of a phone number JID, or nil when it is unknown.
+func (r *SQLiteRepository) GetLIDMappingByPhone(phoneJID string) (*domainChatStorage.LIDMapping, error) {
+	return r.queryLIDMapping(`phone_jid = ?`, phoneJID)
+}
+
+func (r *SQLiteRepository) queryLIDMapping(where string, args ...any) (*domainChatStorage.LIDMapping, error) {
+	var mapping domainChatStorage.LIDMapping
+	err := r.db.QueryRow(`SELECT lid, phone_jid, updated_at FROM lid_mappings WHERE `+where, args...).
+		Scan(&mapping.LID, &mapping.PhoneJID, &mapping.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
 func (r *SQLiteRepository) CreateIncomingCallRecord(ctx context.Context, evt *events.CallOffer, autoRejected bool) error {
 	if evt == nil {
 		return nil
@@ -1713,6 +4196,7 @@ func (r *SQLiteRepository) CreateIncomingCallRecord(ctx context.Context, evt *ev
 
 // GetStorageStatistics returns current storage statistics for logging purposes
 func (r *SQLiteRepository) GetStorageStatistics() (chatCount int64, messageCount int64, err error) {
+	r.flushPendingMessages()
 	// Count all chats using efficient query
 	chatCount, err = r.GetTotalChatCount()
 	if err != nil {
@@ -1838,136 +4322,222 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 	var mediaType, filename, mediaURL string
 	var mediaKey, fileSHA256, fileEncSHA256 []byte
 	var fileLength uint64
+	var quotedMessageID, quotedParticipant string
 	if msg != nil {
 		mediaType, filename, mediaURL, mediaKey, fileSHA256, fileEncSHA256, fileLength = utils.ExtractMediaInfo(msg)
+		quotedMessageID, quotedParticipant = utils.ExtractQuotedReference(msg)
 	}
 
 	// Store the sent message
 	message := &domainChatStorage.Message{
-		ID:            messageID,
-		ChatJID:       chatJID,
-		DeviceID:      deviceID,
-		Sender:        senderJID,
-		Content:       content,
-		Timestamp:     timestamp,
-		IsFromMe:      true,
-		MediaType:     mediaType,
-		Filename:      filename,
-		URL:           mediaURL,
-		MediaKey:      mediaKey,
-		FileSHA256:    fileSHA256,
-		FileEncSHA256: fileEncSHA256,
-		FileLength:    fileLength,
+		ID:                messageID,
+		ChatJID:           chatJID,
+		DeviceID:          deviceID,
+		Sender:            senderJID,
+		Content:           content,
+		Timestamp:         timestamp,
+		IsFromMe:          true,
+		MediaType:         mediaType,
+		Filename:          filename,
+		URL:               mediaURL,
+		MediaKey:          mediaKey,
+		FileSHA256:        fileSHA256,
+		FileEncSHA256:     fileEncSHA256,
+		FileLength:        fileLength,
+		QuotedMessageID:   quotedMessageID,
+		QuotedParticipant: normalizeQuotedParticipant(ctx, quotedParticipant, client),
+	}
+
+	if err := r.StoreMessage(message); err != nil {
+		return err
 	}
+	return r.storePollFromMessage(message, msg)
+}
 
-	return r.StoreMessage(message)
+// MergeLIDChat merges a LID-based chat into a phone-based chat for a single device
+// with MergeChats; the LID chat row is deleted, or renamed in-place when no phone-chat
+// target exists yet. Fork-only — see docs/plans/2026-01-18-fix-history-sync-lid-duplicate-chats.md.
+func (r *SQLiteRepository) MergeLIDChat(deviceID, lidJID, phoneJID string) error {
+	_, err := r.MergeChats(deviceID, lidJID, phoneJID, false)
+	if errors.Is(err, domainChatStorage.ErrChatNotFound) {
+		// LID chat doesn't exist, nothing to merge
+		return nil
+	}
+	return err
 }
 
-// MergeLIDChat merges a LID-based chat into a phone-based chat for a single device.
-// All messages whose chat_jid equals lidJID are rewritten to phoneJID inside a single
-// transaction; the LID chat row is then deleted (or renamed in-place when no phone-chat
-// target exists yet). Fork-only — see docs/plans/2026-01-18-fix-history-sync-lid-duplicate-chats.md.
+// chatMergeSteps move the rows of one chat to another. Every query binds the
+// target JID as ?1, the source JID as ?2 and the device as ?3. Steps without a
+// count only clean up what the step before could not move.
+var chatMergeSteps = []struct {
+	name  string
+	count func(*domainChatStorage.ChatMergeSummary) *int
+	query string
+}{
+	// Edits move before the messages they reference; foreign keys are checked at commit.
+	{"edits", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.Edits },
+		`UPDATE message_edits SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"duplicate messages", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.DuplicateMessages },
+		`DELETE FROM messages WHERE chat_jid = ?2 AND device_id = ?3
+			AND id IN (SELECT id FROM messages WHERE chat_jid = ?1 AND device_id = ?3)`},
+	{"messages", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.Messages },
+		`UPDATE messages SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"reactions", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.Reactions },
+		`UPDATE message_reactions SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"message pins", nil,
+		`UPDATE OR IGNORE message_pins SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"duplicate message pins", nil,
+		`DELETE FROM message_pins WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"polls", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.Polls },
+		`UPDATE polls SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"poll votes", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.PollVotes },
+		`UPDATE poll_votes SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"chat labels", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.ChatLabels },
+		`UPDATE OR IGNORE chat_labels SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"duplicate chat labels", nil,
+		`DELETE FROM chat_labels WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"message labels", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.MessageLabels },
+		`UPDATE OR IGNORE message_labels SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"duplicate message labels", nil,
+		`DELETE FROM message_labels WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"read receipts", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.ReadReceipts },
+		`UPDATE chatwoot_message_links SET wa_chat_jid = ?1 WHERE wa_chat_jid = ?2 AND device_id = ?3`},
+	{"outbox events", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.OutboxEvents },
+		`UPDATE events_outbox SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	// The target's own draft wins over the source's.
+	{"drafts", func(s *domainChatStorage.ChatMergeSummary) *int { return &s.Drafts },
+		`UPDATE OR IGNORE drafts SET chat_jid = ?1 WHERE chat_jid = ?2 AND device_id = ?3`},
+	{"duplicate drafts", nil,
+		`DELETE FROM drafts WHERE chat_jid = ?2 AND device_id = ?3`},
+}
+
+// MergeChats moves everything stored for sourceJID on one device into
+// targetJID: messages with their reactions, edits, polls and labels, Chatwoot
+// links, outbox events and the draft. A message or draft stored in both chats
+// keeps the target copy. The source chat row is then deleted, or renamed when
+// the target chat does not exist yet.
+//
+// With dryRun the merge runs in a transaction that is rolled back, so the
+// summary counts exactly what a real merge would move.
 //
 // IMPORTANT: chatstorage runs with db.SetMaxOpenConns(1) (see cmd/root.go:initChatStorage).
 // All reads inside this transaction MUST go through tx.QueryRow — calling r.GetChatByDevice
 // (which uses r.db.QueryRow) would request a second pool connection and deadlock against
 // the tx itself.
-func (r *SQLiteRepository) MergeLIDChat(deviceID, lidJID, phoneJID string) error {
+func (r *SQLiteRepository) MergeChats(deviceID, sourceJID, targetJID string, dryRun bool) (*domainChatStorage.ChatMergeSummary, error) {
+	if sourceJID == targetJID {
+		return nil, fmt.Errorf("cannot merge chat %s into itself", sourceJID)
+	}
+	r.flushPendingMessages()
+
 	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	if _, err := tx.Exec(`PRAGMA defer_foreign_keys = ON`); err != nil {
+		return nil, fmt.Errorf("failed to defer foreign keys: %w", err)
+	}
+
 	const getChatByDeviceSQL = `
-		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived
+		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived,
+			pinned, muted_until, unread_count, deleted_at
 		FROM chats
 		WHERE jid = ? AND device_id = ?
 	`
 
-	// Get the LID chat to merge its metadata (tx-scoped — see note above).
-	lidChat, err := r.scanChat(tx.QueryRow(getChatByDeviceSQL, lidJID, deviceID))
+	sourceChat, err := r.scanChat(tx.QueryRow(getChatByDeviceSQL, sourceJID, deviceID))
 	if err == sql.ErrNoRows {
-		// LID chat doesn't exist, nothing to merge
-		return nil
+		return nil, domainChatStorage.ErrChatNotFound
 	}
 	if err != nil {
-		return fmt.Errorf("failed to get LID chat: %w", err)
+		return nil, fmt.Errorf("failed to get source chat: %w", err)
 	}
 
-	// Get the phone chat (target) — may legitimately not exist (tx-scoped).
-	phoneChat, err := r.scanChat(tx.QueryRow(getChatByDeviceSQL, phoneJID, deviceID))
+	// The target chat may legitimately not exist.
+	targetChat, err := r.scanChat(tx.QueryRow(getChatByDeviceSQL, targetJID, deviceID))
 	if err == sql.ErrNoRows {
-		phoneChat = nil
+		targetChat = nil
 	} else if err != nil {
-		return fmt.Errorf("failed to get phone chat: %w", err)
+		return nil, fmt.Errorf("failed to get target chat: %w", err)
 	}
 
-	// Update all messages from LID chat to phone chat
-	_, err = tx.Exec(`
-		UPDATE messages SET chat_jid = ?
-		WHERE chat_jid = ? AND device_id = ?
-	`, phoneJID, lidJID, deviceID)
-	if err != nil {
-		return fmt.Errorf("failed to update messages: %w", err)
+	summary := &domainChatStorage.ChatMergeSummary{TargetExists: targetChat != nil}
+	for _, step := range chatMergeSteps {
+		result, err := tx.Exec(step.query, targetJID, sourceJID, deviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge %s: %w", step.name, err)
+		}
+		if step.count != nil {
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return nil, err
+			}
+			*step.count(summary) = int(affected)
+		}
 	}
 
-	// If phone chat exists, update its metadata with LID chat's if newer
-	if phoneChat != nil {
-		// Use LID chat's name if phone chat has a phone number as name
-		if lidChat.Name != "" && isPhoneNumberString(phoneChat.Name) {
-			phoneChat.Name = lidChat.Name
+	if targetChat != nil {
+		// Use the source chat's name if the target chat has none or a phone number as name
+		if sourceChat.Name != "" && (targetChat.Name == "" || isPhoneNumberString(targetChat.Name)) {
+			targetChat.Name = sourceChat.Name
 		}
 		// Use later timestamp
-		if lidChat.LastMessageTime.After(phoneChat.LastMessageTime) {
-			phoneChat.LastMessageTime = lidChat.LastMessageTime
+		if sourceChat.LastMessageTime.After(targetChat.LastMessageTime) {
+			targetChat.LastMessageTime = sourceChat.LastMessageTime
 		}
 		// Preserve ephemeral settings
-		if phoneChat.EphemeralExpiration == 0 && lidChat.EphemeralExpiration > 0 {
-			phoneChat.EphemeralExpiration = lidChat.EphemeralExpiration
+		if targetChat.EphemeralExpiration == 0 && sourceChat.EphemeralExpiration > 0 {
+			targetChat.EphemeralExpiration = sourceChat.EphemeralExpiration
 		}
-		// Update phone chat within the transaction to maintain atomicity
 		_, err = tx.Exec(`
-			UPDATE chats SET name = ?, last_message_time = ?, ephemeral_expiration = ?, updated_at = ?
+			UPDATE chats SET name = ?, last_message_time = ?, ephemeral_expiration = ?, unread_count = unread_count + ?, updated_at = ?
 			WHERE jid = ? AND device_id = ?
-		`, phoneChat.Name, phoneChat.LastMessageTime, phoneChat.EphemeralExpiration, time.Now(), phoneChat.JID, phoneChat.DeviceID)
+		`, targetChat.Name, targetChat.LastMessageTime, targetChat.EphemeralExpiration, sourceChat.UnreadCount, time.Now(), targetJID, deviceID)
 		if err != nil {
-			return fmt.Errorf("failed to update phone chat: %w", err)
+			return nil, fmt.Errorf("failed to update target chat: %w", err)
+		}
+		if _, err = tx.Exec(`DELETE FROM chats WHERE jid = ? AND device_id = ?`, sourceJID, deviceID); err != nil {
+			return nil, fmt.Errorf("failed to delete source chat: %w", err)
 		}
 	} else {
-		// Phone chat doesn't exist, rename LID chat to phone JID
-		_, err = tx.Exec(`
-			UPDATE chats SET jid = ?
-			WHERE jid = ? AND device_id = ?
-		`, phoneJID, lidJID, deviceID)
-		if err != nil {
-			return fmt.Errorf("failed to rename chat: %w", err)
+		// Target chat doesn't exist, rename the source chat to the target JID
+		if _, err = tx.Exec(`UPDATE chats SET jid = ? WHERE jid = ? AND device_id = ?`, targetJID, sourceJID, deviceID); err != nil {
+			return nil, fmt.Errorf("failed to rename chat: %w", err)
 		}
-		return tx.Commit()
 	}
 
-	// Delete the LID chat entry
-	_, err = tx.Exec(`
-		DELETE FROM chats WHERE jid = ? AND device_id = ?
-	`, lidJID, deviceID)
-	if err != nil {
-		return fmt.Errorf("failed to delete LID chat: %w", err)
+	if dryRun {
+		return summary, nil
 	}
-
-	logrus.Infof("Merged LID chat %s into %s for device %s", lidJID, phoneJID, deviceID)
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	logrus.Infof("Merged chat %s into %s for device %s (%d messages)", sourceJID, targetJID, deviceID, summary.Messages)
+	return summary, nil
 }
 
 // GetLIDChats returns all chats with @lid JIDs for a device. Fork-only.
 func (r *SQLiteRepository) GetLIDChats(deviceID string) ([]*domainChatStorage.Chat, error) {
+	return r.getChatsOnServer(deviceID, types.HiddenUserServer)
+}
+
+// GetPhoneChats returns all chats with phone number JIDs for a device.
+func (r *SQLiteRepository) GetPhoneChats(deviceID string) ([]*domainChatStorage.Chat, error) {
+	return r.getChatsOnServer(deviceID, types.DefaultUserServer)
+}
+
+func (r *SQLiteRepository) getChatsOnServer(deviceID, server string) ([]*domainChatStorage.Chat, error) {
 	query := `
-		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived
+		SELECT device_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, archived,
+			pinned, muted_until, unread_count, deleted_at
 		FROM chats
-		WHERE device_id = ? AND jid LIKE '%@lid'
+		WHERE device_id = ? AND jid LIKE ?
 		ORDER BY last_message_time DESC
 	`
 
-	rows, err := r.db.Query(query, deviceID)
+	rows, err := r.db.Query(query, deviceID, "%@"+server)
 	if err != nil {
 		return nil, err
 	}
@@ -1986,7 +4556,7 @@ func (r *SQLiteRepository) GetLIDChats(deviceID string) ([]*domainChatStorage.Ch
 }
 
 // isPhoneNumberString checks if a string looks like a phone number (digits only, optional leading +).
-// Used by MergeLIDChat to decide when an existing phone-chat name should be overwritten.
+// Used by MergeChats to decide when an existing target chat name should be overwritten.
 func isPhoneNumberString(s string) bool {
 	if s == "" {
 		return false
@@ -2002,6 +4572,262 @@ func isPhoneNumberString(s string) bool {
 	return len(s) >= 5 // Minimum phone number length
 }
 
+// GetEmptyChats returns the JIDs of the device's chats that have no messages.
+// Archived, pinned and muted chats are skipped because that state comes from
+// WhatsApp app state and is worth keeping without any stored message.
+func (r *SQLiteRepository) GetEmptyChats(deviceID string) ([]string, error) {
+	r.flushPendingMessages()
+	rows, err := r.db.Query(`
+		SELECT c.jid
+		FROM chats c
+		WHERE c.device_id = ?
+			AND c.archived = 0 AND c.pinned = 0 AND c.muted_until = 0
+			AND NOT EXISTS (SELECT 1 FROM messages m WHERE m.chat_jid = c.jid AND m.device_id = c.device_id)
+		ORDER BY c.jid
+	`, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jids []string
+	for rows.Next() {
+		var jid string
+		if err := rows.Scan(&jid); err != nil {
+			return nil, err
+		}
+		jids = append(jids, jid)
+	}
+	return jids, rows.Err()
+}
+
+// GetMissingChats returns the chats the device has messages for but no chat row.
+func (r *SQLiteRepository) GetMissingChats(deviceID string) ([]*domainChatStorage.MissingChat, error) {
+	r.flushPendingMessages()
+	rows, err := r.db.Query(`
+		SELECT m.chat_jid, COUNT(*)
+		FROM messages m
+		WHERE m.device_id = ?
+			AND NOT EXISTS (SELECT 1 FROM chats c WHERE c.jid = m.chat_jid AND c.device_id = m.device_id)
+		GROUP BY m.chat_jid
+		ORDER BY m.chat_jid
+	`, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []*domainChatStorage.MissingChat
+	for rows.Next() {
+		chat := &domainChatStorage.MissingChat{}
+		if err := rows.Scan(&chat.ChatJID, &chat.MessageCount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		missing = append(missing, chat)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	// Read the latest timestamp separately so it is scanned from the column
+	// itself; an aggregate would lose the column type and come back as text.
+	for _, chat := range missing {
+		err := r.db.QueryRow(`
+			SELECT timestamp FROM messages
+			WHERE chat_jid = ? AND device_id = ?
+			ORDER BY timestamp DESC
+			LIMIT 1
+		`, chat.ChatJID, deviceID).Scan(&chat.LastMessageTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read latest message of %s: %w", chat.ChatJID, err)
+		}
+	}
+	return missing, nil
+}
+
+// RestoreMissingChats creates the chat row of every chat GetMissingChats
+// reports, named the way CreateMessage names a chat it has not seen before.
+func (r *SQLiteRepository) RestoreMissingChats(deviceID string) (int64, error) {
+	missing, err := r.GetMissingChats(deviceID)
+	if err != nil {
+		return 0, err
+	}
+
+	var restored int64
+	for _, chat := range missing {
+		jid, err := types.ParseJID(chat.ChatJID)
+		if err != nil {
+			logrus.Warnf("Skipping restore of chat %s: %v", chat.ChatJID, err)
+			continue
+		}
+		if err := r.StoreChat(&domainChatStorage.Chat{
+			DeviceID:        deviceID,
+			JID:             chat.ChatJID,
+			Name:            r.GetChatNameWithPushNameByDevice(deviceID, jid, chat.ChatJID, "", ""),
+			LastMessageTime: chat.LastMessageTime,
+		}); err != nil {
+			return restored, fmt.Errorf("failed to restore chat %s: %w", chat.ChatJID, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// mediaRef returns the key of the media row a message references: the file's
+// SHA-256, so every copy of a file shares one row. Media without a file hash
+// gets a key of its own derived from the message, and a message without media
+// references nothing.
+func mediaRef(message *domainChatStorage.Message) []byte {
+	if len(message.FileSHA256) > 0 {
+		return message.FileSHA256
+	}
+	if message.URL == "" && len(message.MediaKey) == 0 && len(message.FileEncSHA256) == 0 && message.FileLength == 0 {
+		return nil
+	}
+	return []byte("msg:" + message.DeviceID + "/" + message.ChatJID + "/" + message.ID)
+}
+
+// storeMediaExec upserts the media row a message references, before the message
+// row is written. A message without a download URL does not overwrite what an
+// earlier copy of the file stored.
+func (r *SQLiteRepository) storeMediaExec(execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, message *domainChatStorage.Message, sealedMediaKey []byte) error {
+	key := mediaRef(message)
+	if key == nil {
+		return nil
+	}
+	now := time.Now()
+	_, err := execer.Exec(`
+		INSERT INTO media (device_id, file_sha256, url, media_key, file_enc_sha256, file_length, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, file_sha256) DO UPDATE SET
+			url = excluded.url,
+			media_key = excluded.media_key,
+			file_enc_sha256 = excluded.file_enc_sha256,
+			file_length = excluded.file_length,
+			updated_at = excluded.updated_at
+		WHERE excluded.url != ''
+	`, message.DeviceID, key, message.URL, sealedMediaKey, message.FileEncSHA256, message.FileLength, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to store media of message %s: %w", message.ID, err)
+	}
+	return nil
+}
+
+// GetMedia returns the media blob stored for a file hash, or nil when no
+// message of the device carried that file.
+func (r *SQLiteRepository) GetMedia(deviceID string, fileSHA256 []byte) (*domainChatStorage.Media, error) {
+	r.flushPendingMessages()
+	media, err := r.scanMedia(r.db.QueryRow(`
+		SELECT device_id, file_sha256, url, media_key, file_enc_sha256, file_length, local_path, ref_count, created_at, updated_at
+		FROM media
+		WHERE device_id = ? AND file_sha256 = ?
+	`, deviceID, fileSHA256))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return media, err
+}
+
+// SetMediaLocalPath records where the file of a media blob was downloaded to,
+// so later downloads of any message carrying the same file can reuse it.
+func (r *SQLiteRepository) SetMediaLocalPath(deviceID string, fileSHA256 []byte, localPath string) error {
+	r.flushPendingMessages()
+	_, err := r.db.Exec(`
+		UPDATE media SET local_path = ?, updated_at = ?
+		WHERE device_id = ? AND file_sha256 = ?
+	`, localPath, time.Now(), deviceID, fileSHA256)
+	return err
+}
+
+// GetMessagesByMedia returns every message of the device that references the
+// media blob, oldest first.
+func (r *SQLiteRepository) GetMessagesByMedia(deviceID string, fileSHA256 []byte) ([]*domainChatStorage.Message, error) {
+	r.flushPendingMessages()
+	rows, err := r.db.Query(`
+		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
+			media_type, call_metadata, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, referral_metadata, quoted_message_id, quoted_participant, created_at, updated_at
+		FROM message_rows
+		WHERE device_id = ? AND media_sha256 = ?
+		ORDER BY timestamp ASC, id ASC
+	`, deviceID, fileSHA256)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.Message
+	for rows.Next() {
+		message, err := r.scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// DeleteUnreferencedMedia deletes the device's media blobs that no message
+// references any more and returns them. Removing downloaded files is left to
+// the caller.
+func (r *SQLiteRepository) DeleteUnreferencedMedia(deviceID string) ([]*domainChatStorage.Media, error) {
+	r.flushPendingMessages()
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT device_id, file_sha256, url, media_key, file_enc_sha256, file_length, local_path, ref_count, created_at, updated_at
+		FROM media
+		WHERE device_id = ? AND ref_count <= 0
+	`, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	var unreferenced []*domainChatStorage.Media
+	for rows.Next() {
+		media, err := r.scanMedia(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		unreferenced = append(unreferenced, media)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	if _, err := tx.Exec(`DELETE FROM media WHERE device_id = ? AND ref_count <= 0`, deviceID); err != nil {
+		return nil, fmt.Errorf("failed to delete unreferenced media: %w", err)
+	}
+	return unreferenced, tx.Commit()
+}
+
+func (r *SQLiteRepository) scanMedia(scanner interface{ Scan(...any) error }) (*domainChatStorage.Media, error) {
+	media := &domainChatStorage.Media{}
+	err := scanner.Scan(
+		&media.DeviceID, &media.FileSHA256, &media.URL, &media.MediaKey, &media.FileEncSHA256,
+		&media.FileLength, &media.LocalPath, &media.RefCount, &media.CreatedAt, &media.UpdatedAt,
+	)
+	if err != nil {
+		return media, err
+	}
+	mediaKey, err := r.cipher.decryptBytes(media.MediaKey)
+	if err != nil {
+		return media, fmt.Errorf("failed to decrypt media key: %w", err)
+	}
+	media.MediaKey = mediaKey
+	return media, nil
+}
+
 // _____________________________________________________________________________________________________________________
 
 // initializeSchema creates or migrates the database schema
@@ -2020,7 +4846,74 @@ func (r *SQLiteRepository) InitializeSchema() error {
 		}
 	}
 
-	return nil
+	if err := r.dropMovedMediaColumns(); err != nil {
+		return err
+	}
+
+	return r.encryptExistingData()
+}
+
+// movedMediaColumns are the messages columns whose values migration 50 copied
+// into the media table.
+var movedMediaColumns = []string{"url", "media_key", "file_enc_sha256", "file_length"}
+
+// dropMovedMediaColumns drops the legacy media columns of messages once every
+// message referencing media has its row in the media table. Until then the
+// columns are kept and startup fails, so an incomplete move never loses media
+// fields. It is a no-op once the columns are gone. DROP COLUMN needs SQLite
+// 3.35 or later, which the bundled driver provides.
+func (r *SQLiteRepository) dropMovedMediaColumns() error {
+	rows, err := r.db.Query(`SELECT name FROM pragma_table_info('messages')`)
+	if err != nil {
+		return fmt.Errorf("failed to list message columns: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to list message columns: %w", err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list message columns: %w", err)
+	}
+
+	var columns []string
+	for _, column := range movedMediaColumns {
+		if existing[column] {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	var missing int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM messages m
+		WHERE m.media_sha256 IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM media md WHERE md.device_id = m.device_id AND md.file_sha256 = m.media_sha256)
+	`).Scan(&missing); err != nil {
+		return fmt.Errorf("failed to verify moved media: %w", err)
+	}
+	if missing > 0 {
+		return fmt.Errorf("%d messages reference media missing from the media table; keeping the legacy media columns", missing)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, column := range columns {
+		if _, err := tx.Exec(`ALTER TABLE messages DROP COLUMN ` + column); err != nil {
+			return fmt.Errorf("failed to drop messages.%s: %w", column, err)
+		}
+	}
+	return tx.Commit()
 }
 
 // getSchemaVersion returns the current schema version
@@ -2068,8 +4961,8 @@ func (r *SQLiteRepository) runMigration(migration string, version int) error {
 	return tx.Commit()
 }
 
-// getMigrations returns all database migrations
-// Compatible with SQLite, MySQL, and PostgreSQL
+// getMigrations returns all database migrations. They use SQLite syntax
+// (INSERT OR IGNORE, SQLite triggers), as chat storage only runs on SQLite.
 func (r *SQLiteRepository) getMigrations() []string {
 	return []string{
 		// Migration 1: Create chats table
@@ -2242,5 +5135,318 @@ func (r *SQLiteRepository) getMigrations() []string {
 
 		// Migration 29: Fetch due Chatwoot retry jobs in stable order
 		`CREATE INDEX IF NOT EXISTS idx_chatwoot_forward_queue_due ON chatwoot_forward_queue(next_attempt_at, id)`,
+
+		// Migration 30: Serve keyset message pages (GetMessages with a cursor, IterateMessages)
+		// straight from the index: equality on device/chat, then the (timestamp, id) sort key.
+		`CREATE INDEX IF NOT EXISTS idx_messages_chat_keyset ON messages(device_id, chat_jid, timestamp, id)`,
+
+		// Migration 31: Reply linkage (ExtendedTextMessage/ContextInfo stanza id + participant)
+		`ALTER TABLE messages ADD COLUMN quoted_message_id TEXT DEFAULT ''`,
+
+		// Migration 32: Sender of the quoted message
+		`ALTER TABLE messages ADD COLUMN quoted_participant TEXT DEFAULT ''`,
+
+		// Migration 33: Pinned chats (app state pin action)
+		`ALTER TABLE chats ADD COLUMN pinned BOOLEAN DEFAULT FALSE`,
+
+		// Migration 34: Mute expiry as unix seconds, 0 = not muted (app state mute action)
+		`ALTER TABLE chats ADD COLUMN muted_until INTEGER DEFAULT 0`,
+
+		// Migration 35: Unread message counter per chat
+		`ALTER TABLE chats ADD COLUMN unread_count INTEGER DEFAULT 0`,
+
+		// Migration 36: Poll question and options, keyed by the poll creation message
+		`CREATE TABLE IF NOT EXISTS polls (
+			message_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			creator_jid VARCHAR(255) DEFAULT '',
+			question TEXT NOT NULL DEFAULT '',
+			options TEXT NOT NULL DEFAULT '[]',
+			selectable_count INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (message_id, device_id)
+		)`,
+
+		// Migration 37: Latest decrypted vote per voter and poll
+		`CREATE TABLE IF NOT EXISTS poll_votes (
+			poll_message_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			voter_jid VARCHAR(255) NOT NULL,
+			selected_options TEXT NOT NULL DEFAULT '[]',
+			is_from_me BOOLEAN DEFAULT FALSE,
+			vote_timestamp TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (poll_message_id, voter_jid, device_id)
+		)`,
+
+		// Migration 38: Long-running jobs (search reindex, chat export, ...)
+		`CREATE TABLE IF NOT EXISTS jobs (
+			id VARCHAR(64) PRIMARY KEY,
+			type VARCHAR(50) NOT NULL,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL,
+			current_step VARCHAR(255) DEFAULT '',
+			completed INTEGER DEFAULT 0,
+			total INTEGER DEFAULT 0,
+			result TEXT DEFAULT '',
+			result_path TEXT DEFAULT '',
+			error TEXT DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			started_at TIMESTAMP,
+			finished_at TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+
+		// Migration 39: List jobs newest-first, optionally by type
+		`CREATE INDEX IF NOT EXISTS idx_jobs_type_created ON jobs(type, created_at)`,
+
+		// Migration 40: Last known group metadata and membership per device
+		`CREATE TABLE IF NOT EXISTS group_snapshots (
+			device_id VARCHAR(255) NOT NULL,
+			group_jid VARCHAR(255) NOT NULL,
+			name TEXT DEFAULT '',
+			topic TEXT DEFAULT '',
+			owner_jid VARCHAR(255) DEFAULT '',
+			ephemeral_expiration INTEGER DEFAULT 0,
+			announce BOOLEAN DEFAULT FALSE,
+			locked BOOLEAN DEFAULT FALSE,
+			participants TEXT NOT NULL DEFAULT '[]',
+			participant_count INTEGER DEFAULT 0,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, group_jid)
+		)`,
+
+		// Migration 41: WhatsApp Business labels synced from app state
+		`CREATE TABLE IF NOT EXISTS labels (
+			device_id VARCHAR(255) NOT NULL,
+			label_id VARCHAR(255) NOT NULL,
+			name TEXT DEFAULT '',
+			color INTEGER DEFAULT 0,
+			predefined_id INTEGER DEFAULT 0,
+			order_index INTEGER DEFAULT 0,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, label_id)
+		)`,
+
+		// Migration 42: Labels applied to chats
+		`CREATE TABLE IF NOT EXISTS chat_labels (
+			device_id VARCHAR(255) NOT NULL,
+			label_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, label_id, chat_jid)
+		)`,
+
+		// Migration 43: Look up the labels of a chat
+		`CREATE INDEX IF NOT EXISTS idx_chat_labels_chat ON chat_labels(device_id, chat_jid)`,
+
+		// Migration 44: Labels applied to individual messages
+		`CREATE TABLE IF NOT EXISTS message_labels (
+			device_id VARCHAR(255) NOT NULL,
+			label_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			message_id VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, label_id, chat_jid, message_id)
+		)`,
+
+		// Migration 45: Look up the labels of a chat's messages
+		`CREATE INDEX IF NOT EXISTS idx_message_labels_chat ON message_labels(device_id, chat_jid, message_id)`,
+
+		// Migration 46: Media blobs stored once per device and file hash
+		`CREATE TABLE IF NOT EXISTS media (
+			device_id VARCHAR(255) NOT NULL,
+			file_sha256 BLOB NOT NULL,
+			url TEXT DEFAULT '',
+			media_key BLOB,
+			file_enc_sha256 BLOB,
+			file_length INTEGER DEFAULT 0,
+			local_path TEXT DEFAULT '',
+			ref_count INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (device_id, file_sha256)
+		)`,
+
+		// Migration 47: Key of the media blob a message references (see mediaRef)
+		`ALTER TABLE messages ADD COLUMN media_sha256 BLOB`,
+
+		// Migration 48: Reference the media of existing messages
+		`UPDATE messages SET media_sha256 = CASE
+			WHEN length(file_sha256) > 0 THEN file_sha256
+			ELSE CAST('msg:' || device_id || '/' || chat_jid || '/' || id AS BLOB)
+		END
+		WHERE length(file_sha256) > 0 OR COALESCE(url, '') != '' OR length(media_key) > 0
+			OR length(file_enc_sha256) > 0 OR COALESCE(file_length, 0) > 0`,
+
+		// Migration 49: Look up the messages referencing a media blob
+		`CREATE INDEX IF NOT EXISTS idx_messages_media ON messages(device_id, media_sha256)`,
+
+		// Migration 50: Move the media fields of existing messages into the media table,
+		// keeping the most recent copy of each file
+		`INSERT OR IGNORE INTO media (device_id, file_sha256, url, media_key, file_enc_sha256, file_length, ref_count)
+		SELECT m.device_id, m.media_sha256, COALESCE(m.url, ''), m.media_key, m.file_enc_sha256, COALESCE(m.file_length, 0),
+			(SELECT COUNT(*) FROM messages c WHERE c.device_id = m.device_id AND c.media_sha256 = m.media_sha256)
+		FROM messages m
+		WHERE m.media_sha256 IS NOT NULL
+		ORDER BY m.timestamp DESC`,
+
+		// Migration 51-54: Formerly dropped the media fields moved by migration 50.
+		// dropMovedMediaColumns drops them after the migrations, once the move is
+		// verified, so these are kept as no-ops to preserve the numbering.
+		`SELECT 1`,
+		`SELECT 1`,
+		`SELECT 1`,
+		`SELECT 1`,
+
+		// Migration 55: Messages joined with their media fields, for reads
+		`CREATE VIEW IF NOT EXISTS message_rows AS
+		SELECT m.*, COALESCE(md.url, '') AS url, md.media_key AS media_key,
+			md.file_enc_sha256 AS file_enc_sha256, COALESCE(md.file_length, 0) AS file_length
+		FROM messages m
+		LEFT JOIN media md ON md.device_id = m.device_id AND md.file_sha256 = m.media_sha256`,
+
+		// Migration 56-58: Keep media.ref_count in step with the messages referencing it
+		`CREATE TRIGGER IF NOT EXISTS trg_messages_media_insert AFTER INSERT ON messages
+		WHEN NEW.media_sha256 IS NOT NULL
+		BEGIN
+			UPDATE media SET ref_count = ref_count + 1 WHERE device_id = NEW.device_id AND file_sha256 = NEW.media_sha256;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_messages_media_delete AFTER DELETE ON messages
+		WHEN OLD.media_sha256 IS NOT NULL
+		BEGIN
+			UPDATE media SET ref_count = ref_count - 1 WHERE device_id = OLD.device_id AND file_sha256 = OLD.media_sha256;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_messages_media_update AFTER UPDATE OF device_id, media_sha256 ON messages
+		WHEN OLD.device_id IS NOT NEW.device_id OR OLD.media_sha256 IS NOT NEW.media_sha256
+		BEGIN
+			UPDATE media SET ref_count = ref_count - 1 WHERE device_id = OLD.device_id AND file_sha256 = OLD.media_sha256;
+			UPDATE media SET ref_count = ref_count + 1 WHERE device_id = NEW.device_id AND file_sha256 = NEW.media_sha256;
+		END`,
+
+		// Migration 59: Unix time a chat was moved to the trash, 0 when it is not trashed
+		`ALTER TABLE chats ADD COLUMN deleted_at INTEGER DEFAULT 0`,
+
+		// Migration 60: Find trashed chats due for purging
+		`CREATE INDEX IF NOT EXISTS idx_chats_deleted_at ON chats(deleted_at)`,
+
+		// Migration 61: Webhook events written with the messages they describe (see EnableEventsOutbox)
+		`CREATE TABLE IF NOT EXISTS events_outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			chat_jid VARCHAR(255) NOT NULL DEFAULT '',
+			message_id VARCHAR(255) NOT NULL DEFAULT '',
+			event_name VARCHAR(80) NOT NULL DEFAULT '',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			delivered_at TIMESTAMP NULL,
+			UNIQUE(device_id, message_id, event_name)
+		)`,
+
+		// Migration 62: Fetch due outbox events in stable order
+		`CREATE INDEX IF NOT EXISTS idx_events_outbox_due ON events_outbox(delivered_at, next_attempt_at, id)`,
+
+		// Migration 63: Failed webhook calls retried per URL, kept as dead letters once out of attempts
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			url TEXT NOT NULL DEFAULT '',
+			event_name VARCHAR(80) NOT NULL DEFAULT '',
+			payload_json TEXT NOT NULL DEFAULT '',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMP NOT NULL,
+			dead_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 64: Fetch due webhook deliveries in stable order
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(dead_at, next_attempt_at, id)`,
+
+		// Migration 65: Unsent compositions, one per chat and device
+		`CREATE TABLE IF NOT EXISTS drafts (
+			device_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			content TEXT NOT NULL DEFAULT '',
+			reply_message_id VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, chat_jid)
+		)`,
+
+		// Migration 66: Reusable message bodies with placeholders and optional header media
+		`CREATE TABLE IF NOT EXISTS message_templates (
+			device_id VARCHAR(255) NOT NULL,
+			name VARCHAR(64) NOT NULL,
+			body TEXT NOT NULL DEFAULT '',
+			header_type VARCHAR(20) NOT NULL DEFAULT '',
+			header_url TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, name)
+		)`,
+
+		// Migration 67: Results of sends made with an idempotency key, returned on retries
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			device_id VARCHAR(255) NOT NULL,
+			key VARCHAR(255) NOT NULL,
+			operation VARCHAR(50) NOT NULL DEFAULT '',
+			message_id VARCHAR(255) NOT NULL DEFAULT '',
+			response TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, key)
+		)`,
+
+		// Migration 68: Expire idempotency keys by age
+		`CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at)`,
+
+		// Migration 69: Messages waiting to be sent, in queue order
+		`CREATE TABLE IF NOT EXISTS outgoing_messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			device_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			message_id VARCHAR(255) NOT NULL,
+			payload BLOB NOT NULL,
+			content TEXT NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+
+		// Migration 70: Fetch pending outgoing messages in queue order
+		`CREATE INDEX IF NOT EXISTS idx_outgoing_messages_status ON outgoing_messages(status, id)`,
+
+		// Migration 71: Messages pinned in chats, until WhatsApp unpins them
+		`CREATE TABLE IF NOT EXISTS message_pins (
+			device_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			message_id VARCHAR(255) NOT NULL,
+			pinned_by VARCHAR(255) NOT NULL DEFAULT '',
+			pinned_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, chat_jid, message_id)
+		)`,
+
+		// Migration 72: LID to phone number mappings resolved by WhatsApp
+		`CREATE TABLE IF NOT EXISTS lid_mappings (
+			lid VARCHAR(255) PRIMARY KEY,
+			phone_jid VARCHAR(255) NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+
+		// Migration 73: Look mappings up by phone number
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_lid_mappings_phone_jid ON lid_mappings(phone_jid)`,
+
+		// Migration 74: Find the oldest pending outgoing message of every chat
+		`CREATE INDEX IF NOT EXISTS idx_outgoing_messages_chat ON outgoing_messages(status, device_id, chat_jid, id)`,
 	}
 }
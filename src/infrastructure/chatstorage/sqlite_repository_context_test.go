@@ -0,0 +1,85 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func messageIDs(messages []*domainChatStorage.Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+func storeContextMessage(t *testing.T, repo *SQLiteRepository, id, chatJID, deviceID, content string, ts time.Time) {
+	t.Helper()
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: id, ChatJID: chatJID, DeviceID: deviceID, Sender: chatJID, Content: content, Timestamp: ts,
+	}))
+}
+
+func TestGetMessageByIDScopes(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	storeContextMessage(t, repo, "m1", "chat-a@s.whatsapp.net", "device-a", "a", now)
+	storeContextMessage(t, repo, "m1", "chat-b@s.whatsapp.net", "device-b", "b", now)
+
+	message, err := repo.GetMessageByID("m1", "device-b", "")
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Equal(t, "chat-b@s.whatsapp.net", message.ChatJID)
+
+	message, err = repo.GetMessageByID("m1", "", "chat-a@s.whatsapp.net")
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Equal(t, "device-a", message.DeviceID)
+
+	message, err = repo.GetMessageByID("m1", "device-a", "chat-b@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.Nil(t, message)
+
+	message, err = repo.GetMessageByID("m1", "", "")
+	require.NoError(t, err)
+	assert.NotNil(t, message)
+}
+
+func TestGetMessageWithContext(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	chatJID := "628123456789@s.whatsapp.net"
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	storeContextMessage(t, repo, "m1", chatJID, "device-a", "1", now)
+	storeContextMessage(t, repo, "m2", chatJID, "device-a", "2", now.Add(time.Minute))
+	// m3 shares m4's timestamp: the id breaks the tie.
+	storeContextMessage(t, repo, "m3", chatJID, "device-a", "3", now.Add(2*time.Minute))
+	storeContextMessage(t, repo, "m4", chatJID, "device-a", "4", now.Add(2*time.Minute))
+	storeContextMessage(t, repo, "m5", chatJID, "device-a", "5", now.Add(3*time.Minute))
+	storeContextMessage(t, repo, "m6", chatJID, "device-a", "6", now.Add(4*time.Minute))
+	storeContextMessage(t, repo, "other", "other@s.whatsapp.net", "device-a", "x", now.Add(2*time.Minute))
+	storeContextMessage(t, repo, "m9", chatJID, "device-b", "y", now.Add(2*time.Minute))
+
+	result, err := repo.GetMessageWithContext("device-a", chatJID, "m4", 2, 1)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "m4", result.Message.ID)
+	assert.Equal(t, []string{"m2", "m3"}, messageIDs(result.Before))
+	assert.Equal(t, []string{"m5"}, messageIDs(result.After))
+
+	result, err = repo.GetMessageWithContext("device-a", chatJID, "m1", 5, 0)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Empty(t, result.Before)
+	assert.Empty(t, result.After)
+
+	result, err = repo.GetMessageWithContext("device-a", chatJID, "m9", 1, 1)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = repo.GetMessageWithContext("", chatJID, "m4", 1, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package chatstorage
+
+import (
+	"strings"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrafts(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	alice := "628111@s.whatsapp.net"
+	group := "120363000000000001@g.us"
+
+	missing, err := repo.GetDraft("device-a", alice)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, repo.StoreDraft(&domainChatStorage.Draft{DeviceID: "device-a", ChatJID: alice, Content: "See you"}))
+	require.NoError(t, repo.StoreDraft(&domainChatStorage.Draft{DeviceID: "device-a", ChatJID: group, Content: "Agenda:"}))
+	// Saving again replaces the draft and keeps its creation time.
+	first, err := repo.GetDraft("device-a", alice)
+	require.NoError(t, err)
+	require.NoError(t, repo.StoreDraft(&domainChatStorage.Draft{DeviceID: "device-a", ChatJID: alice, Content: "See you at 9", ReplyMessageID: "m1"}))
+
+	draft, err := repo.GetDraft("device-a", alice)
+	require.NoError(t, err)
+	require.NotNil(t, draft)
+	assert.Equal(t, "See you at 9", draft.Content)
+	assert.Equal(t, "m1", draft.ReplyMessageID)
+	assert.True(t, draft.CreatedAt.Equal(first.CreatedAt))
+	assert.False(t, draft.UpdatedAt.Before(first.UpdatedAt))
+
+	drafts, err := repo.GetDrafts("device-a")
+	require.NoError(t, err)
+	require.Len(t, drafts, 2)
+	assert.Equal(t, alice, drafts[0].ChatJID, "most recently updated first")
+
+	// Drafts are per device.
+	other, err := repo.GetDrafts("device-b")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	deleted, err := repo.DeleteDraft("device-a", alice)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = repo.DeleteDraft("device-a", alice)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	// Deleting a chat deletes its draft, only for that device.
+	require.NoError(t, repo.StoreDraft(&domainChatStorage.Draft{DeviceID: "device-b", ChatJID: group, Content: "Agenda:"}))
+	require.NoError(t, repo.DeleteChatByDevice("device-a", group))
+	draft, err = repo.GetDraft("device-a", group)
+	require.NoError(t, err)
+	assert.Nil(t, draft)
+	draft, err = repo.GetDraft("device-b", group)
+	require.NoError(t, err)
+	assert.NotNil(t, draft)
+	require.NoError(t, repo.DeleteChat(group))
+	draft, err = repo.GetDraft("device-b", group)
+	require.NoError(t, err)
+	assert.Nil(t, draft)
+
+	require.NoError(t, repo.StoreDraft(&domainChatStorage.Draft{DeviceID: "device-a", ChatJID: alice, Content: "See you"}))
+	require.NoError(t, repo.DeleteDeviceData("device-a"))
+	drafts, err = repo.GetDrafts("device-a")
+	require.NoError(t, err)
+	assert.Empty(t, drafts)
+}
+
+func TestDraftsAreEncrypted(t *testing.T) {
+	_, db := newTestRepo(t)
+	encrypted, err := NewEncryptedStorageRepository(db, "correct horse battery staple")
+	require.NoError(t, err)
+	repo := encrypted.(*SQLiteRepository)
+	require.NoError(t, repo.InitializeSchema())
+
+	require.NoError(t, repo.StoreDraft(&domainChatStorage.Draft{DeviceID: "device-a", ChatJID: "628111@s.whatsapp.net", Content: "The code is 1234"}))
+
+	var content string
+	require.NoError(t, db.QueryRow(`SELECT content FROM drafts`).Scan(&content))
+	assert.True(t, strings.HasPrefix(content, encryptedPrefix))
+
+	draft, err := repo.GetDraft("device-a", "628111@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.Equal(t, "The code is 1234", draft.Content)
+}
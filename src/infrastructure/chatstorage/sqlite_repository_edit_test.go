@@ -99,7 +99,7 @@ func (suite *SQLiteRepositoryEditTestSuite) TestCreateMessageStoresEditHistoryAn
 
 			require.NoError(t, suite.repo.CreateMessage(suite.ctx, edited))
 
-			got, err := suite.repo.GetMessageByID(tc.originalMessageID)
+			got, err := suite.repo.GetMessageByID(tc.originalMessageID, "", "")
 			require.NoError(t, err)
 			require.NotNil(t, got)
 			assert.Equal(t, tc.editedContent, got.Content)
@@ -136,6 +136,66 @@ func (suite *SQLiteRepositoryEditTestSuite) TestCreateMessageStoresEditHistoryAn
 	}
 }
 
+func (suite *SQLiteRepositoryEditTestSuite) TestCreateMessageCaptionEditPreservesMediaMetadata() {
+	t := suite.T()
+	chat := types.NewJID("123", types.DefaultUserServer)
+	originalTimestamp := time.Date(2026, time.May, 16, 10, 0, 0, 0, time.UTC)
+
+	original := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+			ID:            "IMG-1",
+			Timestamp:     originalTimestamp,
+		},
+		Message: &waE2E.Message{
+			ImageMessage: &waE2E.ImageMessage{
+				Caption:    editProtoString("sunset"),
+				URL:        editProtoString("https://mmg.whatsapp.net/image"),
+				MediaKey:   []byte("media-key"),
+				FileLength: editProtoUint64(2048),
+			},
+		},
+	}
+	require.NoError(t, suite.repo.CreateMessage(suite.ctx, original))
+
+	edited := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+			ID:            "EDIT-IMG-1",
+			Timestamp:     originalTimestamp.Add(time.Minute),
+		},
+		Message: &waE2E.Message{
+			ProtocolMessage: &waE2E.ProtocolMessage{
+				Type: waE2E.ProtocolMessage_MESSAGE_EDIT.Enum(),
+				Key: &waCommon.MessageKey{
+					ID:        editProtoString("IMG-1"),
+					RemoteJID: editProtoString("123@s.whatsapp.net"),
+					FromMe:    editProtoBool(false),
+				},
+				EditedMessage: &waE2E.Message{
+					ImageMessage: &waE2E.ImageMessage{Caption: editProtoString("sunset at the beach")},
+				},
+			},
+		},
+	}
+	require.NoError(t, suite.repo.CreateMessage(suite.ctx, edited))
+
+	got, err := suite.repo.GetMessageByIDAndDevice("device-1", "IMG-1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "sunset at the beach", got.Content)
+	assert.Equal(t, "image", got.MediaType)
+	assert.Equal(t, "https://mmg.whatsapp.net/image", got.URL)
+	assert.Equal(t, []byte("media-key"), got.MediaKey)
+	assert.Equal(t, uint64(2048), got.FileLength)
+
+	edits, err := suite.repo.GetMessageEdits("IMG-1", "device-1")
+	require.NoError(t, err)
+	require.Len(t, edits, 1)
+	assert.Equal(t, "sunset", edits[0].PreviousContent)
+	assert.Equal(t, "sunset at the beach", edits[0].NewContent)
+}
+
 func TestSQLiteRepositoryEditTestSuite(t *testing.T) {
 	suite.Run(t, new(SQLiteRepositoryEditTestSuite))
 }
@@ -162,6 +222,10 @@ func editProtoString(value string) *string {
 	return &value
 }
 
+func editProtoUint64(value uint64) *uint64 {
+	return &value
+}
+
 func editProtoBool(value bool) *bool {
 	return &value
 }
@@ -0,0 +1,70 @@
+package chatstorage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptionMigratesPlaintextAndRoundTrips(t *testing.T) {
+	plain, db := newTestRepo(t)
+	chatJID := "628123456789@s.whatsapp.net"
+	now := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, plain.StoreMessage(&domainChatStorage.Message{
+		ID: "m1", ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+		Content: "Meet at the harbour", Timestamp: now,
+		MediaType: "image", MediaKey: []byte("media-key-bytes"),
+	}))
+	require.NoError(t, plain.StoreMessageEdit(&domainChatStorage.MessageEdit{
+		OriginalMessageID: "m1", EditEventID: "e1", ChatJID: chatJID, DeviceID: "device-a",
+		PreviousContent: "Meet at the port", NewContent: "Meet at the harbour", EditedAt: now,
+	}))
+
+	encrypted, err := NewEncryptedStorageRepository(db, "correct horse battery staple")
+	require.NoError(t, err)
+	repo := encrypted.(*SQLiteRepository)
+	require.NoError(t, repo.InitializeSchema())
+
+	// Existing rows are sealed in place.
+	var content, previous string
+	var mediaKey []byte
+	require.NoError(t, db.QueryRow(`SELECT content FROM messages WHERE id = 'm1'`).Scan(&content))
+	require.NoError(t, db.QueryRow(`SELECT media_key FROM media`).Scan(&mediaKey))
+	require.NoError(t, db.QueryRow(`SELECT previous_content FROM message_edits WHERE edit_event_id = 'e1'`).Scan(&previous))
+	assert.True(t, strings.HasPrefix(content, encryptedPrefix))
+	assert.True(t, strings.HasPrefix(string(mediaKey), encryptedPrefix))
+	assert.True(t, strings.HasPrefix(previous, encryptedPrefix))
+
+	// New rows are sealed on write and everything reads back as plaintext.
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "m2", ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+		Content: "See you there", Timestamp: now.Add(time.Minute),
+	}))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM messages WHERE content NOT LIKE 'enc:v1:%'`))
+
+	message, err := repo.GetMessageByIDAndDevice("device-a", "m1")
+	require.NoError(t, err)
+	assert.Equal(t, "Meet at the harbour", message.Content)
+	assert.Equal(t, []byte("media-key-bytes"), message.MediaKey)
+
+	edits, err := repo.GetMessageEdits("m1", "device-a")
+	require.NoError(t, err)
+	require.Len(t, edits, 1)
+	assert.Equal(t, "Meet at the port", edits[0].PreviousContent)
+
+	results, err := repo.SearchMessages("device-a", chatJID, "HARBOUR", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "m1", results[0].ID)
+
+	// Re-running the migration leaves sealed rows alone, and a different key is rejected.
+	require.NoError(t, repo.InitializeSchema())
+	wrong, err := NewEncryptedStorageRepository(db, "another key")
+	require.NoError(t, err)
+	assert.ErrorIs(t, wrong.InitializeSchema(), errWrongEncryptionKey)
+}
@@ -0,0 +1,59 @@
+package chatstorage
+
+import (
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupSnapshotRoundTripAndCounts(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	groupJID := "120363000000000001@g.us"
+
+	missing, err := repo.GetGroupSnapshot("device-a", groupJID)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, repo.StoreGroupSnapshot(&domainChatStorage.GroupSnapshot{
+		DeviceID: "device-a", GroupJID: groupJID, Name: "Team", OwnerJID: "628111@s.whatsapp.net",
+		EphemeralExpiration: 86400, Announce: true,
+		Participants: []domainChatStorage.GroupParticipant{
+			{JID: "628111@s.whatsapp.net", IsAdmin: true, IsSuperAdmin: true},
+			{JID: "628222@s.whatsapp.net"},
+		},
+	}))
+	// Storing again replaces the previous snapshot.
+	require.NoError(t, repo.StoreGroupSnapshot(&domainChatStorage.GroupSnapshot{
+		DeviceID: "device-a", GroupJID: groupJID, Name: "Team (renamed)", OwnerJID: "628111@s.whatsapp.net",
+		Participants: []domainChatStorage.GroupParticipant{
+			{JID: "628111@s.whatsapp.net", IsAdmin: true, IsSuperAdmin: true},
+			{JID: "628222@s.whatsapp.net", IsAdmin: true},
+			{JID: "628333@s.whatsapp.net"},
+		},
+	}))
+
+	snapshot, err := repo.GetGroupSnapshot("device-a", groupJID)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "Team (renamed)", snapshot.Name)
+	assert.False(t, snapshot.Announce)
+	require.Len(t, snapshot.Participants, 3)
+	assert.Equal(t, domainChatStorage.GroupRoleAdmin, snapshot.ParticipantRole("628222@s.whatsapp.net"))
+	assert.Equal(t, domainChatStorage.GroupRoleNone, snapshot.ParticipantRole("628999@s.whatsapp.net"))
+
+	counts, err := repo.GetGroupParticipantCounts("device-a", []string{groupJID, "120363000000000002@g.us"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{groupJID: 3}, counts)
+
+	// Snapshots are per device.
+	counts, err = repo.GetGroupParticipantCounts("device-b", []string{groupJID})
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+
+	require.NoError(t, repo.DeleteDeviceData("device-a"))
+	snapshot, err = repo.GetGroupSnapshot("device-a", groupJID)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+}
@@ -0,0 +1,59 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	now := time.Now()
+	dayAgo := now.Add(-24 * time.Hour)
+
+	missing, err := repo.GetIdempotencyKey("device-a", "order-1", dayAgo)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, repo.StoreIdempotencyKey(&domainChatStorage.IdempotencyKey{
+		DeviceID: "device-a", Key: "order-1", Operation: "send/message", MessageID: "m1",
+		Response: `{"message_id":"m1"}`, CreatedAt: now,
+	}))
+	require.NoError(t, repo.StoreIdempotencyKey(&domainChatStorage.IdempotencyKey{
+		DeviceID: "device-a", Key: "order-0", Operation: "send/message", MessageID: "m0",
+		Response: `{"message_id":"m0"}`, CreatedAt: now.Add(-48 * time.Hour),
+	}))
+
+	record, err := repo.GetIdempotencyKey("device-a", "order-1", dayAgo)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "send/message", record.Operation)
+	assert.Equal(t, "m1", record.MessageID)
+	assert.Equal(t, `{"message_id":"m1"}`, record.Response)
+
+	// Keys are per device, and expired keys are not returned.
+	other, err := repo.GetIdempotencyKey("device-b", "order-1", dayAgo)
+	require.NoError(t, err)
+	assert.Nil(t, other)
+	expired, err := repo.GetIdempotencyKey("device-a", "order-0", dayAgo)
+	require.NoError(t, err)
+	assert.Nil(t, expired)
+
+	// Storing an expired key again replaces it.
+	require.NoError(t, repo.StoreIdempotencyKey(&domainChatStorage.IdempotencyKey{
+		DeviceID: "device-a", Key: "order-0", Operation: "send/image", MessageID: "m2",
+		Response: `{"message_id":"m2"}`, CreatedAt: now,
+	}))
+	replaced, err := repo.GetIdempotencyKey("device-a", "order-0", dayAgo)
+	require.NoError(t, err)
+	require.NotNil(t, replaced)
+	assert.Equal(t, "send/image", replaced.Operation)
+	assert.Equal(t, "m2", replaced.MessageID)
+
+	deleted, err := repo.DeleteIdempotencyKeysBefore(now.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
@@ -0,0 +1,56 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrityChecksAndRestore(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	active := "628111@s.whatsapp.net"
+	empty := "628222@s.whatsapp.net"
+	archivedEmpty := "628333@s.whatsapp.net"
+	orphan := "628444@s.whatsapp.net"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, jid := range []string{active, empty, archivedEmpty} {
+		require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{DeviceID: "device-a", JID: jid, Name: jid, LastMessageTime: now}))
+	}
+	archived := true
+	require.NoError(t, repo.UpdateChatState("device-a", archivedEmpty, domainChatStorage.ChatStateUpdate{Archived: &archived}))
+	// The same empty chat on another device is not device-a's concern.
+	require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{DeviceID: "device-b", JID: orphan, Name: orphan, LastMessageTime: now}))
+
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{ID: "m1", ChatJID: active, DeviceID: "device-a", Sender: active, Content: "hi", Timestamp: now}))
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{ID: "m2", ChatJID: orphan, DeviceID: "device-a", Sender: orphan, Content: "one", Timestamp: now.Add(-time.Hour)}))
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{ID: "m3", ChatJID: orphan, DeviceID: "device-a", Sender: orphan, Content: "two", Timestamp: now}))
+
+	emptyChats, err := repo.GetEmptyChats("device-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{empty}, emptyChats)
+
+	missing, err := repo.GetMissingChats("device-a")
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	assert.Equal(t, orphan, missing[0].ChatJID)
+	assert.Equal(t, 2, missing[0].MessageCount)
+	assert.True(t, missing[0].LastMessageTime.Equal(now), "got %s", missing[0].LastMessageTime)
+
+	restored, err := repo.RestoreMissingChats("device-a")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, restored)
+
+	chat, err := repo.GetChatByDevice("device-a", orphan)
+	require.NoError(t, err)
+	require.NotNil(t, chat)
+	assert.Equal(t, "628444", chat.Name)
+	assert.True(t, chat.LastMessageTime.Equal(now))
+
+	missing, err = repo.GetMissingChats("device-a")
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
@@ -0,0 +1,60 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobsAreStoredListedAndFailedOnRestart(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	started := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	done := &domainChatStorage.Job{ID: "job-1", Type: "chat_export", DeviceID: "device-a", Status: domainChatStorage.JobStatusRunning, CreatedAt: started, StartedAt: started}
+	require.NoError(t, repo.StoreJob(done))
+	done.Status = domainChatStorage.JobStatusCompleted
+	done.Completed, done.Total = 10, 10
+	done.Result, done.ResultPath = "chat-628.json", "storages/exports/x-chat-628.json"
+	done.FinishedAt = started.Add(time.Minute)
+	require.NoError(t, repo.StoreJob(done))
+	require.NoError(t, repo.StoreJob(&domainChatStorage.Job{ID: "job-2", Type: "search_reindex", Status: domainChatStorage.JobStatusRunning, CreatedAt: started.Add(time.Hour), StartedAt: started.Add(time.Hour)}))
+
+	got, err := repo.GetJob("job-1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, domainChatStorage.JobStatusCompleted, got.Status)
+	assert.Equal(t, 10, got.Completed)
+	assert.Equal(t, "storages/exports/x-chat-628.json", got.ResultPath)
+	assert.True(t, got.FinishedAt.Equal(started.Add(time.Minute)))
+
+	all, err := repo.GetJobs(nil)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "job-2", all[0].ID, "newest first")
+	assert.True(t, all[0].FinishedAt.IsZero())
+
+	exports, err := repo.GetJobs(&domainChatStorage.JobFilter{DeviceIDs: []string{"device-a"}})
+	require.NoError(t, err)
+	require.Len(t, exports, 1)
+	assert.Equal(t, "job-1", exports[0].ID)
+	visible, err := repo.GetJobs(&domainChatStorage.JobFilter{DeviceIDs: []string{"device-b", ""}})
+	require.NoError(t, err)
+	require.Len(t, visible, 1)
+	assert.Equal(t, "job-2", visible[0].ID)
+
+	n, err := repo.FailActiveJobs("interrupted by restart")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	reindex, err := repo.GetJobs(&domainChatStorage.JobFilter{Type: "search_reindex"})
+	require.NoError(t, err)
+	require.Len(t, reindex, 1)
+	assert.Equal(t, domainChatStorage.JobStatusFailed, reindex[0].Status)
+	assert.Equal(t, "interrupted by restart", reindex[0].Error)
+
+	missing, err := repo.GetJob("nope")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,71 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelsAndAssociations(t *testing.T) {
+	repo, db := newTestRepo(t)
+	customer := "628111@s.whatsapp.net"
+	group := "120363000000000001@g.us"
+	now := time.Now()
+
+	for _, jid := range []string{customer, group} {
+		require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{DeviceID: "device-a", JID: jid, Name: jid, LastMessageTime: now}))
+	}
+	require.NoError(t, repo.StoreLabel(&domainChatStorage.Label{DeviceID: "device-a", ID: "2", Name: "Pending payment", Color: 3, OrderIndex: 1}))
+	require.NoError(t, repo.StoreLabel(&domainChatStorage.Label{DeviceID: "device-a", ID: "1", Name: "New customer", PredefinedID: 1}))
+	// Editing a label updates it in place.
+	require.NoError(t, repo.StoreLabel(&domainChatStorage.Label{DeviceID: "device-a", ID: "1", Name: "New client", PredefinedID: 1}))
+
+	require.NoError(t, repo.SetChatLabel("device-a", customer, "1", true))
+	require.NoError(t, repo.SetChatLabel("device-a", customer, "1", true)) // idempotent
+	require.NoError(t, repo.SetChatLabel("device-a", customer, "2", true))
+	require.NoError(t, repo.SetChatLabel("device-a", group, "2", true))
+	require.NoError(t, repo.SetChatLabel("device-a", group, "2", false))
+	require.NoError(t, repo.SetMessageLabel("device-a", customer, "msg-1", "2", true))
+
+	labels, err := repo.GetLabels("device-a")
+	require.NoError(t, err)
+	require.Len(t, labels, 2)
+	assert.Equal(t, "1", labels[0].ID)
+	assert.Equal(t, "New client", labels[0].Name)
+	assert.Equal(t, 1, labels[0].ChatCount)
+	assert.Equal(t, "2", labels[1].ID)
+	assert.Equal(t, 1, labels[1].ChatCount)
+
+	chatLabels, err := repo.GetChatLabelIDs("device-a", []string{customer, group})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{customer: {"1", "2"}}, chatLabels)
+
+	messageLabels, err := repo.GetMessageLabelIDs("device-a", customer, []string{"msg-1", "msg-2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"msg-1": {"2"}}, messageLabels)
+
+	labeled, err := repo.GetChats(&domainChatStorage.ChatFilter{DeviceID: "device-a", LabelID: "1"})
+	require.NoError(t, err)
+	require.Len(t, labeled, 1)
+	assert.Equal(t, customer, labeled[0].JID)
+	count, err := repo.GetFilteredChatCount(&domainChatStorage.ChatFilter{DeviceID: "device-a", LabelID: "2"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	// Labels are per device.
+	other, err := repo.GetLabels("device-b")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	// Deleting a label drops its associations.
+	require.NoError(t, repo.DeleteLabel("device-a", "2"))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM chat_labels WHERE label_id = '2'`))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM message_labels`))
+
+	require.NoError(t, repo.DeleteDeviceData("device-a"))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM labels`))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM chat_labels`))
+}
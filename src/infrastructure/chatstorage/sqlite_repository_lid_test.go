@@ -0,0 +1,47 @@
+package chatstorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLIDMappingsAreLookedUpBothWays(t *testing.T) {
+	repo, _ := newTestRepo(t)
+
+	mapping, err := repo.GetLIDMapping("100000000000001@lid")
+	require.NoError(t, err)
+	assert.Nil(t, mapping)
+
+	require.NoError(t, repo.StoreLIDMapping("100000000000001@lid", "628123456789@s.whatsapp.net"))
+
+	mapping, err = repo.GetLIDMapping("100000000000001@lid")
+	require.NoError(t, err)
+	require.NotNil(t, mapping)
+	assert.Equal(t, "628123456789@s.whatsapp.net", mapping.PhoneJID)
+	assert.False(t, mapping.UpdatedAt.IsZero())
+
+	mapping, err = repo.GetLIDMappingByPhone("628123456789@s.whatsapp.net")
+	require.NoError(t, err)
+	require.NotNil(t, mapping)
+	assert.Equal(t, "100000000000001@lid", mapping.LID)
+}
+
+func TestLIDMappingReplacesPreviousLIDOfPhone(t *testing.T) {
+	repo, _ := newTestRepo(t)
+
+	require.NoError(t, repo.StoreLIDMapping("100000000000001@lid", "628123456789@s.whatsapp.net"))
+	require.NoError(t, repo.StoreLIDMapping("100000000000002@lid", "628123456789@s.whatsapp.net"))
+
+	mapping, err := repo.GetLIDMapping("100000000000001@lid")
+	require.NoError(t, err)
+	assert.Nil(t, mapping)
+
+	mapping, err = repo.GetLIDMappingByPhone("628123456789@s.whatsapp.net")
+	require.NoError(t, err)
+	require.NotNil(t, mapping)
+	assert.Equal(t, "100000000000002@lid", mapping.LID)
+
+	assert.Error(t, repo.StoreLIDMapping("", "628123456789@s.whatsapp.net"))
+}
@@ -0,0 +1,140 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaIsStoredOncePerFileAndReferenceCounted(t *testing.T) {
+	repo, db := newTestRepo(t)
+	fileHash := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	store := func(id, chatJID, url string, at time.Time) {
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID: id, ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID, Timestamp: at,
+			MediaType: "image", URL: url, MediaKey: []byte("key-" + id), FileSHA256: fileHash,
+			FileEncSHA256: []byte("enc"), FileLength: 4096,
+		}))
+	}
+	store("m1", "111@s.whatsapp.net", "https://mmg.whatsapp.net/first", now)
+	store("m2", "222@s.whatsapp.net", "https://mmg.whatsapp.net/second", now.Add(time.Minute))
+	store("m3", "333@s.whatsapp.net", "", now.Add(2*time.Minute))
+
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM media`))
+	media, err := repo.GetMedia("device-a", fileHash)
+	require.NoError(t, err)
+	require.NotNil(t, media)
+	assert.Equal(t, 3, media.RefCount)
+	// The forward without a URL keeps the last downloadable copy.
+	assert.Equal(t, "https://mmg.whatsapp.net/second", media.URL)
+	assert.Equal(t, []byte("key-m2"), media.MediaKey)
+
+	message, err := repo.GetMessageByIDAndDevice("device-a", "m1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mmg.whatsapp.net/second", message.URL)
+	assert.Equal(t, uint64(4096), message.FileLength)
+	assert.Equal(t, now, message.Timestamp.UTC())
+
+	messages, err := repo.GetMessagesByMedia("device-a", fileHash)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.Equal(t, "m1", messages[0].ID)
+	assert.Equal(t, "m3", messages[2].ID)
+
+	require.NoError(t, repo.SetMediaLocalPath("device-a", fileHash, "/tmp/media/file.jpg"))
+	require.NoError(t, repo.DeleteMessageByDevice("device-a", "m1", "111@s.whatsapp.net"))
+	require.NoError(t, repo.DeleteChatByDevice("device-a", "222@s.whatsapp.net"))
+
+	deleted, err := repo.DeleteUnreferencedMedia("device-a")
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+	media, err = repo.GetMedia("device-a", fileHash)
+	require.NoError(t, err)
+	assert.Equal(t, 1, media.RefCount)
+	assert.Equal(t, "/tmp/media/file.jpg", media.LocalPath)
+
+	require.NoError(t, repo.DeleteMessageByDevice("device-a", "m3", "333@s.whatsapp.net"))
+	deleted, err = repo.DeleteUnreferencedMedia("device-a")
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, "/tmp/media/file.jpg", deleted[0].LocalPath)
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM media`))
+}
+
+func TestMediaMigrationMovesExistingMessageFields(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?_journal_mode=WAL&_busy_timeout=5000")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	repo := &SQLiteRepository{db: db}
+
+	// Build the schema as it was before the media table existed.
+	_, err = repo.getSchemaVersion()
+	require.NoError(t, err)
+	migrations := repo.getMigrations()
+	for i := 0; i < 45; i++ {
+		require.NoError(t, repo.runMigration(migrations[i], i+1))
+	}
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	for i, id := range []string{"old", "new"} {
+		_, err := db.Exec(`INSERT INTO messages (id, chat_jid, device_id, sender, content, timestamp, media_type, filename, url, media_key, file_sha256, file_length)
+			VALUES (?, 'c@s.whatsapp.net', 'device-a', 's', '', ?, 'image', '', ?, ?, X'AABB', 10)`,
+			id, now.Add(time.Duration(i)*time.Minute), "https://mmg.whatsapp.net/"+id, []byte("key-"+id))
+		require.NoError(t, err)
+	}
+	_, err = db.Exec(`INSERT INTO messages (id, chat_jid, device_id, sender, content, timestamp, media_type, filename, url)
+		VALUES ('nohash', 'c@s.whatsapp.net', 'device-a', 's', '', ?, 'document', '', 'https://mmg.whatsapp.net/doc')`, now)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.InitializeSchema())
+
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM media`))
+	media, err := repo.GetMedia("device-a", []byte{0xAA, 0xBB})
+	require.NoError(t, err)
+	require.NotNil(t, media)
+	assert.Equal(t, 2, media.RefCount)
+	assert.Equal(t, "https://mmg.whatsapp.net/new", media.URL)
+
+	message, err := repo.GetMessageByIDAndDevice("device-a", "nohash")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mmg.whatsapp.net/doc", message.URL)
+	assert.Equal(t, now, message.Timestamp.UTC())
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM pragma_table_info('messages') WHERE name = 'url'`))
+}
+
+func TestMediaMigrationKeepsLegacyColumnsWhenMoveIsIncomplete(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?_journal_mode=WAL&_busy_timeout=5000")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	repo := &SQLiteRepository{db: db}
+
+	_, err = repo.getSchemaVersion()
+	require.NoError(t, err)
+	migrations := repo.getMigrations()
+	for i := 0; i < 45; i++ {
+		require.NoError(t, repo.runMigration(migrations[i], i+1))
+	}
+	_, err = db.Exec(`INSERT INTO messages (id, chat_jid, device_id, sender, content, timestamp, media_type, filename, url, media_key, file_sha256, file_length)
+		VALUES ('img', 'c@s.whatsapp.net', 'device-a', 's', '', ?, 'image', '', 'https://mmg.whatsapp.net/img', X'01', X'AABB', 10)`, time.Now())
+	require.NoError(t, err)
+
+	// Lose the moved row, as a backfill that went wrong would.
+	for i := 45; i < 50; i++ {
+		require.NoError(t, repo.runMigration(migrations[i], i+1))
+	}
+	_, err = db.Exec(`DELETE FROM media`)
+	require.NoError(t, err)
+
+	require.Error(t, repo.InitializeSchema())
+	var url string
+	require.NoError(t, db.QueryRow(`SELECT url FROM messages WHERE id = 'img'`).Scan(&url))
+	assert.Equal(t, "https://mmg.whatsapp.net/img", url)
+}
@@ -0,0 +1,85 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeChats(t *testing.T) {
+	repo, db := newTestRepo(t)
+	// Production enables foreign keys; edits reference the messages being moved.
+	db.SetMaxOpenConns(1)
+	_, err := db.Exec(`PRAGMA foreign_keys = ON`)
+	require.NoError(t, err)
+	device := "dev1"
+	sourceJID := "215946727821336@lid"
+	targetJID := "5511999999999@s.whatsapp.net"
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	insertChat(t, db, device, sourceJID, "Alice", now)
+	insertChat(t, db, device, targetJID, "5511999999999", now.Add(-time.Hour))
+	_, err = db.Exec(`UPDATE chats SET unread_count = 2 WHERE jid IN (?, ?)`, sourceJID, targetJID)
+	require.NoError(t, err)
+
+	insertMessage(t, db, "m1", sourceJID, device, sourceJID, "hello", now.Add(-2*time.Minute))
+	insertMessage(t, db, "m2", sourceJID, device, sourceJID, "again", now.Add(-time.Minute))
+	insertMessage(t, db, "m2", targetJID, device, targetJID, "again", now.Add(-time.Minute))
+	insertMessage(t, db, "other", sourceJID, "dev2", sourceJID, "other device", now)
+	for _, stmt := range []string{
+		`INSERT INTO message_reactions (message_id, chat_jid, device_id, reactor_jid, emoji, reaction_timestamp) VALUES ('m1', '` + sourceJID + `', 'dev1', 'x@s.whatsapp.net', '+', CURRENT_TIMESTAMP)`,
+		`INSERT INTO message_edits (original_message_id, edit_event_id, chat_jid, device_id, editor, previous_content, new_content, edited_at) VALUES ('m1', 'e1', '` + sourceJID + `', 'dev1', 'x', 'hi', 'hello', CURRENT_TIMESTAMP)`,
+		`INSERT INTO chat_labels (device_id, label_id, chat_jid, created_at) VALUES ('dev1', '1', '` + sourceJID + `', 0), ('dev1', '1', '` + targetJID + `', 0), ('dev1', '2', '` + sourceJID + `', 0)`,
+		`INSERT INTO chatwoot_message_links (device_id, wa_message_id, wa_chat_jid) VALUES ('dev1', 'm1', '` + sourceJID + `')`,
+		`INSERT INTO drafts (device_id, chat_jid, content, created_at, updated_at) VALUES ('dev1', '` + sourceJID + `', 'unsent', 0, 0)`,
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	want := &domainChatStorage.ChatMergeSummary{
+		TargetExists:      true,
+		Messages:          1,
+		DuplicateMessages: 1,
+		Reactions:         1,
+		Edits:             1,
+		ChatLabels:        1,
+		ReadReceipts:      1,
+		Drafts:            1,
+	}
+
+	// A dry run reports the merge without changing anything.
+	summary, err := repo.MergeChats(device, sourceJID, targetJID, true)
+	require.NoError(t, err)
+	assert.Equal(t, want, summary)
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM messages WHERE chat_jid = ? AND device_id = ?`, sourceJID, device))
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM chats WHERE jid = ? AND device_id = ?`, sourceJID, device))
+
+	summary, err = repo.MergeChats(device, sourceJID, targetJID, false)
+	require.NoError(t, err)
+	assert.Equal(t, want, summary)
+
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM chats WHERE jid = ? AND device_id = ?`, sourceJID, device))
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM messages WHERE chat_jid = ? AND device_id = ?`, targetJID, device))
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM message_reactions WHERE chat_jid = ?`, targetJID))
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM message_edits WHERE chat_jid = ?`, targetJID))
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM chat_labels WHERE chat_jid = ?`, targetJID))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM chat_labels WHERE chat_jid = ?`, sourceJID))
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM chatwoot_message_links WHERE wa_chat_jid = ?`, targetJID))
+	// Other devices keep their own copy of the chat.
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM messages WHERE chat_jid = ? AND device_id = 'dev2'`, sourceJID))
+
+	var name string
+	var unread int
+	require.NoError(t, db.QueryRow(`SELECT name, unread_count FROM chats WHERE jid = ? AND device_id = ?`, targetJID, device).Scan(&name, &unread))
+	assert.Equal(t, "Alice", name)
+	assert.Equal(t, 4, unread)
+
+	_, err = repo.MergeChats(device, sourceJID, targetJID, false)
+	assert.ErrorIs(t, err, domainChatStorage.ErrChatNotFound)
+	_, err = repo.MergeChats(device, targetJID, targetJID, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxEventStoredWithMessage(t *testing.T) {
+	repo, db := newTestRepo(t)
+	chatJID := "628123456789@s.whatsapp.net"
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	message := func(id string) *domainChatStorage.Message {
+		return &domainChatStorage.Message{
+			ID: id, ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+			Content: "hello " + id, Timestamp: now, OutboxEvent: "message",
+		}
+	}
+
+	require.NoError(t, repo.StoreMessage(message("m1")))
+	// A redelivered message keeps its single event.
+	require.NoError(t, repo.StoreMessage(message("m1")))
+	require.NoError(t, repo.StoreMessagesBatch([]*domainChatStorage.Message{message("m2")}))
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "m3", ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID, Content: "no event", Timestamp: now,
+	}))
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM events_outbox`))
+
+	// Events wait for the live delivery before they are due.
+	due, err := repo.ListDueOutboxEvents(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = repo.ListDueOutboxEvents(time.Now().Add(outboxDispatchDelay+time.Second), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "m1", due[0].MessageID)
+	assert.Equal(t, chatJID, due[0].ChatJID)
+	assert.Equal(t, "message", due[0].EventName)
+
+	require.NoError(t, repo.MarkOutboxEventFailed(due[1].ID, "webhook down", time.Now().Add(time.Hour)))
+	require.NoError(t, repo.MarkOutboxEventDone("device-a", "m1", "message"))
+	due, err = repo.ListDueOutboxEvents(time.Now().Add(outboxDispatchDelay+time.Second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = repo.ListDueOutboxEvents(time.Now().Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "m2", due[0].MessageID)
+	assert.Equal(t, 1, due[0].Attempts)
+	assert.Equal(t, "webhook down", due[0].LastError)
+
+	pruned, err := repo.PruneOutboxEvents(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+	pruned, err = repo.PruneOutboxEvents(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, pruned)
+}
+
+func TestOutboxEventDroppedFromWriteBehindBuffer(t *testing.T) {
+	repo, db := newTestRepo(t)
+	repo.EnableWriteBehind(time.Hour, 1000)
+	chatJID := "628123456789@s.whatsapp.net"
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"m1", "m2"} {
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID: id, ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+			Content: "hello", Timestamp: now, OutboxEvent: "message",
+		}))
+	}
+	// Delivered while still buffered: the message is written without its event.
+	require.NoError(t, repo.MarkOutboxEventDone("device-a", "m1", "message"))
+	require.NoError(t, repo.FlushPendingWrites())
+
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM messages`))
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM events_outbox WHERE message_id = 'm2' AND delivered_at IS NULL`))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM events_outbox WHERE message_id = 'm1'`))
+}
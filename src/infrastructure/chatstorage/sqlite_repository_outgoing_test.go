@@ -0,0 +1,107 @@
+package chatstorage
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutgoingMessageQueue(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	enqueue := func(deviceID, chatJID, messageID string) *domainChatStorage.OutgoingMessage {
+		message := &domainChatStorage.OutgoingMessage{
+			DeviceID: deviceID, ChatJID: chatJID, MessageID: messageID,
+			Payload: []byte("payload of " + messageID), Content: "hello",
+		}
+		require.NoError(t, repo.EnqueueOutgoingMessage(message))
+		require.NotZero(t, message.ID)
+		return message
+	}
+
+	first := enqueue("device-a", "628111@s.whatsapp.net", "MSG1")
+	second := enqueue("device-a", "628111@s.whatsapp.net", "MSG2")
+	other := enqueue("device-b", "628222@s.whatsapp.net", "MSG3")
+	assert.Error(t, repo.EnqueueOutgoingMessage(&domainChatStorage.OutgoingMessage{DeviceID: "device-a"}))
+
+	// Only the oldest message of each chat is listed.
+	pending, err := repo.ListDueOutgoingMessages(time.Now())
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, []int64{first.ID, other.ID}, []int64{pending[0].ID, pending[1].ID})
+	assert.Equal(t, domainChatStorage.OutgoingMessagePending, pending[0].Status)
+	assert.Equal(t, []byte("payload of MSG1"), pending[0].Payload)
+	assert.Equal(t, "hello", pending[0].Content)
+
+	nextAttempt := time.Now().Add(time.Minute)
+	require.NoError(t, repo.MarkOutgoingMessageRetry(first.ID, "websocket not connected", nextAttempt))
+	require.NoError(t, repo.MarkOutgoingMessageFailed(second.ID, "server returned error 479"))
+	require.NoError(t, repo.MarkOutgoingMessageSent(other.ID))
+
+	// A message to retry stays pending, holding back its chat until it is
+	// due; failed and sent ones are not sent again.
+	pending, err = repo.ListDueOutgoingMessages(time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	pending, err = repo.ListDueOutgoingMessages(nextAttempt.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, first.ID, pending[0].ID)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "websocket not connected", pending[0].LastError)
+	assert.WithinDuration(t, nextAttempt, pending[0].NextAttemptAt, time.Second)
+
+	listed, err := repo.ListOutgoingMessages("device-a", "", 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, "MSG1", listed[0].MessageID)
+	assert.Equal(t, "MSG2", listed[1].MessageID)
+
+	listed, err = repo.ListOutgoingMessages("device-a", domainChatStorage.OutgoingMessageFailed, 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "server returned error 479", listed[0].LastError)
+
+	listed, err = repo.ListOutgoingMessages("device-b", "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	counts, err := repo.CountOutgoingMessages()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		domainChatStorage.OutgoingMessagePending: 1,
+		domainChatStorage.OutgoingMessageFailed:  1,
+	}, counts)
+
+	require.NoError(t, repo.DeleteDeviceData("device-a"))
+	counts, err = repo.CountOutgoingMessages()
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestOutgoingMessagesAreEncrypted(t *testing.T) {
+	_, db := newTestRepo(t)
+	encrypted, err := NewEncryptedStorageRepository(db, "correct horse battery staple")
+	require.NoError(t, err)
+	repo := encrypted.(*SQLiteRepository)
+	require.NoError(t, repo.InitializeSchema())
+
+	require.NoError(t, repo.EnqueueOutgoingMessage(&domainChatStorage.OutgoingMessage{
+		DeviceID: "device-a", ChatJID: "628111@s.whatsapp.net", MessageID: "MSG1",
+		Payload: []byte("The code is 1234"), Content: "The code is 1234",
+	}))
+
+	var payload []byte
+	require.NoError(t, db.QueryRow(`SELECT payload FROM outgoing_messages`).Scan(&payload))
+	assert.True(t, bytes.HasPrefix(payload, []byte(encryptedPrefix)))
+
+	pending, err := repo.ListDueOutgoingMessages(time.Now())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, []byte("The code is 1234"), pending[0].Payload)
+	assert.Equal(t, "The code is 1234", pending[0].Content)
+}
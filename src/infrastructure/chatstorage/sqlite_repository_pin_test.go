@@ -0,0 +1,96 @@
+package chatstorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func pinEvent(chat, sender types.JID, id, pinnedID string, pinType waE2E.PinInChatMessage_Type, ts time.Time, duration uint32) *events.Message {
+	msg := &waE2E.Message{
+		PinInChatMessage: &waE2E.PinInChatMessage{
+			Key:               &waCommon.MessageKey{ID: proto.String(pinnedID)},
+			Type:              pinType.Enum(),
+			SenderTimestampMS: proto.Int64(ts.UnixMilli()),
+		},
+	}
+	if duration > 0 {
+		msg.MessageContextInfo = &waE2E.MessageContextInfo{MessageAddOnDurationInSecs: proto.Uint32(duration)}
+	}
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: sender},
+			ID:            id,
+			Timestamp:     ts,
+		},
+		Message: msg,
+	}
+}
+
+func TestMessagePinsAreStoredHydratedAndRemoved(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	chat := types.NewJID("628123456789", types.DefaultUserServer)
+	now := time.Now().Truncate(time.Second)
+
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "msg-1", ChatJID: chat.String(), DeviceID: "device-a", Sender: chat.String(),
+		Content: "pin me", Timestamp: now.Add(-time.Minute),
+	}))
+
+	require.NoError(t, repo.CreateMessagePin(ctx, pinEvent(chat, chat, "pin-1", "msg-1", waE2E.PinInChatMessage_PIN_FOR_ALL, now, 86400)))
+
+	pins, err := repo.GetMessagePins("device-a", chat.String(), now)
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+	assert.Equal(t, "msg-1", pins[0].MessageID)
+	assert.Equal(t, chat.String(), pins[0].PinnedBy)
+	assert.True(t, pins[0].ExpiresAt.Equal(now.Add(24*time.Hour)), "expires at %s", pins[0].ExpiresAt)
+
+	messages, err := repo.GetMessages(&domainChatStorage.MessageFilter{DeviceID: "device-a", ChatJID: chat.String(), Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.NotNil(t, messages[0].Pin)
+	assert.Equal(t, chat.String(), messages[0].Pin.PinnedBy)
+
+	// Expired pins are no longer reported
+	pins, err = repo.GetMessagePins("device-a", chat.String(), now.Add(25*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, pins)
+
+	require.NoError(t, repo.CreateMessagePin(ctx, pinEvent(chat, chat, "pin-2", "msg-1", waE2E.PinInChatMessage_UNPIN_FOR_ALL, now.Add(time.Minute), 0)))
+	pins, err = repo.GetMessagePins("device-a", chat.String(), now)
+	require.NoError(t, err)
+	assert.Empty(t, pins)
+}
+
+func TestMessagePinWithoutDurationUsesDefault(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	chat := types.NewJID("120363025246125486", types.GroupServer)
+	alice := types.NewJID("628111111111", types.DefaultUserServer)
+	now := time.Now().Truncate(time.Second)
+
+	require.NoError(t, repo.CreateMessagePin(ctx, pinEvent(chat, alice, "pin-1", "msg-1", waE2E.PinInChatMessage_PIN_FOR_ALL, now, 0)))
+
+	pins, err := repo.GetMessagePins("device-a", chat.String(), now)
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+	assert.Equal(t, alice.String(), pins[0].PinnedBy)
+	assert.True(t, pins[0].ExpiresAt.Equal(now.Add(domainChatStorage.DefaultMessagePinDuration)))
+
+	require.NoError(t, repo.DeleteDeviceData("device-a"))
+	pins, err = repo.GetMessagePins("device-a", chat.String(), now)
+	require.NoError(t, err)
+	assert.Empty(t, pins)
+}
@@ -0,0 +1,102 @@
+package chatstorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func pollVoteEvent(chat, voter types.JID, id, pollID string, ts time.Time) *events.Message {
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: voter},
+			ID:            id,
+			Timestamp:     ts,
+		},
+		Message: &waE2E.Message{
+			PollUpdateMessage: &waE2E.PollUpdateMessage{
+				PollCreationMessageKey: &waCommon.MessageKey{ID: proto.String(pollID)},
+			},
+		},
+	}
+}
+
+func TestPollVotesAreStoredTalliedAndHydrated(t *testing.T) {
+	repo, db := newTestRepo(t)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	chat := types.NewJID("628123456789", types.DefaultUserServer)
+	alice := types.NewJID("628111111111", types.DefaultUserServer)
+	bob := types.NewJID("628222222222", types.DefaultUserServer)
+	now := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.CreateMessage(ctx, &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+			ID:            "poll-1",
+			Timestamp:     now,
+		},
+		Message: &waE2E.Message{
+			PollCreationMessageV3: &waE2E.PollCreationMessage{
+				Name: proto.String("Lunch?"),
+				Options: []*waE2E.PollCreationMessage_Option{
+					{OptionName: proto.String("Pizza")},
+					{OptionName: proto.String("Sushi")},
+				},
+				SelectableOptionsCount: proto.Uint32(1),
+			},
+		},
+	}))
+
+	vote := func(voter types.JID, id string, options ...string) {
+		t.Helper()
+		evt := pollVoteEvent(chat, voter, id, "poll-1", now.Add(time.Minute))
+		require.NoError(t, repo.CreatePollVote(ctx, evt, &waE2E.PollVoteMessage{
+			SelectedOptions: whatsmeow.HashPollOptions(options),
+		}))
+	}
+	vote(alice, "vote-1", "Pizza")
+	vote(bob, "vote-2", "Sushi")
+	// A later vote replaces the voter's earlier selection.
+	vote(alice, "vote-3", "Sushi")
+
+	results, err := repo.GetPollResults("device-a", "poll-1")
+	require.NoError(t, err)
+	require.NotNil(t, results)
+	assert.Equal(t, "Lunch?", results.Poll.Question)
+	assert.Equal(t, uint32(1), results.Poll.SelectableCount)
+	require.Len(t, results.Options, 2)
+	assert.Equal(t, 0, results.Options[0].Votes)
+	assert.Equal(t, 2, results.Options[1].Votes)
+	assert.ElementsMatch(t, []string{alice.String(), bob.String()}, results.Options[1].Voters)
+	assert.Equal(t, 2, results.TotalVoters)
+
+	// An empty selection retracts the vote.
+	vote(bob, "vote-4")
+
+	messages := getMessagesForTest(t, repo, "device-a", chat.String())
+	require.Len(t, messages, 1)
+	assert.Equal(t, "📊 Lunch?", messages[0].Content)
+	require.NotNil(t, messages[0].Poll)
+	assert.Equal(t, 1, messages[0].Poll.TotalVoters)
+	assert.Equal(t, []string{alice.String()}, messages[0].Poll.Options[1].Voters)
+
+	// Polls are device-scoped, and votes on unknown polls are skipped.
+	other, err := repo.GetPollResults("device-b", "poll-1")
+	require.NoError(t, err)
+	assert.Nil(t, other)
+	require.NoError(t, repo.CreatePollVote(ctx, pollVoteEvent(chat, bob, "vote-5", "poll-missing", now), &waE2E.PollVoteMessage{}))
+
+	require.NoError(t, repo.DeleteDeviceData("device-a"))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM polls`))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM poll_votes`))
+}
@@ -0,0 +1,62 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChatStatsAggregatesMessages(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	group := "120363000000000000@g.us"
+	alice := "628111111111@s.whatsapp.net"
+	bob := "628222222222@s.whatsapp.net"
+	me := "628999999999@s.whatsapp.net"
+	day := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	store := func(id, sender string, fromMe bool, mediaType string, at time.Time) {
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID: id, ChatJID: group, DeviceID: "device-a", Sender: sender, IsFromMe: fromMe,
+			Content: "hi " + id, MediaType: mediaType, Timestamp: at,
+		}))
+	}
+	store("m1", alice, false, "", day)
+	store("m2", alice, false, "image", day.Add(time.Hour))
+	store("m3", bob, false, "", day.Add(24*time.Hour))
+	store("m4", me, true, "", day.Add(48*time.Hour))
+	store("m5", alice, false, "", day.Add(48*time.Hour+time.Minute))
+	// Another device's copy of the chat is not counted.
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "x1", ChatJID: group, DeviceID: "device-b", Sender: bob, Content: "other", Timestamp: day,
+	}))
+
+	stats, err := repo.GetChatStats(&domainChatStorage.ChatStatsFilter{
+		DeviceID: "device-a", ChatJID: group, TopSenders: 2, Since: day.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, stats.TotalMessages)
+	assert.Equal(t, 1, stats.SentMessages)
+	assert.Equal(t, 4, stats.ReceivedMessages)
+	assert.Equal(t, []domainChatStorage.MediaTypeCount{{MediaType: "text", Count: 4}, {MediaType: "image", Count: 1}}, stats.MediaTypes)
+	assert.Equal(t, day, stats.FirstMessageTime.UTC())
+	assert.Equal(t, day.Add(48*time.Hour+time.Minute), stats.LastMessageTime.UTC())
+	assert.Equal(t, []domainChatStorage.SenderCount{{Sender: alice, Count: 3}, {Sender: bob, Count: 1}}, stats.TopSenders)
+	assert.Equal(t, []domainChatStorage.DailyCount{{Date: "2026-10-02", Count: 1}, {Date: "2026-10-03", Count: 2}}, stats.Daily)
+}
+
+func TestGetChatStatsEmptyChat(t *testing.T) {
+	repo, _ := newTestRepo(t)
+
+	stats, err := repo.GetChatStats(&domainChatStorage.ChatStatsFilter{DeviceID: "device-a", ChatJID: "628111111111@s.whatsapp.net"})
+	require.NoError(t, err)
+	assert.Zero(t, stats.TotalMessages)
+	assert.True(t, stats.FirstMessageTime.IsZero())
+	assert.Empty(t, stats.Daily)
+
+	_, err = repo.GetChatStats(&domainChatStorage.ChatStatsFilter{ChatJID: "628111111111@s.whatsapp.net"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package chatstorage
+
+import (
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageTemplates(t *testing.T) {
+	repo, _ := newTestRepo(t)
+
+	missing, err := repo.GetMessageTemplate("device-a", "welcome")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, repo.StoreMessageTemplate(&domainChatStorage.MessageTemplate{DeviceID: "device-a", Name: "welcome", Body: "Hi {{name}}"}))
+	require.NoError(t, repo.StoreMessageTemplate(&domainChatStorage.MessageTemplate{
+		DeviceID: "device-a", Name: "promo", Body: "{{discount}} off", HeaderType: "image", HeaderURL: "https://example.com/promo.jpg",
+	}))
+	first, err := repo.GetMessageTemplate("device-a", "welcome")
+	require.NoError(t, err)
+	// Saving under the same name replaces the template.
+	require.NoError(t, repo.StoreMessageTemplate(&domainChatStorage.MessageTemplate{DeviceID: "device-a", Name: "welcome", Body: "Welcome {{name}}"}))
+
+	template, err := repo.GetMessageTemplate("device-a", "welcome")
+	require.NoError(t, err)
+	require.NotNil(t, template)
+	assert.Equal(t, "Welcome {{name}}", template.Body)
+	assert.True(t, template.CreatedAt.Equal(first.CreatedAt))
+
+	templates, err := repo.GetMessageTemplates("device-a")
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "promo", templates[0].Name)
+	assert.Equal(t, "image", templates[0].HeaderType)
+	assert.Equal(t, "https://example.com/promo.jpg", templates[0].HeaderURL)
+
+	// Templates are per device.
+	other, err := repo.GetMessageTemplates("device-b")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	deleted, err := repo.DeleteMessageTemplate("device-a", "promo")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = repo.DeleteMessageTemplate("device-a", "promo")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
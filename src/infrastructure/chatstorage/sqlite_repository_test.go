@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"testing"
 	"time"
@@ -560,3 +561,308 @@ func countMessageReactions(t *testing.T, repo *SQLiteRepository) int {
 	}
 	return count
 }
+
+// TestIterateMessages_WalksAllBatchesInOrder pins the keyset walk across batch
+// boundaries, including rows that share a timestamp at the boundary.
+func TestIterateMessages_WalksAllBatchesInOrder(t *testing.T) {
+	repo, db := newTestRepo(t)
+
+	device := "dev1"
+	chatJID := "5511999999999@s.whatsapp.net"
+	base := time.Date(2026, time.May, 16, 8, 0, 0, 0, time.UTC)
+	total := iterateMessagesBatchSize + 25
+
+	insertChat(t, db, device, chatJID, "Alice", base)
+	messages := []*domainChatStorage.Message{{ID: "other-device", ChatJID: chatJID, DeviceID: "dev2", Sender: chatJID, Content: "hidden", Timestamp: base}}
+	for i := 0; i < total; i++ {
+		// Pairs of rows share a timestamp so ties straddle the batch boundary.
+		messages = append(messages, &domainChatStorage.Message{
+			ID: fmt.Sprintf("msg-%04d", i), ChatJID: chatJID, DeviceID: device, Sender: chatJID, Content: "x",
+			Timestamp: base.Add(time.Duration(i/2) * time.Second),
+		})
+	}
+	if err := repo.StoreMessagesBatch(messages); err != nil {
+		t.Fatalf("StoreMessagesBatch: %v", err)
+	}
+
+	var seen []string
+	err := repo.IterateMessages(&domainChatStorage.MessageFilter{DeviceID: device, ChatJID: chatJID}, func(m *domainChatStorage.Message) error {
+		seen = append(seen, m.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("IterateMessages: %v", err)
+	}
+	if len(seen) != total {
+		t.Fatalf("expected %d messages, got %d", total, len(seen))
+	}
+	for i, id := range seen {
+		if want := fmt.Sprintf("msg-%04d", i); id != want {
+			t.Fatalf("position %d: got %s, want %s", i, id, want)
+		}
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.IterateMessages(&domainChatStorage.MessageFilter{DeviceID: device, ChatJID: chatJID}, func(*domainChatStorage.Message) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("expected callback error to stop iteration after one call, got err=%v calls=%d", err, calls)
+	}
+}
+
+// TestGetMessages_BeforeCursorPagesWithoutGapsOrRepeats walks a chat newest-first
+// using keyset cursors and checks every row is returned exactly once.
+func TestGetMessages_BeforeCursorPagesWithoutGapsOrRepeats(t *testing.T) {
+	repo, db := newTestRepo(t)
+
+	device := "dev1"
+	chatJID := "5511999999999@s.whatsapp.net"
+	base := time.Date(2026, time.May, 16, 8, 0, 0, 0, time.UTC)
+
+	insertChat(t, db, device, chatJID, "Alice", base)
+	var messages []*domainChatStorage.Message
+	for i := 0; i < 25; i++ {
+		messages = append(messages, &domainChatStorage.Message{
+			ID: fmt.Sprintf("msg-%02d", i), ChatJID: chatJID, DeviceID: device, Sender: chatJID, Content: "x",
+			Timestamp: base.Add(time.Duration(i/3) * time.Second),
+		})
+	}
+	if err := repo.StoreMessagesBatch(messages); err != nil {
+		t.Fatalf("StoreMessagesBatch: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var order []string
+	var cursor *domainChatStorage.MessageCursor
+	for page := 0; page < 10; page++ {
+		got, err := repo.GetMessages(&domainChatStorage.MessageFilter{DeviceID: device, ChatJID: chatJID, Limit: 7, Before: cursor})
+		if err != nil {
+			t.Fatalf("GetMessages: %v", err)
+		}
+		for _, m := range got {
+			if seen[m.ID] {
+				t.Fatalf("message %s returned twice", m.ID)
+			}
+			seen[m.ID] = true
+			order = append(order, m.ID)
+		}
+		if len(got) < 7 {
+			break
+		}
+		last := got[len(got)-1]
+		cursor = &domainChatStorage.MessageCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	if len(order) != 25 {
+		t.Fatalf("expected 25 messages across pages, got %d", len(order))
+	}
+	if order[0] != "msg-24" || order[24] != "msg-00" {
+		t.Fatalf("expected newest-first order, got first=%s last=%s", order[0], order[24])
+	}
+}
+
+func TestGetMessages_ResolvesQuotedMessageInline(t *testing.T) {
+	repo, db := newTestRepo(t)
+
+	device := "dev1"
+	chatJID := "5511999999999@s.whatsapp.net"
+	base := time.Date(2026, time.May, 17, 9, 0, 0, 0, time.UTC)
+
+	insertChat(t, db, device, chatJID, "Alice", base)
+	err := repo.StoreMessagesBatch([]*domainChatStorage.Message{
+		{ID: "original", ChatJID: chatJID, DeviceID: device, Sender: chatJID, Content: "lunch?", Timestamp: base},
+		{ID: "filler", ChatJID: chatJID, DeviceID: device, Sender: chatJID, Content: "...", Timestamp: base.Add(time.Minute)},
+		{
+			ID: "reply", ChatJID: chatJID, DeviceID: device, Sender: "me@s.whatsapp.net", Content: "sure", IsFromMe: true,
+			Timestamp: base.Add(2 * time.Minute), QuotedMessageID: "original", QuotedParticipant: chatJID,
+		},
+		{
+			ID: "orphan", ChatJID: chatJID, DeviceID: device, Sender: chatJID, Content: "re: old",
+			Timestamp: base.Add(3 * time.Minute), QuotedMessageID: "never-stored", QuotedParticipant: chatJID,
+		},
+	})
+	if err != nil {
+		t.Fatalf("StoreMessagesBatch: %v", err)
+	}
+
+	// Limit 3 keeps "original" off the page so it must be fetched separately.
+	got, err := repo.GetMessages(&domainChatStorage.MessageFilter{DeviceID: device, ChatJID: chatJID, Limit: 3})
+	if err != nil {
+		t.Fatalf("GetMessages: %v", err)
+	}
+	byID := make(map[string]*domainChatStorage.Message)
+	for _, m := range got {
+		byID[m.ID] = m
+	}
+
+	reply := byID["reply"]
+	if reply == nil || reply.QuotedMessageID != "original" || reply.QuotedParticipant != chatJID {
+		t.Fatalf("reply linkage not stored: %+v", reply)
+	}
+	if reply.QuotedMessage == nil || reply.QuotedMessage.Content != "lunch?" {
+		t.Fatalf("quoted message not resolved: %+v", reply.QuotedMessage)
+	}
+
+	orphan := byID["orphan"]
+	if orphan == nil || orphan.QuotedMessageID != "never-stored" || orphan.QuotedMessage != nil {
+		t.Fatalf("orphan reply should keep its ID without a resolved message: %+v", orphan)
+	}
+}
+
+func TestUpdateChatState_PersistsFlagsAndFilters(t *testing.T) {
+	repo, db := newTestRepo(t)
+
+	device := "dev1"
+	base := time.Date(2026, time.May, 17, 9, 0, 0, 0, time.UTC)
+	pinnedJID := "5511000000001@s.whatsapp.net"
+	mutedJID := "5511000000002@s.whatsapp.net"
+	plainJID := "5511000000003@s.whatsapp.net"
+	insertChat(t, db, device, pinnedJID, "Pinned", base)
+	insertChat(t, db, device, mutedJID, "Muted", base)
+	insertChat(t, db, device, plainJID, "Plain", base)
+
+	pinned, unread := true, 4
+	if err := repo.UpdateChatState(device, pinnedJID, domainChatStorage.ChatStateUpdate{Pinned: &pinned, UnreadCount: &unread}); err != nil {
+		t.Fatalf("UpdateChatState pinned: %v", err)
+	}
+	mutedUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := repo.UpdateChatState(device, mutedJID, domainChatStorage.ChatStateUpdate{MutedUntil: &mutedUntil}); err != nil {
+		t.Fatalf("UpdateChatState muted: %v", err)
+	}
+
+	chat, err := repo.GetChatByDevice(device, pinnedJID)
+	if err != nil || chat == nil {
+		t.Fatalf("GetChatByDevice: %v", err)
+	}
+	if !chat.Pinned || chat.UnreadCount != 4 || chat.IsMuted(time.Now()) {
+		t.Fatalf("unexpected pinned chat state: %+v", chat)
+	}
+
+	// A full StoreChat (as done on every incoming message) must not reset the flags.
+	chat.Name = "Pinned renamed"
+	if err := repo.StoreChat(chat); err != nil {
+		t.Fatalf("StoreChat: %v", err)
+	}
+	if chat, _ = repo.GetChatByDevice(device, pinnedJID); !chat.Pinned || chat.UnreadCount != 4 {
+		t.Fatalf("StoreChat clobbered chat state: %+v", chat)
+	}
+
+	muted, err := repo.GetChatByDevice(device, mutedJID)
+	if err != nil || muted == nil || !muted.MutedUntil.Equal(mutedUntil) || !muted.IsMuted(time.Now()) {
+		t.Fatalf("unexpected muted chat state: %+v (err %v)", muted, err)
+	}
+
+	yes, no := true, false
+	cases := []struct {
+		name   string
+		filter domainChatStorage.ChatFilter
+		want   []string
+	}{
+		{"pinned", domainChatStorage.ChatFilter{IsPinned: &yes}, []string{pinnedJID}},
+		{"muted", domainChatStorage.ChatFilter{IsMuted: &yes}, []string{mutedJID}},
+		{"not muted", domainChatStorage.ChatFilter{IsMuted: &no}, []string{pinnedJID, plainJID}},
+		{"unread", domainChatStorage.ChatFilter{HasUnread: &yes}, []string{pinnedJID}},
+		{"read", domainChatStorage.ChatFilter{HasUnread: &no}, []string{mutedJID, plainJID}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.filter.DeviceID = device
+			chats, err := repo.GetChats(&tc.filter)
+			if err != nil {
+				t.Fatalf("GetChats: %v", err)
+			}
+			got := make(map[string]bool, len(chats))
+			for _, c := range chats {
+				got[c.JID] = true
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for _, jid := range tc.want {
+				if !got[jid] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+			count, err := repo.GetFilteredChatCount(&tc.filter)
+			if err != nil || int(count) != len(tc.want) {
+				t.Fatalf("GetFilteredChatCount = %d (err %v), want %d", count, err, len(tc.want))
+			}
+		})
+	}
+
+	// Clearing the mute stores the zero time.
+	unmuted := time.Time{}
+	if err := repo.UpdateChatState(device, mutedJID, domainChatStorage.ChatStateUpdate{MutedUntil: &unmuted}); err != nil {
+		t.Fatalf("UpdateChatState unmute: %v", err)
+	}
+	if muted, _ = repo.GetChatByDevice(device, mutedJID); muted.IsMuted(time.Now()) {
+		t.Fatalf("chat still muted after unmute: %+v", muted)
+	}
+}
+
+func TestChatQueries_AreScopedToDevice(t *testing.T) {
+	repo, db := newTestRepo(t)
+
+	base := time.Date(2026, time.May, 17, 9, 0, 0, 0, time.UTC)
+	shared := "5511999999999@s.whatsapp.net"
+	insertChat(t, db, "dev1", shared, "Alice (dev1)", base)
+	insertChat(t, db, "dev2", shared, "Alice (dev2)", base)
+	insertChat(t, db, "dev2", "5511888888888@s.whatsapp.net", "Bob", base)
+	seedChatMessage(t, repo, "dev2", shared, "m1", "hi", base)
+
+	chats, err := repo.GetChats(&domainChatStorage.ChatFilter{DeviceID: "dev1"})
+	if err != nil {
+		t.Fatalf("GetChats: %v", err)
+	}
+	if len(chats) != 1 || chats[0].Name != "Alice (dev1)" {
+		t.Fatalf("dev1 should only see its own chat, got %+v", chats)
+	}
+
+	// An unscoped filter must not fall back to every device's chats.
+	if _, err := repo.GetChats(&domainChatStorage.ChatFilter{}); err == nil {
+		t.Fatal("expected GetChats without device_id to fail")
+	}
+	if _, err := repo.GetFilteredChatCount(&domainChatStorage.ChatFilter{}); err == nil {
+		t.Fatal("expected GetFilteredChatCount without device_id to fail")
+	}
+
+	if count, _ := repo.GetTotalChatCountByDevice("dev2"); count != 2 {
+		t.Fatalf("dev2 chat count = %d, want 2", count)
+	}
+	if count, _ := repo.GetTotalMessageCountByDevice("dev1"); count != 0 {
+		t.Fatalf("dev1 message count = %d, want 0", count)
+	}
+	if count, _ := repo.GetTotalMessageCountByDevice("dev2"); count != 1 {
+		t.Fatalf("dev2 message count = %d, want 1", count)
+	}
+}
+
+func TestRebuildSearchIndex_ReportsEveryStep(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	seedChatMessage(t, repo, "dev1", "5511999999999@s.whatsapp.net", "m1", "hello", time.Now())
+
+	var steps []string
+	var last [2]int
+	err := repo.RebuildSearchIndex(context.Background(), func(step string, completed, total int) {
+		steps = append(steps, step)
+		last = [2]int{completed, total}
+	})
+	if err != nil {
+		t.Fatalf("RebuildSearchIndex: %v", err)
+	}
+	if len(steps) != len(searchIndexSteps)+1 || steps[len(steps)-1] != "" {
+		t.Fatalf("progress steps = %q, want every step followed by a final empty step", steps)
+	}
+	if last != [2]int{len(searchIndexSteps), len(searchIndexSteps)} {
+		t.Fatalf("final progress = %v, want all steps completed", last)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := repo.RebuildSearchIndex(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled RebuildSearchIndex = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,108 @@
+package chatstorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTrashChatHidesRestoresAndPurges(t *testing.T) {
+	repo, db := newTestRepo(t)
+	now := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	insertChat(t, db, "device-a", "111@s.whatsapp.net", "Alice", now)
+	insertChat(t, db, "device-a", "222@s.whatsapp.net", "Bob", now)
+	insertMessage(t, db, "m1", "111@s.whatsapp.net", "device-a", "111@s.whatsapp.net", "hello", now)
+
+	listed := func(trashed bool) []string {
+		chats, err := repo.GetChats(&domainChatStorage.ChatFilter{DeviceID: "device-a", Trashed: trashed})
+		require.NoError(t, err)
+		var jids []string
+		for _, chat := range chats {
+			jids = append(jids, chat.JID)
+		}
+		return jids
+	}
+
+	require.NoError(t, repo.TrashChat("device-a", "111@s.whatsapp.net"))
+	assert.Equal(t, []string{"222@s.whatsapp.net"}, listed(false))
+	assert.Equal(t, []string{"111@s.whatsapp.net"}, listed(true))
+	count, err := repo.GetFilteredChatCount(&domainChatStorage.ChatFilter{DeviceID: "device-a"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	chat, err := repo.GetChatByDevice("device-a", "111@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.False(t, chat.DeletedAt.IsZero())
+
+	restored, err := repo.RestoreChat("device-a", "111@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.True(t, restored)
+	restored, err = repo.RestoreChat("device-a", "111@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.False(t, restored)
+	assert.Len(t, listed(false), 2)
+
+	// Only chats trashed before the cutoff are purged, together with their messages.
+	require.NoError(t, repo.TrashChat("device-a", "111@s.whatsapp.net"))
+	purged, err := repo.PurgeTrashedChats(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = repo.PurgeTrashedChats(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Empty(t, listed(true))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM messages WHERE chat_jid = '111@s.whatsapp.net'`))
+}
+
+func TestNewMessageTakesChatOutOfTrash(t *testing.T) {
+	repo, db := newTestRepo(t)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	chat := types.NewJID("111", types.DefaultUserServer)
+	message := func(id string, ts time.Time) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+				ID:            id,
+				Timestamp:     ts,
+			},
+			Message: &waE2E.Message{Conversation: proto.String("hello " + id)},
+		}
+	}
+	listed := func() []string {
+		chats, err := repo.GetChats(&domainChatStorage.ChatFilter{DeviceID: "device-a"})
+		require.NoError(t, err)
+		var jids []string
+		for _, c := range chats {
+			jids = append(jids, c.JID)
+		}
+		return jids
+	}
+
+	require.NoError(t, repo.CreateMessage(ctx, message("m1", time.Now().Add(-time.Hour))))
+	require.NoError(t, repo.TrashChat("device-a", chat.String()))
+	// Trash times are whole seconds, so the new message must be a second later.
+	_, err := db.Exec(`UPDATE chats SET deleted_at = deleted_at - 2`)
+	require.NoError(t, err)
+
+	// History sync of an older message leaves the chat in the trash.
+	require.NoError(t, repo.CreateMessage(ctx, message("m0", time.Now().Add(-2*time.Hour))))
+	assert.Empty(t, listed())
+
+	require.NoError(t, repo.CreateMessage(ctx, message("m2", time.Now())))
+	assert.Equal(t, []string{chat.String()}, listed())
+
+	purged, err := repo.PurgeTrashedChats(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.Equal(t, 3, countRows(t, db, `SELECT COUNT(*) FROM messages WHERE chat_jid = '111@s.whatsapp.net'`))
+}
@@ -0,0 +1,55 @@
+package chatstorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreateMessageCountsIncomingAsUnread(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	chat := types.NewJID("628123456789", types.DefaultUserServer)
+	now := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	textEvent := func(id string, fromMe bool) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: chat, Sender: chat, IsFromMe: fromMe},
+				ID:            id,
+				Timestamp:     now,
+			},
+			Message: &waE2E.Message{Conversation: proto.String("hello " + id)},
+		}
+	}
+	unreadCount := func() int {
+		t.Helper()
+		stored, err := repo.GetChatByDevice("device-a", chat.String())
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		return stored.UnreadCount
+	}
+
+	require.NoError(t, repo.CreateMessage(ctx, textEvent("in-1", false)))
+	require.NoError(t, repo.CreateMessage(ctx, textEvent("in-2", false)))
+	assert.Equal(t, 2, unreadCount())
+
+	// A redelivered message and our own messages are not counted.
+	require.NoError(t, repo.CreateMessage(ctx, textEvent("in-2", false)))
+	require.NoError(t, repo.CreateMessage(ctx, textEvent("out-1", true)))
+	assert.Equal(t, 2, unreadCount())
+
+	chats, err := repo.GetChats(&domainChatStorage.ChatFilter{DeviceID: "device-a"})
+	require.NoError(t, err)
+	require.Len(t, chats, 1)
+	assert.Equal(t, 2, chats[0].UnreadCount)
+}
@@ -0,0 +1,113 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryQueue(t *testing.T) {
+	repo, db := newTestRepo(t)
+	now := time.Now()
+	enqueue := func(deviceID, url string) *domainChatStorage.WebhookDelivery {
+		delivery := &domainChatStorage.WebhookDelivery{
+			DeviceID: deviceID, URL: url, EventName: "message", PayloadJSON: `{"event":"message"}`,
+			LastError: "connection refused", NextAttemptAt: now.Add(time.Minute),
+		}
+		require.NoError(t, repo.EnqueueWebhookDelivery(delivery))
+		require.NotZero(t, delivery.ID)
+		return delivery
+	}
+
+	first := enqueue("device-a", "https://hook-1")
+	second := enqueue("device-a", "https://hook-2")
+	other := enqueue("device-b", "https://hook-1")
+	assert.Error(t, repo.EnqueueWebhookDelivery(&domainChatStorage.WebhookDelivery{DeviceID: "device-a"}))
+
+	due, err := repo.ListDueWebhookDeliveries(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = repo.ListDueWebhookDeliveries(now.Add(2*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 3)
+	assert.Equal(t, first.ID, due[0].ID)
+	assert.Equal(t, "https://hook-1", due[0].URL)
+	assert.Equal(t, `{"event":"message"}`, due[0].PayloadJSON)
+	assert.True(t, due[0].DeadAt.IsZero())
+
+	require.NoError(t, repo.MarkWebhookDeliveryFailed(first.ID, "timeout", now.Add(time.Hour)))
+	require.NoError(t, repo.MarkWebhookDeliveryDead(second.ID, "status 500"))
+	require.NoError(t, repo.MarkWebhookDeliveryDone(other.ID))
+
+	// Dead letters are no longer retried.
+	due, err = repo.ListDueWebhookDeliveries(now.Add(2*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	dead, err := repo.ListDeadWebhookDeliveries("device-a", 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, second.ID, dead[0].ID)
+	assert.Equal(t, 1, dead[0].Attempts)
+	assert.Equal(t, "status 500", dead[0].LastError)
+	assert.False(t, dead[0].DeadAt.IsZero())
+
+	dead, err = repo.ListDeadWebhookDeliveries("device-b", 10)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+
+	// Only a dead letter of the same device can be requeued.
+	requeued, err := repo.RequeueWebhookDelivery("device-b", second.ID)
+	require.NoError(t, err)
+	assert.False(t, requeued)
+	requeued, err = repo.RequeueWebhookDelivery("device-a", first.ID)
+	require.NoError(t, err)
+	assert.False(t, requeued)
+	requeued, err = repo.RequeueWebhookDelivery("device-a", second.ID)
+	require.NoError(t, err)
+	assert.True(t, requeued)
+
+	due, err = repo.ListDueWebhookDeliveries(time.Now().Add(time.Second), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, second.ID, due[0].ID)
+	assert.Zero(t, due[0].Attempts)
+
+	require.NoError(t, repo.DeleteDeviceData("device-a"))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM webhook_deliveries`))
+}
+
+func TestRequeueDeadWebhookDeliveriesSince(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	enqueueDead := func(deviceID string) int64 {
+		delivery := &domainChatStorage.WebhookDelivery{
+			DeviceID: deviceID, URL: "https://hook", EventName: "message", PayloadJSON: `{}`,
+			NextAttemptAt: time.Now().Add(time.Minute),
+		}
+		require.NoError(t, repo.EnqueueWebhookDelivery(delivery))
+		require.NoError(t, repo.MarkWebhookDeliveryDead(delivery.ID, "status 503"))
+		return delivery.ID
+	}
+
+	enqueueDead("device-a")
+	since := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+	recent := enqueueDead("device-a")
+	enqueueDead("device-b")
+
+	replayed, err := repo.RequeueDeadWebhookDeliveries("device-a", since)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), replayed)
+
+	due, err := repo.ListDueWebhookDeliveries(time.Now().Add(time.Second), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, recent, due[0].ID)
+
+	dead, err := repo.ListDeadWebhookDeliveries("device-a", 10)
+	require.NoError(t, err)
+	assert.Len(t, dead, 1, "dead letters from before since stay dead")
+}
@@ -0,0 +1,42 @@
+package chatstorage
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+// trashPurgeInterval is how often trashed chats are checked against the retention window.
+const trashPurgeInterval = time.Hour
+
+// StartTrashPurger permanently deletes chats that have been in the trash for
+// longer than retention, once at start and then periodically until ctx ends.
+func StartTrashPurger(ctx context.Context, repo domainChatStorage.IChatStorageRepository, retention time.Duration) {
+	if repo == nil || retention <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(min(trashPurgeInterval, retention))
+		defer ticker.Stop()
+		for {
+			purgeTrash(repo, retention)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func purgeTrash(repo domainChatStorage.IChatStorageRepository, retention time.Duration) {
+	purged, err := repo.PurgeTrashedChats(time.Now().Add(-retention))
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to purge trashed chats")
+	}
+	if purged > 0 {
+		logrus.Infof("Purged %d chats from the trash", purged)
+	}
+}
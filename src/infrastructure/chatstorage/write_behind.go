@@ -0,0 +1,168 @@
+package chatstorage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+// writeBehind buffers StoreMessage calls and writes them with
+// StoreMessagesBatch, so a burst costs one transaction (and one fsync) per
+// batch instead of one per message.
+type writeBehind struct {
+	interval time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending []*domainChatStorage.Message
+	stopped bool
+
+	// flushMu serializes flushes so batches reach the database in the order
+	// their messages were stored.
+	flushMu sync.Mutex
+
+	kick chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// EnableWriteBehind makes StoreMessage return once the message is buffered.
+// Buffered messages are written every interval, or as soon as maxBatch are
+// waiting. Reads and writes that depend on stored messages flush the buffer
+// first, so callers of this repository never see a missing message. Call
+// FlushPendingWrites on shutdown. Must be called before the repository is used.
+func (r *SQLiteRepository) EnableWriteBehind(interval time.Duration, maxBatch int) {
+	if interval <= 0 || r.writeBehind != nil {
+		return
+	}
+	if maxBatch <= 0 {
+		maxBatch = 100
+	}
+	r.writeBehind = &writeBehind{
+		interval: interval,
+		maxBatch: maxBatch,
+		kick:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go r.runWriteBehind()
+}
+
+func (r *SQLiteRepository) runWriteBehind() {
+	wb := r.writeBehind
+	defer close(wb.done)
+
+	ticker := time.NewTicker(wb.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-wb.stop:
+			return
+		case <-ticker.C:
+		case <-wb.kick:
+		}
+		r.flushPendingMessages()
+	}
+}
+
+// bufferMessage queues a copy of message for the next batch. It reports false
+// when buffering is disabled or stopped and the caller must write directly.
+func (r *SQLiteRepository) bufferMessage(message *domainChatStorage.Message) bool {
+	wb := r.writeBehind
+	if wb == nil {
+		return false
+	}
+
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if wb.stopped {
+		return false
+	}
+	buffered := *message
+	wb.pending = append(wb.pending, &buffered)
+	if len(wb.pending) >= wb.maxBatch {
+		select {
+		case wb.kick <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// dropBufferedOutboxEvent clears the outbox event of a message still waiting in
+// the buffer and reports whether it found one. It waits for a batch being
+// written to commit first, so afterwards the event is either cleared here or
+// already in the database.
+func (r *SQLiteRepository) dropBufferedOutboxEvent(deviceID, messageID, eventName string) bool {
+	wb := r.writeBehind
+	if wb == nil {
+		return false
+	}
+
+	wb.flushMu.Lock()
+	defer wb.flushMu.Unlock()
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	for _, message := range wb.pending {
+		if message.DeviceID == deviceID && message.ID == messageID && message.OutboxEvent == eventName {
+			message.OutboxEvent = ""
+			return true
+		}
+	}
+	return false
+}
+
+// flushPendingMessages writes buffered messages now. Failures are logged, not
+// returned: the messages were already acknowledged to their callers.
+func (r *SQLiteRepository) flushPendingMessages() {
+	if err := r.writePendingMessages(); err != nil {
+		logrus.WithError(err).Error("Failed to write buffered chat storage messages")
+	}
+}
+
+func (r *SQLiteRepository) writePendingMessages() error {
+	wb := r.writeBehind
+	if wb == nil {
+		return nil
+	}
+
+	wb.flushMu.Lock()
+	defer wb.flushMu.Unlock()
+
+	wb.mu.Lock()
+	batch := wb.pending
+	wb.pending = nil
+	wb.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := r.StoreMessagesBatch(batch); err != nil {
+		return fmt.Errorf("failed to write %d buffered messages: %w", len(batch), err)
+	}
+	return nil
+}
+
+// FlushPendingWrites writes every buffered message and turns buffering off;
+// later StoreMessage calls write directly. Safe to call when buffering was
+// never enabled.
+func (r *SQLiteRepository) FlushPendingWrites() error {
+	wb := r.writeBehind
+	if wb == nil {
+		return nil
+	}
+
+	wb.mu.Lock()
+	alreadyStopped := wb.stopped
+	wb.stopped = true
+	wb.mu.Unlock()
+	if !alreadyStopped {
+		close(wb.stop)
+		<-wb.done
+	}
+
+	return r.writePendingMessages()
+}
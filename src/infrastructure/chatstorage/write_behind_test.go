@@ -0,0 +1,86 @@
+package chatstorage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBehindBuffersUntilReadOrFlush(t *testing.T) {
+	repo, db := newTestRepo(t)
+	// An interval this long means only explicit flushes write anything.
+	repo.EnableWriteBehind(time.Hour, 1000)
+	chatJID := "120363000000000000@g.us"
+	now := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID: fmt.Sprintf("m%d", i), ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+			Content: fmt.Sprintf("message %d", i), Timestamp: now.Add(time.Duration(i) * time.Second),
+		}))
+	}
+	// A later update to a buffered message must win over the original.
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "m0", ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+		Content: "message 0 updated", Timestamp: now,
+	}))
+	assert.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM messages`))
+
+	// Reads through the repository see buffered messages.
+	message, err := repo.GetMessageByIDAndDevice("device-a", "m0")
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Equal(t, "message 0 updated", message.Content)
+	assert.Equal(t, 3, countRows(t, db, `SELECT COUNT(*) FROM messages`))
+
+	// After the final flush, writes go straight to the database.
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "m3", ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+		Content: "buffered", Timestamp: now.Add(3 * time.Second),
+	}))
+	require.NoError(t, repo.FlushPendingWrites())
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "m4", ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+		Content: "direct", Timestamp: now.Add(4 * time.Second),
+	}))
+	assert.Equal(t, 5, countRows(t, db, `SELECT COUNT(*) FROM messages`))
+}
+
+func TestWriteBehindFlushesFullBatch(t *testing.T) {
+	repo, db := newTestRepo(t)
+	repo.EnableWriteBehind(time.Hour, 2)
+	t.Cleanup(func() { _ = repo.FlushPendingWrites() })
+	chatJID := "628123456789@s.whatsapp.net"
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID: fmt.Sprintf("m%d", i), ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID,
+			Content: "hello", Timestamp: time.Now(),
+		}))
+	}
+	assert.Eventually(t, func() bool {
+		return countRows(t, db, `SELECT COUNT(*) FROM messages`) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWriteBehindRedeliveredMessageCountsAsUnreadOnce(t *testing.T) {
+	repo, db := newTestRepo(t)
+	repo.EnableWriteBehind(time.Hour, 1000)
+	t.Cleanup(func() { _ = repo.FlushPendingWrites() })
+	chatJID := "628123456789@s.whatsapp.net"
+	require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{JID: chatJID, DeviceID: "device-a", LastMessageTime: time.Now()}))
+
+	message := &domainChatStorage.Message{
+		ID: "m0", ChatJID: chatJID, DeviceID: "device-a", Sender: chatJID, Content: "hello", Timestamp: time.Now(),
+	}
+	// The redelivery arrives while the first copy is still buffered.
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.incrementUnreadCount(message))
+		require.NoError(t, repo.StoreMessage(message))
+	}
+	assert.Equal(t, 1, countRows(t, db, `SELECT unread_count FROM chats WHERE jid = '`+chatJID+`'`))
+}
@@ -213,19 +213,21 @@ func (c *Client) FindContactByIdentifier(identifier string, isGroup bool) (*Cont
 	return nil, nil
 }
 
-func (c *Client) CreateContact(name, identifier string, isGroup bool) (*Contact, error) {
-	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/contacts", c.BaseURL, c.AccountID)
-
-	// For groups, use Identifier field
-	// For private chats, use E.164 phone format
-	// For @lid JIDs (non-phone WhatsApp IDs), use Identifier field
-	var phoneNumber, contactIdentifier string
+// ContactIdentity splits a contact identifier into the phone_number and
+// identifier fields of a Chatwoot contact. Groups and @lid JIDs (non-phone
+// WhatsApp IDs) use the identifier field; private chats use the phone number
+// in E.164 format.
+func ContactIdentity(identifier string, isGroup bool) (phoneNumber, contactIdentifier string) {
 	if isGroup || strings.HasSuffix(identifier, "@lid") {
-		contactIdentifier = identifier
-	} else {
-		phoneNumber = utils.NormalizePhoneE164(identifier)
+		return "", identifier
 	}
+	return utils.NormalizePhoneE164(identifier), ""
+}
+
+func (c *Client) CreateContact(name, identifier string, isGroup bool) (*Contact, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/contacts", c.BaseURL, c.AccountID)
 
+	phoneNumber, contactIdentifier := ContactIdentity(identifier, isGroup)
 	payload := CreateContactRequest{
 		InboxID:     c.InboxID,
 		Name:        name,
@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func newKafkaPublisher(url string) (*kafkaPublisher, error) {
+	var brokers []string
+	for _, broker := range strings.Split(url, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("kafka: no brokers configured")
+	}
+	return &kafkaPublisher{writer: &kafka.Writer{
+		Addr: kafka.TCP(brokers...),
+		// Hashing the chat key sends a chat's events to one partition, in order.
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+		// Each event is written synchronously; don't wait for a batch to fill.
+		BatchTimeout: 10 * time.Millisecond,
+	}}, nil
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, topic, key string, body []byte) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{Topic: topic, Key: []byte(key), Value: body})
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
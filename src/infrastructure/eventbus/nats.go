@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsPublisher publishes to JetStream, so a stream must capture the
+// subjects; a publish without one fails instead of being dropped.
+type natsPublisher struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+func newNATSPublisher(url string) (*natsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("gowa"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	return &natsPublisher{conn: conn, js: js}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, topic, key string, body []byte) error {
+	msg := nats.NewMsg(topic)
+	msg.Data = body
+	if key != "" {
+		msg.Header.Set("Gowa-Chat", key)
+	}
+	_, err := p.js.PublishMsg(ctx, msg)
+	return err
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
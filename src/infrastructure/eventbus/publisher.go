@@ -0,0 +1,51 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	DriverKafka    = "kafka"
+	DriverNATS     = "nats"
+	DriverRabbitMQ = "rabbitmq"
+	// DriverRedisStream appends to a stream, by default one per device;
+	// DriverRedisPubSub publishes to a channel, by default one per event type.
+	DriverRedisStream = "redis-stream"
+	DriverRedisPubSub = "redis-pubsub"
+)
+
+// Publisher publishes serialized events to a message broker. key identifies
+// the chat an event belongs to; brokers that partition by key keep a chat's
+// events in order.
+type Publisher interface {
+	Publish(ctx context.Context, topic, key string, body []byte) error
+	Close() error
+}
+
+// New connects a publisher for driver. The URL format depends on the driver:
+// comma-separated host:port brokers for kafka, a nats:// URL for nats, an
+// amqp:// URL for rabbitmq and a redis:// URL for the Redis drivers.
+func New(driver, url string) (Publisher, error) {
+	switch driver {
+	case DriverKafka:
+		return newKafkaPublisher(url)
+	case DriverNATS:
+		return newNATSPublisher(url)
+	case DriverRabbitMQ:
+		return newRabbitMQPublisher(url)
+	case DriverRedisStream, DriverRedisPubSub:
+		return newRedisPublisher(url, driver == DriverRedisPubSub)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q: must be kafka, nats, rabbitmq, redis-stream or redis-pubsub", driver)
+	}
+}
+
+// DefaultTopic is the topic pattern used for driver when none is configured.
+// Topic patterns may use "{event}" and "{device_id}".
+func DefaultTopic(driver string) string {
+	if driver == DriverRedisStream {
+		return "whatsapp:{device_id}"
+	}
+	return "whatsapp.{event}"
+}
@@ -0,0 +1,16 @@
+package eventbus
+
+import "testing"
+
+func TestNewRejectsBadConfiguration(t *testing.T) {
+	for name, tc := range map[string]struct{ driver, url string }{
+		"unknown driver":    {"sqs", "https://sqs.example.com"},
+		"kafka, no brokers": {DriverKafka, " , "},
+		"redis, bad url":    {DriverRedisStream, "localhost:6379"},
+	} {
+		if publisher, err := New(tc.driver, tc.url); err == nil {
+			publisher.Close()
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
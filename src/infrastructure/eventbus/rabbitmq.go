@@ -0,0 +1,93 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// rabbitMQExchange is the topic exchange every broker predeclares; consumers
+// bind their queues to it with routing patterns such as "whatsapp.message.#".
+const rabbitMQExchange = "amq.topic"
+
+type rabbitMQPublisher struct {
+	url string
+
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+func newRabbitMQPublisher(url string) (*rabbitMQPublisher, error) {
+	p := &rabbitMQPublisher{url: url}
+	if _, err := p.openChannel(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// openChannel returns the publishing channel, redialing when the connection
+// was lost since the last publish. Callers hold p.mu.
+func (p *rabbitMQPublisher) openChannel() (*amqp.Channel, error) {
+	if p.channel != nil && !p.channel.IsClosed() {
+		return p.channel, nil
+	}
+	if p.conn == nil || p.conn.IsClosed() {
+		conn, err := amqp.Dial(p.url)
+		if err != nil {
+			return nil, fmt.Errorf("rabbitmq: %w", err)
+		}
+		p.conn = conn
+	}
+	channel, err := p.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq: %w", err)
+	}
+	// Confirms make Publish wait until the broker has taken the message.
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		return nil, fmt.Errorf("rabbitmq: %w", err)
+	}
+	p.channel = channel
+	return channel, nil
+}
+
+func (p *rabbitMQPublisher) Publish(ctx context.Context, topic, key string, body []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	channel, err := p.openChannel()
+	if err != nil {
+		return err
+	}
+	publishing := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Body:         body,
+	}
+	if key != "" {
+		publishing.Headers = amqp.Table{"gowa-chat": key}
+	}
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, rabbitMQExchange, topic, false, false, publishing)
+	if err != nil {
+		return fmt.Errorf("rabbitmq: %w", err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("rabbitmq: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("rabbitmq: broker rejected the message")
+	}
+	return nil
+}
+
+func (p *rabbitMQPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.Close()
+}
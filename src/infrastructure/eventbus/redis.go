@@ -0,0 +1,66 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisPublishAttempts bounds how often a failed XADD or PUBLISH is retried
+// within the caller's deadline.
+const redisPublishAttempts = 3
+
+// redisPublisher appends events to a Redis stream, or with pubsub set
+// publishes them to a channel. Stream entries get server-assigned IDs, so
+// consumer groups can read them with XREADGROUP and acknowledge with XACK.
+type redisPublisher struct {
+	client *redis.Client
+	pubsub bool
+}
+
+func newRedisPublisher(url string, pubsub bool) (*redisPublisher, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return &redisPublisher{client: client, pubsub: pubsub}, nil
+}
+
+func (p *redisPublisher) Publish(ctx context.Context, topic, key string, body []byte) error {
+	var err error
+	for attempt := 1; attempt <= redisPublishAttempts; attempt++ {
+		if p.pubsub {
+			err = p.client.Publish(ctx, topic, body).Err()
+		} else {
+			err = p.client.XAdd(ctx, &redis.XAddArgs{
+				Stream: topic,
+				Values: []any{"chat", key, "payload", body},
+			}).Err()
+		}
+		if err == nil || attempt == redisPublishAttempts {
+			break
+		}
+		select {
+		case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+		case <-ctx.Done():
+			return fmt.Errorf("redis: %w", err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
+
+func (p *redisPublisher) Close() error {
+	return p.client.Close()
+}
@@ -3,6 +3,7 @@ package whatsapp
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
@@ -15,7 +16,13 @@ import (
 )
 
 func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
-	if config.WhatsappAutoReplyMessage == "" {
+	// During quiet hours the business-hours notice, when configured, replaces
+	// the regular auto-reply.
+	replyText := config.Setting(&config.WhatsappAutoReplyMessage)
+	if notice, ok := quietHoursReply(time.Now()); ok {
+		replyText = notice
+	}
+	if replyText == "" {
 		return
 	}
 
@@ -77,7 +84,7 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	response, err := client.SendMessage(
 		ctx,
 		recipientJID,
-		&waE2E.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)},
+		&waE2E.Message{Conversation: proto.String(replyText)},
 	)
 
 	if err != nil {
@@ -96,12 +103,12 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 		// Store the sent auto-reply message
 		if err := chatStorageRepo.StoreSentMessageWithContext(
 			ctx,
-			response.ID,                     // Message ID from WhatsApp response
-			senderJID,                       // Our JID as sender
-			recipientJID.String(),           // Recipient JID
-			config.WhatsappAutoReplyMessage, // Auto-reply content
-			response.Timestamp,              // Timestamp from response
-			nil,                             // text-only message, no media
+			response.ID,           // Message ID from WhatsApp response
+			senderJID,             // Our JID as sender
+			recipientJID.String(), // Recipient JID
+			replyText,             // Auto-reply content
+			response.Timestamp,    // Timestamp from response
+			nil,                   // text-only message, no media
 		); err != nil {
 			// Log storage error but don't fail the auto-reply
 			log.Errorf("Failed to store auto-reply message in chat storage: %v", err)
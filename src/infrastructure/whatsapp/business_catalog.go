@@ -0,0 +1,179 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// catalogImageSize is the width and height, in pixels, of the product images
+// catalog and order queries ask for.
+const catalogImageSize = "100"
+
+// CatalogProduct is a product of a WhatsApp Business catalog. Prices are in
+// thousandths of the currency unit, as WhatsApp reports them.
+type CatalogProduct struct {
+	ID              string
+	RetailerID      string
+	Name            string
+	Description     string
+	URL             string
+	Currency        string
+	PriceAmount1000 int64
+	ImageURL        string
+	IsHidden        bool
+}
+
+// Catalog is one page of a business catalog; NextCursor fetches the next
+// page and is empty on the last one.
+type Catalog struct {
+	Products   []CatalogProduct
+	NextCursor string
+}
+
+// OrderItem is one line of an order.
+type OrderItem struct {
+	ProductID       string
+	Name            string
+	ImageURL        string
+	Quantity        int
+	Currency        string
+	PriceAmount1000 int64
+}
+
+// OrderDetails are the line items and total of an order.
+type OrderDetails struct {
+	Items           []OrderItem
+	Currency        string
+	TotalAmount1000 int64
+}
+
+// GetBusinessCatalog fetches up to limit products of the catalog of the
+// business account jid, starting at cursor (empty for the first page).
+func GetBusinessCatalog(ctx context.Context, client *whatsmeow.Client, jid types.JID, limit int, cursor string) (*Catalog, error) {
+	if client == nil {
+		return nil, fmt.Errorf("whatsapp client is not initialized")
+	}
+	content := []waBinary.Node{
+		{Tag: "limit", Content: []byte(strconv.Itoa(limit))},
+		{Tag: "width", Content: []byte(catalogImageSize)},
+		{Tag: "height", Content: []byte(catalogImageSize)},
+	}
+	if cursor != "" {
+		content = append(content, waBinary.Node{Tag: "after", Content: []byte(cursor)})
+	}
+	resp, err := client.DangerousInternals().SendIQ(ctx, whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz:catalog",
+		Type:      "get",
+		To:        types.ServerJID,
+		Content: []waBinary.Node{{
+			Tag:     "product_catalog",
+			Attrs:   waBinary.Attrs{"jid": jid.ToNonAD(), "allow_shop_source": "true"},
+			Content: content,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog of %s: %w", jid, err)
+	}
+	return parseCatalogNode(resp), nil
+}
+
+// GetOrderDetails fetches the line items of an order, authorized by the token
+// of its order message.
+func GetOrderDetails(ctx context.Context, client *whatsmeow.Client, orderID, token string) (*OrderDetails, error) {
+	if client == nil {
+		return nil, fmt.Errorf("whatsapp client is not initialized")
+	}
+	resp, err := client.DangerousInternals().SendIQ(ctx, whatsmeow.DangerousInfoQuery{
+		Namespace: "fb:thrift_iq",
+		Type:      "get",
+		To:        types.ServerJID,
+		SMaxID:    "5",
+		Content: []waBinary.Node{{
+			Tag:   "order",
+			Attrs: waBinary.Attrs{"op": "get", "id": orderID},
+			Content: []waBinary.Node{
+				{Tag: "image_dimensions", Content: []waBinary.Node{
+					{Tag: "width", Content: []byte(catalogImageSize)},
+					{Tag: "height", Content: []byte(catalogImageSize)},
+				}},
+				{Tag: "token", Content: []byte(token)},
+			},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %s: %w", orderID, err)
+	}
+	return parseOrderDetailsNode(resp), nil
+}
+
+func parseCatalogNode(node *waBinary.Node) *Catalog {
+	catalog := &Catalog{}
+	catalogNode, ok := node.GetOptionalChildByTag("product_catalog")
+	if !ok {
+		return catalog
+	}
+	for _, productNode := range catalogNode.GetChildrenByTag("product") {
+		image := productNode.GetChildByTag("media", "image")
+		catalog.Products = append(catalog.Products, CatalogProduct{
+			ID:              childText(productNode, "id"),
+			RetailerID:      childText(productNode, "retailer_id"),
+			Name:            childText(productNode, "name"),
+			Description:     childText(productNode, "description"),
+			URL:             childText(productNode, "url"),
+			Currency:        childText(productNode, "currency"),
+			PriceAmount1000: childInt(productNode, "price"),
+			ImageURL:        childText(image, "request_image_url"),
+			IsHidden:        productNode.AttrGetter().OptionalString("is_hidden") == "true",
+		})
+	}
+	catalog.NextCursor = childText(catalogNode.GetChildByTag("paging"), "after")
+	return catalog
+}
+
+func parseOrderDetailsNode(node *waBinary.Node) *OrderDetails {
+	details := &OrderDetails{}
+	orderNode, ok := node.GetOptionalChildByTag("order")
+	if !ok {
+		return details
+	}
+	for _, productNode := range orderNode.GetChildrenByTag("product") {
+		details.Items = append(details.Items, OrderItem{
+			ProductID:       childText(productNode, "id"),
+			Name:            childText(productNode, "name"),
+			ImageURL:        childText(productNode.GetChildByTag("image"), "url"),
+			Quantity:        int(childInt(productNode, "quantity")),
+			Currency:        childText(productNode, "currency"),
+			PriceAmount1000: childInt(productNode, "price"),
+		})
+	}
+	price := orderNode.GetChildByTag("price")
+	details.Currency = childText(price, "currency")
+	details.TotalAmount1000 = childInt(price, "total")
+	return details
+}
+
+// childText returns the text content of the tag child of node, or "".
+func childText(node waBinary.Node, tag string) string {
+	child, ok := node.GetOptionalChildByTag(tag)
+	if !ok {
+		return ""
+	}
+	switch content := child.Content.(type) {
+	case []byte:
+		return string(content)
+	case string:
+		return content
+	}
+	return ""
+}
+
+// childInt returns the integer content of the tag child of node, or 0.
+func childInt(node waBinary.Node, tag string) int64 {
+	value, _ := strconv.ParseInt(childText(node, tag), 10, 64)
+	return value
+}
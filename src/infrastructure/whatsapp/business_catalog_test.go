@@ -0,0 +1,95 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	waBinary "go.mau.fi/whatsmeow/binary"
+)
+
+func textNode(tag, text string) waBinary.Node {
+	return waBinary.Node{Tag: tag, Content: []byte(text)}
+}
+
+func TestParseCatalogNode(t *testing.T) {
+	node := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{
+		Tag: "product_catalog",
+		Content: []waBinary.Node{
+			{Tag: "product", Attrs: waBinary.Attrs{"is_hidden": "true"}, Content: []waBinary.Node{
+				textNode("id", "111"),
+				textNode("retailer_id", "SKU-1"),
+				textNode("name", "Pizza"),
+				textNode("description", "Large"),
+				textNode("url", "https://example.com/pizza"),
+				textNode("currency", "IDR"),
+				textNode("price", "50000000"),
+				{Tag: "media", Content: []waBinary.Node{{Tag: "image", Content: []waBinary.Node{
+					textNode("request_image_url", "https://example.com/pizza.jpg"),
+				}}}},
+			}},
+			{Tag: "product", Content: []waBinary.Node{
+				textNode("id", "222"),
+				textNode("name", "Soda"),
+			}},
+			{Tag: "paging", Content: []waBinary.Node{textNode("after", "cursor-2")}},
+		},
+	}}}
+
+	catalog := parseCatalogNode(node)
+
+	assert.Equal(t, []CatalogProduct{
+		{
+			ID:              "111",
+			RetailerID:      "SKU-1",
+			Name:            "Pizza",
+			Description:     "Large",
+			URL:             "https://example.com/pizza",
+			Currency:        "IDR",
+			PriceAmount1000: 50000000,
+			ImageURL:        "https://example.com/pizza.jpg",
+			IsHidden:        true,
+		},
+		{ID: "222", Name: "Soda"},
+	}, catalog.Products)
+	assert.Equal(t, "cursor-2", catalog.NextCursor)
+}
+
+func TestParseCatalogNodeWithoutCatalog(t *testing.T) {
+	catalog := parseCatalogNode(&waBinary.Node{Tag: "iq"})
+
+	assert.Empty(t, catalog.Products)
+	assert.Empty(t, catalog.NextCursor)
+}
+
+func TestParseOrderDetailsNode(t *testing.T) {
+	node := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{
+		Tag: "order",
+		Content: []waBinary.Node{
+			{Tag: "product", Content: []waBinary.Node{
+				textNode("id", "111"),
+				textNode("name", "Pizza"),
+				{Tag: "image", Content: []waBinary.Node{textNode("url", "https://example.com/pizza.jpg")}},
+				textNode("quantity", "2"),
+				textNode("currency", "IDR"),
+				textNode("price", "50000000"),
+			}},
+			{Tag: "price", Content: []waBinary.Node{
+				textNode("total", "100000000"),
+				textNode("currency", "IDR"),
+			}},
+		},
+	}}}
+
+	details := parseOrderDetailsNode(node)
+
+	assert.Equal(t, []OrderItem{{
+		ProductID:       "111",
+		Name:            "Pizza",
+		ImageURL:        "https://example.com/pizza.jpg",
+		Quantity:        2,
+		Currency:        "IDR",
+		PriceAmount1000: 50000000,
+	}}, details.Items)
+	assert.Equal(t, "IDR", details.Currency)
+	assert.Equal(t, int64(100000000), details.TotalAmount1000)
+}
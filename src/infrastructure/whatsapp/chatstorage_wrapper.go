@@ -53,6 +53,13 @@ func (r *deviceChatStorage) StoreChat(chat *domainChatStorage.Chat) error {
 	return r.base.StoreChat(r.withDeviceChat(chat))
 }
 
+func (r *deviceChatStorage) UpdateChatState(deviceID, jid string, update domainChatStorage.ChatStateUpdate) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.UpdateChatState(deviceID, jid, update)
+}
+
 func (r *deviceChatStorage) GetChat(jid string) (*domainChatStorage.Chat, error) {
 	return r.base.GetChatByDevice(r.deviceID, jid)
 }
@@ -76,6 +83,24 @@ func (r *deviceChatStorage) DeleteChatByDevice(deviceID, jid string) error {
 	return r.base.DeleteChatByDevice(deviceID, jid)
 }
 
+func (r *deviceChatStorage) TrashChat(deviceID, jid string) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.TrashChat(deviceID, jid)
+}
+
+func (r *deviceChatStorage) RestoreChat(deviceID, jid string) (bool, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.RestoreChat(deviceID, jid)
+}
+
+func (r *deviceChatStorage) PurgeTrashedChats(before time.Time) (int, error) {
+	return r.base.PurgeTrashedChats(before)
+}
+
 func (r *deviceChatStorage) StoreMessage(message *domainChatStorage.Message) error {
 	return r.base.StoreMessage(message)
 }
@@ -91,8 +116,12 @@ func (r *deviceChatStorage) StoreMessagesBatch(messages []*domainChatStorage.Mes
 	return r.base.StoreMessagesBatch(messages)
 }
 
-func (r *deviceChatStorage) GetMessageByID(id string) (*domainChatStorage.Message, error) {
-	return r.base.GetMessageByID(id)
+func (r *deviceChatStorage) GetMessageByID(id, deviceID, chatJID string) (*domainChatStorage.Message, error) {
+	targetDeviceID := deviceID
+	if targetDeviceID == "" {
+		targetDeviceID = r.deviceID
+	}
+	return r.base.GetMessageByID(id, targetDeviceID, chatJID)
 }
 
 func (r *deviceChatStorage) GetMessageByIDAndDevice(deviceID, id string) (*domainChatStorage.Message, error) {
@@ -103,6 +132,14 @@ func (r *deviceChatStorage) GetMessageByIDAndDevice(deviceID, id string) (*domai
 	return r.base.GetMessageByIDAndDevice(targetDeviceID, id)
 }
 
+func (r *deviceChatStorage) GetMessageWithContext(deviceID, chatJID, id string, before, after int) (*domainChatStorage.MessageContext, error) {
+	targetDeviceID := deviceID
+	if targetDeviceID == "" {
+		targetDeviceID = r.deviceID
+	}
+	return r.base.GetMessageWithContext(targetDeviceID, chatJID, id, before, after)
+}
+
 func (r *deviceChatStorage) GetMessageEdits(originalMessageID, deviceID string) ([]*domainChatStorage.MessageEdit, error) {
 	targetDeviceID := deviceID
 	if targetDeviceID == "" {
@@ -118,6 +155,13 @@ func (r *deviceChatStorage) GetMessages(filter *domainChatStorage.MessageFilter)
 	return r.base.GetMessages(filter)
 }
 
+func (r *deviceChatStorage) IterateMessages(filter *domainChatStorage.MessageFilter, fn func(*domainChatStorage.Message) error) error {
+	if filter != nil && filter.DeviceID == "" {
+		filter.DeviceID = r.deviceID
+	}
+	return r.base.IterateMessages(filter, fn)
+}
+
 func (r *deviceChatStorage) SearchMessages(deviceID, chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
 	targetDeviceID := deviceID
 	if targetDeviceID == "" {
@@ -126,6 +170,10 @@ func (r *deviceChatStorage) SearchMessages(deviceID, chatJID, searchText string,
 	return r.base.SearchMessages(targetDeviceID, chatJID, searchText, limit)
 }
 
+func (r *deviceChatStorage) RebuildSearchIndex(ctx context.Context, progress func(step string, completed, total int)) error {
+	return r.base.RebuildSearchIndex(ctx, progress)
+}
+
 func (r *deviceChatStorage) DeleteMessage(id, chatJID string) error {
 	return r.base.DeleteMessageByDevice(r.deviceID, id, chatJID)
 }
@@ -188,6 +236,103 @@ func (r *deviceChatStorage) MarkChatwootForwardEventDone(id int64) error {
 	return r.base.MarkChatwootForwardEventDone(id)
 }
 
+func (r *deviceChatStorage) ListDueOutboxEvents(now time.Time, limit int) ([]*domainChatStorage.OutboxEvent, error) {
+	return r.base.ListDueOutboxEvents(now, limit)
+}
+
+func (r *deviceChatStorage) MarkOutboxEventDone(deviceID, messageID, eventName string) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.MarkOutboxEventDone(deviceID, messageID, eventName)
+}
+
+func (r *deviceChatStorage) MarkOutboxEventFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	return r.base.MarkOutboxEventFailed(id, lastError, nextAttemptAt)
+}
+
+func (r *deviceChatStorage) PruneOutboxEvents(deliveredBefore time.Time) (int64, error) {
+	return r.base.PruneOutboxEvents(deliveredBefore)
+}
+
+func (r *deviceChatStorage) EnqueueWebhookDelivery(delivery *domainChatStorage.WebhookDelivery) error {
+	if delivery != nil && delivery.DeviceID == "" {
+		delivery.DeviceID = r.deviceID
+	}
+	return r.base.EnqueueWebhookDelivery(delivery)
+}
+
+func (r *deviceChatStorage) ListDueWebhookDeliveries(now time.Time, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	return r.base.ListDueWebhookDeliveries(now, limit)
+}
+
+func (r *deviceChatStorage) MarkWebhookDeliveryFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	return r.base.MarkWebhookDeliveryFailed(id, lastError, nextAttemptAt)
+}
+
+func (r *deviceChatStorage) MarkWebhookDeliveryDead(id int64, lastError string) error {
+	return r.base.MarkWebhookDeliveryDead(id, lastError)
+}
+
+func (r *deviceChatStorage) MarkWebhookDeliveryDone(id int64) error {
+	return r.base.MarkWebhookDeliveryDone(id)
+}
+
+func (r *deviceChatStorage) ListDeadWebhookDeliveries(deviceID string, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.ListDeadWebhookDeliveries(deviceID, limit)
+}
+
+func (r *deviceChatStorage) RequeueWebhookDelivery(deviceID string, id int64) (bool, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.RequeueWebhookDelivery(deviceID, id)
+}
+
+func (r *deviceChatStorage) RequeueDeadWebhookDeliveries(deviceID string, since time.Time) (int64, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.RequeueDeadWebhookDeliveries(deviceID, since)
+}
+
+func (r *deviceChatStorage) EnqueueOutgoingMessage(message *domainChatStorage.OutgoingMessage) error {
+	if message != nil && message.DeviceID == "" {
+		message.DeviceID = r.deviceID
+	}
+	return r.base.EnqueueOutgoingMessage(message)
+}
+
+func (r *deviceChatStorage) ListDueOutgoingMessages(now time.Time) ([]*domainChatStorage.OutgoingMessage, error) {
+	return r.base.ListDueOutgoingMessages(now)
+}
+
+func (r *deviceChatStorage) MarkOutgoingMessageSent(id int64) error {
+	return r.base.MarkOutgoingMessageSent(id)
+}
+
+func (r *deviceChatStorage) MarkOutgoingMessageRetry(id int64, lastError string, nextAttemptAt time.Time) error {
+	return r.base.MarkOutgoingMessageRetry(id, lastError, nextAttemptAt)
+}
+
+func (r *deviceChatStorage) MarkOutgoingMessageFailed(id int64, lastError string) error {
+	return r.base.MarkOutgoingMessageFailed(id, lastError)
+}
+
+func (r *deviceChatStorage) ListOutgoingMessages(deviceID, status string, limit int) ([]*domainChatStorage.OutgoingMessage, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.ListOutgoingMessages(deviceID, status, limit)
+}
+
+func (r *deviceChatStorage) CountOutgoingMessages() (map[string]int64, error) {
+	return r.base.CountOutgoingMessages()
+}
+
 func (r *deviceChatStorage) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time, msg *waE2E.Message) error {
 	if _, ok := DeviceFromContext(ctx); !ok && r.deviceID != "" {
 		ctx = ContextWithDevice(ctx, NewDeviceInstance(r.deviceID, nil, nil))
@@ -195,6 +340,225 @@ func (r *deviceChatStorage) StoreSentMessageWithContext(ctx context.Context, mes
 	return r.base.StoreSentMessageWithContext(ctx, messageID, senderJID, recipientJID, content, timestamp, msg)
 }
 
+func (r *deviceChatStorage) GetChatStats(filter *domainChatStorage.ChatStatsFilter) (*domainChatStorage.ChatStats, error) {
+	if filter != nil && filter.DeviceID == "" {
+		filter.DeviceID = r.deviceID
+	}
+	return r.base.GetChatStats(filter)
+}
+
+func (r *deviceChatStorage) StorePoll(poll *domainChatStorage.Poll) error {
+	if poll != nil && poll.DeviceID == "" {
+		poll.DeviceID = r.deviceID
+	}
+	return r.base.StorePoll(poll)
+}
+
+func (r *deviceChatStorage) CreatePollVote(ctx context.Context, evt *events.Message, vote *waE2E.PollVoteMessage) error {
+	return r.base.CreatePollVote(ctx, evt, vote)
+}
+
+func (r *deviceChatStorage) GetPollResults(deviceID, messageID string) (*domainChatStorage.PollResults, error) {
+	return r.base.GetPollResults(deviceID, messageID)
+}
+
+func (r *deviceChatStorage) CreateMessagePin(ctx context.Context, evt *events.Message) error {
+	return r.base.CreateMessagePin(ctx, evt)
+}
+
+func (r *deviceChatStorage) StoreMessagePin(pin *domainChatStorage.MessagePin) error {
+	if pin != nil && pin.DeviceID == "" {
+		pin.DeviceID = r.deviceID
+	}
+	return r.base.StoreMessagePin(pin)
+}
+
+func (r *deviceChatStorage) DeleteMessagePin(deviceID, chatJID, messageID string) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.DeleteMessagePin(deviceID, chatJID, messageID)
+}
+
+func (r *deviceChatStorage) GetMessagePins(deviceID, chatJID string, now time.Time) ([]*domainChatStorage.MessagePin, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetMessagePins(deviceID, chatJID, now)
+}
+
+func (r *deviceChatStorage) StoreLIDMapping(lid, phoneJID string) error {
+	return r.base.StoreLIDMapping(lid, phoneJID)
+}
+
+func (r *deviceChatStorage) GetLIDMapping(lid string) (*domainChatStorage.LIDMapping, error) {
+	return r.base.GetLIDMapping(lid)
+}
+
+func (r *deviceChatStorage) GetLIDMappingByPhone(phoneJID string) (*domainChatStorage.LIDMapping, error) {
+	return r.base.GetLIDMappingByPhone(phoneJID)
+}
+
+func (r *deviceChatStorage) StoreGroupSnapshot(snapshot *domainChatStorage.GroupSnapshot) error {
+	if snapshot != nil && snapshot.DeviceID == "" {
+		snapshot.DeviceID = r.deviceID
+	}
+	return r.base.StoreGroupSnapshot(snapshot)
+}
+
+func (r *deviceChatStorage) GetGroupSnapshot(deviceID, groupJID string) (*domainChatStorage.GroupSnapshot, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetGroupSnapshot(deviceID, groupJID)
+}
+
+func (r *deviceChatStorage) GetGroupParticipantCounts(deviceID string, groupJIDs []string) (map[string]int, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetGroupParticipantCounts(deviceID, groupJIDs)
+}
+
+func (r *deviceChatStorage) StoreLabel(label *domainChatStorage.Label) error {
+	if label != nil && label.DeviceID == "" {
+		label.DeviceID = r.deviceID
+	}
+	return r.base.StoreLabel(label)
+}
+
+func (r *deviceChatStorage) DeleteLabel(deviceID, labelID string) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.DeleteLabel(deviceID, labelID)
+}
+
+func (r *deviceChatStorage) GetLabels(deviceID string) ([]*domainChatStorage.Label, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetLabels(deviceID)
+}
+
+func (r *deviceChatStorage) SetChatLabel(deviceID, chatJID, labelID string, labeled bool) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.SetChatLabel(deviceID, chatJID, labelID, labeled)
+}
+
+func (r *deviceChatStorage) SetMessageLabel(deviceID, chatJID, messageID, labelID string, labeled bool) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.SetMessageLabel(deviceID, chatJID, messageID, labelID, labeled)
+}
+
+func (r *deviceChatStorage) GetChatLabelIDs(deviceID string, chatJIDs []string) (map[string][]string, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetChatLabelIDs(deviceID, chatJIDs)
+}
+
+func (r *deviceChatStorage) GetMessageLabelIDs(deviceID, chatJID string, messageIDs []string) (map[string][]string, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetMessageLabelIDs(deviceID, chatJID, messageIDs)
+}
+
+func (r *deviceChatStorage) StoreDraft(draft *domainChatStorage.Draft) error {
+	if draft != nil && draft.DeviceID == "" {
+		draft.DeviceID = r.deviceID
+	}
+	return r.base.StoreDraft(draft)
+}
+
+func (r *deviceChatStorage) GetDraft(deviceID, chatJID string) (*domainChatStorage.Draft, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetDraft(deviceID, chatJID)
+}
+
+func (r *deviceChatStorage) GetDrafts(deviceID string) ([]*domainChatStorage.Draft, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetDrafts(deviceID)
+}
+
+func (r *deviceChatStorage) DeleteDraft(deviceID, chatJID string) (bool, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.DeleteDraft(deviceID, chatJID)
+}
+
+func (r *deviceChatStorage) StoreMessageTemplate(template *domainChatStorage.MessageTemplate) error {
+	if template != nil && template.DeviceID == "" {
+		template.DeviceID = r.deviceID
+	}
+	return r.base.StoreMessageTemplate(template)
+}
+
+func (r *deviceChatStorage) GetMessageTemplate(deviceID, name string) (*domainChatStorage.MessageTemplate, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetMessageTemplate(deviceID, name)
+}
+
+func (r *deviceChatStorage) GetMessageTemplates(deviceID string) ([]*domainChatStorage.MessageTemplate, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetMessageTemplates(deviceID)
+}
+
+func (r *deviceChatStorage) DeleteMessageTemplate(deviceID, name string) (bool, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.DeleteMessageTemplate(deviceID, name)
+}
+
+func (r *deviceChatStorage) StoreIdempotencyKey(key *domainChatStorage.IdempotencyKey) error {
+	if key != nil && key.DeviceID == "" {
+		key.DeviceID = r.deviceID
+	}
+	return r.base.StoreIdempotencyKey(key)
+}
+
+func (r *deviceChatStorage) GetIdempotencyKey(deviceID, key string, since time.Time) (*domainChatStorage.IdempotencyKey, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetIdempotencyKey(deviceID, key, since)
+}
+
+func (r *deviceChatStorage) DeleteIdempotencyKeysBefore(before time.Time) (int64, error) {
+	return r.base.DeleteIdempotencyKeysBefore(before)
+}
+
+func (r *deviceChatStorage) StoreJob(job *domainChatStorage.Job) error {
+	return r.base.StoreJob(job)
+}
+
+func (r *deviceChatStorage) GetJob(id string) (*domainChatStorage.Job, error) {
+	return r.base.GetJob(id)
+}
+
+func (r *deviceChatStorage) GetJobs(filter *domainChatStorage.JobFilter) ([]*domainChatStorage.Job, error) {
+	return r.base.GetJobs(filter)
+}
+
+func (r *deviceChatStorage) FailActiveJobs(reason string) (int64, error) {
+	return r.base.FailActiveJobs(reason)
+}
+
 func (r *deviceChatStorage) GetChatMessageCount(chatJID string) (int64, error) {
 	return r.base.GetChatMessageCountByDevice(r.deviceID, chatJID)
 }
@@ -204,11 +568,19 @@ func (r *deviceChatStorage) GetChatMessageCountByDevice(deviceID, chatJID string
 }
 
 func (r *deviceChatStorage) GetTotalMessageCount() (int64, error) {
-	return r.base.GetTotalMessageCount()
+	return r.base.GetTotalMessageCountByDevice(r.deviceID)
 }
 
 func (r *deviceChatStorage) GetTotalChatCount() (int64, error) {
-	return r.base.GetTotalChatCount()
+	return r.base.GetTotalChatCountByDevice(r.deviceID)
+}
+
+func (r *deviceChatStorage) GetTotalMessageCountByDevice(deviceID string) (int64, error) {
+	return r.base.GetTotalMessageCountByDevice(deviceID)
+}
+
+func (r *deviceChatStorage) GetTotalChatCountByDevice(deviceID string) (int64, error) {
+	return r.base.GetTotalChatCountByDevice(deviceID)
 }
 
 func (r *deviceChatStorage) GetFilteredChatCount(filter *domainChatStorage.ChatFilter) (int64, error) {
@@ -242,6 +614,26 @@ func (r *deviceChatStorage) InitializeSchema() error {
 	return r.base.InitializeSchema()
 }
 
+func (r *deviceChatStorage) CheckpointWAL(ctx context.Context) (*domainChatStorage.WALCheckpoint, error) {
+	return r.base.CheckpointWAL(ctx)
+}
+
+func (r *deviceChatStorage) IncrementalVacuum(ctx context.Context, maxPages int) (int64, error) {
+	return r.base.IncrementalVacuum(ctx, maxPages)
+}
+
+func (r *deviceChatStorage) GetStorageSize(ctx context.Context) (*domainChatStorage.StorageSize, error) {
+	return r.base.GetStorageSize(ctx)
+}
+
+func (r *deviceChatStorage) CompactStorage(ctx context.Context) (*domainChatStorage.CompactionResult, error) {
+	return r.base.CompactStorage(ctx)
+}
+
+func (r *deviceChatStorage) FlushPendingWrites() error {
+	return r.base.FlushPendingWrites()
+}
+
 func (r *deviceChatStorage) DeleteDeviceData(deviceID string) error {
 	if r.base == nil {
 		return nil
@@ -279,6 +671,14 @@ func (r *deviceChatStorage) MergeLIDChat(deviceID, lidJID, phoneJID string) erro
 	return r.base.MergeLIDChat(target, lidJID, phoneJID)
 }
 
+func (r *deviceChatStorage) MergeChats(deviceID, sourceJID, targetJID string, dryRun bool) (*domainChatStorage.ChatMergeSummary, error) {
+	target := deviceID
+	if target == "" {
+		target = r.deviceID
+	}
+	return r.base.MergeChats(target, sourceJID, targetJID, dryRun)
+}
+
 func (r *deviceChatStorage) GetLIDChats(deviceID string) ([]*domainChatStorage.Chat, error) {
 	target := deviceID
 	if target == "" {
@@ -286,3 +686,60 @@ func (r *deviceChatStorage) GetLIDChats(deviceID string) ([]*domainChatStorage.C
 	}
 	return r.base.GetLIDChats(target)
 }
+
+func (r *deviceChatStorage) GetPhoneChats(deviceID string) ([]*domainChatStorage.Chat, error) {
+	target := deviceID
+	if target == "" {
+		target = r.deviceID
+	}
+	return r.base.GetPhoneChats(target)
+}
+
+func (r *deviceChatStorage) GetEmptyChats(deviceID string) ([]string, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetEmptyChats(deviceID)
+}
+
+func (r *deviceChatStorage) GetMissingChats(deviceID string) ([]*domainChatStorage.MissingChat, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetMissingChats(deviceID)
+}
+
+func (r *deviceChatStorage) RestoreMissingChats(deviceID string) (int64, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.RestoreMissingChats(deviceID)
+}
+
+func (r *deviceChatStorage) GetMedia(deviceID string, fileSHA256 []byte) (*domainChatStorage.Media, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetMedia(deviceID, fileSHA256)
+}
+
+func (r *deviceChatStorage) SetMediaLocalPath(deviceID string, fileSHA256 []byte, localPath string) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.SetMediaLocalPath(deviceID, fileSHA256, localPath)
+}
+
+func (r *deviceChatStorage) GetMessagesByMedia(deviceID string, fileSHA256 []byte) ([]*domainChatStorage.Message, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetMessagesByMedia(deviceID, fileSHA256)
+}
+
+func (r *deviceChatStorage) DeleteUnreferencedMedia(deviceID string) ([]*domainChatStorage.Media, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.DeleteUnreferencedMedia(deviceID)
+}
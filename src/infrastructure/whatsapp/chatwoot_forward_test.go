@@ -146,6 +146,7 @@ func TestShouldForwardEventToChatwoot(t *testing.T) {
 		cases := map[string]bool{
 			"message":          true,
 			"message.reaction": true,
+			"message.outgoing": true,
 			"message.ack":      false,
 			"chat_presence":    false,
 		}
@@ -207,7 +208,7 @@ func TestShouldForwardEventToChatwoot(t *testing.T) {
 func TestIsRetryableChatwootForwardEvent(t *testing.T) {
 	// Base messages and their sub-events carry a unique WhatsApp id and are
 	// retry-eligible; read receipts are best-effort and must not be queued.
-	for _, e := range []string{"message", "message.edited", "message.revoked", "message.deleted", "message.reaction"} {
+	for _, e := range []string{"message", "message.edited", "message.revoked", "message.deleted", "message.reaction", "message.outgoing"} {
 		if !isRetryableChatwootForwardEvent(e) {
 			t.Errorf("%q should be retry-eligible", e)
 		}
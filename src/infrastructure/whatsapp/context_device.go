@@ -3,6 +3,7 @@ package whatsapp
 import (
 	"context"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"go.mau.fi/whatsmeow"
 )
 
@@ -13,7 +14,13 @@ func ContextWithDevice(ctx context.Context, device *DeviceInstance) context.Cont
 	if ctx == nil {
 		return context.Background()
 	}
-	return context.WithValue(ctx, deviceContextKey{}, device)
+	ctx = context.WithValue(ctx, deviceContextKey{}, device)
+	// A nil resolver makes lookups fall back to the resolver without a device.
+	var resolver *utils.LIDResolver
+	if device != nil {
+		resolver = device.LIDResolver()
+	}
+	return utils.ContextWithLIDResolver(ctx, resolver)
 }
 
 // DeviceFromContext retrieves a device instance from context if present.
@@ -40,3 +47,13 @@ func ClientFromContext(ctx context.Context) *whatsmeow.Client {
 	// No device in context - fall back to global client for backward compatibility
 	return GetClient()
 }
+
+// webhookContext returns a background context that still carries the device
+// from ctx. Webhook delivery outlives the event handler, but it needs the
+// device to reach its chat storage (outbox, retry queue, Chatwoot links).
+func webhookContext(ctx context.Context) context.Context {
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		return ContextWithDevice(context.Background(), inst)
+	}
+	return context.Background()
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"go.mau.fi/whatsmeow"
 	"golang.org/x/net/proxy"
 )
@@ -23,6 +24,7 @@ type DeviceInstance struct {
 	id              string
 	client          *whatsmeow.Client
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	lidResolver     *utils.LIDResolver
 	state           domainDevice.DeviceState
 	displayName     string
 	phoneNumber     string
@@ -44,6 +46,7 @@ func NewDeviceInstance(deviceID string, client *whatsmeow.Client, chatStorageRep
 		id:              deviceID,
 		client:          client,
 		chatStorageRepo: chatStorageRepo,
+		lidResolver:     utils.NewLIDResolver(NewLIDMappingStore(chatStorageRepo)),
 		state:           domainDevice.DeviceStateDisconnected,
 		displayName:     display,
 		jid:             jid,
@@ -67,6 +70,14 @@ func (d *DeviceInstance) GetChatStorage() domainChatStorage.IChatStorageReposito
 	return d.chatStorageRepo
 }
 
+// LIDResolver returns the resolver translating LIDs for this device.
+func (d *DeviceInstance) LIDResolver() *utils.LIDResolver {
+	if d.lidResolver == nil {
+		return utils.LIDResolverFromContext(context.Background())
+	}
+	return d.lidResolver
+}
+
 func (d *DeviceInstance) SetState(state domainDevice.DeviceState) {
 	d.mu.Lock()
 	d.state = state
@@ -115,6 +126,9 @@ func (d *DeviceInstance) SetChatStorage(repo domainChatStorage.IChatStorageRepos
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	d.chatStorageRepo = repo
+	if d.lidResolver != nil {
+		d.lidResolver.SetStore(NewLIDMappingStore(repo))
+	}
 }
 
 // IsConnected returns the live connection flag if a client exists.
@@ -0,0 +1,62 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/eventbus"
+	"github.com/sirupsen/logrus"
+)
+
+// eventPublisher receives every event forwarded to webhooks when an event bus
+// is configured. It is set once at startup, before events arrive.
+var eventPublisher eventbus.Publisher
+
+// SetEventPublisher publishes events to p alongside the configured webhooks.
+func SetEventPublisher(p eventbus.Publisher) {
+	eventPublisher = p
+}
+
+// eventBusTopic names the topic (subject, routing key, stream or channel) of
+// an event: the WhatsappEventBusTopics override for it, otherwise
+// WhatsappEventBusTopic, or the driver's default, with "{event}" and
+// "{device_id}" filled in.
+func eventBusTopic(eventName string, payload map[string]any) string {
+	pattern := config.WhatsappEventBusTopic
+	if pattern == "" {
+		pattern = eventbus.DefaultTopic(config.WhatsappEventBusDriver)
+	}
+	for _, entry := range config.WhatsappEventBusTopics {
+		event, topic, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if found && strings.TrimSpace(event) == eventName && strings.TrimSpace(topic) != "" {
+			pattern = strings.TrimSpace(topic)
+			break
+		}
+	}
+	deviceID, _ := payload["device_id"].(string)
+	return strings.NewReplacer("{event}", eventName, "{device_id}", deviceID).Replace(pattern)
+}
+
+// publishEvent publishes a webhook payload to the event bus, keyed by its chat.
+// Failures are logged; they do not affect webhook delivery.
+func publishEvent(ctx context.Context, payload map[string]any, eventName string) {
+	if eventPublisher == nil {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logrus.Errorf("Event bus: failed to serialize %s: %v", eventName, err)
+		return
+	}
+	topic := eventBusTopic(eventName, payload)
+	publishCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := eventPublisher.Publish(publishCtx, topic, webhookChatJID(payload), body); err != nil {
+		logrus.Errorf("Event bus: failed to publish %s to %s: %v", eventName, topic, err)
+		return
+	}
+	logrus.Debugf("Event bus: published %s to %s", eventName, topic)
+}
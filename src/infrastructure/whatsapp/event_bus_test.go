@@ -0,0 +1,87 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+type publishedEvent struct {
+	topic, key string
+	body       map[string]any
+}
+
+type fakePublisher struct {
+	published []publishedEvent
+}
+
+func (p *fakePublisher) Publish(_ context.Context, topic, key string, body []byte) error {
+	event := publishedEvent{topic: topic, key: key}
+	if err := json.Unmarshal(body, &event.body); err != nil {
+		return err
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func (p *fakePublisher) Close() error { return nil }
+
+func TestForwardPayloadPublishesToEventBusWithoutWebhooks(t *testing.T) {
+	publisher := &fakePublisher{}
+	prevPublisher, prevWebhooks, prevTopic, prevTopics := eventPublisher, config.WhatsappWebhook, config.WhatsappEventBusTopic, config.WhatsappEventBusTopics
+	eventPublisher, config.WhatsappWebhook = publisher, nil
+	config.WhatsappEventBusTopic, config.WhatsappEventBusTopics = "gowa.{event}", []string{"message.ack=gowa-receipts"}
+	t.Cleanup(func() {
+		eventPublisher, config.WhatsappWebhook, config.WhatsappEventBusTopic, config.WhatsappEventBusTopics = prevPublisher, prevWebhooks, prevTopic, prevTopics
+	})
+
+	if !hasWebhookTargets() {
+		t.Fatal("an event bus alone should count as a webhook target")
+	}
+
+	ctx := context.Background()
+	for _, eventName := range []string{"message", "message.ack"} {
+		payload := map[string]any{
+			"event":   eventName,
+			"payload": map[string]any{"chat_id": "120363000000000001@g.us", "from": "628123456789@s.whatsapp.net"},
+		}
+		if err := forwardPayloadToConfiguredWebhooks(ctx, payload, eventName); err != nil {
+			t.Fatalf("forward %s: %v", eventName, err)
+		}
+	}
+
+	if len(publisher.published) != 2 {
+		t.Fatalf("published %d events, want 2", len(publisher.published))
+	}
+	if got := publisher.published[0]; got.topic != "gowa.message" || got.key != "120363000000000001@g.us" || got.body["event"] != "message" {
+		t.Errorf("message published as %+v", got)
+	}
+	if got := publisher.published[1].topic; got != "gowa-receipts" {
+		t.Errorf("message.ack topic = %q, want the override", got)
+	}
+}
+
+func TestEventBusTopic_FillsPlaceholders(t *testing.T) {
+	prevDriver, prevTopic, prevTopics := config.WhatsappEventBusDriver, config.WhatsappEventBusTopic, config.WhatsappEventBusTopics
+	t.Cleanup(func() {
+		config.WhatsappEventBusDriver, config.WhatsappEventBusTopic, config.WhatsappEventBusTopics = prevDriver, prevTopic, prevTopics
+	})
+	payload := map[string]any{"device_id": "628000@s.whatsapp.net"}
+
+	config.WhatsappEventBusTopic, config.WhatsappEventBusTopics = "", []string{"call.offer=calls:{device_id}"}
+	for driver, want := range map[string]string{
+		"redis-stream": "whatsapp:628000@s.whatsapp.net",
+		"redis-pubsub": "whatsapp.message",
+		"kafka":        "whatsapp.message",
+	} {
+		config.WhatsappEventBusDriver = driver
+		if got := eventBusTopic("message", payload); got != want {
+			t.Errorf("%s default topic = %q, want %q", driver, got, want)
+		}
+	}
+	if got := eventBusTopic("call.offer", payload); got != "calls:628000@s.whatsapp.net" {
+		t.Errorf("override topic = %q", got)
+	}
+}
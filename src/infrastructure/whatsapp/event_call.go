@@ -3,13 +3,19 @@ package whatsapp
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
 )
 
 // handleCallOffer handles incoming call events and optionally auto-rejects them
@@ -18,7 +24,7 @@ func handleCallOffer(ctx context.Context, evt *events.CallOffer, chatStorageRepo
 
 	// Auto-reject call if configured
 	autoRejected := false
-	if config.WhatsappAutoRejectCall {
+	if config.Setting(&config.WhatsappAutoRejectCall) {
 		rejectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
@@ -27,8 +33,10 @@ func handleCallOffer(ctx context.Context, evt *events.CallOffer, chatStorageRepo
 		} else {
 			autoRejected = true
 			logrus.Infof("Auto-rejected call from %s (CallID: %s)", evt.CallCreator.String(), evt.CallID)
+			sendAutoRejectCallMessage(rejectCtx, evt.BasicCallMeta, chatStorageRepo, client)
 		}
 	}
+	trackCall(evt.CallID, evt.Timestamp, autoRejected)
 
 	if chatStorageRepo != nil {
 		if err := chatStorageRepo.CreateIncomingCallRecord(ctx, evt, autoRejected); err != nil {
@@ -43,14 +51,14 @@ func handleCallOffer(ctx context.Context, evt *events.CallOffer, chatStorageRepo
 	}
 
 	// Forward call event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.CallOffer, c *whatsmeow.Client, rejected bool) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.From.ToNonAD().String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardCallOfferToWebhook(webhookCtx, e, deviceID, c, rejected); err != nil {
+			if err := forwardCallOfferToWebhook(webhookCtx, evt, deviceID, client, autoRejected); err != nil {
 				logrus.Errorf("Failed to forward call event to webhook: %v", err)
 			}
-		}(evt, client, autoRejected)
+		})
 	}
 }
 
@@ -61,7 +69,7 @@ func createCallOfferPayload(ctx context.Context, evt *events.CallOffer, deviceID
 
 	// Add call details
 	payload["call_id"] = evt.CallID
-	payload["from"] = evt.CallCreator.ToNonAD().String()
+	addCallerFields(ctx, client, evt.BasicCallMeta, payload)
 	payload["auto_rejected"] = autoRejected
 
 	// Add caller platform info if available
@@ -93,3 +101,160 @@ func forwardCallOfferToWebhook(ctx context.Context, evt *events.CallOffer, devic
 	payload := createCallOfferPayload(ctx, evt, deviceID, client, autoRejected)
 	return forwardPayloadToConfiguredWebhooks(ctx, payload, "call.offer")
 }
+
+// callerJIDs returns the phone number and LID JIDs of a call's creator, each
+// empty when unknown. WhatsApp sends one of them as CallCreator and, for newer
+// clients, the other as CallCreatorAlt.
+func callerJIDs(ctx context.Context, client *whatsmeow.Client, meta types.BasicCallMeta) (pn, lid types.JID) {
+	for _, jid := range []types.JID{meta.CallCreator.ToNonAD(), meta.CallCreatorAlt.ToNonAD()} {
+		switch jid.Server {
+		case types.DefaultUserServer:
+			if pn.IsEmpty() {
+				pn = jid
+			}
+		case types.HiddenUserServer:
+			if lid.IsEmpty() {
+				lid = jid
+			}
+		}
+	}
+	if pn.IsEmpty() && !lid.IsEmpty() {
+		if resolved := utils.ResolveLIDToPhone(ctx, lid, client).ToNonAD(); resolved.Server == types.DefaultUserServer {
+			pn = resolved
+		}
+	}
+	if lid.IsEmpty() && !pn.IsEmpty() {
+		lid = utils.ResolvePhoneToLID(ctx, pn, client)
+	}
+	return pn, lid
+}
+
+// addCallerFields sets from, the caller's phone number when known, and from_lid.
+func addCallerFields(ctx context.Context, client *whatsmeow.Client, meta types.BasicCallMeta, payload map[string]any) {
+	pn, lid := callerJIDs(ctx, client, meta)
+	switch {
+	case !pn.IsEmpty():
+		payload["from"] = pn.String()
+	case !lid.IsEmpty():
+		payload["from"] = lid.String()
+	default:
+		payload["from"] = meta.CallCreator.ToNonAD().String()
+	}
+	if !lid.IsEmpty() {
+		payload["from_lid"] = lid.String()
+	}
+}
+
+// sendAutoRejectCallMessage texts the caller WhatsappAutoRejectCallMessage
+// after a call was auto-rejected, e.g. to point them to chat instead.
+func sendAutoRejectCallMessage(ctx context.Context, meta types.BasicCallMeta, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	text := strings.TrimSpace(config.Setting(&config.WhatsappAutoRejectCallMessage))
+	if text == "" {
+		return
+	}
+	recipient, _ := callerJIDs(ctx, client, meta)
+	if recipient.IsEmpty() {
+		recipient = meta.CallCreator.ToNonAD()
+	}
+
+	response, err := client.SendMessage(ctx, recipient, &waE2E.Message{Conversation: proto.String(text)})
+	if err != nil {
+		logrus.Errorf("Failed to send auto-reject call message to %s: %v", recipient, err)
+		return
+	}
+	if chatStorageRepo == nil || client.Store.ID == nil {
+		return
+	}
+	if err := chatStorageRepo.StoreSentMessageWithContext(ctx, response.ID, client.Store.ID.String(), recipient.String(), text, response.Timestamp, nil); err != nil {
+		logrus.Errorf("Failed to store auto-reject call message in chat storage: %v", err)
+	}
+}
+
+// callState is what call.ended reports about a call: when it was offered,
+// whether it was answered (on any of the user's devices) and when.
+type callState struct {
+	offeredAt    time.Time
+	acceptedAt   time.Time
+	autoRejected bool
+}
+
+// callStateTTL bounds how long a call without a terminate event is tracked.
+const callStateTTL = 6 * time.Hour
+
+var calls = struct {
+	sync.Mutex
+	state map[string]*callState
+}{state: make(map[string]*callState)}
+
+func trackCall(callID string, offeredAt time.Time, autoRejected bool) {
+	calls.Lock()
+	defer calls.Unlock()
+	for id, state := range calls.state {
+		if time.Since(state.offeredAt) > callStateTTL {
+			delete(calls.state, id)
+		}
+	}
+	calls.state[callID] = &callState{offeredAt: offeredAt, autoRejected: autoRejected}
+}
+
+// handleCallAccept records that a call was answered, on this or another device.
+func handleCallAccept(_ context.Context, evt *events.CallAccept) {
+	logrus.Infof("Call %s accepted", evt.CallID)
+	calls.Lock()
+	defer calls.Unlock()
+	if state, ok := calls.state[evt.CallID]; ok && state.acceptedAt.IsZero() {
+		state.acceptedAt = evt.Timestamp
+	}
+}
+
+// handleCallTerminate forwards the end of a call as a call.ended event.
+func handleCallTerminate(ctx context.Context, evt *events.CallTerminate, deviceID string, client *whatsmeow.Client) {
+	logrus.Infof("Call %s from %s ended: %s", evt.CallID, evt.CallCreator.String(), evt.Reason)
+
+	calls.Lock()
+	state := calls.state[evt.CallID]
+	delete(calls.state, evt.CallID)
+	calls.Unlock()
+
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.From.ToNonAD().String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
+			defer cancel()
+			payload := createCallEndedPayload(webhookCtx, evt, state, deviceID, client)
+			if err := forwardPayloadToConfiguredWebhooks(webhookCtx, payload, "call.ended"); err != nil {
+				logrus.Errorf("Failed to forward call.ended event to webhook: %v", err)
+			}
+		})
+	}
+}
+
+// createCallEndedPayload creates a webhook payload for a call that ended. state
+// is nil for calls offered before the app started.
+func createCallEndedPayload(ctx context.Context, evt *events.CallTerminate, state *callState, deviceID string, client *whatsmeow.Client) map[string]any {
+	payload := map[string]any{"call_id": evt.CallID}
+	addCallerFields(ctx, client, evt.BasicCallMeta, payload)
+	if evt.Reason != "" {
+		payload["reason"] = evt.Reason
+	}
+	if !evt.GroupJID.IsEmpty() {
+		payload["group_jid"] = evt.GroupJID.ToNonAD().String()
+	}
+	if state != nil {
+		answered := !state.acceptedAt.IsZero()
+		payload["answered"] = answered
+		payload["auto_rejected"] = state.autoRejected
+		if answered {
+			payload["duration_seconds"] = int(evt.Timestamp.Sub(state.acceptedAt).Seconds())
+		}
+	}
+
+	body := map[string]any{
+		"event":     "call.ended",
+		"timestamp": evt.Timestamp.Format(time.RFC3339),
+		"payload":   payload,
+	}
+	if deviceID != "" {
+		body["device_id"] = deviceID
+	}
+	return body
+}
@@ -0,0 +1,63 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateCallOfferPayload_ResolvesCallerFromAlt(t *testing.T) {
+	evt := &events.CallOffer{BasicCallMeta: types.BasicCallMeta{
+		From:           types.NewJID("251556368777322", types.HiddenUserServer),
+		CallCreator:    types.NewJID("251556368777322", types.HiddenUserServer),
+		CallCreatorAlt: types.NewJID("628987654321", types.DefaultUserServer),
+		CallID:         "CALL1",
+	}}
+
+	payload := createCallOfferPayload(context.Background(), evt, "", nil, false)["payload"].(map[string]any)
+	if payload["from"] != "628987654321@s.whatsapp.net" || payload["from_lid"] != "251556368777322@lid" {
+		t.Errorf("caller fields = %v, %v", payload["from"], payload["from_lid"])
+	}
+}
+
+func TestCallEnded_ReportsAnsweredDuration(t *testing.T) {
+	offered := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	meta := types.BasicCallMeta{
+		From:        types.NewJID("628987654321", types.DefaultUserServer),
+		CallCreator: types.NewJID("628987654321", types.DefaultUserServer),
+		CallID:      "CALL2",
+		Timestamp:   offered,
+	}
+	trackCall(meta.CallID, offered, false)
+	acceptMeta := meta
+	acceptMeta.Timestamp = offered.Add(5 * time.Second)
+	handleCallAccept(context.Background(), &events.CallAccept{BasicCallMeta: acceptMeta})
+
+	calls.Lock()
+	state := calls.state[meta.CallID]
+	delete(calls.state, meta.CallID)
+	calls.Unlock()
+
+	endMeta := meta
+	endMeta.Timestamp = offered.Add(95 * time.Second)
+	body := createCallEndedPayload(context.Background(), &events.CallTerminate{BasicCallMeta: endMeta, Reason: "timeout"}, state, "628000@s.whatsapp.net", nil)
+	payload := body["payload"].(map[string]any)
+
+	if body["event"] != "call.ended" || payload["reason"] != "timeout" || payload["answered"] != true || payload["duration_seconds"] != 90 {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestCallEnded_UnknownCallOmitsOutcome(t *testing.T) {
+	evt := &events.CallTerminate{BasicCallMeta: types.BasicCallMeta{
+		CallCreator: types.NewJID("628987654321", types.DefaultUserServer),
+		CallID:      "CALL3",
+	}}
+	payload := createCallEndedPayload(context.Background(), evt, nil, "", nil)["payload"].(map[string]any)
+	if _, ok := payload["answered"]; ok {
+		t.Errorf("a call offered before startup has no known outcome: %+v", payload)
+	}
+}
@@ -0,0 +1,38 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Campaign webhook events
+const (
+	EventTypeCampaignRecipient = "campaign.recipient"
+	EventTypeCampaignCompleted = "campaign.completed"
+)
+
+// ForwardCampaignEvent forwards the progress of a bulk send campaign to the
+// configured webhooks. Events of one campaign are delivered in order, in the
+// background, so a slow receiver never throttles the campaign further.
+func ForwardCampaignEvent(ctx context.Context, campaignID, eventName string, payload map[string]any) {
+	if !hasWebhookTargets() {
+		return
+	}
+	deviceID := webhookDeviceID(ctx)
+	payload["campaign_id"] = campaignID
+	body := map[string]any{
+		"event":     eventName,
+		"device_id": deviceID,
+		"payload":   payload,
+	}
+	ctx = context.WithoutCancel(ctx)
+	dispatchChatWebhook(deviceID, "campaign:"+campaignID, func() {
+		webhookCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		if err := forwardPayloadToConfiguredWebhooks(webhookCtx, body, eventName); err != nil {
+			logrus.Errorf("Failed to forward %s for campaign %s: %v", eventName, campaignID, err)
+		}
+	})
+}
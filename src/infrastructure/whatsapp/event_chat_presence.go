@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
@@ -27,14 +26,17 @@ func handleChatPresence(ctx context.Context, evt *events.ChatPresence, deviceID
 	}
 
 	// Forward chat presence event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.ChatPresence, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.Chat.ToNonAD().String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardChatPresenceToWebhook(webhookCtx, e, deviceID, c); err != nil {
+			if err := forwardChatPresenceToWebhook(webhookCtx, evt, deviceID, client); err != nil {
 				logrus.Errorf("Failed to forward chat_presence event to webhook: %v", err)
 			}
-		}(evt, client)
+		})
+	}
+	if presenceUpdatesEnabled() {
+		forwardPresenceUpdate(ctx, deviceID, createChatPresenceUpdatePayload(ctx, evt, deviceID, client))
 	}
 }
 
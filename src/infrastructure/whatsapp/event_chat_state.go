@@ -0,0 +1,97 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func handlePin(ctx context.Context, evt *events.Pin, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	if evt == nil || evt.Action == nil || evt.Action.Pinned == nil {
+		return
+	}
+	pinned := evt.Action.GetPinned()
+	updateChatStateFromAppState(ctx, evt.JID, chatStorageRepo, client, "pin", domainChatStorage.ChatStateUpdate{Pinned: &pinned})
+}
+
+func handleMute(ctx context.Context, evt *events.Mute, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	if evt == nil || evt.Action == nil {
+		return
+	}
+	mutedUntil := MuteEndTime(evt.Action.GetMuted(), evt.Action.GetMuteEndTimestamp())
+	updateChatStateFromAppState(ctx, evt.JID, chatStorageRepo, client, "mute", domainChatStorage.ChatStateUpdate{MutedUntil: &mutedUntil})
+}
+
+func handleMarkChatAsRead(ctx context.Context, evt *events.MarkChatAsRead, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	if evt == nil || evt.Action == nil || evt.Action.Read == nil {
+		return
+	}
+
+	unread := 0
+	if !evt.Action.GetRead() {
+		// "Mark as unread" carries no count; keep an existing count or flag the chat with one.
+		unread = 1
+		if chat := chatForAppState(ctx, evt.JID, chatStorageRepo, client); chat != nil && chat.UnreadCount > 0 {
+			unread = chat.UnreadCount
+		}
+	}
+	updateChatStateFromAppState(ctx, evt.JID, chatStorageRepo, client, "mark_read", domainChatStorage.ChatStateUpdate{UnreadCount: &unread})
+}
+
+// MuteEndTime converts a WhatsApp mute action (end timestamp in milliseconds, -1 for
+// "always") into the time stored on the chat. The zero time means not muted.
+func MuteEndTime(muted bool, muteEndTimestampMs int64) time.Time {
+	switch {
+	case !muted:
+		return time.Time{}
+	case muteEndTimestampMs < 0:
+		return store.MutedForever
+	default:
+		return time.UnixMilli(muteEndTimestampMs).UTC()
+	}
+}
+
+func chatForAppState(ctx context.Context, jid types.JID, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) *domainChatStorage.Chat {
+	if chatStorageRepo == nil || client == nil || client.Store == nil || client.Store.ID == nil {
+		return nil
+	}
+	deviceID := client.Store.ID.ToNonAD().String()
+	chat, err := chatStorageRepo.GetChatByDevice(deviceID, utils.ResolveLIDToPhone(ctx, jid, client).String())
+	if err != nil {
+		return nil
+	}
+	return chat
+}
+
+// updateChatStateFromAppState applies an app state flag change to a stored chat.
+// Chats that were never stored locally are skipped, mirroring handleArchive.
+func updateChatStateFromAppState(ctx context.Context, jid types.JID, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client, action string, update domainChatStorage.ChatStateUpdate) {
+	if chatStorageRepo == nil || client == nil || client.Store == nil || client.Store.ID == nil {
+		return
+	}
+
+	deviceID := client.Store.ID.ToNonAD().String()
+	jidStr := utils.ResolveLIDToPhone(ctx, jid, client).String()
+	logFields := logrus.Fields{"device_id": deviceID, "jid": jidStr, "action": action}
+
+	chat, err := chatStorageRepo.GetChatByDevice(deviceID, jidStr)
+	if err != nil {
+		logrus.WithError(err).WithFields(logFields).Debug("Failed to get chat for app state update")
+		return
+	}
+	if chat == nil {
+		logrus.WithFields(logFields).Debug("Chat not found for app state update, skipping")
+		return
+	}
+
+	if err := chatStorageRepo.UpdateChatState(deviceID, jidStr, update); err != nil {
+		logrus.WithError(err).WithFields(logFields).Error("Failed to update chat state")
+	}
+}
@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/store"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMuteEndTime(t *testing.T) {
+	end := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := MuteEndTime(false, end.UnixMilli()); !got.IsZero() {
+		t.Errorf("unmute = %v, want zero time", got)
+	}
+	if got := MuteEndTime(true, -1); !got.Equal(store.MutedForever) {
+		t.Errorf("mute always = %v, want MutedForever", got)
+	}
+	if got := MuteEndTime(true, end.UnixMilli()); !got.Equal(end) {
+		t.Errorf("mute until = %v, want %v", got, end)
+	}
+}
+
+func TestHistorySyncChatState_OnlySetsPresentFields(t *testing.T) {
+	if update := historySyncChatState(&waHistorySync.Conversation{}); update.Pinned != nil || update.MutedUntil != nil || update.UnreadCount != nil || update.Archived != nil {
+		t.Fatalf("empty conversation produced update %+v", update)
+	}
+
+	end := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+	update := historySyncChatState(&waHistorySync.Conversation{
+		Pinned:      proto.Uint32(uint32(end.Unix())),
+		MuteEndTime: proto.Uint64(uint64(end.Unix())),
+		UnreadCount: proto.Uint32(3),
+	})
+	if update.Pinned == nil || !*update.Pinned {
+		t.Errorf("pinned = %v, want true", update.Pinned)
+	}
+	if update.MutedUntil == nil || !update.MutedUntil.Equal(end) {
+		t.Errorf("muted until = %v, want %v", update.MutedUntil, end)
+	}
+	if update.UnreadCount == nil || *update.UnreadCount != 3 {
+		t.Errorf("unread count = %v, want 3", update.UnreadCount)
+	}
+
+	unmuted := historySyncChatState(&waHistorySync.Conversation{Pinned: proto.Uint32(0), MuteEndTime: proto.Uint64(0)})
+	if unmuted.Pinned == nil || *unmuted.Pinned || unmuted.MutedUntil == nil || !unmuted.MutedUntil.IsZero() {
+		t.Errorf("cleared flags = %+v, want unpinned and unmuted", unmuted)
+	}
+}
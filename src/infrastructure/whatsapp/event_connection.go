@@ -0,0 +1,114 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skip2/go-qrcode"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Connection lifecycle events, so provisioning systems can drive pairing and
+// follow a device's connection without polling the login endpoints.
+const (
+	EventTypeQRCodeGenerated = "qr_code_generated"
+	EventTypePairSuccess     = "pair_success"
+	EventTypeConnected       = "connected"
+	EventTypeDisconnected    = "disconnected"
+	EventTypeLoggedOut       = "logged_out"
+	EventTypeStreamReplaced  = "stream_replaced"
+)
+
+// connectionEventBody wraps a lifecycle payload. It always carries the
+// session_id, since device_id stays empty until the device is paired.
+func connectionEventBody(instance *DeviceInstance, eventName string, payload map[string]any) map[string]any {
+	body := map[string]any{
+		"event":     eventName,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"payload":   payload,
+	}
+	if instance != nil {
+		if jid := instance.JID(); jid != "" {
+			body["device_id"] = jid
+		}
+		body["session_id"] = instance.ID()
+	}
+	return body
+}
+
+// forwardConnectionEvent delivers a lifecycle event in the background.
+func forwardConnectionEvent(body map[string]any, eventName string) {
+	if !hasWebhookTargets() {
+		return
+	}
+	go deliverConnectionEvent(body, eventName, 30*time.Second)
+}
+
+func deliverConnectionEvent(body map[string]any, eventName string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := forwardPayloadToConfiguredWebhooks(ctx, body, eventName); err != nil {
+		logrus.Errorf("Failed to forward %s to webhook: %v", eventName, err)
+	}
+}
+
+// NotifyQRCode sends a qr_code_generated event for a login QR code, with the
+// code rendered as a base64 PNG, valid for timeout.
+func NotifyQRCode(instance *DeviceInstance, code string, timeout time.Duration) {
+	if !hasWebhookTargets() {
+		return
+	}
+	png, err := qrcode.Encode(code, qrcode.Medium, 512)
+	if err != nil {
+		logrus.Errorf("Failed to render QR code for webhook: %v", err)
+		return
+	}
+	forwardConnectionEvent(connectionEventBody(instance, EventTypeQRCodeGenerated, map[string]any{
+		"code":            code,
+		"qr_image":        base64.StdEncoding.EncodeToString(png),
+		"timeout_seconds": int(timeout.Seconds()),
+	}), EventTypeQRCodeGenerated)
+}
+
+func createPairSuccessPayload(instance *DeviceInstance, evt *events.PairSuccess) map[string]any {
+	payload := map[string]any{
+		"jid":      evt.ID.ToNonAD().String(),
+		"platform": evt.Platform,
+	}
+	if !evt.LID.IsEmpty() {
+		payload["lid"] = evt.LID.ToNonAD().String()
+	}
+	if evt.BusinessName != "" {
+		payload["business_name"] = evt.BusinessName
+	}
+	body := connectionEventBody(instance, EventTypePairSuccess, payload)
+	// The instance learns its JID after this event is handled.
+	body["device_id"] = evt.ID.ToNonAD().String()
+	return body
+}
+
+func createConnectedPayload(instance *DeviceInstance, client *whatsmeow.Client) map[string]any {
+	payload := map[string]any{}
+	if client != nil && client.Store != nil {
+		if client.Store.ID != nil {
+			payload["jid"] = client.Store.ID.ToNonAD().String()
+		}
+		if client.Store.PushName != "" {
+			payload["push_name"] = client.Store.PushName
+		}
+	}
+	return connectionEventBody(instance, EventTypeConnected, payload)
+}
+
+func createLoggedOutPayload(instance *DeviceInstance, evt *events.LoggedOut) map[string]any {
+	payload := map[string]any{
+		"on_connect": evt.OnConnect,
+	}
+	if evt.OnConnect {
+		payload["reason"] = evt.Reason.String()
+	}
+	return connectionEventBody(instance, EventTypeLoggedOut, payload)
+}
@@ -0,0 +1,87 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestNotifyQRCodeSendsBase64PNG(t *testing.T) {
+	originalWebhookURLs := config.WhatsappWebhook
+	originalWebhookEvents := config.WhatsappWebhookEvents
+	originalSubmit := submitWebhookFn
+	defer func() {
+		config.WhatsappWebhook = originalWebhookURLs
+		config.WhatsappWebhookEvents = originalWebhookEvents
+		submitWebhookFn = originalSubmit
+	}()
+
+	config.WhatsappWebhook = []string{"https://example.test/webhook"}
+	config.WhatsappWebhookEvents = nil
+	done := make(chan map[string]any, 1)
+	submitWebhookFn = func(_ context.Context, payload map[string]any, _ string) error {
+		done <- payload
+		return nil
+	}
+
+	NotifyQRCode(NewDeviceInstance("provisioning-1", nil, nil), "2@abc,def,ghi", 60*time.Second)
+
+	select {
+	case body := <-done:
+		if body["event"] != EventTypeQRCodeGenerated || body["session_id"] != "provisioning-1" {
+			t.Fatalf("body = %v", body)
+		}
+		if _, ok := body["device_id"]; ok {
+			t.Error("an unpaired device should have no device_id")
+		}
+		payload := body["payload"].(map[string]any)
+		if payload["code"] != "2@abc,def,ghi" || payload["timeout_seconds"] != 60 {
+			t.Errorf("payload = %v", payload)
+		}
+		image, err := base64.StdEncoding.DecodeString(payload["qr_image"].(string))
+		if err != nil || !bytes.HasPrefix(image, []byte("\x89PNG")) {
+			t.Errorf("qr_image is not a base64 PNG: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for webhook submission")
+	}
+}
+
+func TestCreatePairSuccessPayload(t *testing.T) {
+	body := createPairSuccessPayload(NewDeviceInstance("provisioning-1", nil, nil), &events.PairSuccess{
+		ID:       types.NewADJID("628123456789", 0, 12),
+		LID:      types.NewJID("123456789012345", types.HiddenUserServer),
+		Platform: "android",
+	})
+
+	if body["device_id"] != "628123456789@s.whatsapp.net" || body["session_id"] != "provisioning-1" {
+		t.Errorf("body = %v", body)
+	}
+	payload := body["payload"].(map[string]any)
+	if payload["jid"] != "628123456789@s.whatsapp.net" || payload["lid"] != "123456789012345@lid" || payload["platform"] != "android" {
+		t.Errorf("payload = %v", payload)
+	}
+	if _, ok := payload["business_name"]; ok {
+		t.Error("business_name should be omitted for personal accounts")
+	}
+}
+
+func TestCreateLoggedOutPayload(t *testing.T) {
+	instance := NewDeviceInstance("provisioning-1", nil, nil)
+
+	payload := createLoggedOutPayload(instance, &events.LoggedOut{OnConnect: true, Reason: events.ConnectFailureLoggedOut})["payload"].(map[string]any)
+	if payload["on_connect"] != true || payload["reason"] != events.ConnectFailureLoggedOut.String() {
+		t.Errorf("payload = %v", payload)
+	}
+
+	payload = createLoggedOutPayload(instance, &events.LoggedOut{})["payload"].(map[string]any)
+	if _, ok := payload["reason"]; ok {
+		t.Errorf("a stream error logout has no reason, got %v", payload)
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"time"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
@@ -28,13 +28,16 @@ func createDeletePayload(ctx context.Context, evt *events.DeleteForMe, message *
 	payload["deleted_message_id"] = evt.MessageID
 	payload["timestamp"] = time.Now().Format(time.RFC3339)
 
-	// Resolve sender JID (convert LID to phone number if needed)
-	normalizedSenderJID := utils.ResolveLIDToPhone(ctx, evt.SenderJID, client)
-	payload["from"] = normalizedSenderJID.ToNonAD().String()
+	// Sender and chat use the configured webhook identifier, like message events
+	setWebhookJID(ctx, client, payload, webhookFromFields, evt.SenderJID, false)
 
 	// Include original message information if available
 	if message != nil {
-		payload["chat_id"] = message.ChatJID
+		if chatJID, err := types.ParseJID(message.ChatJID); err == nil {
+			setWebhookJID(ctx, client, payload, webhookChatFields, chatJID, false)
+		} else {
+			payload["chat_id"] = message.ChatJID
+		}
 		payload["original_content"] = message.Content
 		payload["original_sender"] = message.Sender
 		payload["original_timestamp"] = message.Timestamp.Format(time.RFC3339)
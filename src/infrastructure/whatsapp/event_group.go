@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
@@ -14,7 +14,9 @@ import (
 )
 
 // createGroupInfoPayload creates a webhook payload for group information events
-func createGroupInfoPayload(ctx context.Context, evt *events.GroupInfo, actionType string, jids []types.JID, deviceID string, client *whatsmeow.Client) map[string]any {
+// When the group had a stored snapshot, diff adds the affected participants'
+// roles before and after the event and the participant counts.
+func createGroupInfoPayload(ctx context.Context, evt *events.GroupInfo, actionType string, jids []types.JID, diff *groupMembershipDiff, deviceID string, client *whatsmeow.Client) map[string]any {
 	body := make(map[string]any)
 
 	// Create payload structure matching the expected format
@@ -23,9 +25,16 @@ func createGroupInfoPayload(ctx context.Context, evt *events.GroupInfo, actionTy
 	// Add group chat ID (groups use @g.us, not @lid, so no LID resolution needed)
 	payload["chat_id"] = evt.JID.ToNonAD().String()
 
-	// Add action type and affected users (with LID resolution)
+	// Add action type and affected users in the configured webhook identifier
 	payload["type"] = actionType
-	payload["jids"] = jidsToStrings(ctx, jids, client)
+	setWebhookJIDs(ctx, client, payload, "jids", jids)
+
+	if diff != nil {
+		// Snapshots key participants by phone number
+		payload["changes"] = diff.forJIDs(jidsToStrings(ctx, jids, client))
+		payload["participant_count_before"] = diff.CountBefore
+		payload["participant_count_after"] = diff.CountAfter
+	}
 
 	// Wrap in payload structure
 	body["payload"] = payload
@@ -56,7 +65,7 @@ func jidsToStrings(ctx context.Context, jids []types.JID, client *whatsmeow.Clie
 }
 
 // forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
-func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, deviceID string, client *whatsmeow.Client) error {
+func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, diff *groupMembershipDiff, deviceID string, client *whatsmeow.Client) error {
 	// Send separate webhook events for each action type
 	actions := []struct {
 		actionType string
@@ -70,7 +79,7 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, devic
 
 	for _, action := range actions {
 		if len(action.jids) > 0 {
-			payload := createGroupInfoPayload(ctx, evt, action.actionType, action.jids, deviceID, client)
+			payload := createGroupInfoPayload(ctx, evt, action.actionType, action.jids, diff, deviceID, client)
 
 			if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "group.participants"); err != nil {
 				logrus.Warnf("Failed to forward group %s event to webhook: %v", action.actionType, err)
@@ -78,21 +87,113 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, devic
 		}
 	}
 
+	for _, request := range groupJoinRequests(evt) {
+		payload := createGroupJoinRequestPayload(ctx, evt, request, deviceID, client)
+		if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "group.join_request"); err != nil {
+			logrus.Warnf("Failed to forward group join request to webhook: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// groupJoinRequest is a change to a group's pending join requests, sent when
+// the group requires admin approval to join. whatsmeow leaves these among the
+// UnknownChanges of a GroupInfo event.
+type groupJoinRequest struct {
+	// Action is "created", "revoked" when the requester withdrew it, or
+	// "rejected" when an admin declined it.
+	Action        string
+	JIDs          []types.JID
+	RequestMethod string
+}
+
+// groupJoinRequests extracts the join request changes of a GroupInfo event.
+func groupJoinRequests(evt *events.GroupInfo) []groupJoinRequest {
+	var requests []groupJoinRequest
+	for _, node := range evt.UnknownChanges {
+		if node == nil {
+			continue
+		}
+		var request groupJoinRequest
+		switch node.Tag {
+		case "created_membership_requests":
+			request.Action = "created"
+		case "revoked_membership_requests":
+			request.Action = "revoked"
+		default:
+			continue
+		}
+		request.RequestMethod = node.AttrGetter().OptionalString("request_method")
+		for _, child := range node.GetChildrenByTag("participant") {
+			if jid := child.AttrGetter().OptionalJIDOrEmpty("jid"); !jid.IsEmpty() {
+				request.JIDs = append(request.JIDs, jid)
+			}
+		}
+		if len(request.JIDs) == 0 {
+			continue
+		}
+		if request.Action == "revoked" && !isGroupChangeBy(evt, request.JIDs[0]) {
+			request.Action = "rejected"
+		}
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+// isGroupChangeBy reports whether jid made the change in evt.
+func isGroupChangeBy(evt *events.GroupInfo, jid types.JID) bool {
+	for _, sender := range []*types.JID{evt.Sender, evt.SenderPN} {
+		if sender != nil && sender.User == jid.User && sender.Server == jid.Server {
+			return true
+		}
+	}
+	return false
+}
+
+// createGroupJoinRequestPayload creates the group.join_request webhook payload.
+func createGroupJoinRequestPayload(ctx context.Context, evt *events.GroupInfo, request groupJoinRequest, deviceID string, client *whatsmeow.Client) map[string]any {
+	payload := map[string]any{
+		"chat_id": evt.JID.ToNonAD().String(),
+		"type":    request.Action,
+	}
+	setWebhookJIDs(ctx, client, payload, "jids", request.JIDs)
+	if request.RequestMethod != "" {
+		payload["request_method"] = request.RequestMethod
+	}
+	if request.Action == "rejected" && evt.Sender != nil {
+		setWebhookJID(ctx, client, payload, webhookJIDFields{"rejected_by", "rejected_by_lid", "rejected_by_pn"}, *evt.Sender, false)
+	}
+
+	body := map[string]any{
+		"event":     "group.join_request",
+		"payload":   payload,
+		"timestamp": evt.Timestamp.Format(time.RFC3339),
+	}
+	if deviceID != "" {
+		body["device_id"] = deviceID
+	}
+	return body
+}
+
 // handleJoinedGroup handles the event when the connected device is added to a new group
-func handleJoinedGroup(ctx context.Context, evt *events.JoinedGroup, deviceID string, client *whatsmeow.Client) {
+func handleJoinedGroup(ctx context.Context, evt *events.JoinedGroup, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	log.Infof("Joined group %s (reason: %s, type: %s)", evt.JID, evt.Reason, evt.Type)
 
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.JoinedGroup, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if chatStorageRepo != nil {
+		if err := chatStorageRepo.StoreGroupSnapshot(groupSnapshotFromInfo(ctx, &evt.GroupInfo, client)); err != nil {
+			log.Warnf("Failed to store snapshot of joined group %s: %v", evt.JID, err)
+		}
+	}
+
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.JID.ToNonAD().String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardJoinedGroupToWebhook(webhookCtx, e, deviceID, c); err != nil {
+			if err := forwardJoinedGroupToWebhook(webhookCtx, evt, deviceID, client); err != nil {
 				logrus.Errorf("Failed to forward joined group event to webhook: %v", err)
 			}
-		}(evt, client)
+		})
 	}
 }
 
@@ -107,9 +208,9 @@ func forwardJoinedGroupToWebhook(ctx context.Context, evt *events.JoinedGroup, d
 	payload := map[string]any{
 		"chat_id": evt.JID.ToNonAD().String(),
 		"type":    "join",
-		"jids":    []string{ownJID.ToNonAD().String()},
 		"reason":  evt.Reason, // "invite" if via invite link
 	}
+	setWebhookJIDs(ctx, client, payload, "jids", []types.JID{*ownJID})
 
 	// Include group name if available (GroupName.Name is embedded in GroupInfo)
 	if evt.GroupName.Name != "" {
@@ -0,0 +1,79 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func membershipRequestNode(tag string, attrs waBinary.Attrs, jids ...types.JID) *waBinary.Node {
+	children := make([]waBinary.Node, 0, len(jids))
+	for _, jid := range jids {
+		children = append(children, waBinary.Node{Tag: "participant", Attrs: waBinary.Attrs{"jid": jid}})
+	}
+	return &waBinary.Node{Tag: tag, Attrs: attrs, Content: children}
+}
+
+func TestGroupJoinRequests(t *testing.T) {
+	requester := types.NewJID("628123456789", types.DefaultUserServer)
+	admin := types.NewJID("628987654321", types.DefaultUserServer)
+	group := types.NewJID("120363000000000001", types.GroupServer)
+
+	tests := []struct {
+		name   string
+		sender types.JID
+		node   *waBinary.Node
+		want   string
+	}{
+		{"created", requester, membershipRequestNode("created_membership_requests", waBinary.Attrs{"request_method": "invite_link"}, requester), "created"},
+		{"withdrawn by the requester", requester, membershipRequestNode("revoked_membership_requests", nil, requester), "revoked"},
+		{"declined by an admin", admin, membershipRequestNode("revoked_membership_requests", nil, requester), "rejected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := tt.sender
+			evt := &events.GroupInfo{JID: group, Sender: &sender, UnknownChanges: []*waBinary.Node{
+				tt.node,
+				{Tag: "some_future_change"},
+			}}
+			requests := groupJoinRequests(evt)
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(requests))
+			}
+			if requests[0].Action != tt.want || len(requests[0].JIDs) != 1 || requests[0].JIDs[0] != requester {
+				t.Errorf("request = %+v", requests[0])
+			}
+		})
+	}
+}
+
+func TestCreateGroupJoinRequestPayload(t *testing.T) {
+	requester := types.NewJID("628123456789", types.DefaultUserServer)
+	admin := types.NewJID("628987654321", types.DefaultUserServer)
+	evt := &events.GroupInfo{
+		JID:       types.NewJID("120363000000000001", types.GroupServer),
+		Sender:    &admin,
+		Timestamp: time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC),
+	}
+
+	body := createGroupJoinRequestPayload(context.Background(), evt, groupJoinRequest{Action: "rejected", JIDs: []types.JID{requester}}, "628111@s.whatsapp.net", nil)
+
+	if body["event"] != "group.join_request" || body["device_id"] != "628111@s.whatsapp.net" || body["timestamp"] != "2026-01-18T12:00:00Z" {
+		t.Errorf("body = %v", body)
+	}
+	payload := body["payload"].(map[string]any)
+	jids, _ := payload["jids"].([]string)
+	if payload["chat_id"] != "120363000000000001@g.us" || payload["type"] != "rejected" || len(jids) != 1 || jids[0] != requester.String() {
+		t.Errorf("payload = %v", payload)
+	}
+	if payload["rejected_by"] != admin.String() {
+		t.Errorf("rejected_by = %v", payload["rejected_by"])
+	}
+	if _, ok := payload["request_method"]; ok {
+		t.Error("request_method should be omitted when unknown")
+	}
+}
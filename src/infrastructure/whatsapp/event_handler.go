@@ -30,6 +30,7 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 
 	chatStorageRepo := instance.GetChatStorage()
 	client := instance.GetClient()
+	previousState := instance.State()
 
 	switch evt := rawEvt.(type) {
 	case *events.DeleteForMe:
@@ -37,31 +38,50 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 	case *events.AppStateSyncComplete:
 		handleAppStateSyncComplete(ctx, client, evt)
 	case *events.PairSuccess:
-		handlePairSuccess(ctx, evt)
+		handlePairSuccess(ctx, evt, instance)
 	case *events.LoggedOut:
-		handleLoggedOut(ctx, instance, chatStorageRepo)
+		handleLoggedOut(ctx, evt, instance, chatStorageRepo)
 	case *events.Connected, *events.PushNameSetting:
 		handleConnectionEvents(ctx, client, instance)
+		if _, connected := evt.(*events.Connected); connected {
+			forwardConnectionEvent(createConnectedPayload(instance, client), EventTypeConnected)
+			go refreshGroupSnapshots(context.Background(), instance)
+			wakeSendQueue()
+		}
+	case *events.Disconnected:
+		forwardConnectionEvent(connectionEventBody(instance, EventTypeDisconnected, map[string]any{}), EventTypeDisconnected)
 	case *events.StreamReplaced:
-		handleStreamReplaced(ctx)
+		handleStreamReplaced(ctx, instance)
 	case *events.Message:
 		handleMessage(ctx, evt, chatStorageRepo, client)
 	case *events.Receipt:
-		handleReceipt(ctx, evt, instance.JID(), client)
+		handleReceipt(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.Archive:
 		handleArchive(ctx, evt, chatStorageRepo, client)
+	case *events.Pin:
+		handlePin(ctx, evt, chatStorageRepo, client)
+	case *events.Mute:
+		handleMute(ctx, evt, chatStorageRepo, client)
+	case *events.MarkChatAsRead:
+		handleMarkChatAsRead(ctx, evt, chatStorageRepo, client)
 	case *events.Presence:
-		handlePresence(ctx, evt)
+		handlePresence(ctx, evt, instance.JID(), client)
 	case *events.ChatPresence:
 		handleChatPresence(ctx, evt, instance.JID(), client)
 	case *events.HistorySync:
 		handleHistorySync(ctx, evt, chatStorageRepo, client)
 	case *events.AppState:
 		handleAppState(ctx, evt, instance.JID(), client)
+	case *events.LabelEdit:
+		handleLabelEdit(ctx, evt, chatStorageRepo, client)
+	case *events.LabelAssociationChat:
+		handleLabelAssociationChat(ctx, evt, chatStorageRepo, client)
+	case *events.LabelAssociationMessage:
+		handleLabelAssociationMessage(ctx, evt, chatStorageRepo, client)
 	case *events.GroupInfo:
-		handleGroupInfo(ctx, evt, instance.JID(), client)
+		handleGroupInfo(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.JoinedGroup:
-		handleJoinedGroup(ctx, evt, instance.JID(), client)
+		handleJoinedGroup(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.NewsletterJoin:
 		handleNewsletterJoin(ctx, evt, instance.JID(), client)
 	case *events.NewsletterLeave:
@@ -72,16 +92,22 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 		handleNewsletterMuteChange(ctx, evt, instance.JID(), client)
 	case *events.CallOffer:
 		handleCallOffer(ctx, evt, chatStorageRepo, instance.JID(), client)
+	case *events.CallAccept:
+		handleCallAccept(ctx, evt)
+	case *events.CallTerminate:
+		handleCallTerminate(ctx, evt, instance.JID(), client)
 	}
 
-	instance.UpdateStateFromClient()
+	if state := instance.UpdateStateFromClient(); state != previousState {
+		publishDeviceState(instance, state)
+	}
 }
 
 func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	log.Infof("Deleted message %s for %s", evt.MessageID, evt.SenderJID.String())
 
 	// Find the message to get its chat JID
-	message, err := chatStorageRepo.GetMessageByID(evt.MessageID)
+	message, err := chatStorageRepo.GetMessageByID(evt.MessageID, "", "")
 	if err != nil {
 		log.Errorf("Failed to find message %s for deletion: %v", evt.MessageID, err)
 		return
@@ -100,19 +126,19 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 
 	// Send webhook notification for delete event
-	if len(config.WhatsappWebhook) > 0 {
-		go func(c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, message.ChatJID, func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardDeleteToWebhook(webhookCtx, evt, message, deviceID, c); err != nil {
+			if err := forwardDeleteToWebhook(webhookCtx, evt, message, deviceID, client); err != nil {
 				log.Errorf("Failed to forward delete event to webhook: %v", err)
 			}
-		}(client)
+		})
 	}
 }
 
 func resolvePresenceOnConnect() (types.Presence, bool) {
-	switch config.WhatsappPresenceOnConnect {
+	switch config.Setting(&config.WhatsappPresenceOnConnect) {
 	case "available":
 		return types.PresenceAvailable, false
 	case "none":
@@ -144,7 +170,8 @@ func handleAppStateSyncComplete(_ context.Context, client *whatsmeow.Client, evt
 	}
 }
 
-func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
+func handlePairSuccess(ctx context.Context, evt *events.PairSuccess, instance *DeviceInstance) {
+	forwardConnectionEvent(createPairSuccessPayload(instance, evt), EventTypePairSuccess)
 	websocket.Broadcast <- websocket.BroadcastMessage{
 		Code:    "LOGIN_SUCCESS",
 		Message: fmt.Sprintf("Successfully pair with %s", evt.ID.String()),
@@ -153,8 +180,10 @@ func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
 	syncKeysDevice(ctx, primaryDB, secondaryDB, evt.ID)
 }
 
-func handleLoggedOut(ctx context.Context, instance *DeviceInstance, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func handleLoggedOut(ctx context.Context, evt *events.LoggedOut, instance *DeviceInstance, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	logrus.Warnf("[REMOTE_LOGOUT] Received LoggedOut event for device %s - user logged out from phone", instance.ID())
+	// Built before cleanup, which clears the device JID.
+	forwardConnectionEvent(createLoggedOutPayload(instance, evt), EventTypeLoggedOut)
 
 	if client := instance.GetClient(); client != nil {
 		client.Disconnect()
@@ -222,35 +251,47 @@ func handleConnectionEvents(_ context.Context, client *whatsmeow.Client, instanc
 	sendConfiguredPresence(context.Background(), client)
 }
 
-func handleStreamReplaced(_ context.Context) {
+func handleStreamReplaced(_ context.Context, instance *DeviceInstance) {
+	// Delivered before exiting, since the process does not outlive this event.
+	if hasWebhookTargets() {
+		deliverConnectionEvent(connectionEventBody(instance, EventTypeStreamReplaced, map[string]any{}), EventTypeStreamReplaced, 10*time.Second)
+	}
 	os.Exit(0)
 }
 
-func handleReceipt(ctx context.Context, evt *events.Receipt, deviceID string, client *whatsmeow.Client) {
+func handleReceipt(ctx context.Context, evt *events.Receipt, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	sendReceipt := false
 	switch evt.Type {
 	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
 		sendReceipt = true
 		log.Infof("%v was read by %s at %s: %+v", evt.MessageIDs, evt.SourceString(), evt.Timestamp, evt)
+		if evt.Type == types.ReceiptTypeReadSelf {
+			// The chat was read on another of our devices.
+			unread := 0
+			updateChatStateFromAppState(ctx, evt.Chat, chatStorageRepo, client, "read_self", domainChatStorage.ChatStateUpdate{UnreadCount: &unread})
+		}
 	case types.ReceiptTypeDelivered:
 		sendReceipt = true
 		log.Infof("%s was delivered to %s at %s: %+v", evt.MessageIDs[0], evt.SourceString(), evt.Timestamp, evt)
+	case types.ReceiptTypePlayed, types.ReceiptTypePlayedSelf:
+		sendReceipt = true
+		log.Infof("%v was played by %s at %s: %+v", evt.MessageIDs, evt.SourceString(), evt.Timestamp, evt)
 	}
 
 	// Forward receipt (ack) event to webhook or Chatwoot if configured
 	// Note: Receipt events are not rate limited as they are critical for message delivery status
-	if (len(config.WhatsappWebhook) > 0 || (config.ChatwootEnabled && config.ChatwootMessageRead)) && sendReceipt {
-		go func(e *events.Receipt, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if (hasWebhookTargets() || (config.ChatwootEnabled && config.ChatwootMessageRead)) && sendReceipt {
+		dispatchChatWebhook(deviceID, evt.Chat.ToNonAD().String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardReceiptToWebhook(webhookCtx, e, deviceID, c); err != nil {
+			if err := forwardReceiptToWebhook(webhookCtx, evt, deviceID, client); err != nil {
 				logrus.Errorf("Failed to forward ack event to webhook: %v", err)
 			}
-		}(evt, client)
+		})
 	}
 }
 
-func handlePresence(_ context.Context, evt *events.Presence) {
+func handlePresence(ctx context.Context, evt *events.Presence, deviceID string, client *whatsmeow.Client) {
 	if evt.Unavailable {
 		if evt.LastSeen.IsZero() {
 			log.Infof("%s is now offline", evt.From)
@@ -260,14 +301,18 @@ func handlePresence(_ context.Context, evt *events.Presence) {
 	} else {
 		log.Infof("%s is now online", evt.From)
 	}
+
+	if presenceUpdatesEnabled() {
+		forwardPresenceUpdate(ctx, deviceID, createPresenceUpdatePayload(ctx, evt, deviceID, client))
+	}
 }
 
-func handleAppState(_ context.Context, evt *events.AppState, deviceID string, client *whatsmeow.Client) {
+func handleAppState(ctx context.Context, evt *events.AppState, deviceID string, client *whatsmeow.Client) {
 	log.Debugf("App state event: %+v / %+v", evt.Index, evt.SyncActionValue)
 
-	if len(config.WhatsappWebhook) > 0 && isLabelAppState(evt) {
+	if hasWebhookTargets() && isLabelAppState(evt) {
 		go func(e *events.AppState, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
 			if err := forwardLabelAppStateToWebhook(webhookCtx, e, deviceID, c); err != nil {
 				logrus.Errorf("Failed to forward label appstate event to webhook: %v", err)
@@ -276,15 +321,20 @@ func handleAppState(_ context.Context, evt *events.AppState, deviceID string, cl
 	}
 }
 
-func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, deviceID string, client *whatsmeow.Client) {
+func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	// Only process events that have actual changes
 	hasChanges := len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
-		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil
+		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil || evt.Ephemeral != nil
+	joinRequests := groupJoinRequests(evt)
 
-	if !hasChanges {
+	if !hasChanges && len(joinRequests) == 0 {
 		return
 	}
 
+	// Update the stored snapshot before forwarding, so the webhook carries the
+	// membership diff and later events build on this one.
+	diff := updateGroupSnapshot(ctx, evt, chatStorageRepo, client)
+
 	// Log group events for debugging
 	if len(evt.Join) > 0 {
 		log.Infof("Group %s: %d users joined at %s", evt.JID, len(evt.Join), evt.Timestamp)
@@ -298,15 +348,18 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, deviceID string
 	if len(evt.Demote) > 0 {
 		log.Infof("Group %s: %d users demoted at %s", evt.JID, len(evt.Demote), evt.Timestamp)
 	}
+	for _, request := range joinRequests {
+		log.Infof("Group %s: join request %s for %d users at %s", evt.JID, request.Action, len(request.JIDs), evt.Timestamp)
+	}
 
 	// Forward group info event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.GroupInfo, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.JID.ToNonAD().String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardGroupInfoToWebhook(webhookCtx, e, deviceID, c); err != nil {
+			if err := forwardGroupInfoToWebhook(webhookCtx, evt, diff, deviceID, client); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
 			}
-		}(evt, client)
+		})
 	}
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/proto/waSyncAction"
@@ -132,3 +135,73 @@ func labelAppStateTimestamp(evt *events.AppState) string {
 
 	return time.Now().UTC().Format(time.RFC3339)
 }
+
+// labelDeviceID returns the device ID label rows are stored under, matching
+// the chat state handlers.
+func labelDeviceID(client *whatsmeow.Client) string {
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return ""
+	}
+	return client.Store.ID.ToNonAD().String()
+}
+
+// handleLabelEdit stores a created or edited label, or removes a deleted one.
+func handleLabelEdit(_ context.Context, evt *events.LabelEdit, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	deviceID := labelDeviceID(client)
+	if evt == nil || evt.Action == nil || chatStorageRepo == nil || deviceID == "" {
+		return
+	}
+	logFields := logrus.Fields{"device_id": deviceID, "label_id": evt.LabelID}
+
+	if evt.Action.GetDeleted() {
+		if err := chatStorageRepo.DeleteLabel(deviceID, evt.LabelID); err != nil {
+			logrus.WithError(err).WithFields(logFields).Error("Failed to delete label")
+		}
+		return
+	}
+
+	label := &domainChatStorage.Label{
+		DeviceID:     deviceID,
+		ID:           evt.LabelID,
+		Name:         evt.Action.GetName(),
+		Color:        evt.Action.GetColor(),
+		PredefinedID: evt.Action.GetPredefinedID(),
+		OrderIndex:   evt.Action.GetOrderIndex(),
+	}
+	if err := chatStorageRepo.StoreLabel(label); err != nil {
+		logrus.WithError(err).WithFields(logFields).Error("Failed to store label")
+	}
+}
+
+// handleLabelAssociationChat mirrors a label being applied to or removed from a chat.
+func handleLabelAssociationChat(ctx context.Context, evt *events.LabelAssociationChat, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	deviceID := labelDeviceID(client)
+	if evt == nil || evt.Action == nil || chatStorageRepo == nil || deviceID == "" {
+		return
+	}
+	chatJID := utils.ResolveLIDToPhone(ctx, evt.JID, client).ToNonAD().String()
+	if err := chatStorageRepo.SetChatLabel(deviceID, chatJID, evt.LabelID, evt.Action.GetLabeled()); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"device_id": deviceID,
+			"label_id":  evt.LabelID,
+			"chat_jid":  chatJID,
+		}).Error("Failed to update chat label")
+	}
+}
+
+// handleLabelAssociationMessage mirrors a label being applied to or removed from a message.
+func handleLabelAssociationMessage(ctx context.Context, evt *events.LabelAssociationMessage, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	deviceID := labelDeviceID(client)
+	if evt == nil || evt.Action == nil || chatStorageRepo == nil || deviceID == "" {
+		return
+	}
+	chatJID := utils.ResolveLIDToPhone(ctx, evt.JID, client).ToNonAD().String()
+	if err := chatStorageRepo.SetMessageLabel(deviceID, chatJID, evt.MessageID, evt.LabelID, evt.Action.GetLabeled()); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"device_id":  deviceID,
+			"label_id":   evt.LabelID,
+			"chat_jid":   chatJID,
+			"message_id": evt.MessageID,
+		}).Error("Failed to update message label")
+	}
+}
@@ -0,0 +1,166 @@
+package whatsapp
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Event pipeline stages timed by eventTimer.
+const (
+	eventStageStorage      = "storage"
+	eventStageMedia        = "media"
+	eventStageAutoReply    = "auto_reply"
+	eventStageWebhookQueue = "webhook_queue" // Waiting behind earlier deliveries of the same chat
+	eventStageWebhook      = "webhook"
+)
+
+// eventTimer measures how long one incoming event spends in each stage of the
+// pipeline, from the moment it is handled until its webhook is delivered. A nil
+// *eventTimer is valid and records nothing.
+type eventTimer struct {
+	event   string
+	id      string
+	chatJID string
+	start   time.Time
+
+	mu     sync.Mutex
+	stages map[string]time.Duration
+	done   bool
+}
+
+type eventTimerKey struct{}
+
+func newEventTimer(event, id, chatJID string) *eventTimer {
+	return &eventTimer{event: event, id: id, chatJID: chatJID, start: time.Now(), stages: make(map[string]time.Duration)}
+}
+
+func contextWithEventTimer(ctx context.Context, timer *eventTimer) context.Context {
+	if timer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, eventTimerKey{}, timer)
+}
+
+func eventTimerFromContext(ctx context.Context) *eventTimer {
+	if ctx == nil {
+		return nil
+	}
+	timer, _ := ctx.Value(eventTimerKey{}).(*eventTimer)
+	return timer
+}
+
+// track starts timing stage and returns the func that stops it, for use with
+// defer. Time spent in a stage more than once is added up.
+func (t *eventTimer) track(stage string) func() {
+	if t == nil {
+		return func() {}
+	}
+	started := time.Now()
+	return func() {
+		t.mu.Lock()
+		t.stages[stage] += time.Since(started)
+		t.mu.Unlock()
+	}
+}
+
+// finish records the event in the latency stats and, when the whole pipeline
+// took longer than WhatsappEventLatencyBudget, logs a slow-event record with
+// the time spent per stage. Only the first call counts.
+func (t *eventTimer) finish() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if t.done {
+		t.mu.Unlock()
+		return
+	}
+	t.done = true
+	total := time.Since(t.start)
+	stages := make(map[string]time.Duration, len(t.stages))
+	for stage, elapsed := range t.stages {
+		stages[stage] = elapsed
+	}
+	t.mu.Unlock()
+
+	budget := config.WhatsappEventLatencyBudget
+	slow := budget > 0 && total > budget
+	eventLatency.record(t.event, total, stages, slow)
+	if !slow {
+		return
+	}
+
+	fields := logrus.Fields{
+		"event":     t.event,
+		"event_id":  t.id,
+		"chat_jid":  t.chatJID,
+		"total_ms":  total.Milliseconds(),
+		"budget_ms": budget.Milliseconds(),
+	}
+	for stage, elapsed := range stages {
+		fields[stage+"_ms"] = elapsed.Milliseconds()
+	}
+	logrus.WithFields(fields).Warn("Slow event: pipeline exceeded latency budget")
+}
+
+// EventLatencyStats summarizes the timed events of one type since startup.
+type EventLatencyStats struct {
+	Event   string `json:"event"`
+	Count   int64  `json:"count"`
+	Slow    int64  `json:"slow"`
+	TotalMs int64  `json:"total_ms"`
+	MaxMs   int64  `json:"max_ms"`
+	// StageTotalMs is the cumulative time spent per stage.
+	StageTotalMs map[string]int64 `json:"stage_total_ms"`
+}
+
+type eventLatencyRecorder struct {
+	mu    sync.Mutex
+	stats map[string]*EventLatencyStats
+}
+
+var eventLatency = &eventLatencyRecorder{stats: make(map[string]*EventLatencyStats)}
+
+func (r *eventLatencyRecorder) record(event string, total time.Duration, stages map[string]time.Duration, slow bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[event]
+	if !ok {
+		stats = &EventLatencyStats{Event: event, StageTotalMs: make(map[string]int64)}
+		r.stats[event] = stats
+	}
+	stats.Count++
+	if slow {
+		stats.Slow++
+	}
+	stats.TotalMs += total.Milliseconds()
+	stats.MaxMs = max(stats.MaxMs, total.Milliseconds())
+	for stage, elapsed := range stages {
+		stats.StageTotalMs[stage] += elapsed.Milliseconds()
+	}
+}
+
+// GetEventLatencyStats returns the latency counters of every timed event type,
+// sorted by event name.
+func GetEventLatencyStats() []EventLatencyStats {
+	eventLatency.mu.Lock()
+	defer eventLatency.mu.Unlock()
+
+	result := make([]EventLatencyStats, 0, len(eventLatency.stats))
+	for _, stats := range eventLatency.stats {
+		snapshot := *stats
+		snapshot.StageTotalMs = make(map[string]int64, len(stats.StageTotalMs))
+		for stage, ms := range stats.StageTotalMs {
+			snapshot.StageTotalMs[stage] = ms
+		}
+		result = append(result, snapshot)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Event < result[j].Event })
+	return result
+}
@@ -0,0 +1,59 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func latencyStatsFor(event string) EventLatencyStats {
+	for _, stats := range GetEventLatencyStats() {
+		if stats.Event == event {
+			return stats
+		}
+	}
+	return EventLatencyStats{}
+}
+
+func TestEventTimer_RecordsSlowEventOnce(t *testing.T) {
+	prev := config.WhatsappEventLatencyBudget
+	config.WhatsappEventLatencyBudget = time.Millisecond
+	t.Cleanup(func() { config.WhatsappEventLatencyBudget = prev })
+
+	timer := newEventTimer("test_slow", "MSG1", "628111@s.whatsapp.net")
+	ctx := contextWithEventTimer(context.Background(), timer)
+	stop := eventTimerFromContext(ctx).track(eventStageStorage)
+	time.Sleep(5 * time.Millisecond)
+	stop()
+
+	timer.finish()
+	timer.finish()
+
+	stats := latencyStatsFor("test_slow")
+	if stats.Count != 1 || stats.Slow != 1 {
+		t.Fatalf("expected one slow event, got count=%d slow=%d", stats.Count, stats.Slow)
+	}
+	if stats.StageTotalMs[eventStageStorage] < 5 {
+		t.Fatalf("expected storage stage >= 5ms, got %d", stats.StageTotalMs[eventStageStorage])
+	}
+}
+
+func TestEventTimer_WithinBudgetIsNotSlow(t *testing.T) {
+	prev := config.WhatsappEventLatencyBudget
+	config.WhatsappEventLatencyBudget = time.Minute
+	t.Cleanup(func() { config.WhatsappEventLatencyBudget = prev })
+
+	newEventTimer("test_fast", "MSG2", "").finish()
+
+	if stats := latencyStatsFor("test_fast"); stats.Count != 1 || stats.Slow != 0 {
+		t.Fatalf("expected one fast event, got count=%d slow=%d", stats.Count, stats.Slow)
+	}
+}
+
+func TestEventTimer_NilIsNoop(t *testing.T) {
+	timer := eventTimerFromContext(context.Background())
+	timer.track(eventStageWebhook)()
+	timer.finish()
+}
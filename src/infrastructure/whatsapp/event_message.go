@@ -26,6 +26,9 @@ const (
 	EventTypeMessageReaction = "message.reaction"
 	EventTypeMessageRevoked  = "message.revoked"
 	EventTypeMessageEdited   = "message.edited"
+	EventTypeMessagePollVote = "message.poll_vote"
+	EventTypeMessageOutgoing = "message.outgoing"
+	EventTypeMessagePin      = "message.pin"
 )
 
 // WebhookEvent is the top-level structure for webhook payloads
@@ -41,6 +44,54 @@ type webhookContactPayload struct {
 	PhoneNumber string `json:"phone_number,omitempty"`
 }
 
+// webhookLiveLocationPayload is the live_location of a message event. Phones
+// send one when sharing starts and another for every position update, each
+// with a higher sequence number.
+type webhookLiveLocationPayload struct {
+	DegreesLatitude  float64 `json:"degreesLatitude"`
+	DegreesLongitude float64 `json:"degreesLongitude"`
+	AccuracyInMeters uint32  `json:"accuracy_in_meters,omitempty"`
+	SpeedInMps       float32 `json:"speed_in_mps,omitempty"`
+	Heading          uint32  `json:"heading,omitempty"`
+	Caption          string  `json:"caption,omitempty"`
+	SequenceNumber   int64   `json:"sequence_number"`
+	TimeOffset       uint32  `json:"time_offset"`
+}
+
+// orderDetailsTimeout bounds the query for the line items of an incoming
+// order, so a slow server does not hold up its webhook.
+const orderDetailsTimeout = 10 * time.Second
+
+// webhookOrderPayload is the order of an order message. Amounts are in
+// thousandths of the currency unit; Items is empty when the line items could
+// not be fetched.
+type webhookOrderPayload struct {
+	OrderID         string                    `json:"order_id"`
+	Title           string                    `json:"title,omitempty"`
+	Message         string                    `json:"message,omitempty"`
+	Status          string                    `json:"status,omitempty"`
+	Surface         string                    `json:"surface,omitempty"`
+	SellerJID       string                    `json:"seller_jid,omitempty"`
+	ItemCount       int32                     `json:"item_count"`
+	Currency        string                    `json:"currency,omitempty"`
+	TotalAmount1000 int64                     `json:"total_amount_1000"`
+	Items           []webhookOrderItemPayload `json:"items"`
+}
+
+func (o webhookOrderPayload) GetOrderTitle() string { return o.Title }
+
+type webhookOrderItemPayload struct {
+	ProductID       string `json:"product_id"`
+	Name            string `json:"name"`
+	ImageURL        string `json:"image_url,omitempty"`
+	Quantity        int    `json:"quantity"`
+	Currency        string `json:"currency,omitempty"`
+	PriceAmount1000 int64  `json:"price_amount_1000"`
+}
+
+func (l webhookLiveLocationPayload) GetDegreesLatitude() float64  { return l.DegreesLatitude }
+func (l webhookLiveLocationPayload) GetDegreesLongitude() float64 { return l.DegreesLongitude }
+
 // forwardMessageToWebhook is a helper function to forward message event to webhook url
 func forwardMessageToWebhook(ctx context.Context, client *whatsmeow.Client, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
 	webhookEvent, err := createWebhookEvent(ctx, client, evt, chatStorageRepo)
@@ -54,9 +105,37 @@ func forwardMessageToWebhook(ctx context.Context, client *whatsmeow.Client, evt
 		"payload":   webhookEvent.Payload,
 	}
 
+	defer eventTimerFromContext(ctx).track(eventStageWebhook)()
 	return forwardPayloadToConfiguredWebhooks(ctx, payload, webhookEvent.Event)
 }
 
+func isPinMessage(evt *events.Message) bool {
+	if evt == nil || evt.Message == nil {
+		return false
+	}
+
+	return utils.UnwrapMessage(evt.Message).GetPinInChatMessage() != nil
+}
+
+// buildPinFields describes a pin or unpin: the pinned message, whether it is
+// now pinned, and until when.
+func buildPinFields(evt *events.Message, pinMessage *waE2E.PinInChatMessage, payload map[string]any) {
+	if key := pinMessage.GetKey(); key != nil {
+		payload["pinned_message_id"] = key.GetID()
+	}
+	pinned := pinMessage.GetType() == waE2E.PinInChatMessage_PIN_FOR_ALL
+	payload["pinned"] = pinned
+	if !pinned {
+		return
+	}
+	duration := domainChatStorage.DefaultMessagePinDuration
+	if secs := evt.Message.GetMessageContextInfo().GetMessageAddOnDurationInSecs(); secs > 0 {
+		duration = time.Duration(secs) * time.Second
+	}
+	payload["duration_seconds"] = int(duration.Seconds())
+	payload["expires_at"] = evt.Info.Timestamp.Add(duration).Format(time.RFC3339)
+}
+
 func isReactionMessage(evt *events.Message) bool {
 	if evt == nil || evt.Message == nil {
 		return false
@@ -83,7 +162,7 @@ func createWebhookEvent(ctx context.Context, client *whatsmeow.Client, evt *even
 		return nil, err
 	}
 
-	webhookEvent.Event = eventType
+	webhookEvent.Event = outgoingWebhookEvent(evt, eventType, payload)
 	webhookEvent.Payload = payload
 
 	return webhookEvent, nil
@@ -102,6 +181,10 @@ func buildEventPayload(ctx context.Context, client *whatsmeow.Client, evt *event
 	// Build from/from_lid fields
 	buildFromFields(ctx, client, evt, payload)
 
+	if evt.Info.Chat.Server == types.NewsletterServer {
+		payload["newsletter"] = newsletterMessageFields(ctx, client, evt.Info)
+	}
+
 	// Set from_name (pushname)
 	if pushname := evt.Info.PushName; pushname != "" {
 		payload["from_name"] = pushname
@@ -177,7 +260,9 @@ func buildEventPayload(ctx context.Context, client *whatsmeow.Client, evt *event
 				payload["original_message_id"] = key.GetID()
 			}
 			if editedMessage := protocolMessage.GetEditedMessage(); editedMessage != nil {
-				if editedText := editedMessage.GetExtendedTextMessage(); editedText != nil {
+				if caption, mediaType, ok := utils.ExtractEditedCaption(editedMessage); ok {
+					buildCaptionEditFields(evt, protocolMessage.GetKey().GetID(), caption, mediaType, chatStorageRepo, payload)
+				} else if editedText := editedMessage.GetExtendedTextMessage(); editedText != nil {
 					payload["body"] = editedText.GetText()
 				} else if editedConv := editedMessage.GetConversation(); editedConv != "" {
 					payload["body"] = editedConv
@@ -187,6 +272,18 @@ func buildEventPayload(ctx context.Context, client *whatsmeow.Client, evt *event
 		}
 	}
 
+	// Check for poll vote
+	if pollUpdate := msg.GetPollUpdateMessage(); pollUpdate != nil {
+		buildPollVoteFields(ctx, client, evt, pollUpdate, chatStorageRepo, payload)
+		return EventTypeMessagePollVote, payload, nil
+	}
+
+	// Check for pin or unpin
+	if pinMessage := msg.GetPinInChatMessage(); pinMessage != nil {
+		buildPinFields(evt, pinMessage, payload)
+		return EventTypeMessagePin, payload, nil
+	}
+
 	// Check for reaction message
 	if reactionMessage := msg.GetReactionMessage(); reactionMessage != nil {
 		payload["reaction"] = reactionMessage.GetText()
@@ -209,21 +306,33 @@ func buildEventPayload(ctx context.Context, client *whatsmeow.Client, evt *event
 	return EventTypeMessage, payload, nil
 }
 
-func buildFromFields(ctx context.Context, client *whatsmeow.Client, evt *events.Message, payload map[string]any) {
-	chatJID := evt.Info.Chat.ToNonAD()
-	if chatJID.Server == "lid" {
-		payload["chat_lid"] = chatJID.String()
-		chatJID = utils.ResolveLIDToPhone(ctx, chatJID, client).ToNonAD()
-	}
-	payload["chat_id"] = chatJID.String()
+// buildCaptionEditFields fills a message.edited payload for a media caption edit.
+// The edit has already been stored when the webhook is built, so the previous
+// caption comes from the edit history row recorded for this edit event.
+func buildCaptionEditFields(evt *events.Message, originalMessageID, caption, mediaType string, chatStorageRepo domainChatStorage.IChatStorageRepository, payload map[string]any) {
+	payload["body"] = caption
+	payload["caption"] = caption
+	payload["media_type"] = mediaType
 
-	senderJID := evt.Info.Sender
-	if senderJID.Server == "lid" {
-		payload["from_lid"] = senderJID.ToNonAD().String()
+	if chatStorageRepo == nil || originalMessageID == "" {
+		return
+	}
+	edits, err := chatStorageRepo.GetMessageEdits(originalMessageID, "")
+	if err != nil {
+		logrus.Debugf("Failed to load edit history for %s: %v", originalMessageID, err)
+		return
+	}
+	for _, edit := range edits {
+		if edit.EditEventID == evt.Info.ID {
+			payload["previous_caption"] = edit.PreviousContent
+			return
+		}
 	}
+}
 
-	normalizedSenderJID := utils.ResolveLIDToPhone(ctx, senderJID, client)
-	payload["from"] = normalizedSenderJID.ToNonAD().String()
+func buildFromFields(ctx context.Context, client *whatsmeow.Client, evt *events.Message, payload map[string]any) {
+	setWebhookJID(ctx, client, payload, webhookChatFields, evt.Info.Chat, false)
+	setWebhookJID(ctx, client, payload, webhookFromFields, evt.Info.Sender, false)
 }
 
 func buildMessageBody(ctx context.Context, client *whatsmeow.Client, evt *events.Message, payload map[string]any) error {
@@ -293,12 +402,17 @@ func buildOptionalFields(ctx context.Context, client *whatsmeow.Client, evt *eve
 
 	buildOtherMessageTypes(msg, payload)
 
+	if orderMessage := msg.GetOrderMessage(); orderMessage != nil {
+		payload["order"] = buildWebhookOrderPayload(ctx, client, orderMessage)
+	}
+
 	return nil
 }
 
 func buildMediaFields(ctx context.Context, client *whatsmeow.Client, msg *waE2E.Message, payload map[string]any) error {
+	defer eventTimerFromContext(ctx).track(eventStageMedia)()
 	if audioMedia := msg.GetAudioMessage(); audioMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if config.Setting(&config.WhatsappAutoDownloadMedia) {
 			extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, audioMedia)
 			if err != nil {
 				// Media expired/unavailable: skip the attachment but keep
@@ -316,7 +430,7 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, msg *waE2E.
 	}
 
 	if documentMedia := msg.GetDocumentMessage(); documentMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if config.Setting(&config.WhatsappAutoDownloadMedia) {
 			extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, documentMedia)
 			if err != nil {
 				// Media expired/unavailable: skip the attachment but keep
@@ -335,7 +449,7 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, msg *waE2E.
 	}
 
 	if imageMedia := msg.GetImageMessage(); imageMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if config.Setting(&config.WhatsappAutoDownloadMedia) {
 			extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, imageMedia)
 			if err != nil {
 				// Media expired/unavailable: skip the attachment but keep
@@ -354,7 +468,7 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, msg *waE2E.
 	}
 
 	if stickerMedia := msg.GetStickerMessage(); stickerMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if config.Setting(&config.WhatsappAutoDownloadMedia) {
 			extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, stickerMedia)
 			if err != nil {
 				// Media expired/unavailable: skip the attachment but keep
@@ -372,7 +486,7 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, msg *waE2E.
 	}
 
 	if videoMedia := msg.GetVideoMessage(); videoMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if config.Setting(&config.WhatsappAutoDownloadMedia) {
 			extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, videoMedia)
 			if err != nil {
 				// Media expired/unavailable: skip the attachment but keep
@@ -391,7 +505,7 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, msg *waE2E.
 	}
 
 	if ptvMedia := msg.GetPtvMessage(); ptvMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if config.Setting(&config.WhatsappAutoDownloadMedia) {
 			extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, ptvMedia)
 			if err != nil {
 				// Media expired/unavailable: skip the attachment but keep
@@ -438,18 +552,77 @@ func buildOtherMessageTypes(msg *waE2E.Message, payload map[string]any) {
 	}
 
 	if liveLocationMessage := msg.GetLiveLocationMessage(); liveLocationMessage != nil {
-		payload["live_location"] = liveLocationMessage
+		payload["live_location"] = webhookLiveLocationPayload{
+			DegreesLatitude:  liveLocationMessage.GetDegreesLatitude(),
+			DegreesLongitude: liveLocationMessage.GetDegreesLongitude(),
+			AccuracyInMeters: liveLocationMessage.GetAccuracyInMeters(),
+			SpeedInMps:       liveLocationMessage.GetSpeedInMps(),
+			Heading:          liveLocationMessage.GetDegreesClockwiseFromMagneticNorth(),
+			Caption:          liveLocationMessage.GetCaption(),
+			SequenceNumber:   liveLocationMessage.GetSequenceNumber(),
+			TimeOffset:       liveLocationMessage.GetTimeOffset(),
+		}
 	}
 
 	if locationMessage := msg.GetLocationMessage(); locationMessage != nil {
 		payload["location"] = locationMessage
 	}
 
-	if orderMessage := msg.GetOrderMessage(); orderMessage != nil {
-		payload["order"] = orderMessage
+	if poll := utils.ExtractPollCreation(msg); poll != nil {
+		payload["poll"] = buildWebhookPollPayload(poll)
 	}
 }
 
+// buildWebhookOrderPayload describes order, fetching its line items with the
+// token of the message. The order is still forwarded without items when they
+// cannot be fetched.
+func buildWebhookOrderPayload(ctx context.Context, client *whatsmeow.Client, order *waE2E.OrderMessage) webhookOrderPayload {
+	payload := webhookOrderPayload{
+		OrderID:         order.GetOrderID(),
+		Title:           order.GetOrderTitle(),
+		Message:         order.GetMessage(),
+		SellerJID:       order.GetSellerJID(),
+		ItemCount:       order.GetItemCount(),
+		Currency:        order.GetTotalCurrencyCode(),
+		TotalAmount1000: order.GetTotalAmount1000(),
+		Items:           []webhookOrderItemPayload{},
+	}
+	if order.Status != nil {
+		payload.Status = strings.ToLower(order.GetStatus().String())
+	}
+	if order.Surface != nil {
+		payload.Surface = strings.ToLower(order.GetSurface().String())
+	}
+
+	if client == nil || order.GetOrderID() == "" || order.GetToken() == "" {
+		return payload
+	}
+	detailsCtx, cancel := context.WithTimeout(ctx, orderDetailsTimeout)
+	defer cancel()
+	details, err := GetOrderDetails(detailsCtx, client, order.GetOrderID(), order.GetToken())
+	if err != nil {
+		logrus.Warnf("Failed to get items of order %s: %v", order.GetOrderID(), err)
+		return payload
+	}
+	for _, item := range details.Items {
+		payload.Items = append(payload.Items, webhookOrderItemPayload{
+			ProductID:       item.ProductID,
+			Name:            item.Name,
+			ImageURL:        item.ImageURL,
+			Quantity:        item.Quantity,
+			Currency:        item.Currency,
+			PriceAmount1000: item.PriceAmount1000,
+		})
+	}
+	if payload.Currency == "" {
+		payload.Currency = details.Currency
+	}
+	if payload.TotalAmount1000 == 0 {
+		payload.TotalAmount1000 = details.TotalAmount1000
+	}
+	return payload
+}
+
 func buildWebhookContactPayload(contact *waE2E.ContactMessage) webhookContactPayload {
 	if contact == nil {
 		return webhookContactPayload{}
@@ -2,6 +2,7 @@ package whatsapp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -33,28 +34,63 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 	// decryption fails.
 	evt = materializeSecretEditMessage(ctx, evt, client)
 
+	// Time the message through storage, media, auto-reply and webhook delivery;
+	// handleWebhookForward finishes the timer.
+	timer := newEventTimer(EventTypeMessage, evt.Info.ID, evt.Info.Chat.ToNonAD().String())
+	ctx = contextWithEventTimer(ctx, timer)
+
 	if isReactionMessage(evt) {
+		stopStorage := timer.track(eventStageStorage)
 		if err := chatStorageRepo.CreateReaction(ctx, evt); err != nil {
 			log.Errorf("Failed to store incoming reaction %s: %v", evt.Info.ID, err)
 		}
+		stopStorage()
 
 		handleWebhookForward(ctx, evt, chatStorageRepo, client)
 		return
 	}
 
+	if isPinMessage(evt) {
+		stopStorage := timer.track(eventStageStorage)
+		if err := chatStorageRepo.CreateMessagePin(ctx, evt); err != nil {
+			log.Errorf("Failed to store pin %s: %v", evt.Info.ID, err)
+		}
+		stopStorage()
+
+		handleWebhookForward(ctx, evt, chatStorageRepo, client)
+		return
+	}
+
+	if isPollUpdateMessage(evt) {
+		handlePollVote(ctx, evt, chatStorageRepo, client)
+
+		handleWebhookForward(ctx, evt, chatStorageRepo, client)
+		return
+	}
+
+	stopStorage := timer.track(eventStageStorage)
 	if err := chatStorageRepo.CreateMessage(ctx, evt); err != nil {
 		// Log storage errors to avoid silent failures that could lead to data loss
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
 	}
+	if evt.Info.Chat.Server == types.NewsletterServer {
+		nameNewsletterChat(ctx, evt.Info.Chat, chatStorageRepo, client)
+	}
+	stopStorage()
 
 	// Handle image message if present
 	handleImageMessage(ctx, evt, client)
 
 	// Auto-mark message as read if configured
-	handleAutoMarkRead(ctx, evt, client)
+	handleAutoMarkRead(ctx, evt, chatStorageRepo, client)
+
+	// Ask for the contact's online status if presence webhooks are enabled
+	subscribePresenceForWebhook(ctx, client, evt.Info)
 
 	// Handle auto-reply if configured
+	stopAutoReply := timer.track(eventStageAutoReply)
 	handleAutoReply(ctx, evt, chatStorageRepo, client)
+	stopAutoReply()
 
 	// Forward to webhook if configured
 	handleWebhookForward(ctx, evt, chatStorageRepo, client)
@@ -78,13 +114,14 @@ func buildMessageMetaParts(evt *events.Message) []string {
 }
 
 func handleImageMessage(ctx context.Context, evt *events.Message, client *whatsmeow.Client) {
-	if !config.WhatsappAutoDownloadMedia {
+	if !config.Setting(&config.WhatsappAutoDownloadMedia) {
 		return
 	}
 	if client == nil {
 		return
 	}
 	if img := evt.Message.GetImageMessage(); img != nil {
+		defer eventTimerFromContext(ctx).track(eventStageMedia)()
 		if extracted, err := utils.ExtractMedia(ctx, client, config.PathStorages, img); err != nil {
 			log.Errorf("Failed to download image: %v", err)
 		} else {
@@ -93,9 +130,9 @@ func handleImageMessage(ctx context.Context, evt *events.Message, client *whatsm
 	}
 }
 
-func handleAutoMarkRead(ctx context.Context, evt *events.Message, client *whatsmeow.Client) {
+func handleAutoMarkRead(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
 	// Only mark read if auto-mark read is enabled and message is incoming
-	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe {
+	if !config.Setting(&config.WhatsappAutoMarkRead) || evt.Info.IsFromMe {
 		return
 	}
 
@@ -113,6 +150,8 @@ func handleAutoMarkRead(ctx context.Context, evt *events.Message, client *whatsm
 		log.Warnf("Failed to mark message %s as read: %v", evt.Info.ID, err)
 	} else {
 		log.Debugf("Marked message %s as read", evt.Info.ID)
+		unread := 0
+		updateChatStateFromAppState(ctx, chat, chatStorageRepo, client, "auto_mark_read", domainChatStorage.ChatStateUpdate{UnreadCount: &unread})
 	}
 }
 
@@ -148,7 +187,12 @@ func materializeSecretEditMessage(ctx context.Context, evt *events.Message, clie
 	return &cloned
 }
 
+// handleWebhookForward dispatches the message to the configured webhooks and
+// finishes the event timer carried by ctx once delivery is done, or right away
+// when nothing is dispatched.
 func handleWebhookForward(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	timer := eventTimerFromContext(ctx)
+
 	// Skip webhook for protocol messages that are internal sync messages
 	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
 		protocolType := protocolMessage.GetType().String()
@@ -159,18 +203,39 @@ func handleWebhookForward(ctx context.Context, evt *events.Message, chatStorageR
 			// These are meaningful user actions, allow webhook
 		default:
 			log.Debugf("Skipping webhook for protocol message type: %s", protocolType)
+			timer.finish()
 			return
 		}
 	}
 
-	if (len(config.WhatsappWebhook) > 0 || config.ChatwootEnabled) &&
+	if (hasWebhookTargets() || config.ChatwootEnabled) &&
 		!strings.Contains(evt.Info.SourceString(), "broadcast") {
-		go func(e *events.Message, repo domainChatStorage.IChatStorageRepository, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		stopQueue := timer.track(eventStageWebhookQueue)
+		deviceID := webhookDeviceID(ctx)
+		dispatchChatWebhook(deviceID, evt.Info.Chat.ToNonAD().String(), func() {
+			stopQueue()
+			defer timer.finish()
+			webhookCtx, cancel := context.WithTimeout(contextWithEventTimer(webhookContext(ctx), timer), 30*time.Second)
 			defer cancel()
-			if err := forwardMessageToWebhook(webhookCtx, c, e, repo); err != nil {
+			webhookCtx = contextWithWebhookOutbox(webhookCtx, webhookOutboxRef{
+				repo:      chatStorageRepo,
+				deviceID:  deviceID,
+				messageID: evt.Info.ID,
+				eventName: EventTypeMessage,
+			})
+			err := forwardMessageToWebhook(webhookCtx, client, evt, chatStorageRepo)
+			if errors.Is(err, errWebhookOutboxPending) {
+				// The batch marks the outbox event done once posted.
+				return
+			}
+			if err != nil {
+				// Left pending, the outbox event (if any) is retried by the dispatcher.
 				logrus.Error("Failed forward to webhook: ", err)
+				return
 			}
-		}(evt, chatStorageRepo, client)
+			markWebhookOutboxDone(chatStorageRepo, deviceID, evt.Info.ID)
+		})
+		return
 	}
+	timer.finish()
 }
@@ -13,6 +13,7 @@ import (
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestHandleMessageReactionStoresReactionAndForwardsWebhook(t *testing.T) {
@@ -74,11 +75,72 @@ func TestHandleMessageReactionStoresReactionAndForwardsWebhook(t *testing.T) {
 	}
 }
 
+func TestHandleMessagePinStoresPinAndForwardsWebhook(t *testing.T) {
+	originalWebhookURLs := config.WhatsappWebhook
+	originalWebhookEvents := config.WhatsappWebhookEvents
+	originalSubmit := submitWebhookFn
+	originalLog := log
+	defer func() {
+		config.WhatsappWebhook = originalWebhookURLs
+		config.WhatsappWebhookEvents = originalWebhookEvents
+		submitWebhookFn = originalSubmit
+		log = originalLog
+	}()
+
+	log = waLog.Noop
+	config.WhatsappWebhook = []string{"https://example.test/webhook"}
+	config.WhatsappWebhookEvents = nil
+
+	repo := &messageHandlerRepoSpy{}
+	done := make(chan map[string]any, 1)
+	submitWebhookFn = func(_ context.Context, payload map[string]any, _ string) error {
+		done <- payload
+		return nil
+	}
+
+	evt := reactionEventForTest("pin-event-1", "msg-1", "")
+	evt.Message = &waE2E.Message{
+		PinInChatMessage: &waE2E.PinInChatMessage{
+			Key:  &waCommon.MessageKey{ID: protoString("msg-1")},
+			Type: waE2E.PinInChatMessage_PIN_FOR_ALL.Enum(),
+		},
+		MessageContextInfo: &waE2E.MessageContextInfo{MessageAddOnDurationInSecs: proto.Uint32(86400)},
+	}
+	handleMessage(context.Background(), evt, repo, nil)
+
+	if got := repo.createPinCount(); got != 1 {
+		t.Fatalf("expected pin path to call CreateMessagePin once, got %d", got)
+	}
+	if got := repo.createMessageCount(); got != 0 {
+		t.Fatalf("expected pin path not to call CreateMessage, got %d", got)
+	}
+
+	select {
+	case payload := <-done:
+		if got := payload["event"]; got != EventTypeMessagePin {
+			t.Fatalf("expected webhook event %q, got %v", EventTypeMessagePin, got)
+		}
+		eventPayload := payload["payload"].(map[string]any)
+		if got := eventPayload["pinned_message_id"]; got != "msg-1" {
+			t.Fatalf("expected pinned message id, got %v", got)
+		}
+		if got := eventPayload["pinned"]; got != true {
+			t.Fatalf("expected pinned true, got %v", got)
+		}
+		if got := eventPayload["expires_at"]; got != "2026-05-17T08:00:00Z" {
+			t.Fatalf("expected expiry a day after the pin, got %v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for webhook submission")
+	}
+}
+
 type messageHandlerRepoSpy struct {
 	domainChatStorage.IChatStorageRepository
 	mu                  sync.Mutex
 	createMessageCalls  int
 	createReactionCalls int
+	createPinCalls      int
 }
 
 func (r *messageHandlerRepoSpy) CreateMessage(context.Context, *events.Message) error {
@@ -95,6 +157,13 @@ func (r *messageHandlerRepoSpy) CreateReaction(context.Context, *events.Message)
 	return nil
 }
 
+func (r *messageHandlerRepoSpy) CreateMessagePin(context.Context, *events.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.createPinCalls++
+	return nil
+}
+
 func (r *messageHandlerRepoSpy) createMessageCount() int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -107,6 +176,12 @@ func (r *messageHandlerRepoSpy) createReactionCount() int {
 	return r.createReactionCalls
 }
 
+func (r *messageHandlerRepoSpy) createPinCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.createPinCalls
+}
+
 func reactionEventForTest(eventID, targetID, emoji string) *events.Message {
 	return &events.Message{
 		Info: types.MessageInfo{
@@ -12,6 +12,7 @@ import (
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
 )
 
 // chatNameStubRepo implements only the IChatStorageRepository methods touched
@@ -161,6 +162,41 @@ func TestBuildEventPayloadIncludesIsFromMe(t *testing.T) {
 	}
 }
 
+func TestCreateWebhookEventOutgoing(t *testing.T) {
+	prev := config.WhatsappWebhookOutgoing
+	t.Cleanup(func() { config.WhatsappWebhookOutgoing = prev })
+
+	message := func(fromMe bool) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{
+					Chat:     types.NewJID("123", types.DefaultUserServer),
+					Sender:   types.NewJID("456", types.DefaultUserServer),
+					IsFromMe: fromMe,
+				},
+				ID: "MSG123",
+			},
+			Message: &waE2E.Message{Conversation: protoString("hello")},
+		}
+	}
+
+	config.WhatsappWebhookOutgoing = false
+	webhookEvent, err := createWebhookEvent(context.Background(), nil, message(true), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EventTypeMessage, webhookEvent.Event)
+	assert.NotContains(t, webhookEvent.Payload, "sent_from")
+
+	config.WhatsappWebhookOutgoing = true
+	webhookEvent, err = createWebhookEvent(context.Background(), nil, message(true), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EventTypeMessageOutgoing, webhookEvent.Event)
+	assert.Equal(t, OutgoingSentFromPhone, webhookEvent.Payload["sent_from"])
+
+	webhookEvent, err = createWebhookEvent(context.Background(), nil, message(false), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EventTypeMessage, webhookEvent.Event, "incoming messages keep the message event")
+}
+
 func TestBuildEventPayloadRevokedIncludesIsFromMe(t *testing.T) {
 	key := &waCommon.MessageKey{
 		RemoteJID: protoString("123@s.whatsapp.net"),
@@ -537,3 +573,115 @@ func TestBuildEventPayloadContactsArrayIncludesPhoneNumbers(t *testing.T) {
 		t.Fatalf("expected second phone number, got %q", contacts[1].PhoneNumber)
 	}
 }
+
+type captionEditStubRepo struct {
+	domainChatStorage.IChatStorageRepository
+	edits []*domainChatStorage.MessageEdit
+}
+
+func (r *captionEditStubRepo) GetMessageEdits(string, string) ([]*domainChatStorage.MessageEdit, error) {
+	return r.edits, nil
+}
+
+func TestBuildEventPayloadCaptionEditIncludesPreviousCaption(t *testing.T) {
+	repo := &captionEditStubRepo{edits: []*domainChatStorage.MessageEdit{
+		{OriginalMessageID: "IMG1", EditEventID: "EDIT-OLD", PreviousContent: "first", NewContent: "old caption"},
+		{OriginalMessageID: "IMG1", EditEventID: "EDIT-2", PreviousContent: "old caption", NewContent: "new caption"},
+	}}
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:   types.NewJID("123", types.DefaultUserServer),
+				Sender: types.NewJID("123", types.DefaultUserServer),
+			},
+			ID:        "EDIT-2",
+			Timestamp: time.Date(2026, time.February, 8, 10, 0, 0, 0, time.UTC),
+		},
+		Message: &waE2E.Message{
+			ProtocolMessage: &waE2E.ProtocolMessage{
+				Type: protoProtocolMessageType(waE2E.ProtocolMessage_MESSAGE_EDIT),
+				Key:  &waCommon.MessageKey{ID: protoString("IMG1"), RemoteJID: protoString("123@s.whatsapp.net")},
+				EditedMessage: &waE2E.Message{
+					ImageMessage: &waE2E.ImageMessage{Caption: protoString("new caption")},
+				},
+			},
+		},
+	}
+
+	eventType, payload, err := buildEventPayload(context.Background(), nil, evt, repo)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	assert.Equal(t, EventTypeMessageEdited, eventType)
+	assert.Equal(t, "IMG1", payload["original_message_id"])
+	assert.Equal(t, "new caption", payload["body"])
+	assert.Equal(t, "new caption", payload["caption"])
+	assert.Equal(t, "old caption", payload["previous_caption"])
+	assert.Equal(t, "image", payload["media_type"])
+}
+
+func TestBuildOtherMessageTypesLiveLocation(t *testing.T) {
+	payload := map[string]any{}
+	buildOtherMessageTypes(&waE2E.Message{
+		LiveLocationMessage: &waE2E.LiveLocationMessage{
+			DegreesLatitude:  proto.Float64(-7.8050297),
+			DegreesLongitude: proto.Float64(110.4549165),
+			AccuracyInMeters: proto.Uint32(12),
+			SequenceNumber:   proto.Int64(3),
+			TimeOffset:       proto.Uint32(90),
+			JPEGThumbnail:    []byte{0xff, 0xd8},
+		},
+	}, payload)
+
+	assert.Equal(t, webhookLiveLocationPayload{
+		DegreesLatitude:  -7.8050297,
+		DegreesLongitude: 110.4549165,
+		AccuracyInMeters: 12,
+		SequenceNumber:   3,
+		TimeOffset:       90,
+	}, payload["live_location"])
+	assert.Equal(t, "Live Location: -7.805030, 110.454916", extractStructuredMessageContent(payload))
+}
+
+func TestBuildEventPayloadOrderIsStructured(t *testing.T) {
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:   types.NewJID("123", types.DefaultUserServer),
+				Sender: types.NewJID("123", types.DefaultUserServer),
+			},
+			ID:        "MSG301",
+			Timestamp: time.Date(2026, time.June, 1, 10, 0, 0, 0, time.UTC),
+		},
+		Message: &waE2E.Message{
+			OrderMessage: &waE2E.OrderMessage{
+				OrderID:           proto.String("ORDER1"),
+				ItemCount:         proto.Int32(3),
+				Status:            waE2E.OrderMessage_INQUIRY.Enum(),
+				Surface:           waE2E.OrderMessage_CATALOG.Enum(),
+				Message:           proto.String("Please deliver today"),
+				OrderTitle:        proto.String("Pizza"),
+				SellerJID:         proto.String("456@s.whatsapp.net"),
+				Token:             proto.String("token"),
+				TotalAmount1000:   proto.Int64(150000),
+				TotalCurrencyCode: proto.String("IDR"),
+			},
+		},
+	}
+
+	_, payload, err := buildEventPayload(context.Background(), nil, evt, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, webhookOrderPayload{
+		OrderID:         "ORDER1",
+		Title:           "Pizza",
+		Message:         "Please deliver today",
+		Status:          "inquiry",
+		Surface:         "catalog",
+		SellerJID:       "456@s.whatsapp.net",
+		ItemCount:       3,
+		Currency:        "IDR",
+		TotalAmount1000: 150000,
+		Items:           []webhookOrderItemPayload{},
+	}, payload["order"])
+	assert.Equal(t, "Order: Pizza", extractStructuredMessageContent(payload))
+}
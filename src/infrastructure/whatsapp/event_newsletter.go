@@ -4,9 +4,10 @@ import (
 	"context"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
@@ -14,14 +15,14 @@ import (
 func handleNewsletterJoin(ctx context.Context, evt *events.NewsletterJoin, deviceID string, client *whatsmeow.Client) {
 	log.Infof("Joined newsletter %s", evt.ID)
 
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.NewsletterJoin) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.ID.String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardNewsletterJoinToWebhook(webhookCtx, e, deviceID); err != nil {
+			if err := forwardNewsletterJoinToWebhook(webhookCtx, evt, deviceID); err != nil {
 				logrus.Errorf("Failed to forward newsletter join to webhook: %v", err)
 			}
-		}(evt)
+		})
 	}
 }
 
@@ -29,14 +30,14 @@ func handleNewsletterJoin(ctx context.Context, evt *events.NewsletterJoin, devic
 func handleNewsletterLeave(ctx context.Context, evt *events.NewsletterLeave, deviceID string, client *whatsmeow.Client) {
 	log.Infof("Left newsletter %s (role: %s)", evt.ID, evt.Role)
 
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.NewsletterLeave) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.ID.String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardNewsletterLeaveToWebhook(webhookCtx, e, deviceID); err != nil {
+			if err := forwardNewsletterLeaveToWebhook(webhookCtx, evt, deviceID); err != nil {
 				logrus.Errorf("Failed to forward newsletter leave to webhook: %v", err)
 			}
-		}(evt)
+		})
 	}
 }
 
@@ -44,14 +45,14 @@ func handleNewsletterLeave(ctx context.Context, evt *events.NewsletterLeave, dev
 func handleNewsletterLiveUpdate(ctx context.Context, evt *events.NewsletterLiveUpdate, deviceID string, client *whatsmeow.Client) {
 	log.Infof("Newsletter %s: %d new message(s)", evt.JID, len(evt.Messages))
 
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.NewsletterLiveUpdate) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.JID.String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardNewsletterLiveUpdateToWebhook(webhookCtx, e, deviceID); err != nil {
+			if err := forwardNewsletterLiveUpdateToWebhook(webhookCtx, evt, deviceID, client); err != nil {
 				logrus.Errorf("Failed to forward newsletter live update to webhook: %v", err)
 			}
-		}(evt)
+		})
 	}
 }
 
@@ -59,17 +60,106 @@ func handleNewsletterLiveUpdate(ctx context.Context, evt *events.NewsletterLiveU
 func handleNewsletterMuteChange(ctx context.Context, evt *events.NewsletterMuteChange, deviceID string, client *whatsmeow.Client) {
 	log.Infof("Newsletter %s mute changed to: %s", evt.ID, evt.Mute)
 
-	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.NewsletterMuteChange) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if hasWebhookTargets() {
+		dispatchChatWebhook(deviceID, evt.ID.String(), func() {
+			webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
 			defer cancel()
-			if err := forwardNewsletterMuteChangeToWebhook(webhookCtx, e, deviceID); err != nil {
+			if err := forwardNewsletterMuteChangeToWebhook(webhookCtx, evt, deviceID); err != nil {
 				logrus.Errorf("Failed to forward newsletter mute change to webhook: %v", err)
 			}
-		}(evt)
+		})
 	}
 }
 
+// newsletterMetadata returns the metadata of a newsletter, cached per device,
+// or nil when it cannot be fetched.
+func newsletterMetadata(ctx context.Context, client *whatsmeow.Client, jid types.JID) *types.NewsletterMetadata {
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return nil
+	}
+	ic := GetInfoCache(client.Store.ID.ToNonAD().String())
+	key := jid.ToNonAD().String()
+	if cached, ok := ic.GetNewsletterInfo(key); ok {
+		return cached.Data
+	}
+	metadata, err := client.GetNewsletterInfo(ctx, jid)
+	if err != nil {
+		log.Debugf("Could not fetch newsletter info for %s: %v", key, err)
+		ic.SetNewsletterInfoError(key, err.Error())
+		return nil
+	}
+	ic.SetNewsletterInfo(key, metadata)
+	return metadata
+}
+
+// newsletterMessageFields describes the newsletter a message was posted in,
+// for the "newsletter" field of message webhooks.
+func newsletterMessageFields(ctx context.Context, client *whatsmeow.Client, info types.MessageInfo) map[string]any {
+	fields := map[string]any{
+		"id": info.Chat.ToNonAD().String(),
+	}
+	if info.ServerID != 0 {
+		fields["server_id"] = info.ServerID
+	}
+	if metadata := newsletterMetadata(ctx, client, info.Chat); metadata != nil {
+		if metadata.ThreadMeta.Name.Text != "" {
+			fields["name"] = metadata.ThreadMeta.Name.Text
+		}
+		if metadata.ThreadMeta.VerificationState == types.NewsletterVerificationStateVerified {
+			fields["verified"] = true
+		}
+	}
+	return fields
+}
+
+// nameNewsletterChat replaces the placeholder "Newsletter <id>" name chat
+// storage gives newsletter chats with the newsletter's own name.
+func nameNewsletterChat(ctx context.Context, jid types.JID, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	if chatStorageRepo == nil || client == nil || client.Store == nil || client.Store.ID == nil {
+		return
+	}
+	metadata := newsletterMetadata(ctx, client, jid)
+	if metadata == nil || metadata.ThreadMeta.Name.Text == "" {
+		return
+	}
+	chat, err := chatStorageRepo.GetChatByDevice(client.Store.ID.ToNonAD().String(), jid.ToNonAD().String())
+	if err != nil || chat == nil || chat.Name == metadata.ThreadMeta.Name.Text {
+		return
+	}
+	chat.Name = metadata.ThreadMeta.Name.Text
+	if err := chatStorageRepo.StoreChat(chat); err != nil {
+		log.Warnf("Failed to name newsletter chat %s: %v", jid, err)
+	}
+}
+
+// StoreNewsletterMessages saves messages fetched from a newsletter's history
+// to chat storage, like newsletter messages received live. Messages without
+// content, such as those only carrying view counts, are skipped.
+func StoreNewsletterMessages(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client, jid types.JID, messages []*types.NewsletterMessage) {
+	if chatStorageRepo == nil {
+		return
+	}
+	for _, msg := range messages {
+		if msg == nil || msg.Message == nil || msg.MessageID == "" {
+			continue
+		}
+		evt := &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: jid, Sender: jid},
+				ID:            msg.MessageID,
+				ServerID:      msg.MessageServerID,
+				Type:          msg.Type,
+				Timestamp:     msg.Timestamp,
+			},
+			Message: msg.Message,
+		}
+		if err := chatStorageRepo.CreateMessage(ctx, evt); err != nil {
+			log.Errorf("Failed to store newsletter message %s: %v", msg.MessageID, err)
+		}
+	}
+	nameNewsletterChat(ctx, jid, chatStorageRepo, client)
+}
+
 // Webhook forwarding functions
 
 func forwardNewsletterJoinToWebhook(ctx context.Context, evt *events.NewsletterJoin, deviceID string) error {
@@ -113,7 +203,7 @@ func forwardNewsletterLeaveToWebhook(ctx context.Context, evt *events.Newsletter
 	return forwardPayloadToConfiguredWebhooks(ctx, body, "newsletter.left")
 }
 
-func forwardNewsletterLiveUpdateToWebhook(ctx context.Context, evt *events.NewsletterLiveUpdate, deviceID string) error {
+func forwardNewsletterLiveUpdateToWebhook(ctx context.Context, evt *events.NewsletterLiveUpdate, deviceID string, client *whatsmeow.Client) error {
 	messages := make([]map[string]any, 0, len(evt.Messages))
 	for _, msg := range evt.Messages {
 		m := map[string]any{
@@ -135,6 +225,9 @@ func forwardNewsletterLiveUpdateToWebhook(ctx context.Context, evt *events.Newsl
 		"newsletter_id": evt.JID.String(),
 		"messages":      messages,
 	}
+	if metadata := newsletterMetadata(ctx, client, evt.JID); metadata != nil && metadata.ThreadMeta.Name.Text != "" {
+		payload["name"] = metadata.ThreadMeta.Name.Text
+	}
 
 	body := map[string]any{
 		"event":     "newsletter.message",
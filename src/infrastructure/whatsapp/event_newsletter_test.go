@@ -0,0 +1,100 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+type newsletterRepoSpy struct {
+	domainChatStorage.IChatStorageRepository
+	messages []*events.Message
+	chats    map[string]*domainChatStorage.Chat
+}
+
+func (r *newsletterRepoSpy) CreateMessage(_ context.Context, evt *events.Message) error {
+	r.messages = append(r.messages, evt)
+	jid := evt.Info.Chat.String()
+	if r.chats[jid] == nil {
+		r.chats[jid] = &domainChatStorage.Chat{JID: jid, Name: "Newsletter " + evt.Info.Chat.User}
+	}
+	return nil
+}
+
+func (r *newsletterRepoSpy) GetChatByDevice(_, jid string) (*domainChatStorage.Chat, error) {
+	return r.chats[jid], nil
+}
+
+func (r *newsletterRepoSpy) StoreChat(chat *domainChatStorage.Chat) error {
+	r.chats[chat.JID] = chat
+	return nil
+}
+
+// newsletterTestClient returns a client whose device has the metadata of
+// newsletter cached, so no request reaches WhatsApp.
+func newsletterTestClient(t *testing.T, newsletter types.JID, name string) *whatsmeow.Client {
+	t.Helper()
+	deviceJID := types.NewJID("628111000111", types.DefaultUserServer)
+	ClearDeviceCache(deviceJID.String())
+	t.Cleanup(func() { ClearDeviceCache(deviceJID.String()) })
+
+	metadata := &types.NewsletterMetadata{ID: newsletter}
+	metadata.ThreadMeta.Name.Text = name
+	metadata.ThreadMeta.VerificationState = types.NewsletterVerificationStateVerified
+	GetInfoCache(deviceJID.String()).SetNewsletterInfo(newsletter.String(), metadata)
+
+	return &whatsmeow.Client{Store: &store.Device{ID: &deviceJID}}
+}
+
+func TestNewsletterMessageFields(t *testing.T) {
+	newsletter := types.NewJID("120363123456789", types.NewsletterServer)
+	client := newsletterTestClient(t, newsletter, "Tech News Daily")
+
+	fields := newsletterMessageFields(context.Background(), client, types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: newsletter, Sender: newsletter},
+		ServerID:      123,
+	})
+	if fields["id"] != newsletter.String() || fields["name"] != "Tech News Daily" || fields["server_id"] != types.MessageServerID(123) || fields["verified"] != true {
+		t.Errorf("fields = %v", fields)
+	}
+
+	// Without metadata only what the message carries is known.
+	fields = newsletterMessageFields(context.Background(), nil, types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: newsletter, Sender: newsletter},
+	})
+	if len(fields) != 1 || fields["id"] != newsletter.String() {
+		t.Errorf("fields without metadata = %v", fields)
+	}
+}
+
+func TestStoreNewsletterMessages(t *testing.T) {
+	newsletter := types.NewJID("120363123456789", types.NewsletterServer)
+	client := newsletterTestClient(t, newsletter, "Tech News Daily")
+	repo := &newsletterRepoSpy{chats: map[string]*domainChatStorage.Chat{}}
+	posted := time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC)
+
+	StoreNewsletterMessages(context.Background(), repo, client, newsletter, []*types.NewsletterMessage{
+		{MessageServerID: 123, MessageID: "ABC123", Type: "text", Timestamp: posted, Message: &waE2E.Message{Conversation: proto.String("New release is out")}},
+		// Only view counts, nothing to store.
+		{MessageServerID: 124, MessageID: "ABC124", Type: "text", Timestamp: posted, ViewsCount: 10},
+	})
+
+	if len(repo.messages) != 1 {
+		t.Fatalf("stored %d messages, want 1", len(repo.messages))
+	}
+	info := repo.messages[0].Info
+	if info.ID != "ABC123" || info.ServerID != 123 || info.Chat != newsletter || info.Sender != newsletter || !info.Timestamp.Equal(posted) {
+		t.Errorf("stored message info = %+v", info)
+	}
+	if chat := repo.chats[newsletter.String()]; chat == nil || chat.Name != "Tech News Daily" {
+		t.Errorf("chat = %+v, want it named after the newsletter", chat)
+	}
+}
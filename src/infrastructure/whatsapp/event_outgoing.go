@@ -0,0 +1,134 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Values of the sent_from field of message.outgoing payloads.
+const (
+	OutgoingSentFromPhone = "phone"
+	OutgoingSentFromAPI   = "api"
+)
+
+// outgoingWebhookEvent returns the event a message payload is forwarded as:
+// message.outgoing for a message sent from the linked phone when
+// WhatsappWebhookOutgoing is on, eventType otherwise.
+func outgoingWebhookEvent(evt *events.Message, eventType string, payload map[string]any) string {
+	if eventType != EventTypeMessage || !evt.Info.IsFromMe || !config.Setting(&config.WhatsappWebhookOutgoing) {
+		return eventType
+	}
+	payload["sent_from"] = OutgoingSentFromPhone
+	return EventTypeMessageOutgoing
+}
+
+// SentMessageEvent returns the message event of a message sent through this
+// API, as it would have been received had WhatsApp echoed it back. It returns
+// nil when the client is not logged in.
+func SentMessageEvent(client *whatsmeow.Client, recipient types.JID, resp whatsmeow.SendResponse, msg *waE2E.Message) *events.Message {
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return nil
+	}
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     recipient,
+				Sender:   client.Store.ID.ToNonAD(),
+				IsFromMe: true,
+				IsGroup:  recipient.Server == types.GroupServer,
+			},
+			ID:        resp.ID,
+			Timestamp: resp.Timestamp,
+		},
+		Message: msg,
+	}
+}
+
+// ForwardSentMessageToWebhook forwards a message sent through this API as a
+// message.outgoing event when WhatsappWebhookOutgoingAPI is on. WhatsApp does
+// not echo these back as message events, so this is the only place consumers
+// learn about them. Delivery runs in the background, in order with the
+// events of the chat.
+func ForwardSentMessageToWebhook(ctx context.Context, client *whatsmeow.Client, recipient types.JID, resp whatsmeow.SendResponse, msg *waE2E.Message) {
+	if !config.Setting(&config.WhatsappWebhookOutgoingAPI) || !hasWebhookTargets() {
+		return
+	}
+	evt := SentMessageEvent(client, recipient, resp, msg)
+	if evt == nil {
+		return
+	}
+	deviceID := webhookDeviceID(ctx)
+	ctx = context.WithoutCancel(ctx)
+	dispatchChatWebhook(deviceID, recipient.ToNonAD().String(), func() {
+		webhookCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		_, chatStorageRepo := chatwootLinkStorageFromContext(webhookCtx)
+		webhookEvent, err := createWebhookEvent(webhookCtx, client, evt, chatStorageRepo)
+		if err != nil {
+			logrus.Errorf("Failed to build message.outgoing payload for %s: %v", resp.ID, err)
+			return
+		}
+		if webhookEvent.Event != EventTypeMessage && webhookEvent.Event != EventTypeMessageOutgoing {
+			// Reactions, edits and revokes sent through the API are not messages.
+			return
+		}
+		webhookEvent.Payload["sent_from"] = OutgoingSentFromAPI
+
+		body := map[string]any{
+			"event":     EventTypeMessageOutgoing,
+			"device_id": webhookEvent.DeviceID,
+			"payload":   webhookEvent.Payload,
+		}
+		if err := forwardPayloadToConfiguredWebhooks(webhookCtx, body, EventTypeMessageOutgoing); err != nil {
+			logrus.Errorf("Failed to forward message.outgoing for %s: %v", resp.ID, err)
+		}
+	})
+}
+
+// ForwardSentReactionToWebhook confirms a reaction sent through this API with
+// a message.reaction event, marked sent_from "api". An empty reaction means the
+// reaction was removed. Delivery runs in the background, in order with the
+// events of the chat.
+func ForwardSentReactionToWebhook(ctx context.Context, client *whatsmeow.Client, recipient types.JID, resp whatsmeow.SendResponse, msg *waE2E.Message) {
+	if !hasWebhookTargets() {
+		return
+	}
+	evt := SentMessageEvent(client, recipient, resp, msg)
+	if evt == nil {
+		return
+	}
+	deviceID := webhookDeviceID(ctx)
+	ctx = context.WithoutCancel(ctx)
+	dispatchChatWebhook(deviceID, recipient.ToNonAD().String(), func() {
+		webhookCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		_, chatStorageRepo := chatwootLinkStorageFromContext(webhookCtx)
+		webhookEvent, err := createWebhookEvent(webhookCtx, client, evt, chatStorageRepo)
+		if err != nil {
+			logrus.Errorf("Failed to build message.reaction payload for %s: %v", resp.ID, err)
+			return
+		}
+		if webhookEvent.Event != EventTypeMessageReaction {
+			return
+		}
+		webhookEvent.Payload["sent_from"] = OutgoingSentFromAPI
+
+		body := map[string]any{
+			"event":     EventTypeMessageReaction,
+			"device_id": webhookEvent.DeviceID,
+			"payload":   webhookEvent.Payload,
+		}
+		if err := forwardPayloadToConfiguredWebhooks(webhookCtx, body, EventTypeMessageReaction); err != nil {
+			logrus.Errorf("Failed to forward message.reaction for %s: %v", resp.ID, err)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package whatsapp
+
+import (
+	"context"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// decryptPollVoteFn decrypts the vote carried by a poll update. It is a seam so
+// tests can supply a vote without the poll's message secret.
+var decryptPollVoteFn = func(ctx context.Context, client *whatsmeow.Client, evt *events.Message) (*waE2E.PollVoteMessage, error) {
+	return client.DecryptPollVote(ctx, evt)
+}
+
+type webhookPollPayload struct {
+	Question        string   `json:"question"`
+	Options         []string `json:"options"`
+	SelectableCount uint32   `json:"selectable_count"`
+}
+
+type webhookPollResultsPayload struct {
+	Question        string                     `json:"question"`
+	SelectableCount uint32                     `json:"selectable_count"`
+	Options         []webhookPollOptionPayload `json:"options"`
+	TotalVoters     int                        `json:"total_voters"`
+}
+
+type webhookPollOptionPayload struct {
+	Name   string   `json:"name"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+func isPollUpdateMessage(evt *events.Message) bool {
+	if evt == nil || evt.Message == nil {
+		return false
+	}
+
+	return utils.UnwrapMessage(evt.Message).GetPollUpdateMessage() != nil
+}
+
+// handlePollVote decrypts an incoming poll update and stores the voter's selection.
+func handlePollVote(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	if client == nil || chatStorageRepo == nil {
+		return
+	}
+
+	vote, err := decryptPollVoteFn(ctx, client, evt)
+	if err != nil {
+		log.Warnf("Failed to decrypt poll vote %s: %v", evt.Info.ID, err)
+		return
+	}
+	if err := chatStorageRepo.CreatePollVote(ctx, evt, vote); err != nil {
+		log.Errorf("Failed to store poll vote %s: %v", evt.Info.ID, err)
+	}
+}
+
+// buildPollVoteFields adds the poll a vote belongs to, the voter's selection and
+// the poll's current results. Selection and results are omitted when the vote
+// cannot be decrypted or the poll is not stored.
+func buildPollVoteFields(ctx context.Context, client *whatsmeow.Client, evt *events.Message, pollUpdate *waE2E.PollUpdateMessage, chatStorageRepo domainChatStorage.IChatStorageRepository, payload map[string]any) {
+	pollMessageID := pollUpdate.GetPollCreationMessageKey().GetID()
+	payload["poll_message_id"] = pollMessageID
+
+	deviceID := pollDeviceID(ctx, client)
+	if chatStorageRepo == nil || pollMessageID == "" || deviceID == "" {
+		return
+	}
+	results, err := chatStorageRepo.GetPollResults(deviceID, pollMessageID)
+	if err != nil {
+		logrus.Debugf("Failed to load poll results for %s: %v", pollMessageID, err)
+		return
+	}
+	if results == nil || results.Poll == nil {
+		return
+	}
+	payload["poll_results"] = buildWebhookPollResultsPayload(results)
+
+	if client == nil {
+		return
+	}
+	vote, err := decryptPollVoteFn(ctx, client, evt)
+	if err != nil {
+		logrus.Debugf("Failed to decrypt poll vote %s: %v", evt.Info.ID, err)
+		return
+	}
+	payload["selected_options"] = utils.MatchPollOptionHashes(results.Poll.Options, vote.GetSelectedOptions())
+}
+
+func buildWebhookPollPayload(poll *waE2E.PollCreationMessage) webhookPollPayload {
+	return webhookPollPayload{
+		Question:        poll.GetName(),
+		Options:         utils.PollOptionNames(poll),
+		SelectableCount: poll.GetSelectableOptionsCount(),
+	}
+}
+
+func buildWebhookPollResultsPayload(results *domainChatStorage.PollResults) webhookPollResultsPayload {
+	options := make([]webhookPollOptionPayload, 0, len(results.Options))
+	for _, option := range results.Options {
+		options = append(options, webhookPollOptionPayload{
+			Name:   option.Name,
+			Votes:  option.Votes,
+			Voters: option.Voters,
+		})
+	}
+	return webhookPollResultsPayload{
+		Question:        results.Poll.Question,
+		SelectableCount: results.Poll.SelectableCount,
+		Options:         options,
+		TotalVoters:     results.TotalVoters,
+	}
+}
+
+// pollDeviceID returns the storage device id polls are keyed by: the device in
+// ctx when present, otherwise the client's own JID.
+func pollDeviceID(ctx context.Context, client *whatsmeow.Client) string {
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		if jid := inst.JID(); jid != "" {
+			return jid
+		}
+		return inst.ID()
+	}
+	if client != nil && client.Store != nil && client.Store.ID != nil {
+		return client.Store.ID.ToNonAD().String()
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+type pollStubRepo struct {
+	domainChatStorage.IChatStorageRepository
+	results  *domainChatStorage.PollResults
+	deviceID string
+}
+
+func (r *pollStubRepo) GetPollResults(deviceID, _ string) (*domainChatStorage.PollResults, error) {
+	r.deviceID = deviceID
+	return r.results, nil
+}
+
+func TestBuildEventPayloadPollVoteIncludesSelectionAndResults(t *testing.T) {
+	original := decryptPollVoteFn
+	t.Cleanup(func() { decryptPollVoteFn = original })
+	decryptPollVoteFn = func(context.Context, *whatsmeow.Client, *events.Message) (*waE2E.PollVoteMessage, error) {
+		return &waE2E.PollVoteMessage{SelectedOptions: whatsmeow.HashPollOptions([]string{"Sushi"})}, nil
+	}
+
+	repo := &pollStubRepo{results: &domainChatStorage.PollResults{
+		Poll: &domainChatStorage.Poll{MessageID: "POLL1", Question: "Lunch?", Options: []string{"Pizza", "Sushi"}, SelectableCount: 1},
+		Options: []domainChatStorage.PollOptionResult{
+			{Name: "Pizza", Voters: []string{}},
+			{Name: "Sushi", Votes: 1, Voters: []string{"123@s.whatsapp.net"}},
+		},
+		TotalVoters: 1,
+	}}
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:   types.NewJID("123", types.DefaultUserServer),
+				Sender: types.NewJID("123", types.DefaultUserServer),
+			},
+			ID:        "VOTE1",
+			Timestamp: time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC),
+		},
+		Message: &waE2E.Message{
+			PollUpdateMessage: &waE2E.PollUpdateMessage{
+				PollCreationMessageKey: &waCommon.MessageKey{ID: proto.String("POLL1")},
+			},
+		},
+	}
+	ctx := ContextWithDevice(context.Background(), NewDeviceInstance("device-a", nil, nil))
+
+	eventType, payload, err := buildEventPayload(ctx, &whatsmeow.Client{}, evt, repo)
+	require.NoError(t, err)
+
+	assert.Equal(t, EventTypeMessagePollVote, eventType)
+	assert.Equal(t, "device-a", repo.deviceID)
+	assert.Equal(t, "POLL1", payload["poll_message_id"])
+	assert.Equal(t, []string{"Sushi"}, payload["selected_options"])
+	results, ok := payload["poll_results"].(webhookPollResultsPayload)
+	require.True(t, ok)
+	assert.Equal(t, "Lunch?", results.Question)
+	assert.Equal(t, 1, results.TotalVoters)
+	assert.Equal(t, 1, results.Options[1].Votes)
+}
+
+func TestBuildOtherMessageTypesIncludesPoll(t *testing.T) {
+	payload := map[string]any{}
+	buildOtherMessageTypes(&waE2E.Message{
+		PollCreationMessage: &waE2E.PollCreationMessage{
+			Name: proto.String("Lunch?"),
+			Options: []*waE2E.PollCreationMessage_Option{
+				{OptionName: proto.String("Pizza")},
+				{OptionName: proto.String("Sushi")},
+			},
+			SelectableOptionsCount: proto.Uint32(2),
+		},
+	}, payload)
+
+	assert.Equal(t, webhookPollPayload{Question: "Lunch?", Options: []string{"Pizza", "Sushi"}, SelectableCount: 2}, payload["poll"])
+}
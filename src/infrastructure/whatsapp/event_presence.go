@@ -0,0 +1,132 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// EventTypePresenceUpdate is the webhook event for contacts going online or
+// offline and for typing or recording in a chat. It is opt-in through
+// WhatsappWebhookPresence since it is high volume.
+const EventTypePresenceUpdate = "presence.update"
+
+// presenceSubscriptions remembers the contacts each device asked WhatsApp for
+// presence updates of, so a contact is subscribed to once.
+var presenceSubscriptions sync.Map
+
+// subscribePresenceForWebhook subscribes to the presence of a direct chat's
+// contact, the way WhatsApp Web does when a chat is opened. WhatsApp only
+// sends a contact's online and offline updates after such a subscription.
+func subscribePresenceForWebhook(ctx context.Context, client *whatsmeow.Client, info types.MessageInfo) {
+	if !config.Setting(&config.WhatsappWebhookPresence) || client == nil || client.Store == nil || client.Store.ID == nil || info.IsGroup || info.IsFromMe {
+		return
+	}
+	contact := info.Chat.ToNonAD()
+	if contact.Server != types.DefaultUserServer && contact.Server != types.HiddenUserServer {
+		return
+	}
+	key := client.Store.ID.ToNonAD().String() + "|" + contact.String()
+	if _, subscribed := presenceSubscriptions.LoadOrStore(key, struct{}{}); subscribed {
+		return
+	}
+	go func() {
+		subscribeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		if err := client.SubscribePresence(subscribeCtx, contact); err != nil {
+			presenceSubscriptions.Delete(key)
+			logrus.Debugf("Failed to subscribe to presence of %s: %v", contact, err)
+		}
+	}()
+}
+
+// addPresenceSender sets from and from_lid, with the phone number and LID of
+// the sender when known.
+func addPresenceSender(ctx context.Context, client *whatsmeow.Client, sender types.JID, payload map[string]any) {
+	sender = sender.ToNonAD()
+	lid := sender
+	if sender.Server != types.HiddenUserServer {
+		lid = utils.ResolvePhoneToLID(ctx, sender, client)
+	}
+	payload["from"] = utils.ResolveLIDToPhone(ctx, sender, client).ToNonAD().String()
+	if !lid.IsEmpty() {
+		payload["from_lid"] = lid.String()
+	}
+}
+
+// createPresenceUpdatePayload creates a presence.update payload for a contact
+// going online ("available") or offline ("unavailable").
+func createPresenceUpdatePayload(ctx context.Context, evt *events.Presence, deviceID string, client *whatsmeow.Client) map[string]any {
+	payload := map[string]any{"type": "presence"}
+	addPresenceSender(ctx, client, evt.From, payload)
+	if evt.Unavailable {
+		payload["state"] = "unavailable"
+		if !evt.LastSeen.IsZero() {
+			payload["last_seen"] = evt.LastSeen.Format(time.RFC3339)
+		}
+	} else {
+		payload["state"] = "available"
+	}
+	return wrapPresenceUpdate(payload, deviceID)
+}
+
+// createChatPresenceUpdatePayload creates a presence.update payload for
+// typing ("typing"), recording a voice message ("recording") or stopping
+// ("paused") in a chat.
+func createChatPresenceUpdatePayload(ctx context.Context, evt *events.ChatPresence, deviceID string, client *whatsmeow.Client) map[string]any {
+	payload := map[string]any{"type": "chat", "is_group": evt.IsGroup}
+	addPresenceSender(ctx, client, evt.Sender, payload)
+
+	chatJID := evt.Chat.ToNonAD()
+	if chatJID.Server == types.HiddenUserServer {
+		payload["chat_lid"] = chatJID.String()
+		chatJID = utils.ResolveLIDToPhone(ctx, chatJID, client).ToNonAD()
+	}
+	payload["chat_id"] = chatJID.String()
+
+	switch {
+	case evt.State != types.ChatPresenceComposing:
+		payload["state"] = "paused"
+	case evt.Media == types.ChatPresenceMediaAudio:
+		payload["state"] = "recording"
+	default:
+		payload["state"] = "typing"
+	}
+	return wrapPresenceUpdate(payload, deviceID)
+}
+
+func wrapPresenceUpdate(payload map[string]any, deviceID string) map[string]any {
+	body := map[string]any{
+		"event":     EventTypePresenceUpdate,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"payload":   payload,
+	}
+	if deviceID != "" {
+		body["device_id"] = deviceID
+	}
+	return body
+}
+
+// presenceUpdatesEnabled reports whether presence.update events are built.
+func presenceUpdatesEnabled() bool {
+	return config.Setting(&config.WhatsappWebhookPresence) && hasWebhookTargets()
+}
+
+// forwardPresenceUpdate sends a presence.update payload in the order of the
+// chat (or contact) it belongs to.
+func forwardPresenceUpdate(ctx context.Context, deviceID string, payload map[string]any) {
+	dispatchChatWebhook(deviceID, webhookChatJID(payload), func() {
+		webhookCtx, cancel := context.WithTimeout(webhookContext(ctx), 30*time.Second)
+		defer cancel()
+		if err := forwardPayloadToConfiguredWebhooks(webhookCtx, payload, EventTypePresenceUpdate); err != nil {
+			logrus.Errorf("Failed to forward presence.update event to webhook: %v", err)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreatePresenceUpdatePayload_Offline(t *testing.T) {
+	evt := &events.Presence{
+		From:        types.NewJID("628987654321", types.DefaultUserServer),
+		Unavailable: true,
+		LastSeen:    time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC),
+	}
+
+	body := createPresenceUpdatePayload(context.Background(), evt, "628000@s.whatsapp.net", nil)
+	payload := body["payload"].(map[string]any)
+
+	if body["event"] != EventTypePresenceUpdate || body["device_id"] != "628000@s.whatsapp.net" {
+		t.Errorf("body = %+v", body)
+	}
+	if payload["type"] != "presence" || payload["state"] != "unavailable" || payload["last_seen"] != "2026-10-16T08:30:00Z" ||
+		payload["from"] != "628987654321@s.whatsapp.net" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestCreateChatPresenceUpdatePayload_States(t *testing.T) {
+	sender := types.NewJID("251556368777322", types.HiddenUserServer)
+	for want, evt := range map[string]*events.ChatPresence{
+		"typing":    {MessageSource: types.MessageSource{Chat: sender, Sender: sender}, State: types.ChatPresenceComposing},
+		"recording": {MessageSource: types.MessageSource{Chat: sender, Sender: sender}, State: types.ChatPresenceComposing, Media: types.ChatPresenceMediaAudio},
+		"paused":    {MessageSource: types.MessageSource{Chat: sender, Sender: sender}, State: types.ChatPresencePaused},
+	} {
+		payload := createChatPresenceUpdatePayload(context.Background(), evt, "", nil)["payload"].(map[string]any)
+		if payload["state"] != want || payload["type"] != "chat" {
+			t.Errorf("%s: payload = %+v", want, payload)
+		}
+		if payload["chat_lid"] != "251556368777322@lid" || payload["from_lid"] != "251556368777322@lid" {
+			t.Errorf("%s: LID fields = %v, %v", want, payload["chat_lid"], payload["from_lid"])
+		}
+	}
+}
+
+func TestPresenceUpdatesEnabled_IsOptIn(t *testing.T) {
+	prevPresence, prevWebhooks := config.WhatsappWebhookPresence, config.WhatsappWebhook
+	t.Cleanup(func() { config.WhatsappWebhookPresence, config.WhatsappWebhook = prevPresence, prevWebhooks })
+
+	config.WhatsappWebhook = []string{"https://hooks.example.com"}
+	config.WhatsappWebhookPresence = false
+	if presenceUpdatesEnabled() {
+		t.Error("presence updates should be off unless enabled")
+	}
+	config.WhatsappWebhookPresence = true
+	if !presenceUpdatesEnabled() {
+		t.Error("presence updates should be on with a webhook configured")
+	}
+}
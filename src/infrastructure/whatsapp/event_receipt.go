@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
@@ -35,6 +34,21 @@ func getReceiptTypeDescription(evt types.ReceiptType) string {
 	}
 }
 
+// receiptAckLevel maps a receipt type to the WhatsApp Web ack level: 2 when
+// delivered, 3 when read and 4 when played. Other receipts are 0.
+func receiptAckLevel(receiptType types.ReceiptType) int {
+	switch receiptType {
+	case types.ReceiptTypeDelivered, types.ReceiptTypeSender:
+		return 2
+	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
+		return 3
+	case types.ReceiptTypePlayed, types.ReceiptTypePlayedSelf:
+		return 4
+	default:
+		return 0
+	}
+}
+
 // createReceiptPayload creates a webhook payload for message acknowledgement (receipt) events
 func createReceiptPayload(ctx context.Context, evt *events.Receipt, deviceID string, client *whatsmeow.Client) map[string]any {
 	body := make(map[string]any)
@@ -45,20 +59,18 @@ func createReceiptPayload(ctx context.Context, evt *events.Receipt, deviceID str
 		payload["ids"] = evt.MessageIDs
 	}
 
-	// Add chat_id
-	payload["chat_id"] = evt.Chat.ToNonAD().String()
+	// Add chat_id in the configured webhook identifier, like message events
+	setWebhookJID(ctx, client, payload, webhookChatFields, evt.Chat, false)
+	payload["is_group"] = evt.IsGroup
 
-	// Build from/from_lid fields from sender
-	senderJID := evt.Sender
+	// Build from fields from sender, looking up the LID of a phone number sender
+	setWebhookJID(ctx, client, payload, webhookFromFields, evt.Sender, true)
 
-	if senderJID.Server == "lid" {
-		payload["from_lid"] = senderJID.ToNonAD().String()
+	// In groups the sender is the participant who received or read the message
+	if evt.IsGroup {
+		setWebhookJID(ctx, client, payload, webhookParticipantFields, evt.Sender, true)
 	}
 
-	// Resolve sender JID (convert LID to phone number if needed)
-	normalizedSenderJID := utils.ResolveLIDToPhone(ctx, senderJID, client)
-	payload["from"] = normalizedSenderJID.ToNonAD().String()
-
 	// Receipt type
 	if evt.Type == types.ReceiptTypeDelivered {
 		payload["receipt_type"] = "delivered"
@@ -66,6 +78,7 @@ func createReceiptPayload(ctx context.Context, evt *events.Receipt, deviceID str
 		payload["receipt_type"] = string(evt.Type)
 	}
 	payload["receipt_type_description"] = getReceiptTypeDescription(evt.Type)
+	payload["ack_level"] = receiptAckLevel(evt.Type)
 
 	// Wrap in body structure
 	body["event"] = "message.ack"
@@ -0,0 +1,64 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateReceiptPayload_GroupParticipant(t *testing.T) {
+	evt := &events.Receipt{
+		MessageSource: types.MessageSource{
+			Chat:    types.NewJID("120363000000000001", types.GroupServer),
+			Sender:  types.NewJID("251556368777322", types.HiddenUserServer),
+			IsGroup: true,
+		},
+		MessageIDs: []types.MessageID{"3EB00106E8BE0F407E88EC"},
+		Timestamp:  time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Type:       types.ReceiptTypePlayed,
+	}
+
+	body := createReceiptPayload(context.Background(), evt, "628000@s.whatsapp.net", nil)
+	payload := body["payload"].(map[string]any)
+
+	if body["event"] != "message.ack" || body["timestamp"] != "2026-10-16T09:00:00Z" {
+		t.Errorf("body = %+v", body)
+	}
+	for field, want := range map[string]any{
+		"chat_id":         "120363000000000001@g.us",
+		"is_group":        true,
+		"from_lid":        "251556368777322@lid",
+		"participant_lid": "251556368777322@lid",
+		"receipt_type":    "played",
+		"ack_level":       4,
+	} {
+		if payload[field] != want {
+			t.Errorf("%s = %v, want %v", field, payload[field], want)
+		}
+	}
+	if payload["participant"] != payload["from"] {
+		t.Errorf("participant = %v, want the sender %v", payload["participant"], payload["from"])
+	}
+}
+
+func TestCreateReceiptPayload_DirectChatDelivered(t *testing.T) {
+	evt := &events.Receipt{
+		MessageSource: types.MessageSource{
+			Chat:   types.NewJID("251556368777322", types.HiddenUserServer),
+			Sender: types.NewJID("251556368777322", types.HiddenUserServer),
+		},
+		MessageIDs: []types.MessageID{"3EB0A", "3EB0B"},
+		Type:       types.ReceiptTypeDelivered,
+	}
+
+	payload := createReceiptPayload(context.Background(), evt, "", nil)["payload"].(map[string]any)
+	if payload["chat_lid"] != "251556368777322@lid" || payload["receipt_type"] != "delivered" || payload["ack_level"] != 2 {
+		t.Errorf("payload = %+v", payload)
+	}
+	if _, ok := payload["participant"]; ok {
+		t.Error("direct chat receipts have no participant")
+	}
+}
@@ -0,0 +1,39 @@
+package whatsapp
+
+import (
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+)
+
+// publishToEventSubscribers offers a webhook payload to the /ws/events subscribers.
+func publishToEventSubscribers(payload map[string]any, eventName string) {
+	deviceID, _ := payload["device_id"].(string)
+	sessionID, _ := payload["session_id"].(string)
+	websocket.PublishEvent(websocket.Event{
+		Name:      eventName,
+		DeviceID:  deviceID,
+		SessionID: sessionID,
+		ChatJID:   webhookChatJID(payload),
+		Payload:   payload,
+	})
+}
+
+// publishDeviceState tells /ws/events subscribers that a device changed
+// connection state. It is not sent to webhooks.
+func publishDeviceState(instance *DeviceInstance, state domainDevice.DeviceState) {
+	if !websocket.HasEventSubscribers() {
+		return
+	}
+	payload := map[string]any{
+		"event":      "device.state",
+		"device_id":  instance.JID(),
+		"session_id": instance.ID(),
+		"payload":    map[string]any{"state": state},
+	}
+	websocket.PublishEvent(websocket.Event{
+		Name:      "device.state",
+		DeviceID:  instance.JID(),
+		SessionID: instance.ID(),
+		Payload:   payload,
+	})
+}
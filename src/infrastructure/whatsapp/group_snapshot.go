@@ -0,0 +1,243 @@
+package whatsapp
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// groupSnapshotRefreshTimeout bounds a single GetJoinedGroups round trip.
+const groupSnapshotRefreshTimeout = time.Minute
+
+// groupSnapshotRefreshing holds the IDs of devices whose joined groups are being
+// fetched, so a reconnect during a scheduled refresh does not fetch them twice.
+var groupSnapshotRefreshing sync.Map
+
+// groupParticipantChange is the role of one participant before and after a
+// membership event, using the domainChatStorage.GroupRole* values.
+type groupParticipantChange struct {
+	JID    string `json:"jid"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// groupMembershipDiff is what a GroupInfo event changed in a stored snapshot.
+type groupMembershipDiff struct {
+	CountBefore int
+	CountAfter  int
+	Changes     []groupParticipantChange
+}
+
+// forJIDs returns the changes of the given participants, in their order.
+func (d *groupMembershipDiff) forJIDs(jids []string) []groupParticipantChange {
+	changes := make([]groupParticipantChange, 0, len(jids))
+	for _, jid := range jids {
+		for _, change := range d.Changes {
+			if change.JID == jid {
+				changes = append(changes, change)
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// groupParticipantJID returns the phone-number JID of a participant when known,
+// matching the JIDs used in group webhook payloads.
+func groupParticipantJID(ctx context.Context, participant types.GroupParticipant, client *whatsmeow.Client) string {
+	if !participant.PhoneNumber.IsEmpty() {
+		return participant.PhoneNumber.ToNonAD().String()
+	}
+	return utils.ResolveLIDToPhone(ctx, participant.JID, client).ToNonAD().String()
+}
+
+func groupSnapshotFromInfo(ctx context.Context, info *types.GroupInfo, client *whatsmeow.Client) *domainChatStorage.GroupSnapshot {
+	snapshot := &domainChatStorage.GroupSnapshot{
+		GroupJID:            info.JID.ToNonAD().String(),
+		Name:                info.Name,
+		Topic:               info.Topic,
+		EphemeralExpiration: info.DisappearingTimer,
+		Announce:            info.IsAnnounce,
+		Locked:              info.IsLocked,
+		Participants:        make([]domainChatStorage.GroupParticipant, 0, len(info.Participants)),
+	}
+	switch {
+	case !info.OwnerPN.IsEmpty():
+		snapshot.OwnerJID = info.OwnerPN.ToNonAD().String()
+	case !info.OwnerJID.IsEmpty():
+		snapshot.OwnerJID = utils.ResolveLIDToPhone(ctx, info.OwnerJID, client).ToNonAD().String()
+	}
+	for _, participant := range info.Participants {
+		snapshot.Participants = append(snapshot.Participants, domainChatStorage.GroupParticipant{
+			JID:          groupParticipantJID(ctx, participant, client),
+			IsAdmin:      participant.IsAdmin || participant.IsSuperAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		})
+	}
+	return snapshot
+}
+
+// applyGroupInfoEvent updates snapshot in place with the changes carried by evt
+// and returns the resulting membership diff. Participant JIDs must already be
+// normalized the same way as the snapshot's.
+func applyGroupInfoEvent(snapshot *domainChatStorage.GroupSnapshot, evt *events.GroupInfo, join, leave, promote, demote []string) *groupMembershipDiff {
+	diff := &groupMembershipDiff{CountBefore: len(snapshot.Participants)}
+	before := make(map[string]string)
+	var order []string
+	track := func(jid string) {
+		if _, seen := before[jid]; !seen {
+			before[jid] = snapshot.ParticipantRole(jid)
+			order = append(order, jid)
+		}
+	}
+
+	for _, jid := range leave {
+		track(jid)
+		snapshot.Participants = slices.DeleteFunc(snapshot.Participants, func(p domainChatStorage.GroupParticipant) bool {
+			return p.JID == jid
+		})
+	}
+	for _, jid := range join {
+		track(jid)
+		if snapshot.ParticipantRole(jid) == domainChatStorage.GroupRoleNone {
+			snapshot.Participants = append(snapshot.Participants, domainChatStorage.GroupParticipant{JID: jid})
+		}
+	}
+	setAdmin := func(jids []string, admin bool) {
+		for _, jid := range jids {
+			track(jid)
+			for i := range snapshot.Participants {
+				if snapshot.Participants[i].JID == jid {
+					snapshot.Participants[i].IsAdmin = admin
+					if !admin {
+						snapshot.Participants[i].IsSuperAdmin = false
+					}
+				}
+			}
+		}
+	}
+	setAdmin(promote, true)
+	setAdmin(demote, false)
+
+	if evt.Name != nil {
+		snapshot.Name = evt.Name.Name
+	}
+	if evt.Topic != nil {
+		snapshot.Topic = evt.Topic.Topic
+	}
+	if evt.Locked != nil {
+		snapshot.Locked = evt.Locked.IsLocked
+	}
+	if evt.Announce != nil {
+		snapshot.Announce = evt.Announce.IsAnnounce
+	}
+	if evt.Ephemeral != nil {
+		snapshot.EphemeralExpiration = evt.Ephemeral.DisappearingTimer
+	}
+
+	diff.CountAfter = len(snapshot.Participants)
+	for _, jid := range order {
+		if after := snapshot.ParticipantRole(jid); after != before[jid] {
+			diff.Changes = append(diff.Changes, groupParticipantChange{JID: jid, Before: before[jid], After: after})
+		}
+	}
+	return diff
+}
+
+// updateGroupSnapshot applies a GroupInfo event to the stored snapshot of the
+// group. Without a stored snapshot the group is fetched instead and no diff is
+// returned, since its state before the event is unknown.
+func updateGroupSnapshot(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) *groupMembershipDiff {
+	if chatStorageRepo == nil {
+		return nil
+	}
+
+	groupJID := evt.JID.ToNonAD().String()
+	snapshot, err := chatStorageRepo.GetGroupSnapshot("", groupJID)
+	if err != nil {
+		log.Warnf("Failed to load snapshot of group %s: %v", groupJID, err)
+		return nil
+	}
+	if snapshot == nil {
+		if client == nil {
+			return nil
+		}
+		info, err := client.GetGroupInfo(ctx, evt.JID)
+		if err != nil {
+			log.Debugf("Failed to fetch group %s for its snapshot: %v", groupJID, err)
+			return nil
+		}
+		if err := chatStorageRepo.StoreGroupSnapshot(groupSnapshotFromInfo(ctx, info, client)); err != nil {
+			log.Warnf("Failed to store snapshot of group %s: %v", groupJID, err)
+		}
+		return nil
+	}
+
+	diff := applyGroupInfoEvent(snapshot, evt,
+		jidsToStrings(ctx, evt.Join, client), jidsToStrings(ctx, evt.Leave, client),
+		jidsToStrings(ctx, evt.Promote, client), jidsToStrings(ctx, evt.Demote, client))
+	if err := chatStorageRepo.StoreGroupSnapshot(snapshot); err != nil {
+		log.Warnf("Failed to store snapshot of group %s: %v", groupJID, err)
+	}
+	return diff
+}
+
+// refreshGroupSnapshots stores a fresh snapshot of every group the device is in.
+func refreshGroupSnapshots(ctx context.Context, instance *DeviceInstance) {
+	if instance == nil {
+		return
+	}
+	client := instance.GetClient()
+	repo := instance.GetChatStorage()
+	if client == nil || repo == nil || !client.IsLoggedIn() {
+		return
+	}
+	if _, running := groupSnapshotRefreshing.LoadOrStore(instance.ID(), struct{}{}); running {
+		return
+	}
+	defer groupSnapshotRefreshing.Delete(instance.ID())
+
+	ctx, cancel := context.WithTimeout(ctx, groupSnapshotRefreshTimeout)
+	defer cancel()
+	groups, err := client.GetJoinedGroups(ctx)
+	if err != nil {
+		logrus.WithError(err).WithField("device_id", instance.ID()).Warn("Failed to fetch joined groups for snapshots")
+		return
+	}
+	for _, info := range groups {
+		if err := repo.StoreGroupSnapshot(groupSnapshotFromInfo(ctx, info, client)); err != nil {
+			logrus.WithError(err).WithField("group_jid", info.JID.String()).Warn("Failed to store group snapshot")
+		}
+	}
+	logrus.WithField("device_id", instance.ID()).Debugf("Refreshed %d group snapshots", len(groups))
+}
+
+// StartGroupSnapshotRefresher refreshes the group snapshots of every logged-in
+// device each interval, catching membership changes missed while offline.
+func StartGroupSnapshotRefresher(ctx context.Context, manager *DeviceManager, interval time.Duration) {
+	if manager == nil || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for _, instance := range manager.ListDevices() {
+					refreshGroupSnapshots(ctx, instance)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
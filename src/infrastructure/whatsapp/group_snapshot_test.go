@@ -0,0 +1,56 @@
+package whatsapp
+
+import (
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestApplyGroupInfoEvent_DiffsMembershipAndUpdatesMetadata(t *testing.T) {
+	snapshot := &domainChatStorage.GroupSnapshot{
+		Name: "Team",
+		Participants: []domainChatStorage.GroupParticipant{
+			{JID: "628111@s.whatsapp.net", IsAdmin: true, IsSuperAdmin: true},
+			{JID: "628222@s.whatsapp.net"},
+			{JID: "628333@s.whatsapp.net", IsAdmin: true},
+		},
+	}
+	evt := &events.GroupInfo{
+		Name:      &types.GroupName{Name: "Team v2"},
+		Ephemeral: &types.GroupEphemeral{IsEphemeral: true, DisappearingTimer: 604800},
+	}
+
+	diff := applyGroupInfoEvent(snapshot, evt,
+		[]string{"628444@s.whatsapp.net", "628222@s.whatsapp.net"}, // joining an existing member is a no-op
+		[]string{"628333@s.whatsapp.net"},
+		[]string{"628222@s.whatsapp.net"},
+		nil,
+	)
+
+	if diff.CountBefore != 3 || diff.CountAfter != 3 {
+		t.Errorf("counts = %d -> %d, want 3 -> 3", diff.CountBefore, diff.CountAfter)
+	}
+	want := map[string]groupParticipantChange{
+		"628333@s.whatsapp.net": {JID: "628333@s.whatsapp.net", Before: domainChatStorage.GroupRoleAdmin, After: domainChatStorage.GroupRoleNone},
+		"628444@s.whatsapp.net": {JID: "628444@s.whatsapp.net", Before: domainChatStorage.GroupRoleNone, After: domainChatStorage.GroupRoleMember},
+		"628222@s.whatsapp.net": {JID: "628222@s.whatsapp.net", Before: domainChatStorage.GroupRoleMember, After: domainChatStorage.GroupRoleAdmin},
+	}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("got %d changes %+v, want %d", len(diff.Changes), diff.Changes, len(want))
+	}
+	for _, change := range diff.Changes {
+		if change != want[change.JID] {
+			t.Errorf("change for %s = %+v, want %+v", change.JID, change, want[change.JID])
+		}
+	}
+	if snapshot.Name != "Team v2" || snapshot.EphemeralExpiration != 604800 {
+		t.Errorf("metadata not applied: name %q, ephemeral %d", snapshot.Name, snapshot.EphemeralExpiration)
+	}
+
+	promoted := diff.forJIDs([]string{"628222@s.whatsapp.net"})
+	if len(promoted) != 1 || promoted[0].After != domainChatStorage.GroupRoleAdmin {
+		t.Errorf("forJIDs = %+v, want the promotion only", promoted)
+	}
+}
@@ -77,8 +77,8 @@ func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorage
 
 	// Debounce webhook notification — wait for all sync events to complete.
 	// Only schedule when webhooks are configured to avoid wasted timers.
-	if len(config.WhatsappWebhook) > 0 {
-		scheduleHistorySyncWebhook(chatStorageRepo, client, evt.Data.GetSyncType().String())
+	if hasWebhookTargets() {
+		scheduleHistorySyncWebhook(ctx, chatStorageRepo, client, evt.Data.GetSyncType().String())
 	}
 }
 
@@ -89,7 +89,7 @@ func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorage
 //  2. deduplicateLIDChats         — collapse @lid chats into their phone counterparts
 //  3. forwardHistorySyncCompleteToWebhook — dispatch the fork-specific event
 //  4. clearPushNameCache          — bound memory across sync cycles
-func scheduleHistorySyncWebhook(chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client, syncType string) {
+func scheduleHistorySyncWebhook(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client, syncType string) {
 	historySyncDebounceMu.Lock()
 	defer historySyncDebounceMu.Unlock()
 
@@ -108,7 +108,7 @@ func scheduleHistorySyncWebhook(chatStorageRepo domainChatStorage.IChatStorageRe
 			deduplicateLIDChats(context.Background(), chatStorageRepo, client, deviceJID)
 		}
 
-		forwardHistorySyncCompleteToWebhook(context.Background(), client, syncType)
+		forwardHistorySyncCompleteToWebhook(webhookContext(ctx), client, syncType)
 
 		clearPushNameCache()
 	})
@@ -296,21 +296,43 @@ func processConversationMessages(ctx context.Context, data *waHistorySync.Histor
 				latestTimestamp = timestamp
 			}
 
+			quotedMessageID, quotedParticipant := utils.ExtractQuotedReference(msg.GetMessage())
+			if parsedParticipant, err := types.ParseJID(quotedParticipant); err == nil && quotedParticipant != "" {
+				quotedParticipant = NormalizeJIDFromLIDWithContext(parsedParticipant, client).ToNonAD().String()
+			}
+
 			message := &domainChatStorage.Message{
-				ID:            messageID,
-				ChatJID:       chatJID,
-				DeviceID:      deviceID,
-				Sender:        sender,
-				Content:       content,
-				Timestamp:     timestamp,
-				IsFromMe:      isFromMe,
-				MediaType:     mediaType,
-				Filename:      filename,
-				URL:           url,
-				MediaKey:      mediaKey,
-				FileSHA256:    fileSHA256,
-				FileEncSHA256: fileEncSHA256,
-				FileLength:    fileLength,
+				ID:                messageID,
+				ChatJID:           chatJID,
+				DeviceID:          deviceID,
+				Sender:            sender,
+				Content:           content,
+				Timestamp:         timestamp,
+				IsFromMe:          isFromMe,
+				MediaType:         mediaType,
+				Filename:          filename,
+				URL:               url,
+				MediaKey:          mediaKey,
+				FileSHA256:        fileSHA256,
+				FileEncSHA256:     fileEncSHA256,
+				FileLength:        fileLength,
+				QuotedMessageID:   quotedMessageID,
+				QuotedParticipant: quotedParticipant,
+			}
+
+			if poll := utils.ExtractPollCreation(msg.GetMessage()); poll != nil {
+				if err := chatStorageRepo.StorePoll(&domainChatStorage.Poll{
+					MessageID:       messageID,
+					ChatJID:         chatJID,
+					DeviceID:        deviceID,
+					CreatorJID:      sender,
+					Question:        poll.GetName(),
+					Options:         utils.PollOptionNames(poll),
+					SelectableCount: poll.GetSelectableOptionsCount(),
+					CreatedAt:       timestamp,
+				}); err != nil {
+					log.Warnf("Failed to store history poll %s for chat %s: %v", messageID, chatJID, err)
+				}
 			}
 
 			messageBatch = append(messageBatch, message)
@@ -329,6 +351,9 @@ func processConversationMessages(ctx context.Context, data *waHistorySync.Histor
 				log.Warnf("Failed to store chat %s: %v", chatJID, err)
 				continue
 			}
+			if err := chatStorageRepo.UpdateChatState(deviceID, chatJID, historySyncChatState(conv)); err != nil {
+				log.Warnf("Failed to store chat state for %s: %v", chatJID, err)
+			}
 
 			if err := chatStorageRepo.StoreMessagesBatch(messageBatch); err != nil {
 				log.Warnf("Failed to store messages batch for chat %s: %v", chatJID, err)
@@ -341,6 +366,29 @@ func processConversationMessages(ctx context.Context, data *waHistorySync.Histor
 	return nil
 }
 
+// historySyncChatState maps the pin/mute/unread flags a history sync conversation
+// carries onto a chat state update. Flags absent from the conversation are left
+// untouched. MuteEndTime is in seconds.
+func historySyncChatState(conv *waHistorySync.Conversation) domainChatStorage.ChatStateUpdate {
+	var update domainChatStorage.ChatStateUpdate
+	if conv.Pinned != nil {
+		pinned := conv.GetPinned() > 0
+		update.Pinned = &pinned
+	}
+	if conv.MuteEndTime != nil {
+		var mutedUntil time.Time
+		if end := conv.GetMuteEndTime(); end > 0 {
+			mutedUntil = time.Unix(int64(end), 0).UTC()
+		}
+		update.MutedUntil = &mutedUntil
+	}
+	if conv.UnreadCount != nil {
+		unread := int(conv.GetUnreadCount())
+		update.UnreadCount = &unread
+	}
+	return update
+}
+
 // processOnDemandHistorySync processes ON_DEMAND history sync responses (triggered when we
 // request history for a specific chat, e.g. after unavailable messages). ON_DEMAND messages
 // are forwarded individually to webhooks since they represent "new" messages not received in
@@ -354,7 +402,7 @@ func processOnDemandHistorySync(ctx context.Context, data *waHistorySync.History
 		log.Errorf("[ON_DEMAND] Failed to store messages: %v", err)
 	}
 
-	if len(config.WhatsappWebhook) > 0 {
+	if hasWebhookTargets() {
 		deviceID := ""
 		if client != nil && client.Store != nil && client.Store.ID != nil {
 			deviceJID := NormalizeJIDFromLIDWithContext(client.Store.ID.ToNonAD(), client)
@@ -19,6 +19,7 @@ const (
 	BusinessProfileTTL = 60 * time.Second
 	GroupInfoTTL       = 30 * time.Second
 	GroupLinkInfoTTL   = 60 * time.Second
+	NewsletterInfoTTL  = 5 * time.Minute
 )
 
 // InfoCache provides device-scoped caching for WhatsApp info requests
@@ -28,6 +29,8 @@ type InfoCache struct {
 	businessProfile *cache.Cache
 	groupInfo       *cache.Cache
 	groupLinkInfo   *cache.Cache
+	newsletterInfo  *cache.Cache
+	httpResponse    *cache.Cache
 }
 
 // deviceCaches holds per-device cache instances
@@ -61,6 +64,8 @@ func GetInfoCache(deviceID string) *InfoCache {
 		businessProfile: cache.New(BusinessProfileTTL),
 		groupInfo:       cache.New(GroupInfoTTL),
 		groupLinkInfo:   cache.New(GroupLinkInfoTTL),
+		newsletterInfo:  cache.New(NewsletterInfoTTL),
+		httpResponse:    cache.New(UserInfoTTL),
 	}
 	deviceCaches[deviceID] = ic
 
@@ -212,6 +217,41 @@ func (ic *InfoCache) SetGroupInfoError(groupJID string, errorMsg string) {
 	logrus.Debugf("Cache SET (error) for group info: %s - %s", groupJID, errorMsg)
 }
 
+// NewsletterInfo cache methods
+
+// NewsletterInfoResult holds cached newsletter metadata
+type NewsletterInfoResult struct {
+	Data     *types.NewsletterMetadata
+	ErrorMsg string
+}
+
+// GetNewsletterInfo retrieves cached newsletter metadata
+func (ic *InfoCache) GetNewsletterInfo(newsletterJID string) (*NewsletterInfoResult, bool) {
+	key := fmt.Sprintf("newsletterinfo:%s", newsletterJID)
+	if val, ok := ic.newsletterInfo.Get(key); ok {
+		if result, ok := val.(*NewsletterInfoResult); ok {
+			logrus.Debugf("Cache HIT for newsletter info: %s", newsletterJID)
+			return result, true
+		}
+	}
+	logrus.Debugf("Cache MISS for newsletter info: %s", newsletterJID)
+	return nil, false
+}
+
+// SetNewsletterInfo stores newsletter metadata in cache
+func (ic *InfoCache) SetNewsletterInfo(newsletterJID string, data *types.NewsletterMetadata) {
+	key := fmt.Sprintf("newsletterinfo:%s", newsletterJID)
+	ic.newsletterInfo.Set(key, &NewsletterInfoResult{Data: data})
+	logrus.Debugf("Cache SET for newsletter info: %s", newsletterJID)
+}
+
+// SetNewsletterInfoError stores a newsletter info error in cache to prevent repeated lookups
+func (ic *InfoCache) SetNewsletterInfoError(newsletterJID string, errorMsg string) {
+	key := fmt.Sprintf("newsletterinfo:%s", newsletterJID)
+	ic.newsletterInfo.Set(key, &NewsletterInfoResult{ErrorMsg: errorMsg})
+	logrus.Debugf("Cache SET (error) for newsletter info: %s - %s", newsletterJID, errorMsg)
+}
+
 // GroupLinkInfo cache methods
 
 // GroupLinkInfoResult holds cached group link info data
@@ -250,3 +290,32 @@ func (ic *InfoCache) SetGroupLinkInfo(link string, result *GroupLinkInfoResult)
 	ic.groupLinkInfo.Set(key, result)
 	logrus.Debugf("Cache SET for group link info")
 }
+
+// HTTP response cache methods
+
+// HTTPResponseResult holds a rendered REST response so repeated polls can be
+// answered without touching the handler (and therefore WhatsApp) again.
+type HTTPResponseResult struct {
+	Body        []byte
+	ContentType string
+	ETag        string
+}
+
+// GetHTTPResponse retrieves a cached REST response
+func (ic *InfoCache) GetHTTPResponse(key string) (*HTTPResponseResult, bool) {
+	if val, ok := ic.httpResponse.Get("http:" + key); ok {
+		if result, ok := val.(*HTTPResponseResult); ok {
+			logrus.Debugf("Cache HIT for http response: %s", key)
+			return result, true
+		}
+	}
+	logrus.Debugf("Cache MISS for http response: %s", key)
+	return nil, false
+}
+
+// SetHTTPResponse stores a REST response for the given TTL, which should match
+// the TTL of the info cache backing the endpoint.
+func (ic *InfoCache) SetHTTPResponse(key string, result *HTTPResponseResult, ttl time.Duration) {
+	ic.httpResponse.SetWithTTL("http:"+key, result, ttl)
+	logrus.Debugf("Cache SET for http response: %s", key)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
 )
@@ -17,13 +18,7 @@ import (
 // upstream's utils.ResolveLIDToPhone (byte-identical functionality).
 func NormalizeJIDFromLIDWithContext(jid types.JID, client *whatsmeow.Client) types.JID {
 	// Only process @lid JIDs
-	if jid.Server != "lid" {
-		return jid
-	}
-
-	// Safety check
-	if client == nil || client.Store == nil || client.Store.LIDs == nil {
-		log.Warnf("Cannot resolve LID %s: client not available", jid.String())
+	if jid.Server != types.HiddenUserServer {
 		return jid
 	}
 
@@ -31,20 +26,7 @@ func NormalizeJIDFromLIDWithContext(jid types.JID, client *whatsmeow.Client) typ
 	// This prevents "context canceled" errors from short-lived event contexts
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	ctx = utils.ContextWithLIDResolver(ctx, lidResolverForClient(client))
 
-	// Attempt to get the phone number for this LID
-	pn, err := client.Store.LIDs.GetPNForLID(ctx, jid)
-	if err != nil {
-		log.Debugf("Failed to resolve LID %s to phone number: %v", jid.String(), err)
-		return jid
-	}
-
-	// If we got a valid phone number, use it
-	if !pn.IsEmpty() {
-		log.Debugf("Resolved LID %s to phone number %s", jid.String(), pn.String())
-		return pn
-	}
-
-	// Fallback to original JID
-	return jid
+	return utils.ResolveLIDToPhone(ctx, jid, client)
 }
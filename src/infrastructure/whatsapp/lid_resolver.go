@@ -0,0 +1,118 @@
+package whatsapp
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+// lidRetryTimeout bounds the retries of one device's failed LID lookups.
+const lidRetryTimeout = time.Minute
+
+// DeviceLIDLookups are the pending failed LID lookups of a device.
+type DeviceLIDLookups struct {
+	DeviceID      string                  `json:"device_id"`
+	FailedLookups []utils.FailedLIDLookup `json:"failed_lookups"`
+}
+
+// lidMappingStore persists the resolver's LID mappings in chat storage.
+type lidMappingStore struct {
+	repo domainChatStorage.IChatStorageRepository
+}
+
+// NewLIDMappingStore returns a utils.LIDMappingStore backed by repo, or nil
+// when repo is nil.
+func NewLIDMappingStore(repo domainChatStorage.IChatStorageRepository) utils.LIDMappingStore {
+	if repo == nil {
+		return nil
+	}
+	return &lidMappingStore{repo: repo}
+}
+
+// GetFailedLIDLookups returns the pending failed LID lookups of every device
+// that has any.
+func GetFailedLIDLookups() []DeviceLIDLookups {
+	dm := GetDeviceManager()
+	if dm == nil {
+		return []DeviceLIDLookups{}
+	}
+	result := []DeviceLIDLookups{}
+	for _, inst := range dm.ListDevices() {
+		if lookups := inst.LIDResolver().FailedLookups(); len(lookups) > 0 {
+			result = append(result, DeviceLIDLookups{DeviceID: inst.ID(), FailedLookups: lookups})
+		}
+	}
+	return result
+}
+
+// StartLIDResolverMaintenance drops the expired failed LID lookups of every
+// device and retries the pending ones every interval, plus a random jitter of
+// up to a fifth of it so instances sharing an account do not query WhatsApp in
+// step. It stops when ctx is done.
+func StartLIDResolverMaintenance(ctx context.Context, manager *DeviceManager, interval time.Duration) {
+	if manager == nil || interval <= 0 {
+		return
+	}
+	go func() {
+		for {
+			timer := time.NewTimer(interval + rand.N(interval/5+1))
+			select {
+			case <-timer.C:
+				for _, instance := range manager.ListDevices() {
+					retryFailedLIDLookups(ctx, instance)
+				}
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// retryFailedLIDLookups retries the pending failed LID lookups of a device.
+func retryFailedLIDLookups(ctx context.Context, instance *DeviceInstance) {
+	if instance == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, lidRetryTimeout)
+	defer cancel()
+	if resolved := instance.LIDResolver().RetryFailedLookups(ctx, instance.GetClient()); resolved > 0 {
+		logrus.Debugf("Resolved %d failed LID lookups of device %s on retry", resolved, instance.ID())
+	}
+}
+
+// lidResolverForClient returns the resolver of the device owning client, or
+// the resolver of contexts without a device when no registered device does.
+func lidResolverForClient(client *whatsmeow.Client) *utils.LIDResolver {
+	if dm := GetDeviceManager(); dm != nil && client != nil && client.Store != nil && client.Store.ID != nil {
+		if inst, ok := dm.getDeviceByJID(client.Store.ID.ToNonAD().String()); ok {
+			return inst.LIDResolver()
+		}
+	}
+	return utils.LIDResolverFromContext(context.Background())
+}
+
+func (s *lidMappingStore) StoreLIDMapping(lid, phoneJID string) error {
+	return s.repo.StoreLIDMapping(lid, phoneJID)
+}
+
+func (s *lidMappingStore) LookupPhoneForLID(lid string) (string, error) {
+	mapping, err := s.repo.GetLIDMapping(lid)
+	if err != nil || mapping == nil {
+		return "", err
+	}
+	return mapping.PhoneJID, nil
+}
+
+func (s *lidMappingStore) LookupLIDForPhone(phoneJID string) (string, error) {
+	mapping, err := s.repo.GetLIDMappingByPhone(phoneJID)
+	if err != nil || mapping == nil {
+		return "", err
+	}
+	return mapping.LID, nil
+}
@@ -0,0 +1,23 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextWithDeviceCarriesDeviceLIDResolver(t *testing.T) {
+	deviceA := NewDeviceInstance("device-a", nil, nil)
+	deviceB := NewDeviceInstance("device-b", nil, nil)
+	assert.NotSame(t, deviceA.LIDResolver(), deviceB.LIDResolver())
+
+	ctx := ContextWithDevice(context.Background(), deviceA)
+	assert.Same(t, deviceA.LIDResolver(), utils.LIDResolverFromContext(ctx))
+	assert.Same(t, deviceB.LIDResolver(), utils.LIDResolverFromContext(ContextWithDevice(ctx, deviceB)))
+
+	// Contexts without a device share the fallback resolver
+	assert.Same(t, utils.LIDResolverFromContext(context.Background()), utils.LIDResolverFromContext(ContextWithDevice(ctx, nil)))
+	assert.NotSame(t, deviceA.LIDResolver(), utils.LIDResolverFromContext(context.Background()))
+}
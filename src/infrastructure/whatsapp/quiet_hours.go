@@ -0,0 +1,162 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+// QuietHours is a daily window, in a fixed time zone, during which bulk send
+// campaigns are held. Windows may cross midnight ("22:00-08:00").
+type QuietHours struct {
+	start    time.Duration // offset from local midnight
+	end      time.Duration
+	location *time.Location
+}
+
+var compiledQuietHours struct {
+	sync.Mutex
+	spec, timezone string
+	window         *QuietHours
+}
+
+// ParseQuietHours parses a "HH:MM-HH:MM" window evaluated in the IANA time
+// zone tz (the process time zone when empty).
+func ParseQuietHours(spec, tz string) (*QuietHours, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", spec)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid quiet hours %q: start and end must differ", spec)
+	}
+
+	location := time.Local
+	if tz = strings.TrimSpace(tz); tz != "" {
+		if location, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid quiet hours time zone %q: %w", tz, err)
+		}
+	}
+	return &QuietHours{start: start, end: end, location: location}, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", strings.TrimSpace(value))
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls inside the window.
+func (q *QuietHours) Contains(t time.Time) bool {
+	offset := q.offset(t)
+	if q.start < q.end {
+		return offset >= q.start && offset < q.end
+	}
+	return offset >= q.start || offset < q.end
+}
+
+// NextOpen returns when sends may resume: t itself outside the window,
+// otherwise the end of the window that contains t.
+func (q *QuietHours) NextOpen(t time.Time) time.Time {
+	if !q.Contains(t) {
+		return t
+	}
+	local := t.In(q.location)
+	y, m, d := local.Date()
+	opens := time.Date(y, m, d, int(q.end/time.Hour), int(q.end%time.Hour/time.Minute), 0, 0, q.location)
+	if !opens.After(local) {
+		opens = time.Date(y, m, d+1, int(q.end/time.Hour), int(q.end%time.Hour/time.Minute), 0, 0, q.location)
+	}
+	return opens
+}
+
+func (q *QuietHours) offset(t time.Time) time.Duration {
+	local := t.In(q.location)
+	return time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+}
+
+// ConfiguredQuietHours returns the window from config, or nil when quiet hours
+// are disabled.
+func ConfiguredQuietHours() (*QuietHours, error) {
+	config.SettingsMu.RLock()
+	spec, timezone := config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone
+	config.SettingsMu.RUnlock()
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	compiledQuietHours.Lock()
+	defer compiledQuietHours.Unlock()
+
+	if compiledQuietHours.window != nil &&
+		compiledQuietHours.spec == spec &&
+		compiledQuietHours.timezone == timezone {
+		return compiledQuietHours.window, nil
+	}
+	window, err := ParseQuietHours(spec, timezone)
+	if err != nil {
+		return nil, err
+	}
+	compiledQuietHours.spec = spec
+	compiledQuietHours.timezone = timezone
+	compiledQuietHours.window = window
+	return window, nil
+}
+
+// WaitForSendWindow blocks bulk senders, such as campaigns, until quiet hours
+// are over. It returns immediately when quiet hours are disabled or not active.
+func WaitForSendWindow(ctx context.Context) error {
+	window, err := ConfiguredQuietHours()
+	if err != nil || window == nil {
+		return err
+	}
+	now := time.Now()
+	opens := window.NextOpen(now)
+	if !opens.After(now) {
+		return nil
+	}
+
+	logrus.Infof("Quiet hours: holding send until %s", opens.Format(time.RFC3339))
+	timer := time.NewTimer(opens.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// quietHoursReply returns the configured business-hours notice when now is
+// inside quiet hours, with {opens_at} replaced by the local reopening time.
+func quietHoursReply(now time.Time) (string, bool) {
+	if config.Setting(&config.WhatsappQuietHoursReply) == "" {
+		return "", false
+	}
+	window, err := ConfiguredQuietHours()
+	if err != nil {
+		log.Errorf("Quiet hours: %v", err)
+		return "", false
+	}
+	if window == nil || !window.Contains(now) {
+		return "", false
+	}
+	opens := window.NextOpen(now).In(window.location)
+	return strings.ReplaceAll(config.Setting(&config.WhatsappQuietHoursReply), "{opens_at}", opens.Format("15:04 MST")), true
+}
@@ -0,0 +1,94 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestParseQuietHours_RejectsMalformedWindows(t *testing.T) {
+	for _, spec := range []string{"22:00", "25:00-08:00", "22:00-8am", "08:00-08:00"} {
+		if _, err := ParseQuietHours(spec, "UTC"); err == nil {
+			t.Errorf("ParseQuietHours(%q) = nil error, want error", spec)
+		}
+	}
+	if _, err := ParseQuietHours("22:00-08:00", "Mars/Olympus"); err == nil {
+		t.Error("expected an unknown time zone to be rejected")
+	}
+}
+
+func TestQuietHours_WindowCrossingMidnight(t *testing.T) {
+	window, err := ParseQuietHours("22:00-08:00", "Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+
+	cases := []struct {
+		at       time.Time
+		inside   bool
+		opensDay int
+	}{
+		{time.Date(2026, time.March, 10, 21, 59, 0, 0, jakarta), false, 10},
+		{time.Date(2026, time.March, 10, 22, 0, 0, 0, jakarta), true, 11},
+		{time.Date(2026, time.March, 11, 3, 30, 0, 0, jakarta), true, 11},
+		{time.Date(2026, time.March, 11, 8, 0, 0, 0, jakarta), false, 11},
+	}
+	for _, tc := range cases {
+		// Evaluated from UTC to make sure the window's own zone is used.
+		at := tc.at.UTC()
+		if got := window.Contains(at); got != tc.inside {
+			t.Errorf("Contains(%s) = %v, want %v", tc.at, got, tc.inside)
+		}
+		opens := window.NextOpen(at).In(jakarta)
+		if !tc.inside {
+			if !opens.Equal(at) {
+				t.Errorf("NextOpen(%s) = %s, want the same instant outside quiet hours", tc.at, opens)
+			}
+			continue
+		}
+		if opens.Day() != tc.opensDay || opens.Hour() != 8 || opens.Minute() != 0 {
+			t.Errorf("NextOpen(%s) = %s, want 08:00 on day %d", tc.at, opens, tc.opensDay)
+		}
+	}
+}
+
+func TestQuietHoursReply_OnlyInsideWindow(t *testing.T) {
+	prevHours, prevTZ, prevReply := config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone, config.WhatsappQuietHoursReply
+	config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone = "09:00-17:00", "UTC"
+	config.WhatsappQuietHoursReply = "Closed, back at {opens_at}"
+	t.Cleanup(func() {
+		config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone, config.WhatsappQuietHoursReply = prevHours, prevTZ, prevReply
+	})
+
+	reply, ok := quietHoursReply(time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC))
+	if !ok || reply != "Closed, back at 17:00 UTC" {
+		t.Errorf("got %q (%v), want the notice with the reopening time", reply, ok)
+	}
+	if _, ok := quietHoursReply(time.Date(2026, time.March, 10, 18, 0, 0, 0, time.UTC)); ok {
+		t.Error("expected no notice outside quiet hours")
+	}
+}
+
+func TestWaitForSendWindow_HoldsSendsInsideWindow(t *testing.T) {
+	prevHours, prevTZ := config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone
+	t.Cleanup(func() { config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone = prevHours, prevTZ })
+	now := time.Now().UTC()
+	config.WhatsappQuietHoursTimezone = "UTC"
+
+	config.WhatsappQuietHours = now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := WaitForSendWindow(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("inside quiet hours: err = %v, want the send held until the context ends", err)
+	}
+
+	config.WhatsappQuietHours = now.Add(time.Hour).Format("15:04") + "-" + now.Add(2*time.Hour).Format("15:04")
+	start := time.Now()
+	if err := WaitForSendWindow(context.Background()); err != nil || time.Since(start) > time.Second {
+		t.Errorf("outside quiet hours: err = %v after %s, want an immediate send", err, time.Since(start))
+	}
+}
@@ -0,0 +1,316 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Send policy actions, used both by embedded rules and by the policy callback response.
+const (
+	SendPolicyAllow  = "allow"
+	SendPolicyModify = "modify"
+	SendPolicyReject = "reject"
+
+	sendPolicyRuleBlock = "block"
+	sendPolicyRuleStrip = "strip"
+)
+
+const sendPolicyHookTimeout = 5 * time.Second
+
+// sendPolicyClientKey is the webhook TLS setting a policy client was built for.
+type sendPolicyClientKey struct {
+	caCert, clientCert, clientKey string
+	insecureSkipVerify            bool
+}
+
+var (
+	sendPolicyClientsMu sync.Mutex
+	// sendPolicyClients holds one client per webhook TLS setting, so policy
+	// callbacks reuse their connections instead of opening new ones per send.
+	sendPolicyClients = map[sendPolicyClientKey]*http.Client{}
+)
+
+// sendPolicyHTTPClient returns the shared client for the current webhook TLS
+// setting, creating it on first use.
+func sendPolicyHTTPClient() (*http.Client, error) {
+	key := sendPolicyClientKey{
+		caCert:             config.WhatsappWebhookCACert,
+		clientCert:         config.WhatsappWebhookClientCert,
+		clientKey:          config.WhatsappWebhookClientKey,
+		insecureSkipVerify: config.Setting(&config.WhatsappWebhookInsecureSkipVerify),
+	}
+
+	sendPolicyClientsMu.Lock()
+	defer sendPolicyClientsMu.Unlock()
+	if client, ok := sendPolicyClients[key]; ok {
+		return client, nil
+	}
+	tlsConfig, err := webhookTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{Timeout: sendPolicyHookTimeout, Transport: transport}
+	sendPolicyClients[key] = client
+	return client, nil
+}
+
+// SendPolicyRequest is the JSON body POSTed to the policy callback for every outgoing text.
+type SendPolicyRequest struct {
+	DeviceID  string `json:"device_id"`
+	Recipient string `json:"recipient"`
+	Text      string `json:"text"`
+}
+
+// SendPolicyDecision is what the policy callback answers with. Text is only
+// read when Action is "modify".
+type SendPolicyDecision struct {
+	Action string `json:"action"`
+	Text   string `json:"text,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type sendPolicyRule struct {
+	action  string
+	pattern *regexp.Regexp
+	raw     string
+}
+
+// compiledSendPolicyRules keeps the last compiled rule set so the regexes are
+// not rebuilt on every send; it is recompiled whenever the config slice changes.
+var compiledSendPolicyRules struct {
+	sync.Mutex
+	source []string
+	rules  []sendPolicyRule
+}
+
+// parseSendPolicyRules compiles "<action>:<regex>" rules where action is
+// "block" (reject the message on match) or "strip" (remove every match).
+func parseSendPolicyRules(raw []string) ([]sendPolicyRule, error) {
+	rules := make([]sendPolicyRule, 0, len(raw))
+	for _, entry := range raw {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		action, expr, found := strings.Cut(entry, ":")
+		action = strings.ToLower(strings.TrimSpace(action))
+		if !found || expr == "" || (action != sendPolicyRuleBlock && action != sendPolicyRuleStrip) {
+			return nil, fmt.Errorf("invalid send policy rule %q: expected block:<regex> or strip:<regex>", entry)
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid send policy rule %q: %w", entry, err)
+		}
+		rules = append(rules, sendPolicyRule{action: action, pattern: pattern, raw: entry})
+	}
+	return rules, nil
+}
+
+// ValidateSendPolicyRules reports the first malformed rule in raw, if any.
+func ValidateSendPolicyRules(raw []string) error {
+	_, err := parseSendPolicyRules(raw)
+	return err
+}
+
+func configuredSendPolicyRules() ([]sendPolicyRule, error) {
+	compiledSendPolicyRules.Lock()
+	defer compiledSendPolicyRules.Unlock()
+
+	source := config.Setting(&config.WhatsappSendPolicyRules)
+	if compiledSendPolicyRules.rules != nil && slices.Equal(compiledSendPolicyRules.source, source) {
+		return compiledSendPolicyRules.rules, nil
+	}
+	rules, err := parseSendPolicyRules(source)
+	if err != nil {
+		return nil, err
+	}
+	compiledSendPolicyRules.source = slices.Clone(source)
+	compiledSendPolicyRules.rules = rules
+	return rules, nil
+}
+
+// ApplySendPolicy runs the configured content policy against the text of an
+// outgoing message. Embedded rules run first, then the policy callback (if
+// configured) sees the already-filtered text. Rewrites are applied to msg in
+// place and the returned content mirrors them so storage matches what was sent.
+// Messages without text (stickers, reactions, ...) are not inspected.
+func ApplySendPolicy(ctx context.Context, recipient types.JID, msg *waE2E.Message, content string) (string, error) {
+	hookURL := config.Setting(&config.WhatsappSendPolicyURL)
+	if len(config.Setting(&config.WhatsappSendPolicyRules)) == 0 && hookURL == "" {
+		return content, nil
+	}
+
+	text, setText, ok := sendPolicyText(msg)
+	if !ok || text == "" {
+		return content, nil
+	}
+
+	deviceID := ""
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		deviceID = inst.ID()
+	}
+	logFields := logrus.Fields{
+		"device_id": deviceID,
+		"recipient": recipient.String(),
+	}
+
+	rules, err := configuredSendPolicyRules()
+	if err != nil {
+		logrus.WithFields(logFields).WithError(err).Error("Send policy rules are invalid, rejecting message")
+		return content, pkgError.InternalServerError(err.Error())
+	}
+
+	decision := evaluateSendPolicyRules(rules, text)
+	if decision.Action != SendPolicyReject && hookURL != "" {
+		current := text
+		if decision.Action == SendPolicyModify {
+			current = decision.Text
+		}
+		hookDecision, err := callSendPolicyHook(ctx, hookURL, SendPolicyRequest{
+			DeviceID:  deviceID,
+			Recipient: recipient.String(),
+			Text:      current,
+		})
+		if err != nil {
+			logrus.WithFields(logFields).WithError(err).Error("Send policy callback failed, rejecting message")
+			return content, pkgError.SendPolicyError("message rejected: send policy callback unavailable")
+		}
+		if hookDecision.Action == SendPolicyAllow && decision.Action == SendPolicyModify {
+			hookDecision = decision
+		}
+		decision = hookDecision
+	}
+
+	logFields["action"] = decision.Action
+	logFields["reason"] = decision.Reason
+	switch decision.Action {
+	case SendPolicyReject:
+		logrus.WithFields(logFields).Warn("Send policy rejected outgoing message")
+		reason := decision.Reason
+		if reason == "" {
+			reason = "content not allowed"
+		}
+		return content, pkgError.SendPolicyError("message rejected by send policy: " + reason)
+	case SendPolicyModify:
+		textOnly := msg.Conversation != nil || msg.ExtendedTextMessage != nil
+		if textOnly && strings.TrimSpace(decision.Text) == "" {
+			logrus.WithFields(logFields).Warn("Send policy left outgoing message empty")
+			return content, pkgError.SendPolicyError("message rejected by send policy: message is empty after filtering")
+		}
+		logrus.WithFields(logFields).Info("Send policy modified outgoing message")
+		setText(decision.Text)
+		return strings.Replace(content, text, decision.Text, 1), nil
+	default:
+		logrus.WithFields(logFields).Debug("Send policy allowed outgoing message")
+		return content, nil
+	}
+}
+
+func evaluateSendPolicyRules(rules []sendPolicyRule, text string) SendPolicyDecision {
+	decision := SendPolicyDecision{Action: SendPolicyAllow, Text: text}
+	var stripped []string
+	for _, rule := range rules {
+		if !rule.pattern.MatchString(decision.Text) {
+			continue
+		}
+		if rule.action == sendPolicyRuleBlock {
+			return SendPolicyDecision{Action: SendPolicyReject, Reason: "matched rule " + rule.raw}
+		}
+		decision.Text = rule.pattern.ReplaceAllString(decision.Text, "")
+		stripped = append(stripped, rule.raw)
+	}
+	if len(stripped) > 0 {
+		decision.Action = SendPolicyModify
+		decision.Text = strings.TrimSpace(decision.Text)
+		decision.Reason = "stripped by rules " + strings.Join(stripped, ", ")
+	}
+	return decision
+}
+
+func callSendPolicyHook(ctx context.Context, url string, request SendPolicyRequest) (SendPolicyDecision, error) {
+	var decision SendPolicyDecision
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return decision, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return decision, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := signWebhookRequest(req, body, time.Now()); err != nil {
+		return decision, err
+	}
+
+	client, err := sendPolicyHTTPClient()
+	if err != nil {
+		return decision, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return decision, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decision, fmt.Errorf("send policy callback returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&decision); err != nil {
+		return decision, fmt.Errorf("decode send policy decision: %w", err)
+	}
+
+	switch decision.Action {
+	case SendPolicyAllow, SendPolicyModify, SendPolicyReject:
+		return decision, nil
+	case "":
+		decision.Action = SendPolicyAllow
+		return decision, nil
+	default:
+		return decision, fmt.Errorf("unknown send policy action %q", decision.Action)
+	}
+}
+
+// sendPolicyText locates the user-visible text of an outgoing message: the body
+// of a text message or the caption of an image, video or document.
+func sendPolicyText(msg *waE2E.Message) (string, func(string), bool) {
+	switch {
+	case msg == nil:
+		return "", nil, false
+	case msg.Conversation != nil:
+		return msg.GetConversation(), func(s string) { msg.Conversation = proto.String(s) }, true
+	case msg.ExtendedTextMessage != nil:
+		m := msg.ExtendedTextMessage
+		return m.GetText(), func(s string) { m.Text = proto.String(s) }, true
+	case msg.ImageMessage != nil:
+		m := msg.ImageMessage
+		return m.GetCaption(), func(s string) { m.Caption = proto.String(s) }, true
+	case msg.VideoMessage != nil:
+		m := msg.VideoMessage
+		return m.GetCaption(), func(s string) { m.Caption = proto.String(s) }, true
+	case msg.DocumentMessage != nil:
+		m := msg.DocumentMessage
+		return m.GetCaption(), func(s string) { m.Caption = proto.String(s) }, true
+	default:
+		return "", nil, false
+	}
+}
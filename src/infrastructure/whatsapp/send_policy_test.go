@@ -0,0 +1,160 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func setSendPolicyConfig(t *testing.T, rules []string, url string) {
+	t.Helper()
+	prevRules, prevURL := config.WhatsappSendPolicyRules, config.WhatsappSendPolicyURL
+	config.WhatsappSendPolicyRules, config.WhatsappSendPolicyURL = rules, url
+	t.Cleanup(func() {
+		config.WhatsappSendPolicyRules, config.WhatsappSendPolicyURL = prevRules, prevURL
+	})
+}
+
+var sendPolicyRecipient = types.NewJID("628123", types.DefaultUserServer)
+
+func TestParseSendPolicyRules_RejectsMalformedEntries(t *testing.T) {
+	for _, raw := range []string{"casino", "deny:casino", "block:", "strip:(unclosed"} {
+		if _, err := parseSendPolicyRules([]string{raw}); err == nil {
+			t.Errorf("parseSendPolicyRules(%q) = nil error, want error", raw)
+		}
+	}
+	rules, err := parseSendPolicyRules([]string{" block:(?i)casino ", "", "STRIP:https?://\\S+"})
+	if err != nil || len(rules) != 2 {
+		t.Fatalf("got %d rules, err %v; want 2 rules", len(rules), err)
+	}
+}
+
+func TestApplySendPolicy_StripRuleRewritesMessageAndContent(t *testing.T) {
+	setSendPolicyConfig(t, []string{`strip:https?://\S+`}, "")
+
+	msg := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String("see https://spam.example now")}}
+	content, err := ApplySendPolicy(context.Background(), sendPolicyRecipient, msg, "see https://spam.example now")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := msg.GetExtendedTextMessage().GetText(); got != "see  now" {
+		t.Errorf("message text = %q, want %q", got, "see  now")
+	}
+	if content != "see  now" {
+		t.Errorf("content = %q, want it to mirror the message text", content)
+	}
+}
+
+func TestApplySendPolicy_BlockRuleRejects(t *testing.T) {
+	setSendPolicyConfig(t, []string{"block:(?i)casino"}, "")
+
+	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{Caption: proto.String("Best CASINO deals")}}
+	_, err := ApplySendPolicy(context.Background(), sendPolicyRecipient, msg, "Best CASINO deals")
+
+	var policyErr pkgError.SendPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("err = %v, want SendPolicyError", err)
+	}
+}
+
+func TestApplySendPolicy_StrippingEverythingRejectsTextMessage(t *testing.T) {
+	setSendPolicyConfig(t, []string{`strip:https?://\S+`}, "")
+
+	msg := &waE2E.Message{Conversation: proto.String("https://spam.example")}
+	if _, err := ApplySendPolicy(context.Background(), sendPolicyRecipient, msg, "https://spam.example"); err == nil {
+		t.Fatal("expected an empty text message to be rejected")
+	}
+}
+
+func TestApplySendPolicy_CallbackSeesFilteredTextAndCanModify(t *testing.T) {
+	var received SendPolicyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Hub-Signature-256") == "" {
+			t.Error("policy callback request is not signed")
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_ = json.NewEncoder(w).Encode(SendPolicyDecision{Action: SendPolicyModify, Text: "[redacted]", Reason: "pii"})
+	}))
+	defer server.Close()
+	setSendPolicyConfig(t, []string{"strip:secret"}, server.URL)
+
+	msg := &waE2E.Message{Conversation: proto.String("my secret 1234")}
+	content, err := ApplySendPolicy(context.Background(), sendPolicyRecipient, msg, "my secret 1234")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Text != "my  1234" || received.Recipient != sendPolicyRecipient.String() {
+		t.Errorf("callback received %+v, want rule-filtered text and recipient", received)
+	}
+	if msg.GetConversation() != "[redacted]" || content != "[redacted]" {
+		t.Errorf("got message %q content %q, want callback text applied", msg.GetConversation(), content)
+	}
+}
+
+func TestApplySendPolicy_CallbackFailureFailsClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	setSendPolicyConfig(t, nil, server.URL)
+
+	msg := &waE2E.Message{Conversation: proto.String("hello")}
+	if _, err := ApplySendPolicy(context.Background(), sendPolicyRecipient, msg, "hello"); err == nil {
+		t.Fatal("expected send to be rejected when the policy callback fails")
+	}
+	if msg.GetConversation() != "hello" {
+		t.Errorf("message was modified on failure: %q", msg.GetConversation())
+	}
+}
+
+func TestApplySendPolicy_CallbackReusesConnections(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		connections int
+	)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(SendPolicyDecision{Action: SendPolicyAllow})
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			connections++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+	setSendPolicyConfig(t, nil, server.URL)
+
+	for range 3 {
+		msg := &waE2E.Message{Conversation: proto.String("hello")}
+		if _, err := ApplySendPolicy(context.Background(), sendPolicyRecipient, msg, "hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 1 {
+		t.Errorf("policy callbacks opened %d connections, want 1 reused", connections)
+	}
+}
+
+func TestApplySendPolicy_SkipsMessagesWithoutText(t *testing.T) {
+	setSendPolicyConfig(t, []string{"block:.*"}, "")
+
+	msg := &waE2E.Message{StickerMessage: &waE2E.StickerMessage{}}
+	if _, err := ApplySendPolicy(context.Background(), sendPolicyRecipient, msg, ""); err != nil {
+		t.Fatalf("sticker should bypass text policy, got %v", err)
+	}
+}
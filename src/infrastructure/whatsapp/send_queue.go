@@ -0,0 +1,356 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// sendQueueInterval is how often the dispatcher looks for messages to
+	// send when nothing wakes it earlier.
+	sendQueueInterval = 5 * time.Second
+	// sendQueueWait is how long a send waits for its queued message to go
+	// out before it answers with the message still queued.
+	sendQueueWait = 15 * time.Second
+	// sendQueueMaxAttempts bounds the retries of a message failing with
+	// transient errors while its device is connected.
+	sendQueueMaxAttempts = 10
+)
+
+var (
+	sendQueueMessages = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gowa_send_queue_messages",
+		Help: "Messages in the outgoing queue, by status (pending, failed).",
+	}, []string{"status"})
+	sendQueueAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowa_send_queue_attempts_total",
+		Help: "Sends attempted from the outgoing queue, by result (sent, retry, failed).",
+	}, []string{"result"})
+)
+
+func init() {
+	prometheus.MustRegister(sendQueueMessages, sendQueueAttempts)
+}
+
+// sendQueueDeviceFn returns the device a queued message is sent from, when
+// it is connected and logged in. Tests replace it.
+var sendQueueDeviceFn = func(deviceID string) (*DeviceInstance, bool) {
+	device, _, err := GetDeviceManager().ResolveDevice(deviceID)
+	if err != nil || !device.IsConnected() || !device.IsLoggedIn() {
+		return nil, false
+	}
+	return device, true
+}
+
+// activeSendQueue is the started outgoing queue, nil when sends go directly.
+var activeSendQueue atomic.Pointer[sendQueue]
+
+// sendQueue sends the messages of the outgoing queue with a pool of workers.
+// Only the oldest pending message of a chat is handed to a worker, and the
+// next one only once it is sent or failed, so every chat is sent in order.
+type sendQueue struct {
+	repo domainChatStorage.IChatStorageRepository
+	jobs chan sendQueueJob
+	wake chan struct{}
+
+	mu      sync.Mutex
+	sending map[string]bool                 // Chats with a message at a worker, by device and chat JID
+	waiters map[string]chan sendQueueResult // Sends waiting for their message, by message ID
+}
+
+type sendQueueJob struct {
+	message *domainChatStorage.OutgoingMessage
+	device  *DeviceInstance
+}
+
+type sendQueueResult struct {
+	resp   whatsmeow.SendResponse
+	err    error
+	queued bool // The send failed for now and is retried later
+}
+
+// StartSendQueue starts the dispatcher and workers of the outgoing queue.
+// From then on sends of devices go through the queue.
+func StartSendQueue(ctx context.Context, repo domainChatStorage.IChatStorageRepository, workers int) {
+	if repo == nil || workers <= 0 {
+		return
+	}
+	q := &sendQueue{
+		repo:    repo,
+		jobs:    make(chan sendQueueJob),
+		wake:    make(chan struct{}, 1),
+		sending: make(map[string]bool),
+		waiters: make(map[string]chan sendQueueResult),
+	}
+	for range workers {
+		go q.work(ctx)
+	}
+	go q.run(ctx)
+	activeSendQueue.Store(q)
+}
+
+// SendQueueEnabled reports whether sends go through the outgoing queue.
+func SendQueueEnabled() bool {
+	return activeSendQueue.Load() != nil
+}
+
+// wakeSendQueue lets the dispatcher look for messages right away, for
+// example those that waited for their device to connect.
+func wakeSendQueue() {
+	if q := activeSendQueue.Load(); q != nil {
+		q.notify()
+	}
+}
+
+// QueueMessage adds msg to the outgoing queue of the device in ctx and waits
+// briefly for it to be sent. The message ID is assigned up front, so when the
+// message is still queued, because the device is disconnected or other
+// messages of the chat are ahead of it, the response carries the ID it will
+// be sent with. Sent messages are forwarded to webhooks and stored by the queue.
+func QueueMessage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	q := activeSendQueue.Load()
+	if q == nil {
+		return whatsmeow.SendResponse{}, fmt.Errorf("send queue is not started")
+	}
+	instance, ok := DeviceFromContext(ctx)
+	if !ok || instance == nil {
+		return whatsmeow.SendResponse{}, fmt.Errorf("device identification required")
+	}
+	deviceID := instance.JID()
+	if deviceID == "" {
+		deviceID = instance.ID()
+	}
+
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to encode message: %w", err)
+	}
+	message := &domainChatStorage.OutgoingMessage{
+		DeviceID:  deviceID,
+		ChatJID:   recipient.String(),
+		MessageID: string(client.GenerateMessageID()),
+		Payload:   payload,
+		Content:   content,
+	}
+
+	result := make(chan sendQueueResult, 1)
+	q.mu.Lock()
+	q.waiters[message.MessageID] = result
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.waiters, message.MessageID)
+		q.mu.Unlock()
+	}()
+
+	if err := q.repo.EnqueueOutgoingMessage(message); err != nil {
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to queue message: %w", err)
+	}
+	q.notify()
+
+	timer := time.NewTimer(sendQueueWait)
+	defer timer.Stop()
+	select {
+	case res := <-result:
+		if !res.queued {
+			return res.resp, res.err
+		}
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	logrus.Infof("Send queue: message %s to %s is queued", message.MessageID, message.ChatJID)
+	return whatsmeow.SendResponse{ID: types.MessageID(message.MessageID), Timestamp: message.CreatedAt}, nil
+}
+
+func (q *sendQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *sendQueue) run(ctx context.Context) {
+	ticker := time.NewTicker(sendQueueInterval)
+	defer ticker.Stop()
+	for {
+		q.dispatch(ctx)
+		select {
+		case <-ticker.C:
+		case <-q.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch hands the oldest pending message of every chat to a worker, when
+// it is due, no message of the chat is being sent and its device is
+// connected. Messages of disconnected devices wait for the device.
+func (q *sendQueue) dispatch(ctx context.Context) {
+	q.updateMetrics()
+
+	messages, err := q.repo.ListDueOutgoingMessages(time.Now())
+	if err != nil {
+		logrus.Errorf("Send queue: failed to list pending messages: %v", err)
+		return
+	}
+
+	for _, message := range messages {
+		chat := message.DeviceID + "|" + message.ChatJID
+		q.mu.Lock()
+		busy := q.sending[chat]
+		q.mu.Unlock()
+		if busy {
+			continue
+		}
+
+		device, ok := sendQueueDeviceFn(message.DeviceID)
+		if !ok {
+			continue
+		}
+
+		q.mu.Lock()
+		q.sending[chat] = true
+		q.mu.Unlock()
+		select {
+		case q.jobs <- sendQueueJob{message: message, device: device}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *sendQueue) work(ctx context.Context) {
+	for {
+		select {
+		case job := <-q.jobs:
+			result := q.send(ctx, job)
+
+			q.mu.Lock()
+			delete(q.sending, job.message.DeviceID+"|"+job.message.ChatJID)
+			waiter := q.waiters[job.message.MessageID]
+			q.mu.Unlock()
+			if waiter != nil {
+				select {
+				case waiter <- result:
+				default:
+				}
+			}
+			q.notify()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send sends one queued message and records the outcome: a sent message
+// leaves the queue, a transient failure is retried and any other failure
+// keeps the message as failed.
+func (q *sendQueue) send(ctx context.Context, job sendQueueJob) sendQueueResult {
+	message := job.message
+	recipient, err := types.ParseJID(message.ChatJID)
+	msg := &waE2E.Message{}
+	if err == nil {
+		err = proto.Unmarshal(message.Payload, msg)
+	}
+	if err != nil {
+		q.markFailed(message, err)
+		return sendQueueResult{err: err}
+	}
+
+	client := job.device.GetClient()
+	if client == nil {
+		return q.retry(message, whatsmeow.ErrClientIsNil)
+	}
+	sendCtx := ContextWithDevice(ctx, job.device)
+	resp, err := SendMessageWithReachoutRetry(sendCtx, client, recipient, msg, whatsmeow.SendRequestExtra{ID: types.MessageID(message.MessageID)})
+	if err != nil {
+		if isTransientSendError(err) && message.Attempts+1 < sendQueueMaxAttempts {
+			return q.retry(message, err)
+		}
+		q.markFailed(message, err)
+		return sendQueueResult{err: err}
+	}
+
+	if err := q.repo.MarkOutgoingMessageSent(message.ID); err != nil {
+		logrus.Errorf("Send queue: failed to remove sent message %s: %v", message.MessageID, err)
+	}
+	sendQueueAttempts.WithLabelValues("sent").Inc()
+
+	ForwardSentMessageToWebhook(sendCtx, client, recipient, resp, msg)
+
+	senderJID := ""
+	if client.Store.ID != nil {
+		senderJID = client.Store.ID.String()
+	}
+	storeCtx, cancel := context.WithTimeout(sendCtx, 2*time.Second)
+	defer cancel()
+	if err := q.repo.StoreSentMessageWithContext(storeCtx, resp.ID, senderJID, recipient.String(), message.Content, resp.Timestamp, msg); err != nil {
+		logrus.Warnf("Send queue: failed to store sent message %s: %v", message.MessageID, err)
+	}
+	return sendQueueResult{resp: resp}
+}
+
+func (q *sendQueue) retry(message *domainChatStorage.OutgoingMessage, err error) sendQueueResult {
+	nextAttempt := time.Now().Add(sendQueueRetryDelay(message.Attempts + 1))
+	if markErr := q.repo.MarkOutgoingMessageRetry(message.ID, err.Error(), nextAttempt); markErr != nil {
+		logrus.Errorf("Send queue: failed to reschedule message %s: %v", message.MessageID, markErr)
+	}
+	sendQueueAttempts.WithLabelValues("retry").Inc()
+	logrus.Warnf("Send queue: message %s to %s failed, next attempt at %s: %v", message.MessageID, message.ChatJID, nextAttempt.Format(time.RFC3339), err)
+	return sendQueueResult{queued: true}
+}
+
+func (q *sendQueue) markFailed(message *domainChatStorage.OutgoingMessage, err error) {
+	if markErr := q.repo.MarkOutgoingMessageFailed(message.ID, err.Error()); markErr != nil {
+		logrus.Errorf("Send queue: failed to mark message %s failed: %v", message.MessageID, markErr)
+	}
+	sendQueueAttempts.WithLabelValues("failed").Inc()
+	logrus.Errorf("Send queue: message %s to %s failed: %v", message.MessageID, message.ChatJID, err)
+}
+
+func (q *sendQueue) updateMetrics() {
+	counts, err := q.repo.CountOutgoingMessages()
+	if err != nil {
+		logrus.Warnf("Send queue: failed to count queued messages: %v", err)
+		return
+	}
+	for _, status := range []string{domainChatStorage.OutgoingMessagePending, domainChatStorage.OutgoingMessageFailed} {
+		sendQueueMessages.WithLabelValues(status).Set(float64(counts[status]))
+	}
+}
+
+// isTransientSendError reports whether a send failed because of the
+// connection rather than the message, so sending it again can succeed.
+func isTransientSendError(err error) bool {
+	var disconnected *whatsmeow.DisconnectedError
+	return errors.Is(err, whatsmeow.ErrNotConnected) ||
+		errors.Is(err, whatsmeow.ErrClientIsNil) ||
+		errors.Is(err, whatsmeow.ErrIQTimedOut) ||
+		errors.Is(err, whatsmeow.ErrMessageTimedOut) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &disconnected)
+}
+
+// sendQueueRetryDelay doubles from five seconds up to five minutes. Messages
+// of a disconnected device are not attempted, so the delay only spaces out
+// attempts while the device is connected.
+func sendQueueRetryDelay(attempt int) time.Duration {
+	delay := 5 * time.Second
+	for i := 1; i < attempt && delay < 5*time.Minute; i++ {
+		delay *= 2
+	}
+	return min(delay, 5*time.Minute)
+}
@@ -0,0 +1,127 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow"
+)
+
+// sendQueueTestRepo keeps queued messages in memory and lists the oldest
+// pending message of every chat, like the SQLite repository.
+type sendQueueTestRepo struct {
+	domainChatStorage.IChatStorageRepository
+	pending []*domainChatStorage.OutgoingMessage
+}
+
+func (r *sendQueueTestRepo) ListDueOutgoingMessages(now time.Time) ([]*domainChatStorage.OutgoingMessage, error) {
+	heads := make(map[string]bool)
+	var due []*domainChatStorage.OutgoingMessage
+	for _, message := range r.pending {
+		chat := message.DeviceID + "|" + message.ChatJID
+		if heads[chat] {
+			continue
+		}
+		heads[chat] = true
+		if !message.NextAttemptAt.After(now) {
+			due = append(due, message)
+		}
+	}
+	return due, nil
+}
+
+func (r *sendQueueTestRepo) CountOutgoingMessages() (map[string]int64, error) {
+	return map[string]int64{domainChatStorage.OutgoingMessagePending: int64(len(r.pending))}, nil
+}
+
+func (r *sendQueueTestRepo) sent(messageID string) {
+	for i, message := range r.pending {
+		if message.MessageID == messageID {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestSendQueueRetryDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Second, sendQueueRetryDelay(1))
+	assert.Equal(t, 20*time.Second, sendQueueRetryDelay(3))
+	assert.Equal(t, 5*time.Minute, sendQueueRetryDelay(20))
+}
+
+func TestIsTransientSendError(t *testing.T) {
+	assert.True(t, isTransientSendError(fmt.Errorf("send: %w", whatsmeow.ErrNotConnected)))
+	assert.True(t, isTransientSendError(whatsmeow.ErrMessageTimedOut))
+	assert.True(t, isTransientSendError(&whatsmeow.DisconnectedError{Action: "message send"}))
+	assert.True(t, isTransientSendError(context.DeadlineExceeded))
+	assert.False(t, isTransientSendError(fmt.Errorf("%w 479", whatsmeow.ErrServerReturnedError)))
+	assert.False(t, isTransientSendError(errors.New("invalid JID")))
+}
+
+func TestSendQueueDispatch(t *testing.T) {
+	repo := &sendQueueTestRepo{}
+	for i, m := range []struct{ device, chat, id string }{
+		{"device-a", "628111@s.whatsapp.net", "A1"},
+		{"device-a", "628111@s.whatsapp.net", "A2"},
+		{"device-a", "628222@s.whatsapp.net", "B1"},
+		{"device-b", "628333@s.whatsapp.net", "C1"},
+	} {
+		repo.pending = append(repo.pending, &domainChatStorage.OutgoingMessage{
+			ID: int64(i + 1), DeviceID: m.device, ChatJID: m.chat, MessageID: m.id,
+		})
+	}
+
+	// device-b is disconnected.
+	original := sendQueueDeviceFn
+	sendQueueDeviceFn = func(deviceID string) (*DeviceInstance, bool) {
+		if deviceID != "device-a" {
+			return nil, false
+		}
+		return NewDeviceInstance(deviceID, nil, nil), true
+	}
+	t.Cleanup(func() { sendQueueDeviceFn = original })
+
+	q := &sendQueue{
+		repo:    repo,
+		jobs:    make(chan sendQueueJob, 10),
+		sending: make(map[string]bool),
+		waiters: make(map[string]chan sendQueueResult),
+	}
+	dispatched := func() []string {
+		var ids []string
+		for {
+			select {
+			case job := <-q.jobs:
+				ids = append(ids, job.message.MessageID)
+			default:
+				return ids
+			}
+		}
+	}
+
+	// Only the head of each chat goes out, and nothing of the disconnected device.
+	q.dispatch(context.Background())
+	assert.Equal(t, []string{"A1", "B1"}, dispatched())
+
+	// While A1 is being sent, A2 waits even once B1 is sent.
+	repo.sent("B1")
+	delete(q.sending, "device-a|628222@s.whatsapp.net")
+	q.dispatch(context.Background())
+	assert.Empty(t, dispatched())
+
+	// Once A1 is sent, A2 follows.
+	repo.sent("A1")
+	delete(q.sending, "device-a|628111@s.whatsapp.net")
+	q.dispatch(context.Background())
+	assert.Equal(t, []string{"A2"}, dispatched())
+
+	// The message of the disconnected device waits for it.
+	require.Len(t, repo.pending, 2)
+	assert.Equal(t, "C1", repo.pending[1].MessageID)
+}
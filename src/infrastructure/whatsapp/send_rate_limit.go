@@ -0,0 +1,197 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// tokenBucket holds up to burst tokens and refills at a constant rate; a send
+// takes one token.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket up to now and takes n tokens. When the bucket holds
+// fewer nothing is taken and the wait until it does is returned. More than
+// burst tokens are taken from a full bucket, which then refills from below
+// zero, so a send of n messages is never rejected forever.
+func (b *tokenBucket) take(now time.Time, perMinute, burst, n int, commit bool) time.Duration {
+	rate := float64(perMinute) / float64(time.Minute)
+	tokens := min(float64(burst), b.tokens+float64(now.Sub(b.last))*rate)
+	if need := float64(min(n, burst)); tokens < need {
+		return time.Duration((need - tokens) / rate)
+	}
+	if commit {
+		b.tokens, b.last = tokens-float64(n), now
+	}
+	return 0
+}
+
+// dailyCount is the number of messages a device sent on day.
+type dailyCount struct {
+	day   string
+	count int
+}
+
+// sendRateLimiter keeps the token buckets and daily counts of every device,
+// keyed by device ID (and recipient, for per-recipient buckets).
+type sendRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	daily   map[string]*dailyCount
+}
+
+var sendLimiter = newSendRateLimiter()
+
+// sendLimiterPruneSize is the number of buckets above which buckets that have
+// refilled completely, and so behave like new ones, are dropped.
+const sendLimiterPruneSize = 10000
+
+func newSendRateLimiter() *sendRateLimiter {
+	return &sendRateLimiter{
+		buckets: make(map[string]*tokenBucket),
+		daily:   make(map[string]*dailyCount),
+	}
+}
+
+// bucket returns the bucket of key, created full.
+func (l *sendRateLimiter) bucket(key string, now time.Time, burst int) *tokenBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= sendLimiterPruneSize {
+			l.prune(now, burst)
+		}
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// prune drops the buckets that would be full at now.
+func (l *sendRateLimiter) prune(now time.Time, burst int) {
+	perMinute := config.WhatsappSendRateLimit
+	if perRecipient := config.WhatsappSendRateLimitPerRecipient; perRecipient > 0 && (perMinute <= 0 || perRecipient < perMinute) {
+		perMinute = perRecipient
+	}
+	perMinute = max(1, perMinute)
+	refill := time.Duration(float64(burst) / float64(perMinute) * float64(time.Minute))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// reserve records a send of deviceID to recipient at now, or returns a
+// SendRateLimitError with the wait when a limit or the daily cap would be
+// exceeded. A rejected send takes nothing from any limit.
+func (l *sendRateLimiter) reserve(deviceID, recipient string, now time.Time) error {
+	return l.reserveN(deviceID, recipient, 1, now)
+}
+
+// reserveN is reserve for n messages sent together: all of them are recorded,
+// or none.
+func (l *sendRateLimiter) reserveN(deviceID, recipient string, n int, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	burst := max(1, config.WhatsappSendRateLimitBurst)
+	day := now.Format(time.DateOnly)
+	daily, ok := l.daily[deviceID]
+	if !ok || daily.day != day {
+		daily = &dailyCount{day: day}
+		l.daily[deviceID] = daily
+	}
+	if config.WhatsappSendDailyCap > 0 && daily.count+n > config.WhatsappSendDailyCap {
+		year, month, date := now.Date()
+		midnight := time.Date(year, month, date+1, 0, 0, 0, 0, now.Location())
+		return pkgError.SendRateLimitError{
+			Message: fmt.Sprintf("daily cap of %d messages reached", config.WhatsappSendDailyCap),
+			Wait:    midnight.Sub(now),
+		}
+	}
+
+	type limit struct {
+		bucket    *tokenBucket
+		perMinute int
+		name      string
+	}
+	var limits []limit
+	if config.WhatsappSendRateLimit > 0 {
+		limits = append(limits, limit{l.bucket(deviceID, now, burst), config.WhatsappSendRateLimit, "device"})
+	}
+	if config.WhatsappSendRateLimitPerRecipient > 0 {
+		limits = append(limits, limit{l.bucket(deviceID+"|"+recipient, now, burst), config.WhatsappSendRateLimitPerRecipient, "recipient " + recipient})
+	}
+	for _, lim := range limits {
+		if wait := lim.bucket.take(now, lim.perMinute, burst, n, false); wait > 0 {
+			return pkgError.SendRateLimitError{
+				Message: fmt.Sprintf("send rate limit of %d messages per minute to %s reached", lim.perMinute, lim.name),
+				Wait:    wait,
+			}
+		}
+	}
+	for _, lim := range limits {
+		lim.bucket.take(now, lim.perMinute, burst, n, true)
+	}
+	daily.count += n
+	return nil
+}
+
+type sendReservedKey struct{}
+
+// ReserveSend applies the configured send rate limits and daily cap to a send
+// to recipient, then waits a random time up to WhatsappSendJitter so sends do
+// not go out at machine-regular intervals. It returns a SendRateLimitError
+// when the send must wait, and ctx's error when ctx ends while waiting.
+//
+// Every message this device sends on request goes through it: the send
+// endpoints, edits, reactions, revokes and pins. Automatic replies (auto-reply,
+// call rejection) answer a contact and are not limited.
+func ReserveSend(ctx context.Context, recipient types.JID) error {
+	if reserved, _ := ctx.Value(sendReservedKey{}).(bool); reserved {
+		return nil
+	}
+	return reserveSends(ctx, recipient, 1)
+}
+
+// ReserveSends reserves n messages to recipient at once, such as an album and
+// its items, so the group is rejected as a whole instead of being cut off
+// halfway. The sends made with the returned context are not reserved again.
+func ReserveSends(ctx context.Context, recipient types.JID, n int) (context.Context, error) {
+	if err := reserveSends(ctx, recipient, n); err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, sendReservedKey{}, true), nil
+}
+
+func reserveSends(ctx context.Context, recipient types.JID, n int) error {
+	if config.WhatsappSendRateLimit > 0 || config.WhatsappSendRateLimitPerRecipient > 0 || config.WhatsappSendDailyCap > 0 {
+		deviceID := ""
+		if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+			deviceID = inst.ID()
+		}
+		if err := sendLimiter.reserveN(deviceID, recipient.ToNonAD().String(), n, time.Now()); err != nil {
+			return err
+		}
+	}
+	if config.WhatsappSendJitter <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(rand.N(config.WhatsappSendJitter))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
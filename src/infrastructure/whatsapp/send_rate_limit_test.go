@@ -0,0 +1,138 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func setSendRateLimitConfig(t *testing.T, perMinute, perRecipient, burst, dailyCap int) {
+	t.Helper()
+	prev := []int{config.WhatsappSendRateLimit, config.WhatsappSendRateLimitPerRecipient, config.WhatsappSendRateLimitBurst, config.WhatsappSendDailyCap}
+	config.WhatsappSendRateLimit, config.WhatsappSendRateLimitPerRecipient = perMinute, perRecipient
+	config.WhatsappSendRateLimitBurst, config.WhatsappSendDailyCap = burst, dailyCap
+	t.Cleanup(func() {
+		config.WhatsappSendRateLimit, config.WhatsappSendRateLimitPerRecipient = prev[0], prev[1]
+		config.WhatsappSendRateLimitBurst, config.WhatsappSendDailyCap = prev[2], prev[3]
+	})
+}
+
+func requireRateLimited(t *testing.T, err error, wantWait time.Duration) {
+	t.Helper()
+	var rateErr pkgError.SendRateLimitError
+	require.True(t, errors.As(err, &rateErr), "got %v, want SendRateLimitError", err)
+	assert.Equal(t, 429, rateErr.StatusCode())
+	assert.InDelta(t, wantWait.Seconds(), rateErr.RetryAfter().Seconds(), 0.01)
+}
+
+func TestSendRateLimiter_DeviceBucketAllowsBurstThenRefills(t *testing.T) {
+	setSendRateLimitConfig(t, 60, 0, 2, 0)
+	limiter := newSendRateLimiter()
+	now := time.Date(2026, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, limiter.reserve("dev", "a", now))
+	require.NoError(t, limiter.reserve("dev", "b", now))
+	requireRateLimited(t, limiter.reserve("dev", "c", now), time.Second)
+
+	// Another device has its own bucket.
+	require.NoError(t, limiter.reserve("other", "c", now))
+
+	require.NoError(t, limiter.reserve("dev", "c", now.Add(time.Second)))
+	requireRateLimited(t, limiter.reserve("dev", "c", now.Add(1500*time.Millisecond)), 500*time.Millisecond)
+}
+
+func TestSendRateLimiter_PerRecipientBucket(t *testing.T) {
+	setSendRateLimitConfig(t, 0, 1, 1, 0)
+	limiter := newSendRateLimiter()
+	now := time.Date(2026, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, limiter.reserve("dev", "a", now))
+	requireRateLimited(t, limiter.reserve("dev", "a", now.Add(20*time.Second)), 40*time.Second)
+	require.NoError(t, limiter.reserve("dev", "b", now))
+	require.NoError(t, limiter.reserve("dev", "a", now.Add(time.Minute)))
+}
+
+func TestSendRateLimiter_RejectedSendTakesNoToken(t *testing.T) {
+	setSendRateLimitConfig(t, 60, 1, 1, 0)
+	limiter := newSendRateLimiter()
+	now := time.Date(2026, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, limiter.reserve("dev", "a", now))
+	// Rejected by the recipient bucket after the device bucket has refilled:
+	// the device token must stay available for another recipient.
+	requireRateLimited(t, limiter.reserve("dev", "a", now.Add(time.Second)), 59*time.Second)
+	require.NoError(t, limiter.reserve("dev", "b", now.Add(time.Second)))
+}
+
+func TestSendRateLimiter_DailyCapResetsAtMidnight(t *testing.T) {
+	setSendRateLimitConfig(t, 0, 0, 5, 2)
+	limiter := newSendRateLimiter()
+	now := time.Date(2026, time.June, 1, 23, 0, 0, 0, time.UTC)
+
+	require.NoError(t, limiter.reserve("dev", "a", now))
+	require.NoError(t, limiter.reserve("dev", "b", now))
+	requireRateLimited(t, limiter.reserve("dev", "c", now), time.Hour)
+	require.NoError(t, limiter.reserve("dev", "c", now.Add(time.Hour)))
+}
+
+func TestSendRateLimiter_ReserveNIsAllOrNothing(t *testing.T) {
+	setSendRateLimitConfig(t, 60, 0, 5, 12)
+	limiter := newSendRateLimiter()
+	now := time.Date(2026, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	// A full bucket lets a group larger than the burst through, and refills
+	// from below zero afterwards.
+	require.NoError(t, limiter.reserveN("dev", "a", 10, now))
+	requireRateLimited(t, limiter.reserve("dev", "a", now), 6*time.Second)
+
+	// The daily cap rejects the whole group, not its tail.
+	requireRateLimited(t, limiter.reserveN("dev", "a", 3, now.Add(time.Hour)), 13*time.Hour)
+	require.NoError(t, limiter.reserveN("dev", "a", 2, now.Add(time.Hour)))
+}
+
+func TestReserveSends_CoversTheSendsOfItsContext(t *testing.T) {
+	setSendRateLimitConfig(t, 60, 0, 2, 0)
+	recipient := types.NewJID("628123", types.DefaultUserServer)
+	ctx := ContextWithDevice(context.Background(), NewDeviceInstance("album-device", nil, nil))
+
+	reserved, err := ReserveSends(ctx, recipient, 3)
+	require.NoError(t, err)
+	for range 3 {
+		require.NoError(t, ReserveSend(reserved, recipient))
+	}
+	var rateErr pkgError.SendRateLimitError
+	assert.ErrorAs(t, ReserveSend(ctx, recipient), &rateErr)
+}
+
+func TestReserveSend_DisabledByDefault(t *testing.T) {
+	setSendRateLimitConfig(t, 0, 0, 5, 0)
+	recipient := types.NewJID("628123", types.DefaultUserServer)
+	for range 100 {
+		require.NoError(t, ReserveSend(context.Background(), recipient))
+	}
+}
+
+func TestReserveSend_JitterStopsWithContext(t *testing.T) {
+	setSendRateLimitConfig(t, 0, 0, 5, 0)
+	prev := config.WhatsappSendJitter
+	config.WhatsappSendJitter = time.Hour
+	t.Cleanup(func() { config.WhatsappSendJitter = prev })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ReserveSend(ctx, types.NewJID("628123", types.DefaultUserServer))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, 1, pkgError.RetryAfterSeconds(0))
+	assert.Equal(t, 1, pkgError.RetryAfterSeconds(200*time.Millisecond))
+	assert.Equal(t, 41, pkgError.RetryAfterSeconds(40*time.Second+time.Millisecond))
+}
@@ -23,8 +23,8 @@ var (
 
 // Seams for unit tests. Mirrors the submitWebhookFn pattern in webhook_forward.go.
 var (
-	sendMessageFn = func(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message) (whatsmeow.SendResponse, error) {
-		return client.SendMessage(ctx, recipient, msg)
+	sendMessageFn = func(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+		return client.SendMessage(ctx, recipient, msg, extra...)
 	}
 	subscribePresenceFn = func(ctx context.Context, client *whatsmeow.Client, recipient types.JID) error {
 		return client.SubscribePresence(ctx, recipient)
@@ -56,8 +56,10 @@ func IsReachoutTimelockError(err error) bool {
 //
 // Group/broadcast/newsletter/legacy-server JIDs skip the retry — 463 does
 // not apply to them.
-func SendMessageWithReachoutRetry(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message) (whatsmeow.SendResponse, error) {
-	resp, err := sendMessageFn(ctx, client, recipient, msg)
+//
+// extra is passed on to client.SendMessage; both attempts use the same message ID.
+func SendMessageWithReachoutRetry(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	resp, err := sendMessageFn(ctx, client, recipient, msg, extra...)
 	if err == nil || !IsReachoutTimelockError(err) || !isUserJID(recipient) {
 		return resp, err
 	}
@@ -78,7 +80,7 @@ func SendMessageWithReachoutRetry(ctx context.Context, client *whatsmeow.Client,
 		logrus.Debugf("Retrying send to %s without an observed privacy token", recipient.String())
 	}
 
-	resp, err = sendMessageFn(ctx, client, recipient, msg)
+	resp, err = sendMessageFn(ctx, client, recipient, msg, extra...)
 	if err != nil {
 		logrus.Warnf("Retry send to %s after pre-warm still failed: %v", recipient.String(), err)
 	} else {
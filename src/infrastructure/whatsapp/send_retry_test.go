@@ -130,7 +130,7 @@ func TestSendMessageWithReachoutRetry(t *testing.T) {
 			}()
 
 			var sendCalls, subscribes int
-			sendMessageFn = func(_ context.Context, _ *whatsmeow.Client, _ types.JID, _ *waE2E.Message) (whatsmeow.SendResponse, error) {
+			sendMessageFn = func(_ context.Context, _ *whatsmeow.Client, _ types.JID, _ *waE2E.Message, _ ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
 				sendCalls++
 				if sendCalls == 1 {
 					return tt.first.resp, tt.first.err
@@ -175,7 +175,7 @@ func TestSendMessageWithReachoutRetry_SubscribeFailureDoesNotAbortRetry(t *testi
 	}()
 
 	var sendCalls int
-	sendMessageFn = func(_ context.Context, _ *whatsmeow.Client, _ types.JID, _ *waE2E.Message) (whatsmeow.SendResponse, error) {
+	sendMessageFn = func(_ context.Context, _ *whatsmeow.Client, _ types.JID, _ *waE2E.Message, _ ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
 		sendCalls++
 		if sendCalls == 1 {
 			return whatsmeow.SendResponse{}, reachoutErr()
@@ -220,7 +220,7 @@ func TestSendMessageWithReachoutRetry_WaitsForPrivacyTokenBeforeRetry(t *testing
 	}
 
 	var sendCalls int
-	sendMessageFn = func(ctx context.Context, client *whatsmeow.Client, recipient types.JID, _ *waE2E.Message) (whatsmeow.SendResponse, error) {
+	sendMessageFn = func(ctx context.Context, client *whatsmeow.Client, recipient types.JID, _ *waE2E.Message, _ ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
 		sendCalls++
 		if sendCalls == 1 {
 			return whatsmeow.SendResponse{}, reachoutErr()
@@ -281,7 +281,7 @@ func TestSendMessageWithReachoutRetry_RetriesWhenPrivacyTokenNeverAppears(t *tes
 	}
 
 	var sendCalls int
-	sendMessageFn = func(context.Context, *whatsmeow.Client, types.JID, *waE2E.Message) (whatsmeow.SendResponse, error) {
+	sendMessageFn = func(context.Context, *whatsmeow.Client, types.JID, *waE2E.Message, ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
 		sendCalls++
 		return whatsmeow.SendResponse{}, reachoutErr()
 	}
@@ -3,29 +3,37 @@ package whatsapp
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
 func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
-	// Configure HTTP client with optional TLS skip verification
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: config.WhatsappWebhookInsecureSkipVerify,
-		},
+	return submitWebhookAttempts(ctx, payload, url, 5)
+}
+
+// submitWebhookOnce posts the payload a single time. The retry queue uses it,
+// spacing out its attempts with its own backoff instead of sleeping here.
+func submitWebhookOnce(ctx context.Context, payload map[string]any, url string) error {
+	return submitWebhookAttempts(ctx, payload, url, 1)
+}
+
+// submitWebhookAttempts posts the payload up to maxAttempts times, doubling
+// the pause between attempts from one second.
+func submitWebhookAttempts(ctx context.Context, payload map[string]any, url string, maxAttempts int) error {
+	// Configure HTTP client with the webhook CA bundle, client certificate and optional TLS skip verification
+	tlsConfig, err := webhookTLSConfig()
+	if err != nil {
+		return pkgError.WebhookError(err.Error())
 	}
 	client := &http.Client{
 		Timeout:   10 * time.Second,
-		Transport: transport,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
 	}
 
 	postBody, err := json.Marshal(payload)
@@ -38,31 +46,38 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string) erro
 		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
 	}
 
-	secretKey := []byte(config.WhatsappWebhookSecret)
-	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
-	}
-
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
 
-	var attempt int
-	var maxAttempts = 5
+	info := webhookDeliveryFromContext(ctx, payload)
+	start := time.Now()
+	var attempt, statusCode int
+	var lastErr error
 	var sleepDuration = 1 * time.Second
 
 	for attempt = 0; attempt < maxAttempts; attempt++ {
 		// Create new request body for each attempt
 		req.Body = io.NopCloser(bytes.NewBuffer(postBody))
+		// Sign each attempt so its timestamp is fresh for replay checks
+		if err := signWebhookRequest(req, postBody, time.Now()); err != nil {
+			return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+		}
+		attemptStart := time.Now()
 		resp, err := client.Do(req)
+		statusCode = 0
 		if err == nil {
 			defer resp.Body.Close()
+			statusCode = resp.StatusCode
+		}
+		observeWebhookAttempt(info.event, statusCode, time.Since(attemptStart))
+		if err == nil {
 			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
+				recordWebhookDelivery(info, url, start, attempt+1, statusCode, nil)
 				return nil
 			}
 			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
 		}
+		lastErr = err
 		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
 		if attempt < maxAttempts-1 {
 			time.Sleep(sleepDuration)
@@ -70,5 +85,6 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string) erro
 		}
 	}
 
-	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
+	recordWebhookDelivery(info, url, start, attempt, statusCode, lastErr)
+	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, lastErr))
 }
@@ -0,0 +1,123 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+// webhookBatchEvent is the delivery info recorded for a batch, in place of
+// the names of the events it carries.
+const webhookBatchEvent = "batch"
+
+// webhookBatch collects the events waiting to be posted to one URL.
+type webhookBatch struct {
+	// ctx is the context of the latest event, which carries the device used
+	// to queue a failed batch for retry.
+	ctx    context.Context
+	events []map[string]any
+	// outbox tracks the outbox rows of the events, closed once posted.
+	outbox []*webhookOutboxPending
+	// full wakes the sender when the batch reached WhatsappWebhookBatchSize.
+	full chan struct{}
+	// interval is WhatsappWebhookBatchInterval when the sender started.
+	interval time.Duration
+}
+
+// webhookBatches holds the open batches per device and URL. A key is present
+// while its sender runs, so a batch posted late never overtakes the next one.
+var webhookBatches = struct {
+	sync.Mutex
+	open map[string]*webhookBatch
+}{open: make(map[string]*webhookBatch)}
+
+// webhookBatchingEnabled reports whether events are posted in batches.
+func webhookBatchingEnabled() bool {
+	return config.WhatsappWebhookBatchInterval > 0
+}
+
+// enqueueWebhookBatch adds body to the batch of deviceID and url. The batch is
+// posted WhatsappWebhookBatchInterval after its first event, or as soon as it
+// holds WhatsappWebhookBatchSize events. A non-nil outbox is released once
+// the batch is posted or fails.
+func enqueueWebhookBatch(ctx context.Context, deviceID, url string, body map[string]any, outbox *webhookOutboxPending) {
+	key := deviceID + "|" + url
+	webhookBatches.Lock()
+	defer webhookBatches.Unlock()
+
+	batch, running := webhookBatches.open[key]
+	if !running {
+		batch = &webhookBatch{full: make(chan struct{}, 1), interval: config.WhatsappWebhookBatchInterval}
+		webhookBatches.open[key] = batch
+	}
+	batch.ctx = context.WithoutCancel(ctx)
+	batch.events = append(batch.events, body)
+	if outbox != nil {
+		outbox.add()
+		batch.outbox = append(batch.outbox, outbox)
+	}
+	if size := config.WhatsappWebhookBatchSize; size > 0 && len(batch.events) >= size {
+		select {
+		case batch.full <- struct{}{}:
+		default:
+		}
+	}
+
+	if !running {
+		go sendWebhookBatches(key, deviceID, url, batch)
+	}
+}
+
+// sendWebhookBatches posts the batch of key each time it is due, one post at a
+// time, and exits once no event arrived for a whole interval.
+func sendWebhookBatches(key, deviceID, url string, batch *webhookBatch) {
+	for {
+		timer := time.NewTimer(batch.interval)
+		select {
+		case <-timer.C:
+		case <-batch.full:
+			timer.Stop()
+		}
+
+		webhookBatches.Lock()
+		events, outbox, ctx := batch.events, batch.outbox, batch.ctx
+		batch.events, batch.outbox = nil, nil
+		if len(events) == 0 {
+			delete(webhookBatches.open, key)
+			webhookBatches.Unlock()
+			return
+		}
+		webhookBatches.Unlock()
+
+		posted := postWebhookBatch(ctx, deviceID, url, events)
+		for _, pending := range outbox {
+			pending.done(posted)
+		}
+	}
+}
+
+// postWebhookBatch posts events to url in a batch envelope, queueing it for
+// retry like a single event when every attempt fails. It reports whether the
+// batch was posted.
+func postWebhookBatch(ctx context.Context, deviceID, url string, events []map[string]any) bool {
+	body := map[string]any{
+		"batch":  true,
+		"count":  len(events),
+		"events": events,
+	}
+	if deviceID != "" {
+		body["device_id"] = deviceID
+	}
+
+	ctx = contextWithWebhookDelivery(ctx, webhookDeliveryInfo{deviceID: deviceID, event: webhookBatchEvent, source: webhookDeliverySource(ctx)})
+	if err := submitWebhookFn(ctx, body, url); err != nil {
+		logrus.Warnf("Failed posting batch of %d webhook events to %s: %v", len(events), url, err)
+		enqueueWebhookDelivery(ctx, body, webhookBatchEvent, url, err)
+		return false
+	}
+	logrus.Infof("Posted batch of %d webhook events to %s", len(events), url)
+	return true
+}
@@ -0,0 +1,144 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+func TestForwardToWebhooks_BatchesEventsPerURL(t *testing.T) {
+	prevWebhooks, prevInterval, prevSize := config.WhatsappWebhook, config.WhatsappWebhookBatchInterval, config.WhatsappWebhookBatchSize
+	config.WhatsappWebhook = []string{"https://a.example.com", "https://b.example.com"}
+	config.WhatsappWebhookBatchInterval = time.Hour
+	config.WhatsappWebhookBatchSize = 3
+	originalSubmit := submitWebhookFn
+	t.Cleanup(func() {
+		config.WhatsappWebhook, config.WhatsappWebhookBatchInterval, config.WhatsappWebhookBatchSize = prevWebhooks, prevInterval, prevSize
+		submitWebhookFn = originalSubmit
+	})
+
+	var (
+		mu    sync.Mutex
+		posts = map[string][]map[string]any{}
+	)
+	submitWebhookFn = func(_ context.Context, payload map[string]any, url string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		posts[url] = append(posts[url], payload)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		payload := map[string]any{"event": "message", "device_id": "628111@s.whatsapp.net", "payload": map[string]any{"seq": i}}
+		if err := forwardToWebhooks(context.Background(), payload, "message"); err != nil {
+			t.Fatalf("forwardToWebhooks: %v", err)
+		}
+	}
+
+	// The third event fills both batches, well before the interval.
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		done := len(posts) == 2
+		mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batches were not posted once full: %v", posts)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, url := range config.WhatsappWebhook {
+		if len(posts[url]) != 1 {
+			t.Fatalf("%s: got %d posts, want 1 batch", url, len(posts[url]))
+		}
+		body := posts[url][0]
+		if body["batch"] != true || body["count"] != 3 || body["device_id"] != "628111@s.whatsapp.net" {
+			t.Errorf("%s: envelope = %v", url, body)
+		}
+		events, _ := body["events"].([]map[string]any)
+		for i, event := range events {
+			if event["payload"].(map[string]any)["seq"] != i {
+				t.Errorf("%s: event %d = %v, want events in order", url, i, event)
+			}
+		}
+	}
+}
+
+type outboxDoneRepo struct {
+	domainChatStorage.IChatStorageRepository
+	mu   sync.Mutex
+	done []string
+}
+
+func (r *outboxDoneRepo) MarkOutboxEventDone(_, messageID, _ string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done = append(r.done, messageID)
+	return nil
+}
+
+func (r *outboxDoneRepo) doneMessages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.done...)
+}
+
+func TestForwardToWebhooks_BatchedOutboxEventDoneAfterPost(t *testing.T) {
+	prevWebhooks, prevInterval, prevSize, prevOutbox := config.WhatsappWebhook, config.WhatsappWebhookBatchInterval, config.WhatsappWebhookBatchSize, config.WhatsappWebhookOutbox
+	config.WhatsappWebhook = []string{"https://c.example.com"}
+	config.WhatsappWebhookBatchInterval = time.Hour
+	config.WhatsappWebhookBatchSize = 1
+	config.WhatsappWebhookOutbox = true
+	originalSubmit := submitWebhookFn
+	t.Cleanup(func() {
+		config.WhatsappWebhook, config.WhatsappWebhookBatchInterval, config.WhatsappWebhookBatchSize, config.WhatsappWebhookOutbox = prevWebhooks, prevInterval, prevSize, prevOutbox
+		submitWebhookFn = originalSubmit
+	})
+
+	posted := make(chan struct{}, 2)
+	submitWebhookFn = func(_ context.Context, payload map[string]any, _ string) error {
+		defer func() { posted <- struct{}{} }()
+		events, _ := payload["events"].([]map[string]any)
+		if len(events) > 0 && events[0]["payload"].(map[string]any)["id"] == "m-fail" {
+			return errors.New("status 503")
+		}
+		return nil
+	}
+
+	repo := &outboxDoneRepo{}
+	for _, id := range []string{"m-fail", "m-ok"} {
+		ctx := contextWithWebhookOutbox(context.Background(), webhookOutboxRef{repo: repo, deviceID: "628222@s.whatsapp.net", messageID: id, eventName: "message"})
+		payload := map[string]any{"event": "message", "device_id": "628222@s.whatsapp.net", "payload": map[string]any{"id": id}}
+		if err := forwardToWebhooks(ctx, payload, "message"); !errors.Is(err, errWebhookOutboxPending) {
+			t.Fatalf("forwardToWebhooks(%s) = %v, want the row left pending", id, err)
+		}
+		select {
+		case <-posted:
+		case <-time.After(time.Second):
+			t.Fatalf("batch with %s was not posted", id)
+		}
+	}
+
+	// Batches of one URL are posted in order, so once the second is done the
+	// failed first one has been settled too.
+	deadline := time.Now().Add(time.Second)
+	for len(repo.doneMessages()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("posted batch did not mark its outbox event done")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := repo.doneMessages(); len(got) != 1 || got[0] != "m-ok" {
+		t.Fatalf("outbox events marked done = %v, want only m-ok", got)
+	}
+}
@@ -0,0 +1,118 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+// webhookDeliveryInterval is how often the worker looks for due deliveries.
+const webhookDeliveryInterval = 30 * time.Second
+
+// enqueueWebhookDelivery stores a webhook call that failed so the delivery
+// worker can retry it. It reports whether the call was queued, which needs a
+// device with chat storage in ctx and a positive retry budget.
+func enqueueWebhookDelivery(ctx context.Context, payload map[string]any, eventName, url string, deliveryErr error) bool {
+	if config.WhatsappWebhookRetryMaxAttempts <= 0 {
+		return false
+	}
+	deviceID, repo := chatwootLinkStorageFromContext(ctx)
+	if repo == nil {
+		return false
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		logrus.Errorf("Webhook queue: failed to serialize %s payload: %v", eventName, err)
+		return false
+	}
+
+	delivery := &domainChatStorage.WebhookDelivery{
+		DeviceID:      deviceID,
+		URL:           url,
+		EventName:     eventName,
+		PayloadJSON:   string(payloadJSON),
+		LastError:     truncateChatwootForwardError(deliveryErr),
+		NextAttemptAt: time.Now().Add(webhookOutboxRetryDelay(1)),
+	}
+	if err := repo.EnqueueWebhookDelivery(delivery); err != nil {
+		logrus.Errorf("Webhook queue: failed to queue %s for %s: %v", eventName, url, err)
+		return false
+	}
+	logrus.Warnf("Webhook queue: queued %s for %s, next attempt at %s", eventName, url, delivery.NextAttemptAt.Format(time.RFC3339))
+	return true
+}
+
+// StartWebhookDeliveryWorker retries the queued webhook deliveries until ctx
+// ends. A delivery that fails WhatsappWebhookRetryMaxAttempts times is kept as
+// a dead letter instead of being dropped.
+func StartWebhookDeliveryWorker(ctx context.Context, repo domainChatStorage.IChatStorageRepository) {
+	if repo == nil || config.WhatsappWebhookRetryMaxAttempts <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(webhookDeliveryInterval)
+		defer ticker.Stop()
+		for {
+			processDueWebhookDeliveries(ctx, repo)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func processDueWebhookDeliveries(ctx context.Context, repo domainChatStorage.IChatStorageRepository) {
+	deliveries, err := repo.ListDueWebhookDeliveries(time.Now(), 50)
+	if err != nil {
+		logrus.Errorf("Webhook queue: failed to list due deliveries: %v", err)
+		return
+	}
+	for _, delivery := range deliveries {
+		err := retryWebhookDelivery(ctx, delivery)
+		if err == nil {
+			if err := repo.MarkWebhookDeliveryDone(delivery.ID); err != nil {
+				logrus.Errorf("Webhook queue: failed to delete delivered %d: %v", delivery.ID, err)
+			}
+			continue
+		}
+
+		if delivery.Attempts+1 >= config.WhatsappWebhookRetryMaxAttempts {
+			if markErr := repo.MarkWebhookDeliveryDead(delivery.ID, truncateChatwootForwardError(err)); markErr != nil {
+				logrus.Errorf("Webhook queue: failed to move delivery %d to dead letters: %v", delivery.ID, markErr)
+			}
+			logrus.Errorf("Webhook queue: gave up on %s for %s after %d attempts: %v", delivery.EventName, delivery.URL, delivery.Attempts+1, err)
+			continue
+		}
+
+		nextAttempt := time.Now().Add(webhookOutboxRetryDelay(delivery.Attempts + 2))
+		if markErr := repo.MarkWebhookDeliveryFailed(delivery.ID, truncateChatwootForwardError(err), nextAttempt); markErr != nil {
+			logrus.Errorf("Webhook queue: failed to reschedule delivery %d: %v", delivery.ID, markErr)
+		}
+		logrus.Warnf("Webhook queue: delivery %d failed, next attempt at %s: %v", delivery.ID, nextAttempt.Format(time.RFC3339), err)
+	}
+}
+
+// retryWebhookDelivery makes one attempt at a queued delivery; the queue
+// schedules the next one when it fails.
+func retryWebhookDelivery(ctx context.Context, delivery *domainChatStorage.WebhookDelivery) error {
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(delivery.PayloadJSON), &payload); err != nil {
+		return fmt.Errorf("decode webhook payload %d: %w", delivery.ID, err)
+	}
+	deliverCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	deliverCtx = contextWithWebhookDelivery(deliverCtx, webhookDeliveryInfo{
+		deviceID: delivery.DeviceID,
+		event:    delivery.EventName,
+		source:   WebhookDeliverySourceQueue,
+	})
+	return submitWebhookOnceFn(deliverCtx, payload, delivery.URL)
+}
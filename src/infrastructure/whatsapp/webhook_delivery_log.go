@@ -0,0 +1,193 @@
+package whatsapp
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// webhookDeliveryLogSize is how many recent webhook deliveries are kept for
+// GET /webhooks/deliveries; older ones are overwritten.
+const webhookDeliveryLogSize = 1000
+
+// Webhook delivery sources.
+const (
+	WebhookDeliverySourceLive   = "live"
+	WebhookDeliverySourceQueue  = "queue"
+	WebhookDeliverySourceOutbox = "outbox"
+)
+
+var (
+	webhookAttemptDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gowa_webhook_attempt_duration_seconds",
+		Help:    "Duration of each webhook HTTP attempt, by event and status code (\"error\" when no response).",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"event", "code"})
+	webhookDeliveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gowa_webhook_delivery_duration_seconds",
+		Help:    "Duration of each webhook delivery including retries, by event, source and result.",
+		Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 20, 40, 60},
+	}, []string{"event", "source", "result"})
+	webhookDeliveryAttempts = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gowa_webhook_delivery_attempts",
+		Help:    "HTTP attempts needed per webhook delivery, by event and result.",
+		Buckets: []float64{1, 2, 3, 4, 5},
+	}, []string{"event", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(webhookAttemptDuration, webhookDeliveryDuration, webhookDeliveryAttempts)
+}
+
+// WebhookDeliveryRecord is one call to a webhook URL, with its retries.
+type WebhookDeliveryRecord struct {
+	Time     time.Time `json:"time"`
+	DeviceID string    `json:"device_id,omitempty"`
+	Event    string    `json:"event"`
+	URL      string    `json:"url"`
+	// Source is "live" for events as they happen, "queue" for replays by the
+	// webhook retry queue and "outbox" for events the outbox delivered late.
+	Source  string `json:"source"`
+	Success bool   `json:"success"`
+	// StatusCode is the status of the last attempt, 0 when it got no response.
+	StatusCode int    `json:"status_code"`
+	Attempts   int    `json:"attempts"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WebhookDeliveryFilter narrows GetWebhookDeliveries. Empty fields match
+// everything; Status is "success" or "failed".
+type WebhookDeliveryFilter struct {
+	URL      string
+	Event    string
+	DeviceID string
+	Status   string
+	Limit    int
+}
+
+func (f WebhookDeliveryFilter) matches(record WebhookDeliveryRecord) bool {
+	switch {
+	case f.URL != "" && f.URL != record.URL:
+		return false
+	case f.Event != "" && f.Event != record.Event:
+		return false
+	case f.DeviceID != "" && f.DeviceID != record.DeviceID:
+		return false
+	case f.Status == "success" && !record.Success:
+		return false
+	case f.Status == "failed" && record.Success:
+		return false
+	}
+	return true
+}
+
+type webhookDeliveryRing struct {
+	mu      sync.Mutex
+	records []WebhookDeliveryRecord
+	next    int
+}
+
+var webhookDeliveries = &webhookDeliveryRing{}
+
+func (r *webhookDeliveryRing) add(record WebhookDeliveryRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.records) < webhookDeliveryLogSize {
+		r.records = append(r.records, record)
+		return
+	}
+	r.records[r.next] = record
+	r.next = (r.next + 1) % webhookDeliveryLogSize
+}
+
+// GetWebhookDeliveries returns the recent webhook deliveries that match
+// filter, newest first.
+func GetWebhookDeliveries(filter WebhookDeliveryFilter) []WebhookDeliveryRecord {
+	limit := filter.Limit
+	if limit <= 0 || limit > webhookDeliveryLogSize {
+		limit = webhookDeliveryLogSize
+	}
+
+	webhookDeliveries.mu.Lock()
+	defer webhookDeliveries.mu.Unlock()
+
+	result := make([]WebhookDeliveryRecord, 0, min(limit, len(webhookDeliveries.records)))
+	count := len(webhookDeliveries.records)
+	for i := 1; i <= count && len(result) < limit; i++ {
+		record := webhookDeliveries.records[(webhookDeliveries.next-i+count)%count]
+		if filter.matches(record) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+type webhookDeliveryKey struct{}
+
+// webhookDeliveryInfo describes the delivery submitWebhook records; the body
+// it sends may be a Chatwoot payload or a template output without these.
+type webhookDeliveryInfo struct {
+	deviceID string
+	event    string
+	source   string
+}
+
+func contextWithWebhookDelivery(ctx context.Context, info webhookDeliveryInfo) context.Context {
+	return context.WithValue(ctx, webhookDeliveryKey{}, info)
+}
+
+// webhookDeliverySource returns the source set on ctx, "live" by default.
+func webhookDeliverySource(ctx context.Context) string {
+	if info, ok := ctx.Value(webhookDeliveryKey{}).(webhookDeliveryInfo); ok && info.source != "" {
+		return info.source
+	}
+	return WebhookDeliverySourceLive
+}
+
+func webhookDeliveryFromContext(ctx context.Context, payload map[string]any) webhookDeliveryInfo {
+	if info, ok := ctx.Value(webhookDeliveryKey{}).(webhookDeliveryInfo); ok {
+		return info
+	}
+	info := webhookDeliveryInfo{source: WebhookDeliverySourceLive}
+	info.event, _ = payload["event"].(string)
+	info.deviceID, _ = payload["device_id"].(string)
+	return info
+}
+
+func observeWebhookAttempt(event string, statusCode int, elapsed time.Duration) {
+	code := "error"
+	if statusCode > 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	webhookAttemptDuration.WithLabelValues(event, code).Observe(elapsed.Seconds())
+}
+
+func recordWebhookDelivery(info webhookDeliveryInfo, url string, start time.Time, attempts, statusCode int, deliveryErr error) {
+	result := "success"
+	if deliveryErr != nil {
+		result = "failed"
+	}
+	elapsed := time.Since(start)
+	webhookDeliveryDuration.WithLabelValues(info.event, info.source, result).Observe(elapsed.Seconds())
+	webhookDeliveryAttempts.WithLabelValues(info.event, result).Observe(float64(attempts))
+
+	record := WebhookDeliveryRecord{
+		Time:       start,
+		DeviceID:   info.deviceID,
+		Event:      info.event,
+		URL:        url,
+		Source:     info.source,
+		Success:    deliveryErr == nil,
+		StatusCode: statusCode,
+		Attempts:   attempts,
+		LatencyMs:  elapsed.Milliseconds(),
+	}
+	if deliveryErr != nil {
+		record.Error = deliveryErr.Error()
+	}
+	webhookDeliveries.add(record)
+}
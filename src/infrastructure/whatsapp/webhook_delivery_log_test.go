@@ -0,0 +1,71 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSubmitWebhook_RecordsDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := contextWithWebhookDelivery(context.Background(), webhookDeliveryInfo{
+		deviceID: "628000@s.whatsapp.net",
+		event:    "message.ack",
+		source:   WebhookDeliverySourceQueue,
+	})
+	if err := submitWebhook(ctx, map[string]any{"event": "ignored"}, server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	records := GetWebhookDeliveries(WebhookDeliveryFilter{URL: server.URL, Limit: 1})
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	got := records[0]
+	if !got.Success || got.StatusCode != http.StatusAccepted || got.Attempts != 1 || got.Event != "message.ack" ||
+		got.Source != WebhookDeliverySourceQueue || got.DeviceID != "628000@s.whatsapp.net" {
+		t.Errorf("record = %+v", got)
+	}
+}
+
+func TestGetWebhookDeliveries_FiltersNewestFirst(t *testing.T) {
+	prev := webhookDeliveries
+	webhookDeliveries = &webhookDeliveryRing{}
+	t.Cleanup(func() { webhookDeliveries = prev })
+
+	info := webhookDeliveryInfo{event: "message", source: WebhookDeliverySourceLive}
+	for i := 0; i < webhookDeliveryLogSize+2; i++ {
+		var err error
+		if i%2 == 1 {
+			err = errors.New("webhook returned status 502")
+		}
+		recordWebhookDelivery(info, "https://hooks.example.com/"+string(rune('a'+i%3)), time.Now(), 1, 502, err)
+	}
+
+	if got := len(GetWebhookDeliveries(WebhookDeliveryFilter{})); got != webhookDeliveryLogSize {
+		t.Fatalf("kept %d records, want %d", got, webhookDeliveryLogSize)
+	}
+	failed := GetWebhookDeliveries(WebhookDeliveryFilter{Status: "failed", Limit: 3})
+	if len(failed) != 3 {
+		t.Fatalf("got %d failed records, want 3", len(failed))
+	}
+	for _, record := range failed {
+		if record.Success || record.Error == "" {
+			t.Errorf("unexpected record %+v", record)
+		}
+	}
+	// The newest record is number webhookDeliveryLogSize+1, which failed.
+	if want := "https://hooks.example.com/" + string(rune('a'+(webhookDeliveryLogSize+1)%3)); failed[0].URL != want {
+		t.Errorf("newest failed URL = %q, want %q", failed[0].URL, want)
+	}
+	if got := GetWebhookDeliveries(WebhookDeliveryFilter{URL: "https://hooks.example.com/a", Status: "success"}); len(got) == 0 {
+		t.Error("expected successful deliveries to hooks.example.com/a")
+	}
+}
@@ -0,0 +1,200 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+type webhookDeliveryTestRepo struct {
+	domainChatStorage.IChatStorageRepository
+	queued []*domainChatStorage.WebhookDelivery
+	due    []*domainChatStorage.WebhookDelivery
+	done   []int64
+	failed map[int64]time.Time
+	dead   map[int64]string
+}
+
+func (r *webhookDeliveryTestRepo) EnqueueWebhookDelivery(delivery *domainChatStorage.WebhookDelivery) error {
+	r.queued = append(r.queued, delivery)
+	return nil
+}
+
+func (r *webhookDeliveryTestRepo) ListDueWebhookDeliveries(time.Time, int) ([]*domainChatStorage.WebhookDelivery, error) {
+	return r.due, nil
+}
+
+func (r *webhookDeliveryTestRepo) MarkWebhookDeliveryDone(id int64) error {
+	r.done = append(r.done, id)
+	return nil
+}
+
+func (r *webhookDeliveryTestRepo) MarkWebhookDeliveryFailed(id int64, _ string, nextAttemptAt time.Time) error {
+	r.failed[id] = nextAttemptAt
+	return nil
+}
+
+func (r *webhookDeliveryTestRepo) MarkWebhookDeliveryDead(id int64, lastError string) error {
+	r.dead[id] = lastError
+	return nil
+}
+
+func TestForwardToWebhooksQueuesFailures(t *testing.T) {
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://fail1", "https://fail2"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(_ context.Context, _ map[string]any, url string) error {
+		return errors.New("failure for " + url)
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	repo := &webhookDeliveryTestRepo{}
+	ctx := ContextWithDevice(context.Background(), NewDeviceInstance("device-a", nil, repo))
+	payload := map[string]any{"event": "message", "payload": map[string]any{"id": "m1"}}
+
+	// Every failure was queued for retry, so the event counts as handed off.
+	require.NoError(t, forwardToWebhooks(ctx, payload, "message"))
+	require.Len(t, repo.queued, 2)
+	assert.Equal(t, "device-a", repo.queued[0].DeviceID)
+	assert.Equal(t, "https://fail1", repo.queued[0].URL)
+	assert.Equal(t, "message", repo.queued[0].EventName)
+	assert.JSONEq(t, `{"event":"message","payload":{"id":"m1"}}`, repo.queued[0].PayloadJSON)
+	assert.Equal(t, "failure for https://fail1", repo.queued[0].LastError)
+
+	originalMaxAttempts := config.WhatsappWebhookRetryMaxAttempts
+	config.WhatsappWebhookRetryMaxAttempts = 0
+	defer func() { config.WhatsappWebhookRetryMaxAttempts = originalMaxAttempts }()
+	assert.Error(t, forwardToWebhooks(ctx, payload, "message"))
+	assert.Len(t, repo.queued, 2)
+}
+
+// webhookForwardTestRepo records queued deliveries made from the background
+// webhook goroutine, and answers the chat lookup of the payload builder.
+type webhookForwardTestRepo struct {
+	domainChatStorage.IChatStorageRepository
+	mu     sync.Mutex
+	queued []*domainChatStorage.WebhookDelivery
+}
+
+func (r *webhookForwardTestRepo) GetChat(string) (*domainChatStorage.Chat, error) {
+	return nil, nil
+}
+
+func (r *webhookForwardTestRepo) EnqueueWebhookDelivery(delivery *domainChatStorage.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queued = append(r.queued, delivery)
+	return nil
+}
+
+func (r *webhookForwardTestRepo) queuedDeliveries() []*domainChatStorage.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*domainChatStorage.WebhookDelivery(nil), r.queued...)
+}
+
+func TestHandleWebhookForwardQueuesInboundFailures(t *testing.T) {
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://down"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(context.Context, map[string]any, string) error {
+		return errors.New("status 503")
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	// The event handler context carries the device, the webhook goroutine
+	// builds its own context from it.
+	repo := &webhookForwardTestRepo{}
+	instance := NewDeviceInstance("device-a", nil, repo)
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:   types.NewJID("123", types.DefaultUserServer),
+				Sender: types.NewJID("123", types.DefaultUserServer),
+			},
+			ID:        "MSG-IN-1",
+			Timestamp: time.Now(),
+		},
+		Message: &waE2E.Message{Conversation: protoString("hello")},
+	}
+	handleWebhookForward(ContextWithDevice(context.Background(), instance), evt, repo, nil)
+
+	require.Eventually(t, func() bool { return len(repo.queuedDeliveries()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	delivery := repo.queuedDeliveries()[0]
+	assert.Equal(t, "device-a", delivery.DeviceID)
+	assert.Equal(t, "https://down", delivery.URL)
+	assert.Equal(t, EventTypeMessage, delivery.EventName)
+	assert.Contains(t, delivery.PayloadJSON, "MSG-IN-1")
+}
+
+func TestProcessDueWebhookDeliveries(t *testing.T) {
+	originalMaxAttempts := config.WhatsappWebhookRetryMaxAttempts
+	config.WhatsappWebhookRetryMaxAttempts = 3
+	defer func() { config.WhatsappWebhookRetryMaxAttempts = originalMaxAttempts }()
+
+	repo := &webhookDeliveryTestRepo{
+		due: []*domainChatStorage.WebhookDelivery{
+			{ID: 1, URL: "https://ok", EventName: "message", PayloadJSON: `{"event":"message"}`},
+			{ID: 2, URL: "https://down", EventName: "message", PayloadJSON: `{"event":"message"}`, Attempts: 1},
+			{ID: 3, URL: "https://down", EventName: "message", PayloadJSON: `{"event":"message"}`, Attempts: 2},
+		},
+		failed: map[int64]time.Time{},
+		dead:   map[int64]string{},
+	}
+
+	originalSubmit := submitWebhookOnceFn
+	var delivered []map[string]any
+	submitWebhookOnceFn = func(_ context.Context, payload map[string]any, url string) error {
+		if url == "https://down" {
+			return errors.New("status 503")
+		}
+		delivered = append(delivered, payload)
+		return nil
+	}
+	defer func() { submitWebhookOnceFn = originalSubmit }()
+
+	before := time.Now()
+	processDueWebhookDeliveries(context.Background(), repo)
+
+	assert.Equal(t, []int64{1}, repo.done)
+	assert.Equal(t, []map[string]any{{"event": "message"}}, delivered)
+	require.Contains(t, repo.failed, int64(2))
+	assert.WithinDuration(t, before.Add(webhookOutboxRetryDelay(3)), repo.failed[2], time.Second)
+	// The third failed attempt uses up the budget.
+	assert.Equal(t, map[int64]string{3: "status 503"}, repo.dead)
+}
+
+func TestRetryWebhookDeliveryMakesOneAttempt(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	err := retryWebhookDelivery(context.Background(), &domainChatStorage.WebhookDelivery{
+		ID: 1, URL: server.URL, EventName: "message", PayloadJSON: `{"event":"message"}`,
+	})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+	// No in-process backoff: the queue schedules the next attempt.
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,192 @@
+package whatsapp
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+// Webhook filter actions.
+const (
+	webhookFilterAllow = "allow"
+	webhookFilterDeny  = "deny"
+)
+
+// webhookMediaFields are the payload fields holding a message's media, in the
+// order its media type is reported.
+var webhookMediaFields = []string{"image", "video", "audio", "document", "sticker", "video_note"}
+
+// webhookFilterCondition is one "<key>=<value>" test of a filter rule.
+type webhookFilterCondition struct {
+	key   string
+	value string
+}
+
+type webhookFilterRule struct {
+	action     string
+	conditions []webhookFilterCondition
+}
+
+// compiledWebhookFilters keeps the last parsed rule set; it is parsed again
+// whenever the config slice changes.
+var compiledWebhookFilters struct {
+	sync.Mutex
+	source []string
+	rules  []webhookFilterRule
+}
+
+// parseWebhookFilters parses "<allow|deny>:<condition>[;<condition>...]" rules.
+// A condition is "*" (always true) or "<key>=<value>" with key one of url,
+// event, chat, chat_type, media and from_me.
+func parseWebhookFilters(raw []string) ([]webhookFilterRule, error) {
+	rules := make([]webhookFilterRule, 0, len(raw))
+	for _, entry := range raw {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		action, expr, found := strings.Cut(entry, ":")
+		action = strings.ToLower(strings.TrimSpace(action))
+		if !found || strings.TrimSpace(expr) == "" || (action != webhookFilterAllow && action != webhookFilterDeny) {
+			return nil, fmt.Errorf("invalid webhook filter %q: expected allow:<conditions> or deny:<conditions>", entry)
+		}
+		rule := webhookFilterRule{action: action}
+		for _, part := range strings.Split(expr, ";") {
+			part = strings.TrimSpace(part)
+			if part == "*" {
+				continue
+			}
+			key, value, found := strings.Cut(part, "=")
+			condition := webhookFilterCondition{key: strings.ToLower(strings.TrimSpace(key)), value: strings.TrimSpace(value)}
+			if !found || condition.value == "" {
+				return nil, fmt.Errorf("invalid webhook filter %q: condition %q is not <key>=<value>", entry, part)
+			}
+			if err := validateWebhookFilterCondition(condition); err != nil {
+				return nil, fmt.Errorf("invalid webhook filter %q: %w", entry, err)
+			}
+			rule.conditions = append(rule.conditions, condition)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func validateWebhookFilterCondition(condition webhookFilterCondition) error {
+	switch condition.key {
+	case "url", "event", "chat":
+		return nil
+	case "chat_type":
+		switch condition.value {
+		case "dm", "group", "newsletter", "broadcast":
+			return nil
+		}
+		return fmt.Errorf("chat_type must be dm, group, newsletter or broadcast, got %q", condition.value)
+	case "media":
+		if condition.value == "none" || condition.value == "any" || slices.Contains(webhookMediaFields, condition.value) {
+			return nil
+		}
+		return fmt.Errorf("media must be none, any or one of %s, got %q", strings.Join(webhookMediaFields, ", "), condition.value)
+	case "from_me":
+		if _, err := strconv.ParseBool(condition.value); err != nil {
+			return fmt.Errorf("from_me must be true or false, got %q", condition.value)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown condition %q", condition.key)
+}
+
+// ValidateWebhookFilters reports the first malformed rule in raw, if any.
+func ValidateWebhookFilters(raw []string) error {
+	_, err := parseWebhookFilters(raw)
+	return err
+}
+
+func configuredWebhookFilters() []webhookFilterRule {
+	compiledWebhookFilters.Lock()
+	defer compiledWebhookFilters.Unlock()
+
+	source := config.Setting(&config.WhatsappWebhookFilters)
+	if compiledWebhookFilters.rules != nil && slices.Equal(compiledWebhookFilters.source, source) {
+		return compiledWebhookFilters.rules
+	}
+	rules, err := parseWebhookFilters(source)
+	if err != nil {
+		// Rules are validated on startup and import; a broken set filters nothing.
+		return nil
+	}
+	compiledWebhookFilters.source = slices.Clone(source)
+	compiledWebhookFilters.rules = rules
+	return rules
+}
+
+// webhookFilterAllows reports whether an event may be posted to webhookURL.
+// The first WhatsappWebhookFilters rule whose conditions all hold decides;
+// events no rule matches are posted.
+func webhookFilterAllows(payload map[string]any, eventName, webhookURL string) bool {
+	for _, rule := range configuredWebhookFilters() {
+		if rule.matches(payload, eventName, webhookURL) {
+			return rule.action == webhookFilterAllow
+		}
+	}
+	return true
+}
+
+func (rule webhookFilterRule) matches(payload map[string]any, eventName, webhookURL string) bool {
+	data, _ := payload["payload"].(map[string]any)
+	for _, condition := range rule.conditions {
+		var ok bool
+		switch condition.key {
+		case "url":
+			ok = condition.value == webhookURL
+		case "event":
+			ok = strings.EqualFold(condition.value, eventName)
+		case "chat":
+			chat := webhookChatJID(payload)
+			ok = condition.value == tenantRouteWildcard && chat != "" || utils.MatchesIgnoredJID(chat, []string{condition.value})
+		case "chat_type":
+			ok = webhookChatType(webhookChatJID(payload)) == condition.value
+		case "media":
+			media := webhookMediaType(data)
+			ok = condition.value == media || (condition.value == "any" && media != "none")
+		case "from_me":
+			fromMe, present := data["is_from_me"].(bool)
+			want, _ := strconv.ParseBool(condition.value)
+			ok = present && fromMe == want
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// webhookChatType classifies a chat JID as dm, group, newsletter or
+// broadcast; "" when there is no chat.
+func webhookChatType(chatJID string) string {
+	switch {
+	case chatJID == "":
+		return ""
+	case strings.HasSuffix(chatJID, "@g.us"):
+		return "group"
+	case strings.HasSuffix(chatJID, "@newsletter"):
+		return "newsletter"
+	case strings.HasSuffix(chatJID, "@broadcast"):
+		return "broadcast"
+	}
+	return "dm"
+}
+
+// webhookMediaType returns the media field set in a payload, or "none".
+func webhookMediaType(data map[string]any) string {
+	for _, field := range webhookMediaFields {
+		if value, ok := data[field]; ok && value != nil {
+			return field
+		}
+	}
+	return "none"
+}
@@ -0,0 +1,90 @@
+package whatsapp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestForwardToWebhooks_AppliesFilters(t *testing.T) {
+	prevWebhooks, prevFilters := config.WhatsappWebhook, config.WhatsappWebhookFilters
+	config.WhatsappWebhook = []string{"https://crm.example.com", "https://archive.example.com"}
+	config.WhatsappWebhookFilters = []string{
+		"deny:url=https://crm.example.com;chat_type=group",
+		"deny:url=https://crm.example.com;from_me=true",
+	}
+	originalSubmit := submitWebhookFn
+	t.Cleanup(func() {
+		config.WhatsappWebhook, config.WhatsappWebhookFilters = prevWebhooks, prevFilters
+		submitWebhookFn = originalSubmit
+	})
+
+	var posted []string
+	submitWebhookFn = func(_ context.Context, _ map[string]any, url string) error {
+		posted = append(posted, url)
+		return nil
+	}
+
+	for _, tc := range []struct {
+		name    string
+		payload map[string]any
+		want    []string
+	}{
+		{"group message", map[string]any{"chat_id": "120363025246125486@g.us", "is_from_me": false}, []string{"https://archive.example.com"}},
+		{"own direct message", map[string]any{"chat_id": "628111@s.whatsapp.net", "is_from_me": true}, []string{"https://archive.example.com"}},
+		{"direct message", map[string]any{"chat_id": "628111@s.whatsapp.net", "is_from_me": false}, config.WhatsappWebhook},
+	} {
+		posted = nil
+		if err := forwardToWebhooks(context.Background(), map[string]any{"event": "message", "payload": tc.payload}, "message"); err != nil {
+			t.Fatalf("%s: forwardToWebhooks: %v", tc.name, err)
+		}
+		if !slices.Equal(posted, tc.want) {
+			t.Errorf("%s: posted to %v, want %v", tc.name, posted, tc.want)
+		}
+	}
+}
+
+func TestWebhookFilterAllows(t *testing.T) {
+	prevFilters := config.WhatsappWebhookFilters
+	config.WhatsappWebhookFilters = []string{
+		"allow:chat=@g.us;media=image",
+		"deny:chat_type=group",
+		"deny:event=message;media=any",
+	}
+	t.Cleanup(func() { config.WhatsappWebhookFilters = prevFilters })
+
+	groupImage := map[string]any{"payload": map[string]any{"chat_id": "1203@g.us", "image": "statics/media/a.jpg"}}
+	groupText := map[string]any{"payload": map[string]any{"chat_id": "1203@g.us", "body": "hi"}}
+	dmVideo := map[string]any{"payload": map[string]any{"chat_id": "628111@s.whatsapp.net", "video": "statics/media/b.mp4"}}
+	dmText := map[string]any{"payload": map[string]any{"chat_id": "628111@s.whatsapp.net", "body": "hi"}}
+
+	for name, tc := range map[string]struct {
+		payload map[string]any
+		event   string
+		want    bool
+	}{
+		"group image":   {groupImage, "message", true},
+		"group text":    {groupText, "message", false},
+		"dm video":      {dmVideo, "message", false},
+		"dm text":       {dmText, "message", true},
+		"dm video edit": {dmVideo, "message.edited", true},
+		"no chat":       {map[string]any{"payload": map[string]any{}}, "connected", true},
+	} {
+		if got := webhookFilterAllows(tc.payload, tc.event, "https://a.example.com"); got != tc.want {
+			t.Errorf("%s: allowed = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestValidateWebhookFilters(t *testing.T) {
+	if err := ValidateWebhookFilters([]string{"deny:*", "allow:chat=*;from_me=false", "deny:media=none;event=message"}); err != nil {
+		t.Errorf("valid rules rejected: %v", err)
+	}
+	for _, rule := range []string{"drop:chat_type=group", "deny:", "deny:chat_type=channel", "deny:media=gif", "deny:from_me=maybe", "deny:sender=x", "deny:chat"} {
+		if err := ValidateWebhookFilters([]string{rule}); err == nil {
+			t.Errorf("%q: expected an error", rule)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package whatsapp
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	WebhookFormatDefault  = "default"
+	WebhookFormatChatwoot = "chatwoot"
+)
+
+// webhookBody returns what is posted to webhooks for a payload in the
+// configured WhatsappWebhookFormat.
+func webhookBody(ctx context.Context, payload map[string]any, eventName string) map[string]any {
+	if config.Setting(&config.WhatsappWebhookFormat) != WebhookFormatChatwoot {
+		return payload
+	}
+	if body := chatwootWebhookPayload(ctx, payload, eventName); body != nil {
+		return body
+	}
+	return payload
+}
+
+// chatwootWebhookPayload shapes a message event like the Chatwoot API calls
+// the built-in Chatwoot sync makes: the contact to find or create, the
+// contact_inbox source_id of the conversation, and the message to post.
+// Attachments point at the downloaded media file, to be sent as the
+// "attachments[]" parts of a multipart message request. It returns nil for
+// events with no Chatwoot message, which keep the default format.
+func chatwootWebhookPayload(ctx context.Context, payload map[string]any, eventName string) map[string]any {
+	switch eventName {
+	case "message", "message.reaction", "message.edited", "message.revoked", "message.deleted", EventTypeMessageOutgoing:
+	default:
+		return nil
+	}
+	data, ok := payload["payload"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	info, err := extractChatwootContactInfo(ctx, data)
+	if err != nil {
+		logrus.Debugf("Webhook: sending %s in default format: %v", eventName, err)
+		return nil
+	}
+	content, attachments, opts, ok := buildChatwootMessage(eventName, data, info)
+	if !ok {
+		return nil
+	}
+
+	phoneNumber, identifier := chatwoot.ContactIdentity(info.Identifier, info.IsGroup)
+	contact := map[string]any{
+		"name":              info.Name,
+		"custom_attributes": map[string]any{"gowa_whatsapp_jid": info.Identifier},
+	}
+	if phoneNumber != "" {
+		contact["phone_number"] = phoneNumber
+	}
+	if identifier != "" {
+		contact["identifier"] = identifier
+	}
+
+	message := map[string]any{
+		"content":      content,
+		"message_type": chatwootMessageTypeFromPayload(data),
+		"private":      false,
+	}
+	if opts.SourceID != "" {
+		message["source_id"] = opts.SourceID
+	}
+	if len(opts.ContentAttributes) > 0 {
+		message["content_attributes"] = opts.ContentAttributes
+	}
+	if len(attachments) > 0 {
+		pointers := make([]map[string]any, 0, len(attachments))
+		for _, path := range attachments {
+			pointer := map[string]any{"field": "attachments[]", "path": path}
+			// Downloaded media under statics/ is served by this app.
+			if rel, ok := strings.CutPrefix(filepath.ToSlash(path), "statics/"); ok {
+				parts := strings.Split(rel, "/")
+				for i, part := range parts {
+					parts[i] = url.PathEscape(part)
+				}
+				pointer["url_path"] = config.AppBasePath + "/statics/" + strings.Join(parts, "/")
+			}
+			pointers = append(pointers, pointer)
+		}
+		message["attachments"] = pointers
+	}
+
+	body := map[string]any{
+		"event":        eventName,
+		"format":       WebhookFormatChatwoot,
+		"contact":      contact,
+		"conversation": map[string]any{"source_id": info.ChatJID},
+		"message":      message,
+	}
+	for _, key := range []string{"device_id", "session_id", "tenant_id"} {
+		if value, ok := payload[key]; ok {
+			body[key] = value
+		}
+	}
+	return body
+}
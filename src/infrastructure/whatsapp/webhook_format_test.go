@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func setWebhookFormat(t *testing.T, format string) {
+	t.Helper()
+	prev := config.WhatsappWebhookFormat
+	config.WhatsappWebhookFormat = format
+	t.Cleanup(func() { config.WhatsappWebhookFormat = prev })
+}
+
+func TestWebhookBody_ChatwootFormatShapesMessages(t *testing.T) {
+	setWebhookFormat(t, WebhookFormatChatwoot)
+	orig := contactDisplayNameFn
+	contactDisplayNameFn = func(context.Context, string) string { return "" }
+	t.Cleanup(func() { contactDisplayNameFn = orig })
+
+	payload := map[string]any{
+		"event":     "message",
+		"device_id": "628000@s.whatsapp.net",
+		"tenant_id": "acme",
+		"payload": map[string]any{
+			"id":        "wamid-1",
+			"from":      "628123456789@s.whatsapp.net",
+			"from_name": "Alice",
+			"chat_id":   "628123456789@s.whatsapp.net",
+			"body":      "hello",
+		},
+	}
+	body := webhookBody(context.Background(), payload, "message")
+
+	if body["format"] != WebhookFormatChatwoot || body["event"] != "message" || body["tenant_id"] != "acme" {
+		t.Fatalf("unexpected envelope: %v", body)
+	}
+	contact, _ := body["contact"].(map[string]any)
+	if contact["name"] != "Alice" || contact["phone_number"] != "+628123456789" {
+		t.Errorf("contact = %v", contact)
+	}
+	conversation, _ := body["conversation"].(map[string]any)
+	if conversation["source_id"] != "628123456789@s.whatsapp.net" {
+		t.Errorf("conversation = %v", conversation)
+	}
+	message, _ := body["message"].(map[string]any)
+	if message["content"] != "hello" || message["message_type"] != "incoming" || message["source_id"] != "WAID:wamid-1" {
+		t.Errorf("message = %v", message)
+	}
+}
+
+func TestWebhookBody_KeepsDefaultShape(t *testing.T) {
+	payload := map[string]any{
+		"event":   "group.participants",
+		"payload": map[string]any{"chat_id": "120363000000000001@g.us"},
+	}
+
+	setWebhookFormat(t, WebhookFormatChatwoot)
+	if body := webhookBody(context.Background(), payload, "group.participants"); body["format"] != nil {
+		t.Errorf("non-message event was reshaped: %v", body)
+	}
+
+	setWebhookFormat(t, WebhookFormatDefault)
+	message := map[string]any{"event": "message", "payload": map[string]any{"from": "628123456789@s.whatsapp.net", "body": "hi"}}
+	if body := webhookBody(context.Background(), message, "message"); body["format"] != nil {
+		t.Errorf("default format reshaped the message: %v", body)
+	}
+}
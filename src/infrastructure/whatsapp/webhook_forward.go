@@ -13,12 +13,14 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 )
 
 var (
 	submitWebhookFn     = submitWebhook
+	submitWebhookOnceFn = submitWebhookOnce
 	getChatwootClientFn = chatwoot.GetDefaultClient
 	// contactDisplayNameFn resolves the operator-saved address-book name for a
 	// 1:1 JID from the WhatsApp contact store. It is a seam so tests can stub
@@ -85,10 +87,12 @@ func getContactMutex(phone string) *sync.Mutex {
 // It only returns an error when all webhook deliveries fail. Partial failures are logged and suppressed so
 // successful targets still receive the event.
 func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string) error {
-	webhookAllowed := len(config.WhatsappWebhookEvents) == 0 || isEventWhitelisted(eventName)
+	webhookAllowed := len(config.Setting(&config.WhatsappWebhookEvents)) == 0 || isEventWhitelisted(eventName)
 	chatwootAllowed := config.ChatwootEnabled && shouldForwardEventToChatwoot(eventName) && isEventWhitelistedForChatwoot(eventName)
+	// /ws/events subscribers filter events themselves, regardless of WhatsappWebhookEvents.
+	subscribed := websocket.HasEventSubscribers()
 
-	if !webhookAllowed && !chatwootAllowed {
+	if !webhookAllowed && !chatwootAllowed && !subscribed {
 		logrus.Debugf("Skipping event %s - not allowed for webhooks or Chatwoot", eventName)
 		return nil
 	}
@@ -98,12 +102,18 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 	// back to the session id they registered via POST /devices. Done here,
 	// synchronously, before the Chatwoot goroutine is spawned below, so the
 	// shared payload map is never mutated concurrently.
-	if webhookAllowed {
+	if webhookAllowed || subscribed {
 		addWebhookSessionID(payload)
 	}
+	// The tenant id also selects per-tenant webhook URLs below, so tag it regardless.
+	addWebhookTenantID(payload)
+	if subscribed {
+		publishToEventSubscribers(payload, eventName)
+	}
 
 	var err error
 	if webhookAllowed {
+		publishEvent(ctx, payload, eventName)
 		err = forwardToWebhooks(ctx, payload, eventName)
 	} else {
 		logrus.Debugf("Skipping event %s for configured webhooks, but allowing Chatwoot", eventName)
@@ -152,36 +162,80 @@ func sessionIDForJID(jid string) string {
 }
 
 func forwardToWebhooks(ctx context.Context, payload map[string]any, eventName string) error {
-	total := len(config.WhatsappWebhook)
+	var urls []string
+	for _, url := range webhookURLsForPayload(payload) {
+		if !webhookFilterAllows(payload, eventName, url) {
+			logrus.Debugf("Skipping %s for %s: filtered out", eventName, url)
+			continue
+		}
+		urls = append(urls, url)
+	}
+	total := len(urls)
 	logrus.Infof("Forwarding %s to %d configured webhook(s)", eventName, total)
 
 	if total == 0 {
 		return nil
 	}
 
+	body := webhookBody(ctx, payload, eventName)
+	deviceID, _ := payload["device_id"].(string)
+	ctx = contextWithWebhookDelivery(ctx, webhookDeliveryInfo{deviceID: deviceID, event: eventName, source: webhookDeliverySource(ctx)})
 	var (
 		failed    []string
 		successes int
+		queued    int
+		batched   int
 	)
-	for _, url := range config.WhatsappWebhook {
-		if err := submitWebhookFn(ctx, payload, url); err != nil {
+	outbox := newWebhookOutboxPending(ctx)
+	for _, url := range urls {
+		urlBody, err := applyWebhookTemplate(body, url)
+		if err == nil {
+			urlBody, err = limitWebhookPayload(urlBody, url)
+		}
+		if err != nil {
+			// A broken template or unwritable media directory fails the same way
+			// on every retry, so it is not queued.
+			failed = append(failed, fmt.Sprintf("%s: %v", url, err))
+			logrus.Errorf("Failed forwarding %s to %s: %v", eventName, url, err)
+			continue
+		}
+		if webhookBatchingEnabled() {
+			// Batched events are handed off; the batch is retried as a whole.
+			enqueueWebhookBatch(ctx, deviceID, url, urlBody, outbox)
+			queued++
+			batched++
+			continue
+		}
+		if err := submitWebhookFn(ctx, urlBody, url); err != nil {
 			failed = append(failed, fmt.Sprintf("%s: %v", url, err))
 			logrus.Warnf("Failed forwarding %s to %s: %v", eventName, url, err)
+			if enqueueWebhookDelivery(ctx, urlBody, eventName, url, err) {
+				queued++
+			}
 			continue
 		}
 		successes++
 	}
 
 	if len(failed) > 0 {
-		logrus.Warnf("Some webhook URLs failed for %s (succeeded: %d/%d): %s", eventName, successes, total, strings.Join(failed, "; "))
-		// Return error only if ALL webhooks failed
-		if successes == 0 {
+		logrus.Warnf("Some webhook URLs failed for %s (succeeded: %d/%d, queued for retry: %d): %s", eventName, successes, total, queued, strings.Join(failed, "; "))
+		// Return error only if ALL webhooks failed. A failure queued for retry
+		// is handed off, so callers do not deliver the event a second time.
+		if successes == 0 && queued < total {
+			outbox.done(false)
 			return fmt.Errorf("all %d webhook(s) failed for %s", total, eventName)
 		}
+	} else if batched > 0 {
+		logrus.Infof("%s batched for %d webhook(s)", eventName, batched)
 	} else {
 		logrus.Infof("%s forwarded to all webhook(s)", eventName)
 	}
 
+	outbox.done(true)
+	if outbox != nil && batched > 0 {
+		// The outbox row stays open until the batches are posted.
+		return errWebhookOutboxPending
+	}
 	return nil
 }
 
@@ -208,9 +262,9 @@ type chatwootSyncResult struct {
 // For groups, uses the group JID as identifier and tries to fetch group name.
 // For private chats, uses the sender's phone number.
 func extractChatwootContactInfo(ctx context.Context, data map[string]any) (*chatwootContactInfo, error) {
-	from, _ := data["from"].(string)
+	from := webhookPhoneJID(data, webhookFromFields)
 	fromName, _ := data["from_name"].(string)
-	chatID, _ := data["chat_id"].(string)
+	chatID := webhookPhoneJID(data, webhookChatFields)
 	isFromMe, _ := data["is_from_me"].(bool)
 
 	logrus.Infof("Chatwoot: Processing message from %s (from_name: %s, chat_id: %s, is_from_me: %v)", from, fromName, chatID, isFromMe)
@@ -358,7 +412,7 @@ func buildChatwootMessageContent(data map[string]any, isGroup bool, fromName str
 	fromMe := chatwootMessageTypeFromPayload(data) == "outgoing"
 	senderLabel := fromName
 	if senderLabel == "" {
-		if from, ok := data["from"].(string); ok {
+		if from := webhookPhoneJID(data, webhookFromFields); from != "" {
 			senderLabel = utils.ExtractPhoneFromJID(from)
 		}
 	}
@@ -417,7 +471,7 @@ func buildChatwootMessageContent(data map[string]any, isGroup bool, fromName str
 
 func shouldForwardEventToChatwoot(eventName string) bool {
 	switch eventName {
-	case "message", "message.reaction":
+	case "message", "message.reaction", EventTypeMessageOutgoing:
 		return true
 	case "message.ack":
 		return config.ChatwootMessageRead
@@ -434,7 +488,7 @@ func shouldForwardEventToChatwoot(eventName string) bool {
 }
 
 func isEventWhitelistedForChatwoot(eventName string) bool {
-	if len(config.WhatsappWebhookEvents) == 0 {
+	if len(config.Setting(&config.WhatsappWebhookEvents)) == 0 {
 		return true
 	}
 	if isEventWhitelisted(eventName) {
@@ -444,7 +498,7 @@ func isEventWhitelistedForChatwoot(eventName string) bool {
 	// base "message" event is whitelisted, so operators don't have to enumerate
 	// every sub-event to get them mirrored to Chatwoot.
 	switch eventName {
-	case "message.reaction", "message.ack", "message.edited", "message.revoked", "message.deleted":
+	case "message.reaction", "message.ack", "message.edited", "message.revoked", "message.deleted", EventTypeMessageOutgoing:
 		return isEventWhitelisted("message")
 	}
 	return false
@@ -457,7 +511,7 @@ func buildReactionChatwootContent(data map[string]any, fromName string) string {
 	actor := "Someone"
 	if fromName != "" {
 		actor = fromName
-	} else if from, ok := data["from"].(string); ok && from != "" {
+	} else if from := webhookPhoneJID(data, webhookFromFields); from != "" {
 		actor = utils.ExtractPhoneFromJID(from)
 	}
 
@@ -671,7 +725,7 @@ func buildChatwootForwardMessageLink(deviceID string, data map[string]any, opts
 	if waMessageID == "" {
 		return nil
 	}
-	chatJID, _ := data["chat_id"].(string)
+	chatJID := webhookPhoneJID(data, webhookChatFields)
 
 	return &domainChatStorage.ChatwootMessageLink{
 		DeviceID:                     deviceID,
@@ -838,7 +892,7 @@ func truncateChatwootForwardError(err error) string {
 // receipt.
 func isRetryableChatwootForwardEvent(eventName string) bool {
 	switch eventName {
-	case "message", "message.edited", "message.revoked", "message.deleted", "message.reaction":
+	case "message", "message.edited", "message.revoked", "message.deleted", "message.reaction", EventTypeMessageOutgoing:
 		return true
 	default:
 		return false
@@ -893,6 +947,14 @@ func syncPayloadToChatwoot(ctx context.Context, payload map[string]any, eventNam
 		logrus.Error("Chatwoot: Invalid payload format (missing 'payload' object)")
 		return nil
 	}
+	if eventName == EventTypeMessageOutgoing {
+		// Messages sent through the API, including Chatwoot's own replies,
+		// are never mirrored; the phone's are synced like any message.
+		if sentFrom, _ := data["sent_from"].(string); sentFrom == OutgoingSentFromAPI {
+			return nil
+		}
+		eventName = EventTypeMessage
+	}
 
 	switch eventName {
 	case "message.ack":
@@ -918,39 +980,10 @@ func syncPayloadToChatwoot(ctx context.Context, payload map[string]any, eventNam
 		return nil
 	}
 
-	// Build message content. msgOpts threads WhatsApp identifiers into Chatwoot:
-	// SourceID stamps the live message with WAID:<id> (unifying dedup with the
-	// importer and anchoring future replies); ContentAttributes.in_reply_to_external_id
-	// threads reactions/edits/deletes/replies onto the message they reference.
-	var (
-		content     string
-		attachments []string
-		msgOpts     chatwoot.MessageOptions
-	)
-	switch eventName {
-	case "message.reaction":
-		content = buildReactionChatwootContent(data, info.FromName)
-		if rid, _ := data["reacted_message_id"].(string); rid != "" {
-			msgOpts.ContentAttributes = map[string]any{"in_reply_to_external_id": "WAID:" + rid}
-		}
-	case "message.edited", "message.revoked", "message.deleted":
-		var threadID string
-		content, threadID = buildEditDeleteChatwootContent(eventName, data, info.IsGroup, info.FromName)
-		if content == "" {
-			logrus.Debugf("Chatwoot: Skipping %s with no renderable content", eventName)
-			return nil
-		}
-		if threadID != "" {
-			msgOpts.ContentAttributes = map[string]any{"in_reply_to_external_id": "WAID:" + threadID}
-		}
-	default:
-		content, attachments = buildChatwootMessageContent(data, info.IsGroup, info.FromName)
-		if id, _ := data["id"].(string); id != "" {
-			msgOpts.SourceID = "WAID:" + id
-		}
-		if rid, _ := data["replied_to_id"].(string); rid != "" {
-			msgOpts.ContentAttributes = map[string]any{"in_reply_to_external_id": "WAID:" + rid}
-		}
+	content, attachments, msgOpts, ok := buildChatwootMessage(eventName, data, info)
+	if !ok {
+		logrus.Debugf("Chatwoot: Skipping %s with no renderable content", eventName)
+		return nil
 	}
 	info.IsFromMe = chatwootMessageTypeFromPayload(data) == "outgoing"
 
@@ -983,6 +1016,40 @@ func syncPayloadToChatwoot(ctx context.Context, payload map[string]any, eventNam
 	return nil
 }
 
+// buildChatwootMessage renders an event into the content, attachments and
+// options of a Chatwoot message; ok is false when there is nothing to post.
+// The options thread WhatsApp identifiers into Chatwoot: SourceID stamps the
+// message with WAID:<id> (unifying dedup with the importer and anchoring
+// future replies); ContentAttributes.in_reply_to_external_id threads
+// reactions/edits/deletes/replies onto the message they reference.
+func buildChatwootMessage(eventName string, data map[string]any, info *chatwootContactInfo) (content string, attachments []string, msgOpts chatwoot.MessageOptions, ok bool) {
+	switch eventName {
+	case "message.reaction":
+		content = buildReactionChatwootContent(data, info.FromName)
+		if rid, _ := data["reacted_message_id"].(string); rid != "" {
+			msgOpts.ContentAttributes = map[string]any{"in_reply_to_external_id": "WAID:" + rid}
+		}
+	case "message.edited", "message.revoked", "message.deleted":
+		var threadID string
+		content, threadID = buildEditDeleteChatwootContent(eventName, data, info.IsGroup, info.FromName)
+		if content == "" {
+			return "", nil, msgOpts, false
+		}
+		if threadID != "" {
+			msgOpts.ContentAttributes = map[string]any{"in_reply_to_external_id": "WAID:" + threadID}
+		}
+	default:
+		content, attachments = buildChatwootMessageContent(data, info.IsGroup, info.FromName)
+		if id, _ := data["id"].(string); id != "" {
+			msgOpts.SourceID = "WAID:" + id
+		}
+		if rid, _ := data["replied_to_id"].(string); rid != "" {
+			msgOpts.ContentAttributes = map[string]any{"in_reply_to_external_id": "WAID:" + rid}
+		}
+	}
+	return content, attachments, msgOpts, true
+}
+
 func forwardToChatwoot(ctx context.Context, payload map[string]any, eventName string) {
 	logrus.Infof("Chatwoot: Attempting to forward %s...", eventName)
 	deviceID, linkRepo := chatwootLinkStorageFromContext(ctx)
@@ -1074,7 +1141,7 @@ func buildEditDeleteChatwootContent(eventName string, data map[string]any, isGro
 
 // isEventWhitelisted checks if the given event name is in the configured whitelist
 func isEventWhitelisted(eventName string) bool {
-	for _, allowed := range config.WhatsappWebhookEvents {
+	for _, allowed := range config.Setting(&config.WhatsappWebhookEvents) {
 		if strings.EqualFold(strings.TrimSpace(allowed), eventName) {
 			return true
 		}
@@ -0,0 +1,131 @@
+package whatsapp
+
+import (
+	"context"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Webhook identifier strategies (WhatsappWebhookIdentifier): which of a
+// user's identifiers webhook payloads use for fields such as from and chat_id.
+const (
+	WebhookIdentifierPN   = "pn"
+	WebhookIdentifierLID  = "lid"
+	WebhookIdentifierBoth = "both"
+)
+
+// webhookJIDFields names the payload fields of one JID: the primary field,
+// and the fields holding its LID and its phone number.
+type webhookJIDFields struct {
+	primary, lid, pn string
+}
+
+var (
+	webhookFromFields        = webhookJIDFields{"from", "from_lid", "from_pn"}
+	webhookChatFields        = webhookJIDFields{"chat_id", "chat_lid", "chat_pn"}
+	webhookParticipantFields = webhookJIDFields{"participant", "participant_lid", "participant_pn"}
+)
+
+// webhookIdentifiers returns the phone number and the LID of a user JID,
+// either empty when unknown. The LID of a phone number is only looked up when
+// the strategy needs it, or when lookupLID is set.
+func webhookIdentifiers(ctx context.Context, client *whatsmeow.Client, jid types.JID, lookupLID bool) (pn, lid types.JID) {
+	jid = jid.ToNonAD()
+	switch jid.Server {
+	case types.HiddenUserServer:
+		if resolved := utils.ResolveLIDToPhone(ctx, jid, client).ToNonAD(); resolved.Server == types.DefaultUserServer {
+			pn = resolved
+		}
+		return pn, jid
+	case types.DefaultUserServer:
+		if lookupLID || config.Setting(&config.WhatsappWebhookIdentifier) != WebhookIdentifierPN {
+			lid = utils.ResolvePhoneToLID(ctx, jid, client).ToNonAD()
+		}
+		return jid, lid
+	}
+	return types.EmptyJID, types.EmptyJID
+}
+
+// webhookPrimaryJID returns the identifier primary fields use: the LID with
+// the lid strategy and the phone number otherwise, each falling back to the
+// other when unknown.
+func webhookPrimaryJID(pn, lid types.JID) types.JID {
+	if (config.Setting(&config.WhatsappWebhookIdentifier) == WebhookIdentifierLID && !lid.IsEmpty()) || pn.IsEmpty() {
+		return lid
+	}
+	return pn
+}
+
+// setWebhookJID sets the fields of jid in payload following the configured
+// WhatsappWebhookIdentifier. The primary field holds the identifier of
+// webhookPrimaryJID; with pn and lid the other identifier is added in its own
+// field when known, and with both the lid and pn fields are always present,
+// empty when unknown. Groups, newsletters and broadcasts keep their JID.
+func setWebhookJID(ctx context.Context, client *whatsmeow.Client, payload map[string]any, fields webhookJIDFields, jid types.JID, lookupLID bool) {
+	pn, lid := webhookIdentifiers(ctx, client, jid, lookupLID)
+	if pn.IsEmpty() && lid.IsEmpty() {
+		payload[fields.primary] = jid.ToNonAD().String()
+		return
+	}
+	payload[fields.primary] = webhookPrimaryJID(pn, lid).String()
+
+	switch config.Setting(&config.WhatsappWebhookIdentifier) {
+	case WebhookIdentifierBoth:
+		payload[fields.lid] = jidStringOrEmpty(lid)
+		payload[fields.pn] = jidStringOrEmpty(pn)
+	case WebhookIdentifierLID:
+		if !pn.IsEmpty() {
+			payload[fields.pn] = pn.String()
+		}
+	default:
+		if !lid.IsEmpty() {
+			payload[fields.lid] = lid.String()
+		}
+	}
+}
+
+// setWebhookJIDs sets a list of user JIDs in payload under field, each as
+// setWebhookJID would set a primary field. With the both strategy the LIDs and
+// phone numbers are added as field_lid and field_pn, in the same order and
+// empty when unknown.
+func setWebhookJIDs(ctx context.Context, client *whatsmeow.Client, payload map[string]any, field string, jids []types.JID) {
+	// Empty arrays instead of nil for consistent JSON
+	primary := make([]string, len(jids))
+	lids := make([]string, len(jids))
+	pns := make([]string, len(jids))
+	for i, jid := range jids {
+		pn, lid := webhookIdentifiers(ctx, client, jid, false)
+		if pn.IsEmpty() && lid.IsEmpty() {
+			primary[i] = jid.ToNonAD().String()
+			continue
+		}
+		primary[i] = webhookPrimaryJID(pn, lid).String()
+		lids[i], pns[i] = jidStringOrEmpty(lid), jidStringOrEmpty(pn)
+	}
+	payload[field] = primary
+	if config.Setting(&config.WhatsappWebhookIdentifier) == WebhookIdentifierBoth {
+		payload[field+"_lid"] = lids
+		payload[field+"_pn"] = pns
+	}
+}
+
+// webhookPhoneJID returns the phone number of a JID set in data with
+// setWebhookJID, whatever the strategy: its pn field when set, or else its
+// primary field. The Chatwoot sync keys contacts and conversations by it.
+func webhookPhoneJID(data map[string]any, fields webhookJIDFields) string {
+	if pn, _ := data[fields.pn].(string); pn != "" {
+		return pn
+	}
+	primary, _ := data[fields.primary].(string)
+	return primary
+}
+
+func jidStringOrEmpty(jid types.JID) string {
+	if jid.IsEmpty() {
+		return ""
+	}
+	return jid.String()
+}
@@ -0,0 +1,98 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// staticLIDMappings is a LIDMappingStore of fixed LID to phone number pairs.
+type staticLIDMappings map[string]string
+
+func (m staticLIDMappings) StoreLIDMapping(lid, phoneJID string) error {
+	m[lid] = phoneJID
+	return nil
+}
+
+func (m staticLIDMappings) LookupPhoneForLID(lid string) (string, error) {
+	return m[lid], nil
+}
+
+func (m staticLIDMappings) LookupLIDForPhone(phoneJID string) (string, error) {
+	for lid, pn := range m {
+		if pn == phoneJID {
+			return lid, nil
+		}
+	}
+	return "", nil
+}
+
+func withWebhookIdentifier(t *testing.T, identifier string) context.Context {
+	t.Helper()
+	prev := config.WhatsappWebhookIdentifier
+	config.WhatsappWebhookIdentifier = identifier
+	t.Cleanup(func() { config.WhatsappWebhookIdentifier = prev })
+	resolver := utils.NewLIDResolver(staticLIDMappings{"251556368777322@lid": "628987654321@s.whatsapp.net"})
+	return utils.ContextWithLIDResolver(context.Background(), resolver)
+}
+
+var (
+	knownLID     = types.NewJID("251556368777322", types.HiddenUserServer)
+	knownPN      = types.NewJID("628987654321", types.DefaultUserServer)
+	unknownLID   = types.NewJID("999999999999999", types.HiddenUserServer)
+	groupChatJID = types.NewJID("120363000000000001", types.GroupServer)
+)
+
+func TestSetWebhookJID(t *testing.T) {
+	for _, tt := range []struct {
+		identifier string
+		jid        types.JID
+		lookupLID  bool
+		want       map[string]any
+	}{
+		{WebhookIdentifierPN, knownLID, false, map[string]any{"from": "628987654321@s.whatsapp.net", "from_lid": "251556368777322@lid"}},
+		{WebhookIdentifierPN, knownPN, false, map[string]any{"from": "628987654321@s.whatsapp.net"}},
+		{WebhookIdentifierPN, knownPN, true, map[string]any{"from": "628987654321@s.whatsapp.net", "from_lid": "251556368777322@lid"}},
+		{WebhookIdentifierPN, unknownLID, false, map[string]any{"from": "999999999999999@lid", "from_lid": "999999999999999@lid"}},
+		{WebhookIdentifierLID, knownPN, false, map[string]any{"from": "251556368777322@lid", "from_pn": "628987654321@s.whatsapp.net"}},
+		{WebhookIdentifierLID, knownLID, false, map[string]any{"from": "251556368777322@lid", "from_pn": "628987654321@s.whatsapp.net"}},
+		{WebhookIdentifierLID, unknownLID, false, map[string]any{"from": "999999999999999@lid"}},
+		{WebhookIdentifierBoth, knownLID, false, map[string]any{"from": "628987654321@s.whatsapp.net", "from_lid": "251556368777322@lid", "from_pn": "628987654321@s.whatsapp.net"}},
+		{WebhookIdentifierBoth, unknownLID, false, map[string]any{"from": "999999999999999@lid", "from_lid": "999999999999999@lid", "from_pn": ""}},
+		{WebhookIdentifierBoth, groupChatJID, false, map[string]any{"from": "120363000000000001@g.us"}},
+	} {
+		ctx := withWebhookIdentifier(t, tt.identifier)
+		payload := map[string]any{}
+		setWebhookJID(ctx, nil, payload, webhookFromFields, tt.jid, tt.lookupLID)
+		assert.Equal(t, tt.want, payload, "%s %s", tt.identifier, tt.jid)
+	}
+}
+
+func TestSetWebhookJIDs(t *testing.T) {
+	jids := []types.JID{knownLID, unknownLID}
+
+	payload := map[string]any{}
+	setWebhookJIDs(withWebhookIdentifier(t, WebhookIdentifierPN), nil, payload, "jids", jids)
+	assert.Equal(t, map[string]any{"jids": []string{"628987654321@s.whatsapp.net", "999999999999999@lid"}}, payload)
+
+	payload = map[string]any{}
+	setWebhookJIDs(withWebhookIdentifier(t, WebhookIdentifierBoth), nil, payload, "jids", jids)
+	assert.Equal(t, map[string]any{
+		"jids":     []string{"628987654321@s.whatsapp.net", "999999999999999@lid"},
+		"jids_lid": []string{"251556368777322@lid", "999999999999999@lid"},
+		"jids_pn":  []string{"628987654321@s.whatsapp.net", ""},
+	}, payload)
+}
+
+func TestWebhookPhoneJIDIgnoresStrategy(t *testing.T) {
+	for _, identifier := range []string{WebhookIdentifierPN, WebhookIdentifierLID, WebhookIdentifierBoth} {
+		ctx := withWebhookIdentifier(t, identifier)
+		payload := map[string]any{}
+		setWebhookJID(ctx, nil, payload, webhookChatFields, knownLID, false)
+		assert.Equal(t, "628987654321@s.whatsapp.net", webhookPhoneJID(payload, webhookChatFields), identifier)
+	}
+}
@@ -0,0 +1,223 @@
+package whatsapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+// webhookOffloadMinSize is the smallest inline base64 value moved out of an
+// oversized payload; shorter strings are left alone.
+const webhookOffloadMinSize = 1024
+
+// webhookMediaDir holds the values offloaded from webhook payloads, served by
+// the signed /webhooks/media endpoint.
+func webhookMediaDir() string {
+	return filepath.Join(config.PathStorages, "webhook-media")
+}
+
+// ValidateWebhookMaxPayloadSizes reports the first WhatsappWebhookMaxPayloadSizes
+// entry that is not "<url>=<bytes>" with a positive size.
+func ValidateWebhookMaxPayloadSizes(raw []string) error {
+	for _, entry := range raw {
+		_, size, ok := splitWebhookURLEntry(entry)
+		if !ok {
+			return fmt.Errorf("invalid webhook payload size entry %q: expected <url>=<bytes>", entry)
+		}
+		if n, err := strconv.ParseInt(size, 10, 64); err != nil || n <= 0 {
+			return fmt.Errorf("invalid webhook payload size %q: must be a positive number of bytes", size)
+		}
+	}
+	return nil
+}
+
+// webhookMaxPayloadSize returns the largest body webhookURL accepts, 0 when
+// it has no limit.
+func webhookMaxPayloadSize(webhookURL string) int64 {
+	for _, entry := range config.Setting(&config.WhatsappWebhookMaxPayloadSizes) {
+		target, size, ok := splitWebhookURLEntry(entry)
+		if !ok || target != webhookURL {
+			continue
+		}
+		n, _ := strconv.ParseInt(size, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// inlineWebhookValue is a base64 string found in a payload, with the dotted
+// path of the field holding it.
+type inlineWebhookValue struct {
+	field  string
+	data   []byte
+	parent map[string]any
+	key    string
+}
+
+// limitWebhookPayload returns body as posted to webhookURL. When its JSON is
+// larger than the URL's limit, inline base64 values are written to
+// webhookMediaDir and replaced with signed download URLs, largest first, until
+// it fits; their fields are listed in "offloaded_fields".
+func limitWebhookPayload(body map[string]any, webhookURL string) (map[string]any, error) {
+	limit := webhookMaxPayloadSize(webhookURL)
+	if limit <= 0 {
+		return body, nil
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	size := int64(len(encoded))
+	if size <= limit {
+		return body, nil
+	}
+
+	// Work on a generic copy so structs such as protobuf messages expose
+	// their byte fields, and the caller's body is left untouched.
+	var limited map[string]any
+	if err := json.Unmarshal(encoded, &limited); err != nil {
+		return nil, fmt.Errorf("copy webhook payload: %w", err)
+	}
+	values := findInlineWebhookValues(limited, "")
+	sort.SliceStable(values, func(i, j int) bool { return len(values[i].data) > len(values[j].data) })
+
+	var offloaded []string
+	for _, value := range values {
+		if size <= limit {
+			break
+		}
+		link, err := storeWebhookMedia(value.data)
+		if err != nil {
+			return nil, err
+		}
+		size -= int64(base64.StdEncoding.EncodedLen(len(value.data)) - len(link))
+		value.parent[value.key] = link
+		offloaded = append(offloaded, value.field)
+	}
+	if len(offloaded) == 0 {
+		logrus.Warnf("Webhook payload for %s is %d bytes, over its %d byte limit, with nothing to offload", webhookURL, size, limit)
+		return body, nil
+	}
+	limited["offloaded_fields"] = offloaded
+	if size > limit {
+		logrus.Warnf("Webhook payload for %s is still about %d bytes after offloading %d field(s), over its %d byte limit", webhookURL, size, len(offloaded), limit)
+	}
+	return limited, nil
+}
+
+// findInlineWebhookValues returns the base64 strings of at least
+// webhookOffloadMinSize bytes in the objects of value.
+func findInlineWebhookValues(value any, prefix string) []inlineWebhookValue {
+	var found []inlineWebhookValue
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			field := key
+			if prefix != "" {
+				field = prefix + "." + key
+			}
+			if text, ok := child.(string); ok {
+				if len(text) < webhookOffloadMinSize {
+					continue
+				}
+				if data, err := base64.StdEncoding.DecodeString(text); err == nil {
+					found = append(found, inlineWebhookValue{field: field, data: data, parent: v, key: key})
+				}
+				continue
+			}
+			found = append(found, findInlineWebhookValues(child, field)...)
+		}
+	case []any:
+		for i, child := range v {
+			found = append(found, findInlineWebhookValues(child, prefix+"."+strconv.Itoa(i))...)
+		}
+	}
+	return found
+}
+
+// storeWebhookMedia writes data to webhookMediaDir, named by its content hash,
+// and returns a signed URL to download it.
+func storeWebhookMedia(data []byte) (string, error) {
+	dir := webhookMediaDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create webhook media directory: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])
+	if extensions, _ := mime.ExtensionsByType(http.DetectContentType(data)); len(extensions) > 0 {
+		name += extensions[0]
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return "", fmt.Errorf("store webhook media: %w", err)
+	}
+	now, ttl := time.Now(), config.WhatsappWebhookMediaURLTTL
+	go pruneWebhookMedia(dir, now.Add(-ttl))
+	return signedWebhookMediaURL(name, now.Add(ttl)), nil
+}
+
+// pruneWebhookMedia removes the offloaded files in dir written before cutoff,
+// whose links have expired.
+func pruneWebhookMedia(dir string, cutoff time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			logrus.Debugf("Webhook media: failed to remove expired %s: %v", entry.Name(), err)
+		}
+	}
+}
+
+// signedWebhookMediaURL returns the download URL of an offloaded file, valid
+// until expires. It is absolute when WhatsappWebhookMediaBaseURL is set.
+func signedWebhookMediaURL(name string, expires time.Time) string {
+	expiresAt := strconv.FormatInt(expires.Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", webhookMediaSignature(name, expiresAt))
+	return strings.TrimSuffix(config.WhatsappWebhookMediaBaseURL, "/") + config.AppBasePath +
+		"/webhooks/media/" + url.PathEscape(name) + "?" + query.Encode()
+}
+
+func webhookMediaSignature(name, expires string) string {
+	mac := hmac.New(sha256.New, []byte(config.WhatsappWebhookSecret))
+	mac.Write([]byte(name + "." + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookMediaFile checks the signature of a webhook media download and
+// returns the path of the file to serve.
+func WebhookMediaFile(name, expires, signature string, now time.Time) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", errors.New("invalid file name")
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return "", errors.New("link expired")
+	}
+	if !hmac.Equal([]byte(signature), []byte(webhookMediaSignature(name, expires))) {
+		return "", errors.New("invalid signature")
+	}
+	return filepath.Join(webhookMediaDir(), name), nil
+}
@@ -0,0 +1,83 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestLimitWebhookPayload_OffloadsInlineBase64(t *testing.T) {
+	prevStorages, prevSizes, prevBase := config.PathStorages, config.WhatsappWebhookMaxPayloadSizes, config.WhatsappWebhookMediaBaseURL
+	config.PathStorages = t.TempDir()
+	config.WhatsappWebhookMaxPayloadSizes = []string{"https://hook.example.com/in?a=b=4096"}
+	config.WhatsappWebhookMediaBaseURL = "https://gowa.example.com/"
+	t.Cleanup(func() {
+		config.PathStorages, config.WhatsappWebhookMaxPayloadSizes, config.WhatsappWebhookMediaBaseURL = prevStorages, prevSizes, prevBase
+	})
+
+	image := bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 2048)
+	body := map[string]any{
+		"event":   "connection.qr",
+		"payload": map[string]any{"qr_code": "2@abc", "qr_image": base64.StdEncoding.EncodeToString(image)},
+	}
+
+	if got, err := limitWebhookPayload(body, "https://other.example.com"); err != nil || got["offloaded_fields"] != nil {
+		t.Fatalf("URL without a limit: got %v, %v", got, err)
+	}
+
+	limited, err := limitWebhookPayload(body, "https://hook.example.com/in?a=b")
+	if err != nil {
+		t.Fatalf("limitWebhookPayload: %v", err)
+	}
+	if fields, _ := limited["offloaded_fields"].([]string); len(fields) != 1 || fields[0] != "payload.qr_image" {
+		t.Fatalf("offloaded_fields = %v", limited["offloaded_fields"])
+	}
+	payload := limited["payload"].(map[string]any)
+	if payload["qr_code"] != "2@abc" {
+		t.Errorf("small fields must stay inline, got %v", payload)
+	}
+	if _, ok := body["payload"].(map[string]any)["qr_image"].(string); !ok || body["offloaded_fields"] != nil {
+		t.Error("the caller's body must not be modified")
+	}
+
+	link, err := url.Parse(payload["qr_image"].(string))
+	if err != nil || link.Host != "gowa.example.com" || !strings.HasPrefix(link.Path, "/webhooks/media/") {
+		t.Fatalf("qr_image = %v, want a download link", payload["qr_image"])
+	}
+	name := path.Base(link.Path)
+	file, err := WebhookMediaFile(name, link.Query().Get("expires"), link.Query().Get("signature"), time.Now())
+	if err != nil {
+		t.Fatalf("WebhookMediaFile: %v", err)
+	}
+	if stored, err := os.ReadFile(file); err != nil || !bytes.Equal(stored, image) {
+		t.Errorf("stored file does not hold the offloaded value: %v", err)
+	}
+
+	if _, err := WebhookMediaFile(name, link.Query().Get("expires"), "bad", time.Now()); err == nil {
+		t.Error("a wrong signature must be rejected")
+	}
+	if _, err := WebhookMediaFile(name, link.Query().Get("expires"), link.Query().Get("signature"), time.Now().Add(48*time.Hour)); err == nil {
+		t.Error("an expired link must be rejected")
+	}
+	if _, err := WebhookMediaFile("../whatsapp.db", link.Query().Get("expires"), link.Query().Get("signature"), time.Now()); err == nil {
+		t.Error("paths outside the media directory must be rejected")
+	}
+}
+
+func TestValidateWebhookMaxPayloadSizes(t *testing.T) {
+	if err := ValidateWebhookMaxPayloadSizes([]string{"https://a.example.com=1048576"}); err != nil {
+		t.Errorf("valid entry rejected: %v", err)
+	}
+	for _, entry := range []string{"https://a.example.com", "https://a.example.com=1MB", "https://a.example.com=0"} {
+		if err := ValidateWebhookMaxPayloadSizes([]string{entry}); err == nil {
+			t.Errorf("%q: expected an error", entry)
+		}
+	}
+}
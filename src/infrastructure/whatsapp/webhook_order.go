@@ -0,0 +1,69 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// chatWebhookQueues holds the webhook deliveries waiting per device and chat.
+// A key is present while a worker drains it, so new deliveries for that chat
+// queue behind the running one instead of racing it.
+var chatWebhookQueues = struct {
+	sync.Mutex
+	pending map[string][]func()
+}{pending: make(map[string][]func())}
+
+// dispatchChatWebhook runs deliver in the background. Deliveries for the same
+// device and chat run one at a time in the order they were dispatched, so
+// consumers see a chat's events in the order WhatsApp emitted them; different
+// chats are still delivered concurrently. Event handlers must call it
+// synchronously, before returning, for that order to hold. Without a chat (or
+// with WhatsappWebhookChatOrdering off) deliver simply runs in a new goroutine.
+func dispatchChatWebhook(deviceID, chatJID string, deliver func()) {
+	if !config.Setting(&config.WhatsappWebhookChatOrdering) || chatJID == "" {
+		go deliver()
+		return
+	}
+
+	key := deviceID + "|" + chatJID
+	chatWebhookQueues.Lock()
+	queue, draining := chatWebhookQueues.pending[key]
+	chatWebhookQueues.pending[key] = append(queue, deliver)
+	chatWebhookQueues.Unlock()
+
+	if !draining {
+		go drainChatWebhookQueue(key)
+	}
+}
+
+func drainChatWebhookQueue(key string) {
+	for {
+		chatWebhookQueues.Lock()
+		queue := chatWebhookQueues.pending[key]
+		if len(queue) == 0 {
+			delete(chatWebhookQueues.pending, key)
+			chatWebhookQueues.Unlock()
+			return
+		}
+		deliver := queue[0]
+		queue[0] = nil
+		chatWebhookQueues.pending[key] = queue[1:]
+		chatWebhookQueues.Unlock()
+
+		deliver()
+	}
+}
+
+// webhookDeviceID returns the device JID used to scope webhook ordering for
+// handlers that are not given it explicitly.
+func webhookDeviceID(ctx context.Context) string {
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		if jid := inst.JID(); jid != "" {
+			return jid
+		}
+		return inst.ID()
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package whatsapp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestDispatchChatWebhook_SerializesPerChat(t *testing.T) {
+	prev := config.WhatsappWebhookChatOrdering
+	config.WhatsappWebhookChatOrdering = true
+	t.Cleanup(func() { config.WhatsappWebhookChatOrdering = prev })
+
+	var (
+		mu        sync.Mutex
+		delivered = map[string][]int{}
+		wg        sync.WaitGroup
+	)
+	chats := []string{"628111@s.whatsapp.net", "628222@s.whatsapp.net"}
+	for i := 0; i < 50; i++ {
+		for _, chat := range chats {
+			wg.Add(1)
+			dispatchChatWebhook("device-a", chat, func() {
+				defer wg.Done()
+				// Earlier deliveries are the slow ones; order must still hold.
+				time.Sleep(time.Duration(50-i) * 20 * time.Microsecond)
+				mu.Lock()
+				delivered[chat] = append(delivered[chat], i)
+				mu.Unlock()
+			})
+		}
+	}
+	wg.Wait()
+
+	for _, chat := range chats {
+		got := delivered[chat]
+		if len(got) != 50 {
+			t.Fatalf("%s: got %d deliveries, want 50", chat, len(got))
+		}
+		for i, seq := range got {
+			if seq != i {
+				t.Fatalf("%s: delivery %d was event %d, want in-order delivery: %v", chat, i, seq, got)
+			}
+		}
+	}
+
+	// Workers exit once their queue is empty.
+	deadline := time.Now().Add(time.Second)
+	for {
+		chatWebhookQueues.Lock()
+		left := len(chatWebhookQueues.pending)
+		chatWebhookQueues.Unlock()
+		if left == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d chat queues left after draining, want 0", left)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,234 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// webhookOutboxInterval is how often the dispatcher looks for due events.
+	webhookOutboxInterval = 15 * time.Second
+	// webhookOutboxRetention is how long delivered events are kept, so a
+	// message WhatsApp delivers again is not recorded and sent a second time.
+	webhookOutboxRetention = 24 * time.Hour
+)
+
+// errWebhookOutboxPending is returned by forwardToWebhooks when the event of an
+// outbox row went into a webhook batch. The row stays open and is marked done
+// once every batch carrying the event is posted.
+var errWebhookOutboxPending = errors.New("webhook event batched, awaiting batch post")
+
+// webhookOutboxRef names the outbox row of the event being delivered.
+type webhookOutboxRef struct {
+	repo      domainChatStorage.IChatStorageRepository
+	deviceID  string
+	messageID string
+	eventName string
+}
+
+type webhookOutboxKey struct{}
+
+// contextWithWebhookOutbox marks the delivery in ctx as the one of an outbox
+// row, so a batched event can close the row after its batch is posted.
+func contextWithWebhookOutbox(ctx context.Context, ref webhookOutboxRef) context.Context {
+	if !config.WhatsappWebhookOutbox || ref.repo == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, webhookOutboxKey{}, ref)
+}
+
+// webhookOutboxPending tracks the batches carrying the event of an outbox row.
+// The row is marked done when the last one is posted, unless one failed.
+type webhookOutboxPending struct {
+	ref       webhookOutboxRef
+	mu        sync.Mutex
+	remaining int
+	failed    bool
+}
+
+// newWebhookOutboxPending returns the tracker of the outbox row in ctx, or nil
+// when the delivery has none. The caller holds one reference until it has
+// added every batch.
+func newWebhookOutboxPending(ctx context.Context) *webhookOutboxPending {
+	ref, ok := ctx.Value(webhookOutboxKey{}).(webhookOutboxRef)
+	if !ok {
+		return nil
+	}
+	return &webhookOutboxPending{ref: ref, remaining: 1}
+}
+
+func (p *webhookOutboxPending) add() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.remaining++
+	p.mu.Unlock()
+}
+
+// done releases one reference; posted reports whether its delivery succeeded.
+func (p *webhookOutboxPending) done(posted bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.failed = p.failed || !posted
+	p.remaining--
+	complete := p.remaining == 0 && !p.failed
+	p.mu.Unlock()
+
+	if complete {
+		if err := p.ref.repo.MarkOutboxEventDone(p.ref.deviceID, p.ref.messageID, p.ref.eventName); err != nil {
+			logrus.Warnf("Webhook outbox: failed to mark message %s delivered: %v", p.ref.messageID, err)
+		}
+	}
+}
+
+// StartWebhookOutboxDispatcher delivers the outbox events whose live delivery
+// did not complete, for example because the process stopped right after the
+// message was stored or every webhook failed. Payloads are rebuilt from the
+// stored message.
+func StartWebhookOutboxDispatcher(ctx context.Context, repo domainChatStorage.IChatStorageRepository) {
+	if repo == nil || !config.WhatsappWebhookOutbox {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(webhookOutboxInterval)
+		defer ticker.Stop()
+		for {
+			dispatchDueOutboxEvents(ctx, repo)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func dispatchDueOutboxEvents(ctx context.Context, repo domainChatStorage.IChatStorageRepository) {
+	events, err := repo.ListDueOutboxEvents(time.Now(), 50)
+	if err != nil {
+		logrus.Errorf("Webhook outbox: failed to list due events: %v", err)
+		return
+	}
+	for _, event := range events {
+		err := deliverOutboxEvent(ctx, repo, event)
+		if errors.Is(err, errWebhookOutboxPending) {
+			// The batch marks the event done once posted; look again later
+			// in case it fails.
+			nextAttempt := time.Now().Add(webhookOutboxRetryDelay(event.Attempts + 1))
+			if markErr := repo.MarkOutboxEventFailed(event.ID, err.Error(), nextAttempt); markErr != nil {
+				logrus.Errorf("Webhook outbox: failed to reschedule event %d: %v", event.ID, markErr)
+			}
+			continue
+		}
+		if err != nil {
+			nextAttempt := time.Now().Add(webhookOutboxRetryDelay(event.Attempts + 1))
+			if markErr := repo.MarkOutboxEventFailed(event.ID, truncateChatwootForwardError(err), nextAttempt); markErr != nil {
+				logrus.Errorf("Webhook outbox: failed to reschedule event %d: %v", event.ID, markErr)
+			}
+			logrus.Warnf("Webhook outbox: event %d for message %s failed, next attempt at %s: %v", event.ID, event.MessageID, nextAttempt.Format(time.RFC3339), err)
+			continue
+		}
+		if err := repo.MarkOutboxEventDone(event.DeviceID, event.MessageID, event.EventName); err != nil {
+			logrus.Errorf("Webhook outbox: failed to mark event %d done: %v", event.ID, err)
+		}
+	}
+
+	if pruned, err := repo.PruneOutboxEvents(time.Now().Add(-webhookOutboxRetention)); err != nil {
+		logrus.Warnf("Webhook outbox: failed to prune delivered events: %v", err)
+	} else if pruned > 0 {
+		logrus.Debugf("Webhook outbox: pruned %d delivered events", pruned)
+	}
+}
+
+// deliverOutboxEvent sends one outbox event to the configured webhooks.
+// Chatwoot is left out: it has its own retry queue for live events.
+func deliverOutboxEvent(ctx context.Context, repo domainChatStorage.IChatStorageRepository, event *domainChatStorage.OutboxEvent) error {
+	if len(config.Setting(&config.WhatsappWebhookEvents)) > 0 && !isEventWhitelisted(event.EventName) {
+		return nil
+	}
+
+	message, err := repo.GetMessageByIDAndDevice(event.DeviceID, event.MessageID)
+	if err != nil {
+		return fmt.Errorf("load message %s: %w", event.MessageID, err)
+	}
+	if message == nil {
+		logrus.Infof("Webhook outbox: message %s is no longer stored, dropping event %d", event.MessageID, event.ID)
+		return nil
+	}
+
+	payload := buildOutboxWebhookPayload(event, message)
+	addWebhookSessionID(payload)
+	addWebhookTenantID(payload)
+
+	deliverCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	deliverCtx = contextWithWebhookDelivery(deliverCtx, webhookDeliveryInfo{source: WebhookDeliverySourceOutbox})
+	deliverCtx = contextWithWebhookOutbox(deliverCtx, webhookOutboxRef{
+		repo:      repo,
+		deviceID:  event.DeviceID,
+		messageID: event.MessageID,
+		eventName: event.EventName,
+	})
+	return forwardToWebhooks(deliverCtx, payload, event.EventName)
+}
+
+// buildOutboxWebhookPayload rebuilds a webhook payload from a stored message.
+// It carries the fields chat storage keeps, which are a subset of those sent
+// live (no push name, mentions resolution or media download path).
+func buildOutboxWebhookPayload(event *domainChatStorage.OutboxEvent, message *domainChatStorage.Message) map[string]any {
+	data := map[string]any{
+		"id":         message.ID,
+		"timestamp":  message.Timestamp.Format(time.RFC3339),
+		"is_from_me": message.IsFromMe,
+		"chat_id":    message.ChatJID,
+		"from":       message.Sender,
+	}
+	if message.Content != "" {
+		data["body"] = message.Content
+	}
+	if message.MediaType != "" {
+		data["media_type"] = message.MediaType
+	}
+	if message.Filename != "" {
+		data["filename"] = message.Filename
+	}
+	if message.QuotedMessageID != "" {
+		data["replied_to_id"] = message.QuotedMessageID
+	}
+
+	return map[string]any{
+		"event":     event.EventName,
+		"device_id": event.DeviceID,
+		"payload":   data,
+	}
+}
+
+// webhookOutboxRetryDelay doubles from one minute up to an hour.
+func webhookOutboxRetryDelay(attempt int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempt && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
+
+// markWebhookOutboxDone records the live delivery of a stored message so the
+// dispatcher does not send it again.
+func markWebhookOutboxDone(repo domainChatStorage.IChatStorageRepository, deviceID, messageID string) {
+	if !config.WhatsappWebhookOutbox || repo == nil {
+		return
+	}
+	if err := repo.MarkOutboxEventDone(deviceID, messageID, EventTypeMessage); err != nil {
+		logrus.Warnf("Webhook outbox: failed to mark message %s delivered: %v", messageID, err)
+	}
+}
@@ -0,0 +1,99 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookOutboxTestRepo struct {
+	domainChatStorage.IChatStorageRepository
+	due      []*domainChatStorage.OutboxEvent
+	messages map[string]*domainChatStorage.Message
+	done     []string
+	failed   map[int64]string
+}
+
+func (r *webhookOutboxTestRepo) ListDueOutboxEvents(time.Time, int) ([]*domainChatStorage.OutboxEvent, error) {
+	return r.due, nil
+}
+
+func (r *webhookOutboxTestRepo) GetMessageByIDAndDevice(_, id string) (*domainChatStorage.Message, error) {
+	return r.messages[id], nil
+}
+
+func (r *webhookOutboxTestRepo) MarkOutboxEventDone(_, messageID, _ string) error {
+	r.done = append(r.done, messageID)
+	return nil
+}
+
+func (r *webhookOutboxTestRepo) MarkOutboxEventFailed(id int64, lastError string, _ time.Time) error {
+	r.failed[id] = lastError
+	return nil
+}
+
+func (r *webhookOutboxTestRepo) PruneOutboxEvents(time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestDispatchDueOutboxEvents(t *testing.T) {
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://hook"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	chatJID := "628123456789@s.whatsapp.net"
+	repo := &webhookOutboxTestRepo{
+		due: []*domainChatStorage.OutboxEvent{
+			{ID: 1, DeviceID: "628111@s.whatsapp.net", ChatJID: chatJID, MessageID: "m1", EventName: EventTypeMessage},
+			{ID: 2, DeviceID: "628111@s.whatsapp.net", ChatJID: chatJID, MessageID: "gone", EventName: EventTypeMessage},
+			{ID: 3, DeviceID: "628111@s.whatsapp.net", ChatJID: chatJID, MessageID: "m3", EventName: EventTypeMessage},
+		},
+		messages: map[string]*domainChatStorage.Message{
+			"m1": {ID: "m1", ChatJID: chatJID, Sender: chatJID, Content: "hello", Timestamp: time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)},
+			"m3": {ID: "m3", ChatJID: chatJID, Sender: chatJID, MediaType: "image", Filename: "photo.jpg"},
+		},
+		failed: map[int64]string{},
+	}
+
+	originalSubmit := submitWebhookFn
+	var delivered []map[string]any
+	submitWebhookFn = func(_ context.Context, payload map[string]any, _ string) error {
+		data := payload["payload"].(map[string]any)
+		if data["id"] == "m3" {
+			return errors.New("boom")
+		}
+		delivered = append(delivered, payload)
+		return nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	dispatchDueOutboxEvents(context.Background(), repo)
+
+	// A message no longer stored has nothing to deliver and is marked done.
+	assert.Equal(t, []string{"m1", "gone"}, repo.done)
+	assert.Contains(t, repo.failed, int64(3))
+
+	require.Len(t, delivered, 1)
+	assert.Equal(t, EventTypeMessage, delivered[0]["event"])
+	assert.Equal(t, "628111@s.whatsapp.net", delivered[0]["device_id"])
+	assert.Equal(t, map[string]any{
+		"id":         "m1",
+		"timestamp":  "2026-10-01T09:00:00Z",
+		"is_from_me": false,
+		"chat_id":    chatJID,
+		"from":       chatJID,
+		"body":       "hello",
+	}, delivered[0]["payload"])
+}
+
+func TestWebhookOutboxRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, webhookOutboxRetryDelay(1))
+	assert.Equal(t, 4*time.Minute, webhookOutboxRetryDelay(3))
+	assert.Equal(t, time.Hour, webhookOutboxRetryDelay(20))
+}
@@ -0,0 +1,80 @@
+package whatsapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+// Webhook signature algorithms.
+const (
+	WebhookSignatureSHA256 = "sha256"
+	WebhookSignatureSHA512 = "sha512"
+)
+
+// ValidateWebhookSignatureAlgorithm checks a WhatsappWebhookSignatureAlgorithm value.
+func ValidateWebhookSignatureAlgorithm(algorithm string) error {
+	switch algorithm {
+	case WebhookSignatureSHA256, WebhookSignatureSHA512:
+		return nil
+	}
+	return fmt.Errorf("must be sha256 or sha512, got %q", algorithm)
+}
+
+// signWebhookRequest sets the signature headers of a request posting body:
+// X-Hub-Signature-256 over the body alone, kept for existing consumers, and
+// X-Webhook-Signature over "<timestamp>.<body>" with every active secret, so
+// consumers can reject replays and keep verifying while secrets are rotated.
+func signWebhookRequest(req *http.Request, body []byte, now time.Time) error {
+	signature, err := utils.GetMessageDigestOrSignature(body, []byte(config.WhatsappWebhookSecret))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Hub-Signature-256", "sha256="+signature)
+
+	algorithm := config.Setting(&config.WhatsappWebhookSignatureAlgorithm)
+	if algorithm == "" {
+		algorithm = WebhookSignatureSHA256
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	parts := []string{"t=" + timestamp}
+	for _, secret := range []string{config.WhatsappWebhookSecret, config.WhatsappWebhookSecretPrevious} {
+		if secret == "" {
+			continue
+		}
+		signature, err := timestampedWebhookSignature(algorithm, secret, timestamp, body)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, algorithm+"="+signature)
+	}
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", strings.Join(parts, ","))
+	return nil
+}
+
+func timestampedWebhookSignature(algorithm, secret, timestamp string, body []byte) (string, error) {
+	var newHash func() hash.Hash
+	switch algorithm {
+	case WebhookSignatureSHA256:
+		newHash = sha256.New
+	case WebhookSignatureSHA512:
+		newHash = sha512.New
+	default:
+		return "", ValidateWebhookSignatureAlgorithm(algorithm)
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,57 @@
+package whatsapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+func TestSignWebhookRequest_SignsTimestampWithEverySecret(t *testing.T) {
+	prevSecret, prevPrevious, prevAlgorithm := config.WhatsappWebhookSecret, config.WhatsappWebhookSecretPrevious, config.WhatsappWebhookSignatureAlgorithm
+	config.WhatsappWebhookSecret, config.WhatsappWebhookSecretPrevious, config.WhatsappWebhookSignatureAlgorithm = "new-secret", "old-secret", WebhookSignatureSHA512
+	t.Cleanup(func() {
+		config.WhatsappWebhookSecret, config.WhatsappWebhookSecretPrevious, config.WhatsappWebhookSignatureAlgorithm = prevSecret, prevPrevious, prevAlgorithm
+	})
+
+	body := []byte(`{"event":"message"}`)
+	req, _ := http.NewRequest(http.MethodPost, "https://hooks.example.com", nil)
+	if err := signWebhookRequest(req, body, time.Unix(1760601600, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, _ := utils.GetMessageDigestOrSignature(body, []byte("new-secret"))
+	if got := req.Header.Get("X-Hub-Signature-256"); got != "sha256="+legacy {
+		t.Errorf("X-Hub-Signature-256 = %q, want the body-only signature", got)
+	}
+	if got := req.Header.Get("X-Webhook-Timestamp"); got != "1760601600" {
+		t.Errorf("X-Webhook-Timestamp = %q", got)
+	}
+
+	expected := func(secret string) string {
+		mac := hmac.New(sha512.New, []byte(secret))
+		mac.Write([]byte("1760601600." + string(body)))
+		return "sha512=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	want := strings.Join([]string{"t=1760601600", expected("new-secret"), expected("old-secret")}, ",")
+	if got := req.Header.Get("X-Webhook-Signature"); got != want {
+		t.Errorf("X-Webhook-Signature = %q, want %q", got, want)
+	}
+}
+
+func TestValidateWebhookSignatureAlgorithm(t *testing.T) {
+	for _, algorithm := range []string{WebhookSignatureSHA256, WebhookSignatureSHA512} {
+		if err := ValidateWebhookSignatureAlgorithm(algorithm); err != nil {
+			t.Errorf("%s: %v", algorithm, err)
+		}
+	}
+	if err := ValidateWebhookSignatureAlgorithm("md5"); err == nil {
+		t.Error("md5 should be rejected")
+	}
+}
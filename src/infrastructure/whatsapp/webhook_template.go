@@ -0,0 +1,107 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// webhookTemplateFuncs are available to webhook templates on top of the
+// text/template builtins.
+var webhookTemplateFuncs = template.FuncMap{
+	// json renders a value as JSON, e.g. {{ json .payload.body }} for a quoted string.
+	"json": func(v any) (string, error) {
+		encoded, err := json.Marshal(v)
+		return string(encoded), err
+	},
+}
+
+var (
+	webhookTemplatesMu sync.Mutex
+	// webhookTemplates caches parsed templates by "<url>=<file>" entry, so a
+	// changed WhatsappWebhookTemplates picks up its files on first use.
+	webhookTemplates = map[string]*template.Template{}
+)
+
+// splitWebhookURLEntry splits a per-URL "<url>=<value>" entry at the last "=",
+// since the URL may carry its own query string.
+func splitWebhookURLEntry(entry string) (webhookURL, value string, ok bool) {
+	entry = strings.TrimSpace(entry)
+	i := strings.LastIndex(entry, "=")
+	if i < 0 {
+		return "", "", false
+	}
+	webhookURL, value = strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:])
+	return webhookURL, value, webhookURL != "" && value != ""
+}
+
+func parseWebhookTemplate(path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(path).Funcs(webhookTemplateFuncs).Parse(string(text))
+}
+
+// ValidateWebhookTemplates reports the first WhatsappWebhookTemplates entry
+// that is malformed or whose template file does not parse.
+func ValidateWebhookTemplates(raw []string) error {
+	for _, entry := range raw {
+		_, path, ok := splitWebhookURLEntry(entry)
+		if !ok {
+			return fmt.Errorf("invalid webhook template entry %q: expected <url>=<template file>", entry)
+		}
+		if _, err := parseWebhookTemplate(path); err != nil {
+			return fmt.Errorf("webhook template %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// webhookTemplateFor returns the template configured for webhookURL, or nil
+// when the URL posts the payload unchanged.
+func webhookTemplateFor(webhookURL string) (*template.Template, error) {
+	for _, entry := range config.Setting(&config.WhatsappWebhookTemplates) {
+		target, path, ok := splitWebhookURLEntry(entry)
+		if !ok || target != webhookURL {
+			continue
+		}
+		webhookTemplatesMu.Lock()
+		defer webhookTemplatesMu.Unlock()
+		if tmpl, ok := webhookTemplates[entry]; ok {
+			return tmpl, nil
+		}
+		tmpl, err := parseWebhookTemplate(path)
+		if err != nil {
+			return nil, fmt.Errorf("webhook template %q: %w", path, err)
+		}
+		webhookTemplates[entry] = tmpl
+		return tmpl, nil
+	}
+	return nil, nil
+}
+
+// applyWebhookTemplate renders the template configured for webhookURL with
+// body as its data. The output must be a JSON object, which replaces body.
+// URLs without a template get body back unchanged.
+func applyWebhookTemplate(body map[string]any, webhookURL string) (map[string]any, error) {
+	tmpl, err := webhookTemplateFor(webhookURL)
+	if err != nil || tmpl == nil {
+		return body, err
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, body); err != nil {
+		return nil, fmt.Errorf("render webhook template: %w", err)
+	}
+	var transformed map[string]any
+	if err := json.Unmarshal(rendered.Bytes(), &transformed); err != nil {
+		return nil, fmt.Errorf("webhook template did not render a JSON object: %w", err)
+	}
+	return transformed, nil
+}
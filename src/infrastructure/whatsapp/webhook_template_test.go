@@ -0,0 +1,78 @@
+package whatsapp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func writeWebhookTemplate(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.tmpl")
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestForwardToWebhooks_AppliesTemplatePerURL(t *testing.T) {
+	path := writeWebhookTemplate(t, `{"text": {{ json .payload.body }}, "from": {{ json .payload.from }}, "kind": "{{ .event }}"}`)
+	templated := "https://n8n.example.com/webhook/wa?token=abc"
+
+	prevWebhooks, prevTemplates := config.WhatsappWebhook, config.WhatsappWebhookTemplates
+	config.WhatsappWebhook = []string{templated, "https://raw.example.com"}
+	config.WhatsappWebhookTemplates = []string{templated + "=" + path}
+	defer func() { config.WhatsappWebhook, config.WhatsappWebhookTemplates = prevWebhooks, prevTemplates }()
+
+	received := map[string]map[string]any{}
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(_ context.Context, payload map[string]any, url string) error {
+		received[url] = payload
+		return nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	payload := map[string]any{
+		"event":   "message",
+		"payload": map[string]any{"from": "628123456789@s.whatsapp.net", "body": `say "hi"`},
+	}
+	if err := forwardToWebhooks(context.Background(), payload, "message"); err != nil {
+		t.Fatalf("forward failed: %v", err)
+	}
+
+	got := received[templated]
+	if got["text"] != `say "hi"` || got["from"] != "628123456789@s.whatsapp.net" || got["kind"] != "message" || len(got) != 3 {
+		t.Errorf("templated body = %v", got)
+	}
+	if received["https://raw.example.com"]["event"] != "message" {
+		t.Errorf("untemplated URL got %v", received["https://raw.example.com"])
+	}
+}
+
+func TestApplyWebhookTemplate_RejectsNonObjectOutput(t *testing.T) {
+	path := writeWebhookTemplate(t, `{{ .event }}`)
+	prev := config.WhatsappWebhookTemplates
+	config.WhatsappWebhookTemplates = []string{"https://hook.example.com=" + path}
+	defer func() { config.WhatsappWebhookTemplates = prev }()
+
+	if _, err := applyWebhookTemplate(map[string]any{"event": "message"}, "https://hook.example.com"); err == nil {
+		t.Fatal("expected an error for output that is not a JSON object")
+	}
+}
+
+func TestValidateWebhookTemplates(t *testing.T) {
+	valid := writeWebhookTemplate(t, `{"event": {{ json .event }}}`)
+	broken := writeWebhookTemplate(t, `{"event": {{ .event }`)
+
+	if err := ValidateWebhookTemplates([]string{"https://hook.example.com?a=b=" + valid}); err != nil {
+		t.Errorf("valid entry rejected: %v", err)
+	}
+	for _, entry := range []string{"https://hook.example.com", "=" + valid, "https://hook.example.com=" + broken, "https://hook.example.com=/nonexistent.tmpl"} {
+		if err := ValidateWebhookTemplates([]string{entry}); err == nil {
+			t.Errorf("%q: expected an error", entry)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+)
+
+// tenantRouteWildcard matches every chat; useful as a trailing default route.
+const tenantRouteWildcard = "*"
+
+// parseTenantPairs splits "<tenant>=<value>" entries, skipping malformed ones.
+func parseTenantPairs(raw []string) [][2]string {
+	pairs := make([][2]string, 0, len(raw))
+	for _, entry := range raw {
+		tenant, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		tenant, value = strings.TrimSpace(tenant), strings.TrimSpace(value)
+		if !found || tenant == "" || value == "" {
+			continue
+		}
+		pairs = append(pairs, [2]string{tenant, value})
+	}
+	return pairs
+}
+
+// ValidateTenantPairs reports the first "<tenant>=<value>" entry in raw that
+// parseTenantPairs would skip.
+func ValidateTenantPairs(raw []string) error {
+	for _, entry := range raw {
+		tenant, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || strings.TrimSpace(tenant) == "" || strings.TrimSpace(value) == "" {
+			return fmt.Errorf("invalid tenant entry %q: expected <tenant>=<value>", entry)
+		}
+	}
+	return nil
+}
+
+// tenantForChat returns the tenant of the first WhatsappTenantRoutes rule matching
+// chatJID, or "" when no rule matches.
+func tenantForChat(chatJID string) string {
+	if chatJID == "" {
+		return ""
+	}
+	for _, route := range parseTenantPairs(config.Setting(&config.WhatsappTenantRoutes)) {
+		if route[1] == tenantRouteWildcard || utils.MatchesIgnoredJID(chatJID, []string{route[1]}) {
+			return route[0]
+		}
+	}
+	return ""
+}
+
+// tenantWebhookURLs returns the webhook URLs configured for a tenant.
+func tenantWebhookURLs(tenant string) []string {
+	if tenant == "" {
+		return nil
+	}
+	var urls []string
+	for _, pair := range parseTenantPairs(config.Setting(&config.WhatsappTenantWebhooks)) {
+		if pair[0] == tenant {
+			urls = append(urls, pair[1])
+		}
+	}
+	return urls
+}
+
+// hasWebhookTargets reports whether any webhook URL is configured, globally or
+// per tenant, or an event bus or /ws/events subscriber receives the events instead.
+func hasWebhookTargets() bool {
+	return len(config.Setting(&config.WhatsappWebhook)) > 0 || len(parseTenantPairs(config.Setting(&config.WhatsappTenantWebhooks))) > 0 ||
+		eventPublisher != nil || websocket.HasEventSubscribers()
+}
+
+// webhookChatJID extracts the chat an event belongs to from a webhook body,
+// falling back to the sender for events without a chat_id.
+func webhookChatJID(payload map[string]any) string {
+	data, _ := payload["payload"].(map[string]any)
+	if chatID, _ := data["chat_id"].(string); chatID != "" {
+		return chatID
+	}
+	from, _ := data["from"].(string)
+	return from
+}
+
+// addWebhookTenantID injects the routed tenant id into a webhook payload. It is a
+// no-op when no route matches or tenant_id is already present.
+func addWebhookTenantID(payload map[string]any) {
+	if payload == nil || len(config.Setting(&config.WhatsappTenantRoutes)) == 0 {
+		return
+	}
+	if _, exists := payload["tenant_id"]; exists {
+		return
+	}
+	if tenant := tenantForChat(webhookChatJID(payload)); tenant != "" {
+		payload["tenant_id"] = tenant
+	}
+}
+
+// webhookURLsForPayload picks the delivery targets for a payload: the tenant's own
+// URLs when it has any, otherwise the global WhatsappWebhook list.
+func webhookURLsForPayload(payload map[string]any) []string {
+	tenant, _ := payload["tenant_id"].(string)
+	if urls := tenantWebhookURLs(tenant); len(urls) > 0 {
+		return urls
+	}
+	return config.Setting(&config.WhatsappWebhook)
+}
@@ -0,0 +1,98 @@
+package whatsapp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func setTenantConfig(t *testing.T, routes, webhooks, global []string) {
+	t.Helper()
+	prevRoutes, prevWebhooks, prevGlobal := config.WhatsappTenantRoutes, config.WhatsappTenantWebhooks, config.WhatsappWebhook
+	config.WhatsappTenantRoutes, config.WhatsappTenantWebhooks, config.WhatsappWebhook = routes, webhooks, global
+	t.Cleanup(func() {
+		config.WhatsappTenantRoutes, config.WhatsappTenantWebhooks, config.WhatsappWebhook = prevRoutes, prevWebhooks, prevGlobal
+	})
+}
+
+func TestTenantForChat_FirstMatchingRuleWins(t *testing.T) {
+	setTenantConfig(t, []string{"acme=120363000000000001@g.us", "broken", "globex=@g.us", "=@lid", "initech=*"}, nil, nil)
+
+	cases := map[string]string{
+		"120363000000000001@g.us":     "acme",
+		"120363000000000002@g.us":     "globex",
+		"628123456789@s.whatsapp.net": "initech",
+		"":                            "",
+	}
+	for jid, want := range cases {
+		if got := tenantForChat(jid); got != want {
+			t.Errorf("tenantForChat(%q) = %q, want %q", jid, got, want)
+		}
+	}
+}
+
+func TestAddWebhookTenantID_UsesChatThenSender(t *testing.T) {
+	setTenantConfig(t, []string{"acme=@g.us", "globex=628123456789@s.whatsapp.net"}, nil, nil)
+
+	group := map[string]any{"payload": map[string]any{"chat_id": "120363000000000001@g.us", "from": "628123456789@s.whatsapp.net"}}
+	addWebhookTenantID(group)
+	if group["tenant_id"] != "acme" {
+		t.Errorf("group tenant_id = %v, want acme", group["tenant_id"])
+	}
+
+	call := map[string]any{"payload": map[string]any{"from": "628123456789@s.whatsapp.net"}}
+	addWebhookTenantID(call)
+	if call["tenant_id"] != "globex" {
+		t.Errorf("sender-only tenant_id = %v, want globex", call["tenant_id"])
+	}
+
+	unrouted := map[string]any{"payload": map[string]any{"chat_id": "999@s.whatsapp.net"}}
+	addWebhookTenantID(unrouted)
+	if _, ok := unrouted["tenant_id"]; ok {
+		t.Errorf("unrouted payload got tenant_id %v", unrouted["tenant_id"])
+	}
+}
+
+func TestForwardPayloadRoutesTenantToItsOwnWebhooks(t *testing.T) {
+	setTenantConfig(t,
+		[]string{"acme=@g.us"},
+		[]string{"acme=https://acme.example.com/a", "acme=https://acme.example.com/b", "globex=https://globex.example.com"},
+		nil,
+	)
+	if !hasWebhookTargets() {
+		t.Fatal("tenant webhooks alone should count as webhook targets")
+	}
+	config.WhatsappWebhook = []string{"https://global.example.com"}
+
+	var urls []string
+	var tenants []any
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(_ context.Context, payload map[string]any, url string) error {
+		urls = append(urls, url)
+		tenants = append(tenants, payload["tenant_id"])
+		return nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	groupPayload := map[string]any{"event": "message", "payload": map[string]any{"chat_id": "120363000000000001@g.us"}}
+	if err := forwardPayloadToConfiguredWebhooks(context.Background(), groupPayload, "message"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"https://acme.example.com/a", "https://acme.example.com/b"}; !slices.Equal(urls, want) {
+		t.Fatalf("tenant payload delivered to %v, want %v", urls, want)
+	}
+	if tenants[0] != "acme" {
+		t.Fatalf("delivered tenant_id = %v, want acme", tenants[0])
+	}
+
+	urls = nil
+	directPayload := map[string]any{"event": "message", "payload": map[string]any{"chat_id": "628123456789@s.whatsapp.net"}}
+	if err := forwardPayloadToConfiguredWebhooks(context.Background(), directPayload, "message"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"https://global.example.com"}; !slices.Equal(urls, want) {
+		t.Fatalf("unrouted payload delivered to %v, want %v", urls, want)
+	}
+}
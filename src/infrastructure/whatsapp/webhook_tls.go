@@ -0,0 +1,76 @@
+package whatsapp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+var (
+	webhookTLSMu sync.Mutex
+	// webhookTLSConfigs caches the certificates loaded for a CA bundle, client
+	// certificate and key combination, so files are read once, not per call.
+	webhookTLSConfigs = map[[3]string]*tls.Config{}
+)
+
+// ValidateWebhookTLS loads the configured CA bundle and client certificate,
+// reporting unreadable or mismatched files before the first delivery.
+func ValidateWebhookTLS() error {
+	_, err := webhookTLSConfig()
+	return err
+}
+
+// webhookTLSConfig returns the TLS settings of the webhook HTTP client:
+// WhatsappWebhookCACert added to the system roots, the client certificate for
+// mutual TLS, and WhatsappWebhookInsecureSkipVerify.
+func webhookTLSConfig() (*tls.Config, error) {
+	key := [3]string{config.WhatsappWebhookCACert, config.WhatsappWebhookClientCert, config.WhatsappWebhookClientKey}
+
+	webhookTLSMu.Lock()
+	defer webhookTLSMu.Unlock()
+
+	base, ok := webhookTLSConfigs[key]
+	if !ok {
+		var err error
+		if base, err = loadWebhookTLSConfig(key[0], key[1], key[2]); err != nil {
+			return nil, err
+		}
+		webhookTLSConfigs[key] = base
+	}
+	tlsConfig := base.Clone()
+	tlsConfig.InsecureSkipVerify = config.Setting(&config.WhatsappWebhookInsecureSkipVerify)
+	return tlsConfig, nil
+}
+
+func loadWebhookTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if caFile != "" {
+		bundle, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read webhook CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("webhook CA bundle %s has no PEM certificates", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("webhook client certificate and key must be set together")
+		}
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load webhook client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
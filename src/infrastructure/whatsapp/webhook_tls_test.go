@@ -0,0 +1,114 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// writeClientCertificate writes a self-signed client certificate and its key
+// to dir and returns their paths with the parsed certificate.
+func writeClientCertificate(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gowa"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
+func setWebhookTLSFiles(t *testing.T, caFile, certFile, keyFile string) {
+	t.Helper()
+	prevCA, prevCert, prevKey := config.WhatsappWebhookCACert, config.WhatsappWebhookClientCert, config.WhatsappWebhookClientKey
+	config.WhatsappWebhookCACert, config.WhatsappWebhookClientCert, config.WhatsappWebhookClientKey = caFile, certFile, keyFile
+	t.Cleanup(func() {
+		config.WhatsappWebhookCACert, config.WhatsappWebhookClientCert, config.WhatsappWebhookClientKey = prevCA, prevCert, prevKey
+	})
+}
+
+func TestSubmitWebhook_MutualTLSWithCustomCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCertificate(t, dir)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	setWebhookTLSFiles(t, caFile, certFile, keyFile)
+
+	if err := submitWebhook(context.Background(), map[string]any{"event": "message"}, server.URL); err != nil {
+		t.Fatalf("mutual TLS delivery failed: %v", err)
+	}
+}
+
+func TestValidateWebhookTLS_RejectsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeClientCertificate(t, dir)
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, files := range map[string][3]string{
+		"missing CA":     {filepath.Join(dir, "missing.pem"), "", ""},
+		"CA without PEM": {notPEM, "", ""},
+		"cert, no key":   {"", certFile, ""},
+		"key as cert":    {"", keyFile, keyFile},
+	} {
+		setWebhookTLSFiles(t, files[0], files[1], files[2])
+		if err := ValidateWebhookTLS(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package error
+
+import "net/http"
+
+type DraftNotFoundError string
+
+// Error for complying the error interface
+func (e DraftNotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e DraftNotFoundError) ErrCode() string {
+	return "DRAFT_NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e DraftNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
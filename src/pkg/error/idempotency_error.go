@@ -0,0 +1,20 @@
+package error
+
+import "net/http"
+
+type IdempotencyKeyInUseError string
+
+// Error for complying the error interface
+func (e IdempotencyKeyInUseError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e IdempotencyKeyInUseError) ErrCode() string {
+	return "IDEMPOTENCY_KEY_IN_USE"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e IdempotencyKeyInUseError) StatusCode() int {
+	return http.StatusConflict
+}
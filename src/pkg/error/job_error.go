@@ -0,0 +1,37 @@
+package error
+
+import "net/http"
+
+type JobNotFoundError string
+
+// Error for complying the error interface
+func (e JobNotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e JobNotFoundError) ErrCode() string {
+	return "JOB_NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e JobNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type JobStateError string
+
+// Error for complying the error interface
+func (e JobStateError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e JobStateError) ErrCode() string {
+	return "JOB_INVALID_STATE"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e JobStateError) StatusCode() int {
+	return http.StatusConflict
+}
@@ -0,0 +1,20 @@
+package error
+
+import "net/http"
+
+type LIDMappingNotFoundError string
+
+// Error for complying the error interface
+func (e LIDMappingNotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e LIDMappingNotFoundError) ErrCode() string {
+	return "LID_MAPPING_NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e LIDMappingNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
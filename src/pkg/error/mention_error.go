@@ -0,0 +1,20 @@
+package error
+
+import "net/http"
+
+type MentionRateLimitError string
+
+// Error for complying the error interface
+func (e MentionRateLimitError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e MentionRateLimitError) ErrCode() string {
+	return "MENTION_RATE_LIMITED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e MentionRateLimitError) StatusCode() int {
+	return http.StatusTooManyRequests
+}
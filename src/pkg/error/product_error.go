@@ -0,0 +1,20 @@
+package error
+
+import "net/http"
+
+type ProductNotFoundError string
+
+// Error for complying the error interface
+func (e ProductNotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e ProductNotFoundError) ErrCode() string {
+	return "PRODUCT_NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e ProductNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
@@ -0,0 +1,46 @@
+package error
+
+import (
+	"math"
+	"net/http"
+	"time"
+)
+
+// RetryAfterError is implemented by errors that tell the caller how long to
+// wait before retrying; REST responses carry it as the Retry-After header.
+type RetryAfterError interface {
+	RetryAfter() time.Duration
+}
+
+// SendRateLimitError rejects a send that would exceed the configured send
+// rate limits or daily cap.
+type SendRateLimitError struct {
+	Message string
+	Wait    time.Duration
+}
+
+// Error for complying the error interface
+func (e SendRateLimitError) Error() string {
+	return e.Message
+}
+
+// ErrCode will return the error code based on the error data type
+func (e SendRateLimitError) ErrCode() string {
+	return "SEND_RATE_LIMITED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e SendRateLimitError) StatusCode() int {
+	return http.StatusTooManyRequests
+}
+
+// RetryAfter is how long to wait before the send is allowed
+func (e SendRateLimitError) RetryAfter() time.Duration {
+	return e.Wait
+}
+
+// RetryAfterSeconds rounds wait up to whole seconds, the unit of the
+// Retry-After header, and is at least 1.
+func RetryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
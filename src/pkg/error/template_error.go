@@ -0,0 +1,20 @@
+package error
+
+import "net/http"
+
+type TemplateNotFoundError string
+
+// Error for complying the error interface
+func (e TemplateNotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e TemplateNotFoundError) ErrCode() string {
+	return "TEMPLATE_NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e TemplateNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
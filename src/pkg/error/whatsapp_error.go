@@ -87,6 +87,40 @@ func (e WaReachoutTimelockError) StatusCode() int {
 	return http.StatusTooManyRequests
 }
 
+type SendPolicyError string
+
+// Error for complying the error interface
+func (e SendPolicyError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e SendPolicyError) ErrCode() string {
+	return "SEND_POLICY_REJECTED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e SendPolicyError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+type EditWindowExpiredError string
+
+// Error for complying the error interface
+func (e EditWindowExpiredError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e EditWindowExpiredError) ErrCode() string {
+	return "EDIT_WINDOW_EXPIRED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e EditWindowExpiredError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
 const (
 	ErrInvalidJID         = InvalidJID("your JID is invalid")
 	ErrUserNotRegistered  = InvalidJID("user is not registered")
@@ -344,6 +344,15 @@ func ContainsMention(message string) []string {
 	return phoneNumbers
 }
 
+// urlPattern matches http(s) URLs in message text.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// FirstURL returns the first http(s) URL of text without trailing
+// punctuation, or "" when text has none.
+func FirstURL(text string) string {
+	return strings.TrimRight(urlPattern.FindString(text), ".,;:!?)]}'")
+}
+
 func DownloadImageFromURL(url string) ([]byte, string, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
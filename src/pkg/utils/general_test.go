@@ -57,6 +57,25 @@ func (suite *UtilsTestSuite) TestContainsMention() {
 	}
 }
 
+func (suite *UtilsTestSuite) TestFirstURL() {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "should return empty without url", message: "hello world", want: ""},
+		{name: "should find url in text", message: "see https://example.com/a?b=1 now", want: "https://example.com/a?b=1"},
+		{name: "should return first of several urls", message: "http://one.test and https://two.test", want: "http://one.test"},
+		{name: "should trim trailing punctuation", message: "read this (https://example.com/page).", want: "https://example.com/page"},
+		{name: "should ignore bare domains", message: "visit example.com", want: ""},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, utils.FirstURL(tt.message))
+		})
+	}
+}
+
 func (suite *UtilsTestSuite) TestRemoveFile() {
 	tempFile, err := os.CreateTemp("", "testfile")
 	assert.NoError(suite.T(), err)
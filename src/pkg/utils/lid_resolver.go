@@ -0,0 +1,498 @@
+package utils
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Sources of a LID resolution: the mapping store, the client's LID store,
+// or a user info query to the WhatsApp servers.
+const (
+	LIDSourceStore    = "store"
+	LIDSourceWhatsApp = "whatsapp"
+	LIDSourceUserInfo = "user_info"
+)
+
+// Directions of a LID lookup.
+const (
+	LIDDirectionToPhone = "lid_to_pn"
+	LIDDirectionToLID   = "pn_to_lid"
+)
+
+// userInfoBatchSize is the number of phone numbers asked for in one user info query.
+const userInfoBatchSize = 100
+
+var (
+	lidResolutionAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowa_lid_resolution_attempts_total",
+		Help: "LID lookups, by direction (lid_to_pn, pn_to_lid).",
+	}, []string{"direction"})
+	lidStoreHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowa_lid_store_hits_total",
+		Help: "LID lookups answered by the persistent mapping store, by direction.",
+	}, []string{"direction"})
+	lidProactiveResolutions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gowa_lid_proactive_resolutions_total",
+		Help: "Phone numbers queried from WhatsApp because no store knew their LID, by result (success, failure).",
+	}, []string{"result"})
+	lidFailedLookupsSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gowa_lid_failed_lookups",
+		Help: "Failed LID lookups cached by the resolvers of all devices.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(lidResolutionAttempts, lidStoreHits, lidProactiveResolutions, lidFailedLookupsSize)
+}
+
+// LIDResolution is the outcome of resolving one JID with ResolveMany. LID and
+// Phone are both set when the JID was resolved, and Source is empty when not.
+type LIDResolution struct {
+	JID    types.JID
+	LID    types.JID
+	Phone  types.JID
+	Source string
+}
+
+// Resolved reports whether the LID and phone number of the JID are known.
+func (r LIDResolution) Resolved() bool {
+	return r.Source != ""
+}
+
+// CacheHit reports whether the JID was resolved without querying WhatsApp.
+func (r LIDResolution) CacheHit() bool {
+	return r.Source == LIDSourceStore || r.Source == LIDSourceWhatsApp
+}
+
+// FailedLIDLookup is a JID the resolver could not translate. Until ExpiresAt
+// the resolver only checks its stores for it, without querying WhatsApp.
+type FailedLIDLookup struct {
+	JID           string    `json:"jid"`
+	Direction     string    `json:"direction"`
+	Attempts      int       `json:"attempts"`
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// LIDMappingStore persists the LID to phone number mappings resolved through
+// WhatsApp, so they survive restarts and are shared by all devices. JIDs are
+// stored without their device part; lookups return "" when nothing is known.
+type LIDMappingStore interface {
+	StoreLIDMapping(lid, phoneJID string) error
+	LookupPhoneForLID(lid string) (string, error)
+	LookupLIDForPhone(phoneJID string) (string, error)
+}
+
+// LIDResolver resolves LIDs to phone numbers and back, from its mapping store
+// first and from the client's LID store otherwise. Phone numbers unknown to
+// both are queried from WhatsApp. Every resolution made by the client is
+// recorded in the mapping store, and failures are remembered for
+// WhatsappLIDFailedLookupTTL so a missing JID does not cost a query on every
+// message; RetryFailedLookups retries them in the background.
+//
+// Each device has its own resolver, carried by the contexts of its requests
+// and events; see ContextWithLIDResolver.
+type LIDResolver struct {
+	mu    sync.RWMutex
+	store LIDMappingStore
+
+	failedMu sync.Mutex
+	failed   map[string]*FailedLIDLookup
+}
+
+// NewLIDResolver returns a resolver persisting mappings to store, which may be nil.
+func NewLIDResolver(store LIDMappingStore) *LIDResolver {
+	return &LIDResolver{store: store, failed: make(map[string]*FailedLIDLookup)}
+}
+
+// defaultLIDResolver serves contexts that carry no device.
+var defaultLIDResolver = NewLIDResolver(nil)
+
+type lidResolverContextKey struct{}
+
+// ContextWithLIDResolver returns a copy of ctx carrying resolver.
+func ContextWithLIDResolver(ctx context.Context, resolver *LIDResolver) context.Context {
+	return context.WithValue(ctx, lidResolverContextKey{}, resolver)
+}
+
+// LIDResolverFromContext returns the resolver carried by ctx, or the resolver
+// of contexts without a device.
+func LIDResolverFromContext(ctx context.Context) *LIDResolver {
+	if ctx != nil {
+		if resolver, ok := ctx.Value(lidResolverContextKey{}).(*LIDResolver); ok && resolver != nil {
+			return resolver
+		}
+	}
+	return defaultLIDResolver
+}
+
+// SetLIDMappingStore sets the store the resolver of contexts without a device
+// persists mappings to; nil disables persistence.
+func SetLIDMappingStore(store LIDMappingStore) {
+	defaultLIDResolver.SetStore(store)
+}
+
+// SetStore sets the store the resolver persists mappings to.
+func (r *LIDResolver) SetStore(store LIDMappingStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store = store
+}
+
+func (r *LIDResolver) mappingStore() LIDMappingStore {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store
+}
+
+// PhoneForLID returns the phone number JID of lid and where it was found, or
+// an empty JID when lid is unknown. The device part of lid is kept.
+func (r *LIDResolver) PhoneForLID(ctx context.Context, lid types.JID, client *whatsmeow.Client) (types.JID, string) {
+	if lid.Server != types.HiddenUserServer {
+		return types.JID{}, ""
+	}
+	lidResolutionAttempts.WithLabelValues(LIDDirectionToPhone).Inc()
+	store := r.mappingStore()
+	if store != nil {
+		pn, err := store.LookupPhoneForLID(lid.ToNonAD().String())
+		if err != nil {
+			logrus.Debugf("Failed to look up stored mapping of LID %s: %v", lid.String(), err)
+		} else if parsed, ok := parseStoredJID(pn, types.DefaultUserServer); ok {
+			parsed.Device = lid.Device
+			lidStoreHits.WithLabelValues(LIDDirectionToPhone).Inc()
+			return parsed, LIDSourceStore
+		}
+	}
+
+	if client == nil || client.Store == nil || client.Store.LIDs == nil {
+		logrus.Warnf("Cannot resolve LID %s: client not available", lid.String())
+		return types.JID{}, ""
+	}
+	pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
+	if err != nil {
+		logrus.Debugf("Failed to resolve LID %s to phone number: %v", lid.String(), err)
+	}
+	if err != nil || pn.IsEmpty() {
+		// WhatsApp cannot be asked for the phone number of a LID; it becomes
+		// known once a message or user info query carries both.
+		r.recordFailure(LIDDirectionToPhone, lid, time.Now())
+		return types.JID{}, ""
+	}
+	logrus.Debugf("Resolved LID %s to phone number %s", lid.String(), pn.String())
+	r.remember(store, lid, pn)
+	return pn, LIDSourceWhatsApp
+}
+
+// LIDForPhone returns the LID of the phone number JID pn and where it was
+// found, or an empty JID when pn is unknown. The device part of pn is kept.
+func (r *LIDResolver) LIDForPhone(ctx context.Context, pn types.JID, client *whatsmeow.Client) (types.JID, string) {
+	lid, source := r.lidForPhone(ctx, pn, client)
+	if lid.IsEmpty() && pn.Server == types.DefaultUserServer && r.shouldQuery(LIDDirectionToLID, pn, time.Now()) {
+		lid, source = r.resolveProactively(ctx, pn, client)
+	}
+	return lid, source
+}
+
+// lidForPhone looks pn up in the mapping store and the client's LID store.
+func (r *LIDResolver) lidForPhone(ctx context.Context, pn types.JID, client *whatsmeow.Client) (types.JID, string) {
+	if pn.Server != types.DefaultUserServer {
+		return types.JID{}, ""
+	}
+	lidResolutionAttempts.WithLabelValues(LIDDirectionToLID).Inc()
+	store := r.mappingStore()
+	if store != nil {
+		lid, err := store.LookupLIDForPhone(pn.ToNonAD().String())
+		if err != nil {
+			logrus.Debugf("Failed to look up stored mapping of phone %s: %v", pn.String(), err)
+		} else if parsed, ok := parseStoredJID(lid, types.HiddenUserServer); ok {
+			parsed.Device = pn.Device
+			lidStoreHits.WithLabelValues(LIDDirectionToLID).Inc()
+			return parsed, LIDSourceStore
+		}
+	}
+
+	if client == nil || client.Store == nil || client.Store.LIDs == nil {
+		logrus.Debugf("Cannot resolve phone %s to LID: client not available", pn.String())
+		return types.JID{}, ""
+	}
+	lid, err := client.Store.LIDs.GetLIDForPN(ctx, pn)
+	if err != nil {
+		logrus.Debugf("Failed to resolve phone %s to LID: %v", pn.String(), err)
+		return types.JID{}, ""
+	}
+	if lid.IsEmpty() {
+		return types.JID{}, ""
+	}
+	logrus.Debugf("Resolved phone %s to LID %s", pn.String(), lid.String())
+	r.remember(store, lid, pn)
+	return lid, LIDSourceWhatsApp
+}
+
+// resolveProactively queries WhatsApp for the LID of pn, waiting at most
+// WhatsappLIDProactiveTimeout. A failure is remembered as a failed lookup.
+func (r *LIDResolver) resolveProactively(ctx context.Context, pn types.JID, client *whatsmeow.Client) (types.JID, string) {
+	if client == nil || !client.IsLoggedIn() {
+		return types.JID{}, ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.WhatsappLIDProactiveTimeout)
+	defer cancel()
+
+	infos, err := client.GetUserInfo(ctx, []types.JID{pn.ToNonAD()})
+	if err != nil {
+		logrus.Debugf("Failed to get user info of %s: %v", pn.String(), err)
+	}
+	lid := infos[pn.ToNonAD()].LID
+	if lid.IsEmpty() {
+		lidProactiveResolutions.WithLabelValues("failure").Inc()
+		r.recordFailure(LIDDirectionToLID, pn, time.Now())
+		return types.JID{}, ""
+	}
+	lidProactiveResolutions.WithLabelValues("success").Inc()
+	r.remember(r.mappingStore(), lid, pn)
+	lid.Device = pn.Device
+	return lid, LIDSourceUserInfo
+}
+
+// ResolveMany resolves LIDs to phone numbers and phone numbers to LIDs, in
+// the order of jids. JIDs that are neither are returned unresolved. Phone
+// numbers unknown locally are looked up with user info queries of up to
+// userInfoBatchSize numbers each, even when a recent lookup failed; a
+// failed query leaves its numbers unresolved.
+func (r *LIDResolver) ResolveMany(ctx context.Context, jids []types.JID, client *whatsmeow.Client) []LIDResolution {
+	results := make([]LIDResolution, len(jids))
+	pending := make(map[types.JID][]int)
+	var query []types.JID
+	for i, jid := range jids {
+		results[i].JID = jid
+		switch jid.Server {
+		case types.HiddenUserServer:
+			if pn, source := r.PhoneForLID(ctx, jid, client); !pn.IsEmpty() {
+				results[i].LID, results[i].Phone, results[i].Source = jid, pn, source
+			}
+		case types.DefaultUserServer:
+			if lid, source := r.lidForPhone(ctx, jid, client); !lid.IsEmpty() {
+				results[i].LID, results[i].Phone, results[i].Source = lid, jid, source
+				continue
+			}
+			if _, ok := pending[jid]; !ok {
+				query = append(query, jid)
+			}
+			pending[jid] = append(pending[jid], i)
+		}
+	}
+	if len(query) == 0 || client == nil || !client.IsLoggedIn() {
+		return results
+	}
+
+	store := r.mappingStore()
+	for start := 0; start < len(query); start += userInfoBatchSize {
+		batch := query[start:min(start+userInfoBatchSize, len(query))]
+		infos, err := client.GetUserInfo(ctx, batch)
+		if err != nil {
+			logrus.Warnf("Failed to get user info of %d phone numbers: %v", len(batch), err)
+		}
+		now := time.Now()
+		for _, pn := range batch {
+			lid := infos[pn].LID
+			if lid.IsEmpty() {
+				lidProactiveResolutions.WithLabelValues("failure").Inc()
+				r.recordFailure(LIDDirectionToLID, pn, now)
+				continue
+			}
+			lidProactiveResolutions.WithLabelValues("success").Inc()
+			r.remember(store, lid, pn)
+			for _, i := range pending[pn] {
+				results[i].LID, results[i].Phone, results[i].Source = lid, pn, LIDSourceUserInfo
+			}
+		}
+	}
+	return results
+}
+
+// FailedLookups returns the failed lookups that still hold back queries to
+// WhatsApp, most recent failure first.
+func (r *LIDResolver) FailedLookups() []FailedLIDLookup {
+	r.failedMu.Lock()
+	defer r.failedMu.Unlock()
+	r.pruneFailedLocked(time.Now())
+
+	lookups := make([]FailedLIDLookup, 0, len(r.failed))
+	for _, lookup := range r.failed {
+		lookups = append(lookups, *lookup)
+	}
+	sort.Slice(lookups, func(i, j int) bool {
+		return lookups[i].LastFailedAt.After(lookups[j].LastFailedAt)
+	})
+	return lookups
+}
+
+// RetryFailedLookups drops the expired failed lookups and retries the
+// others: LIDs against the client's LID store, and phone numbers against both
+// stores and then with user info queries of up to userInfoBatchSize numbers
+// each. It returns the number of JIDs resolved.
+func (r *LIDResolver) RetryFailedLookups(ctx context.Context, client *whatsmeow.Client) int {
+	var lids, pns []types.JID
+	r.failedMu.Lock()
+	r.pruneFailedLocked(time.Now())
+	for _, lookup := range r.failed {
+		jid, err := types.ParseJID(lookup.JID)
+		if err != nil {
+			continue
+		}
+		switch lookup.Direction {
+		case LIDDirectionToPhone:
+			lids = append(lids, jid)
+		case LIDDirectionToLID:
+			pns = append(pns, jid)
+		}
+	}
+	r.failedMu.Unlock()
+	if client == nil || client.Store == nil || client.Store.LIDs == nil {
+		return 0
+	}
+
+	resolved := 0
+	store := r.mappingStore()
+	for _, lid := range lids {
+		pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
+		if err != nil || pn.IsEmpty() {
+			r.recordRetryFailure(LIDDirectionToPhone, lid, time.Now())
+			continue
+		}
+		r.remember(store, lid, pn)
+		resolved++
+	}
+
+	var query []types.JID
+	for _, pn := range pns {
+		if lid, _ := r.lidForPhone(ctx, pn, client); !lid.IsEmpty() {
+			resolved++
+			continue
+		}
+		query = append(query, pn)
+	}
+	if len(query) == 0 || !client.IsLoggedIn() {
+		return resolved
+	}
+	for start := 0; start < len(query); start += userInfoBatchSize {
+		batch := query[start:min(start+userInfoBatchSize, len(query))]
+		infos, err := client.GetUserInfo(ctx, batch)
+		if err != nil {
+			logrus.Debugf("Failed to retry user info of %d phone numbers: %v", len(batch), err)
+		}
+		now := time.Now()
+		for _, pn := range batch {
+			lid := infos[pn].LID
+			if lid.IsEmpty() {
+				lidProactiveResolutions.WithLabelValues("failure").Inc()
+				r.recordRetryFailure(LIDDirectionToLID, pn, now)
+				continue
+			}
+			lidProactiveResolutions.WithLabelValues("success").Inc()
+			r.remember(store, lid, pn)
+			resolved++
+		}
+	}
+	return resolved
+}
+
+// shouldQuery reports whether WhatsApp may be queried for jid, i.e. no
+// lookup of it in direction failed within WhatsappLIDFailedLookupTTL.
+func (r *LIDResolver) shouldQuery(direction string, jid types.JID, now time.Time) bool {
+	r.failedMu.Lock()
+	defer r.failedMu.Unlock()
+	lookup, ok := r.failed[failedLookupKey(direction, jid)]
+	return !ok || !now.Before(lookup.ExpiresAt)
+}
+
+// recordFailure remembers that jid could not be resolved in direction.
+func (r *LIDResolver) recordFailure(direction string, jid types.JID, now time.Time) {
+	r.failedMu.Lock()
+	defer r.failedMu.Unlock()
+	if r.failed == nil {
+		r.failed = make(map[string]*FailedLIDLookup)
+	}
+	r.pruneFailedLocked(now)
+
+	key := failedLookupKey(direction, jid)
+	lookup, ok := r.failed[key]
+	if !ok {
+		lookup = &FailedLIDLookup{JID: jid.ToNonAD().String(), Direction: direction, FirstFailedAt: now}
+		r.failed[key] = lookup
+		lidFailedLookupsSize.Inc()
+	}
+	lookup.Attempts++
+	lookup.LastFailedAt = now
+	lookup.ExpiresAt = now.Add(config.WhatsappLIDFailedLookupTTL)
+}
+
+// recordRetryFailure counts a failed retry of a failed lookup. Its expiry is
+// left as is, so a JID that keeps failing is retried until it expires.
+func (r *LIDResolver) recordRetryFailure(direction string, jid types.JID, now time.Time) {
+	r.failedMu.Lock()
+	defer r.failedMu.Unlock()
+	if lookup, ok := r.failed[failedLookupKey(direction, jid)]; ok {
+		lookup.Attempts++
+		lookup.LastFailedAt = now
+	}
+}
+
+// forgetFailures drops the failed lookups of a mapping that has been resolved.
+func (r *LIDResolver) forgetFailures(lid, pn types.JID) {
+	r.failedMu.Lock()
+	defer r.failedMu.Unlock()
+	r.deleteFailedLocked(failedLookupKey(LIDDirectionToPhone, lid))
+	r.deleteFailedLocked(failedLookupKey(LIDDirectionToLID, pn))
+}
+
+// pruneFailedLocked drops the failed lookups expired at now.
+func (r *LIDResolver) pruneFailedLocked(now time.Time) {
+	for key, lookup := range r.failed {
+		if !now.Before(lookup.ExpiresAt) {
+			r.deleteFailedLocked(key)
+		}
+	}
+}
+
+func (r *LIDResolver) deleteFailedLocked(key string) {
+	if _, ok := r.failed[key]; ok {
+		delete(r.failed, key)
+		lidFailedLookupsSize.Dec()
+	}
+}
+
+func failedLookupKey(direction string, jid types.JID) string {
+	return direction + "|" + jid.ToNonAD().String()
+}
+
+// remember records a resolution made by the client in store.
+func (r *LIDResolver) remember(store LIDMappingStore, lid, pn types.JID) {
+	r.forgetFailures(lid, pn)
+	if store == nil {
+		return
+	}
+	if err := store.StoreLIDMapping(lid.ToNonAD().String(), pn.ToNonAD().String()); err != nil {
+		logrus.Warnf("Failed to store mapping of LID %s to %s: %v", lid.String(), pn.String(), err)
+	}
+}
+
+// parseStoredJID parses a JID read from the mapping store, which must be on server.
+func parseStoredJID(value, server string) (types.JID, bool) {
+	if value == "" {
+		return types.JID{}, false
+	}
+	jid, err := types.ParseJID(value)
+	if err != nil || jid.Server != server || jid.User == "" {
+		return types.JID{}, false
+	}
+	return jid, true
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type memoryLIDMappingStore struct {
+	phoneByLID map[string]string
+	lidByPhone map[string]string
+}
+
+func newMemoryLIDMappingStore() *memoryLIDMappingStore {
+	return &memoryLIDMappingStore{phoneByLID: map[string]string{}, lidByPhone: map[string]string{}}
+}
+
+func (s *memoryLIDMappingStore) StoreLIDMapping(lid, phoneJID string) error {
+	s.phoneByLID[lid] = phoneJID
+	s.lidByPhone[phoneJID] = lid
+	return nil
+}
+
+func (s *memoryLIDMappingStore) LookupPhoneForLID(lid string) (string, error) {
+	return s.phoneByLID[lid], nil
+}
+
+func (s *memoryLIDMappingStore) LookupLIDForPhone(phoneJID string) (string, error) {
+	return s.lidByPhone[phoneJID], nil
+}
+
+func TestLIDResolverUsesStoreBeforeClient(t *testing.T) {
+	store := newMemoryLIDMappingStore()
+	_ = store.StoreLIDMapping("100000000000001@lid", "628123456789@s.whatsapp.net")
+	resolver := NewLIDResolver(nil)
+	resolver.SetStore(store)
+	ctx := context.Background()
+
+	// The client is nil, so only the store can answer
+	lid := types.JID{User: "100000000000001", Device: 3, Server: types.HiddenUserServer}
+	pn, source := resolver.PhoneForLID(ctx, lid, nil)
+	assert.Equal(t, types.JID{User: "628123456789", Device: 3, Server: types.DefaultUserServer}, pn)
+	assert.Equal(t, LIDSourceStore, source)
+
+	got, source := resolver.LIDForPhone(ctx, types.NewJID("628123456789", types.DefaultUserServer), nil)
+	assert.Equal(t, types.NewJID("100000000000001", types.HiddenUserServer), got)
+	assert.Equal(t, LIDSourceStore, source)
+
+	pn, source = resolver.PhoneForLID(ctx, types.NewJID("100000000000002", types.HiddenUserServer), nil)
+	assert.True(t, pn.IsEmpty())
+	assert.Empty(t, source)
+}
+
+func TestLIDResolverIgnoresUnexpectedServers(t *testing.T) {
+	store := newMemoryLIDMappingStore()
+	_ = store.StoreLIDMapping("100000000000001@lid", "120363025246125486@g.us")
+	resolver := NewLIDResolver(nil)
+	resolver.SetStore(store)
+
+	pn, _ := resolver.PhoneForLID(context.Background(), types.NewJID("100000000000001", types.HiddenUserServer), nil)
+	assert.True(t, pn.IsEmpty())
+
+	pn, _ = resolver.PhoneForLID(context.Background(), types.NewJID("628123456789", types.DefaultUserServer), nil)
+	assert.True(t, pn.IsEmpty())
+}
+
+func TestLIDResolverResolveManyReportsEachJID(t *testing.T) {
+	store := newMemoryLIDMappingStore()
+	_ = store.StoreLIDMapping("100000000000001@lid", "628123456789@s.whatsapp.net")
+	_ = store.StoreLIDMapping("100000000000002@lid", "628987654321@s.whatsapp.net")
+	resolver := NewLIDResolver(nil)
+	resolver.SetStore(store)
+
+	jids := []types.JID{
+		types.NewJID("100000000000001", types.HiddenUserServer),
+		types.NewJID("628987654321", types.DefaultUserServer),
+		types.NewJID("628000000000", types.DefaultUserServer),
+		types.NewJID("120363025246125486", types.GroupServer),
+	}
+	results := resolver.ResolveMany(context.Background(), jids, nil)
+	assert.Len(t, results, len(jids))
+
+	assert.True(t, results[0].Resolved())
+	assert.True(t, results[0].CacheHit())
+	assert.Equal(t, types.NewJID("628123456789", types.DefaultUserServer), results[0].Phone)
+
+	assert.True(t, results[1].Resolved())
+	assert.Equal(t, types.NewJID("100000000000002", types.HiddenUserServer), results[1].LID)
+	assert.Equal(t, LIDSourceStore, results[1].Source)
+
+	// Unknown numbers stay unresolved without a client to query
+	for _, result := range results[2:] {
+		assert.False(t, result.Resolved())
+		assert.False(t, result.CacheHit())
+	}
+	assert.Equal(t, jids[3], results[3].JID)
+}
+
+func TestLIDResolverFailedLookupsExpireAndClearOnResolution(t *testing.T) {
+	resolver := NewLIDResolver(nil)
+	pn := types.NewJID("628123456789", types.DefaultUserServer)
+	lid := types.NewJID("100000000000001", types.HiddenUserServer)
+	now := time.Now()
+
+	assert.True(t, resolver.shouldQuery(LIDDirectionToLID, pn, now))
+	resolver.recordFailure(LIDDirectionToLID, pn, now.Add(-time.Minute))
+	resolver.recordFailure(LIDDirectionToLID, pn, now)
+	assert.False(t, resolver.shouldQuery(LIDDirectionToLID, pn, now))
+	assert.True(t, resolver.shouldQuery(LIDDirectionToLID, pn, now.Add(config.WhatsappLIDFailedLookupTTL)))
+
+	lookups := resolver.FailedLookups()
+	assert.Len(t, lookups, 1)
+	assert.Equal(t, pn.String(), lookups[0].JID)
+	assert.Equal(t, 2, lookups[0].Attempts)
+	assert.True(t, lookups[0].ExpiresAt.Equal(now.Add(config.WhatsappLIDFailedLookupTTL)))
+
+	// A resolution learned later, e.g. from a message, clears the failure
+	resolver.remember(nil, lid, pn)
+	assert.Empty(t, resolver.FailedLookups())
+	assert.True(t, resolver.shouldQuery(LIDDirectionToLID, pn, now))
+}
+
+func TestLIDResolverRetryFailedLookups(t *testing.T) {
+	prev := config.WhatsappLIDFailedLookupTTL
+	config.WhatsappLIDFailedLookupTTL = time.Minute
+	t.Cleanup(func() { config.WhatsappLIDFailedLookupTTL = prev })
+
+	resolver := NewLIDResolver(nil)
+	expired := types.NewJID("628000000000001", types.DefaultUserServer)
+	pending := types.NewJID("628000000000002", types.DefaultUserServer)
+	now := time.Now()
+	resolver.recordFailure(LIDDirectionToLID, expired, now.Add(-2*time.Minute))
+	resolver.recordFailure(LIDDirectionToLID, pending, now)
+
+	// Without a client nothing resolves, but expired failures are dropped
+	assert.Zero(t, resolver.RetryFailedLookups(context.Background(), nil))
+	lookups := resolver.FailedLookups()
+	assert.Len(t, lookups, 1)
+	assert.Equal(t, pending.String(), lookups[0].JID)
+
+	// A failed retry counts as an attempt without pushing back the expiry
+	resolver.recordRetryFailure(LIDDirectionToLID, pending, now.Add(time.Second))
+	lookups = resolver.FailedLookups()
+	assert.Equal(t, 2, lookups[0].Attempts)
+	assert.True(t, lookups[0].ExpiresAt.Equal(now.Add(time.Minute)))
+}
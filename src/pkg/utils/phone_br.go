@@ -349,5 +349,5 @@ func ValidateAndNormalizeJID(client *whatsmeow.Client, jid string) (types.JID, e
 	// in resolveUserJID (testable via the onWhatsAppProber seam). context.Background
 	// for now: threading the request context through the ~40 callers is a separate
 	// change; the total probe budget (onWhatsAppTotalTimeout) bounds the wall-clock.
-	return resolveUserJID(context.Background(), client, jid, config.WhatsappAccountValidation)
+	return resolveUserJID(context.Background(), client, jid, config.Setting(&config.WhatsappAccountValidation))
 }
@@ -212,6 +212,11 @@ func ExtractMessageTextFromProto(msg *waE2E.Message) string {
 		return "Contacts shared"
 	}
 
+	// Check for poll creation (matches the content stored for sent polls)
+	if poll := ExtractPollCreation(msg); poll != nil {
+		return "📊 " + poll.GetName()
+	}
+
 	return ""
 }
 
@@ -330,6 +335,75 @@ func ExtractContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
 	return nil
 }
 
+// ExtractEditedCaption reports whether an edited message replaces the caption of
+// a media message, returning the new (possibly empty) caption and the media type.
+func ExtractEditedCaption(msg *waE2E.Message) (caption string, mediaType string, ok bool) {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetCaption(), "image", true
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetCaption(), "video", true
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetCaption(), "document", true
+	}
+	return "", "", false
+}
+
+// ExtractPollCreation returns the poll carried by msg, whichever creation
+// message version the sender used, or nil when msg is not a poll.
+func ExtractPollCreation(msg *waE2E.Message) *waE2E.PollCreationMessage {
+	if msg == nil {
+		return nil
+	}
+	for _, poll := range []*waE2E.PollCreationMessage{
+		msg.GetPollCreationMessage(),
+		msg.GetPollCreationMessageV2(),
+		msg.GetPollCreationMessageV3(),
+		msg.GetPollCreationMessageV5(),
+		msg.GetPollCreationMessageV6(),
+	} {
+		if poll != nil {
+			return poll
+		}
+	}
+	return nil
+}
+
+// PollOptionNames returns the option names of a poll in display order.
+func PollOptionNames(poll *waE2E.PollCreationMessage) []string {
+	options := make([]string, 0, len(poll.GetOptions()))
+	for _, option := range poll.GetOptions() {
+		options = append(options, option.GetOptionName())
+	}
+	return options
+}
+
+// MatchPollOptionHashes maps the SHA-256 option hashes of a decrypted poll vote
+// back to option names. Hashes that match no option are dropped.
+func MatchPollOptionHashes(options []string, hashes [][]byte) []string {
+	names := make(map[string]string, len(options))
+	for i, hash := range whatsmeow.HashPollOptions(options) {
+		names[string(hash)] = options[i]
+	}
+	selected := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		if name, ok := names[string(hash)]; ok {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// ExtractQuotedReference returns the ID and participant of the message a reply
+// quotes, or empty strings when msg is not a reply.
+func ExtractQuotedReference(msg *waE2E.Message) (messageID string, participant string) {
+	ci := ExtractContextInfo(msg)
+	if ci == nil || ci.GetStanzaID() == "" {
+		return "", ""
+	}
+	return ci.GetStanzaID(), ci.GetParticipant()
+}
+
 // ExtractEphemeralExpiration extracts ephemeral expiration from a WhatsApp message
 func ExtractEphemeralExpiration(msg *waE2E.Message) uint32 {
 	if msg == nil {
@@ -582,7 +656,7 @@ func ValidateJidWithLogin(client *whatsmeow.Client, jid string) (types.JID, erro
 	// IsOnWhatsapp now probes both BR ninth-digit forms (see brPhoneCandidates),
 	// so this honest gate inherits the fix without changing the return contract,
 	// the validation-off short-circuit, or the no-probe-when-disabled behavior.
-	if config.WhatsappAccountValidation && !IsOnWhatsapp(client, jid) {
+	if config.Setting(&config.WhatsappAccountValidation) && !IsOnWhatsapp(client, jid) {
 		return types.JID{}, pkgError.InvalidJID(fmt.Sprintf("Phone %s is not on whatsapp", jid))
 	}
 
@@ -605,26 +679,11 @@ func MustLogin(client *whatsmeow.Client) {
 // Returns the original JID if it's not an @lid or if LID lookup fails
 func ResolveLIDToPhone(ctx context.Context, jid types.JID, client *whatsmeow.Client) types.JID {
 	// Only process @lid JIDs
-	if jid.Server != "lid" {
-		return jid
-	}
-
-	// Safety check
-	if client == nil || client.Store == nil || client.Store.LIDs == nil {
-		logrus.Warnf("Cannot resolve LID %s: client not available", jid.String())
-		return jid
-	}
-
-	// Attempt to get the phone number for this LID
-	pn, err := client.Store.LIDs.GetPNForLID(ctx, jid)
-	if err != nil {
-		logrus.Debugf("Failed to resolve LID %s to phone number: %v", jid.String(), err)
+	if jid.Server != types.HiddenUserServer {
 		return jid
 	}
 
-	// If we got a valid phone number, use it
-	if !pn.IsEmpty() {
-		logrus.Debugf("Resolved LID %s to phone number %s", jid.String(), pn.String())
+	if pn, _ := LIDResolverFromContext(ctx).PhoneForLID(ctx, jid, client); !pn.IsEmpty() {
 		return pn
 	}
 
@@ -635,31 +694,8 @@ func ResolveLIDToPhone(ctx context.Context, jid types.JID, client *whatsmeow.Cli
 // ResolvePhoneToLID converts @s.whatsapp.net JIDs to their corresponding @lid JIDs
 // Returns empty JID if it's not a user JID or if LID lookup fails
 func ResolvePhoneToLID(ctx context.Context, jid types.JID, client *whatsmeow.Client) types.JID {
-	// Only process user JIDs
-	if jid.Server != types.DefaultUserServer {
-		return types.JID{}
-	}
-
-	// Safety check
-	if client == nil || client.Store == nil || client.Store.LIDs == nil {
-		logrus.Debugf("Cannot resolve phone %s to LID: client not available", jid.String())
-		return types.JID{}
-	}
-
-	// Attempt to get the LID for this phone number
-	lid, err := client.Store.LIDs.GetLIDForPN(ctx, jid)
-	if err != nil {
-		logrus.Debugf("Failed to resolve phone %s to LID: %v", jid.String(), err)
-		return types.JID{}
-	}
-
-	// If we got a valid LID, return it
-	if !lid.IsEmpty() {
-		logrus.Debugf("Resolved phone %s to LID %s", jid.String(), lid.String())
-		return lid
-	}
-
-	return types.JID{}
+	lid, _ := LIDResolverFromContext(ctx).LIDForPhone(ctx, jid, client)
+	return lid
 }
 
 // Internal message types for event handling
@@ -311,3 +311,47 @@ func TestExtractMessageTextFromProtoContactsArrayMessage(t *testing.T) {
 func strPtr(value string) *string {
 	return &value
 }
+
+func TestExtractQuotedReference(t *testing.T) {
+	id, participant := "3EB0QUOTED", "628123@s.whatsapp.net"
+	reply := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		ContextInfo: &waE2E.ContextInfo{StanzaID: &id, Participant: &participant},
+	}}
+	if gotID, gotParticipant := ExtractQuotedReference(reply); gotID != id || gotParticipant != participant {
+		t.Errorf("ExtractQuotedReference() = (%q, %q), want (%q, %q)", gotID, gotParticipant, id, participant)
+	}
+
+	text := "hi"
+	plain := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: &text, ContextInfo: &waE2E.ContextInfo{}}}
+	if gotID, gotParticipant := ExtractQuotedReference(plain); gotID != "" || gotParticipant != "" {
+		t.Errorf("non-reply returned (%q, %q), want empty", gotID, gotParticipant)
+	}
+}
+
+func TestExtractEditedCaption(t *testing.T) {
+	clip, text := "clip", "text"
+	caption, mediaType, ok := ExtractEditedCaption(&waE2E.Message{VideoMessage: &waE2E.VideoMessage{Caption: &clip}})
+	if !ok || caption != "clip" || mediaType != "video" {
+		t.Fatalf("video caption edit = (%q, %q, %v)", caption, mediaType, ok)
+	}
+	if caption, mediaType, ok = ExtractEditedCaption(&waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{}}); !ok || caption != "" || mediaType != "document" {
+		t.Fatalf("cleared document caption = (%q, %q, %v)", caption, mediaType, ok)
+	}
+	if _, _, ok = ExtractEditedCaption(&waE2E.Message{Conversation: &text}); ok {
+		t.Fatal("text edit reported as caption edit")
+	}
+}
+
+func TestMatchPollOptionHashes(t *testing.T) {
+	options := []string{"Pizza", "Sushi", "Tacos"}
+	tacos := sha256.Sum256([]byte("Tacos"))
+	unknown := sha256.Sum256([]byte("Salad"))
+
+	got := MatchPollOptionHashes(options, [][]byte{tacos[:], unknown[:]})
+	if len(got) != 1 || got[0] != "Tacos" {
+		t.Fatalf("MatchPollOptionHashes = %v, want [Tacos]", got)
+	}
+	if got := MatchPollOptionHashes(options, nil); len(got) != 0 {
+		t.Fatalf("empty vote matched %v", got)
+	}
+}
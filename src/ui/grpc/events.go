@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"encoding/json"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/grpc/pb"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamEvents subscribes the call to live events, like a /ws/events
+// connection: commands received from the client manage its subscriptions and
+// every matching event is sent back. A client that falls too far behind is
+// disconnected with ResourceExhausted.
+func (s *Server) StreamEvents(stream pb.WhatsApp_StreamEventsServer) error {
+	events := websocket.OpenEventStream()
+	defer events.Close()
+
+	commandErr := make(chan error, 1)
+	go func() {
+		for {
+			command, err := stream.Recv()
+			if err != nil {
+				commandErr <- err
+				return
+			}
+			if command.GetId() == "" {
+				logrus.Debugf("gRPC events: ignoring command without id")
+				continue
+			}
+			switch command.GetAction() {
+			case pb.EventCommand_ACTION_SUBSCRIBE:
+				events.Subscribe(command.GetId(), websocket.EventFilter{
+					Events:   command.GetEvents(),
+					DeviceID: command.GetDeviceId(),
+					ChatJID:  command.GetChatId(),
+				})
+			case pb.EventCommand_ACTION_UNSUBSCRIBE:
+				events.Unsubscribe(command.GetId())
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-commandErr:
+			// The client closed its side (or the call broke); the stream ends with it.
+			return nil
+		case message, open := <-events.Messages():
+			if !open {
+				if events.TooSlow() {
+					return status.Error(codes.ResourceExhausted, "subscriber too slow")
+				}
+				return nil
+			}
+			event, err := eventFromMessage(message)
+			if err != nil {
+				logrus.Errorf("gRPC events: %v", err)
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventFromMessage converts the JSON sent to /ws/events subscribers.
+func eventFromMessage(message []byte) (*pb.Event, error) {
+	var decoded websocket.EventMessage
+	if err := json.Unmarshal(message, &decoded); err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		DeviceID string `json:"device_id"`
+	}
+	_ = json.Unmarshal(decoded.Payload, &envelope)
+	return &pb.Event{
+		Subscriptions: decoded.Subscriptions,
+		Event:         decoded.Event,
+		DeviceId:      envelope.DeviceID,
+		Payload:       decoded.Payload,
+	}, nil
+}
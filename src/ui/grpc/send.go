@@ -0,0 +1,156 @@
+package grpc
+
+import (
+	"context"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/grpc/pb"
+)
+
+// baseRequest converts the shared send fields, sanitizing the phone like the
+// REST handlers.
+func baseRequest(base *pb.SendBase) domainSend.BaseRequest {
+	request := domainSend.BaseRequest{
+		Phone:       base.GetPhone(),
+		IsForwarded: base.GetIsForwarded(),
+	}
+	if duration := int(base.GetDuration()); duration > 0 {
+		request.Duration = &duration
+	}
+	utils.SanitizePhone(&request.Phone)
+	return request
+}
+
+// replyBaseRequest is baseRequest for requests that carry a reply_message_id.
+func replyBaseRequest(base *pb.SendBase, replyMessageID string) domainSend.BaseRequest {
+	request := baseRequest(base)
+	request.ReplyMessageID = optional(replyMessageID)
+	return request
+}
+
+// optional returns nil for an unset proto3 string, which the usecases read as absent.
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// send resolves the device of the call and runs fn against it.
+func send(ctx context.Context, fn func(context.Context) (domainSend.GenericResponse, error)) (*pb.SendResponse, error) {
+	ctx, err := contextWithDevice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response, err := fn(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.SendResponse{MessageId: response.MessageID, Status: response.Status}, nil
+}
+
+func (s *Server) SendMessage(ctx context.Context, req *pb.SendMessageRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendText(ctx, domainSend.MessageRequest{
+			BaseRequest: replyBaseRequest(req.GetBase(), req.GetReplyMessageId()),
+			Message:     req.GetMessage(),
+			Mentions:    req.GetMentions(),
+		})
+	})
+}
+
+func (s *Server) SendImage(ctx context.Context, req *pb.SendImageRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendImage(ctx, domainSend.ImageRequest{
+			BaseRequest: replyBaseRequest(req.GetBase(), req.GetReplyMessageId()),
+			ImageURL:    optional(req.GetImageUrl()),
+			Caption:     req.GetCaption(),
+			ViewOnce:    req.GetViewOnce(),
+			Compress:    req.GetCompress(),
+		})
+	})
+}
+
+func (s *Server) SendVideo(ctx context.Context, req *pb.SendVideoRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendVideo(ctx, domainSend.VideoRequest{
+			BaseRequest: replyBaseRequest(req.GetBase(), req.GetReplyMessageId()),
+			VideoURL:    optional(req.GetVideoUrl()),
+			Caption:     req.GetCaption(),
+			ViewOnce:    req.GetViewOnce(),
+			Compress:    req.GetCompress(),
+			GifPlayback: req.GetGifPlayback(),
+		})
+	})
+}
+
+func (s *Server) SendAudio(ctx context.Context, req *pb.SendAudioRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendAudio(ctx, domainSend.AudioRequest{
+			BaseRequest: replyBaseRequest(req.GetBase(), req.GetReplyMessageId()),
+			AudioURL:    optional(req.GetAudioUrl()),
+			PTT:         req.GetPtt(),
+		})
+	})
+}
+
+func (s *Server) SendFile(ctx context.Context, req *pb.SendFileRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendFile(ctx, domainSend.FileRequest{
+			BaseRequest: replyBaseRequest(req.GetBase(), req.GetReplyMessageId()),
+			FileURL:     optional(req.GetFileUrl()),
+			Caption:     req.GetCaption(),
+		})
+	})
+}
+
+func (s *Server) SendSticker(ctx context.Context, req *pb.SendStickerRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendSticker(ctx, domainSend.StickerRequest{
+			BaseRequest: baseRequest(req.GetBase()),
+			StickerURL:  optional(req.GetStickerUrl()),
+		})
+	})
+}
+
+func (s *Server) SendContact(ctx context.Context, req *pb.SendContactRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendContact(ctx, domainSend.ContactRequest{
+			BaseRequest:  baseRequest(req.GetBase()),
+			ContactName:  req.GetContactName(),
+			ContactPhone: req.GetContactPhone(),
+		})
+	})
+}
+
+func (s *Server) SendLink(ctx context.Context, req *pb.SendLinkRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendLink(ctx, domainSend.LinkRequest{
+			BaseRequest: baseRequest(req.GetBase()),
+			Link:        req.GetLink(),
+			Caption:     req.GetCaption(),
+		})
+	})
+}
+
+func (s *Server) SendLocation(ctx context.Context, req *pb.SendLocationRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendLocation(ctx, domainSend.LocationRequest{
+			BaseRequest: baseRequest(req.GetBase()),
+			Latitude:    req.GetLatitude(),
+			Longitude:   req.GetLongitude(),
+		})
+	})
+}
+
+func (s *Server) SendPoll(ctx context.Context, req *pb.SendPollRequest) (*pb.SendResponse, error) {
+	return send(ctx, func(ctx context.Context) (domainSend.GenericResponse, error) {
+		return s.sendService.SendPoll(ctx, domainSend.PollRequest{
+			BaseRequest: baseRequest(req.GetBase()),
+			Question:    req.GetQuestion(),
+			Options:     req.GetOptions(),
+			MaxAnswer:   int(req.GetMaxAnswer()),
+		})
+	})
+}
@@ -0,0 +1,160 @@
+// Package grpc serves the WhatsApp gRPC API defined in proto/whatsapp.proto:
+// live event streaming and a subset of the send endpoints of the REST API.
+package grpc
+
+//go:generate protoc --proto_path=proto --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative whatsapp.proto
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/grpc/pb"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DeviceIDMetadata selects the device of a call, like the X-Device-Id header.
+const DeviceIDMetadata = "x-device-id"
+
+type Server struct {
+	pb.UnimplementedWhatsAppServer
+	sendService domainSend.ISendUsecase
+}
+
+// NewServer returns a gRPC server with the WhatsApp service registered,
+// checking APP_BASIC_AUTH credentials on every call.
+func NewServer(sendService domainSend.ISendUsecase) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(recoveryUnaryInterceptor, authUnaryInterceptor),
+		grpc.ChainStreamInterceptor(recoveryStreamInterceptor, authStreamInterceptor),
+	)
+	pb.RegisterWhatsAppServer(server, &Server{sendService: sendService})
+	return server
+}
+
+// authorize accepts a call when no basic auth is configured or its
+// "authorization" metadata carries one of the configured user:password pairs.
+func authorize(ctx context.Context) error {
+	if len(config.AppBasicAuthCredential) == 0 {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		encoded, found := strings.CutPrefix(value, "Basic ")
+		if !found {
+			continue
+		}
+		credential, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			continue
+		}
+		for _, allowed := range config.AppBasicAuthCredential {
+			if subtle.ConstantTimeCompare(credential, []byte(allowed)) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "valid basic auth credentials are required in the authorization metadata")
+}
+
+func authUnaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func authStreamInterceptor(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := authorize(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// Handlers do not panic like the REST ones, but a bug must not take the whole
+// server down.
+func recoveryUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("gRPC: panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Errorf(codes.Internal, "%v", r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoveryStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("gRPC: panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Errorf(codes.Internal, "%v", r)
+		}
+	}()
+	return handler(srv, stream)
+}
+
+// contextWithDevice resolves the device of a call from its x-device-id
+// metadata, falling back to the default/only device.
+func contextWithDevice(ctx context.Context) (context.Context, error) {
+	dm := whatsapp.GetDeviceManager()
+	if dm == nil {
+		return ctx, status.Error(codes.Unavailable, "device manager is not initialized")
+	}
+	var deviceID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(DeviceIDMetadata); len(values) > 0 {
+			deviceID = strings.TrimSpace(values[0])
+		}
+	}
+	instance, resolvedID, err := dm.ResolveDevice(deviceID)
+	if err != nil {
+		if resolvedID != "" || deviceID != "" {
+			return ctx, status.Errorf(codes.NotFound, "device %s not found", resolvedID)
+		}
+		return ctx, status.Error(codes.InvalidArgument, "device_id is required via x-device-id metadata")
+	}
+	return whatsapp.ContextWithDevice(ctx, instance), nil
+}
+
+// toStatus maps a usecase error to a gRPC status, keeping the error code of
+// the REST API in the message.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, "request timed out waiting for WhatsApp server response")
+	}
+	var generic pkgError.GenericError
+	if !errors.As(err, &generic) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	code := codes.Internal
+	switch generic.StatusCode() {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusRequestTimeout:
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, generic.ErrCode()+": "+generic.Error())
+}
@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/grpc/pb"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) pb.WhatsAppClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(nil)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewWhatsAppClient(conn)
+}
+
+func TestStreamEventsDeliversSubscribedEvents(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&pb.EventCommand{Action: pb.EventCommand_ACTION_SUBSCRIBE, Id: "acks", Events: []string{"message.ack"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The subscription is applied asynchronously, so publish until it arrives.
+	received := make(chan *pb.Event, 1)
+	go func() {
+		event, err := stream.Recv()
+		if err == nil {
+			received <- event
+		}
+	}()
+	var event *pb.Event
+	for event == nil {
+		websocket.PublishEvent(websocket.Event{Name: "message", Payload: map[string]any{"event": "message"}})
+		websocket.PublishEvent(websocket.Event{
+			Name:     "message.ack",
+			DeviceID: "628000@s.whatsapp.net",
+			Payload:  map[string]any{"event": "message.ack", "device_id": "628000@s.whatsapp.net"},
+		})
+		select {
+		case event = <-received:
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("no event received")
+		}
+	}
+
+	if event.GetEvent() != "message.ack" || event.GetDeviceId() != "628000@s.whatsapp.net" || len(event.GetSubscriptions()) != 1 || event.GetSubscriptions()[0] != "acks" {
+		t.Errorf("event = %+v", event)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(event.GetPayload(), &payload); err != nil || payload["event"] != "message.ack" {
+		t.Errorf("payload = %s (%v)", event.GetPayload(), err)
+	}
+}
+
+func TestCallsRequireBasicAuthWhenConfigured(t *testing.T) {
+	previous := config.AppBasicAuthCredential
+	config.AppBasicAuthCredential = []string{"admin:secret"}
+	t.Cleanup(func() { config.AppBasicAuthCredential = previous })
+
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.SendMessage(ctx, &pb.SendMessageRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("without credentials: %v", err)
+	}
+	wrong := metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("admin:nope")))
+	if _, err := client.SendMessage(wrong, &pb.SendMessageRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("with wrong credentials: %v", err)
+	}
+
+	authorized := metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("admin:secret")))
+	stream, err := client.StreamEvents(authorized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) == codes.Unauthenticated {
+		t.Fatalf("with credentials: %v", err)
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	err := toStatus(pkgError.ValidationError("phone: cannot be blank."))
+	if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != "VALIDATION_ERROR: phone: cannot be blank." {
+		t.Errorf("validation error = %v", err)
+	}
+	if status.Code(toStatus(context.DeadlineExceeded)) != codes.DeadlineExceeded {
+		t.Error("deadline errors should map to DeadlineExceeded")
+	}
+}
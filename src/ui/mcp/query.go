@@ -35,6 +35,7 @@ func (h *QueryHandler) AddQueryTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(h.toolGetChatMessages(), h.handleGetChatMessages)
 	mcpServer.AddTool(h.toolDownloadMedia(), h.handleDownloadMedia)
 	mcpServer.AddTool(h.toolArchiveChat(), h.handleArchiveChat)
+	mcpServer.AddTool(h.toolListLabels(), h.handleListLabels)
 }
 
 func (h *QueryHandler) toolListContacts() mcp.Tool {
@@ -86,6 +87,18 @@ func (h *QueryHandler) toolListChats() mcp.Tool {
 			mcp.Description("If true, return only chats that contain media messages."),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithBoolean("pinned",
+			mcp.Description("If provided, filter pinned (true) or unpinned (false) chats."),
+		),
+		mcp.WithBoolean("muted",
+			mcp.Description("If provided, filter muted (true) or unmuted (false) chats."),
+		),
+		mcp.WithBoolean("unread",
+			mcp.Description("If provided, filter chats with (true) or without (false) unread messages."),
+		),
+		mcp.WithString("label_id",
+			mcp.Description("If provided, return only chats carrying this WhatsApp Business label (see whatsapp_list_labels)."),
+		),
 	)
 }
 
@@ -96,6 +109,7 @@ func (h *QueryHandler) handleListChats(ctx context.Context, request mcp.CallTool
 	}
 
 	var hasMedia bool
+	var pinnedPtr, mutedPtr, unreadPtr *bool
 	args := request.GetArguments()
 	if args != nil {
 		if value, ok := args["has_media"]; ok {
@@ -105,6 +119,15 @@ func (h *QueryHandler) handleListChats(ctx context.Context, request mcp.CallTool
 			}
 			hasMedia = parsed
 		}
+		for key, target := range map[string]**bool{"pinned": &pinnedPtr, "muted": &mutedPtr, "unread": &unreadPtr} {
+			if value, ok := args[key]; ok {
+				parsed, err := toBool(value)
+				if err != nil {
+					return nil, err
+				}
+				*target = &parsed
+			}
+		}
 	}
 
 	req := domainChat.ListChatsRequest{
@@ -112,6 +135,10 @@ func (h *QueryHandler) handleListChats(ctx context.Context, request mcp.CallTool
 		Offset:   request.GetInt("offset", 0),
 		Search:   request.GetString("search", ""),
 		HasMedia: hasMedia,
+		Pinned:   pinnedPtr,
+		Muted:    mutedPtr,
+		Unread:   unreadPtr,
+		LabelID:  request.GetString("label_id", ""),
 	}
 
 	resp, err := h.chatService.ListChats(ctx, req)
@@ -148,6 +175,9 @@ func (h *QueryHandler) toolGetChatMessages() mcp.Tool {
 			mcp.Description("Number of messages to skip from the start (default 0)."),
 			mcp.DefaultNumber(0),
 		),
+		mcp.WithString("cursor",
+			mcp.Description("Opaque pagination.next_cursor from a previous call; faster than offset for deep pages and cannot be combined with it."),
+		),
 		mcp.WithString("start_time",
 			mcp.Description("Filter messages sent after this RFC3339 timestamp."),
 		),
@@ -223,6 +253,7 @@ func (h *QueryHandler) handleGetChatMessages(ctx context.Context, request mcp.Ca
 		MediaOnly: mediaOnly,
 		IsFromMe:  isFromMePtr,
 		Search:    request.GetString("search", ""),
+		Cursor:    strings.TrimSpace(request.GetString("cursor", "")),
 	}
 
 	resp, err := h.chatService.GetChatMessages(ctx, req)
@@ -366,3 +397,29 @@ func (h *QueryHandler) handleArchiveChat(ctx context.Context, request mcp.CallTo
 	fallback := resp.Message
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
+
+func (h *QueryHandler) toolListLabels() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_list_labels",
+		mcp.WithDescription("List the WhatsApp Business labels of the account with the number of chats carrying each."),
+		mcp.WithTitleAnnotation("List Labels"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *QueryHandler) handleListLabels(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ctx, err := mcpHelpers.ContextWithDefaultDevice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.chatService.ListLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d labels", len(resp.Data))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
@@ -48,7 +48,13 @@ func (s *SendHandler) toolSendText() mcp.Tool {
 			mcp.Description("Message ID to reply to (optional)"),
 		),
 		mcp.WithArray("mentions",
-			mcp.Description("List of phone numbers or JIDs to mention (ghost mentions - users will be notified but @phone won't appear in message text). Use \"@everyone\" to mention all group participants. Example: [\"628123456789\", \"@everyone\"]"),
+			mcp.Description("List of phone numbers or JIDs to mention (ghost mentions - users will be notified but @phone won't appear in message text). Use \"all\" or \"@everyone\" to mention all group participants. Example: [\"628123456789\", \"@everyone\"]"),
+		),
+		mcp.WithBoolean("simulate_typing",
+			mcp.Description("Show \"typing…\" for as long as a person would take to type the message before sending it (default: false)"),
+		),
+		mcp.WithBoolean("link_preview",
+			mcp.Description("Attach a preview of the first URL in the message (default: the server's WHATSAPP_LINK_PREVIEW setting)"),
 		),
 	)
 
@@ -81,6 +87,13 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 		replyMessageId = ""
 	}
 
+	simulateTyping, _ := request.GetArguments()["simulate_typing"].(bool)
+
+	var linkPreview *bool
+	if value, ok := request.GetArguments()["link_preview"].(bool); ok {
+		linkPreview = &value
+	}
+
 	// Parse mentions array (ghost mentions)
 	var mentions []string
 	if mentionsRaw, ok := request.GetArguments()["mentions"].([]any); ok {
@@ -93,12 +106,14 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 
 	res, err := s.sendService.SendText(ctx, domainSend.MessageRequest{
 		BaseRequest: domainSend.BaseRequest{
-			Phone:       phone,
-			IsForwarded: isForwarded,
+			Phone:          phone,
+			IsForwarded:    isForwarded,
+			SimulateTyping: simulateTyping,
+			ReplyMessageID: &replyMessageId,
 		},
-		Message:        message,
-		ReplyMessageID: &replyMessageId,
-		Mentions:       mentions,
+		Message:     message,
+		Mentions:    mentions,
+		LinkPreview: linkPreview,
 	})
 
 	if err != nil {
@@ -1,10 +1,15 @@
 package rest
 
 import (
+	"bufio"
+	"fmt"
+	"strconv"
+
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
 )
 
 type Chat struct {
@@ -16,10 +21,42 @@ func InitRestChat(app fiber.Router, service domainChat.IChatUsecase) Chat {
 
 	// Chat endpoints
 	app.Get("/chats", rest.ListChats)
+	app.Post("/chats/search/reindex", rest.ReindexSearch)
+	app.Get("/chats/search/reindex", rest.GetSearchReindexStatus)
+	app.Get("/chats/doctor", rest.CheckStorage)
+	app.Post("/chats/doctor/repair", rest.RepairStorage)
+	app.Post("/chats/storage/compact", rest.CompactStorage)
+	app.Post("/chats/lid-backfill", rest.StartLIDBackfill)
+	app.Get("/chats/trash", rest.ListTrashedChats)
 	app.Get("/chat/:chat_jid/messages", rest.GetChatMessages)
+	app.Get("/chat/:chat_jid/messages/:message_id/context", rest.GetMessageContext)
+	app.Get("/chat/:chat_jid/stats", rest.GetChatStats)
+	app.Get("/chat/:chat_jid/export", rest.ExportChat)
+	app.Post("/chat/:chat_jid/export", rest.StartExportChatJob)
+	app.Post("/chat/:chat_jid/import", rest.ImportChat)
 	app.Post("/chat/:chat_jid/pin", rest.PinChat)
 	app.Post("/chat/:chat_jid/disappearing", rest.SetDisappearingTimer)
 	app.Post("/chat/:chat_jid/archive", rest.ArchiveChat)
+	app.Post("/chat/:chat_jid/mute", rest.MuteChat)
+	app.Post("/chat/:chat_jid/read", rest.MarkChatRead)
+	app.Post("/chat/:chat_jid/label", rest.LabelChat)
+	app.Post("/chat/:chat_jid/delete", rest.DeleteChat)
+	app.Post("/chat/:chat_jid/restore", rest.RestoreChat)
+	app.Post("/chat/:chat_jid/merge", rest.MergeChat)
+
+	// Label endpoints
+	app.Get("/labels", rest.ListLabels)
+	app.Post("/labels/sync", rest.SyncLabels)
+
+	// Webhook retry queue endpoints
+	app.Get("/webhooks/dead-letters", rest.ListWebhookDeadLetters)
+	app.Post("/webhooks/dead-letters/:delivery_id/retry", rest.RetryWebhookDeadLetter)
+	app.Post("/webhooks/dead-letters/replay", rest.ReplayWebhookDeadLetters)
+	// Deprecated: singular aliases of the routes above, kept for clients of the
+	// first release of dead letter replay.
+	app.Get("/webhooks/dead-letter", rest.ListWebhookDeadLetters)
+	app.Post("/webhooks/dead-letter/:delivery_id/replay", rest.RetryWebhookDeadLetter)
+	app.Post("/webhooks/dead-letter/replay", rest.ReplayWebhookDeadLetters)
 
 	return rest
 }
@@ -36,6 +73,19 @@ func (controller *Chat) ListChats(c *fiber.Ctx) error {
 		isArchived := c.QueryBool("archived")
 		request.Archived = &isArchived
 	}
+	if pinnedStr := c.Query("pinned"); pinnedStr != "" {
+		isPinned := c.QueryBool("pinned")
+		request.Pinned = &isPinned
+	}
+	if mutedStr := c.Query("muted"); mutedStr != "" {
+		isMuted := c.QueryBool("muted")
+		request.Muted = &isMuted
+	}
+	if unreadStr := c.Query("unread"); unreadStr != "" {
+		hasUnread := c.QueryBool("unread")
+		request.Unread = &hasUnread
+	}
+	request.LabelID = c.Query("label_id", "")
 
 	response, err := controller.Service.ListChats(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -59,6 +109,7 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 	request.Offset = c.QueryInt("offset", 0)
 	request.MediaOnly = c.QueryBool("media_only", false)
 	request.Search = c.Query("search", "")
+	request.Cursor = c.Query("cursor", "")
 
 	// Parse time filters
 	if startTime := c.Query("start_time"); startTime != "" {
@@ -85,6 +136,176 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Chat) GetMessageContext(c *fiber.Ctx) error {
+	var request domainChat.GetMessageContextRequest
+
+	// Parse path parameters
+	request.ChatJID = c.Params("chat_jid")
+	request.MessageID = c.Params("message_id")
+
+	request.Before = c.QueryInt("before", 10)
+	request.After = c.QueryInt("after", 10)
+
+	response, err := controller.Service.GetMessageContext(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get message context",
+		Results: response,
+	})
+}
+
+func (controller *Chat) DeleteChat(c *fiber.Ctx) error {
+	var request domainChat.DeleteChatRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	response, err := controller.Service.DeleteChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) RestoreChat(c *fiber.Ctx) error {
+	var request domainChat.RestoreChatRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	response, err := controller.Service.RestoreChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) ListWebhookDeadLetters(c *fiber.Ctx) error {
+	response, err := controller.Service.ListWebhookDeadLetters(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook dead letters",
+		Results: response,
+	})
+}
+
+func (controller *Chat) RetryWebhookDeadLetter(c *fiber.Ctx) error {
+	var request domainChat.RetryWebhookDeadLetterRequest
+
+	// Parse path parameter; an invalid id is left at zero and rejected by validation
+	request.DeliveryID, _ = strconv.ParseInt(c.Params("delivery_id"), 10, 64)
+
+	response, err := controller.Service.RetryWebhookDeadLetter(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) ReplayWebhookDeadLetters(c *fiber.Ctx) error {
+	var request domainChat.ReplayWebhookDeadLettersRequest
+
+	// since may come in the JSON body or the query string
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+				Results: nil,
+			})
+		}
+	}
+	if request.Since == "" {
+		request.Since = c.Query("since")
+	}
+
+	response, err := controller.Service.ReplayWebhookDeadLetters(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) MergeChat(c *fiber.Ctx) error {
+	var request domainChat.MergeChatRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	// Parse JSON body
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	response, err := controller.Service.MergeChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) ListTrashedChats(c *fiber.Ctx) error {
+	response, err := controller.Service.ListTrashedChats(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get trashed chats",
+		Results: response,
+	})
+}
+
+func (controller *Chat) GetChatStats(c *fiber.Ctx) error {
+	var request domainChat.ChatStatsRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+	request.Days = c.QueryInt("days", 30)
+
+	response, err := controller.Service.GetChatStats(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get chat stats",
+		Results: response,
+	})
+}
+
 func (controller *Chat) PinChat(c *fiber.Ctx) error {
 	var request domainChat.PinChatRequest
 
@@ -139,6 +360,57 @@ func (controller *Chat) SetDisappearingTimer(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Chat) LabelChat(c *fiber.Ctx) error {
+	var request domainChat.LabelChatRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	// Parse JSON body
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	response, err := controller.Service.LabelChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) ListLabels(c *fiber.Ctx) error {
+	response, err := controller.Service.ListLabels(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get labels",
+		Results: response,
+	})
+}
+
+func (controller *Chat) SyncLabels(c *fiber.Ctx) error {
+	response, err := controller.Service.SyncLabels(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Labels synced from WhatsApp",
+		Results: response,
+	})
+}
+
 func (controller *Chat) ArchiveChat(c *fiber.Ctx) error {
 	var request domainChat.ArchiveChatRequest
 
@@ -165,3 +437,216 @@ func (controller *Chat) ArchiveChat(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Chat) MuteChat(c *fiber.Ctx) error {
+	var request domainChat.MuteChatRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	// Parse JSON body
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	response, err := controller.Service.MuteChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) MarkChatRead(c *fiber.Ctx) error {
+	var request domainChat.MarkChatReadRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	// Parse JSON body
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	response, err := controller.Service.MarkChatRead(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Message,
+		Results: response,
+	})
+}
+
+func (controller *Chat) ReindexSearch(c *fiber.Ctx) error {
+	response, err := controller.Service.ReindexSearch(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	if !response.Started {
+		return c.Status(fiber.StatusConflict).JSON(utils.ResponseData{
+			Status:  fiber.StatusConflict,
+			Code:    "REINDEX_ALREADY_RUNNING",
+			Message: "A search index rebuild is already in progress",
+			Results: response.Progress,
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(utils.ResponseData{
+		Status:  fiber.StatusAccepted,
+		Code:    "REINDEX_STARTED",
+		Message: "Search index rebuild started in background",
+		Results: response.Progress,
+	})
+}
+
+func (controller *Chat) CheckStorage(c *fiber.Ctx) error {
+	response, err := controller.Service.CheckStorage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), domainChat.StorageDoctorRequest{})
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Chat storage check found %d issue(s)", response.Issues),
+		Results: response,
+	})
+}
+
+func (controller *Chat) RepairStorage(c *fiber.Ctx) error {
+	response, err := controller.Service.CheckStorage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), domainChat.StorageDoctorRequest{Repair: true})
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Chat storage repair handled %d issue(s), %d failed", response.Issues, len(response.RepairErrors)),
+		Results: response,
+	})
+}
+
+func (controller *Chat) CompactStorage(c *fiber.Ctx) error {
+	response, err := controller.Service.CompactStorage(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Chat storage compacted, %d bytes freed", response.FreedBytes),
+		Results: response,
+	})
+}
+
+func (controller *Chat) GetSearchReindexStatus(c *fiber.Ctx) error {
+	response, err := controller.Service.GetSearchReindexStatus(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get search reindex status",
+		Results: response,
+	})
+}
+
+func (controller *Chat) ExportChat(c *fiber.Ctx) error {
+	var request domainChat.ExportChatRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	// Parse query parameters
+	request.Format = c.Query("format", domainChat.ExportFormatJSON)
+	request.IncludeMedia = c.QueryBool("include_media", false)
+
+	response, err := controller.Service.ExportChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	c.Attachment(response.Filename)
+	c.Set(fiber.HeaderContentType, response.ContentType)
+
+	// Stream the body so large chats never have to be held in memory.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := response.Write(w); err != nil {
+			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Chat export stream failed")
+		}
+		_ = w.Flush()
+	})
+	return nil
+}
+
+// StartExportChatJob runs the export as a background job; download the file
+// from /jobs/:job_id/download once it completes.
+func (controller *Chat) StartLIDBackfill(c *fiber.Ctx) error {
+	response, err := controller.Service.StartLIDBackfill(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	if !response.Started {
+		return c.Status(fiber.StatusConflict).JSON(utils.ResponseData{
+			Status:  fiber.StatusConflict,
+			Code:    "LID_BACKFILL_ALREADY_RUNNING",
+			Message: "A LID backfill is already in progress for this device",
+			Results: response.Job,
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(utils.ResponseData{
+		Status:  fiber.StatusAccepted,
+		Code:    "LID_BACKFILL_STARTED",
+		Message: "LID backfill started in background",
+		Results: response.Job,
+	})
+}
+
+func (controller *Chat) StartExportChatJob(c *fiber.Ctx) error {
+	var request domainChat.ExportChatRequest
+
+	request.ChatJID = c.Params("chat_jid")
+	request.Format = c.Query("format", domainChat.ExportFormatJSON)
+	request.IncludeMedia = c.QueryBool("include_media", false)
+
+	response, err := controller.Service.StartExportChatJob(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusAccepted).JSON(utils.ResponseData{
+		Status:  fiber.StatusAccepted,
+		Code:    "EXPORT_STARTED",
+		Message: "Chat export started in background",
+		Results: response,
+	})
+}
+
+// ImportChat loads a WhatsApp "Export chat" file, uploaded as the multipart
+// field "file", into the chat's stored history.
+func (controller *Chat) ImportChat(c *fiber.Ctx) error {
+	var request domainChat.ImportChatRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.ChatJID = c.Params("chat_jid")
+	file, err := c.FormFile("file")
+	utils.PanicIfNeeded(err)
+	request.File = file
+
+	response, err := controller.Service.ImportChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Imported %d messages", response.Imported),
+		Results: response,
+	})
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type Diagnostics struct{}
+
+func InitRestDiagnostics(app fiber.Router) Diagnostics {
+	rest := Diagnostics{}
+	app.Get("/diagnostics/event-latency", rest.EventLatency)
+	app.Get("/webhooks/deliveries", rest.WebhookDeliveries)
+	app.Get("/debug/lid-cache", rest.LIDCache)
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+	return rest
+}
+
+func (controller *Diagnostics) EventLatency(c *fiber.Ctx) error {
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Event latency stats",
+		Results: whatsapp.GetEventLatencyStats(),
+	})
+}
+
+func (controller *Diagnostics) WebhookDeliveries(c *fiber.Ctx) error {
+	filter := whatsapp.WebhookDeliveryFilter{
+		URL:      c.Query("url"),
+		Event:    c.Query("event"),
+		DeviceID: c.Query("device_id"),
+		Status:   c.Query("status"),
+		Limit:    c.QueryInt("limit", 100),
+	}
+	if filter.Status != "" && filter.Status != "success" && filter.Status != "failed" {
+		utils.PanicIfNeeded(pkgError.ValidationError(fmt.Sprintf("status: must be success or failed, got %q", filter.Status)))
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Recent webhook deliveries",
+		Results: whatsapp.GetWebhookDeliveries(filter),
+	})
+}
+
+// LIDCache lists the LID lookups that failed recently and are held back from
+// querying WhatsApp again.
+func (controller *Diagnostics) LIDCache(c *fiber.Ctx) error {
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Pending failed LID lookups",
+		Results: whatsapp.GetFailedLIDLookups(),
+	})
+}
+
+// WebhookMedia serves a value offloaded from an oversized webhook payload. It
+// is public: the signature in the link is the credential.
+func WebhookMedia(c *fiber.Ctx) error {
+	path, err := whatsapp.WebhookMediaFile(c.Params("name"), c.Query("expires"), c.Query("signature"), time.Now())
+	if err != nil {
+		utils.PanicIfNeeded(pkgError.AuthError(fmt.Sprintf("webhook media: %v", err)))
+	}
+	return c.SendFile(path)
+}
@@ -11,6 +11,7 @@ import (
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 	"go.mau.fi/whatsmeow"
 )
@@ -24,7 +25,7 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group", rest.CreateGroup)
 	app.Post("/group/join-with-link", rest.JoinGroupWithLink)
 	app.Get("/group/info-from-link", rest.GetGroupInfoFromLink)
-	app.Get("/group/info", rest.GroupInfo)
+	app.Get("/group/info", middleware.ResponseCache(whatsapp.GroupInfoTTL), rest.GroupInfo)
 	app.Post("/group/leave", rest.LeaveGroup)
 	app.Get("/group/participants", rest.ListParticipants)
 	app.Get("/group/participants/export", rest.ExportParticipants)
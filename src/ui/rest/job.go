@@ -0,0 +1,67 @@
+package rest
+
+import (
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Job struct {
+	Service domainJob.IJobUsecase
+}
+
+func InitRestJob(app fiber.Router, service domainJob.IJobUsecase) Job {
+	rest := Job{Service: service}
+	app.Get("/jobs", rest.ListJobs)
+	app.Get("/jobs/:job_id", rest.GetJob)
+	app.Post("/jobs/:job_id/cancel", rest.CancelJob)
+	app.Get("/jobs/:job_id/download", rest.DownloadJobResult)
+	return rest
+}
+
+func (controller *Job) ListJobs(c *fiber.Ctx) error {
+	var request domainJob.ListJobsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListJobs(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get jobs",
+		Results: response,
+	})
+}
+
+func (controller *Job) GetJob(c *fiber.Ctx) error {
+	response, err := controller.Service.GetJob(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get job",
+		Results: response,
+	})
+}
+
+func (controller *Job) CancelJob(c *fiber.Ctx) error {
+	response, err := controller.Service.CancelJob(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Job cancellation requested",
+		Results: response,
+	})
+}
+
+func (controller *Job) DownloadJobResult(c *fiber.Ctx) error {
+	response, err := controller.Service.GetJobResult(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.Download(response.Path, response.Filename)
+}
@@ -22,12 +22,19 @@ func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase) Me
 
 	// Message action endpoints
 	app.Post("/message/:message_id/reaction", rest.ReactMessage)
+	app.Post("/message/:message_id/react", rest.ReactMessage)
+	app.Delete("/message/:message_id/react", rest.UnreactMessage)
 	app.Post("/message/:message_id/revoke", rest.RevokeMessage)
 	app.Post("/message/:message_id/delete", rest.DeleteMessage)
 	app.Post("/message/:message_id/update", rest.UpdateMessage)
+	app.Post("/message/:message_id/edit", rest.UpdateMessage)
 	app.Post("/message/:message_id/read", rest.MarkAsRead)
 	app.Post("/message/:message_id/star", rest.StarMessage)
+	app.Post("/message/:message_id/pin", rest.PinMessage)
+	app.Post("/message/:message_id/unpin", rest.UnpinMessage)
 	app.Post("/message/:message_id/unstar", rest.UnstarMessage)
+	app.Post("/message/:message_id/label", rest.LabelMessage)
+	app.Post("/message/:message_id/forward", rest.ForwardMessage)
 	app.Get("/message/:message_id/download", rest.DownloadMedia)
 	return rest
 }
@@ -108,6 +115,32 @@ func (controller *Message) ReactMessage(c *fiber.Ctx) error {
 	})
 }
 
+// UnreactMessage removes our reaction from a message.
+func (controller *Message) UnreactMessage(c *fiber.Ctx) error {
+	var request domainMessage.ReactionRequest
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+	if request.Phone == "" {
+		request.Phone = c.Query("phone")
+	}
+
+	request.MessageID = c.Params("message_id")
+	request.Emoji = ""
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.ReactMessage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Message) MarkAsRead(c *fiber.Ctx) error {
 	var request domainMessage.MarkAsReadRequest
 	err := c.BodyParser(&request)
@@ -127,6 +160,84 @@ func (controller *Message) MarkAsRead(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Message) LabelMessage(c *fiber.Ctx) error {
+	var request domainMessage.LabelMessageRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.LabelMessage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Message) ForwardMessage(c *fiber.Ctx) error {
+	var request domainMessage.ForwardMessageRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.ForwardMessage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Message) PinMessage(c *fiber.Ctx) error {
+	var request domainMessage.PinMessageRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	utils.SanitizePhone(&request.Phone)
+	request.Pinned = true
+
+	response, err := controller.Service.PinMessage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Message) UnpinMessage(c *fiber.Ctx) error {
+	var request domainMessage.PinMessageRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	utils.SanitizePhone(&request.Phone)
+	request.Pinned = false
+
+	response, err := controller.Service.PinMessage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Message) StarMessage(c *fiber.Ctx) error {
 	var request domainMessage.StarRequest
 	err := c.BodyParser(&request)
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -36,6 +37,9 @@ func Recovery() fiber.Handler {
 					res.Code = errValidation.ErrCode()
 					res.Message = errValidation.Error()
 				}
+				if errRetry, ok := err.(pkgError.RetryAfterError); ok {
+					ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(pkgError.RetryAfterSeconds(errRetry.RetryAfter())))
+				}
 
 				_ = ctx.Status(res.Status).JSON(res)
 			}
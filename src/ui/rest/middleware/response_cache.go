@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// ResponseCacheHeader reports whether a response was served from the response cache.
+	ResponseCacheHeader = "X-Cache"
+	responseCacheHit    = "HIT"
+	responseCacheMiss   = "MISS"
+)
+
+// ResponseCache caches successful GET responses per device for ttl and answers
+// conditional requests with 304 Not Modified when the client's If-None-Match
+// matches. The entries live in the device's InfoCache, so they share its TTLs
+// and are dropped together with it when the device is logged out or removed.
+func ResponseCache(ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		deviceID, _ := c.Locals("device_id").(string)
+		infoCache := whatsapp.GetInfoCache(deviceID)
+		key := responseCacheKey(c)
+		maxAge := fmt.Sprintf("private, max-age=%d", int(ttl.Seconds()))
+
+		if cached, ok := infoCache.GetHTTPResponse(key); ok {
+			c.Set(fiber.HeaderETag, cached.ETag)
+			c.Set(fiber.HeaderCacheControl, maxAge)
+			c.Set(ResponseCacheHeader, responseCacheHit)
+			if etagMatches(c.Get(fiber.HeaderIfNoneMatch), cached.ETag) {
+				return c.SendStatus(fiber.StatusNotModified)
+			}
+			c.Set(fiber.HeaderContentType, cached.ContentType)
+			return c.Send(cached.Body)
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		// fasthttp reuses the response buffer once the request completes, so keep a copy.
+		body := append([]byte(nil), c.Response().Body()...)
+		sum := sha256.Sum256(body)
+		result := &whatsapp.HTTPResponseResult{
+			Body:        body,
+			ContentType: string(c.Response().Header.ContentType()),
+			ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		}
+		infoCache.SetHTTPResponse(key, result, ttl)
+
+		c.Set(fiber.HeaderETag, result.ETag)
+		c.Set(fiber.HeaderCacheControl, maxAge)
+		c.Set(ResponseCacheHeader, responseCacheMiss)
+		if etagMatches(c.Get(fiber.HeaderIfNoneMatch), result.ETag) {
+			c.Response().ResetBody()
+			c.Status(fiber.StatusNotModified)
+		}
+		return nil
+	}
+}
+
+// responseCacheKey identifies a request by path and its query parameters in a
+// stable order, ignoring device_id since entries are already scoped per device.
+func responseCacheKey(c *fiber.Ctx) string {
+	query := url.Values{}
+	for k, v := range c.Queries() {
+		if k == "device_id" {
+			continue
+		}
+		query.Set(k, v)
+	}
+	if len(query) == 0 {
+		return c.Path()
+	}
+	// url.Values.Encode sorts by key.
+	return c.Path() + "?" + query.Encode()
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
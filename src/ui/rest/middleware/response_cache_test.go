@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResponseCacheApp(deviceID string, calls *int) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("device_id", deviceID)
+		return c.Next()
+	})
+	app.Get("/user/info", ResponseCache(time.Minute), func(c *fiber.Ctx) error {
+		*calls++
+		if c.Query("phone") == "" {
+			return c.Status(fiber.StatusBadRequest).SendString("phone required")
+		}
+		return c.JSON(fiber.Map{"phone": c.Query("phone")})
+	})
+	return app
+}
+
+func TestResponseCache_ServesRepeatedRequestsFromCache(t *testing.T) {
+	t.Cleanup(func() { whatsapp.ClearDeviceCache("resp-cache-dev") })
+
+	calls := 0
+	app := newResponseCacheApp("resp-cache-dev", &calls)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/user/info?phone=628123", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "MISS", resp.Header.Get(ResponseCacheHeader))
+	etag := resp.Header.Get(fiber.HeaderETag)
+	assert.NotEmpty(t, etag)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/user/info?phone=628123", nil), -1)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "HIT", resp.Header.Get(ResponseCacheHeader))
+	assert.Equal(t, etag, resp.Header.Get(fiber.HeaderETag))
+	assert.JSONEq(t, `{"phone":"628123"}`, string(body))
+	assert.Equal(t, 1, calls)
+
+	// A different query is a different entry.
+	_, err = app.Test(httptest.NewRequest("GET", "/user/info?phone=628999", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestResponseCache_IfNoneMatchReturnsNotModified(t *testing.T) {
+	t.Cleanup(func() { whatsapp.ClearDeviceCache("resp-cache-etag") })
+
+	calls := 0
+	app := newResponseCacheApp("resp-cache-etag", &calls)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/user/info?phone=628123", nil), -1)
+	assert.NoError(t, err)
+	etag := resp.Header.Get(fiber.HeaderETag)
+
+	req := httptest.NewRequest("GET", "/user/info?phone=628123", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req, -1)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	assert.Empty(t, body)
+	assert.Equal(t, 1, calls)
+}
+
+func TestResponseCache_DoesNotCacheErrorsOrLeakAcrossDevices(t *testing.T) {
+	t.Cleanup(func() {
+		whatsapp.ClearDeviceCache("resp-cache-a")
+		whatsapp.ClearDeviceCache("resp-cache-b")
+	})
+
+	calls := 0
+	app := newResponseCacheApp("resp-cache-a", &calls)
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest("GET", "/user/info", nil), -1)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	}
+	assert.Equal(t, 2, calls)
+
+	_, err := app.Test(httptest.NewRequest("GET", "/user/info?phone=628123", nil), -1)
+	assert.NoError(t, err)
+
+	otherCalls := 0
+	other := newResponseCacheApp("resp-cache-b", &otherCalls)
+	resp, err := other.Test(httptest.NewRequest("GET", "/user/info?phone=628123", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, "MISS", resp.Header.Get(ResponseCacheHeader))
+	assert.Equal(t, 1, otherCalls)
+}
@@ -14,6 +14,7 @@ type Newsletter struct {
 func InitRestNewsletter(app fiber.Router, service domainNewsletter.INewsletterUsecase) Newsletter {
 	rest := Newsletter{Service: service}
 	app.Post("/newsletter/unfollow", rest.Unfollow)
+	app.Get("/newsletter/:newsletter_id/messages", rest.GetMessages)
 	return rest
 }
 
@@ -31,3 +32,20 @@ func (controller *Newsletter) Unfollow(c *fiber.Ctx) error {
 		Message: "Success unfollow newsletter",
 	})
 }
+
+func (controller *Newsletter) GetMessages(c *fiber.Ctx) error {
+	var request domainNewsletter.GetMessagesRequest
+	request.NewsletterID = c.Params("newsletter_id")
+	request.Count = c.QueryInt("count", 50)
+	request.Before = c.QueryInt("before", 0)
+
+	response, err := controller.Service.GetMessages(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get newsletter messages",
+		Results: response,
+	})
+}
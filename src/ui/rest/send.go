@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"strings"
+
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -17,14 +19,30 @@ func InitRestSend(app fiber.Router, service domainSend.ISendUsecase) Send {
 	app.Post("/send/image", rest.SendImage)
 	app.Post("/send/file", rest.SendFile)
 	app.Post("/send/video", rest.SendVideo)
+	app.Post("/send/album", rest.SendAlbum)
 	app.Post("/send/sticker", rest.SendSticker)
 	app.Post("/send/contact", rest.SendContact)
 	app.Post("/send/link", rest.SendLink)
 	app.Post("/send/location", rest.SendLocation)
+	app.Post("/send/live-location", rest.SendLiveLocation)
 	app.Post("/send/audio", rest.SendAudio)
 	app.Post("/send/poll", rest.SendPoll)
+	app.Post("/send/product", rest.SendProduct)
 	app.Post("/send/presence", rest.SendPresence)
 	app.Post("/send/chat-presence", rest.SendChatPresence)
+	app.Post("/send/campaign", rest.StartCampaign)
+	app.Get("/send/campaign/:job_id", rest.GetCampaign)
+	app.Get("/send/queue", rest.ListQueue)
+	app.Post("/send/template", rest.SendTemplate)
+	app.Get("/templates", rest.ListTemplates)
+	app.Post("/templates", rest.SaveTemplate)
+	app.Get("/templates/:name", rest.GetTemplate)
+	app.Delete("/templates/:name", rest.DeleteTemplate)
+	app.Get("/drafts", rest.ListDrafts)
+	app.Get("/chat/:chat_jid/draft", rest.GetDraft)
+	app.Post("/chat/:chat_jid/draft", rest.SaveDraft)
+	app.Delete("/chat/:chat_jid/draft", rest.DeleteDraft)
+	app.Post("/chat/:chat_jid/draft/send", rest.SendDraft)
 	return rest
 }
 
@@ -34,6 +52,7 @@ func (controller *Send) SendText(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendText(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -59,6 +78,7 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendImage(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -81,6 +101,7 @@ func (controller *Send) SendFile(c *fiber.Ctx) error {
 
 	request.File = file
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendFile(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -104,6 +125,7 @@ func (controller *Send) SendVideo(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendVideo(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -116,6 +138,41 @@ func (controller *Send) SendVideo(c *fiber.Ctx) error {
 	})
 }
 
+// SendAlbum takes the items of the JSON body, or multipart uploads in the
+// repeated "media" field with their captions in the repeated "captions" field.
+func (controller *Send) SendAlbum(c *fiber.Ctx) error {
+	var request domainSend.AlbumRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if form, errForm := c.MultipartForm(); errForm == nil {
+		captions := form.Value["captions"]
+		for i, file := range form.File["media"] {
+			item := domainSend.AlbumItem{Type: domainSend.AlbumItemImage, File: file}
+			if strings.HasPrefix(file.Header.Get(fiber.HeaderContentType), "video/") {
+				item.Type = domainSend.AlbumItemVideo
+			}
+			if i < len(captions) {
+				item.Caption = captions[i]
+			}
+			request.Items = append(request.Items, item)
+		}
+	}
+
+	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
+
+	response, err := controller.Service.SendAlbum(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Send) SendSticker(c *fiber.Ctx) error {
 	var request domainSend.StickerRequest
 	err := c.BodyParser(&request)
@@ -127,6 +184,7 @@ func (controller *Send) SendSticker(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendSticker(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -145,6 +203,7 @@ func (controller *Send) SendContact(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendContact(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -163,6 +222,7 @@ func (controller *Send) SendLink(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendLink(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -181,6 +241,7 @@ func (controller *Send) SendLocation(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendLocation(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -193,6 +254,25 @@ func (controller *Send) SendLocation(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Send) SendLiveLocation(c *fiber.Ctx) error {
+	var request domainSend.LiveLocationRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
+
+	response, err := controller.Service.SendLiveLocation(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Send) SendAudio(c *fiber.Ctx) error {
 	var request domainSend.AudioRequest
 	err := c.BodyParser(&request)
@@ -204,6 +284,7 @@ func (controller *Send) SendAudio(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendAudio(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -222,6 +303,7 @@ func (controller *Send) SendPoll(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendPoll(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
@@ -234,6 +316,25 @@ func (controller *Send) SendPoll(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Send) SendProduct(c *fiber.Ctx) error {
+	var request domainSend.ProductRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
+
+	response, err := controller.Service.SendProduct(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Send) SendPresence(c *fiber.Ctx) error {
 	var request domainSend.PresenceRequest
 	err := c.BodyParser(&request)
@@ -267,3 +368,218 @@ func (controller *Send) SendChatPresence(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+// StartCampaign sends a templated message to many recipients as a background
+// job. Recipients come from the JSON body or a "recipients_csv" upload; cancel
+// the campaign with /jobs/:job_id/cancel.
+func (controller *Send) StartCampaign(c *fiber.Ctx) error {
+	var request domainSend.CampaignRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if csvFile, errFile := c.FormFile("recipients_csv"); errFile == nil {
+		request.RecipientsCSV = csvFile
+	}
+
+	response, err := controller.Service.StartCampaign(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusAccepted).JSON(utils.ResponseData{
+		Status:  fiber.StatusAccepted,
+		Code:    "CAMPAIGN_STARTED",
+		Message: "Campaign started in background",
+		Results: response,
+	})
+}
+
+func (controller *Send) GetCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.GetCampaign(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaign",
+		Results: response,
+	})
+}
+
+func (controller *Send) ListQueue(c *fiber.Ctx) error {
+	request := domainSend.ListQueueRequest{
+		Status: c.Query("status"),
+		Limit:  c.QueryInt("limit", 100),
+	}
+	response, err := controller.Service.ListQueue(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get queued messages",
+		Results: response,
+	})
+}
+
+func (controller *Send) ListDrafts(c *fiber.Ctx) error {
+	response, err := controller.Service.ListDrafts(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get drafts",
+		Results: response,
+	})
+}
+
+func (controller *Send) GetDraft(c *fiber.Ctx) error {
+	chatJID := c.Params("chat_jid")
+	utils.SanitizePhone(&chatJID)
+
+	response, err := controller.Service.GetDraft(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), chatJID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get draft",
+		Results: response,
+	})
+}
+
+func (controller *Send) SaveDraft(c *fiber.Ctx) error {
+	var request domainSend.DraftRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.ChatJID = c.Params("chat_jid")
+	utils.SanitizePhone(&request.ChatJID)
+
+	response, err := controller.Service.SaveDraft(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Draft saved",
+		Results: response,
+	})
+}
+
+func (controller *Send) DeleteDraft(c *fiber.Ctx) error {
+	chatJID := c.Params("chat_jid")
+	utils.SanitizePhone(&chatJID)
+
+	err := controller.Service.DeleteDraft(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), chatJID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Draft deleted",
+	})
+}
+
+// SendDraft sends the chat's draft as a text message and clears it; the draft
+// is kept when sending fails. The optional body takes is_forwarded and duration
+// like /send/message.
+func (controller *Send) SendDraft(c *fiber.Ctx) error {
+	var request domainSend.SendDraftRequest
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+
+	request.Phone = c.Params("chat_jid")
+	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
+
+	response, err := controller.Service.SendDraft(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Send) ListTemplates(c *fiber.Ctx) error {
+	response, err := controller.Service.ListTemplates(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get templates",
+		Results: response,
+	})
+}
+
+func (controller *Send) GetTemplate(c *fiber.Ctx) error {
+	response, err := controller.Service.GetTemplate(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("name"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get template",
+		Results: response,
+	})
+}
+
+// SaveTemplate creates a template, or replaces the one with the same name.
+func (controller *Send) SaveTemplate(c *fiber.Ctx) error {
+	var request domainSend.TemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.SaveTemplate(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template saved",
+		Results: response,
+	})
+}
+
+func (controller *Send) DeleteTemplate(c *fiber.Ctx) error {
+	err := controller.Service.DeleteTemplate(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("name"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template deleted",
+	})
+}
+
+func (controller *Send) SendTemplate(c *fiber.Ctx) error {
+	var request domainSend.SendTemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+	dedupIDFromHeader(c, &request.BaseRequest)
+
+	response, err := controller.Service.SendTemplate(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+// dedupIDFromHeader takes the idempotency key of a send from the
+// Idempotency-Key header when the body carries no dedup_id.
+func dedupIDFromHeader(c *fiber.Ctx, request *domainSend.BaseRequest) {
+	if request.DedupID == "" {
+		request.DedupID = c.Get("Idempotency-Key")
+	}
+}
@@ -0,0 +1,48 @@
+package rest
+
+import (
+	domainSettings "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/settings"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Settings struct {
+	Service domainSettings.ISettingsUsecase
+}
+
+func InitRestSettings(app fiber.Router, service domainSettings.ISettingsUsecase) Settings {
+	rest := Settings{Service: service}
+	app.Get("/settings/export", rest.ExportSettings)
+	app.Post("/settings/import", rest.ImportSettings)
+	return rest
+}
+
+func (controller *Settings) ExportSettings(c *fiber.Ctx) error {
+	document, err := controller.Service.ExportSettings(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	c.Set(fiber.HeaderContentType, "application/yaml")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="settings.yaml"`)
+	return c.Send(document)
+}
+
+func (controller *Settings) ImportSettings(c *fiber.Ctx) error {
+	var request domainSettings.ImportSettingsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+	request.Document = c.Body()
+
+	response, err := controller.Service.ImportSettings(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Settings imported"
+	if response.DryRun {
+		message = "Settings validated, nothing applied"
+	}
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+		Results: response,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,8 +14,8 @@ type User struct {
 
 func InitRestUser(app fiber.Router, service domainUser.IUserUsecase) User {
 	rest := User{Service: service}
-	app.Get("/user/info", rest.UserInfo)
-	app.Get("/user/avatar", rest.UserAvatar)
+	app.Get("/user/info", middleware.ResponseCache(whatsapp.UserInfoTTL), rest.UserInfo)
+	app.Get("/user/avatar", middleware.ResponseCache(whatsapp.UserAvatarTTL), rest.UserAvatar)
 	app.Post("/user/avatar", rest.UserChangeAvatar)
 	app.Post("/user/pushname", rest.UserChangePushName)
 	app.Get("/user/my/privacy", rest.UserMyPrivacySetting)
@@ -23,6 +24,9 @@ func InitRestUser(app fiber.Router, service domainUser.IUserUsecase) User {
 	app.Get("/user/my/contacts", rest.UserMyListContacts)
 	app.Get("/user/check", rest.UserCheck)
 	app.Get("/user/business-profile", rest.UserBusinessProfile)
+	app.Get("/user/business-catalog", rest.UserBusinessCatalog)
+	app.Get("/contacts/resolve", rest.ResolveContact)
+	app.Post("/contacts/resolve", rest.ResolveContacts)
 
 	return rest
 }
@@ -195,6 +199,68 @@ func (controller *User) UserBusinessProfile(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *User) UserBusinessCatalog(c *fiber.Ctx) error {
+	var request domainUser.BusinessCatalogRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	ctx := whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c))
+
+	response, err := controller.Service.BusinessCatalog(ctx, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get business catalog",
+		Results: response,
+	})
+}
+
+func (controller *User) ResolveContact(c *fiber.Ctx) error {
+	var request domainUser.ResolveContactRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	ctx := whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c))
+
+	response, err := controller.Service.ResolveContact(ctx, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success resolve contact",
+		Results: response,
+	})
+}
+
+func (controller *User) ResolveContacts(c *fiber.Ctx) error {
+	var request domainUser.ResolveContactsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	for i := range request.JIDs {
+		utils.SanitizePhone(&request.JIDs[i])
+	}
+
+	ctx := whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c))
+
+	response, err := controller.Service.ResolveContacts(ctx, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success resolve contacts",
+		Results: response,
+	})
+}
+
 func getDeviceFromCtx(c *fiber.Ctx) *whatsapp.DeviceInstance {
 	if c == nil {
 		return nil
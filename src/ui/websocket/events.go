@@ -0,0 +1,292 @@
+package websocket
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// eventSendBuffer is how many events may wait for a slow subscriber before it
+// is disconnected, so one stalled dashboard never holds up event handling.
+const eventSendBuffer = 256
+
+// Event is a live event offered to /ws/events subscribers. Payload is the
+// webhook payload of the event.
+type Event struct {
+	Name      string
+	DeviceID  string
+	SessionID string
+	ChatJID   string
+	Payload   any
+}
+
+// EventFilter narrows a subscription. Empty fields match everything; DeviceID
+// matches the device JID or the session id from POST /devices.
+type EventFilter struct {
+	Events   []string `json:"events"`
+	DeviceID string   `json:"device_id"`
+	ChatJID  string   `json:"chat_id"`
+}
+
+func (f EventFilter) matches(event Event) bool {
+	if len(f.Events) > 0 && !slices.Contains(f.Events, event.Name) {
+		return false
+	}
+	if f.DeviceID != "" && f.DeviceID != event.DeviceID && f.DeviceID != event.SessionID {
+		return false
+	}
+	return f.ChatJID == "" || f.ChatJID == event.ChatJID
+}
+
+// eventCommand is what a client sends to manage its subscriptions.
+type eventCommand struct {
+	Action string `json:"action"` // "subscribe" or "unsubscribe"
+	ID     string `json:"id"`
+	EventFilter
+}
+
+// EventMessage is what a subscriber receives for each matching event.
+type EventMessage struct {
+	Subscriptions []string        `json:"subscriptions"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type eventSubscriber struct {
+	send chan []byte
+
+	mu      sync.Mutex
+	filters map[string]EventFilter
+	closed  bool
+	slow    bool
+}
+
+var eventSubscribers = struct {
+	sync.RWMutex
+	set   map[*eventSubscriber]struct{}
+	count atomic.Int32
+}{set: make(map[*eventSubscriber]struct{})}
+
+// HasEventSubscribers reports whether any /ws/events client is connected, so
+// events can be built for them even without webhook targets.
+func HasEventSubscribers() bool {
+	return eventSubscribers.count.Load() > 0
+}
+
+// PublishEvent offers event to every subscriber with a matching subscription.
+// It never blocks: a subscriber whose buffer is full is disconnected.
+func PublishEvent(event Event) {
+	if !HasEventSubscribers() {
+		return
+	}
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		logrus.Errorf("WebSocket events: failed to serialize %s: %v", event.Name, err)
+		return
+	}
+
+	eventSubscribers.RLock()
+	defer eventSubscribers.RUnlock()
+	for sub := range eventSubscribers.set {
+		ids := sub.matching(event)
+		if len(ids) == 0 {
+			continue
+		}
+		message, err := json.Marshal(EventMessage{Subscriptions: ids, Event: event.Name, Payload: payload})
+		if err != nil {
+			continue
+		}
+		sub.deliver(message)
+	}
+}
+
+func (s *eventSubscriber) matching(event Event) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id, filter := range s.filters {
+		if filter.matches(event) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (s *eventSubscriber) deliver(message []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.send <- message:
+	default:
+		logrus.Warn("WebSocket events: subscriber is too slow, disconnecting")
+		s.closed, s.slow = true, true
+		close(s.send)
+	}
+}
+
+func (s *eventSubscriber) tooSlow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slow
+}
+
+func (s *eventSubscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.send)
+	}
+}
+
+func addEventSubscriber(sub *eventSubscriber) {
+	eventSubscribers.Lock()
+	eventSubscribers.set[sub] = struct{}{}
+	eventSubscribers.count.Store(int32(len(eventSubscribers.set)))
+	eventSubscribers.Unlock()
+}
+
+func removeEventSubscriber(sub *eventSubscriber) {
+	eventSubscribers.Lock()
+	delete(eventSubscribers.set, sub)
+	eventSubscribers.count.Store(int32(len(eventSubscribers.set)))
+	eventSubscribers.Unlock()
+	sub.close()
+}
+
+// EventStream subscribes to live events outside /ws/events, such as a gRPC
+// StreamEvents call. It starts without subscriptions and receives the same
+// EventMessage JSON as /ws/events clients, under the same slow-subscriber rule.
+type EventStream struct {
+	sub *eventSubscriber
+}
+
+// OpenEventStream registers a new event subscriber; Close releases it.
+func OpenEventStream() *EventStream {
+	sub := &eventSubscriber{send: make(chan []byte, eventSendBuffer), filters: map[string]EventFilter{}}
+	addEventSubscriber(sub)
+	return &EventStream{sub: sub}
+}
+
+// Messages yields the matching events; it is closed by Close or when the
+// stream fell too far behind.
+func (s *EventStream) Messages() <-chan []byte {
+	return s.sub.send
+}
+
+// Subscribe adds or replaces the subscription id.
+func (s *EventStream) Subscribe(id string, filter EventFilter) {
+	s.sub.mu.Lock()
+	defer s.sub.mu.Unlock()
+	s.sub.filters[id] = filter
+}
+
+// Unsubscribe removes the subscription id.
+func (s *EventStream) Unsubscribe(id string) {
+	s.sub.mu.Lock()
+	defer s.sub.mu.Unlock()
+	delete(s.sub.filters, id)
+}
+
+// TooSlow reports whether Messages was closed because the stream fell behind.
+func (s *EventStream) TooSlow() bool {
+	return s.sub.tooSlow()
+}
+
+func (s *EventStream) Close() {
+	removeEventSubscriber(s.sub)
+}
+
+// filterFromQuery builds the subscription given in the connection URL, if any.
+func filterFromQuery(c *fiber.Ctx) (EventFilter, bool) {
+	filter := EventFilter{
+		DeviceID: strings.TrimSpace(c.Query("device_id")),
+		ChatJID:  strings.TrimSpace(c.Query("chat_id")),
+	}
+	for _, name := range strings.Split(c.Query("events"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filter.Events = append(filter.Events, name)
+		}
+	}
+	hasFilter := len(filter.Events) > 0 || filter.DeviceID != "" || filter.ChatJID != "" || c.Query("subscribe") == "all"
+	return filter, hasFilter
+}
+
+// RegisterEventRoutes exposes GET /ws/events. Clients subscribe with
+// {"action":"subscribe","id":"<id>","events":[...],"device_id":"...","chat_id":"..."}
+// and unsubscribe with {"action":"unsubscribe","id":"<id>"}; filters in the
+// URL query open a subscription with id "default" on connect.
+func RegisterEventRoutes(app fiber.Router) {
+	app.Get("/ws/events", func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return c.SendStatus(fiber.StatusUpgradeRequired)
+		}
+		filter, ok := filterFromQuery(c)
+		c.Locals("eventFilter", filter)
+		c.Locals("eventFilterSet", ok)
+		return c.Next()
+	}, websocket.New(func(conn *websocket.Conn) {
+		sub := &eventSubscriber{send: make(chan []byte, eventSendBuffer), filters: map[string]EventFilter{}}
+		if ok, _ := conn.Locals("eventFilterSet").(bool); ok {
+			sub.filters["default"], _ = conn.Locals("eventFilter").(EventFilter)
+		}
+		addEventSubscriber(sub)
+
+		done := make(chan struct{})
+		// The writer owns all writes; closing the connection also ends the read loop below.
+		go func() {
+			defer close(done)
+			for message := range sub.send {
+				if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+					_ = conn.Close()
+					return
+				}
+			}
+			if sub.tooSlow() {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber too slow"))
+				_ = conn.Close()
+			}
+		}()
+		defer func() {
+			removeEventSubscriber(sub)
+			<-done
+			_ = conn.Close()
+		}()
+
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+					logrus.Println("WebSocket events: read error:", err)
+				}
+				return
+			}
+			if messageType != websocket.TextMessage {
+				continue
+			}
+			var command eventCommand
+			if err := json.Unmarshal(message, &command); err != nil || command.ID == "" {
+				logrus.Debugf("WebSocket events: ignoring malformed command %q", message)
+				continue
+			}
+			sub.mu.Lock()
+			switch command.Action {
+			case "subscribe":
+				sub.filters[command.ID] = command.EventFilter
+			case "unsubscribe":
+				delete(sub.filters, command.ID)
+			}
+			sub.mu.Unlock()
+		}
+	}))
+}
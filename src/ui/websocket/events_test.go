@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPublishEventDeliversToMatchingSubscriptions(t *testing.T) {
+	sub := &eventSubscriber{send: make(chan []byte, 4), filters: map[string]EventFilter{
+		"messages": {Events: []string{"message"}},
+		"org_2":    {DeviceID: "org_2"},
+		"chat":     {ChatJID: "120363000000000001@g.us", Events: []string{"message.ack"}},
+	}}
+	addEventSubscriber(sub)
+	t.Cleanup(func() { removeEventSubscriber(sub) })
+
+	if !HasEventSubscribers() {
+		t.Fatal("expected a subscriber to be registered")
+	}
+
+	PublishEvent(Event{
+		Name:      "message",
+		DeviceID:  "628000@s.whatsapp.net",
+		SessionID: "org_2",
+		ChatJID:   "628123456789@s.whatsapp.net",
+		Payload:   map[string]any{"event": "message"},
+	})
+	// Matches no subscription: another device, and the chat filter wants receipts only.
+	PublishEvent(Event{Name: "group.participants", DeviceID: "628111@s.whatsapp.net", ChatJID: "120363000000000001@g.us"})
+
+	if len(sub.send) != 1 {
+		t.Fatalf("queued %d messages, want 1", len(sub.send))
+	}
+	var got EventMessage
+	if err := json.Unmarshal(<-sub.send, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Event != "message" || len(got.Subscriptions) != 2 || got.Subscriptions[0] != "messages" || got.Subscriptions[1] != "org_2" {
+		t.Errorf("message = %+v", got)
+	}
+	if string(got.Payload) != `{"event":"message"}` {
+		t.Errorf("payload = %s", got.Payload)
+	}
+}
+
+func TestPublishEventDisconnectsSlowSubscriber(t *testing.T) {
+	sub := &eventSubscriber{send: make(chan []byte, 1), filters: map[string]EventFilter{"all": {}}}
+	addEventSubscriber(sub)
+	t.Cleanup(func() { removeEventSubscriber(sub) })
+
+	PublishEvent(Event{Name: "message"})
+	PublishEvent(Event{Name: "message"})
+
+	if !sub.tooSlow() {
+		t.Fatal("a subscriber with a full buffer should be marked too slow")
+	}
+	<-sub.send
+	if _, open := <-sub.send; open {
+		t.Error("the send channel should be closed after the buffered event")
+	}
+}
@@ -88,6 +88,7 @@ func (service *serviceApp) Login(ctx context.Context, deviceID string) (response
 						logrus.Errorf("[LOGIN][%s] error when remove qrImage file: %v", deviceID, err)
 					}
 				}(qrPath, response.Duration)
+				whatsapp.NotifyQRCode(instance, evt.Code, evt.Timeout)
 				select {
 				case chImage <- qrPath:
 				case <-qrCtx.Done():
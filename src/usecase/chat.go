@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -14,6 +15,10 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
 )
 
 type serviceChat struct {
@@ -53,6 +58,26 @@ func chatDisplayName(jid, name string) string {
 	}
 }
 
+// toChatInfo converts a stored chat into its API representation.
+func toChatInfo(chat *domainChatStorage.Chat) domainChat.ChatInfo {
+	info := domainChat.ChatInfo{
+		JID:                 chat.JID,
+		Name:                chatDisplayName(chat.JID, chat.Name),
+		LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
+		EphemeralExpiration: chat.EphemeralExpiration,
+		CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
+		Archived:            chat.Archived,
+		Pinned:              chat.Pinned,
+		Muted:               chat.IsMuted(time.Now()),
+		UnreadCount:         chat.UnreadCount,
+	}
+	if info.Muted {
+		info.MutedUntil = chat.MutedUntil.Format(time.RFC3339)
+	}
+	return info
+}
+
 func (service serviceChat) ListChats(ctx context.Context, request domainChat.ListChatsRequest) (response domainChat.ListChatsResponse, err error) {
 	if err = validations.ValidateListChats(ctx, &request); err != nil {
 		return response, err
@@ -66,6 +91,10 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 		SearchName: request.Search,
 		HasMedia:   request.HasMedia,
 		IsArchived: request.Archived,
+		IsPinned:   request.Pinned,
+		IsMuted:    request.Muted,
+		HasUnread:  request.Unread,
+		LabelID:    request.LabelID,
 	}
 
 	// Get chats from storage
@@ -83,19 +112,36 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 		totalCount = 0
 	}
 
+	// Participant counts come from stored group snapshots
+	var groupJIDs []string
+	for _, chat := range chats {
+		if utils.IsGroupJID(chat.JID) {
+			groupJIDs = append(groupJIDs, chat.JID)
+		}
+	}
+	participantCounts, err := service.chatStorageRepo.GetGroupParticipantCounts(filter.DeviceID, groupJIDs)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get group participant counts")
+		participantCounts = nil
+	}
+
+	chatJIDs := make([]string, 0, len(chats))
+	for _, chat := range chats {
+		chatJIDs = append(chatJIDs, chat.JID)
+	}
+	chatLabels, err := service.chatStorageRepo.GetChatLabelIDs(filter.DeviceID, chatJIDs)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get chat labels")
+		chatLabels = nil
+	}
+
 	// Convert entities to domain objects
 	chatInfos := make([]domainChat.ChatInfo, 0, len(chats))
 	for _, chat := range chats {
-		chatInfo := domainChat.ChatInfo{
-			JID:                 chat.JID,
-			Name:                chatDisplayName(chat.JID, chat.Name),
-			LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
-			EphemeralExpiration: chat.EphemeralExpiration,
-			CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
-			Archived:            chat.Archived,
-		}
-		chatInfos = append(chatInfos, chatInfo)
+		info := toChatInfo(chat)
+		info.ParticipantCount = participantCounts[chat.JID]
+		info.Labels = chatLabels[chat.JID]
+		chatInfos = append(chatInfos, info)
 	}
 
 	// Create pagination response
@@ -145,6 +191,14 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 		IsFromMe:  request.IsFromMe,
 	}
 
+	if request.Cursor != "" {
+		cursor, err := decodeMessageCursor(request.Cursor)
+		if err != nil {
+			return response, err
+		}
+		filter.Before = cursor
+	}
+
 	// Parse time filters if provided
 	if request.StartTime != nil && *request.StartTime != "" {
 		startTime, err := time.Parse(time.RFC3339, *request.StartTime)
@@ -182,13 +236,106 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get total message count for pagination
-	totalCount, err := service.chatStorageRepo.GetChatMessageCount(request.ChatJID)
+	totalCount, err := service.chatStorageRepo.GetChatMessageCountByDevice(deviceID, request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message count")
 		// Continue with partial data
 		totalCount = 0
 	}
 
+	messageInfos := service.toMessageInfos(deviceID, request.ChatJID, messages)
+
+	// Create chat info for response
+	chatInfo := toChatInfo(chat)
+	if chatLabels, err := service.chatStorageRepo.GetChatLabelIDs(deviceID, []string{chat.JID}); err == nil {
+		chatInfo.Labels = chatLabels[chat.JID]
+	}
+
+	// Create pagination response
+	pagination := domainChat.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(totalCount),
+	}
+	// A full page means more rows may follow; search results are not keyset-paged.
+	if request.Search == "" && request.Limit > 0 && len(messages) == request.Limit {
+		last := messages[len(messages)-1]
+		pagination.NextCursor = encodeMessageCursor(last.Timestamp, last.ID)
+	}
+
+	response.Data = messageInfos
+	response.Pagination = pagination
+	response.ChatInfo = chatInfo
+
+	logrus.WithFields(logrus.Fields{
+		"chat_jid":       request.ChatJID,
+		"total_messages": len(messageInfos),
+		"limit":          request.Limit,
+		"offset":         request.Offset,
+	}).Info("Retrieved chat messages successfully")
+
+	return response, nil
+}
+
+func (service serviceChat) GetMessageContext(ctx context.Context, request domainChat.GetMessageContextRequest) (response domainChat.GetMessageContextResponse, err error) {
+	if err = validations.ValidateGetMessageContext(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	chat, err := service.chatStorageRepo.GetChatByDevice(deviceID, request.ChatJID)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get chat info")
+		return response, err
+	}
+	if chat == nil {
+		return response, fmt.Errorf("chat with JID %s not found", request.ChatJID)
+	}
+
+	messageContext, err := service.chatStorageRepo.GetMessageWithContext(deviceID, request.ChatJID, request.MessageID, request.Before, request.After)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message context")
+		return response, err
+	}
+	if messageContext == nil {
+		return response, fmt.Errorf("message with ID %s not found in chat %s", request.MessageID, request.ChatJID)
+	}
+
+	messages := make([]*domainChatStorage.Message, 0, len(messageContext.Before)+len(messageContext.After)+1)
+	messages = append(messages, messageContext.Before...)
+	messages = append(messages, messageContext.Message)
+	messages = append(messages, messageContext.After...)
+	messageInfos := service.toMessageInfos(deviceID, request.ChatJID, messages)
+
+	before := len(messageContext.Before)
+	response.Before = messageInfos[:before]
+	response.Message = messageInfos[before]
+	response.After = messageInfos[before+1:]
+	response.ChatInfo = toChatInfo(chat)
+	if chatLabels, err := service.chatStorageRepo.GetChatLabelIDs(deviceID, []string{chat.JID}); err == nil {
+		response.ChatInfo.Labels = chatLabels[chat.JID]
+	}
+
+	return response, nil
+}
+
+// toMessageInfos converts stored messages of one chat into their API form,
+// resolving sender names and message labels.
+func (service serviceChat) toMessageInfos(deviceID, chatJID string, messages []*domainChatStorage.Message) []domainChat.MessageInfo {
+	messageIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+	messageLabels, err := service.chatStorageRepo.GetMessageLabelIDs(deviceID, chatJID, messageIDs)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", chatJID).Warn("Failed to get message labels")
+		messageLabels = nil
+	}
+
 	// Convert entities to domain objects
 	messageInfos := make([]domainChat.MessageInfo, 0, len(messages))
 	for _, message := range messages {
@@ -196,7 +343,7 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 		senderName := ""
 		if message.Sender != "" && !message.IsFromMe {
 			// Try to find sender's individual chat to get their name
-			senderChat, _ := service.chatStorageRepo.GetChat(message.Sender)
+			senderChat, _ := service.chatStorageRepo.GetChatByDevice(deviceID, message.Sender)
 			if senderChat != nil && senderChat.Name != "" && !isPhoneNumberString(senderChat.Name) {
 				senderName = senderChat.Name
 			} else {
@@ -220,6 +367,21 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 			FileLength:   message.FileLength,
 			CreatedAt:    message.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:    message.UpdatedAt.Format(time.RFC3339),
+			Labels:       messageLabels[message.ID],
+		}
+		if message.QuotedMessageID != "" {
+			messageInfo.QuotedMessageID = message.QuotedMessageID
+			messageInfo.QuotedParticipant = message.QuotedParticipant
+			if quoted := message.QuotedMessage; quoted != nil {
+				messageInfo.QuotedMessage = &domainChat.QuotedMessageInfo{
+					ID:        quoted.ID,
+					SenderJID: quoted.Sender,
+					Content:   quoted.Content,
+					Timestamp: quoted.Timestamp.Format(time.RFC3339),
+					IsFromMe:  quoted.IsFromMe,
+					MediaType: quoted.MediaType,
+				}
+			}
 		}
 		if len(message.Reactions) > 0 {
 			messageInfo.Reactions = make([]domainChat.ReactionInfo, 0, len(message.Reactions))
@@ -232,39 +394,53 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 				})
 			}
 		}
+		if pin := message.Pin; pin != nil {
+			messageInfo.Pin = &domainChat.PinInfo{
+				PinnedBy:  pin.PinnedBy,
+				PinnedAt:  pin.PinnedAt.Format(time.RFC3339),
+				ExpiresAt: pin.ExpiresAt.Format(time.RFC3339),
+			}
+		}
+		if poll := message.Poll; poll != nil && poll.Poll != nil {
+			messageInfo.Poll = &domainChat.PollInfo{
+				Question:        poll.Poll.Question,
+				SelectableCount: poll.Poll.SelectableCount,
+				Options:         make([]domainChat.PollOptionInfo, 0, len(poll.Options)),
+				TotalVoters:     poll.TotalVoters,
+			}
+			for _, option := range poll.Options {
+				messageInfo.Poll.Options = append(messageInfo.Poll.Options, domainChat.PollOptionInfo{
+					Name:   option.Name,
+					Votes:  option.Votes,
+					Voters: option.Voters,
+				})
+			}
+		}
 		messageInfos = append(messageInfos, messageInfo)
 	}
+	return messageInfos
+}
 
-	// Create chat info for response
-	chatInfo := domainChat.ChatInfo{
-		JID:                 chat.JID,
-		Name:                chatDisplayName(chat.JID, chat.Name),
-		LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
-		EphemeralExpiration: chat.EphemeralExpiration,
-		CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
-		Archived:            chat.Archived,
-	}
+// encodeMessageCursor packs a (timestamp, id) keyset position into an opaque URL-safe token.
+func encodeMessageCursor(timestamp time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(timestamp.Format(time.RFC3339Nano) + "|" + id))
+}
 
-	// Create pagination response
-	pagination := domainChat.PaginationResponse{
-		Limit:  request.Limit,
-		Offset: request.Offset,
-		Total:  int(totalCount),
+// decodeMessageCursor reverses encodeMessageCursor, rejecting tokens it did not produce.
+func decodeMessageCursor(cursor string) (*domainChatStorage.MessageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, pkgError.ValidationError("cursor: invalid format.")
 	}
-
-	response.Data = messageInfos
-	response.Pagination = pagination
-	response.ChatInfo = chatInfo
-
-	logrus.WithFields(logrus.Fields{
-		"chat_jid":       request.ChatJID,
-		"total_messages": len(messageInfos),
-		"limit":          request.Limit,
-		"offset":         request.Offset,
-	}).Info("Retrieved chat messages successfully")
-
-	return response, nil
+	timestampPart, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, pkgError.ValidationError("cursor: invalid format.")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, timestampPart)
+	if err != nil {
+		return nil, pkgError.ValidationError("cursor: invalid format.")
+	}
+	return &domainChatStorage.MessageCursor{Timestamp: timestamp, ID: id}, nil
 }
 
 func deviceIDFromContext(ctx context.Context) string {
@@ -305,6 +481,9 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 		return response, err
 	}
 
+	// Update local storage immediately for consistency
+	service.updateLocalChatState(ctx, request.ChatJID, domainChatStorage.ChatStateUpdate{Pinned: &request.Pinned})
+
 	// Build response
 	response.Status = "success"
 	response.ChatJID = request.ChatJID
@@ -414,10 +593,7 @@ func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.A
 	}
 
 	// Update local storage immediately for consistency
-	if existingChat, _ := service.chatStorageRepo.GetChatByDevice(deviceIDFromContext(ctx), request.ChatJID); existingChat != nil {
-		existingChat.Archived = request.Archived
-		_ = service.chatStorageRepo.StoreChat(existingChat)
-	}
+	service.updateLocalChatState(ctx, request.ChatJID, domainChatStorage.ChatStateUpdate{Archived: &request.Archived})
 
 	logrus.WithFields(logrus.Fields{
 		"chat_jid": request.ChatJID,
@@ -427,6 +603,240 @@ func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.A
 	return response, nil
 }
 
+func (service serviceChat) MuteChat(ctx context.Context, request domainChat.MuteChatRequest) (response domainChat.MuteChatResponse, err error) {
+	if err = validations.ValidateMuteChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	// Validate JID and ensure connection
+	targetJID, err := utils.ValidateAndNormalizeJID(client, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+
+	// A zero duration mutes until the chat is explicitly unmuted
+	duration := time.Duration(request.Duration) * time.Second
+	mutedUntil := time.Time{}
+	if request.Muted {
+		if duration > 0 {
+			mutedUntil = time.Now().Add(duration).UTC()
+		} else {
+			mutedUntil = store.MutedForever
+		}
+	}
+
+	// Build mute patch using whatsmeow's BuildMute
+	patchInfo := appstate.BuildMute(targetJID, request.Muted, duration)
+
+	// Send app state update
+	if err = client.SendAppState(ctx, patchInfo); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"chat_jid": request.ChatJID,
+			"muted":    request.Muted,
+		}).Error("Failed to send mute chat app state")
+		return response, err
+	}
+
+	// Update local storage immediately for consistency
+	service.updateLocalChatState(ctx, request.ChatJID, domainChatStorage.ChatStateUpdate{MutedUntil: &mutedUntil})
+
+	// Build response
+	response.Status = "success"
+	response.ChatJID = request.ChatJID
+	response.Muted = request.Muted
+
+	if request.Muted {
+		response.MutedUntil = mutedUntil.Format(time.RFC3339)
+		response.Message = "Chat muted successfully"
+	} else {
+		response.Message = "Chat unmuted successfully"
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_jid": request.ChatJID,
+		"muted":    request.Muted,
+		"duration": request.Duration,
+	}).Info("Chat mute operation completed successfully")
+
+	return response, nil
+}
+
+func (service serviceChat) MarkChatRead(ctx context.Context, request domainChat.MarkChatReadRequest) (response domainChat.MarkChatReadResponse, err error) {
+	if err = validations.ValidateMarkChatRead(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	// Validate JID and ensure connection
+	targetJID, err := utils.ValidateAndNormalizeJID(client, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+
+	// WhatsApp anchors the read marker on the latest message of the chat when one is known
+	lastMessageTime := time.Now()
+	var lastMessageKey *waCommon.MessageKey
+	latest, err := service.chatStorageRepo.GetMessages(&domainChatStorage.MessageFilter{
+		DeviceID: deviceIDFromContext(ctx),
+		ChatJID:  request.ChatJID,
+		Limit:    1,
+	})
+	if err == nil && len(latest) > 0 {
+		lastMessageTime = latest[0].Timestamp
+		lastMessageKey = &waCommon.MessageKey{
+			RemoteJID: proto.String(targetJID.String()),
+			FromMe:    proto.Bool(latest[0].IsFromMe),
+			ID:        proto.String(latest[0].ID),
+		}
+		if targetJID.Server == types.GroupServer && !latest[0].IsFromMe {
+			lastMessageKey.Participant = proto.String(latest[0].Sender)
+		}
+	}
+
+	// Build mark-as-read patch using whatsmeow's BuildMarkChatAsRead
+	patchInfo := appstate.BuildMarkChatAsRead(targetJID, request.Read, lastMessageTime, lastMessageKey)
+
+	// Send app state update
+	if err = client.SendAppState(ctx, patchInfo); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"chat_jid": request.ChatJID,
+			"read":     request.Read,
+		}).Error("Failed to send mark chat read app state")
+		return response, err
+	}
+
+	// Update local storage immediately for consistency
+	unreadCount := 0
+	if !request.Read {
+		unreadCount = 1
+	}
+	service.updateLocalChatState(ctx, request.ChatJID, domainChatStorage.ChatStateUpdate{UnreadCount: &unreadCount})
+
+	// Build response
+	response.Status = "success"
+	response.ChatJID = request.ChatJID
+	response.Read = request.Read
+
+	if request.Read {
+		response.Message = "Chat marked as read successfully"
+	} else {
+		response.Message = "Chat marked as unread successfully"
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_jid": request.ChatJID,
+		"read":     request.Read,
+	}).Info("Chat read operation completed successfully")
+
+	return response, nil
+}
+
+func (service serviceChat) LabelChat(ctx context.Context, request domainChat.LabelChatRequest) (response domainChat.LabelChatResponse, err error) {
+	if err = validations.ValidateLabelChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	// Validate JID and ensure connection
+	targetJID, err := utils.ValidateAndNormalizeJID(client, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+
+	patchInfo := appstate.BuildLabelChat(targetJID, request.LabelID, request.Labeled)
+	if err = client.SendAppState(ctx, patchInfo); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"chat_jid": request.ChatJID,
+			"label_id": request.LabelID,
+			"labeled":  request.Labeled,
+		}).Error("Failed to send label chat app state")
+		return response, err
+	}
+
+	// Update local storage immediately; the app state echo is idempotent
+	if err := service.chatStorageRepo.SetChatLabel(deviceIDFromContext(ctx), targetJID.String(), request.LabelID, request.Labeled); err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Warn("Failed to update local chat label")
+	}
+
+	labelName := request.LabelName
+	if labelName == "" {
+		labelName = request.LabelID
+	}
+	response.Status = "success"
+	response.ChatJID = request.ChatJID
+	response.LabelID = request.LabelID
+	response.Labeled = request.Labeled
+	if request.Labeled {
+		response.Message = fmt.Sprintf("Chat labeled successfully with label '%s'", labelName)
+	} else {
+		response.Message = fmt.Sprintf("Label '%s' removed from chat successfully", labelName)
+	}
+
+	return response, nil
+}
+
+func (service serviceChat) ListLabels(ctx context.Context) (response domainChat.ListLabelsResponse, err error) {
+	labels, err := service.chatStorageRepo.GetLabels(deviceIDFromContext(ctx))
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainChat.LabelInfo, 0, len(labels))
+	for _, label := range labels {
+		response.Data = append(response.Data, domainChat.LabelInfo{
+			ID:           label.ID,
+			Name:         label.Name,
+			Color:        label.Color,
+			PredefinedID: label.PredefinedID,
+			OrderIndex:   label.OrderIndex,
+			ChatCount:    label.ChatCount,
+			UpdatedAt:    label.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+func (service serviceChat) SyncLabels(ctx context.Context) (response domainChat.ListLabelsResponse, err error) {
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	// Labels live in the regular app state collection. A full fetch replays
+	// every label and association through the event handler, which stores them.
+	if err = client.FetchAppState(ctx, appstate.WAPatchRegular, true, false); err != nil {
+		logrus.WithError(err).Error("Failed to fetch label app state")
+		return response, err
+	}
+
+	return service.ListLabels(ctx)
+}
+
+// updateLocalChatState mirrors an app state change into local storage. Chats that
+// were never stored are skipped; the app state sync event fills them in later.
+func (service serviceChat) updateLocalChatState(ctx context.Context, chatJID string, update domainChatStorage.ChatStateUpdate) {
+	deviceID := deviceIDFromContext(ctx)
+	if existingChat, _ := service.chatStorageRepo.GetChatByDevice(deviceID, chatJID); existingChat == nil {
+		return
+	}
+	if err := service.chatStorageRepo.UpdateChatState(deviceID, chatJID, update); err != nil {
+		logrus.WithError(err).WithField("chat_jid", chatJID).Warn("Failed to update local chat state")
+	}
+}
+
 // isPhoneNumberString checks if a string looks like a phone number
 func isPhoneNumberString(s string) bool {
 	if s == "" {
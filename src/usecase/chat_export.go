@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+// exportTxtTimeLayout mirrors the Android "Export chat" line prefix (e.g. "31/12/2025, 21:15").
+const exportTxtTimeLayout = "02/01/2006, 15:04"
+
+// exportMediaDir is the folder that holds media entries inside an export ZIP bundle.
+const exportMediaDir = "media"
+
+// exportJobDir is the folder under config.PathStorages that export jobs write to.
+const exportJobDir = "exports"
+
+var exportMediaExtensions = map[string]string{
+	"image":   ".jpg",
+	"video":   ".mp4",
+	"audio":   ".ogg",
+	"sticker": ".webp",
+}
+
+var exportCSVHeader = []string{"id", "timestamp", "sender_jid", "sender_name", "is_from_me", "content", "media_type", "filename", "file_length", "media_file"}
+
+func (service serviceChat) ExportChat(ctx context.Context, request domainChat.ExportChatRequest) (response domainChat.ExportChatResponse, err error) {
+	if err = validations.ValidateExportChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	chat, err := service.chatStorageRepo.GetChatByDevice(deviceID, request.ChatJID)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get chat info")
+		return response, err
+	}
+	if chat == nil {
+		return response, fmt.Errorf("chat with JID %s not found", request.ChatJID)
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if request.IncludeMedia && client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	exporter := &chatExporter{
+		repo:         service.chatStorageRepo,
+		client:       client,
+		deviceID:     deviceID,
+		chat:         chat,
+		format:       request.Format,
+		includeMedia: request.IncludeMedia,
+		progress:     request.Progress,
+		senderNames:  make(map[string]string),
+	}
+	if client != nil && client.Store != nil && client.Store.PushName != "" {
+		exporter.ownName = client.Store.PushName
+	}
+
+	baseName := "chat-" + utils.ExtractPhoneNumber(chat.JID)
+	if request.IncludeMedia {
+		response.Filename = baseName + ".zip"
+		response.ContentType = "application/zip"
+	} else {
+		response.Filename = baseName + "." + request.Format
+		response.ContentType = exportContentType(request.Format)
+	}
+
+	// The body is written after the handler returns and its request deadline is cancelled,
+	// so detach from cancellation while keeping the device values in the context.
+	streamCtx := context.WithoutCancel(ctx)
+	response.Write = func(w io.Writer) error {
+		if request.IncludeMedia {
+			return exporter.writeBundle(streamCtx, w)
+		}
+		return exporter.writeChat(w)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_jid":      request.ChatJID,
+		"format":        request.Format,
+		"include_media": request.IncludeMedia,
+	}).Info("Prepared chat export")
+
+	return response, nil
+}
+
+// StartExportChatJob prepares the export up front, so validation errors are
+// returned to the caller, then writes it to a file under the storages folder
+// as a background job.
+func (service serviceChat) StartExportChatJob(ctx context.Context, request domainChat.ExportChatRequest) (response domainJob.JobInfo, err error) {
+	progress := &exportJobProgress{}
+	request.Progress = progress.observe
+	export, err := service.ExportChat(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	total, err := service.chatStorageRepo.GetChatMessageCountByDevice(deviceID, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+	progress.total = int(total)
+
+	job, _, err := jobs.start(ctx, service.chatStorageRepo, domainJob.TypeChatExport, deviceID, false,
+		func(ctx context.Context, report jobReporter) (jobOutput, error) {
+			progress.ctx, progress.report = ctx, report
+
+			dir := filepath.Join(config.PathStorages, exportJobDir)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return jobOutput{}, err
+			}
+			path := filepath.Join(dir, uuid.NewString()+"-"+export.Filename)
+			file, err := os.Create(path)
+			if err != nil {
+				return jobOutput{}, err
+			}
+			if err := export.Write(file); err != nil {
+				file.Close()
+				os.Remove(path)
+				return jobOutput{}, err
+			}
+			if err := file.Close(); err != nil {
+				os.Remove(path)
+				return jobOutput{}, err
+			}
+			return jobOutput{Result: export.Filename, ResultPath: path}, nil
+		})
+	if err != nil {
+		return response, err
+	}
+	return toJobInfo(&job), nil
+}
+
+// exportJobProgress counts exported messages and media for an export job and
+// stops the export once the job is cancelled. ctx and report are set when the
+// job starts, before anything is exported.
+type exportJobProgress struct {
+	ctx      context.Context
+	report   jobReporter
+	total    int
+	messages int
+	media    int
+}
+
+func (p *exportJobProgress) observe(step string) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	if step == domainChat.ExportStepMedia {
+		// Media items are only known while iterating, so the total is open.
+		p.media++
+		p.report(step, p.media, 0)
+		return nil
+	}
+	p.messages++
+	p.report(step, p.messages, p.total)
+	return nil
+}
+
+func exportContentType(format string) string {
+	switch format {
+	case domainChat.ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case domainChat.ExportFormatTXT:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// chatExporter streams one chat through IterateMessages so memory stays bounded
+// regardless of how many messages the chat holds.
+type chatExporter struct {
+	repo         domainChatStorage.IChatStorageRepository
+	client       *whatsmeow.Client
+	deviceID     string
+	chat         *domainChatStorage.Chat
+	format       string
+	includeMedia bool
+	ownName      string
+	senderNames  map[string]string
+	progress     func(step string) error
+}
+
+func (e *chatExporter) report(step string) error {
+	if e.progress == nil {
+		return nil
+	}
+	return e.progress(step)
+}
+
+func (e *chatExporter) filter(mediaOnly bool) *domainChatStorage.MessageFilter {
+	return &domainChatStorage.MessageFilter{
+		DeviceID:  e.deviceID,
+		ChatJID:   e.chat.JID,
+		MediaOnly: mediaOnly,
+	}
+}
+
+// writeBundle writes a ZIP holding the chat file followed by every downloadable media item.
+// Media that can no longer be downloaded (expired URLs, missing keys) is skipped and logged.
+func (e *chatExporter) writeBundle(ctx context.Context, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	chatEntry, err := zw.Create(e.chatFilename())
+	if err != nil {
+		return err
+	}
+	if err := e.writeChat(chatEntry); err != nil {
+		return err
+	}
+
+	err = e.repo.IterateMessages(e.filter(true), func(message *domainChatStorage.Message) error {
+		if err := e.report(domainChat.ExportStepMedia); err != nil {
+			return err
+		}
+		downloadable, err := storedMediaDownloadable(message)
+		if err != nil || message.URL == "" {
+			return nil
+		}
+		data, err := e.client.Download(ctx, downloadable)
+		if err != nil {
+			logrus.WithError(err).WithField("message_id", message.ID).Warn("Skipping media in chat export")
+			return nil
+		}
+		entry, err := zw.Create(exportMediaPath(message))
+		if err != nil {
+			return err
+		}
+		_, err = entry.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func (e *chatExporter) chatFilename() string {
+	if e.format == domainChat.ExportFormatTXT {
+		return "_chat.txt"
+	}
+	return "chat." + e.format
+}
+
+func (e *chatExporter) writeChat(w io.Writer) error {
+	switch e.format {
+	case domainChat.ExportFormatCSV:
+		return e.writeCSV(w)
+	case domainChat.ExportFormatTXT:
+		return e.writeTXT(w)
+	default:
+		return e.writeJSON(w)
+	}
+}
+
+func (e *chatExporter) writeJSON(w io.Writer) error {
+	chatJSON, err := json.Marshal(toChatInfo(e.chat))
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"chat":%s,"messages":[`, chatJSON); err != nil {
+		return err
+	}
+
+	first := true
+	err = e.repo.IterateMessages(e.filter(false), func(message *domainChatStorage.Message) error {
+		if err := e.report(domainChat.ExportStepMessages); err != nil {
+			return err
+		}
+		row, err := json.Marshal(e.exportMessage(message))
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(row)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}")
+	return err
+}
+
+func (e *chatExporter) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	err := e.repo.IterateMessages(e.filter(false), func(message *domainChatStorage.Message) error {
+		if err := e.report(domainChat.ExportStepMessages); err != nil {
+			return err
+		}
+		row := e.exportMessage(message)
+		return cw.Write([]string{
+			row.ID,
+			row.Timestamp,
+			row.SenderJID,
+			row.SenderName,
+			strconv.FormatBool(row.IsFromMe),
+			row.Content,
+			row.MediaType,
+			row.Filename,
+			strconv.FormatUint(row.FileLength, 10),
+			row.MediaFile,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (e *chatExporter) writeTXT(w io.Writer) error {
+	return e.repo.IterateMessages(e.filter(false), func(message *domainChatStorage.Message) error {
+		if err := e.report(domainChat.ExportStepMessages); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, e.txtLine(message))
+		return err
+	})
+}
+
+// txtLine renders a message the way WhatsApp's own "Export chat" does:
+// "31/12/2025, 21:15 - Alice: hello", with media as "<file> (file attached)"
+// or "<Media omitted>" when the export has no media bundle.
+func (e *chatExporter) txtLine(message *domainChatStorage.Message) string {
+	body := message.Content
+	if message.MediaType != "" && message.MediaType != "call" {
+		attachment := "<Media omitted>"
+		if e.includeMedia {
+			attachment = path.Base(exportMediaPath(message)) + " (file attached)"
+		}
+		if body == "" {
+			body = attachment
+		} else {
+			body = attachment + "\n" + body
+		}
+	}
+
+	return fmt.Sprintf("%s - %s: %s\n", message.Timestamp.Format(exportTxtTimeLayout), e.senderName(message), body)
+}
+
+func (e *chatExporter) exportMessage(message *domainChatStorage.Message) domainChat.ExportMessage {
+	row := domainChat.ExportMessage{
+		ID:         message.ID,
+		Timestamp:  message.Timestamp.Format(time.RFC3339),
+		SenderJID:  message.Sender,
+		SenderName: e.senderName(message),
+		IsFromMe:   message.IsFromMe,
+		Content:    message.Content,
+		MediaType:  message.MediaType,
+		Filename:   message.Filename,
+		FileLength: message.FileLength,
+	}
+	if e.includeMedia && message.MediaType != "" && message.MediaType != "call" && message.URL != "" {
+		row.MediaFile = exportMediaPath(message)
+	}
+	return row
+}
+
+// senderName resolves a display name once per sender and caches it for the rest of the export.
+func (e *chatExporter) senderName(message *domainChatStorage.Message) string {
+	if message.IsFromMe {
+		if e.ownName != "" {
+			return e.ownName
+		}
+		return "You"
+	}
+	if name, ok := e.senderNames[message.Sender]; ok {
+		return name
+	}
+
+	name := ""
+	if senderChat, _ := e.repo.GetChatByDevice(e.deviceID, message.Sender); senderChat != nil && senderChat.Name != "" && !isPhoneNumberString(senderChat.Name) {
+		name = senderChat.Name
+	}
+	if name == "" {
+		name = whatsapp.GetPushNameFromCache(extractUserFromJID(message.Sender))
+	}
+	if name == "" {
+		name = extractUserFromJID(message.Sender)
+	}
+
+	e.senderNames[message.Sender] = name
+	return name
+}
+
+// exportMediaPath returns a stable, collision-free ZIP path for a message's media.
+func exportMediaPath(message *domainChatStorage.Message) string {
+	name := message.ID + exportMediaExtensions[message.MediaType]
+	if message.Filename != "" {
+		name = message.ID + "-" + path.Base(strings.ReplaceAll(message.Filename, "\\", "/"))
+	}
+	return path.Join(exportMediaDir, name)
+}
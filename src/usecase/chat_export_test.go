@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+)
+
+type chatExportRepoStub struct {
+	chatUsecaseRepoStub
+}
+
+func (r *chatExportRepoStub) IterateMessages(filter *domainChatStorage.MessageFilter, fn func(*domainChatStorage.Message) error) error {
+	for _, message := range r.messages {
+		if filter.MediaOnly && (message.MediaType == "" || message.MediaType == "call") {
+			continue
+		}
+		if err := fn(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newChatExportFixture() (*chatExportRepoStub, context.Context) {
+	deviceID := "device-a@s.whatsapp.net"
+	chatJID := "628123456789@s.whatsapp.net"
+	now := time.Date(2026, time.May, 16, 8, 30, 0, 0, time.UTC)
+	repo := &chatExportRepoStub{chatUsecaseRepoStub{
+		chat: &domainChatStorage.Chat{DeviceID: deviceID, JID: chatJID, Name: "Alice", LastMessageTime: now},
+		messages: []*domainChatStorage.Message{
+			{ID: "msg-1", ChatJID: chatJID, DeviceID: deviceID, Sender: chatJID, Content: "hello", Timestamp: now},
+			{ID: "msg-2", ChatJID: chatJID, DeviceID: deviceID, Content: "hi, there", Timestamp: now.Add(time.Minute), IsFromMe: true},
+			{ID: "msg-3", ChatJID: chatJID, DeviceID: deviceID, Sender: chatJID, MediaType: "image", URL: "https://mmg.whatsapp.net/x", Timestamp: now.Add(2 * time.Minute)},
+		},
+	}}
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance(deviceID, nil, nil))
+	return repo, ctx
+}
+
+func TestExportChatTXTMatchesWhatsAppLayout(t *testing.T) {
+	repo, ctx := newChatExportFixture()
+	service := NewChatService(repo)
+
+	response, err := service.ExportChat(ctx, domainChat.ExportChatRequest{ChatJID: repo.chat.JID, Format: domainChat.ExportFormatTXT})
+	if err != nil {
+		t.Fatalf("export chat: %v", err)
+	}
+	if response.Filename != "chat-628123456789.txt" {
+		t.Fatalf("unexpected filename %q", response.Filename)
+	}
+
+	var buf bytes.Buffer
+	if err := response.Write(&buf); err != nil {
+		t.Fatalf("write export: %v", err)
+	}
+	want := "16/05/2026, 08:30 - Alice: hello\n" +
+		"16/05/2026, 08:31 - You: hi, there\n" +
+		"16/05/2026, 08:32 - Alice: <Media omitted>\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected txt export:\n%s", buf.String())
+	}
+}
+
+func TestExportChatCSVAndJSON(t *testing.T) {
+	repo, ctx := newChatExportFixture()
+	service := NewChatService(repo)
+
+	response, err := service.ExportChat(ctx, domainChat.ExportChatRequest{ChatJID: repo.chat.JID, Format: domainChat.ExportFormatCSV})
+	if err != nil {
+		t.Fatalf("export chat: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := response.Write(&buf); err != nil {
+		t.Fatalf("write export: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected header plus 3 rows, got %d", len(records))
+	}
+	if records[2][5] != "hi, there" {
+		t.Fatalf("expected quoted content to round-trip, got %q", records[2][5])
+	}
+
+	response, err = service.ExportChat(ctx, domainChat.ExportChatRequest{ChatJID: repo.chat.JID})
+	if err != nil {
+		t.Fatalf("export chat: %v", err)
+	}
+	if response.ContentType != "application/json" {
+		t.Fatalf("expected json to be the default format, got %q", response.ContentType)
+	}
+	buf.Reset()
+	if err := response.Write(&buf); err != nil {
+		t.Fatalf("write export: %v", err)
+	}
+	var decoded struct {
+		Chat     domainChat.ChatInfo        `json:"chat"`
+		Messages []domainChat.ExportMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode json export: %v", err)
+	}
+	if decoded.Chat.Name != "Alice" || len(decoded.Messages) != 3 {
+		t.Fatalf("unexpected json export: %+v", decoded)
+	}
+}
+
+func TestExportChatRejectsUnknownFormat(t *testing.T) {
+	repo, ctx := newChatExportFixture()
+	service := NewChatService(repo)
+
+	if _, err := service.ExportChat(ctx, domainChat.ExportChatRequest{ChatJID: repo.chat.JID, Format: "xml"}); err == nil {
+		t.Fatal("expected validation error for unsupported format")
+	}
+}
@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// importIDPrefix marks messages loaded from an export; their IDs are derived
+// from the message so a repeated import updates them instead of adding copies.
+const importIDPrefix = "IMPORT-"
+
+// importLinePattern matches the header of an exported message, in the Android
+// form "31/12/2025, 21:15 - Alice: hi" and the iOS form "[31/12/25, 9:15:03 PM] Alice: hi".
+var importLinePattern = regexp.MustCompile(`^\[?(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{2,4}),?\s+(\d{1,2})[:.](\d{2})(?:[:.](\d{2}))?\s*([AaPp])?\.?\s*(?:[Mm]\.?)?(?:\]\s*|\s+-\s+)(.*)$`)
+
+var (
+	// importAttachedPattern matches iOS attachments, "<attached: 00000012-PHOTO-2025-12-31-21-15-03.jpg>".
+	importAttachedPattern = regexp.MustCompile(`^<attached: (.+)>$`)
+	// importFileAttachedPattern matches Android attachments, "IMG-20251231-WA0001.jpg (file attached)".
+	importFileAttachedPattern = regexp.MustCompile(`^(.+\.\w+) \(file attached\)$`)
+	// importOmittedPattern matches iOS media left out of the export, "image omitted".
+	importOmittedPattern = regexp.MustCompile(`^(image|video|audio|sticker|document|GIF) omitted$`)
+)
+
+// importInvisibleChars are the direction marks and non-breaking spaces exports
+// put around names and times.
+var importInvisibleChars = strings.NewReplacer(
+	"\ufeff", "", "\u200e", "", "\u200f", "",
+	"\u202a", "", "\u202b", "", "\u202c", "", "\u202d", "", "\u202e", "",
+	"\u202f", " ", "\u00a0", " ",
+)
+
+var importMediaExtensions = map[string]string{
+	".jpg": "image", ".jpeg": "image", ".png": "image",
+	".mp4": "video", ".3gp": "video", ".mov": "video", ".gif": "video",
+	".opus": "audio", ".ogg": "audio", ".m4a": "audio", ".mp3": "audio", ".aac": "audio",
+	".webp": "sticker",
+}
+
+// importedMessage is one message read from a WhatsApp chat export.
+type importedMessage struct {
+	Timestamp time.Time
+	Sender    string
+	Body      string
+	MediaType string
+	Filename  string
+}
+
+// importHeader is a message header whose date is resolved once the whole
+// export has been read and the day/month order is known.
+type importHeader struct {
+	datePart1, datePart2, year int
+	hour, minute, sec          int
+	meridiem                   string
+	sender, body               string
+}
+
+func (service serviceChat) ImportChat(ctx context.Context, request domainChat.ImportChatRequest) (response domainChat.ImportChatResponse, err error) {
+	if err = validations.ValidateImportChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	chatText, chatFilename, err := readChatExport(request.File)
+	if err != nil {
+		return response, err
+	}
+	messages, skipped, err := parseChatExport(bytes.NewReader(chatText), time.Local)
+	if err != nil {
+		return response, err
+	}
+	if len(messages) == 0 {
+		return response, pkgError.ValidationError("file: no messages found, expected a WhatsApp chat export")
+	}
+
+	existing, err := service.chatStorageRepo.GetChatByDevice(deviceID, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+	chat := &domainChatStorage.Chat{DeviceID: deviceID, JID: request.ChatJID}
+	if existing != nil {
+		chat = existing
+	}
+	switch {
+	case request.ChatName != "":
+		chat.Name = request.ChatName
+	case chat.Name == "":
+		chat.Name = exportChatName(chatFilename)
+	}
+	if chat.Name == "" {
+		chat.Name = extractUserFromJID(request.ChatJID)
+	}
+	if last := messages[len(messages)-1].Timestamp; last.After(chat.LastMessageTime) {
+		chat.LastMessageTime = last
+	}
+
+	isGroup := strings.HasSuffix(request.ChatJID, "@"+types.GroupServer)
+	occurrences := make(map[string]int)
+	stored := make([]*domainChatStorage.Message, 0, len(messages))
+	for _, imported := range messages {
+		message := &domainChatStorage.Message{
+			ChatJID:   request.ChatJID,
+			DeviceID:  deviceID,
+			Content:   imported.Body,
+			Timestamp: imported.Timestamp,
+			MediaType: imported.MediaType,
+			Filename:  imported.Filename,
+		}
+		message.Sender, message.IsFromMe = importSender(imported.Sender, request, deviceID, isGroup)
+
+		key := fmt.Sprintf("%s|%d|%s|%s|%s", request.ChatJID, imported.Timestamp.Unix(), imported.Sender, imported.Body, imported.Filename)
+		occurrences[key]++
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, occurrences[key])))
+		message.ID = importIDPrefix + strings.ToUpper(hex.EncodeToString(sum[:8]))
+		stored = append(stored, message)
+	}
+
+	if err = service.chatStorageRepo.StoreChat(chat); err != nil {
+		return response, err
+	}
+	if err = service.chatStorageRepo.StoreMessagesBatch(stored); err != nil {
+		return response, err
+	}
+	logrus.WithFields(logrus.Fields{"chat_jid": request.ChatJID, "messages": len(stored)}).Info("Imported chat export")
+
+	response.ChatJID = request.ChatJID
+	response.ChatName = chat.Name
+	response.Imported = len(stored)
+	response.Skipped = skipped
+	return response, nil
+}
+
+// readChatExport returns the chat text of an export and its file name. A ZIP
+// export holds the text next to the media files; the media is not imported.
+func readChatExport(header *multipart.FileHeader) ([]byte, string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", err
+	}
+	if !strings.EqualFold(filepath.Ext(header.Filename), ".zip") {
+		return data, header.Filename, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, "", pkgError.ValidationError(fmt.Sprintf("file: invalid ZIP archive: %v", err))
+	}
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || !strings.EqualFold(path.Ext(entry.Name), ".txt") {
+			continue
+		}
+		reader, err := entry.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		text, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, "", err
+		}
+		// iOS names the entry _chat.txt; the archive name carries the chat name then.
+		name := path.Base(entry.Name)
+		if name == "_chat.txt" {
+			name = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename)) + ".txt"
+		}
+		return text, name, nil
+	}
+	return nil, "", pkgError.ValidationError("file: the ZIP archive holds no chat .txt file")
+}
+
+// parseChatExport reads the messages of a WhatsApp chat export, in file order.
+// Lines without a message header continue the previous message. System lines
+// (a header without "Name: ") are counted in skipped. Times are in loc, the
+// zone of the phone that made the export.
+func parseChatExport(r io.Reader, loc *time.Location) (messages []importedMessage, skipped int, err error) {
+	var headers []*importHeader
+	var current *importHeader
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := importInvisibleChars.Replace(raw)
+		match := importLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			if current != nil {
+				current.body += "\n" + line
+			}
+			continue
+		}
+
+		sender, body, ok := strings.Cut(match[8], ": ")
+		// iOS writes system lines as "Chat name: <LRM>notice"; media placeholders
+		// are the only messages that start with the mark.
+		if ok && strings.Contains(raw, ": \u200e") {
+			_, mediaType, _ := splitExportAttachment(body)
+			ok = mediaType != ""
+		}
+		if !ok {
+			// A system line ends the previous message too.
+			current = nil
+			skipped++
+			continue
+		}
+		current = &importHeader{
+			datePart1: atoiOrZero(match[1]),
+			datePart2: atoiOrZero(match[2]),
+			year:      atoiOrZero(match[3]),
+			hour:      atoiOrZero(match[4]),
+			minute:    atoiOrZero(match[5]),
+			sec:       atoiOrZero(match[6]),
+			meridiem:  strings.ToUpper(match[7]),
+			sender:    strings.TrimSpace(sender),
+			body:      body,
+		}
+		headers = append(headers, current)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	dayFirst := exportDatesDayFirst(headers)
+	for _, header := range headers {
+		day, month := header.datePart1, header.datePart2
+		if !dayFirst {
+			day, month = month, day
+		}
+		year := header.year
+		if year < 100 {
+			year += 2000
+		}
+		hour := header.hour
+		switch {
+		case header.meridiem == "P" && hour < 12:
+			hour += 12
+		case header.meridiem == "A" && hour == 12:
+			hour = 0
+		}
+
+		message := importedMessage{
+			Timestamp: time.Date(year, time.Month(month), day, hour, header.minute, header.sec, 0, loc),
+			Sender:    header.sender,
+		}
+		message.Body, message.MediaType, message.Filename = splitExportAttachment(strings.TrimRight(header.body, "\n "))
+		messages = append(messages, message)
+	}
+	return messages, skipped, nil
+}
+
+// exportDatesDayFirst reports whether the export writes dates day first. The
+// phone's locale decides the order, so it is read from a date that can only be
+// one way round; exports where every date is ambiguous are read day first.
+func exportDatesDayFirst(headers []*importHeader) bool {
+	for _, header := range headers {
+		if header.datePart1 > 12 {
+			return true
+		}
+		if header.datePart2 > 12 {
+			return false
+		}
+	}
+	return true
+}
+
+// splitExportAttachment separates a media placeholder at the start of an
+// exported message from its caption.
+func splitExportAttachment(body string) (content, mediaType, filename string) {
+	first, caption, _ := strings.Cut(body, "\n")
+	first = strings.TrimSpace(first)
+
+	switch {
+	case first == "<Media omitted>":
+		mediaType = "media"
+	case importOmittedPattern.MatchString(first):
+		mediaType = strings.ToLower(importOmittedPattern.FindStringSubmatch(first)[1])
+		if mediaType == "gif" {
+			mediaType = "video"
+		}
+	case importAttachedPattern.MatchString(first):
+		filename = importAttachedPattern.FindStringSubmatch(first)[1]
+	case importFileAttachedPattern.MatchString(first):
+		filename = importFileAttachedPattern.FindStringSubmatch(first)[1]
+	default:
+		return body, "", ""
+	}
+
+	if filename != "" {
+		mediaType = importMediaExtensions[strings.ToLower(filepath.Ext(filename))]
+		switch {
+		case strings.HasPrefix(filename, "STK-") || strings.Contains(filename, "-STICKER-"):
+			mediaType = "sticker"
+		case mediaType == "sticker":
+			mediaType = "image"
+		case mediaType == "":
+			mediaType = "document"
+		}
+	}
+	return strings.TrimSpace(caption), mediaType, filename
+}
+
+// importSender maps an exported sender name to a JID. Names the export shows
+// as phone numbers become that number; in a direct chat everyone else is the
+// contact. Group members saved under a name keep the name, as the export has
+// nothing else to go on.
+func importSender(name string, request domainChat.ImportChatRequest, deviceID string, isGroup bool) (sender string, isFromMe bool) {
+	if name == "You" || (request.OwnName != "" && name == request.OwnName) {
+		return deviceID, true
+	}
+	digits := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(name)
+	if isPhoneNumberString(digits) {
+		return strings.TrimPrefix(digits, "+") + "@" + types.DefaultUserServer, false
+	}
+	if !isGroup {
+		return request.ChatJID, false
+	}
+	return name, false
+}
+
+// exportChatName reads the chat name from an export file name such as
+// "WhatsApp Chat with Alice.txt" or "WhatsApp Chat - Alice.zip".
+func exportChatName(filename string) string {
+	name := strings.TrimSuffix(path.Base(filename), filepath.Ext(filename))
+	for _, prefix := range []string{"WhatsApp Chat with ", "WhatsApp Chat - "} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(name, prefix))
+		}
+	}
+	return ""
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
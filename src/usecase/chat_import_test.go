@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"mime/multipart"
+	"strings"
+	"testing"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+)
+
+type chatImportRepoStub struct {
+	chatUsecaseRepoStub
+	storedChat *domainChatStorage.Chat
+}
+
+func (r *chatImportRepoStub) StoreChat(chat *domainChatStorage.Chat) error {
+	r.storedChat = chat
+	return nil
+}
+
+func (r *chatImportRepoStub) StoreMessagesBatch(messages []*domainChatStorage.Message) error {
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+// newExportFileHeader wraps data as an uploaded file, the way the REST handler receives it.
+func newExportFileHeader(t *testing.T, filename string, data []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("read form: %v", err)
+	}
+	return form.File["file"][0]
+}
+
+func TestParseChatExportAndroid(t *testing.T) {
+	export := "31/12/2025, 21:15 - Messages and calls are end-to-end encrypted.\n" +
+		"31/12/2025, 21:15 - Alice: hello\n" +
+		"second line\n" +
+		"31/12/2025, 21:16 - Bob: IMG-20251231-WA0001.jpg (file attached)\n" +
+		"look at this\n" +
+		"01/01/2026, 09:00 - Alice: <Media omitted>\n" +
+		"01/01/2026, 09:01 - Alice created group \"Friends\"\n" +
+		"01/01/2026, 09:02 - +62 812-3456-7890: STK-20260101-WA0002.webp (file attached)\n"
+
+	messages, skipped, err := parseChatExport(strings.NewReader(export), time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if skipped != 2 {
+		t.Fatalf("expected 2 system lines skipped, got %d", skipped)
+	}
+	want := []importedMessage{
+		{Timestamp: time.Date(2025, time.December, 31, 21, 15, 0, 0, time.UTC), Sender: "Alice", Body: "hello\nsecond line"},
+		{Timestamp: time.Date(2025, time.December, 31, 21, 16, 0, 0, time.UTC), Sender: "Bob", Body: "look at this", MediaType: "image", Filename: "IMG-20251231-WA0001.jpg"},
+		{Timestamp: time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC), Sender: "Alice", MediaType: "media"},
+		{Timestamp: time.Date(2026, time.January, 1, 9, 2, 0, 0, time.UTC), Sender: "+62 812-3456-7890", MediaType: "sticker", Filename: "STK-20260101-WA0002.webp"},
+	}
+	if len(messages) != len(want) {
+		t.Fatalf("expected %d messages, got %d: %+v", len(want), len(messages), messages)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Fatalf("message %d: got %+v, want %+v", i, messages[i], want[i])
+		}
+	}
+}
+
+func TestParseChatExportIOSMonthFirst(t *testing.T) {
+	export := "\ufeff[12/31/25, 9:15:03 PM] Friends: \u200eMessages and calls are end-to-end encrypted.\n" +
+		"[12/31/25, 9:15:04 PM] Alice: hi\n" +
+		"[1/1/26, 12:05:00 AM] \u200eBob: \u200e<attached: 00000012-PHOTO-2026-01-01-00-05-00.jpg>\n" +
+		"[1/1/26, 12:06:00 AM] Bob: \u200eaudio omitted\n"
+
+	messages, skipped, err := parseChatExport(strings.NewReader(export), time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if skipped != 1 || len(messages) != 3 {
+		t.Fatalf("expected 3 messages and 1 skipped line, got %d and %d: %+v", len(messages), skipped, messages)
+	}
+	if got := messages[0].Timestamp; !got.Equal(time.Date(2025, time.December, 31, 21, 15, 4, 0, time.UTC)) {
+		t.Fatalf("unexpected timestamp %s", got)
+	}
+	if messages[1].Sender != "Bob" || messages[1].MediaType != "image" || messages[1].Filename != "00000012-PHOTO-2026-01-01-00-05-00.jpg" {
+		t.Fatalf("unexpected attachment %+v", messages[1])
+	}
+	if got := messages[1].Timestamp; !got.Equal(time.Date(2026, time.January, 1, 0, 5, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected midnight timestamp %s", got)
+	}
+	if messages[2].MediaType != "audio" || messages[2].Body != "" {
+		t.Fatalf("unexpected omitted media %+v", messages[2])
+	}
+}
+
+func TestImportChatStoresMessagesIdempotently(t *testing.T) {
+	deviceID := "628111@s.whatsapp.net"
+	chatJID := "628123456789@s.whatsapp.net"
+	repo := &chatImportRepoStub{}
+	service := NewChatService(repo)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance(deviceID, nil, nil))
+
+	text := "31/12/2025, 21:15 - Alice: hello\n" +
+		"31/12/2025, 21:15 - Alice: hello\n" +
+		"31/12/2025, 21:16 - Me: hi\n"
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	entry, _ := zw.Create("WhatsApp Chat with Alice.txt")
+	_, _ = entry.Write([]byte(text))
+	_, _ = zw.Create("IMG-20251231-WA0001.jpg")
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+
+	request := domainChat.ImportChatRequest{
+		ChatJID: chatJID,
+		OwnName: "Me",
+		File:    newExportFileHeader(t, "WhatsApp Chat with Alice.zip", archive.Bytes()),
+	}
+	response, err := service.ImportChat(ctx, request)
+	if err != nil {
+		t.Fatalf("import chat: %v", err)
+	}
+	if response.Imported != 3 || response.ChatName != "Alice" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if repo.storedChat.DeviceID != deviceID || !repo.storedChat.LastMessageTime.Equal(repo.messages[2].Timestamp) {
+		t.Fatalf("unexpected chat %+v", repo.storedChat)
+	}
+
+	first, second, own := repo.messages[0], repo.messages[1], repo.messages[2]
+	if first.Sender != chatJID || first.IsFromMe || !strings.HasPrefix(first.ID, importIDPrefix) {
+		t.Fatalf("unexpected contact message %+v", first)
+	}
+	if first.ID == second.ID {
+		t.Fatal("identical lines must get distinct IDs")
+	}
+	if own.Sender != deviceID || !own.IsFromMe {
+		t.Fatalf("unexpected own message %+v", own)
+	}
+
+	// A second import of the same export produces the same IDs.
+	if _, err := service.ImportChat(ctx, request); err != nil {
+		t.Fatalf("re-import chat: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if repo.messages[i].ID != repo.messages[i+3].ID {
+			t.Fatalf("message %d got a new ID on re-import", i)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// lidBackfillBatchSize is the number of phone number chats resolved per
+// progress step.
+const lidBackfillBatchSize = 100
+
+// Steps of a LID backfill job.
+const (
+	lidBackfillStepResolve = "resolving phone number chats"
+	lidBackfillStepMerge   = "merging @lid chats"
+)
+
+// StartLIDBackfill merges the device's @lid chats into the phone number chats
+// of the same contacts, for databases that stored both before LIDs were
+// resolved consistently. The phone number chats are resolved to LIDs first,
+// querying WhatsApp for numbers no store knows: unlike a LID's phone number,
+// a phone number's LID can be asked for, so this finds duplicates the history
+// sync deduplication cannot. One backfill runs per device at a time.
+func (service serviceChat) StartLIDBackfill(ctx context.Context) (response domainChat.LIDBackfillResponse, err error) {
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	job, started, err := jobs.start(ctx, service.chatStorageRepo, domainJob.TypeLIDBackfill, deviceID, true,
+		func(ctx context.Context, report jobReporter) (jobOutput, error) {
+			return service.backfillLIDChats(ctx, client, deviceID, report)
+		})
+	if err != nil {
+		return response, err
+	}
+
+	response.Started = started
+	response.Job = toJobInfo(&job)
+	return response, nil
+}
+
+func (service serviceChat) backfillLIDChats(ctx context.Context, client *whatsmeow.Client, deviceID string, report jobReporter) (jobOutput, error) {
+	lidChats, err := service.chatStorageRepo.GetLIDChats(deviceID)
+	if err != nil {
+		return jobOutput{}, fmt.Errorf("failed to find LID chats: %w", err)
+	}
+	if len(lidChats) == 0 {
+		return jobOutput{Result: "no @lid chats to merge"}, nil
+	}
+	phoneChats, err := service.chatStorageRepo.GetPhoneChats(deviceID)
+	if err != nil {
+		return jobOutput{}, fmt.Errorf("failed to find phone number chats: %w", err)
+	}
+
+	resolver := utils.LIDResolverFromContext(ctx)
+	phoneByLID := make(map[string]string, len(phoneChats))
+	for start := 0; start < len(phoneChats); start += lidBackfillBatchSize {
+		if err := ctx.Err(); err != nil {
+			return jobOutput{}, err
+		}
+		report(lidBackfillStepResolve, start, len(phoneChats))
+
+		batch := phoneChats[start:min(start+lidBackfillBatchSize, len(phoneChats))]
+		jids := make([]types.JID, 0, len(batch))
+		for _, chat := range batch {
+			if jid, err := types.ParseJID(chat.JID); err == nil {
+				jids = append(jids, jid)
+			}
+		}
+		for _, resolution := range resolver.ResolveMany(ctx, jids, client) {
+			if resolution.Resolved() {
+				phoneByLID[resolution.LID.ToNonAD().String()] = resolution.Phone.ToNonAD().String()
+			}
+		}
+	}
+
+	merged, failed := 0, 0
+	for i, chat := range lidChats {
+		if err := ctx.Err(); err != nil {
+			return jobOutput{}, err
+		}
+		report(lidBackfillStepMerge, i, len(lidChats))
+
+		phoneJID, ok := phoneByLID[chat.JID]
+		if !ok {
+			// The LID may be known even though its phone number has no chat yet
+			if lid, err := types.ParseJID(chat.JID); err == nil {
+				if pn, _ := resolver.PhoneForLID(ctx, lid, client); !pn.IsEmpty() {
+					phoneJID = pn.ToNonAD().String()
+				}
+			}
+		}
+		if phoneJID == "" {
+			continue
+		}
+		if err := service.chatStorageRepo.MergeLIDChat(deviceID, chat.JID, phoneJID); err != nil {
+			logrus.WithError(err).Warnf("Failed to merge LID chat %s into %s", chat.JID, phoneJID)
+			failed++
+			continue
+		}
+		merged++
+	}
+	report(lidBackfillStepMerge, len(lidChats), len(lidChats))
+
+	result := fmt.Sprintf("merged %d of %d @lid chats", merged, len(lidChats))
+	if failed > 0 {
+		result += fmt.Sprintf(", %d failed", failed)
+	}
+	return jobOutput{Result: result}, nil
+}
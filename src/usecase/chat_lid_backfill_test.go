@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lidBackfillRepoStub lists fixed chats and records merges.
+type lidBackfillRepoStub struct {
+	domainChatStorage.IChatStorageRepository
+	lidChats   []*domainChatStorage.Chat
+	phoneChats []*domainChatStorage.Chat
+	merges     [][2]string
+}
+
+func (r *lidBackfillRepoStub) GetLIDChats(string) ([]*domainChatStorage.Chat, error) {
+	return r.lidChats, nil
+}
+
+func (r *lidBackfillRepoStub) GetPhoneChats(string) ([]*domainChatStorage.Chat, error) {
+	return r.phoneChats, nil
+}
+
+func (r *lidBackfillRepoStub) MergeLIDChat(_, lidJID, phoneJID string) error {
+	r.merges = append(r.merges, [2]string{lidJID, phoneJID})
+	return nil
+}
+
+// lidMappingStoreStub knows fixed LID to phone number mappings.
+type lidMappingStoreStub map[string]string
+
+func (s lidMappingStoreStub) StoreLIDMapping(lid, phoneJID string) error {
+	s[lid] = phoneJID
+	return nil
+}
+
+func (s lidMappingStoreStub) LookupPhoneForLID(lid string) (string, error) {
+	return s[lid], nil
+}
+
+func (s lidMappingStoreStub) LookupLIDForPhone(phoneJID string) (string, error) {
+	for lid, pn := range s {
+		if pn == phoneJID {
+			return lid, nil
+		}
+	}
+	return "", nil
+}
+
+func TestBackfillLIDChatsMergesResolvedChats(t *testing.T) {
+	repo := &lidBackfillRepoStub{
+		lidChats: []*domainChatStorage.Chat{
+			{JID: "100000000000001@lid"},
+			{JID: "100000000000002@lid"},
+			{JID: "100000000000003@lid"},
+		},
+		phoneChats: []*domainChatStorage.Chat{{JID: "628111111111@s.whatsapp.net"}},
+	}
+	store := lidMappingStoreStub{
+		"100000000000001@lid": "628111111111@s.whatsapp.net",
+		// No chat for this phone number yet; the LID chat is renamed to it
+		"100000000000002@lid": "628222222222@s.whatsapp.net",
+	}
+	ctx := utils.ContextWithLIDResolver(context.Background(), utils.NewLIDResolver(store))
+
+	var steps []string
+	output, err := serviceChat{chatStorageRepo: repo}.backfillLIDChats(ctx, nil, "device-a", func(step string, completed, total int) {
+		steps = append(steps, step)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][2]string{
+		{"100000000000001@lid", "628111111111@s.whatsapp.net"},
+		{"100000000000002@lid", "628222222222@s.whatsapp.net"},
+	}, repo.merges)
+	assert.Equal(t, "merged 2 of 3 @lid chats", output.Result)
+	assert.Contains(t, steps, lidBackfillStepResolve)
+	assert.Contains(t, steps, lidBackfillStepMerge)
+}
+
+func TestBackfillLIDChatsWithoutLIDChats(t *testing.T) {
+	repo := &lidBackfillRepoStub{}
+	output, err := serviceChat{chatStorageRepo: repo}.backfillLIDChats(context.Background(), nil, "device-a", func(string, int, int) {})
+	require.NoError(t, err)
+	assert.Equal(t, "no @lid chats to merge", output.Result)
+	assert.Empty(t, repo.merges)
+}
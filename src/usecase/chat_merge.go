@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+// MergeChat merges two stored chats of the device into the target chat. It
+// covers duplicates the automatic LID merge missed, for example chats stored
+// before the LID resolver existed. Only chat storage changes; WhatsApp is
+// untouched.
+func (service serviceChat) MergeChat(ctx context.Context, request domainChat.MergeChatRequest) (response domainChat.MergeChatResponse, err error) {
+	if err = validations.ValidateMergeChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	for _, jid := range []string{request.ChatJID, request.TargetJID} {
+		chat, err := service.chatStorageRepo.GetChatByDevice(deviceID, jid)
+		if err != nil {
+			return response, err
+		}
+		if jid == request.ChatJID && chat == nil {
+			return response, fmt.Errorf("chat with JID %s not found", jid)
+		}
+		if chat != nil && !chat.DeletedAt.IsZero() {
+			return response, fmt.Errorf("chat with JID %s is in the trash, restore it first", jid)
+		}
+	}
+
+	summary, err := service.chatStorageRepo.MergeChats(deviceID, request.ChatJID, request.TargetJID, request.DryRun)
+	if err != nil {
+		return response, fmt.Errorf("failed to merge chat: %w", err)
+	}
+
+	response = domainChat.MergeChatResponse{
+		Status:            "success",
+		ChatJID:           request.ChatJID,
+		TargetJID:         request.TargetJID,
+		DryRun:            request.DryRun,
+		TargetExists:      summary.TargetExists,
+		Messages:          summary.Messages,
+		DuplicateMessages: summary.DuplicateMessages,
+		Reactions:         summary.Reactions,
+		Edits:             summary.Edits,
+		Polls:             summary.Polls,
+		PollVotes:         summary.PollVotes,
+		ChatLabels:        summary.ChatLabels,
+		MessageLabels:     summary.MessageLabels,
+		ReadReceipts:      summary.ReadReceipts,
+		OutboxEvents:      summary.OutboxEvents,
+		Drafts:            summary.Drafts,
+	}
+	if request.DryRun {
+		response.Message = fmt.Sprintf("Merge would move %d messages", summary.Messages)
+		return response, nil
+	}
+	response.Message = fmt.Sprintf("Merged %d messages", summary.Messages)
+
+	logrus.WithFields(logrus.Fields{
+		"device_id":  deviceID,
+		"chat_jid":   request.ChatJID,
+		"target_jid": request.TargetJID,
+		"messages":   summary.Messages,
+	}).Info("Merged chat")
+	return response, nil
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+)
+
+// ReindexSearch starts a background rebuild of the message search index as a
+// job. The chat storage database is shared by every device, so there is a
+// single rebuild at a time process-wide. It does not wait for the rebuild;
+// poll GetSearchReindexStatus or the job for progress.
+func (service serviceChat) ReindexSearch(ctx context.Context) (response domainChat.SearchReindexResponse, err error) {
+	job, started, err := jobs.start(ctx, service.chatStorageRepo, domainJob.TypeSearchReindex, "", true,
+		func(ctx context.Context, report jobReporter) (jobOutput, error) {
+			return jobOutput{}, service.chatStorageRepo.RebuildSearchIndex(ctx, report)
+		})
+	if err != nil {
+		return response, err
+	}
+
+	response.Started = started
+	response.Progress = toSearchReindexProgress(&job)
+	return response, nil
+}
+
+// GetSearchReindexStatus reports the most recent search index rebuild.
+func (service serviceChat) GetSearchReindexStatus(_ context.Context) (response domainChat.SearchReindexProgress, err error) {
+	latest, err := service.chatStorageRepo.GetJobs(&domainChatStorage.JobFilter{Type: domainJob.TypeSearchReindex, Limit: 1})
+	if err != nil {
+		return response, err
+	}
+	if len(latest) == 0 {
+		return domainChat.SearchReindexProgress{Status: domainChat.SearchReindexStatusIdle}, nil
+	}
+
+	job := latest[0]
+	if live, ok := jobs.snapshot(job.ID); ok {
+		job = &live
+	}
+	return toSearchReindexProgress(job), nil
+}
+
+func toSearchReindexProgress(job *domainChatStorage.Job) domainChat.SearchReindexProgress {
+	progress := domainChat.SearchReindexProgress{
+		JobID:          job.ID,
+		Status:         job.Status,
+		CurrentStep:    job.CurrentStep,
+		CompletedSteps: job.Completed,
+		TotalSteps:     job.Total,
+		Error:          job.Error,
+	}
+	if !job.StartedAt.IsZero() {
+		progress.StartedAt = job.StartedAt.Format(time.RFC3339)
+	}
+	if !job.FinishedAt.IsZero() {
+		progress.CompletedAt = job.FinishedAt.Format(time.RFC3339)
+	}
+	return progress
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// chatStatsTopSenders is how many of a group's most active senders are returned.
+const chatStatsTopSenders = 10
+
+func (service serviceChat) GetChatStats(ctx context.Context, request domainChat.ChatStatsRequest) (response domainChat.ChatStatsResponse, err error) {
+	if err = validations.ValidateGetChatStats(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	chat, err := service.chatStorageRepo.GetChatByDevice(deviceID, request.ChatJID)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get chat info")
+		return response, err
+	}
+	if chat == nil {
+		return response, fmt.Errorf("chat with JID %s not found", request.ChatJID)
+	}
+
+	now := time.Now()
+	filter := &domainChatStorage.ChatStatsFilter{
+		DeviceID: deviceID,
+		ChatJID:  request.ChatJID,
+		Since:    time.Date(now.Year(), now.Month(), now.Day()-(request.Days-1), 0, 0, 0, 0, now.Location()),
+	}
+	if strings.HasSuffix(request.ChatJID, "@"+types.GroupServer) {
+		filter.TopSenders = chatStatsTopSenders
+	}
+
+	stats, err := service.chatStorageRepo.GetChatStats(filter)
+	if err != nil {
+		return response, fmt.Errorf("failed to get chat stats: %w", err)
+	}
+
+	response.ChatJID = request.ChatJID
+	response.TotalMessages = stats.TotalMessages
+	response.SentMessages = stats.SentMessages
+	response.ReceivedMessages = stats.ReceivedMessages
+	response.MediaTypes = make(map[string]int, len(stats.MediaTypes))
+	for _, mediaType := range stats.MediaTypes {
+		response.MediaTypes[mediaType.MediaType] = mediaType.Count
+	}
+	if !stats.FirstMessageTime.IsZero() {
+		response.FirstMessageTime = stats.FirstMessageTime.Format(time.RFC3339)
+		response.LastMessageTime = stats.LastMessageTime.Format(time.RFC3339)
+	}
+	for _, sender := range stats.TopSenders {
+		response.TopSenders = append(response.TopSenders, domainChat.SenderStat{JID: sender.Sender, Count: sender.Count})
+	}
+	response.Daily = make([]domainChat.DailyStat, 0, len(stats.Daily))
+	for _, day := range stats.Daily {
+		response.Daily = append(response.Daily, domainChat.DailyStat{Date: day.Date, Count: day.Count})
+	}
+	return response, nil
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/sirupsen/logrus"
+)
+
+// CompactStorage rewrites the chat storage database to return the space left
+// by deleted chats and messages. It is database-wide and blocks writes while
+// it runs, so it is only done on request.
+func (service serviceChat) CompactStorage(ctx context.Context) (response domainChat.StorageCompactionResponse, err error) {
+	result, err := service.chatStorageRepo.CompactStorage(ctx)
+	if err != nil {
+		return response, fmt.Errorf("failed to compact chat storage: %w", err)
+	}
+
+	response = domainChat.StorageCompactionResponse{
+		SizeBefore: result.Before.Bytes,
+		SizeAfter:  result.After.Bytes,
+		FreedBytes: result.Before.Bytes - result.After.Bytes,
+		WALBusy:    result.Checkpoint.Busy,
+		DurationMS: result.Duration.Milliseconds(),
+	}
+	logrus.Infof("Compacted chat storage from %d to %d bytes in %s", response.SizeBefore, response.SizeAfter, result.Duration)
+	return response, nil
+}
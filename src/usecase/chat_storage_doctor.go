@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// CheckStorage looks for chat storage rows that disagree with each other:
+// chats without messages, messages without a chat, and @lid chats that the
+// history sync LID merge missed.
+//
+// With Repair set, LID chats are merged first, then missing chats are recreated
+// and finally chats that are still empty are deleted, so a phone number chat
+// that only becomes non-empty through a merge is kept. Media that no message
+// references any more is removed last, including its downloaded file.
+func (service serviceChat) CheckStorage(ctx context.Context, request domainChat.StorageDoctorRequest) (response domainChat.StorageDoctorReport, err error) {
+	deviceID := deviceIDFromContext(ctx)
+	response.DeviceID = deviceID
+	response.EmptyChats = []string{}
+	response.MissingChats = []domainChat.MissingChatInfo{}
+	response.DuplicateLIDChats = []domainChat.DuplicateLIDChatInfo{}
+
+	emptyChats, err := service.chatStorageRepo.GetEmptyChats(deviceID)
+	if err != nil {
+		return response, fmt.Errorf("failed to find empty chats: %w", err)
+	}
+	response.EmptyChats = append(response.EmptyChats, emptyChats...)
+
+	missingChats, err := service.chatStorageRepo.GetMissingChats(deviceID)
+	if err != nil {
+		return response, fmt.Errorf("failed to find missing chats: %w", err)
+	}
+	for _, chat := range missingChats {
+		response.MissingChats = append(response.MissingChats, domainChat.MissingChatInfo{
+			ChatJID:         chat.ChatJID,
+			MessageCount:    chat.MessageCount,
+			LastMessageTime: chat.LastMessageTime.Format(time.RFC3339),
+		})
+	}
+
+	duplicates, err := service.findDuplicateLIDChats(ctx, deviceID)
+	if err != nil {
+		return response, err
+	}
+	response.DuplicateLIDChats = append(response.DuplicateLIDChats, duplicates...)
+
+	response.Issues = len(response.EmptyChats) + len(response.MissingChats) + len(response.DuplicateLIDChats)
+	if !request.Repair {
+		return response, nil
+	}
+	if response.Issues == 0 {
+		service.removeUnreferencedMedia(deviceID, &response)
+		return response, nil
+	}
+
+	response.Repaired = true
+	for _, duplicate := range duplicates {
+		if err := service.chatStorageRepo.MergeLIDChat(deviceID, duplicate.LIDJID, duplicate.PhoneJID); err != nil {
+			response.RepairErrors = append(response.RepairErrors, fmt.Sprintf("merge %s into %s: %v", duplicate.LIDJID, duplicate.PhoneJID, err))
+		}
+	}
+
+	if len(missingChats) > 0 {
+		if _, err := service.chatStorageRepo.RestoreMissingChats(deviceID); err != nil {
+			response.RepairErrors = append(response.RepairErrors, fmt.Sprintf("restore missing chats: %v", err))
+		}
+	}
+
+	if len(emptyChats) > 0 {
+		stillEmpty, err := service.chatStorageRepo.GetEmptyChats(deviceID)
+		if err != nil {
+			response.RepairErrors = append(response.RepairErrors, fmt.Sprintf("recheck empty chats: %v", err))
+			stillEmpty = nil
+		}
+		for _, jid := range stillEmpty {
+			// Only delete what the report listed.
+			if !slices.Contains(emptyChats, jid) {
+				continue
+			}
+			if err := service.chatStorageRepo.DeleteChatByDevice(deviceID, jid); err != nil {
+				response.RepairErrors = append(response.RepairErrors, fmt.Sprintf("delete empty chat %s: %v", jid, err))
+			}
+		}
+	}
+
+	service.removeUnreferencedMedia(deviceID, &response)
+
+	logrus.WithFields(logrus.Fields{
+		"device_id":           deviceID,
+		"empty_chats":         len(response.EmptyChats),
+		"missing_chats":       len(response.MissingChats),
+		"duplicate_lid_chats": len(response.DuplicateLIDChats),
+		"removed_media":       response.RemovedMedia,
+		"repair_errors":       len(response.RepairErrors),
+	}).Info("Repaired chat storage")
+	return response, nil
+}
+
+// removeUnreferencedMedia deletes the media rows whose messages are all gone
+// and the files they were downloaded to.
+func (service serviceChat) removeUnreferencedMedia(deviceID string, response *domainChat.StorageDoctorReport) {
+	removed, err := service.chatStorageRepo.DeleteUnreferencedMedia(deviceID)
+	if err != nil {
+		response.RepairErrors = append(response.RepairErrors, fmt.Sprintf("remove unreferenced media: %v", err))
+		return
+	}
+	response.RemovedMedia = len(removed)
+	for _, media := range removed {
+		if media.LocalPath == "" {
+			continue
+		}
+		if err := os.Remove(media.LocalPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			response.RepairErrors = append(response.RepairErrors, fmt.Sprintf("remove media file %s: %v", media.LocalPath, err))
+		}
+	}
+}
+
+// findDuplicateLIDChats returns the device's @lid chats whose phone number the
+// WhatsApp store knows. Without a client the mapping is unavailable and none are
+// reported.
+func (service serviceChat) findDuplicateLIDChats(ctx context.Context, deviceID string) ([]domainChat.DuplicateLIDChatInfo, error) {
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return nil, nil
+	}
+
+	lidChats, err := service.chatStorageRepo.GetLIDChats(deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LID chats: %w", err)
+	}
+
+	var duplicates []domainChat.DuplicateLIDChatInfo
+	for _, chat := range lidChats {
+		lidJID, err := types.ParseJID(chat.JID)
+		if err != nil {
+			continue
+		}
+		phoneJID := whatsapp.NormalizeJIDFromLIDWithContext(lidJID, client)
+		if phoneJID.Server == types.HiddenUserServer {
+			continue
+		}
+
+		phoneChat, err := service.chatStorageRepo.GetChatByDevice(deviceID, phoneJID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chat %s: %w", phoneJID.String(), err)
+		}
+		duplicates = append(duplicates, domainChat.DuplicateLIDChatInfo{
+			LIDJID:          chat.JID,
+			PhoneJID:        phoneJID.String(),
+			PhoneChatExists: phoneChat != nil,
+		})
+	}
+	return duplicates, nil
+}
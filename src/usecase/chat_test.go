@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -82,15 +83,21 @@ type chatUsecaseRepoStub struct {
 	messages []*domainChatStorage.Message
 }
 
-func (r *chatUsecaseRepoStub) GetChatByDevice(_, _ string) (*domainChatStorage.Chat, error) {
-	return r.chat, nil
+// GetChatByDevice only knows the chat under test; the per-message sender-name
+// lookup in GetChatMessages gets (nil, nil) and falls through to the push-name
+// cache without affecting the reaction-mapping assertions under test.
+func (r *chatUsecaseRepoStub) GetChatByDevice(_, jid string) (*domainChatStorage.Chat, error) {
+	if r.chat != nil && r.chat.JID == jid {
+		return r.chat, nil
+	}
+	return nil, nil
 }
 
 func (r *chatUsecaseRepoStub) GetMessages(*domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
 	return r.messages, nil
 }
 
-func (r *chatUsecaseRepoStub) GetChatMessageCount(string) (int64, error) {
+func (r *chatUsecaseRepoStub) GetChatMessageCountByDevice(_, _ string) (int64, error) {
 	return int64(len(r.messages)), nil
 }
 
@@ -98,10 +105,11 @@ func (r *chatUsecaseRepoStub) CreateReaction(context.Context, *events.Message) e
 	return nil
 }
 
-// GetChat is exercised by the fork's per-message sender-name lookup in
-// GetChatMessages; returning (nil, nil) makes that fall through to the push-name
-// cache without affecting the reaction-mapping assertions under test.
-func (r *chatUsecaseRepoStub) GetChat(string) (*domainChatStorage.Chat, error) {
+func (r *chatUsecaseRepoStub) GetChatLabelIDs(string, []string) (map[string][]string, error) {
+	return nil, nil
+}
+
+func (r *chatUsecaseRepoStub) GetMessageLabelIDs(string, string, []string) (map[string][]string, error) {
 	return nil, nil
 }
 
@@ -133,3 +141,106 @@ func TestChatDisplayName(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2026, time.May, 16, 8, 0, 0, 123456789, time.UTC)
+	cursor, err := decodeMessageCursor(encodeMessageCursor(ts, "3EB0|ABC"))
+	if err != nil {
+		t.Fatalf("decode cursor: %v", err)
+	}
+	if !cursor.Timestamp.Equal(ts) || cursor.ID != "3EB0|ABC" {
+		t.Fatalf("unexpected cursor %+v", cursor)
+	}
+
+	for _, bad := range []string{"not base64!", "bm8tc2VwYXJhdG9y", encodeMessageCursor(ts, "")} {
+		if _, err := decodeMessageCursor(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestGetChatMessagesReturnsNextCursorOnFullPage(t *testing.T) {
+	deviceID := "device-a@s.whatsapp.net"
+	chatJID := "628123456789@s.whatsapp.net"
+	now := time.Date(2026, time.May, 16, 8, 0, 0, 0, time.UTC)
+	repo := &chatUsecaseRepoStub{
+		chat: &domainChatStorage.Chat{DeviceID: deviceID, JID: chatJID, Name: "Alice"},
+		messages: []*domainChatStorage.Message{
+			{ID: "msg-2", ChatJID: chatJID, DeviceID: deviceID, Content: "b", Timestamp: now.Add(time.Minute), IsFromMe: true},
+			{ID: "msg-1", ChatJID: chatJID, DeviceID: deviceID, Content: "a", Timestamp: now, IsFromMe: true},
+		},
+	}
+	service := NewChatService(repo)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance(deviceID, nil, nil))
+
+	response, err := service.GetChatMessages(ctx, domainChat.GetChatMessagesRequest{ChatJID: chatJID, Limit: 2})
+	if err != nil {
+		t.Fatalf("get chat messages: %v", err)
+	}
+	if response.Pagination.NextCursor != encodeMessageCursor(now, "msg-1") {
+		t.Fatalf("expected next cursor to point at the oldest returned message, got %q", response.Pagination.NextCursor)
+	}
+
+	response, err = service.GetChatMessages(ctx, domainChat.GetChatMessagesRequest{ChatJID: chatJID, Limit: 3})
+	if err != nil {
+		t.Fatalf("get chat messages: %v", err)
+	}
+	if response.Pagination.NextCursor != "" {
+		t.Fatalf("expected no next cursor on a partial page, got %q", response.Pagination.NextCursor)
+	}
+}
+
+type reindexRepoStub struct {
+	jobRepoStub
+	release chan struct{}
+	err     error
+}
+
+func (r *reindexRepoStub) RebuildSearchIndex(_ context.Context, progress func(string, int, int)) error {
+	progress("reindex messages", 0, 2)
+	<-r.release
+	progress("", 2, 2)
+	return r.err
+}
+
+func TestReindexSearch_RunsOnceAndReportsProgress(t *testing.T) {
+	original := jobs
+	t.Cleanup(func() { jobs = original })
+
+	for _, tc := range []struct {
+		name       string
+		err        error
+		wantStatus string
+	}{
+		{"completes", nil, domainChat.SearchReindexStatusCompleted},
+		{"fails", errors.New("disk I/O error"), domainChat.SearchReindexStatusFailed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			jobs = &jobRunner{running: make(map[string]*runningJob)}
+			repo := &reindexRepoStub{release: make(chan struct{}), err: tc.err}
+			service := serviceChat{chatStorageRepo: repo}
+
+			first, err := service.ReindexSearch(context.Background())
+			if err != nil || !first.Started || first.Progress.Status != domainChat.SearchReindexStatusRunning {
+				t.Fatalf("first ReindexSearch = %+v (err %v), want a started run", first, err)
+			}
+			if second, _ := service.ReindexSearch(context.Background()); second.Started {
+				t.Fatal("second ReindexSearch started while a rebuild was running")
+			}
+
+			close(repo.release)
+			var status domainChat.SearchReindexProgress
+			for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+				if status, _ = service.GetSearchReindexStatus(context.Background()); status.Status != domainChat.SearchReindexStatusRunning {
+					break
+				}
+			}
+			if status.Status != tc.wantStatus || status.CompletedSteps != 2 || status.TotalSteps != 2 || status.CompletedAt == "" {
+				t.Fatalf("status = %+v, want %s after 2/2 steps", status, tc.wantStatus)
+			}
+			if tc.err != nil && status.Error != tc.err.Error() {
+				t.Fatalf("status error = %q, want %q", status.Error, tc.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+// DeleteChat removes a chat from chat storage only; the chat on WhatsApp is
+// untouched. With a trash retention configured the chat is moved to the trash
+// and can be restored until it is purged, otherwise it is deleted for good.
+func (service serviceChat) DeleteChat(ctx context.Context, request domainChat.DeleteChatRequest) (response domainChat.DeleteChatResponse, err error) {
+	if err = validations.ValidateDeleteChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	chat, err := service.chatStorageRepo.GetChatByDevice(deviceID, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+	if chat == nil || !chat.DeletedAt.IsZero() {
+		return response, fmt.Errorf("chat with JID %s not found", request.ChatJID)
+	}
+
+	response.ChatJID = request.ChatJID
+	response.Status = "success"
+	if config.ChatStorageTrashRetention <= 0 {
+		if err = service.chatStorageRepo.DeleteChatByDevice(deviceID, request.ChatJID); err != nil {
+			return response, fmt.Errorf("failed to delete chat: %w", err)
+		}
+		response.Message = "Chat deleted"
+		return response, nil
+	}
+
+	if err = service.chatStorageRepo.TrashChat(deviceID, request.ChatJID); err != nil {
+		return response, fmt.Errorf("failed to move chat to trash: %w", err)
+	}
+	response.Trashed = true
+	response.PurgeAt = time.Now().Add(config.ChatStorageTrashRetention).Format(time.RFC3339)
+	response.Message = "Chat moved to trash"
+
+	logrus.WithFields(logrus.Fields{
+		"device_id": deviceID,
+		"chat_jid":  request.ChatJID,
+		"purge_at":  response.PurgeAt,
+	}).Info("Moved chat to trash")
+	return response, nil
+}
+
+func (service serviceChat) RestoreChat(ctx context.Context, request domainChat.RestoreChatRequest) (response domainChat.RestoreChatResponse, err error) {
+	if err = validations.ValidateRestoreChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	restored, err := service.chatStorageRepo.RestoreChat(deviceID, request.ChatJID)
+	if err != nil {
+		return response, fmt.Errorf("failed to restore chat: %w", err)
+	}
+	if !restored {
+		return response, fmt.Errorf("chat with JID %s is not in the trash", request.ChatJID)
+	}
+
+	response.Status = "success"
+	response.Message = "Chat restored from trash"
+	response.ChatJID = request.ChatJID
+	return response, nil
+}
+
+func (service serviceChat) ListTrashedChats(ctx context.Context) (response domainChat.ListTrashedChatsResponse, err error) {
+	chats, err := service.chatStorageRepo.GetChats(&domainChatStorage.ChatFilter{
+		DeviceID: deviceIDFromContext(ctx),
+		Trashed:  true,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainChat.TrashedChatInfo, 0, len(chats))
+	for _, chat := range chats {
+		response.Data = append(response.Data, domainChat.TrashedChatInfo{
+			ChatInfo:  toChatInfo(chat),
+			DeletedAt: chat.DeletedAt.Format(time.RFC3339),
+			PurgeAt:   chat.DeletedAt.Add(config.ChatStorageTrashRetention).Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,341 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// jobProgressInterval bounds how often progress of a running job is written to
+// storage; step changes and the final state are always written.
+const jobProgressInterval = time.Second
+
+// jobs runs long operations in the background of this process. Job state is
+// persisted in chat storage so it outlives the request that started it.
+var jobs = &jobRunner{running: make(map[string]*runningJob)}
+
+// jobReporter records a job's progress. Its signature matches the progress
+// callbacks of repository operations so it can be passed to them directly.
+type jobReporter func(step string, completed, total int)
+
+// jobOutput is what a successful job leaves behind.
+type jobOutput struct {
+	Result     string
+	ResultPath string
+}
+
+type jobFunc func(ctx context.Context, report jobReporter) (jobOutput, error)
+
+type jobRunner struct {
+	mu      sync.Mutex
+	running map[string]*runningJob
+}
+
+type runningJob struct {
+	job       domainChatStorage.Job
+	cancel    context.CancelFunc
+	persisted time.Time
+}
+
+// start records a new running job and runs fn in the background. The job keeps
+// ctx values (such as the device) but not its cancellation. When exclusive is
+// set and a job of the same type and device is already running, that job is
+// returned with started false instead.
+func (r *jobRunner) start(ctx context.Context, repo domainChatStorage.IChatStorageRepository, jobType, deviceID string, exclusive bool, fn jobFunc) (job domainChatStorage.Job, started bool, err error) {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	now := time.Now()
+
+	r.mu.Lock()
+	if exclusive {
+		for _, rj := range r.running {
+			if rj.job.Type == jobType && rj.job.DeviceID == deviceID {
+				r.mu.Unlock()
+				cancel()
+				return rj.job, false, nil
+			}
+		}
+	}
+	rj := &runningJob{
+		job: domainChatStorage.Job{
+			ID:        uuid.NewString(),
+			Type:      jobType,
+			DeviceID:  deviceID,
+			Status:    domainChatStorage.JobStatusRunning,
+			CreatedAt: now,
+			StartedAt: now,
+		},
+		cancel:    cancel,
+		persisted: now,
+	}
+	r.running[rj.job.ID] = rj
+	job = rj.job
+	r.mu.Unlock()
+
+	if err := repo.StoreJob(&job); err != nil {
+		r.mu.Lock()
+		delete(r.running, job.ID)
+		r.mu.Unlock()
+		cancel()
+		return job, false, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	go func() {
+		output, err := fn(runCtx, func(step string, completed, total int) {
+			r.progress(repo, job.ID, step, completed, total)
+		})
+		r.finish(runCtx, repo, job.ID, output, err)
+	}()
+
+	logrus.WithFields(logrus.Fields{"job_id": job.ID, "type": jobType, "device_id": deviceID}).Info("Job started")
+	return job, true, nil
+}
+
+func (r *jobRunner) progress(repo domainChatStorage.IChatStorageRepository, id, step string, completed, total int) {
+	r.mu.Lock()
+	rj, ok := r.running[id]
+	if !ok {
+		r.mu.Unlock()
+		return
+	}
+	stepChanged := rj.job.CurrentStep != step
+	rj.job.CurrentStep = step
+	rj.job.Completed = completed
+	rj.job.Total = total
+	if !stepChanged && time.Since(rj.persisted) < jobProgressInterval {
+		r.mu.Unlock()
+		return
+	}
+	rj.persisted = time.Now()
+	job := rj.job
+	r.mu.Unlock()
+
+	if err := repo.StoreJob(&job); err != nil {
+		logrus.WithError(err).WithField("job_id", id).Warn("Failed to store job progress")
+	}
+}
+
+func (r *jobRunner) finish(runCtx context.Context, repo domainChatStorage.IChatStorageRepository, id string, output jobOutput, err error) {
+	r.mu.Lock()
+	rj, ok := r.running[id]
+	if !ok {
+		r.mu.Unlock()
+		return
+	}
+	rj.job.CurrentStep = ""
+	rj.job.FinishedAt = time.Now()
+	switch {
+	case err == nil:
+		rj.job.Status = domainChatStorage.JobStatusCompleted
+		rj.job.Result = output.Result
+		rj.job.ResultPath = output.ResultPath
+	case runCtx.Err() != nil && errors.Is(err, context.Canceled):
+		rj.job.Status = domainChatStorage.JobStatusCancelled
+	default:
+		rj.job.Status = domainChatStorage.JobStatusFailed
+		rj.job.Error = err.Error()
+	}
+	job := rj.job
+	r.mu.Unlock()
+
+	// Storage is written before the job leaves the running set, so readers never
+	// see an older persisted state after the in-memory one.
+	if storeErr := repo.StoreJob(&job); storeErr != nil {
+		logrus.WithError(storeErr).WithField("job_id", id).Error("Failed to store job result")
+	}
+
+	r.mu.Lock()
+	delete(r.running, id)
+	r.mu.Unlock()
+	rj.cancel()
+
+	entry := logrus.WithFields(logrus.Fields{"job_id": id, "type": job.Type, "status": job.Status})
+	if job.Status == domainChatStorage.JobStatusFailed {
+		entry.WithError(err).Error("Job failed")
+		return
+	}
+	entry.Info("Job finished")
+}
+
+// snapshot returns the live state of a job running in this process.
+func (r *jobRunner) snapshot(id string) (domainChatStorage.Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rj, ok := r.running[id]
+	if !ok {
+		return domainChatStorage.Job{}, false
+	}
+	return rj.job, true
+}
+
+// cancel asks a job running in this process to stop. The job records itself as
+// cancelled once its function returns.
+func (r *jobRunner) cancel(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rj, ok := r.running[id]
+	if ok {
+		rj.cancel()
+	}
+	return ok
+}
+
+// loadJob returns the freshest state of a job: live when it runs in this
+// process, otherwise the stored record.
+func loadJob(repo domainChatStorage.IChatStorageRepository, id string) (*domainChatStorage.Job, error) {
+	if job, ok := jobs.snapshot(id); ok {
+		return &job, nil
+	}
+	job, err := repo.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, pkgError.JobNotFoundError(fmt.Sprintf("job %s not found", id))
+	}
+	return job, nil
+}
+
+// jobDeviceIDs returns the device IDs jobs of the device in ctx can be stored
+// under, its JID once logged in and its ID before, plus "" for database-wide
+// jobs every device may see.
+func jobDeviceIDs(ctx context.Context) []string {
+	ids := []string{""}
+	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
+		ids = append(ids, inst.ID())
+		if jid := inst.JID(); jid != "" && jid != inst.ID() {
+			ids = append(ids, jid)
+		}
+	}
+	return ids
+}
+
+// loadDeviceJob is loadJob for a job the device in ctx started, or a
+// database-wide one. Jobs of other devices are reported as not found.
+func loadDeviceJob(ctx context.Context, repo domainChatStorage.IChatStorageRepository, id string) (*domainChatStorage.Job, error) {
+	job, err := loadJob(repo, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(jobDeviceIDs(ctx), job.DeviceID) {
+		return nil, pkgError.JobNotFoundError(fmt.Sprintf("job %s not found", id))
+	}
+	return job, nil
+}
+
+type serviceJob struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+// NewJobService creates the job usecase. Jobs run in-process, so any still
+// marked active in storage were interrupted by a restart and are failed here.
+func NewJobService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainJob.IJobUsecase {
+	if n, err := chatStorageRepo.FailActiveJobs("interrupted by restart"); err != nil {
+		logrus.WithError(err).Warn("Failed to mark interrupted jobs")
+	} else if n > 0 {
+		logrus.Warnf("Marked %d interrupted jobs as failed", n)
+	}
+	return &serviceJob{chatStorageRepo: chatStorageRepo}
+}
+
+func (service serviceJob) ListJobs(ctx context.Context, request domainJob.ListJobsRequest) (response domainJob.ListJobsResponse, err error) {
+	if request.Limit <= 0 || request.Limit > 100 {
+		request.Limit = 50
+	}
+	stored, err := service.chatStorageRepo.GetJobs(&domainChatStorage.JobFilter{
+		Type:      request.Type,
+		Status:    request.Status,
+		DeviceIDs: jobDeviceIDs(ctx),
+		Limit:     request.Limit,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainJob.JobInfo, 0, len(stored))
+	for _, job := range stored {
+		if live, ok := jobs.snapshot(job.ID); ok {
+			job = &live
+		}
+		response.Data = append(response.Data, toJobInfo(job))
+	}
+	return response, nil
+}
+
+func (service serviceJob) GetJob(ctx context.Context, jobID string) (response domainJob.JobInfo, err error) {
+	job, err := loadDeviceJob(ctx, service.chatStorageRepo, jobID)
+	if err != nil {
+		return response, err
+	}
+	return toJobInfo(job), nil
+}
+
+func (service serviceJob) CancelJob(ctx context.Context, jobID string) (response domainJob.JobInfo, err error) {
+	job, err := loadDeviceJob(ctx, service.chatStorageRepo, jobID)
+	if err != nil {
+		return response, err
+	}
+	if jobs.cancel(jobID) {
+		return service.GetJob(ctx, jobID)
+	}
+	if !job.IsActive() {
+		return response, pkgError.JobStateError(fmt.Sprintf("job %s is already %s", jobID, job.Status))
+	}
+
+	// Active in storage but not running here: nothing to stop, just record it.
+	job.Status = domainChatStorage.JobStatusCancelled
+	job.FinishedAt = time.Now()
+	if err := service.chatStorageRepo.StoreJob(job); err != nil {
+		return response, err
+	}
+	return toJobInfo(job), nil
+}
+
+func (service serviceJob) GetJobResult(ctx context.Context, jobID string) (response domainJob.JobResultFile, err error) {
+	job, err := loadDeviceJob(ctx, service.chatStorageRepo, jobID)
+	if err != nil {
+		return response, err
+	}
+	if job.Status != domainChatStorage.JobStatusCompleted || job.ResultPath == "" {
+		return response, pkgError.JobStateError(fmt.Sprintf("job %s has no downloadable result", jobID))
+	}
+	if _, err := os.Stat(job.ResultPath); err != nil {
+		return response, pkgError.JobNotFoundError(fmt.Sprintf("result of job %s is no longer available", jobID))
+	}
+	response.Path = job.ResultPath
+	response.Filename = job.Result
+	return response, nil
+}
+
+func toJobInfo(job *domainChatStorage.Job) domainJob.JobInfo {
+	info := domainJob.JobInfo{
+		ID:           job.ID,
+		Type:         job.Type,
+		DeviceID:     job.DeviceID,
+		Status:       job.Status,
+		CurrentStep:  job.CurrentStep,
+		Completed:    job.Completed,
+		Total:        job.Total,
+		Result:       job.Result,
+		Downloadable: job.Status == domainChatStorage.JobStatusCompleted && job.ResultPath != "",
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt.Format(time.RFC3339),
+	}
+	if !job.StartedAt.IsZero() {
+		info.StartedAt = job.StartedAt.Format(time.RFC3339)
+	}
+	if !job.FinishedAt.IsZero() {
+		info.FinishedAt = job.FinishedAt.Format(time.RFC3339)
+	}
+	return info
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// jobRepoStub keeps jobs in memory, newest last.
+type jobRepoStub struct {
+	domainChatStorage.IChatStorageRepository
+	mu   sync.Mutex
+	jobs []domainChatStorage.Job
+}
+
+func (r *jobRepoStub) StoreJob(job *domainChatStorage.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.jobs {
+		if r.jobs[i].ID == job.ID {
+			r.jobs[i] = *job
+			return nil
+		}
+	}
+	r.jobs = append(r.jobs, *job)
+	return nil
+}
+
+func (r *jobRepoStub) GetJob(id string) (*domainChatStorage.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.ID == id {
+			return &job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *jobRepoStub) GetJobs(filter *domainChatStorage.JobFilter) ([]*domainChatStorage.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domainChatStorage.Job
+	for i := len(r.jobs) - 1; i >= 0; i-- {
+		job := r.jobs[i]
+		if filter.Type != "" && job.Type != filter.Type {
+			continue
+		}
+		if len(filter.DeviceIDs) > 0 && !slices.Contains(filter.DeviceIDs, job.DeviceID) {
+			continue
+		}
+		out = append(out, &job)
+		if filter.Limit > 0 && len(out) == filter.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (r *jobRepoStub) FailActiveJobs(string) (int64, error) { return 0, nil }
+
+func waitForJob(t *testing.T, ctx context.Context, service domainJob.IJobUsecase, id string) domainJob.JobInfo {
+	t.Helper()
+	var job domainJob.JobInfo
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		var err error
+		if job, err = service.GetJob(ctx, id); err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if job.Status != domainChatStorage.JobStatusRunning {
+			return job
+		}
+	}
+	t.Fatalf("job %s still running", id)
+	return job
+}
+
+func TestJobRunner_CancelStopsJobAndRejectsSecondCancel(t *testing.T) {
+	original := jobs
+	t.Cleanup(func() { jobs = original })
+	jobs = &jobRunner{running: make(map[string]*runningJob)}
+
+	repo := &jobRepoStub{}
+	service := NewJobService(repo)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+
+	job, started, err := jobs.start(ctx, repo, "test", "device-a", false,
+		func(ctx context.Context, report jobReporter) (jobOutput, error) {
+			report("waiting", 1, 2)
+			<-ctx.Done()
+			return jobOutput{}, ctx.Err()
+		})
+	if err != nil || !started {
+		t.Fatalf("start = %v, %v", started, err)
+	}
+
+	if _, err := service.CancelJob(ctx, job.ID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	final := waitForJob(t, ctx, service, job.ID)
+	if final.Status != domainChatStorage.JobStatusCancelled || final.Completed != 1 || final.Total != 2 || final.FinishedAt == "" {
+		t.Fatalf("job = %+v, want cancelled at 1/2", final)
+	}
+
+	if _, err := service.CancelJob(ctx, job.ID); err == nil {
+		t.Fatal("cancelling a finished job should fail")
+	}
+	if _, err := service.GetJob(ctx, "missing"); err == nil {
+		t.Fatal("GetJob on an unknown id should fail")
+	}
+}
+
+func TestJobsOfOtherDevicesAreNotFound(t *testing.T) {
+	original := jobs
+	t.Cleanup(func() { jobs = original })
+	jobs = &jobRunner{running: make(map[string]*runningJob)}
+
+	now := time.Now()
+	repo := &jobRepoStub{jobs: []domainChatStorage.Job{
+		{ID: "export-a", Type: domainJob.TypeChatExport, DeviceID: "device-a", Status: domainChatStorage.JobStatusCompleted, ResultPath: "/tmp/export.json", CreatedAt: now},
+		{ID: "campaign-a", Type: domainJob.TypeCampaign, DeviceID: "device-a", Status: domainChatStorage.JobStatusRunning, CreatedAt: now},
+		{ID: "reindex", Type: domainJob.TypeSearchReindex, Status: domainChatStorage.JobStatusCompleted, CreatedAt: now},
+	}}
+	service := NewJobService(repo)
+	deviceB := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-b", nil, nil))
+
+	var notFound pkgError.JobNotFoundError
+	if _, err := service.GetJob(deviceB, "export-a"); !errors.As(err, &notFound) {
+		t.Errorf("GetJob of another device = %v, want not found", err)
+	}
+	if _, err := service.GetJobResult(deviceB, "export-a"); !errors.As(err, &notFound) {
+		t.Errorf("GetJobResult of another device = %v, want not found", err)
+	}
+	if _, err := service.CancelJob(deviceB, "campaign-a"); !errors.As(err, &notFound) {
+		t.Errorf("CancelJob of another device = %v, want not found", err)
+	}
+	if repo.jobs[1].Status != domainChatStorage.JobStatusRunning {
+		t.Errorf("campaign of device-a was cancelled by device-b")
+	}
+	if _, err := (serviceSend{chatStorageRepo: repo}).GetCampaign(deviceB, "campaign-a"); !errors.As(err, &notFound) {
+		t.Errorf("GetCampaign of another device = %v, want not found", err)
+	}
+
+	// Database-wide jobs are visible to every device.
+	if _, err := service.GetJob(deviceB, "reindex"); err != nil {
+		t.Errorf("GetJob of a database-wide job: %v", err)
+	}
+	listed, err := service.ListJobs(deviceB, domainJob.ListJobsRequest{})
+	if err != nil || len(listed.Data) != 1 || listed.Data[0].ID != "reindex" {
+		t.Errorf("ListJobs = %+v, %v, want only the database-wide job", listed.Data, err)
+	}
+}
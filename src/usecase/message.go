@@ -20,6 +20,7 @@ import (
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/proto/waSyncAction"
 	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -53,6 +54,13 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 		return response, err
 	}
 
+	// A read receipt covers every earlier message of the chat, so nothing is unread anymore.
+	unread := 0
+	chatJID := utils.ResolveLIDToPhone(ctx, dataWaRecipient, client).String()
+	if err := service.chatStorageRepo.UpdateChatState(deviceIDFromContext(ctx), chatJID, domainChatStorage.ChatStateUpdate{UnreadCount: &unread}); err != nil {
+		logrus.WithError(err).WithField("chat_jid", chatJID).Warn("Failed to reset unread count")
+	}
+
 	logrus.Info(map[string]any{
 		"phone":      request.Phone,
 		"message_id": request.MessageID,
@@ -80,45 +88,64 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 		return response, err
 	}
 
-	// Determine the sender of the original message for BuildReaction.
-	// BuildReaction uses BuildMessageKey internally, which correctly sets the
-	// Participant field for group chats — required by the WhatsApp protocol.
-	// An empty JID means "message was from me".
-	senderJID := types.EmptyJID
-	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID)
-	if err != nil {
-		logrus.Warnf("Failed to lookup message %s for reaction: %v, using heuristic", request.MessageID, err)
-		if len(request.MessageID) > 22 {
-			if dataWaRecipient.Server == types.GroupServer {
-				logrus.Warnf("Cannot determine original sender for group reaction to %s — reaction may not be delivered", request.MessageID)
-			}
-		}
-	} else if message != nil {
-		if !message.IsFromMe && message.Sender != "" {
-			parsed, parseErr := utils.ParseJID(message.Sender)
-			if parseErr == nil {
-				senderJID = parsed
-			} else {
-				logrus.Warnf("Failed to parse sender JID '%s' for reaction: %v", message.Sender, parseErr)
-			}
-		}
-	} else {
-		logrus.Debugf("Message %s not found in database, assuming sent by me", request.MessageID)
-	}
-
 	// BuildReaction correctly constructs the MessageKey with Participant field
 	// for group chats, which is required for the reaction to be delivered.
+	senderJID := service.reactionSender(ctx, request.MessageID, dataWaRecipient)
 	msg := client.BuildReaction(dataWaRecipient, senderJID, request.MessageID, request.Emoji)
+	if err := whatsapp.ReserveSend(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
 	ts, err := client.SendMessage(ctx, dataWaRecipient, msg)
 	if err != nil {
 		return response, err
 	}
 
+	// WhatsApp does not echo our own reactions back, so store and confirm them here.
+	if evt := whatsapp.SentMessageEvent(client, dataWaRecipient, ts, msg); evt != nil {
+		if err := service.chatStorageRepo.CreateReaction(ctx, evt); err != nil {
+			logrus.Warnf("Failed to store reaction to %s: %v", request.MessageID, err)
+		}
+	}
+	whatsapp.ForwardSentReactionToWebhook(ctx, client, dataWaRecipient, ts, msg)
+
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	if request.Emoji == "" {
+		response.Status = fmt.Sprintf("Reaction removed from %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	} else {
+		response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	}
 	return response, nil
 }
 
+// reactionSender returns the sender of the message a reaction targets, for
+// BuildReaction. BuildReaction uses BuildMessageKey internally, which sets the
+// Participant field for group chats — required by the WhatsApp protocol. An
+// empty JID means "message was from me". Message IDs are only unique within a
+// chat, so the lookup is scoped to the device and chat.
+func (service serviceMessage) reactionSender(ctx context.Context, messageID string, chat types.JID) types.JID {
+	message, err := service.chatStorageRepo.GetMessageByID(messageID, deviceIDFromContext(ctx), chat.String())
+	if err != nil {
+		logrus.Warnf("Failed to lookup message %s for reaction: %v, using heuristic", messageID, err)
+		if len(messageID) > 22 && chat.Server == types.GroupServer {
+			logrus.Warnf("Cannot determine original sender for group reaction to %s — reaction may not be delivered", messageID)
+		}
+		return types.EmptyJID
+	}
+	if message == nil {
+		logrus.Debugf("Message %s not found in database, assuming sent by me", messageID)
+		return types.EmptyJID
+	}
+	if message.IsFromMe || message.Sender == "" {
+		return types.EmptyJID
+	}
+	parsed, err := utils.ParseJID(message.Sender)
+	if err != nil {
+		logrus.Warnf("Failed to parse sender JID '%s' for reaction: %v", message.Sender, err)
+		return types.EmptyJID
+	}
+	return parsed
+}
+
 func (service serviceMessage) RevokeMessage(ctx context.Context, request domainMessage.RevokeRequest) (response domainMessage.GenericResponse, err error) {
 	if err = validations.ValidateRevokeMessage(ctx, request); err != nil {
 		return response, err
@@ -141,7 +168,7 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 	// via GetMessageByID yields the same sender regardless of which device
 	// owns the row.
 	senderJID := types.EmptyJID
-	message, lookupErr := service.chatStorageRepo.GetMessageByID(request.MessageID)
+	message, lookupErr := service.chatStorageRepo.GetMessageByID(request.MessageID, "", "")
 	if lookupErr != nil {
 		logrus.Warnf("Failed to lookup message %s for revoke: %v, assuming self-revoke", request.MessageID, lookupErr)
 	} else if message != nil && !message.IsFromMe && message.Sender != "" {
@@ -155,6 +182,9 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 		}
 	}
 
+	if err := whatsapp.ReserveSend(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
 	ts, err := client.SendMessage(ctx, dataWaRecipient, client.BuildRevoke(dataWaRecipient, senderJID, request.MessageID))
 	if err != nil {
 		return response, err
@@ -220,17 +250,74 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 		return response, err
 	}
 
+	// WhatsApp drops edits of messages that are not ours or are past the edit
+	// window without telling the sender, so check what storage knows first.
+	// Unknown messages are left for the server to judge.
+	stored, lookupErr := service.chatStorageRepo.GetMessageByIDAndDevice(deviceIDFromContext(ctx), request.MessageID)
+	if lookupErr != nil {
+		logrus.Warnf("Failed to lookup message %s for edit: %v", request.MessageID, lookupErr)
+	} else if stored != nil {
+		if err = checkMessageEditable(stored, time.Now()); err != nil {
+			return response, err
+		}
+	}
+
+	// The new text replaces the caption of image, video and document messages.
 	msg := &waE2E.Message{Conversation: proto.String(request.Message)}
-	ts, err := client.SendMessage(ctx, dataWaRecipient, client.BuildEdit(dataWaRecipient, request.MessageID, msg))
+	if _, err = whatsapp.ApplySendPolicy(ctx, dataWaRecipient, msg, request.Message); err != nil {
+		return response, err
+	}
+	if err := whatsapp.ReserveSend(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+	edit := client.BuildEdit(dataWaRecipient, request.MessageID, msg)
+	ts, err := client.SendMessage(ctx, dataWaRecipient, edit)
 	if err != nil {
 		return response, err
 	}
 
+	// Our own edits are not echoed back, so record the edit like an incoming one.
+	if client.Store != nil && client.Store.ID != nil {
+		evt := &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{
+					Chat:     dataWaRecipient,
+					Sender:   client.Store.ID.ToNonAD(),
+					IsFromMe: true,
+				},
+				ID:        ts.ID,
+				Timestamp: ts.Timestamp,
+			},
+			Message: edit,
+		}
+		if err := service.chatStorageRepo.CreateMessage(ctx, evt); err != nil {
+			logrus.Warnf("Failed to store edit of message %s: %v", request.MessageID, err)
+		}
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Update message success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
 }
 
+// checkMessageEditable rejects edits WhatsApp would ignore: messages sent by
+// someone else, messages without text or caption, and messages older than
+// whatsmeow.EditWindow.
+func checkMessageEditable(message *domainChatStorage.Message, now time.Time) error {
+	if !message.IsFromMe {
+		return pkgError.ValidationError("only messages you sent can be edited")
+	}
+	switch message.MediaType {
+	case "audio", "sticker", "video_note":
+		return pkgError.ValidationError(fmt.Sprintf("%s messages have no text to edit", message.MediaType))
+	}
+	if !message.Timestamp.IsZero() && now.Sub(message.Timestamp) > whatsmeow.EditWindow {
+		return pkgError.EditWindowExpiredError(fmt.Sprintf("message %s was sent %s ago; messages can only be edited within %s",
+			message.ID, now.Sub(message.Timestamp).Round(time.Minute), whatsmeow.EditWindow))
+	}
+	return nil
+}
+
 // StarMessage implements message.IMessageService.
 func (service serviceMessage) StarMessage(ctx context.Context, request domainMessage.StarRequest) (err error) {
 	if err = validations.ValidateStarMessage(ctx, request); err != nil {
@@ -260,6 +347,117 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 	return nil
 }
 
+func (service serviceMessage) LabelMessage(ctx context.Context, request domainMessage.LabelMessageRequest) (response domainMessage.GenericResponse, err error) {
+	if err = validations.ValidateLabelMessage(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	dataWaRecipient, err := utils.ValidateAndNormalizeJID(client, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	chatJID := dataWaRecipient.ToNonAD()
+
+	patchInfo := appstate.BuildLabelMessage(chatJID, request.LabelID, request.MessageID, request.Labeled)
+	if err = client.SendAppState(ctx, patchInfo); err != nil {
+		return response, err
+	}
+
+	// Update local storage immediately; the app state echo is idempotent
+	if err := service.chatStorageRepo.SetMessageLabel(deviceIDFromContext(ctx), chatJID.String(), request.MessageID, request.LabelID, request.Labeled); err != nil {
+		logrus.WithError(err).WithField("message_id", request.MessageID).Warn("Failed to update local message label")
+	}
+
+	response.MessageID = request.MessageID
+	if request.Labeled {
+		response.Status = fmt.Sprintf("Label %s applied to message", request.LabelID)
+	} else {
+		response.Status = fmt.Sprintf("Label %s removed from message", request.LabelID)
+	}
+	return response, nil
+}
+
+// PinMessage pins a message in its chat for everyone, for request.Duration
+// (seven days by default), or unpins it.
+func (service serviceMessage) PinMessage(ctx context.Context, request domainMessage.PinMessageRequest) (response domainMessage.GenericResponse, err error) {
+	if err = validations.ValidatePinMessage(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	dataWaRecipient, err := utils.ValidateAndNormalizeJID(client, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	// The pin key needs the original sender in groups, as for reactions.
+	senderJID := types.EmptyJID
+	message, lookupErr := service.chatStorageRepo.GetMessageByID(request.MessageID, deviceIDFromContext(ctx), dataWaRecipient.String())
+	if lookupErr != nil {
+		logrus.Warnf("Failed to lookup message %s for pin: %v, assuming it was sent by me", request.MessageID, lookupErr)
+	} else if message != nil && !message.IsFromMe && message.Sender != "" {
+		parsed, parseErr := utils.ParseJID(message.Sender)
+		if parseErr != nil {
+			logrus.Warnf("Failed to parse sender JID '%s' for pin: %v", message.Sender, parseErr)
+		} else {
+			senderJID = parsed
+		}
+	}
+
+	pinType := waE2E.PinInChatMessage_UNPIN_FOR_ALL
+	if request.Pinned {
+		pinType = waE2E.PinInChatMessage_PIN_FOR_ALL
+	}
+	msg := &waE2E.Message{
+		PinInChatMessage: &waE2E.PinInChatMessage{
+			Key:               client.BuildMessageKey(dataWaRecipient, senderJID, request.MessageID),
+			Type:              pinType.Enum(),
+			SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
+		},
+	}
+	duration := request.Duration
+	if request.Pinned {
+		if duration == 0 {
+			duration = domainMessage.PinDuration7Days
+		}
+		msg.MessageContextInfo = &waE2E.MessageContextInfo{
+			MessageAddOnDurationInSecs: proto.Uint32(uint32(duration)),
+		}
+	}
+
+	if err := whatsapp.ReserveSend(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+	ts, err := client.SendMessage(ctx, dataWaRecipient, msg)
+	if err != nil {
+		return response, err
+	}
+
+	// WhatsApp does not echo our own pins back, so store them here.
+	if evt := whatsapp.SentMessageEvent(client, dataWaRecipient, ts, msg); evt != nil {
+		if err := service.chatStorageRepo.CreateMessagePin(ctx, evt); err != nil {
+			logrus.Warnf("Failed to store pin of %s: %v", request.MessageID, err)
+		}
+	}
+
+	response.MessageID = ts.ID
+	if request.Pinned {
+		response.Status = fmt.Sprintf("Message %s pinned in %s for %s", request.MessageID, request.Phone, time.Duration(duration)*time.Second)
+	} else {
+		response.Status = fmt.Sprintf("Message %s unpinned in %s", request.MessageID, request.Phone)
+	}
+	return response, nil
+}
+
 // DownloadMedia implements message.IMessageService.
 func (service serviceMessage) DownloadMedia(ctx context.Context, request domainMessage.DownloadMediaRequest) (response domainMessage.DownloadMediaResponse, err error) {
 	if err = validations.ValidateDownloadMedia(ctx, request); err != nil {
@@ -276,14 +474,14 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, err
 	}
 
-	// Query the message from chat storage
-	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID)
+	// Query the message from chat storage, scoped to the requested chat
+	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID, deviceIDFromContext(ctx), dataWaRecipient.String())
 	if err != nil {
 		return response, fmt.Errorf("message not found: %v", err)
 	}
 
 	if message == nil {
-		return response, fmt.Errorf("message with ID %s not found", request.MessageID)
+		return response, fmt.Errorf("message with ID %s not found in chat %s", request.MessageID, dataWaRecipient.String())
 	}
 
 	// Check if message has media
@@ -291,99 +489,127 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, fmt.Errorf("message %s does not contain downloadable media", request.MessageID)
 	}
 
-	// Verify the message is from the specified chat
-	if message.ChatJID != dataWaRecipient.String() {
-		return response, fmt.Errorf("message %s does not belong to chat %s", request.MessageID, dataWaRecipient.String())
-	}
+	// The same file forwarded to several chats is only downloaded once.
+	mediaPath := service.downloadedMediaPath(message)
+	if mediaPath == "" {
+		// Create directory structure for organized storage
+		chatDir := filepath.Join(config.PathMedia, utils.ExtractPhoneNumber(message.ChatJID))
+		dateDir := filepath.Join(chatDir, message.Timestamp.Format("2006-01-02"))
 
-	// Create directory structure for organized storage
-	chatDir := filepath.Join(config.PathMedia, utils.ExtractPhoneNumber(message.ChatJID))
-	dateDir := filepath.Join(chatDir, message.Timestamp.Format("2006-01-02"))
+		err = os.MkdirAll(dateDir, 0755)
+		if err != nil {
+			return response, fmt.Errorf("failed to create directory: %v", err)
+		}
+
+		downloadableMsg, err := storedMediaDownloadable(message)
+		if err != nil {
+			return response, err
+		}
 
-	err = os.MkdirAll(dateDir, 0755)
+		// Download the media using existing utils.ExtractMedia function
+		extractedMedia, err := utils.ExtractMedia(ctx, client, dateDir, downloadableMsg)
+		if err != nil {
+			return response, fmt.Errorf("failed to download media: %v", err)
+		}
+		mediaPath = extractedMedia.MediaPath
+
+		if len(message.FileSHA256) > 0 {
+			if err := service.chatStorageRepo.SetMediaLocalPath(message.DeviceID, message.FileSHA256, mediaPath); err != nil {
+				logrus.Warnf("Could not record download path of message %s: %v", message.ID, err)
+			}
+		}
+	}
+
+	// Get file size
+	fileInfo, err := os.Stat(mediaPath)
 	if err != nil {
-		return response, fmt.Errorf("failed to create directory: %v", err)
+		logrus.Warnf("Could not get file size for %s: %v", mediaPath, err)
+	}
+
+	// Build response
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Media downloaded successfully to %s", mediaPath)
+	response.MediaType = message.MediaType
+	response.Filename = filepath.Base(mediaPath)
+	response.FilePath = mediaPath
+	if fileInfo != nil {
+		response.FileSize = fileInfo.Size()
 	}
 
-	// Create a downloadable message interface based on media type
-	var downloadableMsg any
+	logrus.Info(map[string]any{
+		"message_id": request.MessageID,
+		"phone":      request.Phone,
+		"chat":       dataWaRecipient.String(),
+		"media_type": response.MediaType,
+		"file_path":  response.FilePath,
+		"file_size":  response.FileSize,
+	})
+
+	return response, nil
+}
 
+// downloadedMediaPath returns where an earlier download of the message's file
+// was saved, or "" when it has to be downloaded.
+func (service serviceMessage) downloadedMediaPath(message *domainChatStorage.Message) string {
+	if len(message.FileSHA256) == 0 {
+		return ""
+	}
+	media, err := service.chatStorageRepo.GetMedia(message.DeviceID, message.FileSHA256)
+	if err != nil || media == nil || media.LocalPath == "" {
+		return ""
+	}
+	if _, err := os.Stat(media.LocalPath); err != nil {
+		return ""
+	}
+	return media.LocalPath
+}
+
+// storedMediaDownloadable rebuilds a whatsmeow downloadable message from the media
+// references persisted in chat storage.
+func storedMediaDownloadable(message *domainChatStorage.Message) (whatsmeow.DownloadableMessage, error) {
 	switch message.MediaType {
 	case "image":
-		downloadableMsg = &waE2E.ImageMessage{
+		return &waE2E.ImageMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	case "video":
-		downloadableMsg = &waE2E.VideoMessage{
+		return &waE2E.VideoMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	case "audio":
-		downloadableMsg = &waE2E.AudioMessage{
+		return &waE2E.AudioMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	case "document":
-		downloadableMsg = &waE2E.DocumentMessage{
+		return &waE2E.DocumentMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
 			FileName:      proto.String(message.Filename),
-		}
+		}, nil
 	case "sticker":
-		downloadableMsg = &waE2E.StickerMessage{
+		return &waE2E.StickerMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	default:
-		return response, fmt.Errorf("unsupported media type: %s", message.MediaType)
-	}
-
-	// Download the media using existing utils.ExtractMedia function
-	extractedMedia, err := utils.ExtractMedia(ctx, client, dateDir, downloadableMsg.(whatsmeow.DownloadableMessage))
-	if err != nil {
-		return response, fmt.Errorf("failed to download media: %v", err)
-	}
-
-	// Get file size
-	fileInfo, err := os.Stat(extractedMedia.MediaPath)
-	if err != nil {
-		logrus.Warnf("Could not get file size for %s: %v", extractedMedia.MediaPath, err)
-	}
-
-	// Build response
-	response.MessageID = request.MessageID
-	response.Status = fmt.Sprintf("Media downloaded successfully to %s", extractedMedia.MediaPath)
-	response.MediaType = message.MediaType
-	response.Filename = filepath.Base(extractedMedia.MediaPath)
-	response.FilePath = extractedMedia.MediaPath
-	if fileInfo != nil {
-		response.FileSize = fileInfo.Size()
+		return nil, fmt.Errorf("unsupported media type: %s", message.MediaType)
 	}
-
-	logrus.Info(map[string]any{
-		"message_id": request.MessageID,
-		"phone":      request.Phone,
-		"chat":       dataWaRecipient.String(),
-		"media_type": response.MediaType,
-		"file_path":  response.FilePath,
-		"file_size":  response.FileSize,
-	})
-
-	return response, nil
 }
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// ForwardMessage forwards a stored message to each target chat. Media is
+// forwarded by reference to the file already on WhatsApp's servers, so nothing
+// is downloaded or uploaded again. Targets fail independently; the request
+// only fails when no target was sent to.
+func (service serviceMessage) ForwardMessage(ctx context.Context, request domainMessage.ForwardMessageRequest) (response domainMessage.ForwardMessageResponse, err error) {
+	if err = validations.ValidateForwardMessage(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	source, err := utils.ValidateAndNormalizeJID(client, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID, deviceIDFromContext(ctx), source.String())
+	if err != nil {
+		return response, fmt.Errorf("failed to get message %s: %w", request.MessageID, err)
+	}
+	if message == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("message with ID %s not found in chat %s", request.MessageID, source.String()))
+	}
+
+	var lastErr error
+	sent := 0
+	for _, target := range request.Targets {
+		result := domainMessage.ForwardResult{Phone: target}
+		if result.MessageID, err = service.forwardTo(ctx, message, target); err != nil {
+			lastErr = err
+			result.Error = err.Error()
+			logrus.Warnf("Failed to forward message %s to %s: %v", message.ID, target, err)
+		} else {
+			sent++
+		}
+		response.Results = append(response.Results, result)
+	}
+	if sent == 0 {
+		return response, lastErr
+	}
+
+	response.MessageID = message.ID
+	response.Status = fmt.Sprintf("Message %s forwarded to %d of %d chats", message.ID, sent, len(request.Targets))
+	return response, nil
+}
+
+// forwardTo sends the forwarded copy of message to one chat and returns the new message ID.
+func (service serviceMessage) forwardTo(ctx context.Context, message *domainChatStorage.Message, target string) (string, error) {
+	client := whatsapp.ClientFromContext(ctx)
+	utils.SanitizePhone(&target)
+	recipient, err := utils.ValidateAndNormalizeJID(client, target)
+	if err != nil {
+		return "", err
+	}
+	// Every target gets its own copy: sending may modify the message.
+	msg, err := forwardedMessage(message)
+	if err != nil {
+		return "", err
+	}
+	ts, err := sendAndStoreMessage(ctx, service.chatStorageRepo, client, recipient, msg, message.Content)
+	if err != nil {
+		return "", err
+	}
+	return ts.ID, nil
+}
+
+// forwardedMessage rebuilds a stored message for sending, with the forwarded
+// flag set. Media messages point at the stored upload; types that chat
+// storage only keeps as text are rejected.
+func forwardedMessage(message *domainChatStorage.Message) (*waE2E.Message, error) {
+	contextInfo := &waE2E.ContextInfo{
+		IsForwarded:     proto.Bool(true),
+		ForwardingScore: proto.Uint32(1),
+	}
+
+	if message.MediaType == "" {
+		if message.Content == "" {
+			return nil, pkgError.ValidationError(fmt.Sprintf("message %s has no content to forward", message.ID))
+		}
+		return &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        proto.String(message.Content),
+			ContextInfo: contextInfo,
+		}}, nil
+	}
+
+	if message.URL == "" || len(message.MediaKey) == 0 {
+		return nil, pkgError.ValidationError(fmt.Sprintf("media of message %s is not stored and cannot be forwarded", message.ID))
+	}
+	directPath := mediaDirectPath(message.URL)
+	var caption *string
+	if message.Content != "" {
+		caption = proto.String(message.Content)
+	}
+
+	switch message.MediaType {
+	case "image":
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			URL: proto.String(message.URL), DirectPath: directPath, MediaKey: message.MediaKey,
+			FileSHA256: message.FileSHA256, FileEncSHA256: message.FileEncSHA256, FileLength: proto.Uint64(message.FileLength),
+			Mimetype: proto.String("image/jpeg"), Caption: caption, ContextInfo: contextInfo,
+		}}, nil
+	case "video", "video_note":
+		video := &waE2E.VideoMessage{
+			URL: proto.String(message.URL), DirectPath: directPath, MediaKey: message.MediaKey,
+			FileSHA256: message.FileSHA256, FileEncSHA256: message.FileEncSHA256, FileLength: proto.Uint64(message.FileLength),
+			Mimetype: proto.String("video/mp4"), Caption: caption, ContextInfo: contextInfo,
+		}
+		if message.MediaType == "video_note" {
+			return &waE2E.Message{PtvMessage: video}, nil
+		}
+		return &waE2E.Message{VideoMessage: video}, nil
+	case "audio":
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			URL: proto.String(message.URL), DirectPath: directPath, MediaKey: message.MediaKey,
+			FileSHA256: message.FileSHA256, FileEncSHA256: message.FileEncSHA256, FileLength: proto.Uint64(message.FileLength),
+			Mimetype: proto.String("audio/ogg; codecs=opus"), ContextInfo: contextInfo,
+		}}, nil
+	case "document":
+		mimetype := mime.TypeByExtension(filepath.Ext(message.Filename))
+		if mimetype == "" {
+			mimetype = "application/octet-stream"
+		}
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			URL: proto.String(message.URL), DirectPath: directPath, MediaKey: message.MediaKey,
+			FileSHA256: message.FileSHA256, FileEncSHA256: message.FileEncSHA256, FileLength: proto.Uint64(message.FileLength),
+			Mimetype: proto.String(mimetype), FileName: proto.String(message.Filename), Title: proto.String(message.Filename),
+			Caption: caption, ContextInfo: contextInfo,
+		}}, nil
+	case "sticker":
+		return &waE2E.Message{StickerMessage: &waE2E.StickerMessage{
+			URL: proto.String(message.URL), DirectPath: directPath, MediaKey: message.MediaKey,
+			FileSHA256: message.FileSHA256, FileEncSHA256: message.FileEncSHA256, FileLength: proto.Uint64(message.FileLength),
+			Mimetype: proto.String("image/webp"), ContextInfo: contextInfo,
+		}}, nil
+	default:
+		return nil, pkgError.ValidationError(fmt.Sprintf("%s messages cannot be forwarded", message.MediaType))
+	}
+}
+
+// mediaDirectPath derives the CDN path WhatsApp clients download from out of a
+// stored media URL: the URL's path and signed query without the host-only
+// mms3 flag. It is nil when the URL has no path.
+func mediaDirectPath(mediaURL string) *string {
+	parsed, err := url.Parse(mediaURL)
+	if err != nil || !strings.HasPrefix(parsed.EscapedPath(), "/") {
+		return nil
+	}
+	path := parsed.EscapedPath()
+	var params []string
+	for _, param := range strings.Split(parsed.RawQuery, "&") {
+		if param != "" && !strings.HasPrefix(param, "mms3=") {
+			params = append(params, param)
+		}
+	}
+	if len(params) > 0 {
+		path += "?" + strings.Join(params, "&")
+	}
+	return proto.String(path)
+}
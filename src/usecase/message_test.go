@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestCheckMessageEditable(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := checkMessageEditable(&domainChatStorage.Message{ID: "A", IsFromMe: true, Timestamp: now.Add(-5 * time.Minute)}, now); err != nil {
+		t.Errorf("recent own text message: %v", err)
+	}
+	if err := checkMessageEditable(&domainChatStorage.Message{ID: "B", IsFromMe: true, MediaType: "image", Timestamp: now}, now); err != nil {
+		t.Errorf("image caption: %v", err)
+	}
+
+	var expired pkgError.EditWindowExpiredError
+	if err := checkMessageEditable(&domainChatStorage.Message{ID: "C", IsFromMe: true, Timestamp: now.Add(-time.Hour)}, now); !errors.As(err, &expired) {
+		t.Errorf("old message error = %v, want EditWindowExpiredError", err)
+	}
+
+	var validation pkgError.ValidationError
+	if err := checkMessageEditable(&domainChatStorage.Message{ID: "D", Timestamp: now}, now); !errors.As(err, &validation) {
+		t.Errorf("message from someone else error = %v, want ValidationError", err)
+	}
+	if err := checkMessageEditable(&domainChatStorage.Message{ID: "E", IsFromMe: true, MediaType: "sticker", Timestamp: now}, now); !errors.As(err, &validation) {
+		t.Errorf("sticker error = %v, want ValidationError", err)
+	}
+}
+
+func TestForwardedMessage(t *testing.T) {
+	text, err := forwardedMessage(&domainChatStorage.Message{ID: "A", Content: "hello"})
+	if err != nil || text.GetExtendedTextMessage().GetText() != "hello" || !text.GetExtendedTextMessage().GetContextInfo().GetIsForwarded() {
+		t.Errorf("text = %v, %v", text, err)
+	}
+
+	document, err := forwardedMessage(&domainChatStorage.Message{
+		ID: "B", MediaType: "document", Filename: "invoice.pdf", Content: "March",
+		URL:      "https://mmg.whatsapp.net/v/t62.7119-24/123_456_n.enc?ccb=11-4&oh=01_abc&oe=6800&_nc_sid=5e03e0&mms3=true",
+		MediaKey: []byte("key"), FileSHA256: []byte("sha"), FileEncSHA256: []byte("enc"), FileLength: 2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := document.GetDocumentMessage()
+	if doc.GetDirectPath() != "/v/t62.7119-24/123_456_n.enc?ccb=11-4&oh=01_abc&oe=6800&_nc_sid=5e03e0" {
+		t.Errorf("direct path = %q", doc.GetDirectPath())
+	}
+	if doc.GetMimetype() != "application/pdf" || doc.GetCaption() != "March" || string(doc.GetMediaKey()) != "key" || doc.GetFileLength() != 2048 {
+		t.Errorf("document = %v", doc)
+	}
+	if !doc.GetContextInfo().GetIsForwarded() {
+		t.Error("document is not marked as forwarded")
+	}
+
+	var validation pkgError.ValidationError
+	if _, err := forwardedMessage(&domainChatStorage.Message{ID: "C", MediaType: "image"}); !errors.As(err, &validation) {
+		t.Errorf("image without stored media: err = %v, want ValidationError", err)
+	}
+	if _, err := forwardedMessage(&domainChatStorage.Message{ID: "D", MediaType: "call", Content: "Missed call"}); !errors.As(err, &validation) {
+		t.Errorf("call: err = %v, want ValidationError", err)
+	}
+}
+
+// messageLookupRepoStub answers GetMessageByID from a list, honouring the
+// device and chat filters as chat storage does.
+type messageLookupRepoStub struct {
+	domainChatStorage.IChatStorageRepository
+	messages []*domainChatStorage.Message
+}
+
+func (r *messageLookupRepoStub) GetMessageByID(id, deviceID, chatJID string) (*domainChatStorage.Message, error) {
+	for _, message := range r.messages {
+		if message.ID == id && (deviceID == "" || message.DeviceID == deviceID) && (chatJID == "" || message.ChatJID == chatJID) {
+			return message, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestReactionSenderScopedToDeviceAndChat(t *testing.T) {
+	deviceID := "628000000001@s.whatsapp.net"
+	groupA := types.NewJID("120363000000001", types.GroupServer)
+	groupB := types.NewJID("120363000000002", types.GroupServer)
+	repo := &messageLookupRepoStub{messages: []*domainChatStorage.Message{
+		{ID: "3EB0SAMEID", DeviceID: "628000000002@s.whatsapp.net", ChatJID: groupB.String(), Sender: "628333333333@s.whatsapp.net"},
+		{ID: "3EB0SAMEID", DeviceID: deviceID, ChatJID: groupA.String(), Sender: "628111111111@s.whatsapp.net"},
+		{ID: "3EB0SAMEID", DeviceID: deviceID, ChatJID: groupB.String(), Sender: "628222222222@s.whatsapp.net"},
+	}}
+	service := serviceMessage{chatStorageRepo: repo}
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance(deviceID, nil, nil))
+
+	if got := service.reactionSender(ctx, "3EB0SAMEID", groupA); got.String() != "628111111111@s.whatsapp.net" {
+		t.Errorf("sender in group A = %s, want 628111111111@s.whatsapp.net", got)
+	}
+	if got := service.reactionSender(ctx, "3EB0SAMEID", groupB); got.String() != "628222222222@s.whatsapp.net" {
+		t.Errorf("sender in group B = %s, want 628222222222@s.whatsapp.net", got)
+	}
+	if got := service.reactionSender(ctx, "3EB0SAMEID", types.NewJID("628444444444", types.DefaultUserServer)); !got.IsEmpty() {
+		t.Errorf("sender in a chat without the message = %s, want empty", got)
+	}
+}
@@ -2,18 +2,26 @@ package usecase
 
 import (
 	"context"
+	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
-type serviceNewsletter struct{}
+type serviceNewsletter struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
 
-func NewNewsletterService() domainNewsletter.INewsletterUsecase {
-	return &serviceNewsletter{}
+func NewNewsletterService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainNewsletter.INewsletterUsecase {
+	return &serviceNewsletter{
+		chatStorageRepo: chatStorageRepo,
+	}
 }
 
 func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNewsletter.UnfollowRequest) (err error) {
@@ -33,3 +41,53 @@ func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNew
 
 	return client.UnfollowNewsletter(ctx, JID)
 }
+
+// GetMessages fetches a page of a newsletter's history from WhatsApp, newest
+// first, and saves the messages to chat storage.
+func (service serviceNewsletter) GetMessages(ctx context.Context, request domainNewsletter.GetMessagesRequest) (response domainNewsletter.GetMessagesResponse, err error) {
+	if err = validations.ValidateGetNewsletterMessages(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+	utils.MustLogin(client)
+
+	JID, err := utils.ValidateAndNormalizeJID(client, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	messages, err := client.GetNewsletterMessages(ctx, JID, &whatsmeow.GetNewsletterMessagesParams{
+		Count:  request.Count,
+		Before: types.MessageServerID(request.Before),
+	})
+	if err != nil {
+		return response, err
+	}
+	whatsapp.StoreNewsletterMessages(ctx, service.chatStorageRepo, client, JID, messages)
+
+	response.NewsletterID = JID.String()
+	response.Data = make([]domainNewsletter.MessageInfo, 0, len(messages))
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		info := domainNewsletter.MessageInfo{
+			ServerID:       int(msg.MessageServerID),
+			ID:             string(msg.MessageID),
+			Type:           msg.Type,
+			Timestamp:      msg.Timestamp.Format(time.RFC3339),
+			ViewsCount:     msg.ViewsCount,
+			ReactionCounts: msg.ReactionCounts,
+		}
+		if msg.Message != nil {
+			info.Body = utils.ExtractMessageTextFromProto(msg.Message)
+			info.MediaType, _, _, _, _, _, _ = utils.ExtractMediaInfo(msg.Message)
+		}
+		response.Data = append(response.Data, info)
+	}
+	return response, nil
+}
@@ -44,21 +44,47 @@ type serviceSend struct {
 }
 
 func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainSend.ISendUsecase {
-	return &serviceSend{
-		appService:      appService,
+	return idempotentSend{
+		ISendUsecase: &serviceSend{
+			appService:      appService,
+			chatStorageRepo: chatStorageRepo,
+		},
 		chatStorageRepo: chatStorageRepo,
 	}
 }
 
-// wrapSendMessage sends the message and stores it asynchronously on success.
+// wrapSendMessage applies the outgoing content policy and send rate limits,
+// sends the message and stores it asynchronously on success.
 // The send goes through whatsapp.SendMessageWithReachoutRetry, which retries
 // once on WhatsApp error 463 after a SubscribePresence pre-warm — see
 // infrastructure/whatsapp/send_retry.go for the protocol-level rationale.
 func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	return sendAndStoreMessage(ctx, service.chatStorageRepo, client, recipient, msg, content)
+}
+
+// sendAndStoreMessage is wrapSendMessage for usecases that send without a
+// serviceSend, such as forwarding.
+func sendAndStoreMessage(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	content, err := whatsapp.ApplySendPolicy(ctx, recipient, msg, content)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	if err := whatsapp.ReserveSend(ctx, recipient); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	setAlbumAssociation(ctx, msg)
+
+	if whatsapp.SendQueueEnabled() {
+		// The queue forwards and stores the message once it is sent.
+		ts, err := whatsapp.QueueMessage(ctx, client, recipient, msg, content)
+		return ts, normalizeSendError(err)
+	}
+
 	ts, err := whatsapp.SendMessageWithReachoutRetry(ctx, client, recipient, msg)
 	if err != nil {
 		return whatsmeow.SendResponse{}, normalizeSendError(err)
 	}
+	whatsapp.ForwardSentMessageToWebhook(ctx, client, recipient, ts, msg)
 
 	// Store the sent message using chatstorage
 	senderJID := ""
@@ -72,7 +98,7 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeo
 		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
 		defer cancel()
 
-		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp, msg); err != nil {
+		if err := chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp, msg); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				logrus.Warn("Timeout storing sent message")
 			} else {
@@ -106,12 +132,33 @@ func (service serviceSend) mergeReplyContext(ctx context.Context, contextInfo *w
 	}
 	contextInfo.StanzaID = replyMessageID
 	contextInfo.Participant = proto.String(message.Sender)
-	contextInfo.QuotedMessage = &waE2E.Message{
-		Conversation: proto.String(message.Content),
-	}
+	contextInfo.QuotedMessage = quotedMessage(message)
 	return contextInfo
 }
 
+// quotedMessage rebuilds the quote of a stored message the way the
+// recipient's client shows it: media by type with its caption, anything else
+// as text.
+func quotedMessage(message *domainChatStorage.Message) *waE2E.Message {
+	caption := proto.String(message.Content)
+	switch message.MediaType {
+	case "image":
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{Caption: caption}}
+	case "video":
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{Caption: caption}}
+	case "video_note":
+		return &waE2E.Message{PtvMessage: &waE2E.VideoMessage{}}
+	case "audio":
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{}}
+	case "document":
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{FileName: proto.String(message.Filename), Caption: caption}}
+	case "sticker":
+		return &waE2E.Message{StickerMessage: &waE2E.StickerMessage{}}
+	default:
+		return &waE2E.Message{Conversation: caption}
+	}
+}
+
 func normalizeSendError(err error) error {
 	if err == nil {
 		return nil
@@ -156,27 +203,29 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
 		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	} else {
-		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.BaseRequest.Phone))
+		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(ctx, request.BaseRequest.Phone))
 	}
 
-	// Get mentions from text (existing behavior - parses @phone from message text)
-	parsedMentions := service.getMentionFromText(ctx, request.Message)
-
-	// Add explicit mentions from request.Mentions (ghost mentions - no @ required in text)
-	if len(request.Mentions) > 0 {
-		explicitMentions := service.getMentionsFromList(ctx, request.Mentions, dataWaRecipient)
-		parsedMentions = append(parsedMentions, explicitMentions...)
-		// Deduplicate to avoid mentioning the same person twice
-		parsedMentions = utils.UniqueStrings(parsedMentions)
+	// Mentions from @phone tokens of the text and from request.Mentions (ghost mentions)
+	text, mentionedJIDs, err := service.resolveMentions(ctx, client, dataWaRecipient, request.Message, request.Mentions)
+	if err != nil {
+		return response, err
 	}
+	content := applyMentions(msg.ExtendedTextMessage, text, mentionedJIDs)
+
+	msg.ExtendedTextMessage.ContextInfo = service.mergeReplyContext(ctx, msg.ExtendedTextMessage.ContextInfo, request.ReplyMessageID)
 
-	if len(parsedMentions) > 0 {
-		msg.ExtendedTextMessage.ContextInfo.MentionedJID = parsedMentions
+	if linkPreviewEnabled(request.LinkPreview) {
+		if link := utils.FirstURL(msg.ExtendedTextMessage.GetText()); link != "" {
+			service.attachLinkPreview(ctx, client, dataWaRecipient, msg.ExtendedTextMessage, link)
+		}
 	}
 
-	msg.ExtendedTextMessage.ContextInfo = service.mergeReplyContext(ctx, msg.ExtendedTextMessage.ContextInfo, request.ReplyMessageID)
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, typingDuration(request.Message)); err != nil {
+		return response, err
+	}
 
-	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, request.Message)
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
@@ -339,13 +388,17 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	if request.Caption != "" {
 		caption = request.Caption
 	}
-	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, caption)
 	go func() {
 		errDelete := utils.RemoveFile(0, deletedItems...)
 		if errDelete != nil {
 			fmt.Println("error when deleting picture: ", errDelete)
 		}
 	}()
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, typingDuration(request.Caption)); err != nil {
+		return response, err
+	}
+
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
 	}
@@ -434,6 +487,10 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	if request.Caption != "" {
 		caption = request.Caption
 	}
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, typingDuration(request.Caption)); err != nil {
+		return response, err
+	}
+
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
@@ -632,25 +689,50 @@ func getAudioDuration(audioPath string) uint32 {
 	return uint32(duration)
 }
 
-// generateWaveform generates a waveform visualization for voice notes using ffmpeg.
-// Returns a []byte with 64 amplitude samples (0-100) for WhatsApp UI visualization.
-func generateWaveform(audioPath string) []byte {
+// waveformSampleRate is the rate voice notes are decoded at to draw their waveform.
+const waveformSampleRate = 8000
+
+// voiceNoteMetadata returns the length in seconds and the waveform of a voice
+// note. When ffprobe cannot read the length it is worked out from the decoded
+// samples, so the recipient's scrubber never shows 0:00.
+func voiceNoteMetadata(audioPath string) (uint32, []byte) {
+	seconds := getAudioDuration(audioPath)
+
 	// Extract audio samples as signed 8-bit PCM
 	// -ac 1: mono, -ar 8000: 8kHz sample rate, -f s8: signed 8-bit output
-	output, err := runFFMpeg(
+	samples, err := runFFMpeg(
 		"-i", audioPath,
 		"-ac", "1",
-		"-ar", "8000",
+		"-ar", strconv.Itoa(waveformSampleRate),
 		"-f", "s8",
 		"-acodec", "pcm_s8",
 		"pipe:1",
 	)
 	if err != nil {
 		logrus.Warnf("Failed to generate waveform: %v", err)
-		return generateDefaultWaveform()
+		return seconds, generateDefaultWaveform()
+	}
+
+	if seconds == 0 && len(samples) > 0 {
+		seconds = uint32(max(1, math.Round(float64(len(samples))/waveformSampleRate)))
 	}
+	return seconds, downsampleToWaveform(samples, 64)
+}
 
-	return downsampleToWaveform(output, 64)
+// audioCodec returns the codec of the first audio stream of a file, or an
+// empty string when ffprobe is not available or cannot read it.
+func audioCodec(audioPath string) string {
+	output, err := runFFProbe(
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=codec_name",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		audioPath,
+	)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
 }
 
 // downsampleToWaveform converts raw PCM samples to a fixed number of amplitude peaks.
@@ -796,7 +878,8 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		return response, pkgError.InternalServerError(fmt.Sprintf("Failed to open generated video thumbnail image '%s': %v. Possible causes: file not found, unsupported format, or permission denied.", thumbnailVideoPath, err))
 	}
 	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
-	thumbnailResizeVideoPath := fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, generateUUID+".png")
+	// Saved as JPEG, the format of the message's JPEGThumbnail
+	thumbnailResizeVideoPath := fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, generateUUID+".jpg")
 	if err = imaging.Save(resizedImage, thumbnailResizeVideoPath); err != nil {
 		return response, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
 	}
@@ -837,7 +920,12 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		videoPath = compresVideoPath
 		deletedItems = append(deletedItems, compresVideoPath)
 	} else {
-		videoPath = oriVideoPath
+		convertedVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+"-converted.mp4")
+		deletedItems = append(deletedItems, convertedVideoPath)
+		videoPath, err = convertVideo(ctx, oriVideoPath, convertedVideoPath)
+		if err != nil {
+			return response, err
+		}
 	}
 	deletedItems = append(deletedItems, oriVideoPath)
 
@@ -891,6 +979,10 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	if request.Caption != "" {
 		caption = "🎥 " + request.Caption
 	}
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, typingDuration(request.Caption)); err != nil {
+		return response, err
+	}
+
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
@@ -917,32 +1009,58 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 		return response, err
 	}
 
-	contactName := strings.TrimSpace(request.ContactName)
-	contactPhone := utils.CleanPhoneForWhatsApp(request.ContactPhone)
-	msgVCard := fmt.Sprintf("BEGIN:VCARD\nVERSION:3.0\nN:;%v;;;\nFN:%v\nTEL;type=CELL;waid=%v:+%v\nEND:VCARD",
-		contactName, contactName, contactPhone, contactPhone)
-	msg := &waE2E.Message{ContactMessage: &waE2E.ContactMessage{
-		DisplayName: proto.String(contactName),
-		Vcard:       proto.String(msgVCard),
-	}}
+	cards := request.Contacts
+	if len(cards) == 0 {
+		cards = []domainSend.ContactCard{{Name: request.ContactName, Phones: []string{request.ContactPhone}}}
+	}
 
+	var contextInfo *waE2E.ContextInfo
 	if request.BaseRequest.IsForwarded {
-		msg.ContactMessage.ContextInfo = &waE2E.ContextInfo{
+		contextInfo = &waE2E.ContextInfo{
 			IsForwarded:     proto.Bool(true),
 			ForwardingScore: proto.Uint32(100),
 		}
 	}
 
 	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.ContactMessage.ContextInfo == nil {
-			msg.ContactMessage.ContextInfo = &waE2E.ContextInfo{}
+		if contextInfo == nil {
+			contextInfo = &waE2E.ContextInfo{}
 		}
-		msg.ContactMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+		contextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
+	contextInfo = service.mergeReplyContext(ctx, contextInfo, request.ReplyMessageID)
 
-	content := "👤 " + contactName
-	if contactPhone != "" {
-		content = fmt.Sprintf("👤 %s (+%s)", contactName, contactPhone)
+	contacts := make([]*waE2E.ContactMessage, len(cards))
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = strings.TrimSpace(card.Name)
+		contacts[i] = &waE2E.ContactMessage{
+			DisplayName: proto.String(names[i]),
+			Vcard:       proto.String(contactVCard(card)),
+		}
+	}
+
+	var msg *waE2E.Message
+	var content string
+	if len(contacts) == 1 {
+		contacts[0].ContextInfo = contextInfo
+		msg = &waE2E.Message{ContactMessage: contacts[0]}
+		content = "👤 " + names[0]
+		if phone := utils.CleanPhoneForWhatsApp(cards[0].Phones[0]); phone != "" {
+			content = fmt.Sprintf("👤 %s (+%s)", names[0], phone)
+		}
+	} else {
+		displayName := fmt.Sprintf("%d contacts", len(contacts))
+		msg = &waE2E.Message{ContactsArrayMessage: &waE2E.ContactsArrayMessage{
+			DisplayName: proto.String(displayName),
+			Contacts:    contacts,
+			ContextInfo: contextInfo,
+		}}
+		content = fmt.Sprintf("👥 %s: %s", displayName, strings.Join(names, ", "))
+	}
+
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, minTypingDuration); err != nil {
+		return response, err
 	}
 
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
@@ -955,6 +1073,28 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 	return response, nil
 }
 
+// vcardEscaper escapes the characters vCard 3.0 reserves in text values.
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// contactVCard renders card as the vCard WhatsApp clients expect: the waid
+// parameter of each phone links the card to its WhatsApp account.
+func contactVCard(card domainSend.ContactCard) string {
+	name := vcardEscaper.Replace(strings.TrimSpace(card.Name))
+	lines := []string{"BEGIN:VCARD", "VERSION:3.0", "N:;" + name + ";;;", "FN:" + name}
+	if organization := strings.TrimSpace(card.Organization); organization != "" {
+		lines = append(lines, "ORG:"+vcardEscaper.Replace(organization))
+	}
+	for _, phone := range card.Phones {
+		phone = utils.CleanPhoneForWhatsApp(phone)
+		lines = append(lines, fmt.Sprintf("TEL;type=CELL;waid=%s:+%s", phone, phone))
+	}
+	for _, email := range card.Emails {
+		lines = append(lines, "EMAIL;type=INTERNET:"+strings.TrimSpace(email))
+	}
+	lines = append(lines, "END:VCARD")
+	return strings.Join(lines, "\n")
+}
+
 func (service serviceSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (response domainSend.GenericResponse, err error) {
 	err = validations.ValidateSendLink(ctx, request)
 	if err != nil {
@@ -985,11 +1125,7 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 
 	// Create the message
 	msg := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
-		Text:          proto.String(fmt.Sprintf("%s\n%s", request.Caption, request.Link)),
-		Title:         proto.String(metadata.Title),
-		MatchedText:   proto.String(request.Link),
-		Description:   proto.String(metadata.Description),
-		JPEGThumbnail: metadata.JPEGThumb,
+		Text: proto.String(fmt.Sprintf("%s\n%s", request.Caption, request.Link)),
 	}}
 
 	if request.BaseRequest.IsForwarded {
@@ -1005,32 +1141,18 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 		}
 		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
+	msg.ExtendedTextMessage.ContextInfo = service.mergeReplyContext(ctx, msg.ExtendedTextMessage.ContextInfo, request.ReplyMessageID)
 
-	// If we have a thumbnail image, upload it to WhatsApp's servers
-	if len(metadata.ImageThumb) > 0 {
-		uploadedThumb, err := service.uploadMedia(ctx, client, whatsmeow.MediaLinkThumbnail, metadata.ImageThumb, dataWaRecipient)
-		if err == nil {
-			// Update the message with the uploaded thumbnail information
-			msg.ExtendedTextMessage.ThumbnailDirectPath = proto.String(uploadedThumb.DirectPath)
-			msg.ExtendedTextMessage.ThumbnailSHA256 = uploadedThumb.FileSHA256
-			msg.ExtendedTextMessage.ThumbnailEncSHA256 = uploadedThumb.FileEncSHA256
-			msg.ExtendedTextMessage.MediaKey = uploadedThumb.MediaKey
-			msg.ExtendedTextMessage.MediaKeyTimestamp = proto.Int64(time.Now().Unix())
-			if metadata.Height != nil {
-				msg.ExtendedTextMessage.ThumbnailHeight = metadata.Height
-			}
-			if metadata.Width != nil {
-				msg.ExtendedTextMessage.ThumbnailWidth = metadata.Width
-			}
-		} else {
-			logrus.Warnf("Failed to upload thumbnail: %v, continue without uploaded thumbnail", err)
-		}
-	}
+	service.applyLinkPreview(ctx, client, dataWaRecipient, msg.ExtendedTextMessage, request.Link, metadata)
 
 	content := "🔗 " + request.Link
 	if request.Caption != "" {
 		content = "🔗 " + request.Caption
 	}
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, typingDuration(request.Caption)); err != nil {
+		return response, err
+	}
+
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -1064,6 +1186,12 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 			DegreesLongitude: proto.Float64(utils.StrToFloat64(request.Longitude)),
 		},
 	}
+	if name := strings.TrimSpace(request.Name); name != "" {
+		msg.LocationMessage.Name = proto.String(name)
+	}
+	if address := strings.TrimSpace(request.Address); address != "" {
+		msg.LocationMessage.Address = proto.String(address)
+	}
 
 	if request.BaseRequest.IsForwarded {
 		msg.LocationMessage.ContextInfo = &waE2E.ContextInfo{
@@ -1078,8 +1206,16 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 		}
 		msg.LocationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
+	msg.LocationMessage.ContextInfo = service.mergeReplyContext(ctx, msg.LocationMessage.ContextInfo, request.ReplyMessageID)
 
 	content := "📍 " + request.Latitude + ", " + request.Longitude
+	if msg.LocationMessage.Name != nil {
+		content = "📍 " + msg.LocationMessage.GetName() + " (" + request.Latitude + ", " + request.Longitude + ")"
+	}
+
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, minTypingDuration); err != nil {
+		return response, err
+	}
 
 	// Send WhatsApp Message Proto
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
@@ -1159,26 +1295,23 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		defer os.Remove(tempAudioPath)
 	}
 
-	// For PTT (voice notes), WhatsApp requires "audio/ogg; codecs=opus"
-	// Check if it's an OGG file and add codec info for PTT
-	if request.PTT && strings.HasPrefix(audioMimeType, "audio/ogg") {
-		audioMimeType = "audio/ogg; codecs=opus"
-	}
-
-	// Generate waveform for PTT voice notes
+	// Voice notes are only shown with a scrubber when they are OGG Opus and
+	// carry their length and waveform, so anything else is converted first.
+	voiceNote := request.PTT || request.VoiceNote
 	var waveformData []byte
-	if request.PTT && tempAudioPath != "" {
-		waveformData = generateWaveform(tempAudioPath)
-	}
-
-	// If PTT is requested, convert audio to OGG Opus format for WhatsApp voice note compatibility
-	// WhatsApp clients require OGG Opus format for voice notes to play correctly
-	if request.PTT {
-		// Check if already OGG format - skip conversion
+	if voiceNote {
 		isAlreadyOgg := strings.HasPrefix(audioMimeType, "audio/ogg") ||
 			strings.HasPrefix(audioMimeType, "application/ogg")
+		// An OGG whose codec cannot be read is trusted to be Opus.
+		isOpus := isAlreadyOgg
+		if isAlreadyOgg && tempAudioPath != "" {
+			if codec := audioCodec(tempAudioPath); codec != "" {
+				isOpus = codec == "opus"
+			}
+		}
 
-		if !isAlreadyOgg {
+		metadataPath := tempAudioPath
+		if !isOpus {
 			// Check if ffmpeg is installed
 			_, err := exec.LookPath("ffmpeg")
 			if err != nil {
@@ -1212,11 +1345,13 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 			// -application voip: Optimize for voice
 			// -ar 48000: Sample rate (Opus requires 48kHz)
 			// -ac 1: Mono (WhatsApp voice notes are mono)
+			// -vn: Drop cover art, which some players reject in voice notes
 			convCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
 			defer cancel()
 
 			cmdConvert := exec.CommandContext(convCtx, "ffmpeg",
 				"-i", inputPath,
+				"-vn",
 				"-c:a", "libopus",
 				"-b:a", "64k",
 				"-vbr", "on",
@@ -1240,14 +1375,17 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 			if err != nil {
 				return response, pkgError.InternalServerError(fmt.Sprintf("failed to read converted audio: %v", err))
 			}
-
-			// Update MIME type to OGG Opus
-			audioMimeType = "audio/ogg; codecs=opus"
+			metadataPath = outputPath
 
 			logrus.Infof("Converted audio to OGG Opus for PTT: %d bytes", len(audioBytes))
+		}
+		audioMimeType = "audio/ogg; codecs=opus"
+
+		// Length and waveform are read from the file that is actually sent.
+		if metadataPath != "" {
+			audioDuration, waveformData = voiceNoteMetadata(metadataPath)
 		} else {
-			// Already OGG format, ensure MIME type is correctly set
-			audioMimeType = "audio/ogg; codecs=opus"
+			waveformData = generateDefaultWaveform()
 		}
 	}
 
@@ -1267,7 +1405,7 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 			FileSHA256:    audioUploaded.FileSHA256,
 			FileEncSHA256: audioUploaded.FileEncSHA256,
 			MediaKey:      audioUploaded.MediaKey,
-			PTT:           proto.Bool(request.PTT),
+			PTT:           proto.Bool(voiceNote),
 			Seconds:       proto.Uint32(audioDuration),
 			Waveform:      waveformData,
 		},
@@ -1289,6 +1427,14 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 	msg.AudioMessage.ContextInfo = service.mergeReplyContext(ctx, msg.AudioMessage.ContextInfo, request.ReplyMessageID)
 
 	content := "🎵 Audio"
+	typingMedia, typingTime := types.ChatPresenceMediaText, minTypingDuration
+	if voiceNote {
+		content = "🎤 Voice note"
+		typingMedia, typingTime = types.ChatPresenceMediaAudio, time.Duration(audioDuration)*time.Second
+	}
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, typingMedia, typingTime); err != nil {
+		return response, err
+	}
 
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
 	if err != nil {
@@ -1326,6 +1472,11 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		}
 		msg.PollCreationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
+	msg.PollCreationMessage.ContextInfo = service.mergeReplyContext(ctx, msg.PollCreationMessage.ContextInfo, request.ReplyMessageID)
+
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, typingDuration(request.Question+strings.Join(request.Options, ""))); err != nil {
+		return response, err
+	}
 
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
 	if err != nil {
@@ -1401,52 +1552,6 @@ func (service serviceSend) SendChatPresence(ctx context.Context, request domainS
 	return response, nil
 }
 
-func (service serviceSend) getMentionFromText(ctx context.Context, messages string) (result []string) {
-	client := whatsapp.ClientFromContext(ctx)
-	if client == nil {
-		return result
-	}
-
-	mentions := utils.ContainsMention(messages)
-	for _, mention := range mentions {
-		// Get JID from phone number
-		if dataWaRecipient, err := utils.ValidateAndNormalizeJID(client, mention); err == nil {
-			result = append(result, dataWaRecipient.String())
-		}
-	}
-	return result
-}
-
-// getMentionsFromList converts a list of phone numbers to JIDs for ghost mentions
-// Special keyword "@everyone" will fetch all group participants
-func (service serviceSend) getMentionsFromList(ctx context.Context, mentions []string, recipientJID types.JID) (result []string) {
-	client := whatsapp.ClientFromContext(ctx)
-	if client == nil {
-		return result
-	}
-
-	for _, mention := range mentions {
-		// Handle @everyone keyword - fetch all group participants
-		if mention == "@everyone" {
-			if recipientJID.Server == types.GroupServer {
-				groupInfo, err := client.GetGroupInfo(ctx, recipientJID)
-				if err == nil && groupInfo != nil {
-					for _, participant := range groupInfo.Participants {
-						result = append(result, participant.JID.String())
-					}
-				}
-			}
-			continue
-		}
-
-		// Validate phone number/JID with WhatsApp check
-		if dataWaRecipient, err := utils.ValidateAndNormalizeJID(client, mention); err == nil {
-			result = append(result, dataWaRecipient.String())
-		}
-	}
-	return result
-}
-
 func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
 	// Validate request
 	err = validations.ValidateSendSticker(ctx, request)
@@ -1588,9 +1693,14 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 			}
 			msg.StickerMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 		}
+		msg.StickerMessage.ContextInfo = service.mergeReplyContext(ctx, msg.StickerMessage.ContextInfo, request.ReplyMessageID)
 
 		content := "🎨 Animated Sticker"
 
+		if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, minTypingDuration); err != nil {
+			return response, err
+		}
+
 		// Send the animated sticker message
 		ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
 		if err != nil {
@@ -1752,9 +1862,14 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 		}
 		msg.StickerMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
+	msg.StickerMessage.ContextInfo = service.mergeReplyContext(ctx, msg.StickerMessage.ContextInfo, request.ReplyMessageID)
 
 	content := "🎨 Sticker"
 
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, minTypingDuration); err != nil {
+		return response, err
+	}
+
 	// Send the sticker message
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
 	if err != nil {
@@ -1811,13 +1926,13 @@ func getWebPInfo(ctx context.Context, filePath string) (isAnimated bool, width i
 	return isAnimated, width, height
 }
 
-func (service serviceSend) getDefaultEphemeralExpiration(jid string) (expiration uint32) {
+func (service serviceSend) getDefaultEphemeralExpiration(ctx context.Context, jid string) (expiration uint32) {
 	expiration = 0
 	if jid == "" {
 		return expiration
 	}
 
-	chat, err := service.chatStorageRepo.GetChat(jid)
+	chat, err := service.chatStorageRepo.GetChatByDevice(deviceIDFromContext(ctx), jid)
 	if err != nil {
 		return expiration
 	}
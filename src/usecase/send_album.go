@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+type albumParentKey struct{}
+
+// withAlbumParent marks the messages sent with ctx as items of the album
+// message identified by parent.
+func withAlbumParent(ctx context.Context, parent *waCommon.MessageKey) context.Context {
+	return context.WithValue(ctx, albumParentKey{}, parent)
+}
+
+// setAlbumAssociation links msg to the album of ctx, if any, so recipients
+// stack it into that album instead of showing it on its own.
+func setAlbumAssociation(ctx context.Context, msg *waE2E.Message) {
+	parent, ok := ctx.Value(albumParentKey{}).(*waCommon.MessageKey)
+	if !ok || parent == nil {
+		return
+	}
+	if msg.MessageContextInfo == nil {
+		msg.MessageContextInfo = &waE2E.MessageContextInfo{}
+	}
+	msg.MessageContextInfo.MessageAssociation = &waE2E.MessageAssociation{
+		AssociationType:  waE2E.MessageAssociation_MEDIA_ALBUM.Enum(),
+		ParentMessageKey: parent,
+	}
+}
+
+// SendAlbum sends an album message announcing how many images and videos
+// follow, then each item through SendImage or SendVideo linked to it.
+// Items are sent in order; an item failing stops the album there.
+func (service serviceSend) SendAlbum(ctx context.Context, request domainSend.AlbumRequest) (response domainSend.AlbumResponse, err error) {
+	if err = validations.ValidateSendAlbum(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	dataWaRecipient, err := utils.ValidateAndNormalizeJID(client, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	var images, videos uint32
+	for _, item := range request.Items {
+		if item.Type == domainSend.AlbumItemVideo {
+			videos++
+		} else {
+			images++
+		}
+	}
+
+	album := &waE2E.AlbumMessage{
+		ExpectedImageCount: proto.Uint32(images),
+		ExpectedVideoCount: proto.Uint32(videos),
+	}
+	if request.IsForwarded {
+		album.ContextInfo = &waE2E.ContextInfo{
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(100),
+		}
+	}
+	if request.Duration != nil && *request.Duration > 0 {
+		if album.ContextInfo == nil {
+			album.ContextInfo = &waE2E.ContextInfo{}
+		}
+		album.ContextInfo.Expiration = proto.Uint32(uint32(*request.Duration))
+	}
+	album.ContextInfo = service.mergeReplyContext(ctx, album.ContextInfo, request.ReplyMessageID)
+
+	// Reserve the album message and every item up front, so the send limits
+	// reject the album as a whole rather than stopping it halfway.
+	ctx, err = whatsapp.ReserveSends(ctx, dataWaRecipient, 1+len(request.Items))
+	if err != nil {
+		return response, err
+	}
+
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, minTypingDuration); err != nil {
+		return response, err
+	}
+
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, &waE2E.Message{AlbumMessage: album},
+		fmt.Sprintf("🖼️ Album (%d items)", len(request.Items)))
+	if err != nil {
+		return response, err
+	}
+	response.MessageID = ts.ID
+
+	itemCtx := withAlbumParent(ctx, &waCommon.MessageKey{
+		RemoteJID: proto.String(dataWaRecipient.String()),
+		FromMe:    proto.Bool(true),
+		ID:        proto.String(ts.ID),
+	})
+	for i, item := range request.Items {
+		caption := item.Caption
+		base := request.BaseRequest
+		if i == 0 && caption == "" {
+			caption = request.Caption
+		}
+		if i > 0 {
+			// Like the caption, the quote is shown on the first item only.
+			base.ReplyMessageID = nil
+		}
+
+		var sent domainSend.GenericResponse
+		if item.Type == domainSend.AlbumItemVideo {
+			sent, err = service.SendVideo(itemCtx, domainSend.VideoRequest{
+				BaseRequest: base,
+				Caption:     caption,
+				Video:       item.File,
+				VideoURL:    item.URL,
+				Compress:    request.Compress,
+			})
+		} else {
+			sent, err = service.SendImage(itemCtx, domainSend.ImageRequest{
+				BaseRequest: base,
+				Caption:     caption,
+				Image:       item.File,
+				ImageURL:    item.URL,
+				Compress:    request.Compress,
+			})
+		}
+		if err != nil {
+			logrus.Warnf("Album %s stopped at item %d of %d: %v", ts.ID, i+1, len(request.Items), err)
+			return response, fmt.Errorf("album item %d: %w", i, err)
+		}
+		response.MessageIDs = append(response.MessageIDs, sent.MessageID)
+	}
+
+	response.Status = fmt.Sprintf("Album of %d items sent to %s (server timestamp: %s)", len(request.Items), request.Phone, ts.Timestamp.String())
+	return response, nil
+}
@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainJob "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/job"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+// campaignJobDir is the folder under config.PathStorages that campaign reports are written to.
+const campaignJobDir = "campaigns"
+
+// campaigns holds the recipient statuses of the campaigns running in this
+// process, keyed by job ID. Finished campaigns are read from their report.
+var campaigns = struct {
+	sync.Mutex
+	running map[string]*campaignRun
+}{running: make(map[string]*campaignRun)}
+
+type campaignRun struct {
+	mu         sync.Mutex
+	id         string
+	reportPath string
+	recipients []domainSend.CampaignRecipientStatus
+}
+
+// StartCampaign sends one templated message, the request's text or a stored
+// template, to every recipient as a background job. Recipients are sent in order, no faster than the requested
+// rate, and each outcome is recorded in a JSON report and forwarded to the
+// webhooks as it happens.
+func (service serviceSend) StartCampaign(ctx context.Context, request domainSend.CampaignRequest) (response domainJob.JobInfo, err error) {
+	if request.RecipientsCSV != nil {
+		file, err := request.RecipientsCSV.Open()
+		if err != nil {
+			return response, err
+		}
+		recipients, err := parseCampaignCSV(file)
+		file.Close()
+		if err != nil {
+			return response, err
+		}
+		request.Recipients = append(request.Recipients, recipients...)
+	}
+	for i := range request.Recipients {
+		request.Recipients[i].Phone = strings.TrimSpace(request.Recipients[i].Phone)
+	}
+	if err = validations.ValidateSendCampaign(ctx, &request); err != nil {
+		return response, err
+	}
+	// A plain message is sent like a template without header.
+	template := &domainChatStorage.MessageTemplate{Body: request.Message}
+	if request.Template != "" {
+		if template, err = service.loadTemplate(ctx, request.Template); err != nil {
+			return response, err
+		}
+	}
+
+	// Every message is rendered up front so a template error fails its
+	// recipient right away instead of mid-campaign.
+	messages := make([]string, len(request.Recipients))
+	renderErrs := make([]error, len(request.Recipients))
+	for i, recipient := range request.Recipients {
+		messages[i], renderErrs[i] = renderCampaignMessage(template.Body, recipient)
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	run := &campaignRun{recipients: make([]domainSend.CampaignRecipientStatus, len(request.Recipients))}
+	for i, recipient := range request.Recipients {
+		run.recipients[i] = domainSend.CampaignRecipientStatus{Phone: recipient.Phone, Status: domainSend.CampaignRecipientPending}
+	}
+
+	// The job ID is only known once the job is created, so the campaign waits
+	// for it before sending anything.
+	registered := make(chan struct{})
+	job, _, err := jobs.start(ctx, service.chatStorageRepo, domainJob.TypeCampaign, deviceID, false,
+		func(ctx context.Context, report jobReporter) (jobOutput, error) {
+			<-registered
+			defer func() {
+				campaigns.Lock()
+				delete(campaigns.running, run.id)
+				campaigns.Unlock()
+			}()
+
+			interval := time.Minute / time.Duration(request.RatePerMinute)
+			jitter := time.Duration(request.JitterSeconds) * time.Second
+			total := len(request.Recipients)
+			report("sending", 0, total)
+			if err := run.writeReport(); err != nil {
+				return jobOutput{}, err
+			}
+
+			for i, recipient := range request.Recipients {
+				if i > 0 && renderErrs[i] == nil {
+					delay := interval
+					if jitter > 0 {
+						delay += rand.N(jitter)
+					}
+					select {
+					case <-ctx.Done():
+						run.finish(ctx)
+						return jobOutput{}, ctx.Err()
+					case <-time.After(delay):
+					}
+				}
+				if err := ctx.Err(); err != nil {
+					run.finish(ctx)
+					return jobOutput{}, err
+				}
+				if renderErrs[i] == nil {
+					held := false
+					if err := waitForCampaignWindow(ctx, func() { held = true; report("held", i, total) }); err != nil {
+						run.finish(ctx)
+						return jobOutput{}, err
+					}
+					if held {
+						report("sending", i, total)
+					}
+				}
+
+				status := domainSend.CampaignRecipientStatus{Phone: recipient.Phone, Status: domainSend.CampaignRecipientFailed}
+				if renderErrs[i] != nil {
+					status.Error = renderErrs[i].Error()
+				} else {
+					phone := recipient.Phone
+					utils.SanitizePhone(&phone)
+					sent, err := service.sendTemplateMessage(ctx, template, domainSend.BaseRequest{Phone: phone}, messages[i])
+					if err != nil {
+						status.Error = err.Error()
+					} else {
+						status.Status = domainSend.CampaignRecipientSent
+						status.MessageID = sent.MessageID
+						status.SentAt = time.Now().Format(time.RFC3339)
+					}
+				}
+				run.record(ctx, i, status)
+				report("sending", i+1, total)
+			}
+
+			run.finish(ctx)
+			return jobOutput{Result: "campaign-" + run.id + ".json", ResultPath: run.reportPath}, nil
+		})
+	if err != nil {
+		return response, err
+	}
+
+	run.id = job.ID
+	run.reportPath = filepath.Join(config.PathStorages, campaignJobDir, job.ID+".json")
+	campaigns.Lock()
+	campaigns.running[job.ID] = run
+	campaigns.Unlock()
+	close(registered)
+
+	logrus.WithFields(logrus.Fields{
+		"job_id":          job.ID,
+		"recipients":      len(request.Recipients),
+		"rate_per_minute": request.RatePerMinute,
+	}).Info("Campaign started")
+	return toJobInfo(&job), nil
+}
+
+// waitForCampaignWindow holds a campaign until quiet hours are over, calling
+// held first when they are active. It only fails when ctx ends while waiting;
+// a quiet hours configuration error is logged and the send goes ahead.
+func waitForCampaignWindow(ctx context.Context, held func()) error {
+	if window, _ := whatsapp.ConfiguredQuietHours(); window != nil && window.Contains(time.Now()) {
+		held()
+	}
+	if err := whatsapp.WaitForSendWindow(ctx); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		logrus.Warnf("Campaign: quiet hours not applied: %v", err)
+	}
+	return nil
+}
+
+// GetCampaign returns the job of a campaign with the status of every recipient.
+func (service serviceSend) GetCampaign(ctx context.Context, jobID string) (response domainSend.CampaignStatusResponse, err error) {
+	job, err := loadDeviceJob(ctx, service.chatStorageRepo, jobID)
+	if err != nil {
+		return response, err
+	}
+	if job.Type != domainJob.TypeCampaign {
+		return response, pkgError.JobNotFoundError(fmt.Sprintf("campaign %s not found", jobID))
+	}
+	response.Job = toJobInfo(job)
+
+	campaigns.Lock()
+	run, ok := campaigns.running[jobID]
+	campaigns.Unlock()
+	if ok {
+		run.mu.Lock()
+		response.Recipients = append([]domainSend.CampaignRecipientStatus(nil), run.recipients...)
+		run.mu.Unlock()
+	} else {
+		data, err := os.ReadFile(filepath.Join(config.PathStorages, campaignJobDir, jobID+".json"))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return response, pkgError.JobNotFoundError(fmt.Sprintf("report of campaign %s is no longer available", jobID))
+			}
+			return response, err
+		}
+		if err := json.Unmarshal(data, &response.Recipients); err != nil {
+			return response, err
+		}
+	}
+
+	response.Sent, response.Failed, response.Pending = countCampaignRecipients(response.Recipients)
+	return response, nil
+}
+
+// record stores the outcome of recipient i, rewrites the report and forwards
+// the outcome to the webhooks.
+func (run *campaignRun) record(ctx context.Context, i int, status domainSend.CampaignRecipientStatus) {
+	run.mu.Lock()
+	run.recipients[i] = status
+	sent, failed, pending := countCampaignRecipients(run.recipients)
+	run.mu.Unlock()
+
+	if err := run.writeReport(); err != nil {
+		logrus.WithError(err).WithField("job_id", run.id).Warn("Failed to write campaign report")
+	}
+	whatsapp.ForwardCampaignEvent(ctx, run.id, whatsapp.EventTypeCampaignRecipient, map[string]any{
+		"phone":      status.Phone,
+		"status":     status.Status,
+		"message_id": status.MessageID,
+		"error":      status.Error,
+		"sent":       sent,
+		"failed":     failed,
+		"pending":    pending,
+	})
+}
+
+// finish forwards the totals of the campaign once it completes or is cancelled.
+func (run *campaignRun) finish(ctx context.Context) {
+	status := "completed"
+	if ctx.Err() != nil {
+		status = "cancelled"
+	}
+	run.mu.Lock()
+	sent, failed, pending := countCampaignRecipients(run.recipients)
+	total := len(run.recipients)
+	run.mu.Unlock()
+
+	whatsapp.ForwardCampaignEvent(ctx, run.id, whatsapp.EventTypeCampaignCompleted, map[string]any{
+		"status":  status,
+		"total":   total,
+		"sent":    sent,
+		"failed":  failed,
+		"pending": pending,
+	})
+}
+
+// writeReport replaces the report with the current recipient statuses.
+func (run *campaignRun) writeReport() error {
+	run.mu.Lock()
+	data, err := json.MarshalIndent(run.recipients, "", "  ")
+	run.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(run.reportPath), 0o755); err != nil {
+		return err
+	}
+	tmp := run.reportPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, run.reportPath)
+}
+
+func countCampaignRecipients(recipients []domainSend.CampaignRecipientStatus) (sent, failed, pending int) {
+	for _, recipient := range recipients {
+		switch recipient.Status {
+		case domainSend.CampaignRecipientSent:
+			sent++
+		case domainSend.CampaignRecipientFailed:
+			failed++
+		default:
+			pending++
+		}
+	}
+	return sent, failed, pending
+}
+
+// renderCampaignMessage fills the {{name}} placeholders of message with the
+// recipient's variables; {{phone}} defaults to the recipient's phone.
+func renderCampaignMessage(message string, recipient domainSend.CampaignRecipient) (string, error) {
+	return renderTemplate(message, recipient.Variables, recipient.Phone)
+}
+
+// parseCampaignCSV reads recipients from a CSV with a header row. The "phone"
+// column is required; every other column becomes a variable named after its
+// header.
+func parseCampaignCSV(r io.Reader) ([]domainSend.CampaignRecipient, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, pkgError.ValidationError("recipients_csv: file is empty")
+		}
+		return nil, pkgError.ValidationError(fmt.Sprintf("recipients_csv: %v", err))
+	}
+	phoneColumn := -1
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if strings.EqualFold(header[i], "phone") {
+			phoneColumn = i
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, pkgError.ValidationError("recipients_csv: a phone column is required")
+	}
+
+	var recipients []domainSend.CampaignRecipient
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, pkgError.ValidationError(fmt.Sprintf("recipients_csv: %v", err))
+		}
+		recipient := domainSend.CampaignRecipient{Phone: row[phoneColumn]}
+		for i, value := range row {
+			if i == phoneColumn || header[i] == "" {
+				continue
+			}
+			if recipient.Variables == nil {
+				recipient.Variables = make(map[string]string)
+			}
+			recipient.Variables[header[i]] = strings.TrimSpace(value)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+)
+
+func TestRenderCampaignMessage(t *testing.T) {
+	recipient := domainSend.CampaignRecipient{Phone: "6281234567890", Variables: map[string]string{"name": "Budi"}}
+
+	got, err := renderCampaignMessage("Hi {{ name }}, your number is {{phone}}.", recipient)
+	if err != nil || got != "Hi Budi, your number is 6281234567890." {
+		t.Errorf("render = %q, %v", got, err)
+	}
+
+	if _, err := renderCampaignMessage("Your code is {{code}}", recipient); err == nil || !strings.Contains(err.Error(), "code") {
+		t.Errorf("missing variable error = %v", err)
+	}
+}
+
+func TestParseCampaignCSV(t *testing.T) {
+	recipients, err := parseCampaignCSV(strings.NewReader("name,Phone\nBudi,6281234567890\nSiti, 6289876543210\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipients) != 2 {
+		t.Fatalf("recipients = %+v", recipients)
+	}
+	if recipients[1].Phone != "6289876543210" || recipients[1].Variables["name"] != "Siti" {
+		t.Errorf("second recipient = %+v", recipients[1])
+	}
+
+	if _, err := parseCampaignCSV(strings.NewReader("name,number\nBudi,628\n")); err == nil {
+		t.Error("expected an error without a phone column")
+	}
+}
+
+func TestWaitForCampaignWindowHoldsDuringQuietHours(t *testing.T) {
+	prevHours, prevTimezone := config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone
+	t.Cleanup(func() { config.WhatsappQuietHours, config.WhatsappQuietHoursTimezone = prevHours, prevTimezone })
+	now := time.Now().UTC()
+	config.WhatsappQuietHours = now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")
+	config.WhatsappQuietHoursTimezone = "UTC"
+
+	held := false
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := waitForCampaignWindow(ctx, func() { held = true }); !errors.Is(err, context.DeadlineExceeded) || !held {
+		t.Errorf("inside quiet hours: err = %v, held = %v", err, held)
+	}
+
+	config.WhatsappQuietHours = ""
+	held = false
+	if err := waitForCampaignWindow(context.Background(), func() { held = true }); err != nil || held {
+		t.Errorf("without quiet hours: err = %v, held = %v", err, held)
+	}
+}
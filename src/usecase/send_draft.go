@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+// draftSends holds the drafts being sent, keyed by device and chat, so a
+// draft is not sent twice by concurrent requests.
+var draftSends = struct {
+	sync.Mutex
+	sending map[string]bool
+}{sending: make(map[string]bool)}
+
+func (service serviceSend) SaveDraft(ctx context.Context, request domainSend.DraftRequest) (response domainSend.Draft, err error) {
+	if err = validations.ValidateSaveDraft(ctx, request); err != nil {
+		return response, err
+	}
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	draft := &domainChatStorage.Draft{
+		DeviceID:       deviceID,
+		ChatJID:        request.ChatJID,
+		Content:        request.Content,
+		ReplyMessageID: request.ReplyMessageID,
+	}
+	if err = service.chatStorageRepo.StoreDraft(draft); err != nil {
+		return response, fmt.Errorf("failed to save draft: %w", err)
+	}
+	// StoreDraft only sets CreatedAt for new drafts.
+	stored, err := service.chatStorageRepo.GetDraft(deviceID, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+	if stored != nil {
+		draft = stored
+	}
+	return toDraft(draft), nil
+}
+
+func (service serviceSend) GetDraft(ctx context.Context, chatJID string) (response domainSend.Draft, err error) {
+	draft, err := service.loadDraft(ctx, chatJID)
+	if err != nil {
+		return response, err
+	}
+	return toDraft(draft), nil
+}
+
+func (service serviceSend) ListDrafts(ctx context.Context) (response domainSend.ListDraftsResponse, err error) {
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+	drafts, err := service.chatStorageRepo.GetDrafts(deviceID)
+	if err != nil {
+		return response, fmt.Errorf("failed to list drafts: %w", err)
+	}
+	response.Data = make([]domainSend.Draft, 0, len(drafts))
+	for _, draft := range drafts {
+		response.Data = append(response.Data, toDraft(draft))
+	}
+	return response, nil
+}
+
+func (service serviceSend) DeleteDraft(ctx context.Context, chatJID string) error {
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return fmt.Errorf("device identification required")
+	}
+	deleted, err := service.chatStorageRepo.DeleteDraft(deviceID, chatJID)
+	if err != nil {
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+	if !deleted {
+		return pkgError.DraftNotFoundError(fmt.Sprintf("chat %s has no draft", chatJID))
+	}
+	return nil
+}
+
+// SendDraft sends the draft of a chat as a text message, replying to the
+// draft's reply message if it has one, and deletes the draft once WhatsApp
+// accepted the message. A draft edited while it was being sent is kept.
+func (service serviceSend) SendDraft(ctx context.Context, request domainSend.SendDraftRequest) (response domainSend.GenericResponse, err error) {
+	draft, err := service.loadDraft(ctx, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	key := draft.DeviceID + "|" + draft.ChatJID
+	draftSends.Lock()
+	if draftSends.sending[key] {
+		draftSends.Unlock()
+		return response, pkgError.ValidationError(fmt.Sprintf("the draft of %s is already being sent", draft.ChatJID))
+	}
+	draftSends.sending[key] = true
+	draftSends.Unlock()
+	defer func() {
+		draftSends.Lock()
+		delete(draftSends.sending, key)
+		draftSends.Unlock()
+	}()
+
+	message := domainSend.MessageRequest{BaseRequest: request.BaseRequest, Message: draft.Content}
+	if draft.ReplyMessageID != "" {
+		message.ReplyMessageID = &draft.ReplyMessageID
+	}
+	response, err = service.SendText(ctx, message)
+	if err != nil {
+		return response, err
+	}
+
+	current, err := service.chatStorageRepo.GetDraft(draft.DeviceID, draft.ChatJID)
+	if err == nil && current != nil && current.UpdatedAt.Equal(draft.UpdatedAt) {
+		_, err = service.chatStorageRepo.DeleteDraft(draft.DeviceID, draft.ChatJID)
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", draft.ChatJID).Warn("Draft was sent but could not be cleared")
+	}
+	return response, nil
+}
+
+// loadDraft returns the device's draft of a chat or a DraftNotFoundError.
+func (service serviceSend) loadDraft(ctx context.Context, chatJID string) (*domainChatStorage.Draft, error) {
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return nil, fmt.Errorf("device identification required")
+	}
+	if chatJID == "" {
+		return nil, pkgError.ValidationError("chat_jid: cannot be blank.")
+	}
+	draft, err := service.chatStorageRepo.GetDraft(deviceID, chatJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	if draft == nil {
+		return nil, pkgError.DraftNotFoundError(fmt.Sprintf("chat %s has no draft", chatJID))
+	}
+	return draft, nil
+}
+
+func toDraft(draft *domainChatStorage.Draft) domainSend.Draft {
+	return domainSend.Draft{
+		ChatJID:        draft.ChatJID,
+		Content:        draft.Content,
+		ReplyMessageID: draft.ReplyMessageID,
+		CreatedAt:      draft.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      draft.UpdatedAt.Format(time.RFC3339),
+	}
+}
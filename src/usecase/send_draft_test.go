@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+type draftRepo struct {
+	domainChatStorage.IChatStorageRepository
+	draft   *domainChatStorage.Draft
+	deleted bool
+}
+
+func (r *draftRepo) GetDraft(deviceID, chatJID string) (*domainChatStorage.Draft, error) {
+	if r.draft == nil || r.draft.DeviceID != deviceID || r.draft.ChatJID != chatJID {
+		return nil, nil
+	}
+	return r.draft, nil
+}
+
+func (r *draftRepo) DeleteDraft(deviceID, chatJID string) (bool, error) {
+	r.deleted = true
+	return true, nil
+}
+
+func TestSendDraftKeepsDraftWhenSendFails(t *testing.T) {
+	chatJID := "628111@s.whatsapp.net"
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+
+	repo := &draftRepo{}
+	service := serviceSend{chatStorageRepo: repo}
+	var notFound pkgError.DraftNotFoundError
+	if _, err := service.SendDraft(ctx, domainSend.SendDraftRequest{BaseRequest: domainSend.BaseRequest{Phone: chatJID}}); !errors.As(err, &notFound) {
+		t.Fatalf("without a draft: err = %v, want DraftNotFoundError", err)
+	}
+
+	repo.draft = &domainChatStorage.Draft{DeviceID: "device-a", ChatJID: chatJID, Content: "See you"}
+	// The device has no client, so sending fails.
+	if _, err := service.SendDraft(ctx, domainSend.SendDraftRequest{BaseRequest: domainSend.BaseRequest{Phone: chatJID}}); err == nil {
+		t.Fatal("expected the send to fail")
+	}
+	if repo.deleted {
+		t.Error("draft was deleted although it was not sent")
+	}
+}
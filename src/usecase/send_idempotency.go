@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// idempotencyKeyTTL is how long the result of a send is returned to
+	// retries with the same key; afterwards the key sends again.
+	idempotencyKeyTTL = 24 * time.Hour
+	// maxIdempotencyKeyLength is the longest idempotency key accepted.
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotentSends holds the idempotency keys of the sends in progress, keyed
+// by device and key, so a retry racing the original send is rejected instead
+// of sending twice.
+var idempotentSends = struct {
+	sync.Mutex
+	sending map[string]bool
+}{sending: make(map[string]bool)}
+
+// idempotentSend answers sends retried with the idempotency key
+// (BaseRequest.DedupID) of an earlier successful send with that send's
+// response, instead of sending the message again. Sends without a key, and
+// everything else, go straight to the wrapped usecase.
+type idempotentSend struct {
+	domainSend.ISendUsecase
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func (s idempotentSend) SendText(ctx context.Context, request domainSend.MessageRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/message", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendText(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/image", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendImage(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendFile(ctx context.Context, request domainSend.FileRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/file", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendFile(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/video", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendVideo(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendAlbum(ctx context.Context, request domainSend.AlbumRequest) (domainSend.AlbumResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/album", request.BaseRequest, func() (domainSend.AlbumResponse, error) {
+		return s.ISendUsecase.SendAlbum(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/audio", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendAudio(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/sticker", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendSticker(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/contact", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendContact(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/link", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendLink(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/location", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendLocation(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendLiveLocation(ctx context.Context, request domainSend.LiveLocationRequest) (domainSend.LiveLocationResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/live-location", request.BaseRequest, func() (domainSend.LiveLocationResponse, error) {
+		return s.ISendUsecase.SendLiveLocation(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/poll", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendPoll(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendProduct(ctx context.Context, request domainSend.ProductRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/product", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendProduct(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendTemplate(ctx context.Context, request domainSend.SendTemplateRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/template", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendTemplate(ctx, request)
+	})
+}
+
+func (s idempotentSend) SendDraft(ctx context.Context, request domainSend.SendDraftRequest) (domainSend.GenericResponse, error) {
+	return idempotent(ctx, s.chatStorageRepo, "send/draft", request.BaseRequest, func() (domainSend.GenericResponse, error) {
+		return s.ISendUsecase.SendDraft(ctx, request)
+	})
+}
+
+// idempotent runs send once per idempotency key and device. A retry with the
+// key of a successful send gets its recorded response back; a failed send
+// records nothing, so it can be retried with the same key.
+func idempotent[T any](ctx context.Context, repo domainChatStorage.IChatStorageRepository, operation string, base domainSend.BaseRequest, send func() (T, error)) (response T, err error) {
+	key := strings.TrimSpace(base.DedupID)
+	if key == "" {
+		return send()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return response, pkgError.ValidationError(fmt.Sprintf("dedup_id: the length must be no more than %d.", maxIdempotencyKeyLength))
+	}
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	sendingKey := deviceID + "|" + key
+	idempotentSends.Lock()
+	if idempotentSends.sending[sendingKey] {
+		idempotentSends.Unlock()
+		return response, pkgError.IdempotencyKeyInUseError(fmt.Sprintf("a send with idempotency key %s is still in progress", key))
+	}
+	idempotentSends.sending[sendingKey] = true
+	idempotentSends.Unlock()
+	defer func() {
+		idempotentSends.Lock()
+		delete(idempotentSends.sending, sendingKey)
+		idempotentSends.Unlock()
+	}()
+
+	now := time.Now()
+	record, err := repo.GetIdempotencyKey(deviceID, key, now.Add(-idempotencyKeyTTL))
+	if err != nil {
+		return response, err
+	}
+	if record != nil {
+		if record.Operation != operation {
+			return response, pkgError.ValidationError(fmt.Sprintf("idempotency key %s was already used for %s", key, record.Operation))
+		}
+		if err := json.Unmarshal([]byte(record.Response), &response); err != nil {
+			return response, err
+		}
+		logrus.Debugf("Idempotency key %s already sent message %s, returning its result", key, record.MessageID)
+		return response, nil
+	}
+
+	response, err = send()
+	if err != nil {
+		return response, err
+	}
+
+	// The message is sent at this point, so failing to record it is only logged.
+	data, err := json.Marshal(response)
+	if err != nil {
+		logrus.Warnf("Failed to record idempotency key %s: %v", key, err)
+		return response, nil
+	}
+	var sent struct {
+		MessageID string `json:"message_id"`
+	}
+	_ = json.Unmarshal(data, &sent)
+
+	if _, err := repo.DeleteIdempotencyKeysBefore(now.Add(-idempotencyKeyTTL)); err != nil {
+		logrus.Warnf("Failed to delete expired idempotency keys: %v", err)
+	}
+	if err := repo.StoreIdempotencyKey(&domainChatStorage.IdempotencyKey{
+		DeviceID:  deviceID,
+		Key:       key,
+		Operation: operation,
+		MessageID: sent.MessageID,
+		Response:  string(data),
+		CreatedAt: now,
+	}); err != nil {
+		logrus.Warnf("Failed to record idempotency key %s: %v", key, err)
+	}
+	return response, nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+type idempotencyRepo struct {
+	domainChatStorage.IChatStorageRepository
+	keys map[string]*domainChatStorage.IdempotencyKey
+}
+
+func (r *idempotencyRepo) GetIdempotencyKey(deviceID, key string, since time.Time) (*domainChatStorage.IdempotencyKey, error) {
+	record := r.keys[deviceID+"|"+key]
+	if record == nil || record.CreatedAt.Before(since) {
+		return nil, nil
+	}
+	return record, nil
+}
+
+func (r *idempotencyRepo) StoreIdempotencyKey(key *domainChatStorage.IdempotencyKey) error {
+	r.keys[key.DeviceID+"|"+key.Key] = key
+	return nil
+}
+
+func (r *idempotencyRepo) DeleteIdempotencyKeysBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type countingSender struct {
+	domainSend.ISendUsecase
+	sends int
+	err   error
+}
+
+func (s *countingSender) SendText(ctx context.Context, request domainSend.MessageRequest) (domainSend.GenericResponse, error) {
+	s.sends++
+	if s.err != nil {
+		return domainSend.GenericResponse{}, s.err
+	}
+	return domainSend.GenericResponse{MessageID: "m1", Status: "Message sent to " + request.Phone}, nil
+}
+
+func TestIdempotentSendReturnsOriginalResult(t *testing.T) {
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	repo := &idempotencyRepo{keys: make(map[string]*domainChatStorage.IdempotencyKey)}
+	sender := &countingSender{}
+	service := idempotentSend{ISendUsecase: sender, chatStorageRepo: repo}
+	request := domainSend.MessageRequest{BaseRequest: domainSend.BaseRequest{Phone: "628111", DedupID: "order-1"}, Message: "Your order shipped"}
+
+	first, err := service.SendText(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, err := service.SendText(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender.sends != 1 {
+		t.Errorf("sent %d times, want 1", sender.sends)
+	}
+	if retry != first {
+		t.Errorf("retry = %+v, want %+v", retry, first)
+	}
+	if record := repo.keys["device-a|order-1"]; record == nil || record.MessageID != "m1" || record.Operation != "send/message" {
+		t.Errorf("record = %+v", record)
+	}
+
+	// Without a key every call sends.
+	request.DedupID = ""
+	if _, err := service.SendText(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	if sender.sends != 2 {
+		t.Errorf("sent %d times, want 2", sender.sends)
+	}
+
+	// A key cannot be reused for another kind of send.
+	_, err = service.SendImage(ctx, domainSend.ImageRequest{BaseRequest: domainSend.BaseRequest{Phone: "628111", DedupID: "order-1"}})
+	var validation pkgError.ValidationError
+	if !errors.As(err, &validation) {
+		t.Errorf("reusing the key for an image: err = %v, want ValidationError", err)
+	}
+}
+
+func TestIdempotentSendRetriesFailedSends(t *testing.T) {
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	repo := &idempotencyRepo{keys: make(map[string]*domainChatStorage.IdempotencyKey)}
+	sender := &countingSender{err: errors.New("timeout")}
+	service := idempotentSend{ISendUsecase: sender, chatStorageRepo: repo}
+	request := domainSend.MessageRequest{BaseRequest: domainSend.BaseRequest{Phone: "628111", DedupID: "order-1"}, Message: "Your order shipped"}
+
+	if _, err := service.SendText(ctx, request); err == nil {
+		t.Fatal("expected the send to fail")
+	}
+	sender.err = nil
+	if _, err := service.SendText(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	if sender.sends != 2 {
+		t.Errorf("sent %d times, want 2", sender.sends)
+	}
+}
+
+func TestIdempotentSendRejectsConcurrentRetry(t *testing.T) {
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	repo := &idempotencyRepo{keys: make(map[string]*domainChatStorage.IdempotencyKey)}
+	base := domainSend.BaseRequest{Phone: "628111", DedupID: "order-1"}
+
+	_, err := idempotent(ctx, repo, "send/message", base, func() (domainSend.GenericResponse, error) {
+		_, err := idempotent(ctx, repo, "send/message", base, func() (domainSend.GenericResponse, error) {
+			t.Error("the retry was sent while the original send was in progress")
+			return domainSend.GenericResponse{}, nil
+		})
+		var inUse pkgError.IdempotencyKeyInUseError
+		if !errors.As(err, &inUse) {
+			t.Errorf("concurrent retry: err = %v, want IdempotencyKeyInUseError", err)
+		}
+		return domainSend.GenericResponse{MessageID: "m1"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// linkPreviewEnabled reports whether a text message gets a link preview: the
+// request's choice when set, the configured default otherwise.
+func linkPreviewEnabled(requested *bool) bool {
+	if requested != nil {
+		return *requested
+	}
+	return config.Setting(&config.WhatsappLinkPreview)
+}
+
+// attachLinkPreview fetches the page at link and attaches its title,
+// description and thumbnail to msg. A page that cannot be fetched leaves msg
+// as plain text.
+func (service serviceSend) attachLinkPreview(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.ExtendedTextMessage, link string) {
+	metadata, err := utils.GetMetaDataFromURL(link)
+	if err != nil {
+		logrus.Warnf("Failed to fetch link preview of %s: %v, sending without preview", link, err)
+		return
+	}
+	service.applyLinkPreview(ctx, client, recipient, msg, link, metadata)
+}
+
+// applyLinkPreview attaches metadata of link to msg, uploading the thumbnail
+// so recipients see the large preview image.
+func (service serviceSend) applyLinkPreview(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.ExtendedTextMessage, link string, metadata utils.Metadata) {
+	msg.Title = proto.String(metadata.Title)
+	msg.MatchedText = proto.String(link)
+	msg.Description = proto.String(metadata.Description)
+	msg.JPEGThumbnail = metadata.JPEGThumb
+
+	if len(metadata.ImageThumb) == 0 {
+		return
+	}
+	uploadedThumb, err := service.uploadMedia(ctx, client, whatsmeow.MediaLinkThumbnail, metadata.ImageThumb, recipient)
+	if err != nil {
+		logrus.Warnf("Failed to upload thumbnail: %v, continue without uploaded thumbnail", err)
+		return
+	}
+	msg.ThumbnailDirectPath = proto.String(uploadedThumb.DirectPath)
+	msg.ThumbnailSHA256 = uploadedThumb.FileSHA256
+	msg.ThumbnailEncSHA256 = uploadedThumb.FileEncSHA256
+	msg.MediaKey = uploadedThumb.MediaKey
+	msg.MediaKeyTimestamp = proto.Int64(time.Now().Unix())
+	if metadata.Height != nil {
+		msg.ThumbnailHeight = metadata.Height
+	}
+	if metadata.Width != nil {
+		msg.ThumbnailWidth = metadata.Width
+	}
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkPreviewEnabled(t *testing.T) {
+	original := config.WhatsappLinkPreview
+	t.Cleanup(func() { config.WhatsappLinkPreview = original })
+
+	enabled, disabled := true, false
+
+	config.WhatsappLinkPreview = false
+	assert.False(t, linkPreviewEnabled(nil))
+	assert.True(t, linkPreviewEnabled(&enabled))
+
+	config.WhatsappLinkPreview = true
+	assert.True(t, linkPreviewEnabled(nil))
+	assert.False(t, linkPreviewEnabled(&disabled))
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// liveLocations tracks the live location shares of this process, keyed by
+// device and chat. A share is dropped when stopped or once it expires, after
+// which updates are rejected.
+var liveLocations = struct {
+	sync.Mutex
+	shares map[string]*liveLocationShare
+}{shares: make(map[string]*liveLocationShare)}
+
+type liveLocationShare struct {
+	messageID string
+	caption   string
+	startedAt time.Time
+	expiresAt time.Time
+	sequence  int64
+	timer     *time.Timer
+}
+
+func liveLocationKey(deviceID, chatJID string) string {
+	return deviceID + "|" + chatJID
+}
+
+// SendLiveLocation starts, updates or stops sharing a live location. Each
+// start and update sends a live location message to the chat; updates carry
+// an increasing sequence number and the seconds since the share started, so
+// recipients move the existing pin instead of showing a new one.
+func (service serviceSend) SendLiveLocation(ctx context.Context, request domainSend.LiveLocationRequest) (response domainSend.LiveLocationResponse, err error) {
+	if err = validations.ValidateSendLiveLocation(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	dataWaRecipient, err := utils.ValidateAndNormalizeJID(client, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	key := liveLocationKey(deviceIDFromContext(ctx), dataWaRecipient.String())
+
+	if request.Action == domainSend.LiveLocationStop {
+		liveLocations.Lock()
+		share, ok := liveLocations.shares[key]
+		if ok {
+			share.timer.Stop()
+			delete(liveLocations.shares, key)
+		}
+		liveLocations.Unlock()
+		if !ok {
+			return response, pkgError.ValidationError(fmt.Sprintf("no live location is being shared with %s", request.Phone))
+		}
+		response.MessageID = share.messageID
+		response.SequenceNumber = share.sequence
+		response.Status = fmt.Sprintf("Live location sharing with %s stopped", request.Phone)
+		return response, nil
+	}
+
+	now := time.Now()
+	share := &liveLocationShare{caption: request.Caption, startedAt: now}
+	var sequence int64
+	if request.Action == domainSend.LiveLocationUpdate {
+		liveLocations.Lock()
+		current, ok := liveLocations.shares[key]
+		if ok {
+			current.sequence++
+			share, sequence = current, current.sequence
+		}
+		liveLocations.Unlock()
+		if !ok {
+			return response, pkgError.ValidationError(fmt.Sprintf("no live location is being shared with %s; start one first", request.Phone))
+		}
+	} else {
+		share.expiresAt = now.Add(time.Duration(request.DurationSeconds) * time.Second)
+	}
+
+	liveLocation := &waE2E.LiveLocationMessage{
+		DegreesLatitude:  proto.Float64(utils.StrToFloat64(request.Latitude)),
+		DegreesLongitude: proto.Float64(utils.StrToFloat64(request.Longitude)),
+		SequenceNumber:   proto.Int64(sequence),
+		TimeOffset:       proto.Uint32(uint32(now.Sub(share.startedAt).Seconds())),
+	}
+	if request.AccuracyInMeters > 0 {
+		liveLocation.AccuracyInMeters = proto.Uint32(request.AccuracyInMeters)
+	}
+	if share.caption != "" {
+		liveLocation.Caption = proto.String(share.caption)
+	}
+	if request.IsForwarded {
+		liveLocation.ContextInfo = &waE2E.ContextInfo{
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(100),
+		}
+	}
+	if request.Duration != nil && *request.Duration > 0 {
+		if liveLocation.ContextInfo == nil {
+			liveLocation.ContextInfo = &waE2E.ContextInfo{}
+		}
+		liveLocation.ContextInfo.Expiration = proto.Uint32(uint32(*request.Duration))
+	}
+	if request.Action == domainSend.LiveLocationStart {
+		liveLocation.ContextInfo = service.mergeReplyContext(ctx, liveLocation.ContextInfo, request.ReplyMessageID)
+	}
+
+	content := "📍 Live location " + request.Latitude + ", " + request.Longitude
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, &waE2E.Message{LiveLocationMessage: liveLocation}, content)
+	if err != nil {
+		return response, err
+	}
+
+	if request.Action == domainSend.LiveLocationStart {
+		share.messageID = ts.ID
+		share.timer = time.AfterFunc(share.expiresAt.Sub(now), func() {
+			liveLocations.Lock()
+			if liveLocations.shares[key] == share {
+				delete(liveLocations.shares, key)
+			}
+			liveLocations.Unlock()
+			logrus.Debugf("Live location sharing with %s expired", dataWaRecipient)
+		})
+		liveLocations.Lock()
+		if previous, ok := liveLocations.shares[key]; ok {
+			previous.timer.Stop()
+		}
+		liveLocations.shares[key] = share
+		liveLocations.Unlock()
+	}
+
+	response.MessageID = ts.ID
+	response.Active = true
+	response.SequenceNumber = sequence
+	response.ExpiresAt = share.expiresAt.Format(time.RFC3339)
+	response.Status = fmt.Sprintf("Live location %s sent to %s (server timestamp: %s)", request.Action, request.Phone, ts.Timestamp.String())
+	return response, nil
+}
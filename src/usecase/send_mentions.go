@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// everyoneMentionCooldown is how long a group must wait between two messages
+// mentioning all of its participants, so a retry loop cannot spam a group
+// with notifications.
+const everyoneMentionCooldown = time.Minute
+
+// mentionTokenPattern matches the @phone tokens of a message text.
+var mentionTokenPattern = regexp.MustCompile(`@(\d+)`)
+
+// everyoneMentions records when each device last mentioned everyone in a
+// group, keyed by device and group JID.
+var everyoneMentions = struct {
+	sync.Mutex
+	last map[string]time.Time
+}{last: make(map[string]time.Time)}
+
+// allowEveryoneMention reports whether key may mention everyone at now, and
+// records the mention when it may.
+func allowEveryoneMention(key string, now time.Time) bool {
+	everyoneMentions.Lock()
+	defer everyoneMentions.Unlock()
+	if last, ok := everyoneMentions.last[key]; ok && now.Sub(last) < everyoneMentionCooldown {
+		return false
+	}
+	for k, last := range everyoneMentions.last {
+		if now.Sub(last) >= everyoneMentionCooldown {
+			delete(everyoneMentions.last, k)
+		}
+	}
+	everyoneMentions.last[key] = now
+	return true
+}
+
+// groupMentionJIDs maps the phone number of each group participant to the JID
+// its mentions must use: the LID in LID-addressed groups, the phone JID
+// otherwise. A mention of any other JID does not notify the participant.
+func groupMentionJIDs(participants []types.GroupParticipant) map[string]types.JID {
+	result := make(map[string]types.JID, len(participants))
+	for _, participant := range participants {
+		if !participant.PhoneNumber.IsEmpty() {
+			result[participant.PhoneNumber.User] = participant.JID
+		}
+		if participant.JID.Server == types.DefaultUserServer {
+			result[participant.JID.User] = participant.JID
+		}
+	}
+	return result
+}
+
+// rewriteMentionTokens replaces the @phone tokens of text with the user of the
+// JID mentioned for that phone, so WhatsApp renders them as mentions of the
+// mentioned JID. Tokens without a mention are left as typed.
+func rewriteMentionTokens(text string, mentioned map[string]types.JID) string {
+	return mentionTokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if jid, ok := mentioned[token[1:]]; ok {
+			return "@" + jid.User
+		}
+		return token
+	})
+}
+
+// applyMentions sets the text resolveMentions rewrote and the mentioned JIDs
+// on an outgoing text message. It returns the text the message now carries,
+// which is the content to store, so chat history matches what was sent.
+func applyMentions(message *waE2E.ExtendedTextMessage, text string, mentionedJIDs []string) string {
+	if len(mentionedJIDs) > 0 {
+		message.Text = proto.String(text)
+		if message.ContextInfo == nil {
+			message.ContextInfo = &waE2E.ContextInfo{}
+		}
+		message.ContextInfo.MentionedJID = mentionedJIDs
+	}
+	return message.GetText()
+}
+
+// resolveMentions returns the message text and the JIDs it mentions. Mentions
+// come from the @phone tokens of text and from mentions (ghost mentions, which
+// notify without appearing in the text). In groups each mention uses the JID
+// the participant is addressed by, and the @phone tokens of the text are
+// rewritten to match; "@everyone" or "all" mention every participant except
+// ourselves, at most once per everyoneMentionCooldown per group. Mentions that
+// cannot be resolved are dropped.
+func (service serviceSend) resolveMentions(ctx context.Context, client *whatsmeow.Client, recipient types.JID, text string, mentions []string) (string, []string, error) {
+	tokens := utils.ContainsMention(text)
+	if len(tokens) == 0 && len(mentions) == 0 {
+		return text, nil, nil
+	}
+
+	var (
+		participants []types.GroupParticipant
+		groupErr     error
+	)
+	if recipient.Server == types.GroupServer {
+		groupInfo, err := client.GetGroupInfo(ctx, recipient)
+		if err != nil {
+			groupErr = err
+			logrus.Warnf("Failed to get participants of %s for mentions: %v", recipient, err)
+		} else if groupInfo != nil {
+			participants = groupInfo.Participants
+		}
+	}
+	members := groupMentionJIDs(participants)
+
+	// resolve returns the JID to mention for a phone number or JID.
+	resolve := func(mention string) (types.JID, bool) {
+		jid, err := utils.ValidateAndNormalizeJID(client, mention)
+		if err != nil {
+			return types.JID{}, false
+		}
+		if member, ok := members[jid.User]; ok && jid.Server == types.DefaultUserServer {
+			return member, true
+		}
+		return jid, true
+	}
+
+	var result []string
+	mentioned := make(map[string]types.JID, len(tokens))
+	for _, token := range tokens {
+		if jid, ok := resolve(token); ok {
+			mentioned[token] = jid
+			result = append(result, jid.String())
+		}
+	}
+	text = rewriteMentionTokens(text, mentioned)
+
+	everyone := false
+	for _, mention := range mentions {
+		if domainSend.IsMentionEveryone(mention) {
+			everyone = true
+			continue
+		}
+		if jid, ok := resolve(mention); ok {
+			result = append(result, jid.String())
+		}
+	}
+
+	if everyone && recipient.Server == types.GroupServer {
+		if groupErr != nil {
+			return text, nil, fmt.Errorf("failed to get participants of %s: %w", recipient, groupErr)
+		}
+		key := deviceIDFromContext(ctx) + "|" + recipient.String()
+		if !allowEveryoneMention(key, time.Now()) {
+			return text, nil, pkgError.MentionRateLimitError(fmt.Sprintf("everyone in %s was mentioned less than %s ago", recipient, everyoneMentionCooldown))
+		}
+		own, ownLID := client.Store.GetJID().ToNonAD(), client.Store.GetLID().ToNonAD()
+		for _, participant := range participants {
+			if participant.JID == own || participant.JID == ownLID {
+				continue
+			}
+			result = append(result, participant.JID.String())
+		}
+	}
+
+	return text, utils.UniqueStrings(result), nil
+}
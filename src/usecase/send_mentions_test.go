@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGroupMentionJIDs(t *testing.T) {
+	lid := types.NewJID("123456789012345", types.HiddenUserServer)
+	pn := types.NewJID("628123456789", types.DefaultUserServer)
+	other := types.NewJID("628987654321", types.DefaultUserServer)
+
+	members := groupMentionJIDs([]types.GroupParticipant{
+		{JID: lid, PhoneNumber: pn, LID: lid},
+		{JID: other},
+	})
+
+	if got := members["628123456789"]; got != lid {
+		t.Errorf("phone of an LID participant maps to %s, want %s", got, lid)
+	}
+	if got := members["628987654321"]; got != other {
+		t.Errorf("phone participant maps to %s, want %s", got, other)
+	}
+}
+
+func TestRewriteMentionTokens(t *testing.T) {
+	lid := types.NewJID("123456789012345", types.HiddenUserServer)
+	mentioned := map[string]types.JID{
+		"628123456789": lid,
+		"628555":       types.NewJID("628555", types.DefaultUserServer),
+	}
+
+	got := rewriteMentionTokens("hi @628123456789 and @628555, not @628999", mentioned)
+	want := "hi @123456789012345 and @628555, not @628999"
+	if got != want {
+		t.Errorf("rewriteMentionTokens() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyMentionsReturnsSentText(t *testing.T) {
+	lid := types.NewJID("123456789012345", types.HiddenUserServer)
+	message := &waE2E.ExtendedTextMessage{Text: proto.String("hi @628123456789")}
+
+	content := applyMentions(message, "hi @123456789012345", []string{lid.String()})
+	if content != "hi @123456789012345" || message.GetText() != content {
+		t.Errorf("stored content %q, sent text %q, want both rewritten", content, message.GetText())
+	}
+	if got := message.GetContextInfo().GetMentionedJID(); len(got) != 1 || got[0] != lid.String() {
+		t.Errorf("mentioned JIDs = %v, want [%s]", got, lid)
+	}
+
+	plain := &waE2E.ExtendedTextMessage{Text: proto.String("hi @628999")}
+	if content := applyMentions(plain, "hi @628999", nil); content != "hi @628999" || plain.GetContextInfo() != nil {
+		t.Errorf("message without mentions changed: content %q, context %v", content, plain.GetContextInfo())
+	}
+}
+
+func TestAllowEveryoneMention(t *testing.T) {
+	now := time.Now()
+	key := "device-a|120363025246125486@g.us"
+
+	if !allowEveryoneMention(key, now) {
+		t.Fatal("first mention of everyone was refused")
+	}
+	if allowEveryoneMention(key, now.Add(everyoneMentionCooldown/2)) {
+		t.Error("mention of everyone within the cooldown was allowed")
+	}
+	if !allowEveryoneMention("device-b|120363025246125486@g.us", now) {
+		t.Error("another device was refused by the cooldown of device-a")
+	}
+	if !allowEveryoneMention(key, now.Add(everyoneMentionCooldown)) {
+		t.Error("mention of everyone after the cooldown was refused")
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Products are looked up in pages of catalogPageSize, reading at most
+// catalogLookupPages pages before giving up.
+const (
+	catalogPageSize    = 100
+	catalogLookupPages = 10
+)
+
+// findCatalogProduct returns the product productID of the catalog of owner.
+func findCatalogProduct(ctx context.Context, client *whatsmeow.Client, owner types.JID, productID string) (*whatsapp.CatalogProduct, error) {
+	cursor := ""
+	for page := 0; page < catalogLookupPages; page++ {
+		catalog, err := whatsapp.GetBusinessCatalog(ctx, client, owner, catalogPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for i := range catalog.Products {
+			if catalog.Products[i].ID == productID {
+				return &catalog.Products[i], nil
+			}
+		}
+		if catalog.NextCursor == "" {
+			break
+		}
+		cursor = catalog.NextCursor
+	}
+	return nil, pkgError.ProductNotFoundError(fmt.Sprintf("product %s is not in the catalog of %s", productID, owner))
+}
+
+// uploadProductImage downloads the catalog image at url and uploads it for
+// recipient. Product messages render without an image, so failures are logged
+// and return nil.
+func (service serviceSend) uploadProductImage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, url string) *waE2E.ImageMessage {
+	if url == "" {
+		return nil
+	}
+	image, _, err := utils.DownloadImageFromURL(url)
+	if err != nil {
+		logrus.Warnf("Failed to download product image %s: %v", url, err)
+		return nil
+	}
+	uploaded, err := service.uploadMedia(ctx, client, whatsmeow.MediaImage, image, recipient)
+	if err != nil {
+		logrus.Warnf("Failed to upload product image %s: %v", url, err)
+		return nil
+	}
+	return &waE2E.ImageMessage{
+		URL:           proto.String(uploaded.URL),
+		DirectPath:    proto.String(uploaded.DirectPath),
+		MediaKey:      uploaded.MediaKey,
+		Mimetype:      proto.String(http.DetectContentType(image)),
+		FileEncSHA256: uploaded.FileEncSHA256,
+		FileSHA256:    uploaded.FileSHA256,
+		FileLength:    proto.Uint64(uint64(len(image))),
+	}
+}
+
+func (service serviceSend) SendProduct(ctx context.Context, request domainSend.ProductRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendProduct(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	dataWaRecipient, err := utils.ValidateAndNormalizeJID(client, request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	owner := client.Store.GetJID().ToNonAD()
+	productMessage := &waE2E.ProductMessage{
+		BusinessOwnerJID: proto.String(owner.String()),
+	}
+	var content string
+	if request.ProductID != "" {
+		product, err := findCatalogProduct(ctx, client, owner, request.ProductID)
+		if err != nil {
+			return response, err
+		}
+		productMessage.Product = &waE2E.ProductMessage_ProductSnapshot{
+			ProductImage:      service.uploadProductImage(ctx, client, dataWaRecipient, product.ImageURL),
+			ProductID:         proto.String(product.ID),
+			Title:             proto.String(product.Name),
+			Description:       proto.String(product.Description),
+			CurrencyCode:      proto.String(product.Currency),
+			PriceAmount1000:   proto.Int64(product.PriceAmount1000),
+			RetailerID:        proto.String(product.RetailerID),
+			URL:               proto.String(product.URL),
+			ProductImageCount: proto.Uint32(1),
+		}
+		content = "🛍️ " + product.Name
+	} else {
+		catalog, err := whatsapp.GetBusinessCatalog(ctx, client, owner, 1, "")
+		if err != nil {
+			return response, err
+		}
+		snapshot := &waE2E.ProductMessage_CatalogSnapshot{
+			Title: proto.String(client.Store.PushName),
+		}
+		if len(catalog.Products) > 0 {
+			snapshot.CatalogImage = service.uploadProductImage(ctx, client, dataWaRecipient, catalog.Products[0].ImageURL)
+		}
+		productMessage.Catalog = snapshot
+		content = "🛍️ " + client.Store.PushName + " catalog"
+	}
+	if body := strings.TrimSpace(request.Body); body != "" {
+		productMessage.Body = proto.String(body)
+		content += "\n" + body
+	}
+	if footer := strings.TrimSpace(request.Footer); footer != "" {
+		productMessage.Footer = proto.String(footer)
+	}
+
+	if request.BaseRequest.IsForwarded {
+		productMessage.ContextInfo = &waE2E.ContextInfo{
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(100),
+		}
+	}
+	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
+		if productMessage.ContextInfo == nil {
+			productMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		productMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	}
+	productMessage.ContextInfo = service.mergeReplyContext(ctx, productMessage.ContextInfo, request.ReplyMessageID)
+
+	if err := service.simulateTyping(ctx, client, dataWaRecipient, request.BaseRequest, types.ChatPresenceMediaText, typingDuration(request.Body)); err != nil {
+		return response, err
+	}
+
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, &waE2E.Message{ProductMessage: productMessage}, content)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send product success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	return response, nil
+}
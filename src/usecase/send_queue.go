@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// maxQueueListLimit is the most queued messages listed at once.
+const maxQueueListLimit = 500
+
+func (service serviceSend) ListQueue(ctx context.Context, request domainSend.ListQueueRequest) (response domainSend.ListQueueResponse, err error) {
+	switch request.Status {
+	case "", domainChatStorage.OutgoingMessagePending, domainChatStorage.OutgoingMessageFailed:
+	default:
+		return response, pkgError.ValidationError(fmt.Sprintf("status: must be %s or %s, got %q",
+			domainChatStorage.OutgoingMessagePending, domainChatStorage.OutgoingMessageFailed, request.Status))
+	}
+	if request.Limit < 0 || request.Limit > maxQueueListLimit {
+		return response, pkgError.ValidationError(fmt.Sprintf("limit: must be between 0 and %d.", maxQueueListLimit))
+	}
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	messages, err := service.chatStorageRepo.ListOutgoingMessages(deviceID, request.Status, request.Limit)
+	if err != nil {
+		return response, fmt.Errorf("failed to list queued messages: %w", err)
+	}
+	response.Data = make([]domainSend.QueuedMessage, 0, len(messages))
+	for _, message := range messages {
+		response.Data = append(response.Data, domainSend.QueuedMessage{
+			MessageID:     message.MessageID,
+			ChatJID:       message.ChatJID,
+			Status:        message.Status,
+			Attempts:      message.Attempts,
+			LastError:     message.LastError,
+			NextAttemptAt: message.NextAttemptAt.Format(time.RFC3339),
+			CreatedAt:     message.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
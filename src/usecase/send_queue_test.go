@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+type outgoingQueueRepo struct {
+	domainChatStorage.IChatStorageRepository
+	deviceID, status string
+}
+
+func (r *outgoingQueueRepo) ListOutgoingMessages(deviceID, status string, limit int) ([]*domainChatStorage.OutgoingMessage, error) {
+	r.deviceID, r.status = deviceID, status
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	return []*domainChatStorage.OutgoingMessage{{
+		MessageID: "3EB0A1", ChatJID: "628111@s.whatsapp.net", Status: domainChatStorage.OutgoingMessagePending,
+		Attempts: 2, LastError: "websocket not connected", NextAttemptAt: createdAt.Add(time.Minute), CreatedAt: createdAt,
+	}}, nil
+}
+
+func TestListQueue(t *testing.T) {
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	repo := &outgoingQueueRepo{}
+	service := serviceSend{chatStorageRepo: repo}
+
+	response, err := service.ListQueue(ctx, domainSend.ListQueueRequest{Status: "pending", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.deviceID != "device-a" || repo.status != "pending" {
+		t.Errorf("listed device %q status %q, want device-a pending", repo.deviceID, repo.status)
+	}
+	want := domainSend.QueuedMessage{
+		MessageID: "3EB0A1", ChatJID: "628111@s.whatsapp.net", Status: "pending", Attempts: 2,
+		LastError: "websocket not connected", NextAttemptAt: "2026-03-01T10:01:00Z", CreatedAt: "2026-03-01T10:00:00Z",
+	}
+	if len(response.Data) != 1 || response.Data[0] != want {
+		t.Errorf("data = %+v, want [%+v]", response.Data, want)
+	}
+
+	var validationErr pkgError.ValidationError
+	if _, err := service.ListQueue(ctx, domainSend.ListQueueRequest{Status: "sent"}); !errors.As(err, &validationErr) {
+		t.Errorf("status sent: err = %v, want a validation error", err)
+	}
+	if _, err := service.ListQueue(ctx, domainSend.ListQueueRequest{Limit: maxQueueListLimit + 1}); !errors.As(err, &validationErr) {
+		t.Errorf("limit over the maximum: err = %v, want a validation error", err)
+	}
+}
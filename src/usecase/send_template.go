@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+func (service serviceSend) SaveTemplate(ctx context.Context, request domainSend.TemplateRequest) (response domainSend.Template, err error) {
+	if err = validations.ValidateSaveTemplate(ctx, request); err != nil {
+		return response, err
+	}
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	template := &domainChatStorage.MessageTemplate{
+		DeviceID:   deviceID,
+		Name:       request.Name,
+		Body:       request.Body,
+		HeaderType: request.HeaderType,
+		HeaderURL:  request.HeaderURL,
+	}
+	if err = service.chatStorageRepo.StoreMessageTemplate(template); err != nil {
+		return response, fmt.Errorf("failed to save template: %w", err)
+	}
+	// StoreMessageTemplate only sets CreatedAt for new templates.
+	stored, err := service.chatStorageRepo.GetMessageTemplate(deviceID, request.Name)
+	if err != nil {
+		return response, err
+	}
+	if stored != nil {
+		template = stored
+	}
+	return toTemplate(template), nil
+}
+
+func (service serviceSend) GetTemplate(ctx context.Context, name string) (response domainSend.Template, err error) {
+	template, err := service.loadTemplate(ctx, name)
+	if err != nil {
+		return response, err
+	}
+	return toTemplate(template), nil
+}
+
+func (service serviceSend) ListTemplates(ctx context.Context) (response domainSend.ListTemplatesResponse, err error) {
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+	templates, err := service.chatStorageRepo.GetMessageTemplates(deviceID)
+	if err != nil {
+		return response, fmt.Errorf("failed to list templates: %w", err)
+	}
+	response.Data = make([]domainSend.Template, 0, len(templates))
+	for _, template := range templates {
+		response.Data = append(response.Data, toTemplate(template))
+	}
+	return response, nil
+}
+
+func (service serviceSend) DeleteTemplate(ctx context.Context, name string) error {
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return fmt.Errorf("device identification required")
+	}
+	deleted, err := service.chatStorageRepo.DeleteMessageTemplate(deviceID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	if !deleted {
+		return pkgError.TemplateNotFoundError(fmt.Sprintf("template %s not found", name))
+	}
+	return nil
+}
+
+// SendTemplate fills the placeholders of a stored template and sends it: as a
+// text message, or as the caption of its header image, video or document.
+func (service serviceSend) SendTemplate(ctx context.Context, request domainSend.SendTemplateRequest) (response domainSend.GenericResponse, err error) {
+	if err = validations.ValidateSendTemplate(ctx, request); err != nil {
+		return response, err
+	}
+	template, err := service.loadTemplate(ctx, request.Template)
+	if err != nil {
+		return response, err
+	}
+
+	phone := strings.SplitN(request.Phone, "@", 2)[0]
+	body, err := renderTemplate(template.Body, request.Variables, phone)
+	if err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
+	return service.sendTemplateMessage(ctx, template, request.BaseRequest, body)
+}
+
+// sendTemplateMessage sends an already rendered template body.
+func (service serviceSend) sendTemplateMessage(ctx context.Context, template *domainChatStorage.MessageTemplate, base domainSend.BaseRequest, body string) (domainSend.GenericResponse, error) {
+	headerURL := template.HeaderURL
+	switch template.HeaderType {
+	case domainSend.TemplateHeaderImage:
+		return service.SendImage(ctx, domainSend.ImageRequest{BaseRequest: base, ImageURL: &headerURL, Caption: body, Compress: true})
+	case domainSend.TemplateHeaderVideo:
+		return service.SendVideo(ctx, domainSend.VideoRequest{BaseRequest: base, VideoURL: &headerURL, Caption: body})
+	case domainSend.TemplateHeaderDocument:
+		return service.SendFile(ctx, domainSend.FileRequest{BaseRequest: base, FileURL: &headerURL, Caption: body})
+	default:
+		return service.SendText(ctx, domainSend.MessageRequest{BaseRequest: base, Message: body})
+	}
+}
+
+// loadTemplate returns the device's template by name or a TemplateNotFoundError.
+func (service serviceSend) loadTemplate(ctx context.Context, name string) (*domainChatStorage.MessageTemplate, error) {
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return nil, fmt.Errorf("device identification required")
+	}
+	template, err := service.chatStorageRepo.GetMessageTemplate(deviceID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	if template == nil {
+		return nil, pkgError.TemplateNotFoundError(fmt.Sprintf("template %s not found", name))
+	}
+	return template, nil
+}
+
+// renderTemplate fills the {{name}} placeholders of body from variables;
+// {{phone}} defaults to phone. Every placeholder must be filled.
+func renderTemplate(body string, variables map[string]string, phone string) (string, error) {
+	var missing []string
+	rendered := templatePlaceholder.ReplaceAllStringFunc(body, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		if name == "phone" {
+			return phone
+		}
+		missing = append(missing, name)
+		return placeholder
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing template variables: %s", strings.Join(missing, ", "))
+	}
+	return rendered, nil
+}
+
+// templateVariables lists the placeholders of body in order of first use.
+func templateVariables(body string) []string {
+	variables := []string{}
+	seen := make(map[string]bool)
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(body, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			variables = append(variables, match[1])
+		}
+	}
+	return variables
+}
+
+func toTemplate(template *domainChatStorage.MessageTemplate) domainSend.Template {
+	return domainSend.Template{
+		Name:       template.Name,
+		Body:       template.Body,
+		HeaderType: template.HeaderType,
+		HeaderURL:  template.HeaderURL,
+		Variables:  templateVariables(template.Body),
+		CreatedAt:  template.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  template.UpdatedAt.Format(time.RFC3339),
+	}
+}
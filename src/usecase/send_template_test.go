@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+type templateRepo struct {
+	domainChatStorage.IChatStorageRepository
+	template *domainChatStorage.MessageTemplate
+}
+
+func (r *templateRepo) GetMessageTemplate(deviceID, name string) (*domainChatStorage.MessageTemplate, error) {
+	if r.template == nil || r.template.DeviceID != deviceID || r.template.Name != name {
+		return nil, nil
+	}
+	return r.template, nil
+}
+
+func TestTemplateVariables(t *testing.T) {
+	got := templateVariables("Hi {{name}}, order {{ order }} ships to {{name}} at {{phone}}")
+	if want := []string{"name", "order", "phone"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("variables = %v, want %v", got, want)
+	}
+	if got := templateVariables("No placeholders"); got == nil || len(got) != 0 {
+		t.Errorf("variables = %#v, want an empty list", got)
+	}
+}
+
+func TestSendTemplateChecksTemplateAndVariables(t *testing.T) {
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("device-a", nil, nil))
+	repo := &templateRepo{}
+	service := serviceSend{chatStorageRepo: repo}
+	request := domainSend.SendTemplateRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: "628111@s.whatsapp.net"},
+		Template:    "order_shipped",
+	}
+
+	var notFound pkgError.TemplateNotFoundError
+	if _, err := service.SendTemplate(ctx, request); !errors.As(err, &notFound) {
+		t.Fatalf("unknown template: err = %v, want TemplateNotFoundError", err)
+	}
+
+	repo.template = &domainChatStorage.MessageTemplate{DeviceID: "device-a", Name: "order_shipped", Body: "Order {{order}} shipped"}
+	var validation pkgError.ValidationError
+	if _, err := service.SendTemplate(ctx, request); !errors.As(err, &validation) || err.Error() != "missing template variables: order" {
+		t.Errorf("missing variable: err = %v, want a ValidationError", err)
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waCommon"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"google.golang.org/protobuf/proto"
 )
@@ -223,3 +228,102 @@ func TestMergeReplyContextLeavesExistingContextWhenReplyUnavailable(t *testing.T
 		})
 	}
 }
+
+func TestQuotedMessageKeepsMediaType(t *testing.T) {
+	tests := []struct {
+		name    string
+		message domainChatStorage.Message
+		check   func(*waE2E.Message) bool
+	}{
+		{"text", domainChatStorage.Message{Content: "hello"}, func(m *waE2E.Message) bool {
+			return m.GetConversation() == "hello"
+		}},
+		{"image", domainChatStorage.Message{MediaType: "image", Content: "at the beach"}, func(m *waE2E.Message) bool {
+			return m.GetImageMessage().GetCaption() == "at the beach"
+		}},
+		{"document", domainChatStorage.Message{MediaType: "document", Filename: "invoice.pdf"}, func(m *waE2E.Message) bool {
+			return m.GetDocumentMessage().GetFileName() == "invoice.pdf"
+		}},
+		{"voice note", domainChatStorage.Message{MediaType: "audio"}, func(m *waE2E.Message) bool {
+			return m.GetAudioMessage() != nil
+		}},
+		{"sticker", domainChatStorage.Message{MediaType: "sticker"}, func(m *waE2E.Message) bool {
+			return m.GetStickerMessage() != nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quotedMessage(&tt.message); !tt.check(got) {
+				t.Errorf("quotedMessage() = %v", got)
+			}
+		})
+	}
+}
+
+func TestContactVCard(t *testing.T) {
+	legacy := contactVCard(domainSend.ContactCard{Name: "Aldino", Phones: []string{"62788712738123"}})
+	if want := "BEGIN:VCARD\nVERSION:3.0\nN:;Aldino;;;\nFN:Aldino\nTEL;type=CELL;waid=62788712738123:+62788712738123\nEND:VCARD"; legacy != want {
+		t.Errorf("single phone vCard = %q, want %q", legacy, want)
+	}
+
+	full := contactVCard(domainSend.ContactCard{
+		Name:         "Budi; Jr",
+		Phones:       []string{"+6281234567890", "6289876543210"},
+		Organization: "Acme, Inc",
+		Emails:       []string{"budi@example.com"},
+	})
+	for _, line := range []string{`FN:Budi\; Jr`, `ORG:Acme\, Inc`, "TEL;type=CELL;waid=6281234567890:+6281234567890", "TEL;type=CELL;waid=6289876543210:+6289876543210", "EMAIL;type=INTERNET:budi@example.com"} {
+		if !strings.Contains(full, line+"\n") {
+			t.Errorf("vCard %q is missing %q", full, line)
+		}
+	}
+}
+
+func TestSetAlbumAssociation(t *testing.T) {
+	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}
+	setAlbumAssociation(context.Background(), msg)
+	if msg.MessageContextInfo != nil {
+		t.Fatal("messages outside an album must not be associated")
+	}
+
+	parent := &waCommon.MessageKey{ID: proto.String("ALBUM1"), FromMe: proto.Bool(true)}
+	setAlbumAssociation(withAlbumParent(context.Background(), parent), msg)
+	association := msg.GetMessageContextInfo().GetMessageAssociation()
+	if association.GetAssociationType() != waE2E.MessageAssociation_MEDIA_ALBUM || association.GetParentMessageKey().GetID() != "ALBUM1" {
+		t.Errorf("association = %v", association)
+	}
+}
+
+func TestVoiceNoteMetadata(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+	path := filepath.Join(t.TempDir(), "tone.wav")
+	if out, err := exec.Command("ffmpeg", "-f", "lavfi", "-i", "sine=frequency=440:duration=2", "-y", path).CombinedOutput(); err != nil {
+		t.Fatalf("generate tone: %v: %s", err, out)
+	}
+
+	seconds, waveform := voiceNoteMetadata(path)
+	if seconds != 2 {
+		t.Errorf("seconds = %d, want 2", seconds)
+	}
+	if len(waveform) != 64 {
+		t.Fatalf("waveform has %d samples, want 64", len(waveform))
+	}
+	for i, v := range waveform {
+		if v > 100 {
+			t.Errorf("waveform[%d] = %d, want at most 100", i, v)
+		}
+	}
+}
+
+func TestVoiceNoteMetadataWithoutAudio(t *testing.T) {
+	seconds, waveform := voiceNoteMetadata(filepath.Join(t.TempDir(), "missing.ogg"))
+	if seconds != 0 {
+		t.Errorf("seconds = %d, want 0", seconds)
+	}
+	if len(waveform) != 64 {
+		t.Errorf("waveform has %d samples, want the 64 sample default", len(waveform))
+	}
+}
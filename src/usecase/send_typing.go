@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Typing simulation timing: about as fast as a person types on a phone, never
+// shorter than a glance at the screen and never long enough to stall the API.
+const (
+	typingPerCharacter = 60 * time.Millisecond
+	minTypingDuration  = time.Second
+	maxTypingDuration  = 30 * time.Second
+	// maxAutoTypingDuration caps durations derived from the message itself;
+	// typing_seconds can ask for up to maxTypingDuration.
+	maxAutoTypingDuration = 8 * time.Second
+)
+
+// typingDuration returns how long a person would take to type text.
+func typingDuration(text string) time.Duration {
+	duration := minTypingDuration + time.Duration(utf8.RuneCountInString(text))*typingPerCharacter
+	return min(duration, maxAutoTypingDuration)
+}
+
+// simulateTyping shows the recipient "typing…", or "recording audio…" for
+// ChatPresenceMediaAudio, for the given duration before the message is sent.
+// The request's typing_seconds replaces the duration. Nothing is done unless
+// the request sets simulate_typing; a failed presence update is only logged so
+// the message is still sent.
+func (service serviceSend) simulateTyping(ctx context.Context, client *whatsmeow.Client, recipient types.JID, base domainSend.BaseRequest, media types.ChatPresenceMedia, duration time.Duration) error {
+	if !base.SimulateTyping {
+		return nil
+	}
+	if base.TypingSeconds > 0 {
+		duration = time.Duration(base.TypingSeconds) * time.Second
+	}
+	duration = min(max(duration, minTypingDuration), maxTypingDuration)
+
+	if err := client.SendChatPresence(ctx, recipient, types.ChatPresenceComposing, media); err != nil {
+		logrus.Warnf("Failed to simulate typing to %s: %v", recipient, err)
+		return nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		if err := client.SendChatPresence(context.WithoutCancel(ctx), recipient, types.ChatPresencePaused, media); err != nil {
+			logrus.Warnf("Failed to stop simulated typing to %s: %v", recipient, err)
+		}
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
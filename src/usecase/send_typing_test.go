@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestTypingDuration(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Duration
+	}{
+		{"", minTypingDuration},
+		{"hi", minTypingDuration + 2*typingPerCharacter},
+		{"héllo wörld", minTypingDuration + 11*typingPerCharacter},
+		{strings.Repeat("a", 1000), maxAutoTypingDuration},
+	}
+	for _, tt := range tests {
+		if got := typingDuration(tt.text); got != tt.want {
+			t.Errorf("typingDuration(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSimulateTypingDisabled(t *testing.T) {
+	// Without simulate_typing the client is never used, so a nil one is fine.
+	start := time.Now()
+	err := serviceSend{}.simulateTyping(context.Background(), nil, types.NewJID("628123", types.DefaultUserServer),
+		domainSend.BaseRequest{TypingSeconds: 5}, types.ChatPresenceMediaText, maxTypingDuration)
+	if err != nil {
+		t.Fatalf("simulateTyping() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("simulateTyping() waited %v without simulate_typing", elapsed)
+	}
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/dustin/go-humanize"
+	"github.com/sirupsen/logrus"
+)
+
+// Limits of converted videos. WhatsApp clients play H.264/AAC MP4 within
+// these on every platform; larger or other formats may fail to play.
+const (
+	videoMaxDimension   = 1280
+	videoMaxBitrate     = "2500k"
+	videoBufferSize     = "5000k"
+	videoConvertTimeout = 5 * time.Minute
+)
+
+// videoInfo is what ffprobe reports about a video file.
+type videoInfo struct {
+	Format     string
+	VideoCodec string
+	AudioCodec string
+	Width      int
+	Height     int
+}
+
+// needsConversion reports whether a video has to be converted before every
+// WhatsApp client can play it.
+func (info videoInfo) needsConversion() bool {
+	if info.VideoCodec != "h264" || !strings.Contains(info.Format, "mp4") {
+		return true
+	}
+	if info.AudioCodec != "" && info.AudioCodec != "aac" {
+		return true
+	}
+	return max(info.Width, info.Height) > videoMaxDimension
+}
+
+// parseVideoInfo reads the JSON printed by ffprobe -show_entries
+// stream=codec_type,codec_name,width,height:format=format_name -of json.
+func parseVideoInfo(output []byte) (videoInfo, error) {
+	var probe struct {
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			CodecName string `json:"codec_name"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			FormatName string `json:"format_name"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return videoInfo{}, err
+	}
+
+	info := videoInfo{Format: probe.Format.FormatName}
+	for _, stream := range probe.Streams {
+		switch {
+		case stream.CodecType == "video" && info.VideoCodec == "":
+			info.VideoCodec = stream.CodecName
+			info.Width, info.Height = stream.Width, stream.Height
+		case stream.CodecType == "audio" && info.AudioCodec == "":
+			info.AudioCodec = stream.CodecName
+		}
+	}
+	if info.VideoCodec == "" {
+		return info, fmt.Errorf("no video stream found")
+	}
+	return info, nil
+}
+
+func probeVideo(videoPath string) (videoInfo, error) {
+	output, err := runFFProbe(
+		"-v", "error",
+		"-show_entries", "stream=codec_type,codec_name,width,height:format=format_name",
+		"-of", "json",
+		videoPath,
+	)
+	if err != nil {
+		return videoInfo{}, err
+	}
+	return parseVideoInfo(output)
+}
+
+// convertVideo converts a video to H.264/AAC MP4 within the limits WhatsApp
+// clients play, when WhatsappSettingAutoConvertVideo is enabled and the video
+// needs it. It returns the path of the video to send, which is outputPath
+// only when a conversion took place.
+func convertVideo(ctx context.Context, inputPath, outputPath string) (string, error) {
+	if !config.Setting(&config.WhatsappSettingAutoConvertVideo) {
+		return inputPath, nil
+	}
+
+	info, err := probeVideo(inputPath)
+	if err != nil {
+		// Without ffprobe the video cannot be checked, so it is sent as it is.
+		logrus.Warnf("Failed to probe video %s, sending it unconverted: %v", inputPath, err)
+		return inputPath, nil
+	}
+	if !info.needsConversion() {
+		return inputPath, nil
+	}
+
+	convCtx, cancel := context.WithTimeout(ctx, videoConvertTimeout)
+	defer cancel()
+
+	// -vf: Fit within videoMaxDimension keeping the aspect ratio; H.264 needs even dimensions
+	// -profile:v main -pix_fmt yuv420p: Playable by older phones
+	// -crf 23 with -maxrate/-bufsize: Good quality, capped bitrate
+	// -c:a aac -ac 2 -ar 44100: AAC stereo, which WhatsApp plays everywhere
+	// -movflags +faststart: Playback starts before the download completes
+	scale := fmt.Sprintf("scale='min(%[1]d,iw)':'min(%[1]d,ih)':force_original_aspect_ratio=decrease,scale=trunc(iw/2)*2:trunc(ih/2)*2", videoMaxDimension)
+	cmd := exec.CommandContext(convCtx, "ffmpeg",
+		"-i", inputPath,
+		"-vf", scale,
+		"-c:v", "libx264",
+		"-profile:v", "main",
+		"-pix_fmt", "yuv420p",
+		"-preset", "fast",
+		"-crf", "23",
+		"-maxrate", videoMaxBitrate,
+		"-bufsize", videoBufferSize,
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-ac", "2",
+		"-ar", "44100",
+		"-movflags", "+faststart",
+		"-y", // Overwrite output file if it exists
+		outputPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logrus.Errorf("ffmpeg video conversion failed: %v, output: %s", err, string(output))
+		return "", pkgError.InternalServerError(fmt.Sprintf("failed to convert video: %v", err))
+	}
+
+	stat, err := os.Stat(outputPath)
+	if err != nil {
+		return "", pkgError.InternalServerError(fmt.Sprintf("failed to read converted video: %v", err))
+	}
+	if stat.Size() > config.WhatsappSettingMaxVideoSize {
+		return "", pkgError.ValidationError(fmt.Sprintf("converted video is %s, larger than the %s limit",
+			humanize.Bytes(uint64(stat.Size())), humanize.Bytes(uint64(config.WhatsappSettingMaxVideoSize))))
+	}
+
+	logrus.Infof("Converted %s %dx%d video to H.264/AAC MP4: %s", info.VideoCodec, info.Width, info.Height, humanize.Bytes(uint64(stat.Size())))
+	return outputPath, nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import "testing"
+
+func TestParseVideoInfo(t *testing.T) {
+	output := []byte(`{
+		"streams": [
+			{"codec_name": "hevc", "codec_type": "video", "width": 1920, "height": 1080},
+			{"codec_name": "opus", "codec_type": "audio"},
+			{"codec_name": "mov_text", "codec_type": "subtitle"}
+		],
+		"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2"}
+	}`)
+
+	info, err := parseVideoInfo(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := videoInfo{Format: "mov,mp4,m4a,3gp,3g2,mj2", VideoCodec: "hevc", AudioCodec: "opus", Width: 1920, Height: 1080}
+	if info != want {
+		t.Errorf("info = %+v, want %+v", info, want)
+	}
+
+	if _, err := parseVideoInfo([]byte(`{"streams": [{"codec_name": "mp3", "codec_type": "audio"}]}`)); err == nil {
+		t.Error("a file without video stream should be rejected")
+	}
+}
+
+func TestVideoInfoNeedsConversion(t *testing.T) {
+	playable := videoInfo{Format: "mov,mp4,m4a,3gp,3g2,mj2", VideoCodec: "h264", AudioCodec: "aac", Width: 1280, Height: 720}
+
+	tests := []struct {
+		name string
+		info func(videoInfo) videoInfo
+		want bool
+	}{
+		{"h264 aac mp4", func(info videoInfo) videoInfo { return info }, false},
+		{"no audio", func(info videoInfo) videoInfo { info.AudioCodec = ""; return info }, false},
+		{"portrait at the limit", func(info videoInfo) videoInfo { info.Width, info.Height = 720, 1280; return info }, false},
+		{"hevc", func(info videoInfo) videoInfo { info.VideoCodec = "hevc"; return info }, true},
+		{"opus audio", func(info videoInfo) videoInfo { info.AudioCodec = "opus"; return info }, true},
+		{"matroska", func(info videoInfo) videoInfo { info.Format = "matroska,webm"; return info }, true},
+		{"1080p", func(info videoInfo) videoInfo { info.Width, info.Height = 1920, 1080; return info }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info(playable).needsConversion(); got != tt.want {
+				t.Errorf("needsConversion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
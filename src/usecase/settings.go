@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSettings "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/settings"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// settingsMu serializes imports so two documents are never applied interleaved.
+// Only imports write the settings, so holding it is enough to read them.
+var settingsMu sync.Mutex
+
+type serviceSettings struct{}
+
+func NewSettingsService() domainSettings.ISettingsUsecase {
+	return &serviceSettings{}
+}
+
+func (service *serviceSettings) ExportSettings(_ context.Context) ([]byte, error) {
+	settingsMu.Lock()
+	current := currentSettings()
+	settingsMu.Unlock()
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(current); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (service *serviceSettings) ImportSettings(_ context.Context, request domainSettings.ImportSettingsRequest) (response domainSettings.ImportSettingsResponse, err error) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+
+	current := currentSettings()
+	// Decoding over the current settings keeps the value of every key the
+	// document leaves out; lists that are present replace the current list.
+	next := current
+	next.Version = 0
+	decoder := yaml.NewDecoder(bytes.NewReader(request.Document))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&next); err != nil {
+		if errors.Is(err, io.EOF) {
+			return response, pkgError.ValidationError("settings document is empty")
+		}
+		return response, pkgError.ValidationError(fmt.Sprintf("invalid settings document: %v", err))
+	}
+	if err := validateSettings(next); err != nil {
+		return response, err
+	}
+	next.Version = domainSettings.DocumentVersion
+
+	response.DryRun = request.DryRun
+	response.Changed = changedSettings(current, next)
+	if request.DryRun || len(response.Changed) == 0 {
+		return response, nil
+	}
+
+	applySettings(next)
+	logrus.WithField("changed", response.Changed).Info("Applied imported settings")
+	return response, nil
+}
+
+func currentSettings() domainSettings.Settings {
+	return domainSettings.Settings{
+		Version: domainSettings.DocumentVersion,
+		AutoReply: domainSettings.AutoReplySettings{
+			Message:            config.WhatsappAutoReplyMessage,
+			QuietHours:         config.WhatsappQuietHours,
+			QuietHoursTimezone: config.WhatsappQuietHoursTimezone,
+			QuietHoursReply:    config.WhatsappQuietHoursReply,
+		},
+		Webhook: domainSettings.WebhookSettings{
+			URLs:               slices.Clone(config.WhatsappWebhook),
+			Events:             slices.Clone(config.WhatsappWebhookEvents),
+			InsecureSkipVerify: config.WhatsappWebhookInsecureSkipVerify,
+			ChatOrdering:       config.WhatsappWebhookChatOrdering,
+			TenantRoutes:       slices.Clone(config.WhatsappTenantRoutes),
+			TenantWebhooks:     slices.Clone(config.WhatsappTenantWebhooks),
+			Format:             config.WhatsappWebhookFormat,
+			Identifier:         config.WhatsappWebhookIdentifier,
+			Templates:          slices.Clone(config.WhatsappWebhookTemplates),
+			MaxPayloadSizes:    slices.Clone(config.WhatsappWebhookMaxPayloadSizes),
+			Filters:            slices.Clone(config.WhatsappWebhookFilters),
+			SignatureAlgorithm: config.WhatsappWebhookSignatureAlgorithm,
+			Presence:           config.WhatsappWebhookPresence,
+			Outgoing:           config.WhatsappWebhookOutgoing,
+			OutgoingAPI:        config.WhatsappWebhookOutgoingAPI,
+		},
+		SendPolicy: domainSettings.SendPolicySettings{
+			Rules: slices.Clone(config.WhatsappSendPolicyRules),
+			URL:   config.WhatsappSendPolicyURL,
+		},
+		Behavior: domainSettings.BehaviorSettings{
+			AutoMarkRead:          config.WhatsappAutoMarkRead,
+			AutoDownloadMedia:     config.WhatsappAutoDownloadMedia,
+			AutoConvertVideo:      config.WhatsappSettingAutoConvertVideo,
+			LinkPreview:           config.WhatsappLinkPreview,
+			AutoRejectCall:        config.WhatsappAutoRejectCall,
+			AutoRejectCallMessage: config.WhatsappAutoRejectCallMessage,
+			AccountValidation:     config.WhatsappAccountValidation,
+			PresenceOnConnect:     config.WhatsappPresenceOnConnect,
+		},
+	}
+}
+
+func validateSettings(settings domainSettings.Settings) error {
+	if settings.Version != 0 && settings.Version != domainSettings.DocumentVersion {
+		return pkgError.ValidationError(fmt.Sprintf("unsupported settings version %d", settings.Version))
+	}
+	if settings.AutoReply.QuietHours != "" {
+		if _, err := whatsapp.ParseQuietHours(settings.AutoReply.QuietHours, settings.AutoReply.QuietHoursTimezone); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("auto_reply.quiet_hours: %v", err))
+		}
+	}
+	for _, webhookURL := range settings.Webhook.URLs {
+		if err := validateSettingsURL(webhookURL); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("webhook.urls: %v", err))
+		}
+	}
+	if err := whatsapp.ValidateTenantPairs(settings.Webhook.TenantRoutes); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("webhook.tenant_routes: %v", err))
+	}
+	if err := whatsapp.ValidateTenantPairs(settings.Webhook.TenantWebhooks); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("webhook.tenant_webhooks: %v", err))
+	}
+	switch settings.Webhook.Format {
+	case whatsapp.WebhookFormatDefault, whatsapp.WebhookFormatChatwoot:
+	default:
+		return pkgError.ValidationError(fmt.Sprintf("webhook.format: must be default or chatwoot, got %q", settings.Webhook.Format))
+	}
+	switch settings.Webhook.Identifier {
+	case whatsapp.WebhookIdentifierPN, whatsapp.WebhookIdentifierLID, whatsapp.WebhookIdentifierBoth:
+	default:
+		return pkgError.ValidationError(fmt.Sprintf("webhook.identifier: must be pn, lid or both, got %q", settings.Webhook.Identifier))
+	}
+	if err := whatsapp.ValidateWebhookTemplates(settings.Webhook.Templates); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("webhook.templates: %v", err))
+	}
+	if err := whatsapp.ValidateWebhookMaxPayloadSizes(settings.Webhook.MaxPayloadSizes); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("webhook.max_payload_sizes: %v", err))
+	}
+	if err := whatsapp.ValidateWebhookFilters(settings.Webhook.Filters); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("webhook.filters: %v", err))
+	}
+	if err := whatsapp.ValidateWebhookSignatureAlgorithm(settings.Webhook.SignatureAlgorithm); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("webhook.signature_algorithm: %v", err))
+	}
+	if err := whatsapp.ValidateSendPolicyRules(settings.SendPolicy.Rules); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("send_policy.rules: %v", err))
+	}
+	if settings.SendPolicy.URL != "" {
+		if err := validateSettingsURL(settings.SendPolicy.URL); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("send_policy.url: %v", err))
+		}
+	}
+	switch settings.Behavior.PresenceOnConnect {
+	case "available", "unavailable", "none":
+	default:
+		return pkgError.ValidationError(fmt.Sprintf("behavior.presence_on_connect: must be available, unavailable or none, got %q", settings.Behavior.PresenceOnConnect))
+	}
+	return nil
+}
+
+func validateSettingsURL(raw string) error {
+	parsed, err := url.ParseRequestURI(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%q is not an http(s) URL", raw)
+	}
+	return nil
+}
+
+// changedSettings returns the dotted keys whose values differ between a and b.
+func changedSettings(a, b domainSettings.Settings) []string {
+	changed := []string{}
+	var walk func(prefix string, va, vb reflect.Value)
+	walk = func(prefix string, va, vb reflect.Value) {
+		for i := 0; i < va.NumField(); i++ {
+			field := va.Type().Field(i)
+			key := field.Tag.Get("yaml")
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			fa, fb := va.Field(i), vb.Field(i)
+			if fa.Kind() == reflect.Struct {
+				walk(key, fa, fb)
+				continue
+			}
+			if fa.Kind() == reflect.Slice && fa.Len() == 0 && fb.Len() == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+				changed = append(changed, key)
+			}
+		}
+	}
+	walk("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return changed
+}
+
+// applySettings swaps the runtime settings under config.SettingsMu, so events
+// and requests in flight never read a half-written value.
+func applySettings(settings domainSettings.Settings) {
+	config.SettingsMu.Lock()
+	defer config.SettingsMu.Unlock()
+
+	config.WhatsappAutoReplyMessage = settings.AutoReply.Message
+	config.WhatsappQuietHours = settings.AutoReply.QuietHours
+	config.WhatsappQuietHoursTimezone = settings.AutoReply.QuietHoursTimezone
+	config.WhatsappQuietHoursReply = settings.AutoReply.QuietHoursReply
+
+	config.WhatsappWebhook = settings.Webhook.URLs
+	config.WhatsappWebhookEvents = settings.Webhook.Events
+	config.WhatsappWebhookInsecureSkipVerify = settings.Webhook.InsecureSkipVerify
+	config.WhatsappWebhookChatOrdering = settings.Webhook.ChatOrdering
+	config.WhatsappTenantRoutes = settings.Webhook.TenantRoutes
+	config.WhatsappTenantWebhooks = settings.Webhook.TenantWebhooks
+	config.WhatsappWebhookFormat = settings.Webhook.Format
+	config.WhatsappWebhookIdentifier = settings.Webhook.Identifier
+	config.WhatsappWebhookTemplates = settings.Webhook.Templates
+	config.WhatsappWebhookMaxPayloadSizes = settings.Webhook.MaxPayloadSizes
+	config.WhatsappWebhookFilters = settings.Webhook.Filters
+	config.WhatsappWebhookSignatureAlgorithm = settings.Webhook.SignatureAlgorithm
+	config.WhatsappWebhookPresence = settings.Webhook.Presence
+	config.WhatsappWebhookOutgoing = settings.Webhook.Outgoing
+	config.WhatsappWebhookOutgoingAPI = settings.Webhook.OutgoingAPI
+
+	config.WhatsappSendPolicyRules = settings.SendPolicy.Rules
+	config.WhatsappSendPolicyURL = settings.SendPolicy.URL
+
+	config.WhatsappAutoMarkRead = settings.Behavior.AutoMarkRead
+	config.WhatsappAutoDownloadMedia = settings.Behavior.AutoDownloadMedia
+	config.WhatsappSettingAutoConvertVideo = settings.Behavior.AutoConvertVideo
+	config.WhatsappLinkPreview = settings.Behavior.LinkPreview
+	config.WhatsappAutoRejectCall = settings.Behavior.AutoRejectCall
+	config.WhatsappAutoRejectCallMessage = settings.Behavior.AutoRejectCallMessage
+	config.WhatsappAccountValidation = settings.Behavior.AccountValidation
+	config.WhatsappPresenceOnConnect = settings.Behavior.PresenceOnConnect
+}
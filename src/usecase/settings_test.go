@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSettings "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/settings"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// preserveSettings restores every setting the import can change.
+func preserveSettings(t *testing.T) {
+	t.Helper()
+	saved := currentSettings()
+	t.Cleanup(func() { applySettings(saved) })
+}
+
+func TestSettingsExportImportRoundTrip(t *testing.T) {
+	preserveSettings(t)
+	service := NewSettingsService()
+	ctx := context.Background()
+
+	config.WhatsappWebhook = []string{"https://staging.example.com/hook"}
+	config.WhatsappAutoReplyMessage = "We will get back to you"
+	config.WhatsappSendPolicyRules = []string{`block:(?i)password`}
+	document, err := service.ExportSettings(ctx)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if !strings.Contains(string(document), "version: 1") || strings.Contains(string(document), config.WhatsappWebhookSecret) {
+		t.Fatalf("unexpected export:\n%s", document)
+	}
+
+	config.WhatsappWebhook = nil
+	config.WhatsappAutoReplyMessage = ""
+	config.WhatsappSendPolicyRules = nil
+	response, err := service.ImportSettings(ctx, domainSettings.ImportSettingsRequest{Document: document})
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	want := []string{"auto_reply.message", "webhook.urls", "send_policy.rules"}
+	if !slices.Equal(response.Changed, want) {
+		t.Errorf("changed = %v, want %v", response.Changed, want)
+	}
+	if !slices.Equal(config.WhatsappWebhook, []string{"https://staging.example.com/hook"}) || config.WhatsappAutoReplyMessage != "We will get back to you" {
+		t.Errorf("settings not applied: webhook=%v auto_reply=%q", config.WhatsappWebhook, config.WhatsappAutoReplyMessage)
+	}
+}
+
+func TestSettingsImportKeepsMissingKeysAndSupportsDryRun(t *testing.T) {
+	preserveSettings(t)
+	service := NewSettingsService()
+	config.WhatsappAutoMarkRead = false
+	config.WhatsappAutoReplyMessage = "unchanged"
+
+	document := []byte("behavior:\n  auto_mark_read: true\n")
+	response, err := service.ImportSettings(context.Background(), domainSettings.ImportSettingsRequest{Document: document, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !slices.Equal(response.Changed, []string{"behavior.auto_mark_read"}) || config.WhatsappAutoMarkRead {
+		t.Fatalf("dry run changed = %v, auto_mark_read = %v", response.Changed, config.WhatsappAutoMarkRead)
+	}
+
+	if _, err := service.ImportSettings(context.Background(), domainSettings.ImportSettingsRequest{Document: document}); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if !config.WhatsappAutoMarkRead || config.WhatsappAutoReplyMessage != "unchanged" {
+		t.Errorf("auto_mark_read = %v, auto_reply = %q", config.WhatsappAutoMarkRead, config.WhatsappAutoReplyMessage)
+	}
+}
+
+func TestSettingsImportRejectsInvalidDocuments(t *testing.T) {
+	preserveSettings(t)
+	service := NewSettingsService()
+	config.WhatsappWebhook = []string{"https://keep.example.com"}
+
+	for name, document := range map[string]string{
+		"empty":            "",
+		"unknown key":      "webhook:\n  url: https://example.com\n",
+		"bad version":      "version: 2\n",
+		"bad webhook url":  "webhook:\n  urls: [not-a-url]\n",
+		"bad policy rule":  "send_policy:\n  rules: ['drop:x']\n",
+		"bad quiet hours":  "auto_reply:\n  quiet_hours: 25:00-07:00\n",
+		"bad tenant route": "webhook:\n  tenant_routes: [acme]\n",
+		"bad format":       "webhook:\n  format: xml\n",
+		"bad identifier":   "webhook:\n  identifier: phone\n",
+		"missing template": "webhook:\n  templates: ['https://example.com=/nonexistent.tmpl']\n",
+		"bad payload size": "webhook:\n  max_payload_sizes: ['https://example.com=1MB']\n",
+		"bad algorithm":    "webhook:\n  signature_algorithm: md5\n",
+		"bad filter":       "webhook:\n  filters: ['deny:chat_type=channel']\n",
+		"bad presence":     "behavior:\n  presence_on_connect: away\n",
+	} {
+		if _, err := service.ImportSettings(context.Background(), domainSettings.ImportSettingsRequest{Document: []byte(document)}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if !slices.Equal(config.WhatsappWebhook, []string{"https://keep.example.com"}) {
+		t.Errorf("rejected documents must not change settings, webhook = %v", config.WhatsappWebhook)
+	}
+}
+
+// Run with -race: an import must not race with events being forwarded.
+func TestSettingsImportWhileForwardingEvents(t *testing.T) {
+	preserveSettings(t)
+	var posted atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		posted.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config.WhatsappWebhook = []string{server.URL}
+	config.WhatsappWebhookOutgoingAPI = true
+	service := NewSettingsService()
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 50 {
+			document := fmt.Sprintf("webhook:\n  events: [message, message.outgoing]\n  filters: ['deny:chat=%d@s.whatsapp.net']\n  tenant_routes: ['acme=%d@s.whatsapp.net']\nsend_policy:\n  rules: ['block:secret%d']\n", i, i, i)
+			if _, err := service.ImportSettings(ctx, domainSettings.ImportSettingsRequest{Document: []byte(document)}); err != nil {
+				t.Errorf("import failed: %v", err)
+				return
+			}
+		}
+	}()
+
+	sender := types.NewJID("628000", types.DefaultUserServer)
+	client := &whatsmeow.Client{Store: &store.Device{ID: &sender}}
+	recipient := types.NewJID("628111", types.DefaultUserServer)
+	const events = 50
+	for i := range events {
+		msg := &waE2E.Message{Conversation: proto.String("hello")}
+		if _, err := whatsapp.ApplySendPolicy(ctx, recipient, msg, "hello"); err != nil {
+			t.Fatalf("send policy failed: %v", err)
+		}
+		resp := whatsmeow.SendResponse{ID: types.MessageID(fmt.Sprintf("MSG%d", i)), Timestamp: time.Now()}
+		whatsapp.ForwardSentMessageToWebhook(ctx, client, recipient, resp, msg)
+	}
+	<-done
+
+	deadline := time.Now().Add(10 * time.Second)
+	for posted.Load() < events && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := posted.Load(); got != events {
+		t.Errorf("posted %d events, want %d", got, events)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"strings"
 	"sync"
 	"time"
 
@@ -456,3 +457,114 @@ func (service serviceUser) BusinessProfile(ctx context.Context, request domainUs
 
 	return response, nil
 }
+
+func (service serviceUser) BusinessCatalog(ctx context.Context, request domainUser.BusinessCatalogRequest) (response domainUser.BusinessCatalogResponse, err error) {
+	err = validations.ValidateBusinessCatalog(ctx, &request)
+	if err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	owner := client.Store.GetJID().ToNonAD()
+	if request.Phone != "" {
+		owner, err = utils.ValidateAndNormalizeJID(client, request.Phone)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	catalog, err := whatsapp.GetBusinessCatalog(ctx, client, owner, request.Limit, request.Cursor)
+	if err != nil {
+		return response, err
+	}
+
+	response.JID = owner.String()
+	response.Products = make([]domainUser.BusinessCatalogProduct, 0, len(catalog.Products))
+	for _, product := range catalog.Products {
+		response.Products = append(response.Products, domainUser.BusinessCatalogProduct{
+			ID:              product.ID,
+			RetailerID:      product.RetailerID,
+			Name:            product.Name,
+			Description:     product.Description,
+			URL:             product.URL,
+			Currency:        product.Currency,
+			PriceAmount1000: product.PriceAmount1000,
+			ImageURL:        product.ImageURL,
+			IsHidden:        product.IsHidden,
+		})
+	}
+	response.NextCursor = catalog.NextCursor
+
+	return response, nil
+}
+
+func (service serviceUser) ResolveContact(ctx context.Context, request domainUser.ResolveContactRequest) (response domainUser.ResolveContactResponse, err error) {
+	err = validations.ValidateResolveContact(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	// The mapping store answers without a client, so a missing one is not an error.
+	client := whatsapp.ClientFromContext(ctx)
+	resolver := utils.LIDResolverFromContext(ctx)
+
+	if request.LID != "" {
+		lid := types.NewJID(strings.TrimSuffix(request.LID, "@"+types.HiddenUserServer), types.HiddenUserServer)
+		pn, source := resolver.PhoneForLID(ctx, lid, client)
+		if pn.IsEmpty() {
+			return response, pkgError.LIDMappingNotFoundError(fmt.Sprintf("no phone number known for LID %s", lid))
+		}
+		return domainUser.ResolveContactResponse{LID: lid.String(), Phone: pn.ToNonAD().String(), Source: source}, nil
+	}
+
+	pn, err := types.ParseJID(strings.TrimPrefix(request.Phone, "+"))
+	if err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
+	lid, source := resolver.LIDForPhone(ctx, pn, client)
+	if lid.IsEmpty() {
+		return response, pkgError.LIDMappingNotFoundError(fmt.Sprintf("no LID known for phone %s", pn))
+	}
+	return domainUser.ResolveContactResponse{LID: lid.ToNonAD().String(), Phone: pn.String(), Source: source}, nil
+}
+
+func (service serviceUser) ResolveContacts(ctx context.Context, request domainUser.ResolveContactsRequest) (response domainUser.ResolveContactsResponse, err error) {
+	err = validations.ValidateResolveContacts(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	jids := make([]types.JID, len(request.JIDs))
+	for i, value := range request.JIDs {
+		jids[i], err = types.ParseJID(strings.TrimPrefix(value, "+"))
+		if err != nil {
+			return response, pkgError.ValidationError(err.Error())
+		}
+	}
+
+	resolutions := utils.LIDResolverFromContext(ctx).ResolveMany(ctx, jids, whatsapp.ClientFromContext(ctx))
+	response.Items = make([]domainUser.ResolveContactsItem, len(resolutions))
+	for i, resolution := range resolutions {
+		item := domainUser.ResolveContactsItem{
+			JID:      request.JIDs[i],
+			Resolved: resolution.Resolved(),
+			CacheHit: resolution.CacheHit(),
+			Source:   resolution.Source,
+		}
+		if item.Resolved {
+			item.LID = resolution.LID.ToNonAD().String()
+			item.Phone = resolution.Phone.ToNonAD().String()
+			response.Resolved++
+		}
+		if item.CacheHit {
+			response.CacheHits++
+		}
+		response.Items[i] = item
+	}
+
+	return response, nil
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+func (service serviceChat) ListWebhookDeadLetters(ctx context.Context) (response domainChat.ListWebhookDeadLettersResponse, err error) {
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	deliveries, err := service.chatStorageRepo.ListDeadWebhookDeliveries(deviceID, 100)
+	if err != nil {
+		return response, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+
+	response.Data = make([]domainChat.WebhookDeadLetter, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		payload := json.RawMessage(delivery.PayloadJSON)
+		if !json.Valid(payload) {
+			payload = nil
+		}
+		response.Data = append(response.Data, domainChat.WebhookDeadLetter{
+			ID:        delivery.ID,
+			URL:       delivery.URL,
+			Event:     delivery.EventName,
+			Attempts:  delivery.Attempts,
+			LastError: delivery.LastError,
+			Payload:   payload,
+			CreatedAt: delivery.CreatedAt.Format(time.RFC3339),
+			DeadAt:    delivery.DeadAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+func (service serviceChat) RetryWebhookDeadLetter(ctx context.Context, request domainChat.RetryWebhookDeadLetterRequest) (response domainChat.RetryWebhookDeadLetterResponse, err error) {
+	if err = validations.ValidateRetryWebhookDeadLetter(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	requeued, err := service.chatStorageRepo.RequeueWebhookDelivery(deviceID, request.DeliveryID)
+	if err != nil {
+		return response, fmt.Errorf("failed to requeue webhook delivery: %w", err)
+	}
+	if !requeued {
+		return response, fmt.Errorf("webhook dead letter %d not found", request.DeliveryID)
+	}
+
+	response.Status = "success"
+	response.Message = "Webhook delivery queued for retry"
+	response.DeliveryID = request.DeliveryID
+	return response, nil
+}
+
+func (service serviceChat) ReplayWebhookDeadLetters(ctx context.Context, request domainChat.ReplayWebhookDeadLettersRequest) (response domainChat.ReplayWebhookDeadLettersResponse, err error) {
+	if err = validations.ValidateReplayWebhookDeadLetters(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	since, _ := time.Parse(time.RFC3339, request.Since)
+	replayed, err := service.chatStorageRepo.RequeueDeadWebhookDeliveries(deviceID, since)
+	if err != nil {
+		return response, fmt.Errorf("failed to requeue webhook dead letters: %w", err)
+	}
+
+	response.Status = "success"
+	response.Message = fmt.Sprintf("%d webhook deliveries queued for retry", replayed)
+	response.Replayed = replayed
+	return response, nil
+}
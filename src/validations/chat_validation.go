@@ -2,6 +2,9 @@ package validations
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
+	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -42,6 +45,25 @@ func ValidateGetChatMessages(ctx context.Context, request *domainChat.GetChatMes
 		return pkgError.ValidationError(err.Error())
 	}
 
+	if request.Cursor != "" && request.Offset > 0 {
+		return pkgError.ValidationError("cursor: cannot be combined with offset.")
+	}
+
+	return nil
+}
+
+func ValidateGetMessageContext(ctx context.Context, request *domainChat.GetMessageContextRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.Before, validation.Min(0), validation.Max(100)),
+		validation.Field(&request.After, validation.Min(0), validation.Max(100)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
 	return nil
 }
 
@@ -102,3 +124,162 @@ func ValidateArchiveChat(ctx context.Context, request *domainChat.ArchiveChatReq
 
 	return nil
 }
+
+func ValidateMuteChat(ctx context.Context, request *domainChat.MuteChatRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.Duration, validation.Min(int64(0))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateMarkChatRead(ctx context.Context, request *domainChat.MarkChatReadRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateExportChat(ctx context.Context, request *domainChat.ExportChatRequest) error {
+	// Default to JSON when no format is requested
+	if request.Format == "" {
+		request.Format = domainChat.ExportFormatJSON
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.Format, validation.In(domainChat.ExportFormatJSON, domainChat.ExportFormatCSV, domainChat.ExportFormatTXT)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateImportChat(ctx context.Context, request *domainChat.ImportChatRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.File == nil {
+		return pkgError.ValidationError("file: cannot be blank.")
+	}
+	ext := strings.ToLower(filepath.Ext(request.File.Filename))
+	if ext != ".txt" && ext != ".zip" {
+		return pkgError.ValidationError("file: must be a WhatsApp chat export (.txt or .zip)")
+	}
+
+	return nil
+}
+
+func ValidateDeleteChat(ctx context.Context, request *domainChat.DeleteChatRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateMergeChat(ctx context.Context, request *domainChat.MergeChatRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.TargetJID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.ChatJID == request.TargetJID {
+		return pkgError.ValidationError("target_jid: must differ from chat_jid.")
+	}
+
+	return nil
+}
+
+func ValidateRestoreChat(ctx context.Context, request *domainChat.RestoreChatRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateRetryWebhookDeadLetter(ctx context.Context, request *domainChat.RetryWebhookDeadLetterRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.DeliveryID, validation.Required, validation.Min(int64(1))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateReplayWebhookDeadLetters(ctx context.Context, request *domainChat.ReplayWebhookDeadLettersRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Since, validation.Required, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetChatStats(ctx context.Context, request *domainChat.ChatStatsRequest) error {
+	// Default to the last 30 days
+	if request.Days == 0 {
+		request.Days = 30
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.Days, validation.Min(1), validation.Max(366)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateLabelChat(ctx context.Context, request *domainChat.LabelChatRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.LabelID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package validations
 
 import (
 	"context"
+	"mime/multipart"
 	"testing"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
@@ -131,6 +132,16 @@ func TestValidateGetChatMessages(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("offset: must be no less than 0."),
 		},
+		{
+			name: "should error when cursor is combined with offset",
+			args: args{request: domainChat.GetChatMessagesRequest{
+				ChatJID: "628123456789@s.whatsapp.net",
+				Limit:   50,
+				Offset:  10,
+				Cursor:  "abc",
+			}},
+			err: pkgError.ValidationError("cursor: cannot be combined with offset."),
+		},
 	}
 
 	for _, tt := range tests {
@@ -250,3 +261,187 @@ func TestValidateSetDisappearingTimer(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExportChat(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    domainChat.ExportChatRequest
+		err        any
+		wantFormat string
+	}{
+		{
+			name:       "should default format to json",
+			request:    domainChat.ExportChatRequest{ChatJID: "628123456789@s.whatsapp.net"},
+			err:        nil,
+			wantFormat: domainChat.ExportFormatJSON,
+		},
+		{
+			name:       "should success with txt format",
+			request:    domainChat.ExportChatRequest{ChatJID: "628123456789@s.whatsapp.net", Format: domainChat.ExportFormatTXT},
+			err:        nil,
+			wantFormat: domainChat.ExportFormatTXT,
+		},
+		{
+			name:       "should error with unsupported format",
+			request:    domainChat.ExportChatRequest{ChatJID: "628123456789@s.whatsapp.net", Format: "xml"},
+			err:        pkgError.ValidationError("format: must be a valid value."),
+			wantFormat: "xml",
+		},
+		{
+			name:       "should error without chat jid",
+			request:    domainChat.ExportChatRequest{Format: domainChat.ExportFormatCSV},
+			err:        pkgError.ValidationError("chat_jid: cannot be blank."),
+			wantFormat: domainChat.ExportFormatCSV,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExportChat(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantFormat, tt.request.Format)
+		})
+	}
+}
+
+func TestValidateDeleteAndRestoreChat(t *testing.T) {
+	assert.NoError(t, ValidateDeleteChat(context.Background(), &domainChat.DeleteChatRequest{ChatJID: "628123456789@s.whatsapp.net"}))
+	assert.Equal(t, pkgError.ValidationError("chat_jid: cannot be blank."), ValidateDeleteChat(context.Background(), &domainChat.DeleteChatRequest{}))
+	assert.NoError(t, ValidateRestoreChat(context.Background(), &domainChat.RestoreChatRequest{ChatJID: "628123456789@s.whatsapp.net"}))
+	assert.Equal(t, pkgError.ValidationError("chat_jid: cannot be blank."), ValidateRestoreChat(context.Background(), &domainChat.RestoreChatRequest{}))
+}
+
+func TestValidateGetChatStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  domainChat.ChatStatsRequest
+		err      any
+		wantDays int
+	}{
+		{
+			name:     "should default days to 30",
+			request:  domainChat.ChatStatsRequest{ChatJID: "628123456789@s.whatsapp.net"},
+			err:      nil,
+			wantDays: 30,
+		},
+		{
+			name:     "should error with more than a year of days",
+			request:  domainChat.ChatStatsRequest{ChatJID: "628123456789@s.whatsapp.net", Days: 400},
+			err:      pkgError.ValidationError("days: must be no greater than 366."),
+			wantDays: 400,
+		},
+		{
+			name:     "should error without chat jid",
+			request:  domainChat.ChatStatsRequest{Days: 7},
+			err:      pkgError.ValidationError("chat_jid: cannot be blank."),
+			wantDays: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetChatStats(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantDays, tt.request.Days)
+		})
+	}
+}
+
+func TestValidateMuteChat(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainChat.MuteChatRequest
+		err     any
+	}{
+		{
+			name:    "should success muting forever",
+			request: domainChat.MuteChatRequest{ChatJID: "628123456789@s.whatsapp.net", Muted: true},
+			err:     nil,
+		},
+		{
+			name:    "should success muting with duration",
+			request: domainChat.MuteChatRequest{ChatJID: "628123456789@s.whatsapp.net", Muted: true, Duration: 28800},
+			err:     nil,
+		},
+		{
+			name:    "should error with negative duration",
+			request: domainChat.MuteChatRequest{ChatJID: "628123456789@s.whatsapp.net", Muted: true, Duration: -1},
+			err:     pkgError.ValidationError("duration: must be no less than 0."),
+		},
+		{
+			name:    "should error without chat jid",
+			request: domainChat.MuteChatRequest{Muted: true},
+			err:     pkgError.ValidationError("chat_jid: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMuteChat(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateMarkChatRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainChat.MarkChatReadRequest
+		err     any
+	}{
+		{
+			name:    "should success marking as unread",
+			request: domainChat.MarkChatReadRequest{ChatJID: "628123456789@s.whatsapp.net"},
+			err:     nil,
+		},
+		{
+			name:    "should error without chat jid",
+			request: domainChat.MarkChatReadRequest{Read: true},
+			err:     pkgError.ValidationError("chat_jid: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMarkChatRead(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateImportChat(t *testing.T) {
+	chatJID := "628123456789@s.whatsapp.net"
+	assert.NoError(t, ValidateImportChat(context.Background(), &domainChat.ImportChatRequest{
+		ChatJID: chatJID, File: &multipart.FileHeader{Filename: "WhatsApp Chat with Alice.txt"},
+	}))
+	assert.NoError(t, ValidateImportChat(context.Background(), &domainChat.ImportChatRequest{
+		ChatJID: chatJID, File: &multipart.FileHeader{Filename: "export.ZIP"},
+	}))
+	assert.Equal(t, pkgError.ValidationError("chat_jid: cannot be blank."), ValidateImportChat(context.Background(), &domainChat.ImportChatRequest{
+		File: &multipart.FileHeader{Filename: "chat.txt"},
+	}))
+	assert.Equal(t, pkgError.ValidationError("file: cannot be blank."), ValidateImportChat(context.Background(), &domainChat.ImportChatRequest{ChatJID: chatJID}))
+	assert.Equal(t, pkgError.ValidationError("file: must be a WhatsApp chat export (.txt or .zip)"), ValidateImportChat(context.Background(), &domainChat.ImportChatRequest{
+		ChatJID: chatJID, File: &multipart.FileHeader{Filename: "chat.json"},
+	}))
+}
+
+func TestValidateMergeChat(t *testing.T) {
+	lidJID := "215946727821336@lid"
+	phoneJID := "628123456789@s.whatsapp.net"
+	assert.NoError(t, ValidateMergeChat(context.Background(), &domainChat.MergeChatRequest{ChatJID: lidJID, TargetJID: phoneJID}))
+	assert.Equal(t, pkgError.ValidationError("target_jid: cannot be blank."), ValidateMergeChat(context.Background(), &domainChat.MergeChatRequest{ChatJID: lidJID}))
+	assert.Equal(t, pkgError.ValidationError("target_jid: must differ from chat_jid."), ValidateMergeChat(context.Background(), &domainChat.MergeChatRequest{ChatJID: phoneJID, TargetJID: phoneJID}))
+}
+
+func TestValidateRetryWebhookDeadLetter(t *testing.T) {
+	assert.NoError(t, ValidateRetryWebhookDeadLetter(context.Background(), &domainChat.RetryWebhookDeadLetterRequest{DeliveryID: 7}))
+	assert.Equal(t, pkgError.ValidationError("delivery_id: cannot be blank."), ValidateRetryWebhookDeadLetter(context.Background(), &domainChat.RetryWebhookDeadLetterRequest{}))
+	assert.Error(t, ValidateRetryWebhookDeadLetter(context.Background(), &domainChat.RetryWebhookDeadLetterRequest{DeliveryID: -1}))
+}
+
+func TestValidateReplayWebhookDeadLetters(t *testing.T) {
+	assert.NoError(t, ValidateReplayWebhookDeadLetters(context.Background(), &domainChat.ReplayWebhookDeadLettersRequest{Since: "2026-01-19T08:00:00Z"}))
+	assert.Equal(t, pkgError.ValidationError("since: cannot be blank."), ValidateReplayWebhookDeadLetters(context.Background(), &domainChat.ReplayWebhookDeadLettersRequest{}))
+	assert.Error(t, ValidateReplayWebhookDeadLetters(context.Background(), &domainChat.ReplayWebhookDeadLettersRequest{Since: "yesterday"}))
+}
@@ -2,6 +2,7 @@ package validations
 
 import (
 	"context"
+	"fmt"
 
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -60,9 +61,76 @@ func ValidateReactMessage(ctx context.Context, request domainMessage.ReactionReq
 		return pkgError.ValidationError(err.Error())
 	}
 
+	// An empty emoji removes the reaction
+	if request.Emoji != "" && !isSingleEmoji(request.Emoji) {
+		return pkgError.ValidationError("emoji: must be a single emoji.")
+	}
+
 	return nil
 }
 
+// maxEmojiRunes bounds the code points of one emoji; the longest ZWJ
+// sequences (families and couples with skin tones) use around a dozen.
+const maxEmojiRunes = 16
+
+// isSingleEmoji reports whether s is exactly one emoji: a flag, a keycap, or
+// pictographs joined by zero width joiners, each optionally followed by a
+// variation selector, skin tone or tag sequence.
+func isSingleEmoji(s string) bool {
+	runes := []rune(s)
+	if len(runes) == 0 || len(runes) > maxEmojiRunes {
+		return false
+	}
+
+	switch first := runes[0]; {
+	case isRegionalIndicator(first):
+		return len(runes) == 2 && isRegionalIndicator(runes[1])
+	case first == '#' || first == '*' || (first >= '0' && first <= '9'):
+		rest := runes[1:]
+		if len(rest) > 0 && rest[0] == 0xFE0F {
+			rest = rest[1:]
+		}
+		return len(rest) == 1 && rest[0] == 0x20E3
+	}
+
+	expectPictograph := true
+	for _, r := range runes {
+		switch {
+		case expectPictograph:
+			if !isPictograph(r) {
+				return false
+			}
+			expectPictograph = false
+		case r == 0x200D: // zero width joiner
+			expectPictograph = true
+		case r == 0xFE0E || r == 0xFE0F, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+			// variation selectors, skin tones and tag sequences modify the pictograph
+		default:
+			return false
+		}
+	}
+	return !expectPictograph
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isPictograph reports whether r can start an emoji.
+func isPictograph(r rune) bool {
+	switch {
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		r == 0x24C2, r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	case r >= 0x2194 && r <= 0x21AA, r >= 0x231A && r <= 0x23FF, r >= 0x25AA && r <= 0x27BF,
+		r >= 0x2934 && r <= 0x2935, r >= 0x2B05 && r <= 0x2B55:
+		return true
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return !isRegionalIndicator(r) && !(r >= 0x1F3FB && r <= 0x1F3FF)
+	}
+	return false
+}
+
 func ValidateDeleteMessage(ctx context.Context, request domainMessage.DeleteRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -90,6 +158,23 @@ func ValidateStarMessage(ctx context.Context, request domainMessage.StarRequest)
 	return nil
 }
 
+func ValidatePinMessage(ctx context.Context, request domainMessage.PinMessageRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.Duration, validation.When(request.Pinned,
+			validation.In(0, domainMessage.PinDuration24Hours, domainMessage.PinDuration7Days, domainMessage.PinDuration30Days).
+				Error(fmt.Sprintf("must be %d (24 hours), %d (7 days) or %d (30 days)",
+					domainMessage.PinDuration24Hours, domainMessage.PinDuration7Days, domainMessage.PinDuration30Days)))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateDownloadMedia(ctx context.Context, request domainMessage.DownloadMediaRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -102,3 +187,44 @@ func ValidateDownloadMedia(ctx context.Context, request domainMessage.DownloadMe
 
 	return nil
 }
+
+func ValidateLabelMessage(ctx context.Context, request domainMessage.LabelMessageRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.LabelID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// ForwardMaxTargets is how many chats one message can be forwarded to at
+// once, as in the WhatsApp apps.
+const ForwardMaxTargets = 5
+
+func ValidateForwardMessage(ctx context.Context, request domainMessage.ForwardMessageRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.Targets, validation.Required, validation.Length(1, ForwardMaxTargets)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	seen := make(map[string]bool, len(request.Targets))
+	for i, target := range request.Targets {
+		if err := validatePhoneNumber(target); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("targets[%d]: %v", i, err))
+		}
+		if seen[target] {
+			return pkgError.ValidationError(fmt.Sprintf("targets[%d]: %s is listed more than once", i, target))
+		}
+		seen[target] = true
+	}
+	return nil
+}
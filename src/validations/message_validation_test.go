@@ -130,6 +130,33 @@ func TestValidateReactMessage(t *testing.T) {
 			}},
 			err: nil,
 		},
+		{
+			name: "should success with ZWJ sequence emoji",
+			args: args{request: domainMessage.ReactionRequest{
+				Phone:     "6281234567890@s.whatsapp.net",
+				MessageID: "3EB0789ABC123456",
+				Emoji:     "👩🏽‍💻",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with text instead of emoji",
+			args: args{request: domainMessage.ReactionRequest{
+				Phone:     "6281234567890@s.whatsapp.net",
+				MessageID: "3EB0789ABC123456",
+				Emoji:     "ok",
+			}},
+			err: pkgError.ValidationError("emoji: must be a single emoji."),
+		},
+		{
+			name: "should error with two emoji",
+			args: args{request: domainMessage.ReactionRequest{
+				Phone:     "6281234567890@s.whatsapp.net",
+				MessageID: "3EB0789ABC123456",
+				Emoji:     "👍👍",
+			}},
+			err: pkgError.ValidationError("emoji: must be a single emoji."),
+		},
 		{
 			name: "should error with all empty fields",
 			args: args{request: domainMessage.ReactionRequest{
@@ -157,6 +184,57 @@ func TestValidateReactMessage(t *testing.T) {
 	}
 }
 
+func TestValidatePinMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainMessage.PinMessageRequest
+		err     string
+	}{
+		{
+			name:    "should success pinning with default duration",
+			request: domainMessage.PinMessageRequest{Phone: "6281234567890@s.whatsapp.net", MessageID: "3EB0789ABC123456", Pinned: true},
+		},
+		{
+			name:    "should success pinning for 30 days",
+			request: domainMessage.PinMessageRequest{Phone: "6281234567890@s.whatsapp.net", MessageID: "3EB0789ABC123456", Pinned: true, Duration: domainMessage.PinDuration30Days},
+		},
+		{
+			name:    "should error pinning for an unsupported duration",
+			request: domainMessage.PinMessageRequest{Phone: "6281234567890@s.whatsapp.net", MessageID: "3EB0789ABC123456", Pinned: true, Duration: 3600},
+			err:     "duration: must be 86400 (24 hours), 604800 (7 days) or 2592000 (30 days).",
+		},
+		{
+			name:    "should ignore duration when unpinning",
+			request: domainMessage.PinMessageRequest{Phone: "6281234567890@s.whatsapp.net", MessageID: "3EB0789ABC123456", Duration: 3600},
+		},
+		{
+			name:    "should error with empty message id",
+			request: domainMessage.PinMessageRequest{Phone: "6281234567890@s.whatsapp.net", Pinned: true},
+			err:     "message_id: cannot be blank.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePinMessage(context.Background(), tt.request)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, pkgError.ValidationError(tt.err), err)
+			}
+		})
+	}
+}
+
+func TestIsSingleEmoji(t *testing.T) {
+	for _, emoji := range []string{"👍", "❤️", "🇮🇩", "1️⃣", "#⃣", "👍🏾", "👨‍👩‍👧‍👦", "🏴󠁧󠁢󠁥󠁮󠁧󠁿", "©️"} {
+		assert.True(t, isSingleEmoji(emoji), emoji)
+	}
+	for _, emoji := range []string{"", "a", "1", "🇮", "🇮🇩🇮🇩", "👍 ", "👍‍", "🏾", "👍a"} {
+		assert.False(t, isSingleEmoji(emoji), emoji)
+	}
+}
+
 func TestValidateDeleteMessage(t *testing.T) {
 	type args struct {
 		request domainMessage.DeleteRequest
@@ -290,3 +368,38 @@ func TestValidateStarMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateForwardMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainMessage.ForwardMessageRequest
+		err     any
+	}{
+		{
+			name:    "should success with two targets",
+			request: domainMessage.ForwardMessageRequest{MessageID: "3EB0789ABC123456", Phone: "6281234567890@s.whatsapp.net", Targets: []string{"6289876543210", "120363000000000001@g.us"}},
+		},
+		{
+			name:    "should error without targets",
+			request: domainMessage.ForwardMessageRequest{MessageID: "3EB0789ABC123456", Phone: "6281234567890@s.whatsapp.net"},
+			err:     pkgError.ValidationError("targets: cannot be blank."),
+		},
+		{
+			name: "should error above the target limit",
+			request: domainMessage.ForwardMessageRequest{MessageID: "3EB0789ABC123456", Phone: "6281234567890@s.whatsapp.net",
+				Targets: []string{"6281", "6282", "6283", "6284", "6285", "6286"}},
+			err: pkgError.ValidationError("targets: the length must be between 1 and 5."),
+		},
+		{
+			name:    "should error with a duplicate target",
+			request: domainMessage.ForwardMessageRequest{MessageID: "3EB0789ABC123456", Phone: "6281234567890@s.whatsapp.net", Targets: []string{"6281", "6281"}},
+			err:     pkgError.ValidationError("targets[1]: 6281 is listed more than once"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, ValidateForwardMessage(context.Background(), tt.request))
+		})
+	}
+}
@@ -18,3 +18,21 @@ func ValidateUnfollowNewsletter(ctx context.Context, request domainNewsletter.Un
 
 	return nil
 }
+
+func ValidateGetNewsletterMessages(ctx context.Context, request *domainNewsletter.GetMessagesRequest) error {
+	if request.Count == 0 {
+		request.Count = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.NewsletterID, validation.Required),
+		validation.Field(&request.Count, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Before, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -48,3 +48,38 @@ func TestValidateUnfollowNewsletter(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGetNewsletterMessages(t *testing.T) {
+	request := domainNewsletter.GetMessagesRequest{NewsletterID: "120363123456789@newsletter"}
+	assert.NoError(t, ValidateGetNewsletterMessages(context.Background(), &request))
+	assert.Equal(t, 50, request.Count, "count should default to 50")
+
+	tests := []struct {
+		name    string
+		request domainNewsletter.GetMessagesRequest
+		err     any
+	}{
+		{
+			name:    "should error with empty newsletter id",
+			request: domainNewsletter.GetMessagesRequest{Count: 10},
+			err:     pkgError.ValidationError("newsletter_id: cannot be blank."),
+		},
+		{
+			name:    "should error with count above 100",
+			request: domainNewsletter.GetMessagesRequest{NewsletterID: "120363123456789@newsletter", Count: 101},
+			err:     pkgError.ValidationError("count: must be no greater than 100."),
+		},
+		{
+			name:    "should error with negative before",
+			request: domainNewsletter.GetMessagesRequest{NewsletterID: "120363123456789@newsletter", Before: -1},
+			err:     pkgError.ValidationError("before: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetNewsletterMessages(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
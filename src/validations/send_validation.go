@@ -3,6 +3,7 @@ package validations
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/dustin/go-humanize"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // ValidDurationValues contains WhatsApp's allowed disappearing message durations in seconds.
@@ -83,8 +85,11 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 
 	// Validate mentions if provided
 	for _, mention := range request.Mentions {
-		// Skip validation for special @everyone keyword
-		if mention == "@everyone" {
+		// "@everyone" and "all" mention every participant, so only groups accept them
+		if domainSend.IsMentionEveryone(mention) {
+			if !strings.HasSuffix(strings.TrimSpace(request.Phone), "@"+types.GroupServer) {
+				return pkgError.ValidationError(fmt.Sprintf("mention %s: only allowed when sending to a group", mention))
+			}
 			continue
 		}
 		if err := validatePhoneNumber(mention); err != nil {
@@ -298,7 +303,14 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 	return nil
 }
 
+// ContactMaxCards bounds the cards of one contacts array message.
+const ContactMaxCards = 50
+
 func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest) error {
+	if len(request.Contacts) > 0 {
+		return validateSendContactCards(ctx, request)
+	}
+
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
 		validation.Field(&request.ContactPhone, validation.Required),
@@ -326,6 +338,43 @@ func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest)
 	return nil
 }
 
+func validateSendContactCards(ctx context.Context, request domainSend.ContactRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.ContactName, validation.Empty.Error("must be blank when contacts is set")),
+		validation.Field(&request.ContactPhone, validation.Empty.Error("must be blank when contacts is set")),
+		validation.Field(&request.Contacts, validation.Length(1, ContactMaxCards)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	for i, card := range request.Contacts {
+		if strings.TrimSpace(card.Name) == "" {
+			return pkgError.ValidationError(fmt.Sprintf("contacts[%d].name: cannot be blank.", i))
+		}
+		if len(card.Phones) == 0 {
+			return pkgError.ValidationError(fmt.Sprintf("contacts[%d].phones: cannot be blank.", i))
+		}
+		for j, phone := range card.Phones {
+			if err := validatePhoneNumber(phone); err != nil {
+				return pkgError.ValidationError(fmt.Sprintf("contacts[%d].phones[%d]: %v", i, j, err))
+			}
+		}
+		for j, email := range card.Emails {
+			if err := validation.Validate(email, validation.Required, is.EmailFormat); err != nil {
+				return pkgError.ValidationError(fmt.Sprintf("contacts[%d].emails[%d]: %v", i, j, err))
+			}
+		}
+	}
+
+	return validateDuration(request.Duration)
+}
+
 func ValidateSendLink(ctx context.Context, request domainSend.LinkRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -372,6 +421,38 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 	return nil
 }
 
+// Live location share durations
+const (
+	LiveLocationDefaultDuration = 15 * 60
+	LiveLocationMinDuration     = 60
+	LiveLocationMaxDuration     = 8 * 60 * 60
+)
+
+func ValidateSendLiveLocation(ctx context.Context, request *domainSend.LiveLocationRequest) error {
+	sharing := request.Action != domainSend.LiveLocationStop
+	if request.Action == domainSend.LiveLocationStart && request.DurationSeconds == 0 {
+		request.DurationSeconds = LiveLocationDefaultDuration
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Action, validation.Required, validation.In(domainSend.LiveLocationStart, domainSend.LiveLocationUpdate, domainSend.LiveLocationStop)),
+		validation.Field(&request.Latitude, validation.When(sharing, validation.Required, is.Latitude)),
+		validation.Field(&request.Longitude, validation.When(sharing, validation.Required, is.Longitude)),
+		validation.Field(&request.DurationSeconds, validation.When(request.Action == domainSend.LiveLocationStart,
+			validation.Min(LiveLocationMinDuration), validation.Max(LiveLocationMaxDuration))),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	return validateDuration(request.Duration)
+}
+
 func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -489,6 +570,35 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 	return nil
 }
 
+// Product message text limits, as the WhatsApp apps enforce them.
+const (
+	ProductBodyMaxLength   = 1024
+	ProductFooterMaxLength = 60
+)
+
+func ValidateSendProduct(ctx context.Context, request domainSend.ProductRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.ProductID, is.Digit),
+		validation.Field(&request.Body, validation.RuneLength(0, ProductBodyMaxLength)),
+		validation.Field(&request.Footer, validation.RuneLength(0, ProductFooterMaxLength)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	if err := validateDuration(request.Duration); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func ValidateSendPresence(ctx context.Context, request domainSend.PresenceRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Type, validation.In("available", "unavailable")),
@@ -518,3 +628,127 @@ func ValidateSendChatPresence(ctx context.Context, request domainSend.ChatPresen
 
 	return nil
 }
+
+// Campaign limits
+const (
+	CampaignMaxRecipients        = 5000
+	CampaignDefaultRatePerMinute = 20
+	CampaignMaxRatePerMinute     = 60
+	CampaignMaxJitterSeconds     = 300
+)
+
+func ValidateSendCampaign(ctx context.Context, request *domainSend.CampaignRequest) error {
+	if request.RatePerMinute == 0 {
+		request.RatePerMinute = CampaignDefaultRatePerMinute
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Message, validation.When(request.Template == "", validation.Required.Error("either message or template is required")),
+			validation.When(request.Template != "", validation.Empty.Error("cannot be combined with template"))),
+		validation.Field(&request.Recipients, validation.Required, validation.Length(1, CampaignMaxRecipients)),
+		validation.Field(&request.RatePerMinute, validation.Min(1), validation.Max(CampaignMaxRatePerMinute)),
+		validation.Field(&request.JitterSeconds, validation.Min(0), validation.Max(CampaignMaxJitterSeconds)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	seen := make(map[string]bool, len(request.Recipients))
+	for i, recipient := range request.Recipients {
+		if err := validatePhoneNumber(recipient.Phone); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("recipients[%d]: %v", i, err))
+		}
+		if seen[recipient.Phone] {
+			return pkgError.ValidationError(fmt.Sprintf("recipients[%d]: %s is listed more than once", i, recipient.Phone))
+		}
+		seen[recipient.Phone] = true
+	}
+	return nil
+}
+
+// Album limits
+const (
+	AlbumMinItems = 2
+	AlbumMaxItems = 30
+)
+
+func ValidateSendAlbum(ctx context.Context, request domainSend.AlbumRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Items, validation.Required, validation.Length(AlbumMinItems, AlbumMaxItems)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	for i, item := range request.Items {
+		if item.Type != domainSend.AlbumItemImage && item.Type != domainSend.AlbumItemVideo {
+			return pkgError.ValidationError(fmt.Sprintf("items[%d].type: must be image or video", i))
+		}
+		hasURL := item.URL != nil && *item.URL != ""
+		if hasURL == (item.File != nil) {
+			return pkgError.ValidationError(fmt.Sprintf("items[%d]: exactly one of url or an uploaded file is required", i))
+		}
+		if hasURL {
+			if err := validation.Validate(*item.URL, is.URL); err != nil {
+				return pkgError.ValidationError(fmt.Sprintf("items[%d].url: %v", i, err))
+			}
+		}
+	}
+
+	return validateDuration(request.Duration)
+}
+
+// DraftMaxLength is the longest draft accepted, in bytes.
+const DraftMaxLength = 65536
+
+func ValidateSaveDraft(ctx context.Context, request domainSend.DraftRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.Content, validation.Required, validation.RuneLength(0, DraftMaxLength)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return validatePhoneNumber(request.ChatJID)
+}
+
+// TemplateNamePattern limits template names to what is safe in a URL path.
+var TemplateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+func ValidateSaveTemplate(ctx context.Context, request domainSend.TemplateRequest) error {
+	hasHeader := request.HeaderType != ""
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required,
+			validation.Match(TemplateNamePattern).Error("must be 1-64 letters, digits, '_', '.' or '-'")),
+		validation.Field(&request.Body, validation.Required, validation.RuneLength(0, DraftMaxLength)),
+		validation.Field(&request.HeaderType, validation.In(domainSend.TemplateHeaderImage, domainSend.TemplateHeaderVideo, domainSend.TemplateHeaderDocument)),
+		validation.Field(&request.HeaderURL, validation.When(hasHeader, validation.Required, is.URL),
+			validation.When(!hasHeader, validation.Empty.Error("requires header_type"))),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+	return nil
+}
+
+func ValidateSendTemplate(ctx context.Context, request domainSend.SendTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Template, validation.Required),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	return validateDuration(request.Duration)
+}
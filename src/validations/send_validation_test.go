@@ -3,6 +3,7 @@ package validations
 import (
 	"context"
 	"mime/multipart"
+	"strings"
 	"testing"
 
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
@@ -50,6 +51,28 @@ func TestValidateSendMessage(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("message: cannot be blank."),
 		},
+		{
+			name: "should success mentioning everyone in a group",
+			args: args{request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "120363025246125486@g.us",
+				},
+				Message:  "Hello everyone",
+				Mentions: []string{"all", "628123456789", "@everyone"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error mentioning everyone outside a group",
+			args: args{request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				Message:  "Hello everyone",
+				Mentions: []string{"all"},
+			}},
+			err: pkgError.ValidationError("mention all: only allowed when sending to a group"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -80,11 +103,11 @@ func TestValidateSendImage(t *testing.T) {
 			name: "should success with normal condition",
 			args: args{request: domainSend.ImageRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone: "1728937129312@s.whatsapp.net",
+					Phone:          "1728937129312@s.whatsapp.net",
+					ReplyMessageID: &replyMessageID,
 				},
-				Caption:        "Hello this is testing",
-				ReplyMessageID: &replyMessageID,
-				Image:          image,
+				Caption: "Hello this is testing",
+				Image:   image,
 			}},
 			err: nil,
 		},
@@ -152,10 +175,10 @@ func TestValidateSendFile(t *testing.T) {
 			name: "should success with normal condition",
 			args: args{request: domainSend.FileRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone: "1728937129312@s.whatsapp.net",
+					Phone:          "1728937129312@s.whatsapp.net",
+					ReplyMessageID: &replyMessageID,
 				},
-				File:           file,
-				ReplyMessageID: &replyMessageID,
+				File: file,
 			}},
 			err: nil,
 		},
@@ -209,13 +232,13 @@ func TestValidateSendVideo(t *testing.T) {
 			name: "should success with normal condition",
 			args: args{request: domainSend.VideoRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone: "1728937129312@s.whatsapp.net",
+					Phone:          "1728937129312@s.whatsapp.net",
+					ReplyMessageID: &replyMessageID,
 				},
-				Caption:        "simple caption",
-				ReplyMessageID: &replyMessageID,
-				Video:          file,
-				ViewOnce:       false,
-				Compress:       false,
+				Caption:  "simple caption",
+				Video:    file,
+				ViewOnce: false,
+				Compress: false,
 			}},
 			err: nil,
 		},
@@ -538,6 +561,50 @@ func TestValidateSendContact(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("contact phone number cannot be empty"),
 		},
+		{
+			name: "should success with multiple contact cards",
+			args: args{request: domainSend.ContactRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				Contacts: []domainSend.ContactCard{
+					{Name: "Aldino", Phones: []string{"62788712738123"}, Organization: "GOWA", Emails: []string{"aldino@example.com"}},
+					{Name: "Budi", Phones: []string{"+6281234567890", "6289876543210"}},
+				},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with contact name and cards together",
+			args: args{request: domainSend.ContactRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				ContactName: "Aldino",
+				Contacts:    []domainSend.ContactCard{{Name: "Budi", Phones: []string{"6281234567890"}}},
+			}},
+			err: pkgError.ValidationError("contact_name: must be blank when contacts is set."),
+		},
+		{
+			name: "should error with a card without phones",
+			args: args{request: domainSend.ContactRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				Contacts: []domainSend.ContactCard{{Name: "Budi"}},
+			}},
+			err: pkgError.ValidationError("contacts[0].phones: cannot be blank."),
+		},
+		{
+			name: "should error with an invalid card email",
+			args: args{request: domainSend.ContactRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				Contacts: []domainSend.ContactCard{{Name: "Budi", Phones: []string{"6281234567890"}, Emails: []string{"budi"}}},
+			}},
+			err: pkgError.ValidationError("contacts[0].emails[0]: must be a valid email address"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -653,10 +720,10 @@ func TestValidateSendAudio(t *testing.T) {
 			name: "should success with normal condition",
 			args: args{request: domainSend.AudioRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone: "1728937129312@s.whatsapp.net",
+					Phone:          "1728937129312@s.whatsapp.net",
+					ReplyMessageID: &replyMessageID,
 				},
-				Audio:          audio,
-				ReplyMessageID: &replyMessageID,
+				Audio: audio,
 			}},
 			err: nil,
 		},
@@ -1130,3 +1197,266 @@ func TestValidateSendAudio_WithDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSendCampaign(t *testing.T) {
+	recipients := []domainSend.CampaignRecipient{{Phone: "6281234567890"}, {Phone: "6289876543210"}}
+	tests := []struct {
+		name    string
+		request domainSend.CampaignRequest
+		err     any
+	}{
+		{
+			name:    "should success and default the rate",
+			request: domainSend.CampaignRequest{Message: "Hi {{name}}", Recipients: recipients},
+		},
+		{
+			name:    "should success with a template",
+			request: domainSend.CampaignRequest{Template: "welcome", Recipients: recipients},
+		},
+		{
+			name:    "should error with both a message and a template",
+			request: domainSend.CampaignRequest{Message: "Hi", Template: "welcome", Recipients: recipients},
+			err:     pkgError.ValidationError("message: cannot be combined with template."),
+		},
+		{
+			name:    "should error without recipients",
+			request: domainSend.CampaignRequest{Message: "Hi"},
+			err:     pkgError.ValidationError("recipients: cannot be blank."),
+		},
+		{
+			name:    "should error above the maximum rate",
+			request: domainSend.CampaignRequest{Message: "Hi", Recipients: recipients, RatePerMinute: 61},
+			err:     pkgError.ValidationError("rate_per_minute: must be no greater than 60."),
+		},
+		{
+			name:    "should error with a local phone number",
+			request: domainSend.CampaignRequest{Message: "Hi", Recipients: []domainSend.CampaignRecipient{{Phone: "081234567890"}}},
+			err:     pkgError.ValidationError("recipients[0]: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx"),
+		},
+		{
+			name:    "should error with a duplicate recipient",
+			request: domainSend.CampaignRequest{Message: "Hi", Recipients: append(recipients, recipients[0])},
+			err:     pkgError.ValidationError("recipients[2]: 6281234567890 is listed more than once"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendCampaign(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			if err == nil {
+				assert.Equal(t, CampaignDefaultRatePerMinute, tt.request.RatePerMinute)
+			}
+		})
+	}
+}
+
+func TestValidateSendAlbum(t *testing.T) {
+	imageURL := "https://example.com/a.jpg"
+	videoURL := "https://example.com/b.mp4"
+	items := []domainSend.AlbumItem{
+		{Type: domainSend.AlbumItemImage, URL: &imageURL},
+		{Type: domainSend.AlbumItemVideo, URL: &videoURL},
+	}
+	tests := []struct {
+		name    string
+		request domainSend.AlbumRequest
+		err     any
+	}{
+		{
+			name:    "should success with an image and a video",
+			request: domainSend.AlbumRequest{BaseRequest: domainSend.BaseRequest{Phone: "6281234567890@s.whatsapp.net"}, Items: items},
+		},
+		{
+			name:    "should error with a single item",
+			request: domainSend.AlbumRequest{BaseRequest: domainSend.BaseRequest{Phone: "6281234567890@s.whatsapp.net"}, Items: items[:1]},
+			err:     pkgError.ValidationError("items: the length must be between 2 and 30."),
+		},
+		{
+			name: "should error with an unsupported type",
+			request: domainSend.AlbumRequest{BaseRequest: domainSend.BaseRequest{Phone: "6281234567890@s.whatsapp.net"}, Items: []domainSend.AlbumItem{
+				items[0], {Type: "document", URL: &imageURL},
+			}},
+			err: pkgError.ValidationError("items[1].type: must be image or video"),
+		},
+		{
+			name: "should error with both url and file",
+			request: domainSend.AlbumRequest{BaseRequest: domainSend.BaseRequest{Phone: "6281234567890@s.whatsapp.net"}, Items: []domainSend.AlbumItem{
+				{Type: domainSend.AlbumItemImage, URL: &imageURL, File: &multipart.FileHeader{Filename: "a.jpg"}}, items[1],
+			}},
+			err: pkgError.ValidationError("items[0]: exactly one of url or an uploaded file is required"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, ValidateSendAlbum(context.Background(), tt.request))
+		})
+	}
+}
+
+func TestValidateSendLiveLocation(t *testing.T) {
+	base := domainSend.BaseRequest{Phone: "6281234567890@s.whatsapp.net"}
+	tests := []struct {
+		name    string
+		request domainSend.LiveLocationRequest
+		err     any
+	}{
+		{
+			name:    "should success starting with the default duration",
+			request: domainSend.LiveLocationRequest{BaseRequest: base, Action: "start", Latitude: "-7.80", Longitude: "110.45"},
+		},
+		{
+			name:    "should success stopping without coordinates",
+			request: domainSend.LiveLocationRequest{BaseRequest: base, Action: "stop"},
+		},
+		{
+			name:    "should error updating without coordinates",
+			request: domainSend.LiveLocationRequest{BaseRequest: base, Action: "update"},
+			err:     pkgError.ValidationError("latitude: cannot be blank; longitude: cannot be blank."),
+		},
+		{
+			name:    "should error with an unknown action",
+			request: domainSend.LiveLocationRequest{BaseRequest: base, Action: "pause", Latitude: "-7.80", Longitude: "110.45"},
+			err:     pkgError.ValidationError("action: must be a valid value."),
+		},
+		{
+			name:    "should error above the maximum duration",
+			request: domainSend.LiveLocationRequest{BaseRequest: base, Action: "start", Latitude: "-7.80", Longitude: "110.45", DurationSeconds: 9 * 60 * 60},
+			err:     pkgError.ValidationError("duration_seconds: must be no greater than 28800."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendLiveLocation(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			if err == nil && tt.request.Action == "start" {
+				assert.Equal(t, LiveLocationDefaultDuration, tt.request.DurationSeconds)
+			}
+		})
+	}
+}
+
+func TestValidateSaveDraft(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainSend.DraftRequest
+		err     any
+	}{
+		{
+			name:    "should success with content",
+			request: domainSend.DraftRequest{ChatJID: "6281234567890@s.whatsapp.net", Content: "See you at 9"},
+		},
+		{
+			name:    "should error without content",
+			request: domainSend.DraftRequest{ChatJID: "6281234567890@s.whatsapp.net"},
+			err:     pkgError.ValidationError("content: cannot be blank."),
+		},
+		{
+			name:    "should error with a local number",
+			request: domainSend.DraftRequest{ChatJID: "081234567890@s.whatsapp.net", Content: "See you"},
+			err:     pkgError.ValidationError("phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, ValidateSaveDraft(context.Background(), tt.request))
+		})
+	}
+}
+
+func TestValidateSaveTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainSend.TemplateRequest
+		err     any
+	}{
+		{
+			name:    "should success with a text template",
+			request: domainSend.TemplateRequest{Name: "order_shipped", Body: "Hi {{name}}, order {{order}} shipped"},
+		},
+		{
+			name:    "should success with an image header",
+			request: domainSend.TemplateRequest{Name: "promo", Body: "{{discount}} off", HeaderType: "image", HeaderURL: "https://example.com/promo.jpg"},
+		},
+		{
+			name:    "should error with a name unsafe in a path",
+			request: domainSend.TemplateRequest{Name: "order/shipped", Body: "Hi"},
+			err:     pkgError.ValidationError("name: must be 1-64 letters, digits, '_', '.' or '-'."),
+		},
+		{
+			name:    "should error with a header type but no url",
+			request: domainSend.TemplateRequest{Name: "promo", Body: "Hi", HeaderType: "video"},
+			err:     pkgError.ValidationError("header_url: cannot be blank."),
+		},
+		{
+			name:    "should error with a header url but no type",
+			request: domainSend.TemplateRequest{Name: "promo", Body: "Hi", HeaderURL: "https://example.com/promo.jpg"},
+			err:     pkgError.ValidationError("header_url: requires header_type."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, ValidateSaveTemplate(context.Background(), tt.request))
+		})
+	}
+}
+
+func TestValidateSendProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainSend.ProductRequest
+		err     any
+	}{
+		{
+			name: "should success with product",
+			request: domainSend.ProductRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "1728937129312@s.whatsapp.net"},
+				ProductID:   "4567890123456789",
+				Body:        "Fresh today",
+				Footer:      "Free delivery",
+			},
+			err: nil,
+		},
+		{
+			name: "should success without product for the catalog",
+			request: domainSend.ProductRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "1728937129312@s.whatsapp.net"},
+			},
+			err: nil,
+		},
+		{
+			name: "should error with empty phone",
+			request: domainSend.ProductRequest{
+				ProductID: "4567890123456789",
+			},
+			err: pkgError.ValidationError("phone: cannot be blank."),
+		},
+		{
+			name: "should error with non numeric product id",
+			request: domainSend.ProductRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "1728937129312@s.whatsapp.net"},
+				ProductID:   "SKU-1",
+			},
+			err: pkgError.ValidationError("product_id: must contain digits only."),
+		},
+		{
+			name: "should error with footer too long",
+			request: domainSend.ProductRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "1728937129312@s.whatsapp.net"},
+				Footer:      strings.Repeat("a", ProductFooterMaxLength+1),
+			},
+			err: pkgError.ValidationError("footer: the length must be no more than 60."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendProduct(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package validations
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -33,6 +35,75 @@ func ValidateUserAvatar(ctx context.Context, request domainUser.AvatarRequest) e
 	return nil
 }
 
+// Business catalog page sizes
+const (
+	BusinessCatalogDefaultLimit = 10
+	BusinessCatalogMaxLimit     = 100
+)
+
+func ValidateBusinessCatalog(ctx context.Context, request *domainUser.BusinessCatalogRequest) error {
+	if request.Limit == 0 {
+		request.Limit = BusinessCatalogDefaultLimit
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(BusinessCatalogMaxLimit)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+var (
+	resolveLIDPattern   = regexp.MustCompile(`^[0-9]+(@lid)?$`)
+	resolvePhonePattern = regexp.MustCompile(`^\+?[0-9]{1,15}@s\.whatsapp\.net$`)
+)
+
+func ValidateResolveContact(ctx context.Context, request domainUser.ResolveContactRequest) error {
+	if (request.LID == "") == (request.Phone == "") {
+		return pkgError.ValidationError("exactly one of lid or phone is required")
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.LID, validation.Match(resolveLIDPattern).Error("must be a LID")),
+		validation.Field(&request.Phone, validation.Match(resolvePhonePattern).Error("must be a phone number")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// ResolveContactsMaxItems is the number of JIDs one bulk resolve request may translate.
+const ResolveContactsMaxItems = 1000
+
+func ValidateResolveContacts(ctx context.Context, request domainUser.ResolveContactsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.JIDs,
+			validation.Required,
+			validation.Length(1, ResolveContactsMaxItems),
+			validation.Each(validation.By(func(value any) error {
+				jid, _ := value.(string)
+				if !resolveLIDPattern.MatchString(jid) && !resolvePhonePattern.MatchString(jid) {
+					return fmt.Errorf("%q is not a LID or phone number", jid)
+				}
+				return nil
+			})),
+		),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateBusinessProfile(ctx context.Context, request domainUser.BusinessProfileRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
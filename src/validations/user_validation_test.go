@@ -110,3 +110,138 @@ func TestValidateBusinessProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBusinessCatalog(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainUser.BusinessCatalogRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should default limit and allow own catalog",
+			request:   domainUser.BusinessCatalogRequest{},
+			err:       nil,
+			wantLimit: BusinessCatalogDefaultLimit,
+		},
+		{
+			name:      "should success with phone and cursor",
+			request:   domainUser.BusinessCatalogRequest{Phone: "1728937129312@s.whatsapp.net", Limit: 50, Cursor: "abc"},
+			err:       nil,
+			wantLimit: 50,
+		},
+		{
+			name:      "should error with limit above max",
+			request:   domainUser.BusinessCatalogRequest{Limit: BusinessCatalogMaxLimit + 1},
+			err:       pkgError.ValidationError("limit: must be no greater than 100."),
+			wantLimit: BusinessCatalogMaxLimit + 1,
+		},
+		{
+			name:      "should error with negative limit",
+			request:   domainUser.BusinessCatalogRequest{Limit: -1},
+			err:       pkgError.ValidationError("limit: must be no less than 1."),
+			wantLimit: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request
+			err := ValidateBusinessCatalog(context.Background(), &request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, request.Limit)
+		})
+	}
+}
+
+func TestValidateResolveContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainUser.ResolveContactRequest
+		err     any
+	}{
+		{
+			name:    "should success with lid",
+			request: domainUser.ResolveContactRequest{LID: "100000000000001@lid"},
+			err:     nil,
+		},
+		{
+			name:    "should success with bare lid",
+			request: domainUser.ResolveContactRequest{LID: "100000000000001"},
+			err:     nil,
+		},
+		{
+			name:    "should success with phone",
+			request: domainUser.ResolveContactRequest{Phone: "628123456789@s.whatsapp.net"},
+			err:     nil,
+		},
+		{
+			name:    "should error without lid and phone",
+			request: domainUser.ResolveContactRequest{},
+			err:     pkgError.ValidationError("exactly one of lid or phone is required"),
+		},
+		{
+			name:    "should error with both lid and phone",
+			request: domainUser.ResolveContactRequest{LID: "100000000000001@lid", Phone: "628123456789@s.whatsapp.net"},
+			err:     pkgError.ValidationError("exactly one of lid or phone is required"),
+		},
+		{
+			name:    "should error with group as phone",
+			request: domainUser.ResolveContactRequest{Phone: "120363025246125486@g.us"},
+			err:     pkgError.ValidationError("phone: must be a phone number."),
+		},
+		{
+			name:    "should error with phone as lid",
+			request: domainUser.ResolveContactRequest{LID: "628123456789@s.whatsapp.net"},
+			err:     pkgError.ValidationError("lid: must be a LID."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResolveContact(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateResolveContacts(t *testing.T) {
+	tooMany := make([]string, ResolveContactsMaxItems+1)
+	for i := range tooMany {
+		tooMany[i] = "628123456789@s.whatsapp.net"
+	}
+
+	tests := []struct {
+		name    string
+		request domainUser.ResolveContactsRequest
+		err     any
+	}{
+		{
+			name:    "should success with lids and phones",
+			request: domainUser.ResolveContactsRequest{JIDs: []string{"100000000000001@lid", "628123456789@s.whatsapp.net"}},
+			err:     nil,
+		},
+		{
+			name:    "should error without jids",
+			request: domainUser.ResolveContactsRequest{},
+			err:     pkgError.ValidationError("jids: cannot be blank."),
+		},
+		{
+			name:    "should error with too many jids",
+			request: domainUser.ResolveContactsRequest{JIDs: tooMany},
+			err:     pkgError.ValidationError("jids: the length must be between 1 and 1000."),
+		},
+		{
+			name:    "should error with a group",
+			request: domainUser.ResolveContactsRequest{JIDs: []string{"100000000000001@lid", "120363025246125486@g.us"}},
+			err:     pkgError.ValidationError(`jids: (1: "120363025246125486@g.us" is not a LID or phone number.).`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResolveContacts(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
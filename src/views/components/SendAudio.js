@@ -64,7 +64,7 @@ export default {
                 let payload = new FormData();
                 payload.append("phone", this.phone_id)
                 payload.append("is_forwarded", this.is_forwarded)
-                payload.append("ptt", this.ptt)
+                payload.append("voice_note", this.ptt)
                 const replyMessageID = this.reply_message_id.trim()
                 if (this.isShowAttributes() && replyMessageID !== '') {
                     payload.append("reply_message_id", replyMessageID)